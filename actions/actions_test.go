@@ -74,7 +74,7 @@ func connectMapStores(peers []*p2p.QriNode) {
 	}
 }
 
-func newTestNode(t *testing.T) *p2p.QriNode {
+func newTestNode(t testing.TB) *p2p.QriNode {
 	ms := cafs.NewMapstore()
 	mr, err := repo.NewMemRepo(testPeerProfile, ms, newTestFS(ms), profile.NewMemStore())
 	if err != nil {
@@ -1,17 +1,23 @@
 package actions
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p"
 )
 
 // GetBody grabs some or all of a dataset's body, writing an output in the desired format
-func GetBody(node *p2p.QriNode, ds *dataset.Dataset, format dataset.DataFormat, fcfg dataset.FormatConfig, limit, offset int, all bool) (data []byte, err error) {
+// if useIndex is true and the stored version of ds has a body index, paged
+// reads seek directly to the requested offset instead of scanning from the
+// start. a non-nil where filter selects only matching rows. GetBody also
+// returns the number of entries written
+func GetBody(node *p2p.QriNode, ds *dataset.Dataset, useIndex bool, format dataset.DataFormat, fcfg dataset.FormatConfig, where *base.RowFilter, limit, offset int, all bool) (data []byte, entries int, err error) {
 	if ds == nil {
-		return nil, fmt.Errorf("can't load body from a nil dataset")
+		return nil, 0, fmt.Errorf("can't load body from a nil dataset")
 	}
 
 	file := ds.BodyFile()
@@ -30,11 +36,25 @@ func GetBody(node *p2p.QriNode, ds *dataset.Dataset, format dataset.DataFormat,
 	}
 	st.Assign(ds.Structure, assign)
 
-	data, err = base.ConvertBodyFile(file, ds.Structure, st, limit, offset, all)
+	// offsets of filtered reads count matching rows, which the index can't seek to
+	if !all && offset > 0 && where == nil && useIndex && ds.Path != "" {
+		if idx, err := base.ReadBodyIndex(context.TODO(), node.Repo.Store(), ds.Path); err == nil {
+			rdr, rest, err := idx.Seek(file, offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			file = qfs.NewMemfileReader(file.FileName(), rdr)
+			offset = rest
+		} else if err != base.ErrNoBodyIndex {
+			log.Debugf("reading body index: %s", err)
+		}
+	}
+
+	data, entries, err = base.ConvertBodyFile(file, ds.Structure, st, where, limit, offset, all)
 	if err != nil {
 		log.Debug(err.Error())
-		return nil, err
+		return nil, 0, err
 	}
 
-	return data, nil
+	return data, entries, nil
 }
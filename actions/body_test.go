@@ -3,10 +3,18 @@ package actions
 import (
 	"bytes"
 	"context"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/p2p"
+	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/repo/kvstore"
+	"github.com/qri-io/qri/repo/profile"
 )
 
 func TestGetBody(t *testing.T) {
@@ -19,10 +27,13 @@ func TestGetBody(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	data, err := GetBody(node, ds, dataset.JSONDataFormat, nil, 1, 1, false)
+	data, entries, err := GetBody(node, ds, false, dataset.JSONDataFormat, nil, nil, 1, 1, false)
 	if err != nil {
 		t.Error(err.Error())
 	}
+	if entries != 1 {
+		t.Errorf("entry count mismatch. expected: %d, got: %d", 1, entries)
+	}
 	if !bytes.Equal(data, []byte(`[["new york",8500000,44.4,true]]`)) {
 		t.Errorf("byte response mismatch. got: %s", string(data))
 	}
@@ -31,3 +42,73 @@ func TestGetBody(t *testing.T) {
 		t.Errorf("bodypath mismatch")
 	}
 }
+
+func TestGetBodyIndexed(t *testing.T) {
+	ctx := context.Background()
+	// kvstore versions are written as directories, which can hold a body index
+	store := kvstore.NewFilestore("cafs", kvstore.NewMemStore())
+	mr, err := repo.NewMemRepo(testPeerProfile, store, newTestFS(store), profile.NewMemStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := make([]string, base.DefaultBodyIndexInterval*2+1)
+	for i := range rows {
+		rows[i] = strconv.Itoa(i)
+	}
+	ds := &dataset.Dataset{
+		Name:      "indexed",
+		Meta:      &dataset.Meta{Title: "indexed"},
+		Structure: &dataset.Structure{Format: "json", Schema: map[string]interface{}{"type": "array"}},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("["+strings.Join(rows, ",")+"]")))
+	ref, err := SaveDataset(ctx, node, ds, nil, nil, SaveDatasetSwitches{Pin: true, ShouldRender: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.ReadBodyIndex(ctx, store, ref.Path); err != nil {
+		t.Fatalf("expected saved version to have a body index. got: %s", err)
+	}
+
+	for _, offset := range []int{0, 1, base.DefaultBodyIndexInterval - 1, base.DefaultBodyIndexInterval, len(rows) - 1} {
+		ds, err := base.ReadDatasetPath(ctx, node.Repo, ref.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect, _, err := GetBody(node, ds, false, dataset.JSONDataFormat, nil, nil, 2, offset, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ds, err = base.ReadDatasetPath(ctx, node.Repo, ref.String()); err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := GetBody(node, ds, true, dataset.JSONDataFormat, nil, nil, 2, offset, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(expect, got) {
+			t.Errorf("offset %d indexed read mismatch.\nwant: %s\ngot:  %s", offset, string(expect), string(got))
+		}
+	}
+
+	// negative minimum sizes turn indexing off
+	ds = &dataset.Dataset{
+		Name:      "unindexed",
+		Meta:      &dataset.Meta{Title: "unindexed"},
+		Structure: &dataset.Structure{Format: "json", Schema: map[string]interface{}{"type": "array"}},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("["+strings.Join(rows, ",")+"]")))
+	ref, err = SaveDataset(ctx, node, ds, nil, nil, SaveDatasetSwitches{Pin: true, BodyIndexMinSize: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.ReadBodyIndex(ctx, store, ref.Path); err != base.ErrNoBodyIndex {
+		t.Errorf("expected version saved with indexing off to have no body index. got: %v", err)
+	}
+}
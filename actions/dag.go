@@ -1,11 +1,19 @@
 package actions
 
 import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
 	"github.com/qri-io/dag"
+	"github.com/qri-io/dag/dsync"
+	"github.com/qri-io/dataset/dsfs"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p"
 
+	cid "github.com/ipfs/go-cid"
 	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/interface-go-ipfs-core/path"
 )
 
 // NewManifest generates a manifest for a given node
@@ -38,6 +46,68 @@ func NewDAGInfo(node *p2p.QriNode, path, label string) (*dag.Info, error) {
 	return base.NewDAGInfo(node.Context(), node.Repo.Store(), ng, path, label)
 }
 
+// DAGDelta calculates the count & total size of blocks in the DAG at path b
+// that are not present in the DAG at path a. DAGDelta requires an IPFS-backed
+// store
+func DAGDelta(node *p2p.QriNode, a, b string) (*base.DAGDelta, error) {
+	ng, err := newNodeGetter(node)
+	if err != nil {
+		return nil, fmt.Errorf("computing delta size requires an IPFS-backed store: %s", err)
+	}
+
+	return base.NewDAGDelta(node.Context(), ng, a, b)
+}
+
+// VerifyDataset checks the local copy of the dataset at dsPath is complete,
+// re-hashing every block of the dataset DAG & comparing the result against
+// the block's content identifier. Verification only reads local blocks,
+// missing blocks are reported instead of being fetched from the network
+func VerifyDataset(node *p2p.QriNode, dsPath string) error {
+	capi, err := node.IPFSCoreAPI()
+	if err != nil {
+		return fmt.Errorf("verifying datasets requires an IPFS-backed store: %s", err)
+	}
+	lng, err := dsync.NewLocalNodeGetter(capi)
+	if err != nil {
+		return err
+	}
+
+	rootStr := strings.TrimPrefix(strings.TrimSuffix(dsPath, "/"+dsfs.PackageFileDataset.String()), "/ipfs/")
+	root, err := cid.Parse(rootStr)
+	if err != nil {
+		return fmt.Errorf("invalid dataset path %q: %s", dsPath, err)
+	}
+
+	ctx := node.Context()
+	m, err := dag.NewManifest(ctx, lng, root)
+	if err != nil {
+		return fmt.Errorf("dataset %s is incomplete: %s", dsPath, err)
+	}
+
+	for _, hash := range m.Nodes {
+		id, err := cid.Parse(hash)
+		if err != nil {
+			return err
+		}
+		r, err := capi.Block().Get(ctx, path.IpfsPath(id))
+		if err != nil {
+			return fmt.Errorf("dataset %s is incomplete: reading block %s: %s", dsPath, hash, err)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		sum, err := id.Prefix().Sum(data)
+		if err != nil {
+			return err
+		}
+		if !sum.Equals(id) {
+			return fmt.Errorf("dataset %s failed verification: block %s doesn't match its hash", dsPath, hash)
+		}
+	}
+	return nil
+}
+
 // newNodeGetter generates an ipld.NodeGetter from a QriNode
 func newNodeGetter(node *p2p.QriNode) (ipld.NodeGetter, error) {
 	capi, err := node.IPFSCoreAPI()
@@ -27,6 +27,12 @@ type SaveDatasetSwitches struct {
 	ConvertFormatToPrev bool
 	Force               bool
 	ShouldRender        bool
+	// UnpinPrevious unpins the prior version once the new version is pinned,
+	// keeping only the latest version of the dataset pinned
+	UnpinPrevious bool
+	// BodyIndexMinSize is the smallest body size in bytes that gets a body
+	// index written into the saved version, a negative value turns indexing off
+	BodyIndexMinSize int64
 }
 
 // SaveDataset initializes a dataset from a dataset pointer and data file
@@ -37,6 +43,13 @@ func SaveDataset(ctx context.Context, node *p2p.QriNode, changes *dataset.Datase
 		r        = node.Repo
 	)
 
+	// fail before doing any work if the save can't be written
+	if !sw.DryRun {
+		if err = base.StoreWritable(r.Store()); err != nil {
+			return
+		}
+	}
+
 	prev, mutable, prevPath, err := base.PrepareDatasetSave(ctx, r, changes.Peername, changes.Name)
 	if err != nil {
 		return
@@ -109,7 +122,22 @@ func SaveDataset(ctx context.Context, node *p2p.QriNode, changes *dataset.Datase
 	// let's make history, if it exists
 	changes.PreviousPath = prevPath
 
-	return base.CreateDataset(ctx, r, node.LocalStreams, changes, prev, sw.DryRun, sw.Pin, sw.Force, sw.ShouldRender)
+	if ref, err = base.CreateDataset(ctx, r, node.LocalStreams, changes, prev, sw.DryRun, sw.Pin, sw.Force, sw.ShouldRender, sw.BodyIndexMinSize); err != nil {
+		return
+	}
+	if !sw.DryRun {
+		if sw.Pin && sw.UnpinPrevious && prevPath != "" {
+			prevRef := repo.DatasetRef{ProfileID: ref.ProfileID, Peername: ref.Peername, Name: ref.Name, Path: prevPath}
+			if err := base.UnpinDataset(ctx, r, prevRef); err != nil && err != repo.ErrNotPinner {
+				log.Debugf("unpinning previous version %s: %s", prevPath, err)
+			}
+		}
+		// new versions of published datasets are announced to peers
+		if stored, err := r.GetRef(ref); err == nil && stored.Published {
+			announceDataset(ctx, node, stored)
+		}
+	}
+	return ref, nil
 }
 
 // UpdateRemoteDataset brings a reference to the latest version, syncing to the
@@ -133,8 +161,11 @@ func UpdateRemoteDataset(ctx context.Context, node *p2p.QriNode, ref *repo.Datas
 	return
 }
 
-// AddDataset fetches & pins a dataset to the store, adding it to the list of stored refs
-func AddDataset(ctx context.Context, node *p2p.QriNode, rc *remote.Client, remoteAddr string, ref *repo.DatasetRef) (err error) {
+// AddDataset fetches & pins a dataset to the store, adding it to the list of
+// stored refs. When verify is true every block of the fetched dataset is
+// checked before the ref is stored, a dataset that fails verification is
+// unpinned & not added
+func AddDataset(ctx context.Context, node *p2p.QriNode, rc *remote.Client, remoteAddr string, ref *repo.DatasetRef, verify bool) (err error) {
 	log.Debugf("add dataset %s. remoteAddr: %s", ref.String(), remoteAddr)
 	if !ref.Complete() {
 		// TODO (ramfox): we should check to see if the dataset already exists locally
@@ -217,6 +248,160 @@ func AddDataset(ctx context.Context, node *p2p.QriNode, rc *remote.Client, remot
 		return fmt.Errorf("add failed: %s", err.Error())
 	}
 
+	if verify {
+		if err = VerifyDataset(node, ref.Path); err != nil {
+			// drop the pin this add took, unless the version was already stored
+			if prev, perr := node.Repo.GetRef(repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}); perr != nil || prev.Path != ref.Path {
+				if uerr := base.UnpinDataset(ctx, node.Repo, *ref); uerr != nil {
+					log.Debugf("unpinning unverified dataset %s: %s", ref.Path, uerr)
+				}
+			}
+			return err
+		}
+	}
+
+	return putAddedRef(ctx, node, ref)
+}
+
+// AddDatasetDependencies walks the transforms of a dataset & its upstream
+// datasets, adding each referenced dataset that isn't already stored. Every
+// dataset is visited once, so reference cycles terminate. partial adds
+// dependencies without their bodies. It returns the references it added
+func AddDatasetDependencies(ctx context.Context, node *p2p.QriNode, rc *remote.Client, remoteAddr string, ref repo.DatasetRef, partial bool) ([]repo.DatasetRef, error) {
+	var added []repo.DatasetRef
+	visited := map[string]bool{ref.Path: true}
+	queue := []repo.DatasetRef{ref}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		deps, err := datasetDependencies(ctx, node.Repo, cur.Path)
+		if err != nil {
+			return added, fmt.Errorf("reading dependencies of %s: %s", cur.AliasString(), err)
+		}
+
+		for _, refstr := range deps {
+			if visited[refstr] {
+				continue
+			}
+			visited[refstr] = true
+
+			dep, err := repo.ParseDatasetRef(refstr)
+			if err != nil {
+				return added, fmt.Errorf("dependency %q of %s: %s", refstr, cur.AliasString(), err)
+			}
+
+			if !storedDataset(ctx, node.Repo, &dep) {
+				log.Debugf("add dependency %s of %s", refstr, cur.AliasString())
+				if partial {
+					err = AddDatasetPartial(ctx, node, rc, remoteAddr, &dep)
+				} else {
+					err = AddDataset(ctx, node, rc, remoteAddr, &dep, false)
+				}
+				if err != nil {
+					return added, fmt.Errorf("adding dependency %s: %s", refstr, err)
+				}
+				added = append(added, dep)
+			}
+
+			if visited[dep.Path] {
+				continue
+			}
+			visited[dep.Path] = true
+			queue = append(queue, dep)
+		}
+	}
+	return added, nil
+}
+
+// datasetDependencies lists the datasets read by the transform of the stored
+// dataset at path
+func datasetDependencies(ctx context.Context, r repo.Repo, path string) ([]string, error) {
+	ds, err := dsfs.LoadDataset(ctx, r.Store(), path)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Transform == nil {
+		return nil, nil
+	}
+	if ds.Transform.ScriptPath != "" {
+		if err = ds.Transform.OpenScriptFile(ctx, r.Filesystem()); err != nil {
+			return nil, err
+		}
+	}
+	return base.TransformDependencies(ds.Transform)
+}
+
+// storedDataset reports whether a reference resolves to a dataset in the
+// local store, canonicalizing ref if it does
+func storedDataset(ctx context.Context, r repo.Repo, ref *repo.DatasetRef) bool {
+	local := *ref
+	if err := repo.CanonicalizeDatasetRef(r, &local); err != nil || local.Path == "" {
+		return false
+	}
+	if has, err := r.Store().Has(ctx, local.Path); err != nil || !has {
+		return false
+	}
+	*ref = local
+	return true
+}
+
+// bodyComponent is the name of the dataset body within a dataset package
+const bodyComponent = "body"
+
+// AddDatasetPartial fetches & pins a dataset from a remote without its body,
+// adding it to the list of stored refs with the body marked as missing. The
+// body can be fetched later with FetchDatasetBody
+func AddDatasetPartial(ctx context.Context, node *p2p.QriNode, rc *remote.Client, remoteAddr string, ref *repo.DatasetRef) (err error) {
+	log.Debugf("add partial dataset %s. remoteAddr: %s", ref.String(), remoteAddr)
+	if rc == nil || remoteAddr == "" {
+		return fmt.Errorf("adding a dataset without its body requires a remote")
+	}
+	if !ref.Complete() {
+		if _, err := ResolveDatasetRef(ctx, node, rc, remoteAddr, ref); err != nil {
+			return err
+		}
+	}
+
+	if err = rc.PullDatasetExcept(ctx, ref, remoteAddr, []string{bodyComponent}); err != nil {
+		return fmt.Errorf("add failed: %s", err.Error())
+	}
+	node.LocalStreams.PrintErr("🗼 fetched from registry. body not fetched\n")
+
+	ref.BodyMissing = true
+	return putAddedRef(ctx, node, ref)
+}
+
+// FetchDatasetBody fetches & pins the body of a dataset that was added without
+// one. it's a no-op if the body is already stored locally
+func FetchDatasetBody(ctx context.Context, node *p2p.QriNode, rc *remote.Client, remoteAddr string, ref *repo.DatasetRef) (err error) {
+	if err = repo.CanonicalizeDatasetRef(node.Repo, ref); err != nil {
+		return err
+	}
+	if !ref.BodyMissing {
+		return nil
+	}
+	if rc == nil || remoteAddr == "" {
+		return fmt.Errorf("fetching a dataset body requires a remote")
+	}
+
+	if err = rc.PullDatasetComponents(ctx, ref, remoteAddr, []string{bodyComponent}); err != nil {
+		return fmt.Errorf("fetching body failed: %s", err.Error())
+	}
+	if pinner, ok := node.Repo.Store().(cafs.Pinner); ok {
+		if err = pinner.Pin(ctx, ref.Path, true); err != nil {
+			return err
+		}
+	}
+
+	ref.BodyMissing = false
+	return node.Repo.PutRef(*ref)
+}
+
+// putAddedRef stores a newly-added reference, keeping any existing reference
+// to the same dataset if it's more recent
+func putAddedRef(ctx context.Context, node *p2p.QriNode, ref *repo.DatasetRef) (err error) {
 	prevRef, err := node.Repo.GetRef(repo.DatasetRef{Peername: ref.Peername, Name: ref.Name})
 	if err != nil && err == repo.ErrNotFound {
 		if err = node.Repo.PutRef(*ref); err != nil {
@@ -276,7 +461,24 @@ func SetPublishStatus(node *p2p.QriNode, ref *repo.DatasetRef, published bool) (
 	} else {
 		node.LocalStreams.PrintErr("unlisting dataset from p2p discovery\n")
 	}
-	return base.SetPublishStatus(node.Repo, ref, published)
+	if err = base.SetPublishStatus(node.Repo, ref, published); err != nil {
+		return err
+	}
+	if published {
+		announceDataset(context.TODO(), node, *ref)
+	}
+	return nil
+}
+
+// announceDataset tells connected peers about a dataset when the node is
+// online. announcing is best-effort, failures are only logged
+func announceDataset(ctx context.Context, node *p2p.QriNode, ref repo.DatasetRef) {
+	if !node.Online {
+		return
+	}
+	if err := node.AnnounceDataset(ctx, ref); err != nil {
+		log.Debugf("announcing dataset %s: %s", ref, err)
+	}
 }
 
 // ModifyDataset alters a reference by changing what dataset it refers to
@@ -300,6 +502,8 @@ func ModifyDataset(node *p2p.QriNode, current, new *repo.DatasetRef, isRename bo
 	}
 	if isRename {
 		new.Path = current.Path
+		// renamed datasets keep any link to a working directory
+		new.FSIPath = current.FSIPath
 	}
 
 	if err = r.DeleteRef(*current); err != nil {
@@ -355,3 +559,80 @@ func DeleteDataset(ctx context.Context, node *p2p.QriNode, ref *repo.DatasetRef)
 
 	return r.LogEvent(repo.ETDsDeleted, *ref)
 }
+
+// TrashDataset soft-deletes a dataset, removing its reference from the repo
+// & placing it in the trash. Dataset content remains pinned until the trash
+// is swept
+func TrashDataset(ctx context.Context, node *p2p.QriNode, trash *base.Trash, ref *repo.DatasetRef) (err error) {
+	r := node.Repo
+
+	if err = repo.CanonicalizeDatasetRef(r, ref); err != nil {
+		log.Debug(err.Error())
+		return err
+	}
+
+	p, err := r.GetRef(*ref)
+	if err != nil {
+		log.Debug(err.Error())
+		return err
+	}
+	if ref.Path != p.Path {
+		return fmt.Errorf("given path does not equal most recent dataset path: cannot trash a specific save, can only trash entire dataset history. use `me/dataset_name` to trash entire dataset")
+	}
+
+	if err = r.DeleteRef(*ref); err != nil {
+		return err
+	}
+
+	trashed := *ref
+	trashed.FSIPath = ""
+	trashed.Dataset = nil
+	if err = trash.Put(trashed, time.Now()); err != nil {
+		// put the reference back, a failure to trash shouldn't lose the dataset
+		if putErr := r.PutRef(*ref); putErr != nil {
+			log.Errorf("restoring reference after failed trash: %s", putErr)
+		}
+		return err
+	}
+
+	return r.LogEvent(repo.ETDsDeleted, *ref)
+}
+
+// RestoreTrashedDataset takes a dataset out of the trash by alias, restoring
+// its reference to the repo. Restoring fails if a dataset with the same name
+// has since been created
+func RestoreTrashedDataset(ctx context.Context, node *p2p.QriNode, trash *base.Trash, alias string) (ref repo.DatasetRef, err error) {
+	r := node.Repo
+
+	entries, err := trash.List()
+	if err != nil {
+		return ref, err
+	}
+	found := false
+	for _, e := range entries {
+		if e.Ref.AliasString() == alias {
+			ref = e.Ref
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ref, base.ErrNotInTrash
+	}
+
+	existing := repo.DatasetRef{Peername: ref.Peername, ProfileID: ref.ProfileID, Name: ref.Name}
+	if _, err = r.GetRef(existing); err == nil {
+		return ref, fmt.Errorf("cannot restore: dataset '%s' already exists", alias)
+	} else if err != repo.ErrNotFound {
+		return ref, err
+	}
+
+	if err = r.PutRef(ref); err != nil {
+		return ref, err
+	}
+	if _, err = trash.Take(alias); err != nil {
+		return ref, err
+	}
+
+	return ref, r.LogEvent(repo.ETDsCreated, ref)
+}
@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/remote"
 	"github.com/qri-io/qri/repo"
 	"github.com/qri-io/qri/repo/profile"
 )
 
+// ErrNotLocal indicates a dataset reference can't be resolved using only the
+// local repo
+var ErrNotLocal = fmt.Errorf("dataset is not stored locally")
+
 // ResolveDatasetRef uses a node to complete the missing pieces of a dataset
 // reference. The most typical example is completing a human ref like
 // peername/dataset_name with content-addressed identifiers
@@ -87,3 +93,35 @@ func ResolveDatasetRef(ctx context.Context, node *p2p.QriNode, rc *remote.Client
 	}
 	return false, nil
 }
+
+// ResolveDatasetRefLocal completes a dataset reference using only the local
+// repo, never making network calls. Unlike canonicalizing a reference, the
+// referenced dataset is loaded to confirm it's actually stored locally, and
+// returned so callers don't need to load it again.
+// ResolveDatasetRefLocal returns ErrNotLocal if the dataset isn't in the local
+// repo
+func ResolveDatasetRefLocal(ctx context.Context, node *p2p.QriNode, ref *repo.DatasetRef) (*dataset.Dataset, error) {
+	if err := repo.CanonicalizeDatasetRef(node.Repo, ref); err == repo.ErrNotFound || err == profile.ErrNotFound {
+		return nil, ErrNotLocal
+	} else if err != nil {
+		return nil, err
+	}
+	if ref.Path == "" {
+		return nil, ErrNotLocal
+	}
+
+	// check the store before loading, filestores backed by a network fetch
+	// missing content on load
+	store := node.Repo.Store()
+	if has, err := store.Has(ctx, ref.Path); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrNotLocal
+	}
+	ds, err := dsfs.LoadDataset(ctx, store, ref.Path)
+	if err != nil {
+		log.Debugf("loading local dataset %s: %s", ref, err)
+		return nil, ErrNotLocal
+	}
+	return ds, nil
+}
@@ -69,3 +69,37 @@ func TestResolveDatasetRef(t *testing.T) {
 		t.Error("expected local to equal true")
 	}
 }
+
+func TestResolveDatasetRefLocal(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t)
+	ref := addCitiesDataset(t, node)
+
+	in := &repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}
+	ds, err := ResolveDatasetRefLocal(ctx, node, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Path != ref.Path {
+		t.Errorf("path mismatch. expected: %s, got: %s", ref.Path, in.Path)
+	}
+	if ds == nil || ds.Path != ref.Path {
+		t.Errorf("expected resolved dataset to be returned. got: %v", ds)
+	}
+
+	missing := &repo.DatasetRef{Peername: ref.Peername, Name: "not_a_dataset"}
+	if _, err := ResolveDatasetRefLocal(ctx, node, missing); err != ErrNotLocal {
+		t.Errorf("expected ErrNotLocal for unknown dataset. got: %v", err)
+	}
+
+	// a reference whose content isn't in the store isn't local, even though
+	// the ref itself canonicalizes
+	absent := repo.DatasetRef{Peername: ref.Peername, ProfileID: ref.ProfileID, Name: "absent", Path: "/map/QmXSGsgt8Bn8jepw7beXibYUfWSJVU2SzP3TpkioQVUrmM"}
+	if err := node.Repo.PutRef(absent); err != nil {
+		t.Fatal(err)
+	}
+	in = &repo.DatasetRef{Peername: ref.Peername, Name: "absent"}
+	if _, err := ResolveDatasetRefLocal(ctx, node, in); err != ErrNotLocal {
+		t.Errorf("expected ErrNotLocal for dataset missing from store. got: %v", err)
+	}
+}
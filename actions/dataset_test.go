@@ -2,10 +2,12 @@ package actions
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qfs/cafs"
 	"github.com/qri-io/qri/base"
@@ -31,7 +33,7 @@ func TestUpdateRemoteDataset(t *testing.T) {
 	connectMapStores(peers)
 
 	now := addNowTransformDataset(t, peers[0])
-	if err := AddDataset(ctx, peers[1], nil, "", &repo.DatasetRef{Peername: now.Peername, Name: now.Name}); err != nil {
+	if err := AddDataset(ctx, peers[1], nil, "", &repo.DatasetRef{Peername: now.Peername, Name: now.Name}, false); err != nil {
 		t.Error(err)
 	}
 
@@ -70,7 +72,7 @@ func TestAddDataset(t *testing.T) {
 	ctx := context.Background()
 	node := newTestNode(t)
 
-	if err := AddDataset(ctx, node, nil, "", &repo.DatasetRef{Peername: "foo", Name: "bar"}); err == nil {
+	if err := AddDataset(ctx, node, nil, "", &repo.DatasetRef{Peername: "foo", Name: "bar"}, false); err == nil {
 		t.Error("expected add of invalid ref to error")
 	}
 
@@ -88,11 +90,59 @@ func TestAddDataset(t *testing.T) {
 
 	connectMapStores(peers)
 	p2Pro, _ := peers[1].Repo.Profile()
-	if err := AddDataset(ctx, peers[0], nil, "", &repo.DatasetRef{Peername: p2Pro.Peername, Name: "cities"}); err != nil {
+	if err := AddDataset(ctx, peers[0], nil, "", &repo.DatasetRef{Peername: p2Pro.Peername, Name: "cities"}, false); err != nil {
 		t.Error(err.Error())
 	}
 }
 
+func TestAddDatasetDependencies(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t)
+	cities := addCitiesDataset(t, node)
+
+	writeDependent := func(script string) repo.DatasetRef {
+		ds := &dataset.Dataset{
+			Commit:    &dataset.Commit{Title: "initial commit"},
+			Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+			Transform: &dataset.Transform{
+				Syntax: "starlark",
+				Resources: map[string]*dataset.TransformResource{
+					cities.Path: {Path: cities.String()},
+				},
+			},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[]")))
+		ds.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.star", []byte(script)))
+		path, err := dsfs.WriteDataset(ctx, node.Repo.Store(), ds, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo.DatasetRef{Peername: "peer", Name: "dependent", Path: path}
+	}
+
+	// the same stored dependency referenced by path & by name is already stored
+	ref := writeDependent(`
+def transform(ds, ctx):
+  ds.set_body(load_dataset("peer/cities").get_body())
+`)
+	added, err := AddDatasetDependencies(ctx, node, nil, "", ref, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 0 {
+		t.Errorf("expected stored dependencies not to be added, got: %v", added)
+	}
+
+	ref = writeDependent(`
+def transform(ds, ctx):
+  ds.set_body(load_dataset("other_peer/missing").get_body())
+`)
+	_, err = AddDatasetDependencies(ctx, node, nil, "", ref, false)
+	if err == nil || !strings.HasPrefix(err.Error(), "adding dependency other_peer/missing") {
+		t.Errorf("expected adding a missing dependency to an offline node to error, got: %v", err)
+	}
+}
+
 func TestDataset(t *testing.T) {
 	rmf := func(t *testing.T) repo.Repo {
 		store := cafs.NewMapstore()
@@ -272,6 +322,98 @@ func TestSaveDatasetWithoutStructureOrBody(t *testing.T) {
 	}
 }
 
+func TestSaveDatasetReadOnlyStore(t *testing.T) {
+	ctx := context.Background()
+	store := base.NewReadOnlyStore(cafs.NewMapstore())
+	mr, err := repo.NewMemRepo(testPeerProfile, store, newTestFS(store), profile.NewMemStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDs := func() *dataset.Dataset {
+		ds := &dataset.Dataset{
+			Peername:  "me",
+			Name:      "read_only_test",
+			Structure: &dataset.Structure{Format: "json", Schema: map[string]interface{}{"type": "array"}},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[]")))
+		return ds
+	}
+
+	if _, err := SaveDataset(ctx, n, newDs(), nil, nil, SaveDatasetSwitches{}); err != base.ErrReadOnlyStore {
+		t.Errorf("expected ErrReadOnlyStore. got: %v", err)
+	}
+	if _, err := SaveDataset(ctx, n, newDs(), nil, nil, SaveDatasetSwitches{DryRun: true}); err != nil {
+		t.Errorf("expected dry run to succeed against a read-only store. got: %s", err)
+	}
+}
+
+// pinRecorder is a map store that tracks pinned paths
+type pinRecorder struct {
+	*cafs.MapStore
+	pinned map[string]bool
+}
+
+func (s *pinRecorder) Pin(ctx context.Context, key string, recursive bool) error {
+	s.pinned[key] = true
+	return nil
+}
+
+func (s *pinRecorder) Unpin(ctx context.Context, key string, recursive bool) error {
+	delete(s.pinned, key)
+	return nil
+}
+
+func TestSaveDatasetUnpinPrevious(t *testing.T) {
+	ctx := context.Background()
+	store := &pinRecorder{MapStore: cafs.NewMapstore(), pinned: map[string]bool{}}
+	mr, err := repo.NewMemRepo(testPeerProfile, store, newTestFS(store), profile.NewMemStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDs := func(body string) *dataset.Dataset {
+		ds := &dataset.Dataset{
+			Peername:  "me",
+			Name:      "pin_policy_test",
+			Structure: &dataset.Structure{Format: "json", Schema: map[string]interface{}{"type": "array"}},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(body)))
+		return ds
+	}
+
+	first, err := SaveDataset(ctx, n, newDs("[1]"), nil, nil, SaveDatasetSwitches{Pin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SaveDataset(ctx, n, newDs("[1,2]"), nil, nil, SaveDatasetSwitches{Pin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// record version paths directly, so checks don't depend on how dsfs keys pins
+	store.pinned[first.Path] = true
+	store.pinned[second.Path] = true
+
+	third, err := SaveDataset(ctx, n, newDs("[1,2,3]"), nil, nil, SaveDatasetSwitches{Pin: true, UnpinPrevious: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.pinned[second.Path] {
+		t.Errorf("expected previous version %s to be unpinned", second.Path)
+	}
+	if !store.pinned[first.Path] {
+		t.Errorf("expected only the version before %s to be unpinned", third.Path)
+	}
+}
+
 func TestSaveDatasetReplace(t *testing.T) {
 	ctx := context.Background()
 	n := newTestNode(t)
@@ -624,3 +766,13 @@ func TestReplaceRefIfMoreRecent(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyDatasetRequiresIPFS(t *testing.T) {
+	node := newTestNode(t)
+	ref := addCitiesDataset(t, node)
+
+	err := VerifyDataset(node, ref.Path)
+	if err == nil || !strings.HasPrefix(err.Error(), "verifying datasets requires an IPFS-backed store") {
+		t.Errorf("expected verifying a map store dataset to error. got: %v", err)
+	}
+}
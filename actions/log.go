@@ -22,3 +22,18 @@ func DatasetLog(ctx context.Context, node *p2p.QriNode, ref repo.DatasetRef, lim
 
 	return base.DatasetLog(ctx, node.Repo, ref, limit, offset, true)
 }
+
+// DatasetHistory fetches the complete history of a dataset. Unlike DatasetLog,
+// local histories aren't cut short when reading them takes a while
+func DatasetHistory(ctx context.Context, node *p2p.QriNode, ref repo.DatasetRef) ([]repo.DatasetRef, error) {
+	local, err := ResolveDatasetRef(ctx, node, nil, "", &ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if !local {
+		return node.RequestDatasetLog(ctx, ref, -1, 0)
+	}
+
+	return base.DatasetHistory(ctx, node.Repo, ref, -1, 0, true)
+}
@@ -39,3 +39,29 @@ func TestDatasetLog(t *testing.T) {
 	}
 
 }
+
+func TestDatasetHistory(t *testing.T) {
+	ctx := context.Background()
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+
+	p, err := p2p.NewTestableQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	node := p.(*p2p.QriNode)
+
+	if _, err := DatasetHistory(ctx, node, repo.MustParseDatasetRef("peer/not_a_dataset")); err == nil {
+		t.Errorf("expected history of a nonexistent dataset to fail")
+	}
+
+	history, err := DatasetHistory(ctx, node, repo.MustParseDatasetRef("peer/movies"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Errorf("history length mismatch. expected: %d, got: %d", 1, len(history))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/startf"
 )
@@ -71,3 +72,53 @@ func ExecTransform(ctx context.Context, node *p2p.QriNode, ds, prev *dataset.Dat
 
 	return nil
 }
+
+// PreviewTransform executes a transform against the current version of a
+// dataset, returning the resulting dataset without writing to the store or
+// the dataset log. If changes doesn't supply a transform, the transform
+// attached to the current version is run. PreviewTransform stops waiting on
+// the script when ctx is done, returning the context error. Secrets are
+// removed from the returned dataset
+func PreviewTransform(ctx context.Context, node *p2p.QriNode, changes *dataset.Dataset, secrets map[string]string, scriptOut io.Writer) (*dataset.Dataset, error) {
+	prev, mutable, prevPath, err := base.PrepareDatasetSave(ctx, node.Repo, changes.Peername, changes.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.Transform == nil {
+		if prev.Transform == nil {
+			return nil, fmt.Errorf("no transform provided")
+		}
+		changes.Transform = &dataset.Transform{}
+		changes.Transform.Assign(prev.Transform)
+	}
+	if changes.Transform.ScriptFile() == nil {
+		if err = changes.Transform.OpenScriptFile(ctx, node.Repo.Filesystem()); err != nil {
+			return nil, err
+		}
+	}
+
+	mutateCheck := mutatedComponentsFunc(changes)
+	changes.Transform.Secrets = secrets
+
+	// starlark threads can't be interrupted, wait on the script in a goroutine so
+	// callers aren't held past their deadline. the script gets the same context &
+	// returns at its next cancellation check once ctx is done, ending the goroutine
+	done := make(chan error, 1)
+	go func() {
+		done <- ExecTransform(ctx, node, changes, prev, scriptOut, mutateCheck)
+	}()
+	select {
+	case err = <-done:
+		if err != nil {
+			return nil, err
+		}
+	case <-ctx.Done():
+		return nil, fmt.Errorf("transform preview stopped: %s", ctx.Err())
+	}
+
+	mutable.Assign(changes)
+	mutable.PreviousPath = prevPath
+	mutable.Transform.Secrets = nil
+	return mutable, nil
+}
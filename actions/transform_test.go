@@ -1,8 +1,11 @@
 package actions
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
@@ -51,3 +54,72 @@ def transform(ds,ctx):
 		t.Error(err.Error())
 	}
 }
+
+func TestPreviewTransform(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t)
+	ref := addCitiesDataset(t, node)
+
+	script := []byte(`
+def transform(ds, ctx):
+	print("previewing")
+	ds.set_meta("title", ctx.get_secret("title"))
+	`)
+	changes := &dataset.Dataset{
+		Peername:  ref.Peername,
+		Name:      ref.Name,
+		Transform: &dataset.Transform{ScriptBytes: script},
+	}
+	out := &bytes.Buffer{}
+	ds, err := PreviewTransform(ctx, node, changes, map[string]string{"title": "from a secret"}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Meta == nil || ds.Meta.Title != "from a secret" {
+		t.Errorf("expected transform to set meta title, got: %v", ds.Meta)
+	}
+	if ds.Transform.Secrets != nil {
+		t.Error("expected secrets to be removed from the previewed dataset")
+	}
+	if ds.PreviousPath != ref.Path {
+		t.Errorf("expected previous path to be the current version. expected: %s, got: %s", ref.Path, ds.PreviousPath)
+	}
+	if !strings.Contains(out.String(), "previewing") {
+		t.Errorf("expected script output to be recorded, got: %q", out.String())
+	}
+
+	head, err := node.Repo.GetRef(repo.DatasetRef{Peername: ref.Peername, Name: ref.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Path != ref.Path {
+		t.Errorf("expected preview not to create a new version")
+	}
+
+	// a dataset without a transform needs one provided
+	if _, err := PreviewTransform(ctx, node, &dataset.Dataset{Peername: ref.Peername, Name: ref.Name}, nil, nil); err == nil {
+		t.Error("expected preview without a transform to error")
+	}
+}
+
+func TestPreviewTransformTimeout(t *testing.T) {
+	node := newTestNode(t)
+	ref := addCitiesDataset(t, node)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	script := []byte(`
+def transform(ds, ctx):
+	for i in range(1000000):
+		pass
+	`)
+	changes := &dataset.Dataset{
+		Peername:  ref.Peername,
+		Name:      ref.Name,
+		Transform: &dataset.Transform{ScriptBytes: script},
+	}
+	if _, err := PreviewTransform(ctx, node, changes, nil, nil); err == nil {
+		t.Error("expected preview past the deadline to error")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strconv"
+	"strings"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/detect"
@@ -14,15 +16,20 @@ import (
 	"github.com/qri-io/dataset/validate"
 	"github.com/qri-io/jsonschema"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
 )
 
-// Validate checks a dataset body for errors based on a schema
-func Validate(ctx context.Context, node *p2p.QriNode, ref repo.DatasetRef, body, schema qfs.File) (errors []jsonschema.ValError, err error) {
+// Validate checks a dataset body for errors based on a schema. When workers is
+// greater than one, CSV bodies with row-level schemas are split into chunks
+// validated in parallel by up to workers goroutines. Results are the same as
+// validating the entire body at once
+func Validate(ctx context.Context, node *p2p.QriNode, ref repo.DatasetRef, body, schema qfs.File, workers int) (errors []jsonschema.ValError, err error) {
 	if !ref.IsEmpty() {
+		// linked datasets with no saved versions validate working directory files
 		err = repo.CanonicalizeDatasetRef(node.Repo, &ref)
-		if err != nil && err != repo.ErrNotFound {
+		if err != nil && err != repo.ErrNotFound && err != repo.ErrNoHistory {
 			log.Debug(err.Error())
 			err = fmt.Errorf("error with new reference: %s", err.Error())
 			return
@@ -106,6 +113,10 @@ func Validate(ctx context.Context, node *p2p.QriNode, ref repo.DatasetRef, body,
 		}
 	}
 
+	if workers > 1 && canValidateChunks(st) {
+		return validateChunks(st, data, workers)
+	}
+
 	er, err := dsio.NewEntryReader(st, bytes.NewBuffer(data))
 	if err != nil {
 		log.Debug(err.Error())
@@ -115,3 +126,82 @@ func Validate(ctx context.Context, node *p2p.QriNode, ref repo.DatasetRef, body,
 
 	return validate.EntryReader(er)
 }
+
+// validateChunkSize is the size of body chunks validated in parallel
+var validateChunkSize = base.DefaultBodyChunkSize
+
+// chunkSafeSchemaKeys are keywords a body schema can use at the top level &
+// still be validated one chunk of rows at a time. other keywords, like
+// minItems or uniqueItems, need the whole body
+var chunkSafeSchemaKeys = map[string]bool{
+	"$schema":     true,
+	"title":       true,
+	"description": true,
+	"type":        true,
+	"items":       true,
+}
+
+// canValidateChunks checks if a body can be validated in parallel
+func canValidateChunks(st *dataset.Structure) bool {
+	if st.Format != dataset.CSVDataFormat.String() || st.Schema["type"] != "array" {
+		return false
+	}
+	// items must be a single schema applied to every row, not a tuple
+	if _, ok := st.Schema["items"].(map[string]interface{}); !ok {
+		return false
+	}
+	for key := range st.Schema {
+		if !chunkSafeSchemaKeys[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateChunks validates a CSV body in parallel, merging errors in body
+// order with property paths relative to the whole body
+func validateChunks(st *dataset.Structure, data []byte, workers int) ([]jsonschema.ValError, error) {
+	chunks := base.SplitCSVBody(st, data, validateChunkSize)
+	results := make([][]jsonschema.ValError, len(chunks))
+	err := base.EachBodyChunk(st, chunks, workers, func(chunk base.BodyChunk, r dsio.EntryReader) error {
+		if chunk.Entries == 0 {
+			return nil
+		}
+		errs, err := validate.EntryReader(r)
+		if err != nil {
+			return err
+		}
+		for i, e := range errs {
+			errs[i].PropertyPath = offsetRowPath(e.PropertyPath, chunk.Offset)
+		}
+		results[chunk.Index] = errs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := []jsonschema.ValError{}
+	for _, errs := range results {
+		merged = append(merged, errs...)
+	}
+	return merged, nil
+}
+
+// offsetRowPath shifts the row index at the start of a property path like
+// "/3/1" by offset rows
+func offsetRowPath(path string, offset int) string {
+	if offset == 0 || !strings.HasPrefix(path, "/") {
+		return path
+	}
+	rest := path[1:]
+	end := strings.Index(rest, "/")
+	if end == -1 {
+		end = len(rest)
+	}
+	row, err := strconv.Atoi(rest[:end])
+	if err != nil {
+		return path
+	}
+	return "/" + strconv.Itoa(row+offset) + rest[end:]
+}
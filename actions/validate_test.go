@@ -1,8 +1,16 @@
 package actions
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strconv"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/jsonschema"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/repo"
 )
 
 func TestValidate(t *testing.T) {
@@ -10,7 +18,7 @@ func TestValidate(t *testing.T) {
 	node := newTestNode(t)
 	cities := addCitiesDataset(t, node)
 
-	errs, err := Validate(ctx, node, cities, nil, nil)
+	errs, err := Validate(ctx, node, cities, nil, nil, 0)
 	if err != nil {
 		t.Error(err.Error())
 	}
@@ -19,3 +27,74 @@ func TestValidate(t *testing.T) {
 		t.Errorf("expected 0 errors. got: %d", len(errs))
 	}
 }
+
+// validateTestBody generates a csv body with a header row where every
+// tenth row has a non-integer count
+func validateTestBody(rows int) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("name,count,note\n")
+	for i := 0; i < rows; i++ {
+		count := strconv.Itoa(i)
+		if i%10 == 0 {
+			count = "not_a_number"
+		}
+		fmt.Fprintf(buf, "row_%d,%s,\"note, with a\nnewline\"\n", i, count)
+	}
+	return buf.Bytes()
+}
+
+var validateTestSchema = []byte(`{
+  "type": "array",
+  "items": {
+    "type": "array",
+    "items": [
+      {"title": "name", "type": "string"},
+      {"title": "count", "type": "integer"},
+      {"title": "note", "type": "string"}
+    ]
+  }
+}`)
+
+func TestValidateParallel(t *testing.T) {
+	ctx := context.Background()
+	node := newTestNode(t)
+
+	prevChunkSize := validateChunkSize
+	validateChunkSize = 512
+	defer func() { validateChunkSize = prevChunkSize }()
+
+	body := validateTestBody(200)
+	validateWith := func(workers int) []jsonschema.ValError {
+		errs, err := Validate(ctx, node, repo.DatasetRef{}, qfs.NewMemfileBytes("body.csv", body), qfs.NewMemfileBytes("schema.json", validateTestSchema), workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return errs
+	}
+
+	expect := validateWith(1)
+	if len(expect) != 20 {
+		t.Fatalf("expected 20 sequential validation errors, got: %d", len(expect))
+	}
+	got := validateWith(4)
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("parallel validation mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	ctx := context.Background()
+	node := newTestNode(b)
+	body := validateTestBody(200000)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers_%d", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				if _, err := Validate(ctx, node, repo.DatasetRef{}, qfs.NewMemfileBytes("body.csv", body), qfs.NewMemfileBytes("schema.json", validateTestSchema), workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
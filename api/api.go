@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -37,14 +38,24 @@ func init() {
 // Create one with New, start it up with Serve
 type Server struct {
 	*lib.Instance
+	// RateLimiter limits requests per client, nil disables rate limiting
+	RateLimiter RateLimiter
+	// requests tracks in-flight requests for graceful shutdown
+	requests *requestTracker
 }
 
 // New creates a new qri server from a p2p node & configuration
 func New(inst *lib.Instance) (s Server) {
-	return Server{Instance: inst}
+	s = Server{Instance: inst, requests: newRequestTracker()}
+	if cfg := inst.Config(); cfg != nil {
+		s.RateLimiter = NewRateLimiter(cfg.API)
+	}
+	return s
 }
 
-// Serve starts the server. It will block while the server is running
+// Serve starts the server. It will block while the server is running. When
+// ctx is cancelled the server stops accepting connections & waits up to
+// cfg.API.ShutdownTimeout seconds for in-flight requests before returning
 func (s Server) Serve(ctx context.Context) (err error) {
 	node := s.Node()
 	cfg := s.Config()
@@ -53,6 +64,9 @@ func (s Server) Serve(ctx context.Context) (err error) {
 		return err
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	server := &http.Server{}
 	mux := NewServerRoutes(s)
 	server.Handler = mux
@@ -113,21 +127,31 @@ func (s Server) Serve(ctx context.Context) (err error) {
 
 	if cfg.API.DisconnectAfter != 0 {
 		log.Infof("disconnecting after %d seconds", cfg.API.DisconnectAfter)
-		go func(s *http.Server, t int) {
-			<-time.After(time.Second * time.Duration(t))
-			log.Infof("disconnecting")
-			s.Close()
-		}(server, cfg.API.DisconnectAfter)
+		go func(t int) {
+			select {
+			case <-time.After(time.Second * time.Duration(t)):
+				log.Infof("disconnecting")
+				cancel()
+			case <-ctx.Done():
+			}
+		}(cfg.API.DisconnectAfter)
 	}
 
+	drained := make(chan struct{})
 	go func() {
 		<-ctx.Done()
 		log.Info("shutting down")
-		server.Close()
+		s.drain(server, cfg.API.ShutdownWait())
+		close(drained)
 	}()
 
 	// http.ListenAndServe will not return unless there's an error
-	return StartServer(cfg.API, server)
+	err = StartServer(cfg.API, server)
+	if err == http.ErrServerClosed {
+		// listening stops as soon as shutdown starts, wait for requests to drain
+		<-drained
+	}
+	return err
 }
 
 // ServeRPC checks for a configured RPC port, and registers a listner if so
@@ -166,6 +190,10 @@ func (s *Server) HandleIPFSPath(w http.ResponseWriter, r *http.Request) {
 		readOnlyResponse(w, "/ipfs/")
 		return
 	}
+	if s.Config().Remote.Restricted() {
+		restrictedRemoteResponse(w, "/ipfs/")
+		return
+	}
 
 	s.fetchCAFSPath(r.URL.Path, w, r)
 }
@@ -187,6 +215,10 @@ func (s Server) HandleIPNSPath(w http.ResponseWriter, r *http.Request) {
 		readOnlyResponse(w, "/ipns/")
 		return
 	}
+	if s.Config().Remote.Restricted() {
+		restrictedRemoteResponse(w, "/ipns/")
+		return
+	}
 
 	namesys, err := node.GetIPFSNamesys()
 	if err != nil {
@@ -214,6 +246,13 @@ func readOnlyResponse(w http.ResponseWriter, endpoint string) {
 	apiutil.WriteErrResponse(w, http.StatusForbidden, fmt.Errorf("qri server is in read-only mode, access to '%s' endpoint is forbidden", endpoint))
 }
 
+// restrictedRemoteResponse refuses raw content requests on remotes that
+// limit the datasets they serve, raw paths can't be checked against access
+// lists
+func restrictedRemoteResponse(w http.ResponseWriter, endpoint string) {
+	apiutil.WriteErrResponse(w, http.StatusForbidden, fmt.Errorf("remote restricts the datasets it serves, access to '%s' endpoint is forbidden", endpoint))
+}
+
 // HealthCheckHandler is a basic ok response for load balancers & co
 // returns the version of qri this node is running, pulled from the lib package
 func HealthCheckHandler(w http.ResponseWriter, r *http.Request) {
@@ -221,6 +260,51 @@ func HealthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{ "meta": { "code": 200, "status": "ok", "version":"` + lib.VersionNumber + `" }, "data": [] }`))
 }
 
+// HealthHandler responds like HealthCheckHandler for cheap liveness checks.
+// With a verbose=true query param it reports store reachability, network
+// status & peer count, responding 503 when the node isn't ready
+func (s Server) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.FormValue("verbose") != "true" {
+		HealthCheckHandler(w, r)
+		return
+	}
+
+	h := s.Health(r.Context())
+	code, status := http.StatusOK, "ok"
+	if !h.Ready() {
+		code, status = http.StatusServiceUnavailable, "unavailable"
+	}
+
+	env := map[string]interface{}{
+		"meta": map[string]interface{}{
+			"code":    code,
+			"status":  status,
+			"version": lib.VersionNumber,
+		},
+		"data": h,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(env); err != nil {
+		log.Debugf("writing health response: %s", err)
+	}
+}
+
+// StatsHandler reports runtime metrics on the size of the store & repo
+func (s Server) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		stats, err := s.Stats(r.Context())
+		if err != nil {
+			apiutil.WriteErrResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+		apiutil.WriteResponse(w, stats)
+	default:
+		apiutil.NotFoundHandler(w, r)
+	}
+}
+
 // NewServerRoutes returns a Muxer that has all API routes
 func NewServerRoutes(s Server) *http.ServeMux {
 	node := s.Node()
@@ -228,7 +312,8 @@ func NewServerRoutes(s Server) *http.ServeMux {
 
 	m := http.NewServeMux()
 
-	m.Handle("/health", s.middleware(HealthCheckHandler))
+	m.Handle("/health", s.middleware(s.HealthHandler))
+	m.Handle("/stats", s.middleware(s.StatsHandler))
 	m.Handle("/ipfs/", s.middleware(s.HandleIPFSPath))
 	m.Handle("/ipns/", s.middleware(s.HandleIPNSPath))
 
@@ -238,6 +323,9 @@ func NewServerRoutes(s Server) *http.ServeMux {
 	m.Handle("/profile/photo", s.middleware(proh.ProfilePhotoHandler))
 	m.Handle("/profile/poster", s.middleware(proh.PosterHandler))
 
+	ch := NewConfigHandlers(s.Instance, cfg.API.ReadOnly)
+	m.Handle("/config", s.middleware(ch.ConfigHandler))
+
 	ph := NewPeerHandlers(node, cfg.API.ReadOnly)
 	m.Handle("/peers", s.middleware(ph.PeersHandler))
 	m.Handle("/peers/", s.middleware(ph.PeerHandler))
@@ -257,14 +345,19 @@ func NewServerRoutes(s Server) *http.ServeMux {
 	m.Handle("/list/", s.middleware(dsh.PeerListHandler))
 	m.Handle("/save", s.middleware(dsh.SaveHandler))
 	m.Handle("/save/", s.middleware(dsh.SaveHandler))
+	m.Handle("/save/batch", s.middleware(dsh.SaveBatchHandler))
 	m.Handle("/remove/", s.middleware(dsh.RemoveHandler))
 	m.Handle("/me/", s.middleware(dsh.GetHandler))
 	m.Handle("/add/", s.middleware(dsh.AddHandler))
+	m.Handle("/fetchbody/", s.middleware(dsh.FetchBodyHandler))
 	m.Handle("/rename", s.middleware(dsh.RenameHandler))
 	m.Handle("/export/", s.middleware(dsh.ZipDatasetHandler))
 	m.Handle("/diff", s.middleware(dsh.DiffHandler))
 	m.Handle("/body/", s.middleware(dsh.BodyHandler))
+	m.Handle("/attachment/", s.middleware(dsh.AttachmentHandler))
+	m.Handle("/manifest/", s.middleware(dsh.ManifestHandler))
 	m.Handle("/unpack/", s.middleware(dsh.UnpackHandler))
+	m.Handle("/transform/preview/", s.middleware(dsh.PreviewTransformHandler))
 
 	remClientH := NewRemoteClientHandlers(s.Instance, cfg.API.ReadOnly)
 	m.Handle("/publish/", s.middleware(remClientH.PublishHandler))
@@ -278,6 +371,7 @@ func NewServerRoutes(s Server) *http.ServeMux {
 	m.Handle("/update/logs", s.middleware(uh.LogsHandler))
 	m.Handle("/update/logs/file", s.middleware(uh.LogFileHandler))
 	m.Handle("/update/service", s.middleware(uh.ServiceHandler))
+	m.Handle("/update/health", s.middleware(uh.HealthHandler))
 
 	fsih := NewFSIHandlers(s.Instance, cfg.API.ReadOnly)
 	m.Handle("/status/", s.middleware(fsih.StatusHandler("/status")))
@@ -292,6 +386,9 @@ func NewServerRoutes(s Server) *http.ServeMux {
 	lh := NewLogHandlers(node)
 	m.Handle("/history/", s.middleware(lh.LogHandler))
 
+	eh := NewEventHandlers(node.Repo, cfg.API.AllowedOrigins, cfg.API.ReadOnly)
+	m.Handle("/events", s.middleware(eh.EventsHandler))
+
 	rch := NewRegistryClientHandlers(s.Instance, cfg.API.ReadOnly)
 	m.Handle("/registry/profile/new", s.middleware(rch.CreateProfileHandler))
 	m.Handle("/registry/profile/prove", s.middleware(rch.ProveProfileKeyHandler))
@@ -299,6 +396,9 @@ func NewServerRoutes(s Server) *http.ServeMux {
 	sh := NewSearchHandlers(s.Instance)
 	m.Handle("/search", s.middleware(sh.SearchHandler))
 
+	sqh := NewSQLHandlers(s.Instance, cfg.API.ReadOnly)
+	m.Handle("/sql", s.middleware(sqh.SQLHandler))
+
 	rh := NewRootHandler(dsh, ph)
 	m.Handle("/", s.datasetRefMiddleware(s.middleware(rh.Handler)))
 
@@ -148,13 +148,11 @@ func TestServerRoutes(t *testing.T) {
 	inst := newTestInstanceWithProfileFromNode(node)
 	h := NewRootHandler(NewDatasetHandlers(inst, false), NewPeerHandlers(node, false))
 	rootCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 	}
 	runHandlerTestCases(t, "root", h.Handler, rootCases, true)
 
 	healthCheckCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 	}
 	runHandlerTestCases(t, "health check", HealthCheckHandler, healthCheckCases, true)
@@ -227,6 +225,7 @@ func TestServerReadOnlyRoutes(t *testing.T) {
 		{"GET", "/me/", 403},
 		{"POST", "/add/", 403},
 		{"PUT", "/add/", 403},
+		{"POST", "/fetchbody/", 403},
 		{"POST", "/rename", 403},
 		{"PUT", "/rename", 403},
 		{"GET", "/export/", 403},
@@ -237,9 +236,14 @@ func TestServerReadOnlyRoutes(t *testing.T) {
 		{"GET", "/checkout", 403},
 		{"GET", "/status", 403},
 		{"GET", "/init", 403},
+		{"PUT", "/config", 403},
+		{"GET", "/sql", 403},
+		{"GET", "/events", 403},
 
 		// active endpoints:
 		{"GET", "/health", 200},
+		{"GET", "/stats", 200},
+		{"GET", "/config", 200},
 		{"GET", "/list/peer", 200},
 		// Cannot test connect endpoint until we have peers in this test suite
 		// {"GET", "/connect/QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt", 200},
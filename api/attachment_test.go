@@ -0,0 +1,66 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestAttachmentHandler(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+	inst := newTestInstanceWithProfileFromNode(node)
+
+	dir, err := ioutil.TempDir("", "api_attachment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	codebook := filepath.Join(dir, "codebook.txt")
+	if err := ioutil.WriteFile(codebook, []byte("codebook"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dsr := lib.NewDatasetRequestsInstance(inst)
+	if err := dsr.Save(&lib.SaveParams{Ref: "peer/movies", Attachments: []string{codebook}}, &repo.DatasetRef{}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewDatasetHandlers(inst, false)
+	cases := []struct {
+		method, endpoint string
+		code             int
+		body             string
+	}{
+		{"GET", "/attachment/peer/movies?name=codebook.txt", http.StatusOK, "codebook"},
+		{"GET", "/attachment/peer/movies?name=missing.pdf", http.StatusNotFound, ""},
+		{"GET", "/attachment/peer/movies", http.StatusBadRequest, ""},
+		{"DELETE", "/attachment/peer/movies", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.AttachmentHandler(w, httptest.NewRequest(c.method, c.endpoint, nil))
+		if w.Code != c.code {
+			t.Errorf("%s %s status mismatch. expected: %d, got: %d\n%s", c.method, c.endpoint, c.code, w.Code, w.Body.String())
+			continue
+		}
+		if c.body != "" && w.Body.String() != c.body {
+			t.Errorf("%s %s body mismatch. expected: %q, got: %q", c.method, c.endpoint, c.body, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.AttachmentHandler(w, httptest.NewRequest("GET", "/attachment/peer/movies?name=codebook.txt", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("content type mismatch. got: %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="codebook.txt"` {
+		t.Errorf("content disposition mismatch. got: %q", cd)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	util "github.com/qri-io/apiutil"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/lib"
+)
+
+// ConfigHandlers wraps lib.ConfigMethods, adding HTTP JSON API handles
+type ConfigHandlers struct {
+	lib.ConfigMethods
+	ReadOnly bool
+}
+
+// NewConfigHandlers allocates a ConfigHandlers pointer
+func NewConfigHandlers(inst *lib.Instance, readOnly bool) *ConfigHandlers {
+	return &ConfigHandlers{
+		ConfigMethods: *lib.NewConfigMethods(inst),
+		ReadOnly:      readOnly,
+	}
+}
+
+// ConfigHandler is the endpoint for reading & replacing the configuration.
+// private keys are never included in responses
+func (h *ConfigHandlers) ConfigHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		h.getConfigHandler(w, r)
+	case "PUT":
+		if h.ReadOnly {
+			readOnlyResponse(w, "/config")
+			return
+		}
+		h.setConfigHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h *ConfigHandlers) getConfigHandler(w http.ResponseWriter, r *http.Request) {
+	p := &lib.GetConfigParams{
+		Field:   r.FormValue("field"),
+		Format:  "json",
+		Concise: true,
+	}
+	var res []byte
+	if err := h.GetConfig(p, &res); err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	util.WriteResponse(w, json.RawMessage(res))
+}
+
+func (h *ConfigHandlers) setConfigHandler(w http.ResponseWriter, r *http.Request) {
+	cfg := &config.Config{}
+	if err := json.NewDecoder(r.Body).Decode(cfg); err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("error decoding request body: %s", err.Error()))
+		return
+	}
+
+	// private values are never sent to clients, so updates won't have them.
+	// SetConfig carries private values over from the current config
+	var set bool
+	if err := h.SetConfig(cfg, &set); err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.getConfigHandler(w, r)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/lib"
+)
+
+func TestConfigHandler(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	// profiles encoded from the repo drop their private key, use the full
+	// testing config so updates validate
+	inst := lib.NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	h := NewConfigHandlers(inst, false)
+
+	w := httptest.NewRecorder()
+	h.ConfigHandler(w, httptest.NewRequest("GET", "/config", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code mismatch. expected: %d, got: %d. body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), inst.Config().P2P.PrivKey) {
+		t.Error("expected response not to include private key")
+	}
+
+	res := struct {
+		Data *config.Config `json:"data"`
+	}{}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	cfg := res.Data
+	cfg.Profile.Twitter = "@qri_io"
+	body, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	h.ConfigHandler(w, httptest.NewRequest("PUT", "/config", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code mismatch. expected: %d, got: %d. body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if inst.Config().Profile.Twitter != "@qri_io" {
+		t.Errorf("expected config to be updated")
+	}
+	if inst.Config().P2P.PrivKey == "" {
+		t.Errorf("expected private key to be kept")
+	}
+
+	w = httptest.NewRecorder()
+	h.ConfigHandler(w, httptest.NewRequest("GET", "/config?field=profile.twitter", nil))
+	if !strings.Contains(w.Body.String(), `"@qri_io"`) {
+		t.Errorf("expected field response. got: %s", w.Body.String())
+	}
+
+	h.ReadOnly = true
+	w = httptest.NewRecorder()
+	h.ConfigHandler(w, httptest.NewRequest("PUT", "/config", bytes.NewReader(body)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status code mismatch. expected: %d, got: %d", http.StatusForbidden, w.Code)
+	}
+}
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"bufio"
+	"encoding/json"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
 )
 
 func TestDatasetHandlers(t *testing.T) {
@@ -18,7 +25,6 @@ func TestDatasetHandlers(t *testing.T) {
 	h := NewDatasetHandlers(inst, false)
 
 	listCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 		{"DELETE", "/", nil},
 	}
@@ -26,21 +32,18 @@ func TestDatasetHandlers(t *testing.T) {
 
 	// TODO: Remove this case, update API snapshot.
 	initCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"POST", "/", mustFile(t, "testdata/newRequestFromURL.json")},
 		{"DELETE", "/", nil},
 	}
 	runHandlerTestCases(t, "init", h.SaveHandler, initCases, true)
 
 	saveCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"POST", "/", mustFile(t, "testdata/newRequestFromURL.json")},
 		{"DELETE", "/", nil},
 	}
 	runHandlerTestCases(t, "save", h.SaveHandler, saveCases, true)
 
 	getCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/me/family_relationships", nil},
 		{"GET", "/me/family_relationships/at/map/Qme7LVBp6hfi4Y5N29CXeXjpAqgT3fWtAmQWtZgjpQAZph", nil},
 		{"GET", "/at/map/Qme7LVBp6hfi4Y5N29CXeXjpAqgT3fWtAmQWtZgjpQAZph", nil},
@@ -52,7 +55,6 @@ func TestDatasetHandlers(t *testing.T) {
 	runHandlerTestCases(t, "get", h.GetHandler, getCases, true)
 
 	bodyCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/body/me/family_relationships", nil},
 		{"GET", "/body/me/family_relationships?download=true", nil},
 		{"DELETE", "/", nil},
@@ -60,14 +62,12 @@ func TestDatasetHandlers(t *testing.T) {
 	runHandlerTestCases(t, "body", h.BodyHandler, bodyCases, true)
 
 	renameCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"POST", "/rename", mustFile(t, "testdata/renameRequest.json")},
 		{"DELETE", "/", nil},
 	}
 	runHandlerTestCases(t, "rename", h.RenameHandler, renameCases, true)
 
 	exportCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/export/me/cities", nil},
 		{"GET", "/export/me/cities/at/map/QmPRjfgUFrH1GxBqujJ3sEvwV3gzHdux1j4g8SLyjbhwot", nil},
 		{"DELETE", "/", nil},
@@ -77,20 +77,17 @@ func TestDatasetHandlers(t *testing.T) {
 	// TODO: Perhaps add an option to runHandlerTestCases to set Content-Type, then combin, truee
 	// `runHandlerZipPostTestCases` with `runHandlerTestCases`, true.
 	unpackCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"POST", "/unpack/", mustFile(t, "testdata/exported.zip")},
 	}
 	runHandlerZipPostTestCases(t, "unpack", h.UnpackHandler, unpackCases)
 
 	diffCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/?left_path=me/family_relationships&right_path=me/cities", nil},
 		{"DELETE", "/", nil},
 	}
 	runHandlerTestCases(t, "diff", h.DiffHandler, diffCases, false)
 
 	removeCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 		{"POST", "/remove/me/cities", nil},
 		{"POST", "/remove/at/map/QmPRjfgUFrH1GxBqujJ3sEvwV3gzHdux1j4g8SLyjbhwot", nil},
@@ -144,6 +141,95 @@ func TestDatasetHandlers(t *testing.T) {
 	runMimeMultipartHandlerTestCases(t, "save mime/multipart", h.SaveHandler, newMimeCases)
 }
 
+func TestListHandlerNDJSON(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	// use a small page size to exercise streaming across multiple pages
+	prevPageSize := listStreamPageSize
+	listStreamPageSize = 2
+	defer func() { listStreamPageSize = prevPageSize }()
+
+	inst := newTestInstanceWithProfileFromNode(node)
+	h := NewDatasetHandlers(inst, false)
+
+	req := httptest.NewRequest("GET", "/list", nil)
+	req.Header.Set("Accept", NDJSONMimeType)
+	w := httptest.NewRecorder()
+	h.ListHandler(w, req)
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status code mismatch. expected: %d, got: %d", http.StatusOK, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != NDJSONMimeType {
+		t.Errorf("content type mismatch. expected: %q, got: %q", NDJSONMimeType, ct)
+	}
+
+	names := []string{}
+	sc := bufio.NewScanner(res.Body)
+	for sc.Scan() {
+		ref := repo.DatasetRef{}
+		if err := json.Unmarshal(sc.Bytes(), &ref); err != nil {
+			t.Fatalf("line %d isn't a dataset reference: %s", len(names), err)
+		}
+		names = append(names, ref.Name)
+	}
+
+	expect := []string{"cities", "counter", "craigslist", "movies", "sitemap"}
+	if len(names) != len(expect) {
+		t.Fatalf("line count mismatch. expected: %d, got: %d", len(expect), len(names))
+	}
+	for i, name := range expect {
+		if names[i] != name {
+			t.Errorf("line %d name mismatch. expected: %q, got: %q", i, name, names[i])
+		}
+	}
+}
+
+func TestBodyHandlerGeoJSON(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	inst := newTestInstanceWithProfileFromNode(node)
+	h := NewDatasetHandlers(inst, false)
+
+	saveParams := &lib.SaveParams{
+		Ref: "me/places",
+		Dataset: &dataset.Dataset{
+			Structure: &dataset.Structure{Format: "json"},
+			BodyPath:  "places.json",
+			BodyBytes: []byte(`[{"name":"toronto","lat":43.65,"lon":-79.38},{"name":"nowhere"}]`),
+		},
+	}
+	if err := h.Save(saveParams, &repo.DatasetRef{}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/body/me/places?format=geojson", nil)
+	w := httptest.NewRecorder()
+	h.BodyHandler(w, req)
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status code mismatch. expected: %d, got: %d", http.StatusOK, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "application/geo+json" {
+		t.Errorf("content type mismatch. expected: %q, got: %q", "application/geo+json", ct)
+	}
+	expect := `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[-79.38,43.65]},"properties":{"name":"toronto"}},{"type":"Feature","geometry":null,"properties":{"name":"nowhere"}}]}`
+	if got := w.Body.String(); got != expect {
+		t.Errorf("body mismatch.\nexpected: %s\ngot:      %s", expect, got)
+	}
+
+	req = httptest.NewRequest("GET", "/body/me/cities?format=geojson", nil)
+	w = httptest.NewRecorder()
+	h.BodyHandler(w, req)
+	if w.Result().StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected dataset without geometry to return status %d, got: %d", http.StatusUnprocessableEntity, w.Result().StatusCode)
+	}
+}
+
 func newMockDataServer(t *testing.T) *httptest.Server {
 	mockData := []byte(`Parent Identifier,Student Identifier
 1001,1002
@@ -160,3 +246,123 @@ func newMockDataServer(t *testing.T) *httptest.Server {
 	mockDataServer.Start()
 	return mockDataServer
 }
+
+func TestPreviewTransformHandler(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	inst := newTestInstanceWithProfileFromNode(node)
+	h := NewDatasetHandlers(inst, false)
+
+	saveParams := &lib.SaveParams{
+		Ref: "me/preview",
+		Dataset: &dataset.Dataset{
+			Structure: &dataset.Structure{Format: "json"},
+			BodyPath:  "body.json",
+			BodyBytes: []byte(`[["a"]]`),
+		},
+	}
+	ref := &repo.DatasetRef{}
+	if err := h.Save(saveParams, ref); err != nil {
+		t.Fatal(err)
+	}
+
+	tf := `{"syntax":"starlark","scriptBytes":"ZGVmIHRyYW5zZm9ybShkcywgY3R4KToKICBkcy5zZXRfYm9keShbWyJiIl1dKQo="}`
+	req := httptest.NewRequest("POST", "/transform/preview/me/preview", strings.NewReader(tf))
+	w := httptest.NewRecorder()
+	h.PreviewTransformHandler(w, req)
+	if w.Result().StatusCode != http.StatusOK {
+		t.Fatalf("status code mismatch. expected: %d, got: %d. body: %s", http.StatusOK, w.Result().StatusCode, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"body":[["b"]]`) {
+		t.Errorf("expected previewed body in response, got: %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/transform/preview/me/preview", strings.NewReader(`{"scriptPath":"/etc/passwd"}`))
+	w = httptest.NewRecorder()
+	h.PreviewTransformHandler(w, req)
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected local script path to return status %d, got: %d", http.StatusBadRequest, w.Result().StatusCode)
+	}
+
+	ro := NewDatasetHandlers(inst, true)
+	req = httptest.NewRequest("POST", "/transform/preview/me/preview", strings.NewReader(tf))
+	w = httptest.NewRecorder()
+	ro.PreviewTransformHandler(w, req)
+	if w.Result().StatusCode != http.StatusForbidden {
+		t.Errorf("expected read-only preview to return status %d, got: %d", http.StatusForbidden, w.Result().StatusCode)
+	}
+}
+
+func TestSaveBatchHandler(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	inst := newTestInstanceWithProfileFromNode(node)
+	h := NewDatasetHandlers(inst, false)
+
+	batch := `[
+	{"ref":"me/batch_one","dataset":{"structure":{"format":"json"},"bodyPath":"body.json","bodyBytes":"W1siYSJdXQ=="}},
+	{"ref":"me/bad name","dataset":{"structure":{"format":"json"},"bodyPath":"body.json","bodyBytes":"W1siYSJdXQ=="}},
+	{"ref":"me/batch_two","title":"second","dataset":{"structure":{"format":"json"},"bodyPath":"body.json","bodyBytes":"W1siYiJdXQ=="}}
+]`
+	w := httptest.NewRecorder()
+	h.SaveBatchHandler(w, httptest.NewRequest("POST", "/save/batch", strings.NewReader(batch)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status code mismatch. expected: %d, got: %d. body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	res := struct {
+		Data []SaveBatchResult `json:"data"`
+	}{}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 3 {
+		t.Fatalf("expected 3 results, got: %d", len(res.Data))
+	}
+	for i, name := range []string{"batch_one", "", "batch_two"} {
+		got := res.Data[i]
+		if name == "" {
+			if got.Error == "" || got.Result != nil {
+				t.Errorf("result %d: expected an error, got: %v", i, got)
+			}
+			continue
+		}
+		if got.Error != "" {
+			t.Errorf("result %d: unexpected error: %s", i, got.Error)
+			continue
+		}
+		if got.Result == nil || got.Result.Name != name {
+			t.Errorf("result %d: expected saved dataset %q, got: %v", i, name, got.Result)
+		}
+	}
+	if res.Data[2].Result != nil && res.Data[2].Result.Dataset.Commit.Title != "second" {
+		t.Errorf("expected commit title to be set. got: %q", res.Data[2].Result.Dataset.Commit.Title)
+	}
+
+	w = httptest.NewRecorder()
+	h.SaveBatchHandler(w, httptest.NewRequest("POST", "/save/batch", strings.NewReader(`[]`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected empty batch to return status %d, got: %d", http.StatusBadRequest, w.Code)
+	}
+
+	pro, err := node.Repo.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	me := h.saveBatchKey(SaveBatchItem{Ref: "me/batch_one"})
+	if peer := h.saveBatchKey(SaveBatchItem{Ref: pro.Peername + "/batch_one"}); me != peer {
+		t.Errorf("expected references to the same dataset to share a key. got: %q, %q", me, peer)
+	}
+	if me, peer := h.saveBatchKey(SaveBatchItem{Ref: "me/new_ds"}), h.saveBatchKey(SaveBatchItem{Ref: pro.Peername + "/new_ds"}); me != peer {
+		t.Errorf("expected references to a new dataset to share a key. got: %q, %q", me, peer)
+	}
+
+	ro := NewDatasetHandlers(inst, true)
+	w = httptest.NewRecorder()
+	ro.SaveBatchHandler(w, httptest.NewRequest("POST", "/save/batch", strings.NewReader(batch)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected read-only batch save to return status %d, got: %d", http.StatusForbidden, w.Code)
+	}
+}
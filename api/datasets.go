@@ -5,14 +5,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	util "github.com/qri-io/apiutil"
+	"github.com/qri-io/dag"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsutil"
 	"github.com/qri-io/qri/fsi"
@@ -41,8 +47,6 @@ func NewDatasetHandlers(inst *lib.Instance, readOnly bool) *DatasetHandlers {
 // ListHandler is a dataset list endpoint
 func (h *DatasetHandlers) ListHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/list")
@@ -57,8 +61,6 @@ func (h *DatasetHandlers) ListHandler(w http.ResponseWriter, r *http.Request) {
 // SaveHandler is a dataset save/update endpoint
 func (h *DatasetHandlers) SaveHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "PUT", "POST":
 		h.saveHandler(w, r)
 	default:
@@ -66,11 +68,23 @@ func (h *DatasetHandlers) SaveHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// SaveBatchHandler saves a list of datasets in a single request
+func (h *DatasetHandlers) SaveBatchHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "PUT", "POST":
+		if h.ReadOnly {
+			readOnlyResponse(w, "/save/batch")
+			return
+		}
+		h.saveBatchHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
 // RemoveHandler is a a dataset delete endpoint
 func (h *DatasetHandlers) RemoveHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "DELETE", "POST":
 		h.removeHandler(w, r)
 	default:
@@ -81,8 +95,6 @@ func (h *DatasetHandlers) RemoveHandler(w http.ResponseWriter, r *http.Request)
 // GetHandler is a dataset single endpoint
 func (h *DatasetHandlers) GetHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/me/")
@@ -97,8 +109,6 @@ func (h *DatasetHandlers) GetHandler(w http.ResponseWriter, r *http.Request) {
 // DiffHandler is a dataset single endpoint
 func (h *DatasetHandlers) DiffHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "POST", "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/diff")
@@ -110,11 +120,24 @@ func (h *DatasetHandlers) DiffHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// PreviewTransformHandler runs a transform & returns the resulting dataset
+// without saving it
+func (h *DatasetHandlers) PreviewTransformHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST":
+		if h.ReadOnly {
+			readOnlyResponse(w, "/transform/preview/")
+			return
+		}
+		h.previewTransformHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
 // PeerListHandler is a dataset list endpoint
 func (h *DatasetHandlers) PeerListHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.peerListHandler(w, r)
 	default:
@@ -125,8 +148,6 @@ func (h *DatasetHandlers) PeerListHandler(w http.ResponseWriter, r *http.Request
 // AddHandler is an endpoint for creating new datasets
 func (h *DatasetHandlers) AddHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "POST", "PUT":
 		h.addHandler(w, r)
 	default:
@@ -134,11 +155,20 @@ func (h *DatasetHandlers) AddHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// FetchBodyHandler is an endpoint for fetching the body of a dataset that was
+// added without one
+func (h *DatasetHandlers) FetchBodyHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST", "PUT":
+		h.fetchBodyHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
 // RenameHandler is the endpoint for renaming datasets
 func (h *DatasetHandlers) RenameHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "POST", "PUT":
 		h.renameHandler(w, r)
 	default:
@@ -149,8 +179,6 @@ func (h *DatasetHandlers) RenameHandler(w http.ResponseWriter, r *http.Request)
 // BodyHandler gets the contents of a dataset
 func (h *DatasetHandlers) BodyHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/body/")
@@ -162,11 +190,83 @@ func (h *DatasetHandlers) BodyHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// AttachmentHandler is the endpoint for downloading a dataset file attachment
+func (h *DatasetHandlers) AttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		if h.ReadOnly {
+			readOnlyResponse(w, "/attachment/")
+			return
+		}
+		h.attachmentHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h *DatasetHandlers) attachmentHandler(w http.ResponseWriter, r *http.Request) {
+	p := &lib.AttachmentParams{
+		Ref:  HTTPPathToQriPath(r.URL.Path[len("/attachment"):]),
+		Name: r.FormValue("name"),
+	}
+
+	var data []byte
+	if err := h.Attachment(p, &data); err != nil {
+		if libErr, ok := err.(lib.Error); ok {
+			status := http.StatusBadRequest
+			if libErr.Error() == lib.ErrAttachmentNotFound.Error() {
+				status = http.StatusNotFound
+			}
+			util.WriteErrResponse(w, status, errors.New(libErr.Message()))
+			return
+		}
+		util.WriteErrResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(p.Name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(p.Name)))
+	w.Write(data)
+}
+
+// ManifestHandler is the endpoint for getting the manifest of blocks that
+// make up a dataset version. manifests are read-only, so they're available in
+// read-only mode
+func (h *DatasetHandlers) ManifestHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		h.manifestHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h *DatasetHandlers) manifestHandler(w http.ResponseWriter, r *http.Request) {
+	refstr := HTTPPathToQriPath(r.URL.Path[len("/manifest"):])
+	mf := &dag.Manifest{}
+	if err := h.Manifest(&refstr, mf); err != nil {
+		if err == repo.ErrNotFound {
+			util.NotFoundHandler(w, r)
+			return
+		}
+		if err == repo.ErrEmptyRef {
+			util.WriteErrResponse(w, http.StatusBadRequest, err)
+			return
+		}
+		util.WriteErrResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	util.WriteResponse(w, mf)
+}
+
 // UnpackHandler unpacks a zip file and sends it back as json
 func (h *DatasetHandlers) UnpackHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "POST":
 		postData, err := ioutil.ReadAll(r.Body)
 		if err != nil {
@@ -182,8 +282,6 @@ func (h *DatasetHandlers) UnpackHandler(w http.ResponseWriter, r *http.Request)
 // ZipDatasetHandler is the endpoint for getting a zip archive of a dataset
 func (h *DatasetHandlers) ZipDatasetHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/export/")
@@ -205,7 +303,15 @@ func (h *DatasetHandlers) zipDatasetHandler(w http.ResponseWriter, r *http.Reque
 		util.WriteErrResponse(w, http.StatusInternalServerError, err)
 		return
 	}
+	defer os.RemoveAll(tmpDir)
 	params := lib.ExportParams{Ref: ref, TargetDir: tmpDir, Format: format, Zipped: zipped}
+	if components := r.FormValue("components"); components != "" {
+		for _, name := range strings.Split(components, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				params.Components = append(params.Components, name)
+			}
+		}
+	}
 
 	var fileWritten string
 	req := lib.NewExportRequests(h.node, nil)
@@ -220,15 +326,11 @@ func (h *DatasetHandlers) zipDatasetHandler(w http.ResponseWriter, r *http.Reque
 		util.WriteErrResponse(w, http.StatusInternalServerError, err)
 		return
 	}
-	bytes, err := ioutil.ReadAll(f)
-	if err != nil {
-		util.WriteErrResponse(w, http.StatusInternalServerError, err)
-		return
-	}
+	defer f.Close()
 
 	w.Header().Set("Content-Type", extensionToMimeType(path.Ext(fileWritten)))
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", path.Base(fileWritten)))
-	w.Write(bytes)
+	io.Copy(w, f)
 }
 
 func extensionToMimeType(ext string) string {
@@ -241,12 +343,45 @@ func extensionToMimeType(ext string) string {
 		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 	case ".zip":
 		return "application/zip"
+	case ".geojson":
+		return "application/geo+json"
+	case ".arrow":
+		return ArrowStreamMimeType
+	case ".feather":
+		return ArrowFileMimeType
+	case ".ndjson", ".jsonl":
+		return NDJSONMimeType
 	default:
 		return ""
 	}
 }
 
+// ArrowStreamMimeType is the content type for Arrow IPC streams
+const ArrowStreamMimeType = "application/vnd.apache.arrow.stream"
+
+// ArrowFileMimeType is the content type for Arrow IPC files
+const ArrowFileMimeType = "application/vnd.apache.arrow.file"
+
+// NDJSONMimeType is the content type for newline-delimited JSON. list requests
+// that accept this type are streamed one reference per line
+const NDJSONMimeType = "application/x-ndjson"
+
+// listStreamPageSize is the number of references a streaming list loads at a
+// time
+var listStreamPageSize = 100
+
+// streamError is written as the final line of an NDJSON stream when an error
+// occurs after the response has started
+type streamError struct {
+	Error string `json:"error"`
+}
+
 func (h *DatasetHandlers) listHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.Header.Get("Accept"), NDJSONMimeType) {
+		h.listStreamHandler(w, r)
+		return
+	}
+
 	args := lib.ListParamsFromRequest(r)
 	args.OrderBy = "created"
 
@@ -263,6 +398,56 @@ func (h *DatasetHandlers) listHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// listStreamHandler writes the complete list of datasets as newline-delimited
+// JSON, loading & flushing references one page at a time. Errors encountered
+// before any references are written produce a standard error response, errors
+// after the stream has started are written as a final {"error": "..."} line
+func (h *DatasetHandlers) listStreamHandler(w http.ResponseWriter, r *http.Request) {
+	args := lib.ListParamsFromRequest(r)
+	args.OrderBy = "created"
+	args.Term = r.FormValue("term")
+	args.Limit = listStreamPageSize
+	args.Offset = 0
+
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	started := false
+
+	for {
+		res := []repo.DatasetRef{}
+		if err := h.List(&args, &res); err != nil {
+			log.Infof("error listing datasets: %s", err.Error())
+			if !started {
+				util.WriteErrResponse(w, http.StatusInternalServerError, err)
+				return
+			}
+			enc.Encode(streamError{Error: err.Error()})
+			return
+		}
+
+		if !started {
+			w.Header().Set("Content-Type", NDJSONMimeType)
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		for _, ref := range res {
+			if err := enc.Encode(ref); err != nil {
+				log.Infof("error writing dataset list stream: %s", err.Error())
+				return
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if len(res) < args.Limit {
+			return
+		}
+		args.Offset += len(res)
+	}
+}
+
 // TODO (ramfox): we have two places where `get` is happening, here and at root.go
 // we should deprecate the `/me` endpoint (and this handler)
 // and have the root check to see if `me` is the peername
@@ -310,6 +495,18 @@ func (h *DatasetHandlers) diffHandler(w http.ResponseWriter, r *http.Request) {
 			LeftPath:  r.FormValue("left_path"),
 			RightPath: r.FormValue("right_path"),
 			Selector:  r.FormValue("selector"),
+
+			LeftSelector:  r.FormValue("left_selector"),
+			RightSelector: r.FormValue("right_selector"),
+			Summarize:     r.FormValue("summary") == "true",
+		}
+		if limit := r.FormValue("memory_limit"); limit != "" {
+			n, err := strconv.ParseInt(limit, 10, 64)
+			if err != nil {
+				util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("invalid memory_limit: %s", err))
+				return
+			}
+			req.MemoryLimit = n
 		}
 	}
 
@@ -371,8 +568,11 @@ func (h *DatasetHandlers) addHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	p := &lib.AddParams{
-		Ref:     ref.String(),
-		LinkDir: r.FormValue("dir"),
+		Ref:       ref.String(),
+		LinkDir:   r.FormValue("dir"),
+		Lazy:      r.FormValue("lazy") == "true",
+		Verify:    r.FormValue("verify") == "true",
+		Recursive: r.FormValue("recursive") == "true",
 	}
 
 	res := repo.DatasetRef{}
@@ -385,6 +585,30 @@ func (h *DatasetHandlers) addHandler(w http.ResponseWriter, r *http.Request) {
 	util.WriteResponse(w, res)
 }
 
+func (h *DatasetHandlers) fetchBodyHandler(w http.ResponseWriter, r *http.Request) {
+	ref, err := DatasetRefFromPath(r.URL.Path[len("/fetchbody"):])
+	if err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	if ref.Peername == "" || ref.Name == "" {
+		util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("need peername and dataset name: '/fetchbody/[peername]/[datasetname]'"))
+		return
+	}
+
+	p := &lib.FetchBodyParams{
+		Ref:        ref.String(),
+		RemoteAddr: r.FormValue("remote"),
+	}
+	res := repo.DatasetRef{}
+	if err := h.FetchBody(p, &res); err != nil {
+		util.WriteErrResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	util.WriteResponse(w, res)
+}
+
 func (h *DatasetHandlers) saveHandler(w http.ResponseWriter, r *http.Request) {
 	ds := &dataset.Dataset{}
 
@@ -461,14 +685,201 @@ func (h *DatasetHandlers) saveHandler(w http.ResponseWriter, r *http.Request) {
 	util.WriteMessageResponse(w, msg, res)
 }
 
+// SaveBatchConcurrency is the number of saves in a batch that run at once
+var SaveBatchConcurrency = 4
+
+// SaveBatchItem is a single save within a batch
+type SaveBatchItem struct {
+	// Ref is the name to save to, defaults to the dataset peername & name
+	Ref      string            `json:"ref"`
+	Dataset  *dataset.Dataset  `json:"dataset"`
+	Title    string            `json:"title,omitempty"`
+	Message  string            `json:"message,omitempty"`
+	Secrets  map[string]string `json:"secrets,omitempty"`
+	Private  bool              `json:"private,omitempty"`
+	DryRun   bool              `json:"dryRun,omitempty"`
+	Force    bool              `json:"force,omitempty"`
+	NoRender bool              `json:"noRender,omitempty"`
+}
+
+// SaveBatchResult is the outcome of a single save within a batch. Exactly one
+// of Result or Error is set
+type SaveBatchResult struct {
+	Ref    string           `json:"ref"`
+	Result *repo.DatasetRef `json:"result,omitempty"`
+	Error  string           `json:"error,omitempty"`
+	// ScriptOutput holds anything printed by a transform script
+	ScriptOutput string `json:"scriptOutput,omitempty"`
+}
+
+// saveBatchHandler runs each save in a batch, reporting results in request
+// order. failed saves are reported per item & don't fail the batch
+func (h *DatasetHandlers) saveBatchHandler(w http.ResponseWriter, r *http.Request) {
+	items := []SaveBatchItem{}
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("parsing batch: %s", err))
+		return
+	}
+	if len(items) == 0 {
+		util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("batch has no datasets to save"))
+		return
+	}
+
+	// saves to the same dataset run in order, one at a time, so each builds
+	// on the version written by the save before it
+	var (
+		order  []string
+		groups = map[string][]int{}
+	)
+	for i, item := range items {
+		key := h.saveBatchKey(item)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	results := make([]SaveBatchResult, len(items))
+	sem := make(chan struct{}, SaveBatchConcurrency)
+	wg := sync.WaitGroup{}
+	for _, key := range order {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idxs []int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, i := range idxs {
+				results[i] = h.saveBatchItem(items[i])
+			}
+		}(groups[key])
+	}
+	wg.Wait()
+
+	util.WriteResponse(w, results)
+}
+
+// saveBatchRef is the reference a batch item saves to
+func saveBatchRef(item SaveBatchItem) string {
+	if item.Ref != "" || item.Dataset == nil {
+		return item.Ref
+	}
+	return repo.DatasetRef{Peername: item.Dataset.Peername, Name: item.Dataset.Name}.AliasString()
+}
+
+// saveBatchKey groups batch items by the dataset they save to. references are
+// canonicalized so different ways of writing the same dataset, like "me/ds"
+// & "peer/ds", share a key
+func (h *DatasetHandlers) saveBatchKey(item SaveBatchItem) string {
+	alias := saveBatchRef(item)
+	ref, err := repo.ParseDatasetRef(alias)
+	if err != nil {
+		return alias
+	}
+	// datasets that don't exist yet aren't found, but still have their
+	// peername resolved
+	if err = repo.CanonicalizeDatasetRef(h.repo, &ref); err != nil && err != repo.ErrNotFound {
+		return alias
+	}
+	return repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}.AliasString()
+}
+
+func (h *DatasetHandlers) saveBatchItem(item SaveBatchItem) SaveBatchResult {
+	ds := item.Dataset
+	if ds == nil {
+		ds = &dataset.Dataset{}
+	}
+	ref := saveBatchRef(item)
+
+	res := SaveBatchResult{Ref: ref}
+	scriptOutput := &bytes.Buffer{}
+	p := &lib.SaveParams{
+		Ref:          ref,
+		Dataset:      ds,
+		Title:        item.Title,
+		Message:      item.Message,
+		Secrets:      item.Secrets,
+		Private:      item.Private,
+		DryRun:       item.DryRun,
+		Force:        item.Force,
+		ShouldRender: !item.NoRender,
+
+		ConvertFormatToPrev: true,
+		ScriptOutput:        scriptOutput,
+	}
+
+	saved := &repo.DatasetRef{}
+	if err := h.Save(p, saved); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if saved.Dataset != nil {
+		// Don't leak paths across the API, it's possible they contain absolute paths or tmp dirs.
+		saved.Dataset.BodyPath = filepath.Base(saved.Dataset.BodyPath)
+	}
+	res.Result = saved
+	res.ScriptOutput = scriptOutput.String()
+	return res
+}
+
+func (h *DatasetHandlers) previewTransformHandler(w http.ResponseWriter, r *http.Request) {
+	ref, err := DatasetRefFromPath(r.URL.Path[len("/transform/preview"):])
+	if err != nil {
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	p := &lib.PreviewTransformParams{Ref: ref.AliasString()}
+
+	if r.ContentLength != 0 {
+		tf := &dataset.Transform{}
+		if err := json.NewDecoder(r.Body).Decode(tf); err != nil && err != io.EOF {
+			util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("parsing transform: %s", err))
+			return
+		}
+		if tf.ScriptBytes != nil {
+			p.Transform = tf
+			p.Secrets = tf.Secrets
+			tf.Secrets = nil
+		} else if tf.ScriptPath != "" {
+			// scripts are never read from the server's filesystem
+			util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("transform previews require scriptBytes"))
+			return
+		}
+	}
+
+	if r.FormValue("secrets") != "" {
+		p.Secrets = map[string]string{}
+		if err := json.Unmarshal([]byte(r.FormValue("secrets")), &p.Secrets); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("parsing secrets: %s", err))
+			return
+		}
+	}
+	if r.FormValue("timeout") != "" {
+		if p.Timeout, err = time.ParseDuration(r.FormValue("timeout")); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("invalid timeout: %s", err))
+			return
+		}
+	}
+
+	res := &lib.PreviewTransformResponse{}
+	if err := h.PreviewTransform(p, res); err != nil {
+		util.WriteErrResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	util.WriteMessageResponse(w, res.Output, res.Dataset)
+}
+
 func (h *DatasetHandlers) removeHandler(w http.ResponseWriter, r *http.Request) {
 	p := lib.RemoveParams{
 		Ref:            HTTPPathToQriPath(r.URL.Path[len("/remove"):]),
 		Revision:       rev.Rev{Field: "ds", Gen: -1},
 		Unlink:         r.FormValue("unlink") == "true",
 		DeleteFSIFiles: r.FormValue("files") == "true",
+		KeepFiles:      r.FormValue("keep_files") == "true",
 	}
-	if r.FormValue("all") == "true" {
+	if r.FormValue("all") == "true" || p.KeepFiles {
 		p.Revision = rev.NewAllRevisions()
 	}
 
@@ -546,18 +957,34 @@ type DataResponse struct {
 	Data json.RawMessage `json:"data"`
 }
 
+// isConvertedBodyFormat returns true for body formats lib converts from the
+// json body, which are written as-is instead of in a response envelope
+func isConvertedBodyFormat(format string) bool {
+	return format == lib.GeoJSONFormat || format == lib.ArrowFormat || format == lib.FeatherFormat || format == lib.NDJSONFormat || format == "jsonl"
+}
+
 // getParamsFromRequest creates getParams from a request. It's currently only used for paginating dataset bodies
 func getParamsFromRequest(r *http.Request, readOnly bool, path string) (*lib.GetParams, error) {
 	listParams := lib.ListParamsFromRequest(r)
 	download := r.FormValue("download") == "true"
+	start, end, hasRange, err := rowRangeFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 	format := "json"
-	if download {
+	if download || hasRange || isConvertedBodyFormat(r.FormValue("format")) {
 		format = r.FormValue("format")
 	}
 	// if download is not set, and format is set, make sure the user knows that
 	// setting format won't do anything
-	if !download && r.FormValue("format") != "" && r.FormValue("format") != "json" {
-		return nil, fmt.Errorf("the format must be json if used without the download parameter")
+	if !download && !hasRange && r.FormValue("format") != "" && r.FormValue("format") != "json" && !isConvertedBodyFormat(r.FormValue("format")) {
+		return nil, fmt.Errorf("the format must be json, ndjson, geojson, arrow or feather if used without the download parameter")
+	}
+	if hasRange && format == "" {
+		format = "json"
+	}
+	if hasRange && isConvertedBodyFormat(format) {
+		return nil, fmt.Errorf("row ranges can't be requested as %s", format)
 	}
 
 	p := &lib.GetParams{
@@ -568,6 +995,7 @@ func getParamsFromRequest(r *http.Request, readOnly bool, path string) (*lib.Get
 		Limit:    listParams.Limit,
 		Offset:   listParams.Offset,
 		All:      r.FormValue("all") == "true" && !readOnly,
+		Where:    r.FormValue("where"),
 	}
 
 	if !readOnly {
@@ -591,9 +1019,57 @@ func getParamsFromRequest(r *http.Request, readOnly bool, path string) (*lib.Get
 		// return all rows
 		p.All = r.FormValue("all") == "true" || (p.Offset == 0 && p.Limit == -1)
 	}
+
+	if hasRange {
+		p.Offset = start
+		p.Limit = end - start
+		p.All = false
+	}
 	return p, nil
 }
 
+// rowRangeFromRequest reads the start & end row indices of a body request.
+// start is inclusive, end is exclusive. if only start is given the range
+// covers one default-sized page
+func rowRangeFromRequest(r *http.Request) (start, end int, ok bool, err error) {
+	if r.FormValue("start") == "" && r.FormValue("end") == "" {
+		return 0, 0, false, nil
+	}
+	if r.FormValue("start") != "" {
+		if start, err = util.ReqParamInt("start", r); err != nil || start < 0 {
+			return 0, 0, false, fmt.Errorf("start must be a non-negative integer")
+		}
+	}
+	end = start + util.DefaultPageSize
+	if r.FormValue("end") != "" {
+		if end, err = util.ReqParamInt("end", r); err != nil || end <= start {
+			return 0, 0, false, fmt.Errorf("end must be an integer greater than start")
+		}
+	}
+	return start, end, true, nil
+}
+
+// writeBodyRange writes the raw bytes of a body row range in the requested
+// format. the rows actually returned, which may be fewer than requested if
+// the range runs past the end of the body, are reported in a Content-Range
+// header, eg: "rows 10-19/1000". the total is "*" if the entry count isn't
+// recorded in the dataset structure
+func writeBodyRange(w http.ResponseWriter, ds *dataset.Dataset, format string, start, n int, data []byte) {
+	total := "*"
+	if ds.Structure != nil && ds.Structure.Entries > 0 {
+		total = strconv.Itoa(ds.Structure.Entries)
+	}
+	if n == 0 {
+		w.Header().Set("Content-Range", fmt.Sprintf("rows */%s", total))
+	} else {
+		w.Header().Set("Content-Range", fmt.Sprintf("rows %d-%d/%s", start, start+n-1, total))
+	}
+	if mt := extensionToMimeType("." + format); mt != "" {
+		w.Header().Set("Content-Type", mt)
+	}
+	w.Write(data)
+}
+
 func (h DatasetHandlers) bodyHandler(w http.ResponseWriter, r *http.Request) {
 	refStr := HTTPPathToQriPath(r.URL.Path[len("/body/"):])
 	p, err := getParamsFromRequest(r, h.ReadOnly, refStr)
@@ -604,7 +1080,7 @@ func (h DatasetHandlers) bodyHandler(w http.ResponseWriter, r *http.Request) {
 
 	result := &lib.GetResult{}
 	if err := h.Get(p, result); err != nil {
-		if err == repo.ErrNoHistory {
+		if err == repo.ErrNoHistory || err == lib.ErrNoGeometry {
 			util.WriteErrResponse(w, http.StatusUnprocessableEntity, err)
 			return
 		}
@@ -612,7 +1088,19 @@ func (h DatasetHandlers) bodyHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if start, _, hasRange, _ := rowRangeFromRequest(r); hasRange {
+		writeBodyRange(w, result.Dataset, p.Format, start, result.Entries, result.Bytes)
+		return
+	}
+
 	download := r.FormValue("download") == "true"
+	if isConvertedBodyFormat(p.Format) && !download {
+		// GeoJSON consumers expect a bare FeatureCollection, not a response
+		// envelope. arrow formats are binary, ndjson is read line by line
+		w.Header().Set("Content-Type", extensionToMimeType("."+p.Format))
+		w.Write(result.Bytes)
+		return
+	}
 	if download {
 		filename, err := lib.GenerateFilename(result.Dataset, p.Format)
 		if err != nil {
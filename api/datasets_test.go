@@ -16,7 +16,7 @@ func TestGetParamsFromRequest(t *testing.T) {
 		{"download not set, format set",
 			false,
 			"foo",
-			"the format must be json if used without the download parameter",
+			"the format must be json, ndjson, geojson, arrow or feather if used without the download parameter",
 		},
 	}
 	for _, c := range casesErr {
@@ -138,3 +138,45 @@ func TestGetParamsFromRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestGetParamsFromRequestRowRange(t *testing.T) {
+	cases := []struct {
+		query               string
+		expOffset, expLimit int
+		expFormat           string
+		err                 string
+	}{
+		{"start=10&end=20", 10, 10, "json", ""},
+		{"start=5&end=6&format=csv", 5, 1, "csv", ""},
+		{"end=50", 0, 50, "json", ""},
+		{"start=30", 30, 100, "json", ""},
+		{"start=-1&end=10", 0, 0, "", "start must be a non-negative integer"},
+		{"start=10&end=10", 0, 0, "", "end must be an integer greater than start"},
+		{"start=0&end=10&format=geojson", 0, 0, "", "row ranges can't be requested as geojson"},
+		{"start=0&end=10&format=arrow", 0, 0, "", "row ranges can't be requested as arrow"},
+	}
+
+	for _, c := range cases {
+		r, err := http.NewRequest("GET", "/body?"+c.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := getParamsFromRequest(r, false, "/body/path")
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("case %q error mismatch. expected: %q, got: %v", c.query, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %q unexpected error: %s", c.query, err)
+			continue
+		}
+		if p.Offset != c.expOffset || p.Limit != c.expLimit || p.All {
+			t.Errorf("case %q range mismatch. expected offset %d, limit %d. got offset %d, limit %d, all %t", c.query, c.expOffset, c.expLimit, p.Offset, p.Limit, p.All)
+		}
+		if p.Format != c.expFormat {
+			t.Errorf("case %q format mismatch. expected: %q, got: %q", c.query, c.expFormat, p.Format)
+		}
+	}
+}
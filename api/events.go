@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	util "github.com/qri-io/apiutil"
+	"github.com/qri-io/qri/repo"
+)
+
+// EventHandlers streams repo events to websocket clients
+type EventHandlers struct {
+	repo     repo.Repo
+	upgrader websocket.Upgrader
+	ReadOnly bool
+}
+
+// NewEventHandlers allocates an EventHandlers pointer. browser connections are
+// accepted from the same host, or any of allowedOrigins. an allowed origin of
+// "*" accepts connections from any origin
+func NewEventHandlers(r repo.Repo, allowedOrigins []string, readOnly bool) *EventHandlers {
+	return &EventHandlers{
+		repo:     r,
+		ReadOnly: readOnly,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true
+				}
+				if originAllowed(allowedOrigins, origin) {
+					return true
+				}
+				u, err := url.Parse(origin)
+				return err == nil && u.Host == r.Host
+			},
+		},
+	}
+}
+
+// EventsHandler upgrades a request to a websocket connection & writes events
+// as JSON messages as they're logged. clients can limit the events they
+// receive to a comma-separated list of event types with the "type" query
+// param, eg: /events?type=ds_created,ds_pinned
+func (h *EventHandlers) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		if h.ReadOnly {
+			readOnlyResponse(w, "/events")
+			return
+		}
+		h.eventsHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h *EventHandlers) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	types := map[repo.EventType]bool{}
+	for _, t := range strings.Split(r.FormValue("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types[repo.EventType(t)] = true
+		}
+	}
+
+	sub, ok := h.repo.(repo.EventSubscriber)
+	if !ok {
+		util.WriteErrResponse(w, http.StatusNotImplemented, fmt.Errorf("repo doesn't support subscribing to events"))
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade writes an error response to the client
+		log.Debugf("upgrading events connection: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// clients don't send messages, but reading is required to process control
+	// frames & notice when the client goes away
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for e := range sub.SubscribeEvents(ctx) {
+		if len(types) > 0 && !types[e.Type] {
+			continue
+		}
+		if err := conn.WriteJSON(e); err != nil {
+			log.Debugf("writing event: %s", err)
+			return
+		}
+	}
+}
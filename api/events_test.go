@@ -0,0 +1,131 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestEventsHandler(t *testing.T) {
+	r, teardown := newTestRepo(t)
+	defer teardown()
+
+	h := NewEventHandlers(r, nil, false)
+	ts := httptest.NewServer(http.HandlerFunc(h.EventsHandler))
+	defer ts.Close()
+
+	addr := "ws" + strings.TrimPrefix(ts.URL, "http") + "/events?type=ds_created"
+	conn, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// give the subscription a moment to start before logging events
+	time.Sleep(time.Millisecond * 20)
+	ref := repo.DatasetRef{Peername: "peer", Name: "movies"}
+	if err := r.LogEvent(repo.ETDsPinned, ref); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LogEvent(repo.ETDsCreated, ref); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second * 2))
+	e := &repo.Event{}
+	if err := conn.ReadJSON(e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != repo.ETDsCreated {
+		t.Errorf("expected filtered event type %s, got: %s", repo.ETDsCreated, e.Type)
+	}
+	if e.Ref.Name != "movies" {
+		t.Errorf("expected event ref name movies, got: %q", e.Ref.Name)
+	}
+}
+
+func TestEventsHandlerReadOnly(t *testing.T) {
+	r, teardown := newTestRepo(t)
+	defer teardown()
+
+	h := NewEventHandlers(r, nil, true)
+	ts := httptest.NewServer(http.HandlerFunc(h.EventsHandler))
+	defer ts.Close()
+
+	addr := "ws" + strings.TrimPrefix(ts.URL, "http") + "/events"
+	conn, res, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err == nil {
+		conn.Close()
+		t.Fatal("expected read-only server to refuse the websocket upgrade")
+	}
+	if res == nil || res.StatusCode != http.StatusForbidden {
+		t.Errorf("expected a forbidden response. got: %v", res)
+	}
+}
+
+func TestEventsHandlerShutdown(t *testing.T) {
+	r, teardown := newTestRepo(t)
+	defer teardown()
+
+	s := Server{requests: newRequestTracker()}
+	h := NewEventHandlers(r, nil, false)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: s.requests.track(h.EventsHandler)}
+	go server.Serve(ln)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	s.drain(server, time.Second)
+
+	// draining the server closes the subscription, ending the connection
+	conn.SetReadDeadline(time.Now().Add(time.Second * 2))
+	if _, _, err := conn.NextReader(); err == nil {
+		t.Fatal("expected the websocket connection to close on shutdown")
+	} else if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		t.Fatal("expected shutdown to close the connection, timed out waiting")
+	}
+}
+
+func TestEventsHandlerOrigin(t *testing.T) {
+	h := NewEventHandlers(nil, []string{"http://localhost:2505"}, false)
+	cases := []struct {
+		origin string
+		expect bool
+	}{
+		{"", true},
+		{"http://localhost:2505", true},
+		{"http://example.com", true},
+		{"http://evil.com", false},
+	}
+	for i, c := range cases {
+		req := httptest.NewRequest("GET", "http://example.com/events", nil)
+		if c.origin != "" {
+			req.Header.Set("Origin", c.origin)
+		}
+		if got := h.upgrader.CheckOrigin(req); got != c.expect {
+			t.Errorf("case %d origin %q: expected %t, got %t", i, c.origin, c.expect, got)
+		}
+	}
+}
+
+func TestEventsHandlerWildcardOrigin(t *testing.T) {
+	h := NewEventHandlers(nil, []string{"*"}, false)
+	req := httptest.NewRequest("GET", "http://example.com/events", nil)
+	req.Header.Set("Origin", "http://other.com")
+	if !h.upgrader.CheckOrigin(req) {
+		t.Error("expected a wildcard allowed origin to accept any origin")
+	}
+}
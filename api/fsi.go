@@ -40,8 +40,6 @@ func (h *FSIHandlers) StatusHandler(routePrefix string) http.HandlerFunc {
 		}
 
 		switch r.Method {
-		case "OPTIONS":
-			util.EmptyOkHandler(w, r)
 		default:
 			util.NotFoundHandler(w, r)
 		case "GET":
@@ -97,8 +95,6 @@ func (h *FSIHandlers) InitHandler(routePrefix string) http.HandlerFunc {
 		}
 
 		switch r.Method {
-		case "OPTIONS":
-			util.EmptyOkHandler(w, r)
 		case "POST":
 			handleInit(w, r)
 		default:
@@ -121,6 +117,7 @@ func (h *FSIHandlers) initHandler(routePrefix string) http.HandlerFunc {
 			Format:         r.FormValue("format"),
 			Mkdir:          r.FormValue("mkdir"),
 			SourceBodyPath: r.FormValue("sourcebodypath"),
+			Sheet:          r.FormValue("sheet"),
 		}
 
 		var name string
@@ -176,8 +173,6 @@ func (h *FSIHandlers) WriteHandler(routePrefix string) http.HandlerFunc {
 		}
 
 		switch r.Method {
-		case "OPTIONS":
-			util.EmptyOkHandler(w, r)
 		case "POST":
 			handler(w, r)
 		default:
@@ -225,8 +220,6 @@ func (h *FSIHandlers) CheckoutHandler(routePrefix string) http.HandlerFunc {
 		}
 
 		switch r.Method {
-		case "OPTIONS":
-			util.EmptyOkHandler(w, r)
 		case "POST":
 			handleCheckout(w, r)
 		default:
@@ -250,6 +243,10 @@ func (h *FSIHandlers) checkoutHandler(routePrefix string) http.HandlerFunc {
 
 		var res string
 		if err := h.Checkout(p, &res); err != nil {
+			if _, ok := err.(lib.MergeConflictError); ok {
+				util.WriteErrResponse(w, http.StatusConflict, err)
+				return
+			}
 			util.WriteErrResponse(w, http.StatusInternalServerError, err)
 			return
 		}
@@ -268,8 +265,6 @@ func (h *FSIHandlers) RestoreHandler(routePrefix string) http.HandlerFunc {
 		}
 
 		switch r.Method {
-		case "OPTIONS":
-			util.EmptyOkHandler(w, r)
 		case "POST":
 			handleRestore(w, r)
 		default:
@@ -39,7 +39,6 @@ func TestFSIHandlers(t *testing.T) {
 	defer os.RemoveAll(filepath.Join("fsi_tests"))
 
 	initCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 		{"POST", "/", nil},
 		{"POST", fmt.Sprintf("/?filepath=%s", initDir), nil},
@@ -50,7 +49,6 @@ func TestFSIHandlers(t *testing.T) {
 	runHandlerTestCases(t, "init", h.InitHandler(""), initCases, true)
 
 	statusCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		// TODO (b5) - can't ask for an FSI-linked status b/c the responses change with
 		// temp directory names
 		{"GET", "/me/movies", nil},
@@ -59,7 +57,6 @@ func TestFSIHandlers(t *testing.T) {
 	runHandlerTestCases(t, "status", h.StatusHandler(""), statusCases, true)
 
 	checkoutCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"POST", "/me/movies", nil},
 		// TODO (b5) - can't ask for an FSI-linked status b/c the responses change with
 		// temp directory names
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipResponses(t *testing.T) {
+	large := strings.Repeat(`{"title":"example movie data"},`, 100)
+	small := `{"ok":true}`
+
+	writeBody := func(contentType, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if contentType != "" {
+				w.Header().Set("Content-Type", contentType)
+			}
+			w.WriteHeader(http.StatusCreated)
+			// write in pieces to check buffering across writes
+			for i := 0; i < len(body); i += 100 {
+				end := i + 100
+				if end > len(body) {
+					end = len(body)
+				}
+				w.Write([]byte(body[i:end]))
+			}
+		}
+	}
+
+	cases := []struct {
+		description    string
+		acceptEncoding string
+		contentType    string
+		body           string
+		gzipped        bool
+	}{
+		{"large body", "gzip, deflate", "application/json", large, true},
+		{"quality values", "deflate;q=1.0, gzip;q=0.5", "application/json", large, true},
+		{"gzip not acceptable", "gzip;q=0", "application/json", large, false},
+		{"no accept-encoding", "", "application/json", large, false},
+		{"small body", "gzip", "application/json", small, false},
+		{"empty body", "gzip", "", "", false},
+		{"compressed media type", "gzip", "image/jpeg", large, false},
+		{"detected content type", "gzip", "", large, true},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/list", nil)
+		if c.acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", c.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		gzipResponses(writeBody(c.contentType, c.body))(w, r)
+		res := w.Result()
+
+		if res.StatusCode != http.StatusCreated {
+			t.Errorf("case %q status mismatch. expected: %d, got: %d", c.description, http.StatusCreated, res.StatusCode)
+		}
+		if c.contentType != "" && res.Header.Get("Content-Type") != c.contentType {
+			t.Errorf("case %q content type mismatch. expected: %q, got: %q", c.description, c.contentType, res.Header.Get("Content-Type"))
+		}
+
+		gotGzip := res.Header.Get("Content-Encoding") == "gzip"
+		if gotGzip != c.gzipped {
+			t.Errorf("case %q expected gzipped: %t, got: %t", c.description, c.gzipped, gotGzip)
+			continue
+		}
+
+		body := res.Body
+		if gotGzip {
+			gz, err := gzip.NewReader(res.Body)
+			if err != nil {
+				t.Errorf("case %q reading gzip body: %s", c.description, err)
+				continue
+			}
+			body = gz
+		}
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			t.Errorf("case %q reading body: %s", c.description, err)
+			continue
+		}
+		if string(data) != c.body {
+			t.Errorf("case %q body mismatch. expected %d bytes, got %d bytes", c.description, len(c.body), len(data))
+		}
+	}
+}
+
+func TestGzipResponsesFlush(t *testing.T) {
+	r := httptest.NewRequest("GET", "/list?stream=true", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	gzipResponses(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", NDJSONMimeType)
+		w.Write([]byte("{\"a\":1}\n"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("{\"a\":2}\n"))
+	})(w, r)
+
+	if !w.Flushed {
+		t.Error("expected flush to reach the underlying response writer")
+	}
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("expected flushed stream to be gzipped")
+	}
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "{\"a\":1}\n{\"a\":2}\n"; string(data) != expect {
+		t.Errorf("body mismatch. expected: %q, got: %q", expect, string(data))
+	}
+}
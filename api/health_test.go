@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/lib"
+)
+
+func TestHealthHandler(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	inst := lib.NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	s := New(inst)
+
+	w := httptest.NewRecorder()
+	s.HealthHandler(w, httptest.NewRequest("GET", "/health", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status code mismatch. expected: %d, got: %d", http.StatusOK, w.Code)
+	}
+	res := struct {
+		Data []interface{} `json:"data"`
+	}{}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Data) != 0 {
+		t.Errorf("expected default health check to be minimal. got: %v", res.Data)
+	}
+
+	w = httptest.NewRecorder()
+	s.HealthHandler(w, httptest.NewRequest("GET", "/health?verbose=true", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status code mismatch. expected: %d, got: %d", http.StatusOK, w.Code)
+	}
+	verbose := struct {
+		Meta map[string]interface{} `json:"meta"`
+		Data *lib.Health            `json:"data"`
+	}{}
+	if err := json.NewDecoder(w.Body).Decode(&verbose); err != nil {
+		t.Fatal(err)
+	}
+	if verbose.Meta["version"] != lib.VersionNumber {
+		t.Errorf("version mismatch. expected: %q, got: %v", lib.VersionNumber, verbose.Meta["version"])
+	}
+	if verbose.Data == nil || !verbose.Data.StoreReachable {
+		t.Errorf("expected verbose health check to report a reachable store. got: %v", verbose.Data)
+	}
+
+	w = httptest.NewRecorder()
+	Server{Instance: &lib.Instance{}}.HealthHandler(w, httptest.NewRequest("GET", "/health?verbose=true", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected an instance without a store to be unavailable. got status: %d", w.Code)
+	}
+}
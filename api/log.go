@@ -1,8 +1,11 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	util "github.com/qri-io/apiutil"
 	"github.com/qri-io/qri/lib"
@@ -25,8 +28,6 @@ func NewLogHandlers(n *p2p.QriNode) *LogHandlers {
 // LogHandler is the endpoint for dataset logs
 func (h *LogHandlers) LogHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.logHandler(w, r)
 	default:
@@ -49,13 +50,27 @@ func (h *LogHandlers) logHandler(w http.ResponseWriter, r *http.Request) {
 	lp := lib.ListParamsFromRequest(r)
 	lp.Peername = args.Peername
 
+	now := time.Now()
+	if s := r.FormValue("after"); s != "" {
+		if lp.NewerThan, err = lib.ParseTimeBound(s, now); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("invalid 'after' param: %s", err))
+			return
+		}
+	}
+	if s := r.FormValue("before"); s != "" {
+		if lp.OlderThan, err = lib.ParseTimeBound(s, now); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("invalid 'before' param: %s", err))
+			return
+		}
+	}
+
 	params := &lib.LogParams{
 		Ref:        args.String(),
 		ListParams: lp,
 	}
 
-	res := []repo.DatasetRef{}
-	if err := h.Log(params, &res); err != nil {
+	res := &lib.LogPage{}
+	if err := h.LogPage(params, res); err != nil {
 		if err == repo.ErrNoHistory {
 			util.WriteErrResponse(w, http.StatusUnprocessableEntity, err)
 			return
@@ -63,7 +78,43 @@ func (h *LogHandlers) logHandler(w http.ResponseWriter, r *http.Request) {
 		util.WriteErrResponse(w, http.StatusInternalServerError, err)
 		return
 	}
-	if err := util.WritePageResponse(w, res, r, params.Page()); err != nil {
+	if err := writeLogPageResponse(w, r, params.ListParams, res); err != nil {
 		log.Infof("error list dataset history response: %s", err.Error())
 	}
 }
+
+// writeLogPageResponse writes a paginated response envelope that includes the
+// total number of versions, so clients can tell when they've read the full
+// history
+func writeLogPageResponse(w http.ResponseWriter, r *http.Request, lp lib.ListParams, res *lib.LogPage) error {
+	page := lp.Page()
+	pagination := map[string]interface{}{
+		"total": res.Total,
+	}
+	if page.Number*page.Size < res.Total {
+		pagination["nextUrl"] = pageURL(r, page.Number+1)
+	}
+	if page.Number > 1 {
+		pagination["prevUrl"] = pageURL(r, page.Number-1)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"data":       res.Versions,
+		"meta":       map[string]interface{}{"code": http.StatusOK},
+		"pagination": pagination,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(data)
+	return err
+}
+
+// pageURL returns the request URL with the page query param set to number
+func pageURL(r *http.Request, number int) string {
+	q := r.URL.Query()
+	q.Set("page", strconv.Itoa(number))
+	return r.URL.Path + "?" + q.Encode()
+}
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/qri-io/dataset"
@@ -29,7 +32,6 @@ func TestHistoryHandlers(t *testing.T) {
 	h := NewLogHandlers(node)
 
 	logCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/history/me/cities", nil},
 		{"GET", "/history/me/cities/at/map/QmZrmGvTPMCkJYfqaagFZBUWuX5bkqSXu179eNnFfhCKze", nil},
 		{"GET", "/history/at/map/QmZrmGvTPMCkJYfqaagFZBUWuX5bkqSXu179eNnFfhCKze", nil},
@@ -37,3 +39,60 @@ func TestHistoryHandlers(t *testing.T) {
 	}
 	runHandlerTestCases(t, "log", h.LogHandler, logCases, true)
 }
+
+func TestHistoryHandlersFilters(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	res := &repo.DatasetRef{}
+	p := &lib.SaveParams{
+		Ref:     "me/cities",
+		Dataset: &dataset.Dataset{Meta: &dataset.Meta{Title: "Updated Title"}},
+	}
+	if err := lib.NewDatasetRequests(node, nil).Save(p, res); err != nil {
+		t.Fatalf("error writing dataset update: %s", err.Error())
+	}
+
+	h := NewLogHandlers(node)
+
+	w := httptest.NewRecorder()
+	h.LogHandler(w, httptest.NewRequest("GET", "/history/me/cities?before=nope", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status code mismatch. expected: %d, got: %d", http.StatusBadRequest, w.Code)
+	}
+
+	cases := []struct {
+		url     string
+		total   int
+		nextURL string
+	}{
+		{"/history/me/cities?after=2000-01-01", 2, ""},
+		{"/history/me/cities?before=2000-01-01", 0, ""},
+		{"/history/me/cities?after=2000-01-01&pageSize=1", 2, "/history/me/cities?after=2000-01-01&page=2&pageSize=1"},
+		{"/history/me/cities?after=2000-01-01&pageSize=1&page=2", 2, ""},
+	}
+
+	for i, c := range cases {
+		w = httptest.NewRecorder()
+		h.LogHandler(w, httptest.NewRequest("GET", c.url, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("case %d status code mismatch. expected: %d, got: %d. body: %s", i, http.StatusOK, w.Code, w.Body.String())
+			continue
+		}
+		res := struct {
+			Pagination struct {
+				NextURL string `json:"nextUrl"`
+				Total   int    `json:"total"`
+			} `json:"pagination"`
+		}{}
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Pagination.Total != c.total {
+			t.Errorf("case %d total mismatch. expected: %d, got: %d", i, c.total, res.Pagination.Total)
+		}
+		if res.Pagination.NextURL != c.nextURL {
+			t.Errorf("case %d nextUrl mismatch. expected: %q, got: %q", i, c.nextURL, res.Pagination.NextURL)
+		}
+	}
+}
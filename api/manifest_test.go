@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestManifestHandler(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+	inst := newTestInstanceWithProfileFromNode(node)
+
+	// manifests are read-only, & should be served in read-only mode
+	h := NewDatasetHandlers(inst, true)
+	cases := []struct {
+		method, endpoint string
+		code             int
+	}{
+		{"GET", "/manifest/", http.StatusBadRequest},
+		{"GET", "/manifest/peer/not_a_dataset", http.StatusNotFound},
+		{"POST", "/manifest/peer/movies", http.StatusNotFound},
+		{"DELETE", "/manifest/peer/movies", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h.ManifestHandler(w, httptest.NewRequest(c.method, c.endpoint, nil))
+		if w.Code != c.code {
+			t.Errorf("%s %s status mismatch. expected: %d, got: %d\n%s", c.method, c.endpoint, c.code, w.Code, w.Body.String())
+		}
+	}
+}
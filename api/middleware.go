@@ -9,9 +9,10 @@ import (
 	util "github.com/qri-io/apiutil"
 )
 
-// middleware handles request logging
+// middleware handles request logging, response compression, CORS, rate
+// limiting & read-only mode checks
 func (s Server) middleware(handler http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	return s.requests.track(gzipResponses(func(w http.ResponseWriter, r *http.Request) {
 		log.Infof("%s %s %s", r.Method, r.URL.Path, time.Now())
 
 		// If this server is operating behind a proxy, but we still want to force
@@ -31,32 +32,93 @@ func (s Server) middleware(handler http.HandlerFunc) http.HandlerFunc {
 		// 	// If TLS is enabled, set 1 week strict TLS, 1 week for now to prevent catastrophic mess-ups
 		// 	w.Header().Add("Strict-Transport-Security", "max-age=604800")
 		// }
-		s.addCORSHeaders(w, r)
+		if preflight := handleCORS(w, r, s.Config().API.AllowedOrigins); preflight {
+			return
+		}
+		// OPTIONS requests are answered here so every route responds the same
+		// way, handlers only see the methods they implement
+		if r.Method == "OPTIONS" {
+			util.EmptyOkHandler(w, r)
+			return
+		}
+
+		if !s.rateLimitCheck(w, r) {
+			return
+		}
 
 		if ok := s.readOnlyCheck(r); ok {
 			handler(w, r)
 		} else {
 			util.WriteErrResponse(w, http.StatusForbidden, fmt.Errorf("qri server is in read-only mode, only certain GET requests are allowed"))
 		}
-	}
+	}))
 }
 
 func (s *Server) readOnlyCheck(r *http.Request) bool {
-	return !s.Config().API.ReadOnly || r.Method == "GET" || r.Method == "OPTIONS"
+	return !s.Config().API.ReadOnly || r.Method == "GET"
 }
 
-// addCORSHeaders adds CORS header info for whitelisted servers
-func (s *Server) addCORSHeaders(w http.ResponseWriter, r *http.Request) {
+// corsAllowedMethods & corsAllowedHeaders are sent in response to CORS
+// preflight requests
+const (
+	corsAllowedMethods = "GET, PUT, POST, DELETE, OPTIONS"
+	corsAllowedHeaders = "Content-Type,Authorization"
+	// corsMaxAge is the number of seconds browsers may cache a preflight
+	// response
+	corsMaxAge = "600"
+)
+
+// handleCORS adds CORS headers to responses for requests from allowed
+// origins. an allowed origin of "*" permits any origin to read responses, but
+// never with credentials, only explicitly listed origins may send credentials.
+// CORS preflight requests are answered directly, handleCORS returns true when
+// it has written a preflight response & the request shouldn't be handled
+// further
+func handleCORS(w http.ResponseWriter, r *http.Request, allowed []string) (preflight bool) {
 	origin := r.Header.Get("Origin")
-	for _, o := range s.Config().API.AllowedOrigins {
-		if origin == o {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, POST, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Authorization")
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			return
+	if origin == "" {
+		return false
+	}
+	preflight = r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != ""
+
+	// responses differ by origin, caches must key on it
+	w.Header().Add("Vary", "Origin")
+	switch {
+	case originListed(allowed, origin):
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	case originListed(allowed, "*"):
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	default:
+		if preflight {
+			util.WriteErrResponse(w, http.StatusForbidden, fmt.Errorf("origin %q is not allowed", origin))
+		}
+		return preflight
+	}
+
+	if preflight {
+		w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+		w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+		w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	return preflight
+}
+
+// originAllowed checks an origin against a list of allowed origins, where
+// "*" allows any origin
+func originAllowed(allowed []string, origin string) bool {
+	return originListed(allowed, "*") || originListed(allowed, origin)
+}
+
+// originListed checks if an origin is in a list of origins
+func originListed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == origin {
+			return true
 		}
 	}
+	return false
 }
 
 func (s *Server) datasetRefMiddleware(handler http.HandlerFunc) http.HandlerFunc {
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleCORS(t *testing.T) {
+	allowed := []string{"https://app.qri.io"}
+
+	cases := []struct {
+		description string
+		allowed     []string
+		method      string
+		origin      string
+		preflight   bool
+		expectDone  bool
+		expectCode  int
+		expectAllow string
+		expectCreds bool
+	}{
+		{"no origin", allowed, "GET", "", false, false, http.StatusOK, "", false},
+		{"allowed origin", allowed, "GET", "https://app.qri.io", false, false, http.StatusOK, "https://app.qri.io", true},
+		{"disallowed origin", allowed, "POST", "https://example.com", false, false, http.StatusOK, "", false},
+		{"wildcard", []string{"*"}, "GET", "https://example.com", false, false, http.StatusOK, "*", false},
+		{"listed origin with wildcard", []string{"*", "https://app.qri.io"}, "GET", "https://app.qri.io", false, false, http.StatusOK, "https://app.qri.io", true},
+		{"plain options request", allowed, "OPTIONS", "https://app.qri.io", false, false, http.StatusOK, "https://app.qri.io", true},
+		{"preflight", allowed, "OPTIONS", "https://app.qri.io", true, true, http.StatusNoContent, "https://app.qri.io", true},
+		{"wildcard preflight", []string{"*"}, "OPTIONS", "https://example.com", true, true, http.StatusNoContent, "*", false},
+		{"disallowed preflight", allowed, "OPTIONS", "https://example.com", true, true, http.StatusForbidden, "", false},
+		{"no allowed origins", nil, "GET", "https://app.qri.io", false, false, http.StatusOK, "", false},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, "/list", nil)
+		if c.origin != "" {
+			r.Header.Set("Origin", c.origin)
+		}
+		if c.preflight {
+			r.Header.Set("Access-Control-Request-Method", "POST")
+		}
+		w := httptest.NewRecorder()
+
+		done := handleCORS(w, r, c.allowed)
+		if done != c.expectDone {
+			t.Errorf("case %q expected handled: %t, got: %t", c.description, c.expectDone, done)
+		}
+		if w.Code != c.expectCode {
+			t.Errorf("case %q status mismatch. expected: %d, got: %d", c.description, c.expectCode, w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != c.expectAllow {
+			t.Errorf("case %q allow origin mismatch. expected: %q, got: %q", c.description, c.expectAllow, got)
+		}
+		if gotCreds := w.Header().Get("Access-Control-Allow-Credentials") == "true"; gotCreds != c.expectCreds {
+			t.Errorf("case %q expected allow credentials: %t, got: %t", c.description, c.expectCreds, gotCreds)
+		}
+		if c.origin != "" && w.Header().Get("Vary") != "Origin" {
+			t.Errorf("case %q expected a Vary: Origin header", c.description)
+		}
+		gotMethods := w.Header().Get("Access-Control-Allow-Methods") != ""
+		if expect := c.preflight && c.expectAllow != ""; gotMethods != expect {
+			t.Errorf("case %q expected allow methods header: %t, got: %t", c.description, expect, gotMethods)
+		}
+	}
+}
@@ -27,8 +27,6 @@ func NewPeerHandlers(node *p2p.QriNode, readOnly bool) *PeerHandlers {
 // PeersHandler is the endpoint for fetching peers
 func (h *PeerHandlers) PeersHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/peers")
@@ -43,8 +41,6 @@ func (h *PeerHandlers) PeersHandler(w http.ResponseWriter, r *http.Request) {
 // PeerHandler gets info on a single peer
 func (h *PeerHandlers) PeerHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/peers/")
@@ -59,8 +55,6 @@ func (h *PeerHandlers) PeerHandler(w http.ResponseWriter, r *http.Request) {
 // ConnectToPeerHandler is the endpoint for explicitly connecting to a peer
 func (h *PeerHandlers) ConnectToPeerHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.connectToPeerHandler(w, r)
 	default:
@@ -68,11 +62,10 @@ func (h *PeerHandlers) ConnectToPeerHandler(w http.ResponseWriter, r *http.Reque
 	}
 }
 
-// ConnectionsHandler is the endpoint for listing qri & IPFS connections
+// ConnectionsHandler is the endpoint for listing qri & IPFS connections,
+// with the latency, direction & qri protocol support of each peer
 func (h *PeerHandlers) ConnectionsHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/connections")
@@ -109,9 +102,9 @@ func (h *PeerHandlers) listConnectionsHandler(w http.ResponseWriter, r *http.Req
 	//limit := 0
 	// TODO: double check with @b5 on this change
 	listParams := lib.ListParamsFromRequest(r)
-	peers := []string{}
+	peers := []p2p.PeerConnection{}
 
-	if err := h.ConnectedIPFSPeers(&listParams.Limit, &peers); err != nil {
+	if err := h.ConnectionInfo(&listParams.Limit, &peers); err != nil {
 		log.Infof("error showing connected peers: %s", err.Error())
 		util.WriteErrResponse(w, http.StatusInternalServerError, err)
 		return
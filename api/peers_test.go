@@ -11,7 +11,6 @@ func TestPeerHandlers(t *testing.T) {
 	h := NewPeerHandlers(node, false)
 
 	connectionsCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 		{"DELETE", "/", nil},
 	}
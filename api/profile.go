@@ -1,11 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
-	"net/http"
-
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 
+	"github.com/dustin/go-humanize"
 	util "github.com/qri-io/apiutil"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/lib"
@@ -15,6 +18,7 @@ import (
 type ProfileHandlers struct {
 	lib.ProfileMethods
 	ReadOnly bool
+	inst     *lib.Instance
 }
 
 // NewProfileHandlers allocates a ProfileHandlers pointer
@@ -22,6 +26,7 @@ func NewProfileHandlers(inst *lib.Instance, readOnly bool) *ProfileHandlers {
 	h := ProfileHandlers{
 		ProfileMethods: *lib.NewProfileMethods(inst),
 		ReadOnly:       readOnly,
+		inst:           inst,
 	}
 	return &h
 }
@@ -29,8 +34,6 @@ func NewProfileHandlers(inst *lib.Instance, readOnly bool) *ProfileHandlers {
 // ProfileHandler is the endpoint for this peer's profile
 func (h *ProfileHandlers) ProfileHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		if h.ReadOnly {
 			readOnlyResponse(w, "/profile' or '/me")
@@ -73,8 +76,6 @@ func (h *ProfileHandlers) saveProfileHandler(w http.ResponseWriter, r *http.Requ
 // ProfilePhotoHandler is the endpoint for uploading this peer's profile photo
 func (h *ProfileHandlers) ProfilePhotoHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.getProfilePhotoHandler(w, r)
 	case "PUT", "POST":
@@ -95,7 +96,7 @@ func (h *ProfileHandlers) getProfilePhotoHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Type", http.DetectContentType(data))
 	w.Write(data)
 }
 
@@ -104,17 +105,11 @@ func (h *ProfileHandlers) setProfilePhotoHandler(w http.ResponseWriter, r *http.
 	if r.Header.Get("Content-Type") == "application/json" {
 		json.NewDecoder(r.Body).Decode(p)
 	} else {
-		infile, header, err := r.FormFile("file")
-		if err != nil && err != http.ErrMissingFile {
+		var err error
+		if p, err = imageUploadParams(w, r, lib.PhotoLimits(h.inst.Config())); err != nil {
 			util.WriteErrResponse(w, http.StatusBadRequest, err)
 			return
 		}
-
-		p = &lib.FileParams{
-			// Url:          r.FormValue("url"),
-			Filename: header.Filename,
-			Data:     infile,
-		}
 	}
 
 	res := &config.ProfilePod{}
@@ -129,8 +124,6 @@ func (h *ProfileHandlers) setProfilePhotoHandler(w http.ResponseWriter, r *http.
 // PosterHandler is the endpoint for uploading this peer's poster photo
 func (h *ProfileHandlers) PosterHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.getPosterHandler(w, r)
 	case "PUT", "POST":
@@ -151,7 +144,7 @@ func (h *ProfileHandlers) getPosterHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Type", http.DetectContentType(data))
 	w.Write(data)
 }
 
@@ -160,16 +153,11 @@ func (h *ProfileHandlers) setPosterHandler(w http.ResponseWriter, r *http.Reques
 	if r.Header.Get("Content-Type") == "application/json" {
 		json.NewDecoder(r.Body).Decode(p)
 	} else {
-		infile, header, err := r.FormFile("file")
-		if err != nil && err != http.ErrMissingFile {
+		var err error
+		if p, err = imageUploadParams(w, r, lib.PosterLimits(h.inst.Config())); err != nil {
 			util.WriteErrResponse(w, http.StatusBadRequest, err)
 			return
 		}
-
-		p = &lib.FileParams{
-			Filename: header.Filename,
-			Data:     infile,
-		}
 	}
 
 	res := &config.ProfilePod{}
@@ -180,3 +168,38 @@ func (h *ProfileHandlers) setPosterHandler(w http.ResponseWriter, r *http.Reques
 	}
 	util.WriteResponse(w, res)
 }
+
+// multipartOverhead is extra room allowed in an upload request body beyond the
+// image size limit, for multipart boundaries & headers
+const multipartOverhead = 10 << 10
+
+// imageUploadParams reads an image from the "file" field of a multipart form,
+// rejecting files that are missing or break limits before they reach lib
+func imageUploadParams(w http.ResponseWriter, r *http.Request, limits lib.ImageLimits) (*lib.FileParams, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, limits.MaxSize+multipartOverhead)
+	infile, header, err := r.FormFile("file")
+	if err == http.ErrMissingFile {
+		return nil, fmt.Errorf("file is required")
+	} else if err != nil {
+		if err.Error() == "http: request body too large" {
+			return nil, fmt.Errorf("file size too large. max size is %s", humanize.Bytes(uint64(limits.MaxSize)))
+		}
+		return nil, err
+	}
+	defer infile.Close()
+
+	// read one byte past the limit so oversized files are caught without
+	// buffering all of them
+	data, err := ioutil.ReadAll(io.LimitReader(infile, limits.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := lib.ValidateImage(data, limits); err != nil {
+		return nil, err
+	}
+
+	return &lib.FileParams{
+		Filename: header.Filename,
+		Data:     bytes.NewReader(data),
+	}, nil
+}
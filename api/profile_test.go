@@ -21,7 +21,6 @@ func TestProfileHandler(t *testing.T) {
 	defer teardown()
 
 	cases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 		{"POST", "/", mustFile(t, "testdata/profileRequest.json")},
 		{"POST", "/", []byte(``)},
@@ -56,7 +55,6 @@ func TestProfilePhotoHandler(t *testing.T) {
 		filepaths              map[string]string
 		params                 map[string]string
 	}{
-		{"OPTIONS", "OPTIONS", "/", nil, nil},
 		{"POST", "POST", "/",
 			map[string]string{
 				"file": "testdata/rico_400x400.jpg",
@@ -112,7 +110,6 @@ func TestProfilePosterHandler(t *testing.T) {
 		filepaths              map[string]string
 		params                 map[string]string
 	}{
-		{"OPTIONS", "OPTIONS", "/", nil, nil},
 		{"POST", "POST", "/",
 			map[string]string{
 				"file": "testdata/rico_poster_1500x500.jpg",
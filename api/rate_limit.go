@@ -0,0 +1,168 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	util "github.com/qri-io/apiutil"
+	"github.com/qri-io/qri/config"
+)
+
+// RateLimiter decides if a client may make a request. RateLimiter is an
+// extension point, the default in-memory implementation can be swapped for
+// one backed by a shared store when running more than one server.
+// implementations must be safe for concurrent use
+type RateLimiter interface {
+	// Allow consumes a request for the client identified by key. when a
+	// request isn't allowed retryAfter is the time to wait before trying again
+	Allow(key string) (ok bool, retryAfter time.Duration)
+}
+
+// NewRateLimiter creates an in-memory RateLimiter from API configuration,
+// returning nil if rate limiting is disabled
+func NewRateLimiter(cfg *config.API) RateLimiter {
+	if cfg == nil || cfg.RateLimit <= 0 {
+		return nil
+	}
+	burst := cfg.RateLimitBurst
+	if burst <= 0 {
+		burst = cfg.RateLimit
+	}
+	return NewMemRateLimiter(float64(cfg.RateLimit)/60, burst)
+}
+
+// NewMemRateLimiter creates an in-memory token bucket rate limiter. Each client
+// gets a bucket of burst tokens, refilled at perSecond tokens each second
+func NewMemRateLimiter(perSecond float64, burst int) *MemRateLimiter {
+	return &MemRateLimiter{
+		rate:    perSecond,
+		burst:   float64(burst),
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+// MemRateLimiter is an in-memory token bucket RateLimiter
+type MemRateLimiter struct {
+	lock      sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// assert at compile time that MemRateLimiter is a RateLimiter
+var _ RateLimiter = (*MemRateLimiter)(nil)
+
+// tokenBucket tracks tokens available to a single client
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// memPruneInterval is how often full buckets are dropped from memory
+const memPruneInterval = time.Minute
+
+// Allow implements the RateLimiter interface
+func (l *MemRateLimiter) Allow(key string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// refill returns the number of tokens in a bucket at time now
+func (l *MemRateLimiter) refill(b *tokenBucket, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.updated).Seconds()*l.rate
+	return math.Min(tokens, l.burst)
+}
+
+// prune drops buckets that have refilled, they're identical to new buckets
+func (l *MemRateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < memPruneInterval {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitExempt checks if a request path is never rate limited
+func rateLimitExempt(path string) bool {
+	return path == "/health" || path == "/status" || strings.HasPrefix(path, "/status/")
+}
+
+// rateLimitCheck applies the server rate limiter to a request, writing a
+// 429 Too Many Requests response & returning false if the request is limited
+func (s Server) rateLimitCheck(w http.ResponseWriter, r *http.Request) bool {
+	if s.RateLimiter == nil || rateLimitExempt(r.URL.Path) {
+		return true
+	}
+
+	ok, retryAfter := s.RateLimiter.Allow(s.clientKey(r))
+	if ok {
+		return true
+	}
+
+	secs := int(math.Ceil(retryAfter.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", secs))
+	util.WriteErrResponse(w, http.StatusTooManyRequests, fmt.Errorf("rate limit exceeded, try again in %d seconds", secs))
+	return false
+}
+
+// clientKey identifies the client making a request by IP address. requests
+// made by a trusted proxy are identified by X-Forwarded-For, read from the
+// end back to the first address that isn't a trusted proxy. earlier entries
+// can be forged by clients
+func (s Server) clientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	cfg := s.Config()
+	if cfg == nil || cfg.API == nil || !cfg.API.TrustsProxy(host) {
+		return host
+	}
+
+	addrs := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(addrs) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(addrs[i])
+		if addr == "" {
+			break
+		}
+		host = addr
+		if !cfg.API.TrustsProxy(addr) {
+			break
+		}
+	}
+	return host
+}
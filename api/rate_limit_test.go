@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/lib"
+)
+
+func TestMemRateLimiter(t *testing.T) {
+	now := time.Date(2001, 01, 01, 01, 01, 01, 01, time.UTC)
+	l := NewMemRateLimiter(1, 2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("expected request %d within burst to be allowed", i)
+		}
+	}
+	ok, retryAfter := l.Allow("a")
+	if ok {
+		t.Fatal("expected request over burst to be limited")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("retry after mismatch. expected: %s, got: %s", time.Second, retryAfter)
+	}
+
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("expected clients to be limited independently")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("expected a token to refill after one second")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("expected refilled token to be consumed")
+	}
+
+	now = now.Add(time.Hour)
+	l.Allow("c")
+	if len(l.buckets) != 1 {
+		t.Errorf("expected refilled buckets to be pruned. got %d buckets", len(l.buckets))
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	if l := NewRateLimiter(&config.API{}); l != nil {
+		t.Errorf("expected no limiter when rate limit is unset. got: %v", l)
+	}
+
+	l := NewRateLimiter(&config.API{RateLimit: 120}).(*MemRateLimiter)
+	if l.rate != 2 || l.burst != 120 {
+		t.Errorf("expected burst to default to rate limit. got rate: %f burst: %f", l.rate, l.burst)
+	}
+	l = NewRateLimiter(&config.API{RateLimit: 120, RateLimitBurst: 5}).(*MemRateLimiter)
+	if l.burst != 5 {
+		t.Errorf("expected burst of 5. got: %f", l.burst)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	cfg := config.DefaultConfigForTesting()
+	cfg.API.RateLimit = 1
+	cfg.API.RateLimitBurst = 1
+	s := New(lib.NewInstanceFromConfigAndNode(cfg, node))
+	if s.RateLimiter == nil {
+		t.Fatal("expected server to be configured with a rate limiter")
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	handler := s.middleware(ok)
+	do := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	if w := do("/list", "10.0.0.1:1234"); w.Code != http.StatusOK {
+		t.Fatalf("expected first request to succeed. got: %d", w.Code)
+	}
+	w := do("/list", "10.0.0.1:4321")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected second request from the same IP to be limited. got: %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After mismatch. expected: %q, got: %q", "60", got)
+	}
+	if w := do("/list", "10.0.0.2:1234"); w.Code != http.StatusOK {
+		t.Errorf("expected request from a different IP to succeed. got: %d", w.Code)
+	}
+	for _, path := range []string{"/status", "/status/me/cities", "/health"} {
+		if w := do(path, "10.0.0.1:1234"); w.Code != http.StatusOK {
+			t.Errorf("expected %s to be exempt from rate limiting. got: %d", path, w.Code)
+		}
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	cfg := config.DefaultConfigForTesting()
+	s := New(lib.NewInstanceFromConfigAndNode(cfg, node))
+
+	r := httptest.NewRequest("GET", "/list", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "1.1.1.1, 2.2.2.2")
+	if got := s.clientKey(r); got != "10.0.0.1" {
+		t.Errorf("expected forwarded header to be ignored when not behind a proxy. got: %q", got)
+	}
+
+	cfg.API.ProxyForceHTTPS = true
+	if got := s.clientKey(r); got != "10.0.0.1" {
+		t.Errorf("expected forwarded header to be ignored from an untrusted address. got: %q", got)
+	}
+
+	cfg.API.TrustedProxies = []string{"10.0.0.0/24"}
+	if got := s.clientKey(r); got != "2.2.2.2" {
+		t.Errorf("expected address added by proxy. got: %q", got)
+	}
+
+	// addresses added by a chain of trusted proxies are skipped
+	cfg.API.TrustedProxies = []string{"10.0.0.1", "2.2.2.2"}
+	if got := s.clientKey(r); got != "1.1.1.1" {
+		t.Errorf("expected address before the trusted proxy chain. got: %q", got)
+	}
+
+	r.Header.Del("X-Forwarded-For")
+	if got := s.clientKey(r); got != "10.0.0.1" {
+		t.Errorf("expected proxy address without a forwarded header. got: %q", got)
+	}
+}
@@ -62,9 +62,6 @@ func (h *RemoteClientHandlers) PublishHandler(w http.ResponseWriter, r *http.Req
 	}
 
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
-		return
 	case "GET":
 		h.listPublishedHandler(w, r)
 		return
@@ -9,7 +9,6 @@ func TestRenderHandler(t *testing.T) {
 	defer teardown()
 
 	cases := []handlerTestCase{
-		{"OPTIONS", "/render", nil},
 		{"GET", "/render/me/movies", nil},
 	}
 
@@ -22,8 +22,6 @@ func NewSearchHandlers(inst *lib.Instance) *SearchHandlers {
 // SearchHandler is the endpoint for searching qri
 func (h *SearchHandlers) SearchHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.searchHandler(w, r)
 	default:
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// requestTracker counts in-flight requests so shutdown can report what it's
+// waiting on. upgraded connections like websockets are hijacked from the http
+// server & live until they're closed, they aren't counted. Instead their
+// request contexts are cancelled when the server shuts down
+type requestTracker struct {
+	lk       sync.Mutex
+	active   int
+	next     int
+	upgraded map[int]context.CancelFunc
+}
+
+func newRequestTracker() *requestTracker {
+	return &requestTracker{upgraded: map[int]context.CancelFunc{}}
+}
+
+// track wraps a handler, counting requests while they're being served. a nil
+// tracker counts nothing
+func (t *requestTracker) track(handler http.HandlerFunc) http.HandlerFunc {
+	if t == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			t.lk.Lock()
+			id := t.next
+			t.next++
+			t.upgraded[id] = cancel
+			t.lk.Unlock()
+			defer func() {
+				t.lk.Lock()
+				delete(t.upgraded, id)
+				t.lk.Unlock()
+			}()
+			handler(w, r.WithContext(ctx))
+			return
+		}
+		t.lk.Lock()
+		t.active++
+		t.lk.Unlock()
+		defer func() {
+			t.lk.Lock()
+			t.active--
+			t.lk.Unlock()
+		}()
+		handler(w, r)
+	}
+}
+
+// cancelUpgraded cancels the request context of every upgraded connection
+func (t *requestTracker) cancelUpgraded() {
+	if t == nil {
+		return
+	}
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	for _, cancel := range t.upgraded {
+		cancel()
+	}
+}
+
+// Active returns the number of requests being served
+func (t *requestTracker) Active() int {
+	if t == nil {
+		return 0
+	}
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	return t.active
+}
+
+// drain gracefully shuts down an http server, refusing new connections &
+// waiting up to timeout for in-flight requests to finish. requests still
+// running when the timeout expires are cut off. upgraded connections are
+// closed once the server stops accepting connections
+func (s Server) drain(server *http.Server, timeout time.Duration) {
+	defer s.requests.cancelUpgraded()
+
+	if n := s.requests.Active(); n > 0 {
+		log.Infof("waiting up to %s for %d in-flight request(s) to finish", timeout, n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Infof("shutdown timeout reached, closing %d unfinished request(s)", s.requests.Active())
+		server.Close()
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServerDrain(t *testing.T) {
+	s := Server{requests: newRequestTracker()}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := s.requests.track(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(ln)
+
+	type response struct {
+		body string
+		err  error
+	}
+	resc := make(chan response, 1)
+	go func() {
+		res, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			resc <- response{err: err}
+			return
+		}
+		defer res.Body.Close()
+		data, err := ioutil.ReadAll(res.Body)
+		resc <- response{body: string(data), err: err}
+	}()
+
+	<-started
+	if n := s.requests.Active(); n != 1 {
+		t.Errorf("expected 1 active request, got: %d", n)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		s.drain(server, time.Second*5)
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned before the in-flight request finished")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	close(release)
+	res := <-resc
+	if res.err != nil {
+		t.Fatalf("in-flight request failed: %s", res.err)
+	}
+	if res.body != "done" {
+		t.Errorf("response mismatch. expected: %q, got: %q", "done", res.body)
+	}
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second * 5):
+		t.Fatal("drain didn't return after requests finished")
+	}
+	if n := s.requests.Active(); n != 0 {
+		t.Errorf("expected 0 active requests, got: %d", n)
+	}
+}
+
+func TestServerDrainTimeout(t *testing.T) {
+	s := Server{requests: newRequestTracker()}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := s.requests.track(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(ln)
+	go http.Get("http://" + ln.Addr().String())
+	<-started
+
+	start := time.Now()
+	s.drain(server, time.Millisecond*200)
+	if elapsed := time.Since(start); elapsed > time.Second*2 {
+		t.Errorf("drain should give up after the timeout, took: %s", elapsed)
+	}
+}
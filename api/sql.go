@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	util "github.com/qri-io/apiutil"
+	"github.com/qri-io/qri/lib"
+)
+
+// SQLHandlers wraps a requests struct to interface with http.HandlerFunc
+type SQLHandlers struct {
+	lib.SQLMethods
+	ReadOnly bool
+}
+
+// NewSQLHandlers allocates a SQLHandlers pointer
+func NewSQLHandlers(inst *lib.Instance, readOnly bool) *SQLHandlers {
+	req := lib.NewSQLMethods(inst)
+	return &SQLHandlers{*req, readOnly}
+}
+
+// SQLHandler is the endpoint for running SQL queries against dataset bodies
+func (h *SQLHandlers) SQLHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET", "POST":
+		if h.ReadOnly {
+			readOnlyResponse(w, "/sql")
+			return
+		}
+		h.sqlHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h *SQLHandlers) sqlHandler(w http.ResponseWriter, r *http.Request) {
+	p := &lib.SQLQueryParams{
+		Query:  r.FormValue("q"),
+		Format: r.FormValue("format"),
+		Ctx:    r.Context(),
+	}
+
+	if r.Header.Get("Content-Type") == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	res := []byte{}
+	if err := h.SQLMethods.Exec(p, &res); err != nil {
+		log.Infof("sql error: %s", err.Error())
+		util.WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if p.Format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Write(res)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSQLHandler(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	h := NewSQLHandlers(newTestInstanceWithProfileFromNode(node), false)
+
+	cases := []struct {
+		method, query, format string
+		status                int
+		contentType, body     string
+	}{
+		{"GET", "SELECT city FROM me/cities WHERE pop < 100000", "", http.StatusOK, "application/json", `[{"city":"chatham"}]`},
+		{"GET", "SELECT city, pop FROM me/cities WHERE city = 'chatham'", "csv", http.StatusOK, "text/csv", "city,pop\nchatham,35000\n"},
+		{"POST", "SELECT count(*) FROM me/cities", "", http.StatusOK, "application/json", `[{"count(*)":5}]`},
+		{"GET", "SELECT town FROM me/cities", "", http.StatusBadRequest, "", `unknown column \"town\"`},
+		{"DELETE", "", "", http.StatusNotFound, "", ""},
+	}
+
+	for i, c := range cases {
+		params := url.Values{"q": {c.query}}
+		if c.format != "" {
+			params.Set("format", c.format)
+		}
+		req := httptest.NewRequest(c.method, "/sql?"+params.Encode(), nil)
+		w := httptest.NewRecorder()
+		h.SQLHandler(w, req)
+
+		if w.Code != c.status {
+			t.Errorf("case %d status mismatch. expected: %d, got: %d", i, c.status, w.Code)
+			continue
+		}
+		if c.contentType != "" && w.Header().Get("Content-Type") != c.contentType {
+			t.Errorf("case %d content type mismatch. expected: %q, got: %q", i, c.contentType, w.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(w.Body.String(), c.body) {
+			t.Errorf("case %d body mismatch. expected to contain: %q, got: %q", i, c.body, w.Body.String())
+		}
+	}
+}
+
+func TestSQLHandlerReadOnly(t *testing.T) {
+	node, teardown := newTestNode(t)
+	defer teardown()
+
+	h := NewSQLHandlers(newTestInstanceWithProfileFromNode(node), true)
+	params := url.Values{"q": {"SELECT city FROM me/cities"}}
+	for _, method := range []string{"GET", "POST"} {
+		req := httptest.NewRequest(method, "/sql?"+params.Encode(), nil)
+		w := httptest.NewRecorder()
+		h.SQLHandler(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s status mismatch. expected: %d, got: %d", method, http.StatusForbidden, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "chatham") {
+			t.Errorf("%s expected read-only response not to include body rows. got: %q", method, w.Body.String())
+		}
+	}
+}
@@ -12,7 +12,8 @@ import (
 )
 
 // StartServer interprets info from config to start the server
-// if config.TLS == true it'll spin up an https server using LetsEncrypt
+// if config.CertFile & config.KeyFile are set it'll serve https using them,
+// otherwise if config.TLS == true it'll spin up an https server using LetsEncrypt
 // that should work just fine on the raw internet (ie not behind a proxy like nginx etc)
 // it'll also redirect http traffic to it's https route counterpart if port 80 is open
 func StartServer(c *config.API, s *http.Server) error {
@@ -25,6 +26,11 @@ func StartServer(c *config.API, s *http.Server) error {
 		return nil
 	}
 
+	if c.ServeTLSFiles() {
+		log.Infof("serving https using certificate %s", c.CertFile)
+		return s.ListenAndServeTLS(c.CertFile, c.KeyFile)
+	}
+
 	if !c.TLS {
 		return s.ListenAndServe()
 	}
@@ -3,6 +3,8 @@ package api
 import (
 	"net/http"
 	"testing"
+
+	"github.com/qri-io/qri/config"
 )
 
 func TestHTTPSRedirect(t *testing.T) {
@@ -25,3 +27,15 @@ func TestHTTPSRedirect(t *testing.T) {
 	req, _ := http.NewRequest("GET", "http://localhost:5432/foo", nil)
 	cli.Do(req)
 }
+
+func TestStartServerTLSFiles(t *testing.T) {
+	c := &config.API{
+		Enabled:  true,
+		Port:     5433,
+		CertFile: "testdata/missing_cert.pem",
+		KeyFile:  "testdata/missing_key.pem",
+	}
+	if err := StartServer(c, &http.Server{}); err == nil {
+		t.Error("expected serving with missing certificate files to error")
+	}
+}
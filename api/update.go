@@ -9,6 +9,7 @@ import (
 	util "github.com/qri-io/apiutil"
 	"github.com/qri-io/qri/lib"
 	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/update/cron"
 )
 
 // UpdateHandlers wraps lib.UpdateMethods, adding HTTP JSON API handles
@@ -20,8 +21,6 @@ type UpdateHandlers struct {
 // UpdatesHandler brings a dataset to the latest version
 func (h *UpdateHandlers) UpdatesHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.listUpdatesHandler(w, r)
 	case "POST":
@@ -95,11 +94,10 @@ func (h UpdateHandlers) unscheduleUpdateHandler(w http.ResponseWriter, r *http.R
 	}
 }
 
-// LogsHandler shows the log of previously run updates
+// LogsHandler lists previously run updates with the duration & status of
+// each run. An output=true query param includes captured run output
 func (h *UpdateHandlers) LogsHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		h.logsHandler(w, r)
 	default:
@@ -108,9 +106,12 @@ func (h *UpdateHandlers) LogsHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UpdateHandlers) logsHandler(w http.ResponseWriter, r *http.Request) {
-	args := lib.ListParamsFromRequest(r)
-	res := []*lib.Job{}
-	if err := h.Logs(&args, &res); err != nil {
+	args := &lib.RunLogParams{
+		ListParams: lib.ListParamsFromRequest(r),
+		Output:     r.FormValue("output") == "true",
+	}
+	res := []*lib.JobRun{}
+	if err := h.RunLogs(args, &res); err != nil {
 		log.Errorf("listing update logs: %s", err.Error())
 		util.WriteErrResponse(w, http.StatusInternalServerError, err)
 		return
@@ -172,6 +173,41 @@ func (h UpdateHandlers) RunHandler(w http.ResponseWriter, r *http.Request) {
 	util.WriteResponse(w, res)
 }
 
+// HealthHandler reports on the health of the update scheduler, responding
+// with 503 Service Unavailable if the scheduler can't be reached
+func (h UpdateHandlers) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		h.healthHandler(w, r)
+	default:
+		util.NotFoundHandler(w, r)
+	}
+}
+
+func (h UpdateHandlers) healthHandler(w http.ResponseWriter, r *http.Request) {
+	p := &lib.HealthParams{}
+	if r.FormValue("recent_runs") != "" {
+		n, err := util.ReqParamInt("recent_runs", r)
+		if err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("invalid recent_runs: %s", err))
+			return
+		}
+		p.RecentRuns = n
+	}
+
+	res := &lib.SchedulerHealth{}
+	if err := h.Health(p, res); err != nil {
+		log.Errorf("checking scheduler health: %s", err)
+		if err == cron.ErrUnreachable {
+			util.WriteErrResponse(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		util.WriteErrResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	util.WriteResponse(w, res)
+}
+
 // ServiceHandler configures & reports on the update daemon
 func (h UpdateHandlers) ServiceHandler(w http.ResponseWriter, r *http.Request) {
 	if h.ReadOnly {
@@ -181,8 +217,6 @@ func (h UpdateHandlers) ServiceHandler(w http.ResponseWriter, r *http.Request) {
 
 	in := false
 	switch r.Method {
-	case "OPTIONS":
-		util.EmptyOkHandler(w, r)
 	case "GET":
 		res := &lib.ServiceStatus{}
 		if err := h.ServiceStatus(&in, res); err != nil {
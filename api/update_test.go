@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -40,19 +43,17 @@ func TestUpdateHandlers(t *testing.T) {
 	h := UpdateHandlers{UpdateMethods: lib.NewUpdateMethods(inst), ReadOnly: false}
 
 	listCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
 		{"GET", "/", nil},
 		// {"DELETE", "/", nil},
 	}
 	runHandlerTestCases(t, "list", h.UpdatesHandler, listCases, true)
 
 	logCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
+		{"GET", "/update/logs", nil},
 	}
 	runHandlerTestCases(t, "update log", h.LogsHandler, logCases, false)
 
 	runUpdateCases := []handlerMimeMultipartTestCase{
-		{"OPTIONS", "/update/run", nil, nil},
 		{"GET", "/update/run", nil, nil},
 		{"POST", "/update/run/me/cities", nil, map[string]string{
 			"secrets": "bad request",
@@ -62,9 +63,55 @@ func TestUpdateHandlers(t *testing.T) {
 		}},
 	}
 	runMimeMultipartHandlerTestCases(t, "update run", h.RunHandler, runUpdateCases)
+}
+
+func TestUpdateHealthHandler(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "update_health_handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store.Type = "map"
+	cfg.Repo.Type = "mem"
+	cfg.Update = &config.Update{Type: "mem"}
+
+	inst, err := lib.NewInstance(context.Background(), tmpDir, lib.OptConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := UpdateHandlers{UpdateMethods: lib.NewUpdateMethods(inst)}
+
+	w := httptest.NewRecorder()
+	h.HealthHandler(w, httptest.NewRequest("GET", "/update/health", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	res := struct {
+		Data lib.SchedulerHealth
+	}{}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Data.Running {
+		t.Error("expected scheduler that hasn't started to report it isn't running")
+	}
+	if res.Data.JobCount != 0 {
+		t.Errorf("expected 0 jobs, got: %d", res.Data.JobCount)
+	}
+
+	w = httptest.NewRecorder()
+	h.HealthHandler(w, httptest.NewRequest("GET", "/update/health?recent_runs=nope", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected invalid recent_runs to respond with status %d, got %d", http.StatusBadRequest, w.Code)
+	}
 
-	serviceCases := []handlerTestCase{
-		{"OPTIONS", "/", nil},
+	// an instance without a scheduler can't report health
+	h = UpdateHandlers{UpdateMethods: lib.NewUpdateMethods(lib.NewInstanceFromConfigAndNode(cfg, nil))}
+	w = httptest.NewRecorder()
+	h.HealthHandler(w, httptest.NewRequest("GET", "/update/health", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected unreachable scheduler to respond with status %d, got %d", http.StatusServiceUnavailable, w.Code)
 	}
-	runHandlerTestCases(t, "update service", h.ServiceHandler, serviceCases, false)
 }
@@ -0,0 +1,353 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/qri-io/dataset"
+)
+
+// column kinds map JSON schema types to arrow types. values of "json" columns
+// are objects & arrays, encoded as JSON strings
+const (
+	arrowInteger = "integer"
+	arrowNumber  = "number"
+	arrowBoolean = "boolean"
+	arrowString  = "string"
+	arrowJSON    = "json"
+)
+
+// arrowColumn describes a body column as an arrow field
+type arrowColumn struct {
+	name     string
+	kind     string
+	nullable bool
+}
+
+func (c arrowColumn) field() arrow.Field {
+	f := arrow.Field{Name: c.name, Nullable: c.nullable}
+	switch c.kind {
+	case arrowInteger:
+		f.Type = arrow.PrimitiveTypes.Int64
+	case arrowNumber:
+		f.Type = arrow.PrimitiveTypes.Float64
+	case arrowBoolean:
+		f.Type = arrow.FixedWidthTypes.Boolean
+	default:
+		f.Type = arrow.BinaryTypes.String
+	}
+	return f
+}
+
+// ConvertJSONBodyToArrow converts a JSON array body into an Arrow IPC stream
+// of a single record batch. Column types come from the dataset schema,
+// falling back to inferring types from body values when the schema doesn't
+// name exactly one type for a column. Columns are nullable when the schema
+// allows null or the body contains null values
+func ConvertJSONBodyToArrow(st *dataset.Structure, body []byte) ([]byte, error) {
+	return convertJSONBodyToArrow(st, body, false)
+}
+
+// ConvertJSONBodyToFeather converts a JSON array body into an Arrow IPC file,
+// also known as Feather V2. Columns are typed the same way as
+// ConvertJSONBodyToArrow
+func ConvertJSONBodyToFeather(st *dataset.Structure, body []byte) ([]byte, error) {
+	return convertJSONBodyToArrow(st, body, true)
+}
+
+// arrowWriter is the subset of arrow stream & file writers used to write a
+// record batch
+type arrowWriter interface {
+	Write(rec array.Record) error
+	Close() error
+}
+
+func convertJSONBodyToArrow(st *dataset.Structure, body []byte, file bool) ([]byte, error) {
+	rows := []interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// keep numbers as json.Number to tell integers from floats
+	dec.UseNumber()
+	if err := dec.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("arrow conversion requires a body that is an array of rows: %s", err)
+	}
+
+	cols, err := arrowColumns(st, rows)
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]arrow.Field, len(cols))
+	for i, c := range cols {
+		fields[i] = c.field()
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	mem := memory.NewGoAllocator()
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+
+	for i, row := range rows {
+		for j, c := range cols {
+			if err := appendArrowValue(b.Field(j), c.kind, rowValue(row, j, c.name)); err != nil {
+				return nil, fmt.Errorf("row %d, column %q: %s", i, c.name, err)
+			}
+		}
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	buf := &seekBuffer{}
+	var w arrowWriter
+	if file {
+		if w, err = ipc.NewFileWriter(buf, ipc.WithSchema(schema), ipc.WithAllocator(mem)); err != nil {
+			return nil, err
+		}
+	} else {
+		w = ipc.NewWriter(buf, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	}
+	if err := w.Write(rec); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.data, nil
+}
+
+// seekBuffer is an in-memory io.WriteSeeker, arrow file writers seek to
+// track the offsets of record batches
+type seekBuffer struct {
+	data []byte
+	pos  int64
+}
+
+func (b *seekBuffer) Write(p []byte) (int, error) {
+	if end := b.pos + int64(len(p)); end > int64(len(b.data)) {
+		b.data = append(b.data, make([]byte, end-int64(len(b.data)))...)
+	}
+	n := copy(b.data[b.pos:], p)
+	b.pos += int64(n)
+	return n, nil
+}
+
+func (b *seekBuffer) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.pos
+	case io.SeekEnd:
+		offset += int64(len(b.data))
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative position: %d", offset)
+	}
+	b.pos = offset
+	return offset, nil
+}
+
+// arrowColumns determines the name, kind & nullability of each body column
+func arrowColumns(st *dataset.Structure, rows []interface{}) ([]arrowColumn, error) {
+	names, types := arrowSchemaColumns(st)
+	if len(names) == 0 && len(rows) > 0 {
+		switch r := rows[0].(type) {
+		case map[string]interface{}:
+			for name := range r {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		case []interface{}:
+			for i := range r {
+				names = append(names, fmt.Sprintf("field_%d", i+1))
+			}
+		default:
+			return nil, fmt.Errorf("arrow conversion requires rows that are arrays or objects, got: %T", rows[0])
+		}
+		types = make([][]string, len(names))
+	}
+
+	cols := make([]arrowColumn, len(names))
+	for i, name := range names {
+		c := arrowColumn{name: name}
+		var declared []string
+		for _, t := range types[i] {
+			if t == "null" {
+				c.nullable = true
+			} else {
+				declared = append(declared, t)
+			}
+		}
+
+		seen := map[string]bool{}
+		for _, row := range rows {
+			k := valueKind(rowValue(row, i, name))
+			if k == "" {
+				c.nullable = true
+				continue
+			}
+			seen[k] = true
+		}
+
+		if len(declared) == 1 {
+			c.kind = schemaKind(declared[0])
+		} else {
+			c.kind = inferKind(seen)
+		}
+		cols[i] = c
+	}
+	return cols, nil
+}
+
+// arrowSchemaColumns returns column names & the JSON schema types of each
+// column, reading type lists like ["integer", "null"] as well as single types
+func arrowSchemaColumns(st *dataset.Structure) (names []string, types [][]string) {
+	names = SchemaColumnNames(st)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	items, _ := st.Schema["items"].(map[string]interface{})
+	cols, _ := items["items"].([]interface{})
+	props, _ := items["properties"].(map[string]interface{})
+
+	types = make([][]string, len(names))
+	for i, name := range names {
+		var col map[string]interface{}
+		if cols != nil {
+			col, _ = cols[i].(map[string]interface{})
+		} else {
+			col, _ = props[name].(map[string]interface{})
+		}
+		switch t := col["type"].(type) {
+		case string:
+			types[i] = []string{t}
+		case []interface{}:
+			for _, v := range t {
+				if s, ok := v.(string); ok {
+					types[i] = append(types[i], s)
+				}
+			}
+		}
+	}
+	return names, types
+}
+
+// rowValue gets a column value from an array or object row
+func rowValue(row interface{}, i int, name string) interface{} {
+	switch r := row.(type) {
+	case []interface{}:
+		if i < len(r) {
+			return r[i]
+		}
+	case map[string]interface{}:
+		return r[name]
+	}
+	return nil
+}
+
+// schemaKind maps a JSON schema type to a column kind
+func schemaKind(t string) string {
+	switch t {
+	case "integer":
+		return arrowInteger
+	case "number":
+		return arrowNumber
+	case "boolean":
+		return arrowBoolean
+	case "object", "array":
+		return arrowJSON
+	default:
+		return arrowString
+	}
+}
+
+// valueKind gives the column kind of a decoded JSON value, empty for null
+func valueKind(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			return arrowInteger
+		}
+		return arrowNumber
+	case bool:
+		return arrowBoolean
+	case string:
+		return arrowString
+	default:
+		return arrowJSON
+	}
+}
+
+// inferKind picks the narrowest column kind that holds all seen value kinds.
+// integers widen to numbers, any other mix of kinds is stored as strings
+func inferKind(seen map[string]bool) string {
+	switch {
+	case len(seen) == 1:
+		for k := range seen {
+			return k
+		}
+	case len(seen) == 2 && seen[arrowInteger] && seen[arrowNumber]:
+		return arrowNumber
+	}
+	return arrowString
+}
+
+// appendArrowValue adds a value to a column builder, converting it to the
+// column kind. values that can't be converted are an error
+func appendArrowValue(b array.Builder, kind string, v interface{}) error {
+	if v == nil {
+		b.AppendNull()
+		return nil
+	}
+
+	switch kind {
+	case arrowInteger:
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("expected integer, got %s", valueKind(v))
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return fmt.Errorf("expected integer, got %s", n)
+		}
+		b.(*array.Int64Builder).Append(i)
+	case arrowNumber:
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("expected number, got %s", valueKind(v))
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return err
+		}
+		b.(*array.Float64Builder).Append(f)
+	case arrowBoolean:
+		t, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("expected boolean, got %s", valueKind(v))
+		}
+		b.(*array.BooleanBuilder).Append(t)
+	default:
+		switch x := v.(type) {
+		case string:
+			b.(*array.StringBuilder).Append(x)
+		case json.Number:
+			b.(*array.StringBuilder).Append(x.String())
+		default:
+			data, err := json.Marshal(x)
+			if err != nil {
+				return err
+			}
+			b.(*array.StringBuilder).Append(string(data))
+		}
+	}
+	return nil
+}
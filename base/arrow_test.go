@@ -0,0 +1,185 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/qri-io/dataset"
+)
+
+func decodeRows(t *testing.T, body string) []interface{} {
+	rows := []interface{}{}
+	dec := json.NewDecoder(bytes.NewReader([]byte(body)))
+	dec.UseNumber()
+	if err := dec.Decode(&rows); err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestArrowColumns(t *testing.T) {
+	tabular := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": []interface{}{"integer", "null"}},
+					map[string]interface{}{"title": "avg_age", "type": "number"},
+					map[string]interface{}{"title": "in_usa", "type": "boolean"},
+					map[string]interface{}{"title": "tags", "type": "array"},
+					map[string]interface{}{"title": "untyped"},
+				},
+			},
+		},
+	}
+	cases := []struct {
+		description string
+		st          *dataset.Structure
+		body        string
+		expect      []arrowColumn
+	}{
+		{"schema types", tabular,
+			`[["toronto",40000000,55.5,false,["a"],1],["new york",null,44.4,true,[],2.5]]`,
+			[]arrowColumn{
+				{name: "city", kind: arrowString},
+				{name: "pop", kind: arrowInteger, nullable: true},
+				{name: "avg_age", kind: arrowNumber},
+				{name: "in_usa", kind: arrowBoolean},
+				{name: "tags", kind: arrowJSON},
+				{name: "untyped", kind: arrowNumber},
+			},
+		},
+		{"nulls in data make columns nullable", tabular,
+			`[[null,1,null,null,null,null]]`,
+			[]arrowColumn{
+				{name: "city", kind: arrowString, nullable: true},
+				{name: "pop", kind: arrowInteger, nullable: true},
+				{name: "avg_age", kind: arrowNumber, nullable: true},
+				{name: "in_usa", kind: arrowBoolean, nullable: true},
+				{name: "tags", kind: arrowJSON, nullable: true},
+				{name: "untyped", kind: arrowString, nullable: true},
+			},
+		},
+		{"infer from array rows", nil,
+			`[[1,"a",true,1],[2,"b",false,"x"]]`,
+			[]arrowColumn{
+				{name: "field_1", kind: arrowInteger},
+				{name: "field_2", kind: arrowString},
+				{name: "field_3", kind: arrowBoolean},
+				{name: "field_4", kind: arrowString},
+			},
+		},
+		{"infer from object rows", nil,
+			`[{"b":1.5,"a":"x"},{"a":"y"}]`,
+			[]arrowColumn{
+				{name: "a", kind: arrowString},
+				{name: "b", kind: arrowNumber, nullable: true},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := arrowColumns(c.st, decodeRows(t, c.body))
+		if err != nil {
+			t.Errorf("case %q unexpected error: %s", c.description, err)
+			continue
+		}
+		if !reflect.DeepEqual(c.expect, got) {
+			t.Errorf("case %q mismatch.\nwant: %v\ngot:  %v", c.description, c.expect, got)
+		}
+	}
+}
+
+func TestConvertJSONBodyToArrow(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+					map[string]interface{}{"title": "in_usa", "type": "boolean"},
+				},
+			},
+		},
+	}
+	body := []byte(`[["toronto",40000000,false],["new york",null,true],["chicago",300000,null]]`)
+
+	data, err := ConvertJSONBodyToArrow(st, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rdr, err := ipc.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Release()
+
+	expectFields := []arrow.Field{
+		{Name: "city", Type: arrow.BinaryTypes.String},
+		{Name: "pop", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "in_usa", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	}
+	gotFields := rdr.Schema().Fields()
+	if len(gotFields) != len(expectFields) {
+		t.Fatalf("expected %d fields, got: %d", len(expectFields), len(gotFields))
+	}
+	for i, f := range expectFields {
+		got := gotFields[i]
+		if f.Name != got.Name || f.Type.ID() != got.Type.ID() || f.Nullable != got.Nullable {
+			t.Errorf("field %d mismatch. want: %v, got: %v", i, f, got)
+		}
+	}
+
+	if !rdr.Next() {
+		t.Fatal("expected a record batch")
+	}
+	rec := rdr.Record()
+	if rec.NumRows() != 3 {
+		t.Fatalf("expected 3 rows, got: %d", rec.NumRows())
+	}
+	cities := rec.Column(0).(*array.String)
+	if cities.Value(1) != "new york" {
+		t.Errorf("expected city in row 1 to be 'new york', got: %q", cities.Value(1))
+	}
+	pops := rec.Column(1).(*array.Int64)
+	if pops.Value(0) != 40000000 || !pops.IsNull(1) {
+		t.Errorf("pop mismatch. expected 40000000 & null, got: %d & %d", pops.Value(0), pops.Value(1))
+	}
+	inUSA := rec.Column(2).(*array.Boolean)
+	if !inUSA.Value(1) || !inUSA.IsNull(2) {
+		t.Errorf("in_usa mismatch. expected true & null")
+	}
+	if rdr.Next() {
+		t.Error("expected a single record batch")
+	}
+
+	if _, err := ConvertJSONBodyToArrow(st, []byte(`[["toronto","lots",false]]`)); err == nil {
+		t.Error("expected values that don't match the schema type to error")
+	}
+	if _, err := ConvertJSONBodyToArrow(st, []byte(`{"a":"b"}`)); err == nil {
+		t.Error("expected non-array body to error")
+	}
+}
+
+func TestConvertJSONBodyToFeather(t *testing.T) {
+	data, err := ConvertJSONBodyToFeather(nil, []byte(`[{"a":1},{"a":2}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// arrow files begin & end with the magic string "ARROW1"
+	if !bytes.HasPrefix(data, []byte("ARROW1")) || !bytes.HasSuffix(data, []byte("ARROW1")) {
+		t.Errorf("expected arrow file magic bytes")
+	}
+}
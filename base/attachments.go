@@ -0,0 +1,105 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// AttachmentsMetaKey is the meta field that lists a dataset's file attachments.
+// meta records a mapping of attachment name to store path. Saved versions also
+// link attachments into their directory under PackageDirAttachments, so
+// attachments are carried along when a dataset moves between peers
+const AttachmentsMetaKey = "attachments"
+
+// ErrAttachmentNotFound is returned when a dataset has no attachment by a
+// given name
+var ErrAttachmentNotFound = fmt.Errorf("attachment not found")
+
+// Attachments gives the file attachments of a dataset as a map of attachment
+// name to store path. Attachments never returns nil
+func Attachments(ds *dataset.Dataset) map[string]string {
+	att := map[string]string{}
+	if ds == nil || ds.Meta == nil {
+		return att
+	}
+	// values are map[string]string when set in memory, map[string]interface{}
+	// when decoded from JSON
+	switch v := ds.Meta.Meta()[AttachmentsMetaKey].(type) {
+	case map[string]string:
+		for name, path := range v {
+			att[name] = path
+		}
+	case map[string]interface{}:
+		for name, path := range v {
+			if str, ok := path.(string); ok {
+				att[name] = str
+			}
+		}
+	}
+	return att
+}
+
+// AttachmentNames gives the sorted names of a dataset's file attachments
+func AttachmentNames(ds *dataset.Dataset) []string {
+	att := Attachments(ds)
+	names := make([]string, 0, len(att))
+	for name := range att {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddAttachments writes local files to the store, attaching them to ds by file
+// name. Attachments & arbitrary metadata from prev are carried forward, an
+// attachment with the same name as one in prev replaces it. prev may be nil
+func AddAttachments(ctx context.Context, store cafs.Filestore, ds, prev *dataset.Dataset, filepaths []string) error {
+	att := Attachments(prev)
+	for name, path := range Attachments(ds) {
+		att[name] = path
+	}
+
+	for _, fp := range filepaths {
+		data, err := ioutil.ReadFile(fp)
+		if err != nil {
+			return fmt.Errorf("reading attachment: %s", err)
+		}
+		name := filepath.Base(fp)
+		path, err := store.Put(ctx, qfs.NewMemfileBytes(name, data), true)
+		if err != nil {
+			return fmt.Errorf("storing attachment %s: %s", name, err)
+		}
+		att[name] = path
+	}
+
+	if ds.Meta == nil {
+		ds.Meta = &dataset.Meta{}
+	}
+	// changes replace the full set of arbitrary metadata when saved, start
+	// from the previous version's values
+	if prev != nil && prev.Meta != nil {
+		md := ds.Meta.Meta()
+		for key, val := range prev.Meta.Meta() {
+			if _, ok := md[key]; !ok {
+				md[key] = val
+			}
+		}
+	}
+	return ds.Meta.SetArbitrary(AttachmentsMetaKey, att)
+}
+
+// OpenAttachment opens a dataset's file attachment by name
+func OpenAttachment(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, name string) (qfs.File, error) {
+	path, ok := Attachments(ds)[name]
+	if !ok {
+		return nil, ErrAttachmentNotFound
+	}
+	return store.Get(ctx, path)
+}
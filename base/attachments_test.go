@@ -0,0 +1,139 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/repo/kvstore"
+	"github.com/qri-io/qri/repo/profile"
+)
+
+func TestAddAttachments(t *testing.T) {
+	ctx := context.Background()
+	store := cafs.NewMapstore()
+
+	dir, err := ioutil.TempDir("", "attachments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	prev := &dataset.Dataset{Meta: &dataset.Meta{Title: "previous"}}
+	prev.Meta.SetArbitrary("source", "survey")
+	if err := AddAttachments(ctx, store, prev, nil, []string{writeFile("codebook.pdf", "codebook v1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// round trip through JSON, as if loaded from the store
+	data, err := json.Marshal(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev = &dataset.Dataset{}
+	if err := json.Unmarshal(data, prev); err != nil {
+		t.Fatal(err)
+	}
+
+	ds := &dataset.Dataset{}
+	paths := []string{writeFile("codebook.pdf", "codebook v2"), writeFile("dictionary.csv", "field,description")}
+	if err := AddAttachments(ctx, store, ds, prev, paths); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"codebook.pdf", "dictionary.csv"}, AttachmentNames(ds)); diff != "" {
+		t.Errorf("attachment names mismatch (-want +got):\n%s", diff)
+	}
+	if ds.Meta.Meta()["source"] != "survey" {
+		t.Errorf("expected arbitrary meta to carry forward. got: %v", ds.Meta.Meta())
+	}
+
+	f, err := OpenAttachment(ctx, store, ds, "codebook.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "codebook v2" {
+		t.Errorf("expected replaced attachment contents. got: %q", string(got))
+	}
+
+	if _, err := OpenAttachment(ctx, store, ds, "missing.pdf"); err != ErrAttachmentNotFound {
+		t.Errorf("expected ErrAttachmentNotFound. got: %v", err)
+	}
+
+	expect := "reading attachment: open /not/a/file.pdf: no such file or directory"
+	if err := AddAttachments(ctx, store, &dataset.Dataset{}, nil, []string{"/not/a/file.pdf"}); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
+
+func TestAttachmentsEmpty(t *testing.T) {
+	for _, ds := range []*dataset.Dataset{nil, {}, {Meta: &dataset.Meta{}}} {
+		if att := Attachments(ds); att == nil || len(att) != 0 {
+			t.Errorf("expected empty, non-nil attachments. got: %v", att)
+		}
+	}
+}
+
+func TestCreateDatasetAttachments(t *testing.T) {
+	ctx := context.Background()
+	// kvstore versions are written as directories, like IPFS
+	store := kvstore.NewFilestore("kv", kvstore.NewMemStore())
+	r, err := repo.NewMemRepo(testPeerProfile, store, qfs.NewMemFS(), profile.NewMemStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "attachments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "codebook.pdf")
+	if err := ioutil.WriteFile(path, []byte("codebook"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ds := indexedTestDataset(1)
+	if err := AddAttachments(ctx, store, ds, nil, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	ref, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), ds, nil, false, true, false, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := store.Get(ctx, PackageFilePath(ref.Path, PackageDirAttachments+"/codebook.pdf"))
+	if err != nil {
+		t.Fatalf("expected attachment to be linked into the version: %s", err)
+	}
+	got, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "codebook" {
+		t.Errorf("linked attachment contents mismatch. got: %q", string(got))
+	}
+	if names := AttachmentNames(ref.Dataset); len(names) != 1 || names[0] != "codebook.pdf" {
+		t.Errorf("expected saved dataset to list its attachment. got: %v", names)
+	}
+}
@@ -63,7 +63,7 @@ func addCitiesDataset(t *testing.T, r repo.Repo) repo.DatasetRef {
 		t.Fatal(err.Error())
 	}
 
-	ref, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true)
+	ref, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
@@ -96,7 +96,7 @@ func updateCitiesDataset(t *testing.T, r repo.Repo) repo.DatasetRef {
 		tc.Input.PreviousPath = ""
 	}()
 
-	ref, err = CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true)
+	ref, err = CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
@@ -110,7 +110,7 @@ func addFlourinatedCompoundsDataset(t *testing.T, r repo.Repo) repo.DatasetRef {
 		t.Fatal(err.Error())
 	}
 
-	ref, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true)
+	ref, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
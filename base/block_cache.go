@@ -0,0 +1,140 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	// registers the dag-pb & raw block decoders used to decode fetched blocks
+	_ "github.com/ipfs/go-merkledag"
+	uio "github.com/ipfs/go-unixfs/io"
+	"github.com/qri-io/qfs"
+)
+
+// BlockCache is an ipld.NodeGetter that keeps recently fetched blocks in an
+// in-memory LRU cache, bounding the cache by the total size of the blocks it
+// holds. Blocks are content-addressed & never change, so cached blocks are
+// only removed when they fall out of the cache
+type BlockCache struct {
+	ng      ipld.NodeGetter
+	maxSize int64
+
+	lk    sync.Mutex
+	size  int64
+	cache *simplelru.LRU
+}
+
+// compile-time assertion that BlockCache is an ipld.NodeGetter
+var _ ipld.NodeGetter = (*BlockCache)(nil)
+
+// NewBlockCache wraps a NodeGetter with a cache that holds up to maxSize bytes
+// of blocks
+func NewBlockCache(ng ipld.NodeGetter, maxSize int64) (*BlockCache, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("block cache size must be greater than zero")
+	}
+	c := &BlockCache{ng: ng, maxSize: maxSize}
+	cache, err := simplelru.NewLRU(int(^uint(0)>>1), func(_, v interface{}) {
+		c.size -= int64(len(v.(ipld.Node).RawData()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.cache = cache
+	return c, nil
+}
+
+// Get implements the ipld.NodeGetter interface, serving cached blocks without
+// touching the wrapped NodeGetter
+func (c *BlockCache) Get(ctx context.Context, id cid.Cid) (ipld.Node, error) {
+	c.lk.Lock()
+	v, ok := c.cache.Get(id)
+	c.lk.Unlock()
+	if ok {
+		return v.(ipld.Node), nil
+	}
+
+	nd, err := c.ng.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.add(nd)
+	return nd, nil
+}
+
+// GetMany implements the ipld.NodeGetter interface
+func (c *BlockCache) GetMany(ctx context.Context, ids []cid.Cid) <-chan *ipld.NodeOption {
+	out := make(chan *ipld.NodeOption, len(ids))
+	go func() {
+		defer close(out)
+		for _, id := range ids {
+			nd, err := c.Get(ctx, id)
+			select {
+			case out <- &ipld.NodeOption{Node: nd, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// add caches a block, evicting the least recently used blocks until the cache
+// fits. blocks larger than the whole cache aren't cached
+func (c *BlockCache) add(nd ipld.Node) {
+	size := int64(len(nd.RawData()))
+	if size > c.maxSize {
+		return
+	}
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.cache.Contains(nd.Cid()) {
+		return
+	}
+	c.cache.Add(nd.Cid(), nd)
+	c.size += size
+	for c.size > c.maxSize {
+		c.cache.RemoveOldest()
+	}
+}
+
+// File reads the unixfs file at an /ipfs/ path through the cache, resolving
+// the path one block at a time. File returns a nil file without an error for
+// paths it can't read, like directories, which callers should read from the
+// store the blocks come from
+func (c *BlockCache) File(ctx context.Context, key string) (qfs.File, error) {
+	if !strings.HasPrefix(key, "/ipfs/") {
+		return nil, nil
+	}
+	names := strings.Split(strings.Trim(strings.TrimPrefix(key, "/ipfs/"), "/"), "/")
+	id, err := cid.Decode(names[0])
+	if err != nil {
+		return nil, nil
+	}
+
+	nd, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for rest := names[1:]; len(rest) > 0; {
+		var lnk *ipld.Link
+		if lnk, rest, err = nd.ResolveLink(rest); err != nil {
+			return nil, err
+		}
+		if nd, err = c.Get(ctx, lnk.Cid); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := uio.NewDagReader(ctx, nd, c)
+	if err == uio.ErrIsDir || err == uio.ErrCantReadSymlinks {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return qfs.NewMemfileReader(key, r), nil
+}
@@ -0,0 +1,119 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	chunker "github.com/ipfs/go-ipfs-chunker"
+	ipld "github.com/ipfs/go-ipld-format"
+	dag "github.com/ipfs/go-merkledag"
+	mdtest "github.com/ipfs/go-merkledag/test"
+	unixfs "github.com/ipfs/go-unixfs"
+	"github.com/ipfs/go-unixfs/importer"
+)
+
+// countingGetter counts calls to Get
+type countingGetter struct {
+	ipld.NodeGetter
+	gets int
+}
+
+func (g *countingGetter) Get(ctx context.Context, id cid.Cid) (ipld.Node, error) {
+	g.gets++
+	return g.NodeGetter.Get(ctx, id)
+}
+
+func TestBlockCache(t *testing.T) {
+	ctx := context.Background()
+	ds := mdtest.Mock()
+	// distinct lines so no two 512 byte chunks are the same block
+	buf := &bytes.Buffer{}
+	for i := 0; buf.Len() < 4096; i++ {
+		fmt.Fprintf(buf, "row %d\n", i)
+	}
+	data := buf.String()[:4096]
+	file, err := importer.BuildDagFromReader(ds, chunker.NewSizeSplitter(strings.NewReader(data), 512))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := dag.NodeWithData(unixfs.FolderPBData())
+	if err := dir.AddNodeLink("body.json", file); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Add(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+	dirKey := "/ipfs/" + dir.Cid().String()
+	fileKey := dirKey + "/body.json"
+
+	counter := &countingGetter{NodeGetter: ds}
+	cache, err := NewBlockCache(counter, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBlockCacheFile(t, cache, fileKey, data)
+	fetched := counter.gets
+	// a directory, a file root & 8 leaves
+	if fetched != 10 {
+		t.Errorf("expected 10 blocks to be fetched, got %d", fetched)
+	}
+	assertBlockCacheFile(t, cache, fileKey, data)
+	if counter.gets != fetched {
+		t.Errorf("expected cached blocks to skip the wrapped getter. got %d more gets", counter.gets-fetched)
+	}
+
+	for _, key := range []string{dirKey, "/map/foo"} {
+		if f, err := cache.File(ctx, key); f != nil || err != nil {
+			t.Errorf("expected %s to be left to the store. got file: %v, err: %v", key, f, err)
+		}
+	}
+
+	small := &countingGetter{NodeGetter: ds}
+	cache, err = NewBlockCache(small, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBlockCacheFile(t, cache, fileKey, data)
+	// readers prefetch blocks concurrently, scan leaves in order so evictions
+	// are predictable. once a scan has filled the cache, a cache smaller than
+	// the file misses on every block of the next scan
+	scan := func() (gets int) {
+		before := small.gets
+		for _, lnk := range file.Links() {
+			if _, err := cache.Get(ctx, lnk.Cid); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return small.gets - before
+	}
+	scan()
+	if got, expect := scan(), len(file.Links()); got != expect {
+		t.Errorf("expected blocks evicted from a full cache to be fetched again. expected %d gets, got %d", expect, got)
+	}
+	if cache.size > 1024 {
+		t.Errorf("expected cache to hold at most 1024 bytes, holds %d", cache.size)
+	}
+}
+
+func assertBlockCacheFile(t *testing.T, cache *BlockCache, key, expect string) {
+	t.Helper()
+	f, err := cache.File(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f == nil {
+		t.Fatalf("expected %s to be read through the cache", key)
+	}
+	got, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, []byte(expect)) {
+		t.Errorf("contents of %s mismatch. expected %d bytes, got %d", key, len(expect), len(got))
+	}
+}
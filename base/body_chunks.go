@@ -0,0 +1,182 @@
+package base
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// DefaultBodyChunkSize is the target size in bytes of body chunks processed
+// in parallel
+const DefaultBodyChunkSize = 4 << 20
+
+// BodyChunk is a contiguous run of complete records from a body
+type BodyChunk struct {
+	// Index is the position of this chunk in the body
+	Index int
+	// Offset is the index of the first entry in the chunk within the body
+	Offset int
+	// Entries is the number of entries in the chunk
+	Entries int
+	// Data is the raw chunk, including the header row for the first chunk of a
+	// body that has one
+	Data []byte
+}
+
+// SplitCSVBody splits raw CSV data into chunks of roughly chunkSize bytes.
+// Chunks always break at record boundaries, never within quoted fields, which
+// may contain newlines. The header row, if present, is kept in the first chunk
+// & isn't counted as an entry
+func SplitCSVBody(st *dataset.Structure, data []byte, chunkSize int) []BodyChunk {
+	if chunkSize <= 0 {
+		chunkSize = DefaultBodyChunkSize
+	}
+
+	sep := byte(',')
+	if fc, err := dataset.ParseFormatConfigMap(dataset.CSVDataFormat, st.FormatConfig); err == nil {
+		if opts, ok := fc.(*dataset.CSVOptions); ok && opts.Separator != rune(0) && opts.Separator < 128 {
+			sep = byte(opts.Separator)
+		}
+	}
+	skipHeader := dsio.HasHeaderRow(st)
+
+	var (
+		chunks     []BodyChunk
+		start      int
+		entries    int
+		offset     int
+		quoted     bool
+		fieldStart = true
+		recordLen  int
+	)
+
+	endRecord := func(end int) {
+		if recordLen > 0 {
+			if skipHeader {
+				skipHeader = false
+			} else {
+				entries++
+			}
+		}
+		recordLen = 0
+		fieldStart = true
+		if end-start >= chunkSize {
+			chunks = append(chunks, BodyChunk{Index: len(chunks), Offset: offset, Entries: entries, Data: data[start:end]})
+			offset += entries
+			entries = 0
+			start = end
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if quoted {
+			if c == '"' {
+				if i+1 < len(data) && data[i+1] == '"' {
+					// escaped quote
+					i++
+				} else {
+					quoted = false
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			// quotes only open a quoted field at the start of a field, elsewhere
+			// they're literal (lazy quotes)
+			if fieldStart {
+				quoted = true
+			}
+			fieldStart = false
+			recordLen++
+		case sep:
+			fieldStart = true
+			recordLen++
+		case '\n':
+			endRecord(i + 1)
+		case '\r':
+			if i+1 < len(data) && data[i+1] == '\n' {
+				i++
+			}
+			endRecord(i + 1)
+		default:
+			fieldStart = false
+			recordLen++
+		}
+	}
+	if recordLen > 0 {
+		if !skipHeader {
+			entries++
+		}
+	}
+	if start < len(data) {
+		chunks = append(chunks, BodyChunk{Index: len(chunks), Offset: offset, Entries: entries, Data: data[start:]})
+	}
+	return chunks
+}
+
+// ChunkStructure returns the structure used to read a body chunk. Only the
+// first chunk of a body contains the header row
+func ChunkStructure(st *dataset.Structure, chunk BodyChunk) *dataset.Structure {
+	if chunk.Index == 0 || !dsio.HasHeaderRow(st) {
+		return st
+	}
+	cp := &dataset.Structure{}
+	cp.Assign(st)
+	cfg := map[string]interface{}{}
+	for k, v := range st.FormatConfig {
+		cfg[k] = v
+	}
+	cfg["headerRow"] = false
+	cp.FormatConfig = cfg
+	return cp
+}
+
+// EachBodyChunk calls fn with an entry reader for each chunk of a CSV body
+// split with SplitCSVBody, processing at most workers chunks at a time.
+// Callers should store per-chunk results by chunk index & merge them in index
+// order for deterministic output. EachBodyChunk returns the error from the
+// lowest indexed chunk that fails
+func EachBodyChunk(st *dataset.Structure, chunks []BodyChunk, workers int, fn func(chunk BodyChunk, r dsio.EntryReader) error) error {
+	if st.Format != dataset.CSVDataFormat.String() {
+		return fmt.Errorf("parallel body processing requires csv data, got: %q", st.Format)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, len(chunks))
+	queue := make(chan BodyChunk)
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range queue {
+				r, err := dsio.NewEntryReader(ChunkStructure(st, chunk), bytes.NewReader(chunk.Data))
+				if err != nil {
+					errs[chunk.Index] = err
+					continue
+				}
+				errs[chunk.Index] = fn(chunk, r)
+			}
+		}()
+	}
+	for _, chunk := range chunks {
+		queue <- chunk
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package base
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestSplitCSVBody(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	data := "city,note\n" +
+		"toronto,\"multi\nline, with comma\"\n" +
+		"new york,\"quote \"\"inside\"\"\"\n" +
+		"\n" +
+		"chicago,plain\r\n" +
+		"chatham,last"
+
+	chunks := SplitCSVBody(st, []byte(data), 1)
+	expect := []struct {
+		offset, entries int
+		data            string
+	}{
+		{0, 0, "city,note\n"},
+		{0, 1, "toronto,\"multi\nline, with comma\"\n"},
+		{1, 1, "new york,\"quote \"\"inside\"\"\"\n"},
+		{2, 0, "\n"},
+		{2, 1, "chicago,plain\r\n"},
+		{3, 1, "chatham,last"},
+	}
+	if len(chunks) != len(expect) {
+		t.Fatalf("chunk count mismatch. expected: %d, got: %d", len(expect), len(chunks))
+	}
+	for i, e := range expect {
+		c := chunks[i]
+		if c.Index != i || c.Offset != e.offset || c.Entries != e.entries || string(c.Data) != e.data {
+			t.Errorf("chunk %d mismatch. expected: %d %d %q, got: %d %d %q", i, e.offset, e.entries, e.data, c.Offset, c.Entries, c.Data)
+		}
+	}
+
+	if chunks := SplitCSVBody(st, []byte(data), 1<<20); len(chunks) != 1 || chunks[0].Entries != 4 {
+		t.Errorf("expected a single chunk with 4 entries, got: %v", chunks)
+	}
+}
+
+func TestEachBodyChunk(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema:       dataset.BaseSchemaArray,
+	}
+	data := "a,b\n" + strings.Repeat("1,\"x\ny\"\n", 500)
+
+	chunks := SplitCSVBody(st, []byte(data), 64)
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got: %d", len(chunks))
+	}
+
+	var entries int64
+	err := EachBodyChunk(st, chunks, 4, func(chunk BodyChunk, r dsio.EntryReader) error {
+		return dsio.EachEntry(r, func(i int, ent dsio.Entry, err error) error {
+			if err != nil {
+				return err
+			}
+			if row, ok := ent.Value.([]interface{}); !ok || row[1] != "x\ny" {
+				t.Errorf("chunk %d entry %d: unexpected value %v", chunk.Index, i, ent.Value)
+			}
+			atomic.AddInt64(&entries, 1)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries != 500 {
+		t.Errorf("expected 500 entries, got: %d", entries)
+	}
+
+	if err := EachBodyChunk(&dataset.Structure{Format: "json"}, nil, 2, nil); err == nil {
+		t.Error("expected non-csv structure to error")
+	}
+}
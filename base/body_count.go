@@ -0,0 +1,178 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// BodyCountSampleBytes is the number of bytes read from the start of a body
+// to estimate its entry count
+const BodyCountSampleBytes = 1 << 20
+
+// BodyCount sources
+const (
+	// BodyCountFromStructure is a count recorded in the dataset structure
+	BodyCountFromStructure = "structure"
+	// BodyCountFromIndex is a count read from a body index
+	BodyCountFromIndex = "index"
+	// BodyCountFromCache is a count cached by an earlier scan
+	BodyCountFromCache = "cache"
+	// BodyCountFromScan is a count computed by streaming the body
+	BodyCountFromScan = "scan"
+	// BodyCountFromSample is a count extrapolated from the start of the body
+	BodyCountFromSample = "sample"
+)
+
+// BodyCount is the size of a dataset body
+type BodyCount struct {
+	// Entries is the number of top-level entries in the body
+	Entries int `json:"entries"`
+	// Length is the size of the body in bytes
+	Length int `json:"length"`
+	// Exact is false when Entries is an estimate
+	Exact bool `json:"exact"`
+	// Source is where the count came from
+	Source string `json:"source"`
+}
+
+// CountBody counts the entries in a dataset body, doing as little work as
+// possible. Counts recorded in the structure are used when present, followed
+// by a cached count in dir or the body index of the version stored in store.
+// Otherwise the open body file is streamed, counting entries without holding
+// them in memory, and the result is cached in dir. When estimate is true & the
+// body is known to be larger than BodyCountSampleBytes only a sample from the
+// start of the body is read & the total is extrapolated. Bodies that aren't
+// stored, like linked working directory bodies, must pass a nil store & an
+// empty dir
+func CountBody(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, dir string, estimate bool) (*BodyCount, error) {
+	st := ds.Structure
+	if st == nil {
+		return nil, fmt.Errorf("structure is required to count a body")
+	}
+	if st.Entries > 0 && st.Length > 0 {
+		return &BodyCount{Entries: st.Entries, Length: st.Length, Exact: true, Source: BodyCountFromStructure}, nil
+	}
+
+	if dir != "" && ds.BodyPath != "" {
+		if c, err := ReadBodyCount(dir, ds.BodyPath); err == nil {
+			return c, nil
+		}
+	}
+	if store != nil && ds.Path != "" {
+		if idx, err := ReadBodyIndex(ctx, store, ds.Path); err == nil {
+			return &BodyCount{Entries: idx.Rows, Length: int(idx.Size), Exact: true, Source: BodyCountFromIndex}, nil
+		}
+	}
+
+	file := ds.BodyFile()
+	if file == nil {
+		return nil, fmt.Errorf("no body file to count")
+	}
+	if estimate && st.Length > BodyCountSampleBytes {
+		sample := make([]byte, BodyCountSampleBytes)
+		n, err := io.ReadFull(file, sample)
+		if err != nil {
+			return nil, err
+		}
+		er, err := dsio.NewEntryReader(st, bytes.NewReader(sample[:n]))
+		if err != nil {
+			return nil, err
+		}
+		entries := 0
+		// the sample likely ends partway through an entry, which stops the count
+		for {
+			if _, err := er.ReadEntry(); err != nil {
+				break
+			}
+			entries++
+		}
+		return &BodyCount{
+			Entries: int(float64(entries) / float64(n) * float64(st.Length)),
+			Length:  st.Length,
+			Source:  BodyCountFromSample,
+		}, nil
+	}
+
+	cr := &countingReader{r: file}
+	er, err := dsio.NewEntryReader(st, cr)
+	if err != nil {
+		return nil, err
+	}
+	entries := 0
+	for {
+		if _, err := er.ReadEntry(); err != nil {
+			if err.Error() == io.EOF.Error() {
+				break
+			}
+			return nil, err
+		}
+		entries++
+	}
+
+	c := &BodyCount{Entries: entries, Length: cr.n, Exact: true, Source: BodyCountFromScan}
+	if dir != "" && ds.BodyPath != "" {
+		if err := WriteBodyCount(dir, ds.BodyPath, c); err != nil {
+			log.Debugf("caching body count: %s", err)
+		}
+	}
+	return c, nil
+}
+
+// countingReader tracks the number of bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+// BodyCountPath returns the location of the cached count for a body path
+// within a directory
+func BodyCountPath(dir, bodyPath string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.count.json", filepath.Base(bodyPath)))
+}
+
+// ReadBodyCount loads a cached body count, reporting its source as
+// BodyCountFromCache
+func ReadBodyCount(dir, bodyPath string) (*BodyCount, error) {
+	data, err := ioutil.ReadFile(BodyCountPath(dir, bodyPath))
+	if err != nil {
+		return nil, err
+	}
+	c := &BodyCount{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	c.Source = BodyCountFromCache
+	return c, nil
+}
+
+// WriteBodyCount caches an exact body count. body paths are
+// content-addressed, so a cached count never needs to be invalidated
+func WriteBodyCount(dir, bodyPath string, c *BodyCount) error {
+	if !c.Exact {
+		return fmt.Errorf("only exact body counts can be cached")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(BodyCountPath(dir, bodyPath), data, 0600)
+}
@@ -0,0 +1,104 @@
+package base
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func bodyCountTestDataset(body string, entries, length int) *dataset.Dataset {
+	ds := &dataset.Dataset{
+		BodyPath: "/map/QmBodyCount",
+		Structure: &dataset.Structure{
+			Format:       "csv",
+			FormatConfig: map[string]interface{}{"headerRow": true},
+			Schema:       dataset.BaseSchemaArray,
+			Entries:      entries,
+			Length:       length,
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+	return ds
+}
+
+func TestCountBody(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "qri_test_body_count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	body := "a,b\n1,2\n3,4\n5,6\n"
+
+	c, err := CountBody(ctx, nil, bodyCountTestDataset(body, 3, len(body)), dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := (BodyCount{Entries: 3, Length: len(body), Exact: true, Source: BodyCountFromStructure}); *c != expect {
+		t.Errorf("structure count mismatch. expected: %v, got: %v", expect, *c)
+	}
+
+	c, err = CountBody(ctx, nil, bodyCountTestDataset(body, 0, 0), dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := (BodyCount{Entries: 3, Length: len(body), Exact: true, Source: BodyCountFromScan}); *c != expect {
+		t.Errorf("scanned count mismatch. expected: %v, got: %v", expect, *c)
+	}
+	fi, err := os.Stat(BodyCountPath(dir, bodyCountTestDataset(body, 0, 0).BodyPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("expected cached count to only be readable by its owner, got mode %s", fi.Mode().Perm())
+	}
+
+	// second count is read from the cache, without a body
+	ds := bodyCountTestDataset(body, 0, 0)
+	ds.SetBodyFile(nil)
+	c, err = CountBody(ctx, nil, ds, dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := (BodyCount{Entries: 3, Length: len(body), Exact: true, Source: BodyCountFromCache}); *c != expect {
+		t.Errorf("cached count mismatch. expected: %v, got: %v", expect, *c)
+	}
+}
+
+func TestCountBodyEstimate(t *testing.T) {
+	ctx := context.Background()
+	rows := BodyCountSampleBytes / 6 * 4
+	body := "a,b\n" + strings.Repeat("10,20\n", rows)
+
+	c, err := CountBody(ctx, nil, bodyCountTestDataset(body, 0, len(body)), "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Exact || c.Source != BodyCountFromSample {
+		t.Errorf("expected a sampled estimate, got: %v", *c)
+	}
+	// rows are uniform, so estimates should be within a percent
+	if c.Entries < rows*99/100 || c.Entries > rows*101/100 {
+		t.Errorf("estimate %d too far from %d", c.Entries, rows)
+	}
+
+	// bodies smaller than the sample are counted exactly
+	small := "a,b\n1,2\n"
+	c, err = CountBody(ctx, nil, bodyCountTestDataset(small, 0, len(small)), "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Exact || c.Entries != 1 {
+		t.Errorf("expected an exact count of 1, got: %v", *c)
+	}
+
+	if err := WriteBodyCount("", "/map/Qm", &BodyCount{}); err == nil {
+		t.Error("expected caching an estimate to error")
+	}
+}
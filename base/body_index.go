@@ -0,0 +1,238 @@
+package base
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// DefaultBodyIndexInterval is the number of rows between indexed offsets
+const DefaultBodyIndexInterval = 1000
+
+// DefaultBodyIndexMinSize is the smallest body size in bytes that gets a body
+// index when no other threshold is configured
+const DefaultBodyIndexMinSize = 1 << 20
+
+var (
+	// ErrNoBodyIndex indicates no index exists for a body
+	ErrNoBodyIndex = fmt.Errorf("no body index")
+	// ErrBodyIndexUnsupported indicates a body format can't be indexed
+	ErrBodyIndexUnsupported = fmt.Errorf("body format doesn't support indexing")
+)
+
+// BodyIndex records the byte offsets of rows in a body file, allowing readers
+// to skip directly to a row instead of parsing every row that precedes it.
+// Only every Interval-th row is recorded to keep indexes small, so a seek
+// lands at most Interval-1 rows before the requested row
+type BodyIndex struct {
+	// number of rows between recorded offsets
+	Interval int `json:"interval"`
+	// bytes that must precede a seeked-to row for the body to parse. For CSV
+	// this is the header row (if any), for JSON the opening bracket or brace
+	Prefix []byte `json:"prefix"`
+	// Offsets[i] is the byte offset of row i*Interval
+	Offsets []int64 `json:"offsets"`
+	// total number of rows in the body
+	Rows int `json:"rows"`
+	// total size of the body in bytes
+	Size int64 `json:"size,omitempty"`
+}
+
+// NewBodyIndex scans a body, recording the offset of every interval-th row.
+// supported formats are CSV & JSON
+func NewBodyIndex(r io.Reader, st *dataset.Structure, interval int) (*BodyIndex, error) {
+	if st == nil {
+		return nil, fmt.Errorf("structure is required to index a body")
+	}
+	if interval <= 0 {
+		interval = DefaultBodyIndexInterval
+	}
+	idx := &BodyIndex{Interval: interval}
+	br := bufio.NewReader(r)
+
+	switch st.DataFormat() {
+	case dataset.CSVDataFormat:
+		headerRow, _ := st.FormatConfig["headerRow"].(bool)
+		return idx, idx.scanCSV(br, headerRow)
+	case dataset.JSONDataFormat:
+		return idx, idx.scanJSON(br)
+	default:
+		return nil, ErrBodyIndexUnsupported
+	}
+}
+
+// addRow records a row starting at offset
+func (idx *BodyIndex) addRow(offset int64) {
+	if idx.Rows%idx.Interval == 0 {
+		idx.Offsets = append(idx.Offsets, offset)
+	}
+	idx.Rows++
+}
+
+func (idx *BodyIndex) scanCSV(r io.ByteReader, headerRow bool) error {
+	var (
+		pos        int64
+		rowStarted bool
+		inQuote    bool
+		inHeader   = headerRow
+		header     = &bytes.Buffer{}
+	)
+
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if inHeader {
+			header.WriteByte(b)
+		}
+
+		if !rowStarted && b != '\n' && b != '\r' {
+			// csv readers skip blank lines, only count lines with content
+			rowStarted = true
+			if !inHeader {
+				idx.addRow(pos)
+			}
+		}
+
+		if b == '"' {
+			inQuote = !inQuote
+		} else if b == '\n' && !inQuote {
+			rowStarted = false
+			inHeader = false
+		}
+		pos++
+	}
+
+	if headerRow {
+		idx.Prefix = header.Bytes()
+	}
+	idx.Size = pos
+	return nil
+}
+
+func (idx *BodyIndex) scanJSON(r io.ByteReader) error {
+	var (
+		pos         int64
+		depth       int
+		inString    bool
+		escaped     bool
+		expectEntry bool
+	)
+
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if depth == 0 {
+			switch b {
+			case '[', '{':
+				idx.Prefix = []byte{b}
+				depth = 1
+				expectEntry = true
+			case ' ', '\t', '\n', '\r':
+			default:
+				return fmt.Errorf("invalid json body: top level must be an array or object")
+			}
+			pos++
+			continue
+		}
+
+		if expectEntry && depth == 1 {
+			switch b {
+			case ' ', '\t', '\n', '\r', ']', '}':
+			default:
+				idx.addRow(pos)
+				expectEntry = false
+			}
+		}
+
+		if inString {
+			if escaped {
+				escaped = false
+			} else if b == '\\' {
+				escaped = true
+			} else if b == '"' {
+				inString = false
+			}
+			pos++
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = true
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case ',':
+			if depth == 1 {
+				expectEntry = true
+			}
+		}
+		pos++
+	}
+
+	idx.Size = pos
+	return nil
+}
+
+// Seek positions a body reader at the closest indexed row at or before row,
+// returning a reader that yields a parsable body starting at the indexed row,
+// and the number of rows remaining to skip to reach the requested row
+func (idx *BodyIndex) Seek(r io.Reader, row int) (io.Reader, int, error) {
+	if row <= 0 || len(idx.Offsets) == 0 {
+		return r, row, nil
+	}
+
+	i := row / idx.Interval
+	if i >= len(idx.Offsets) {
+		i = len(idx.Offsets) - 1
+	}
+	target := idx.Offsets[i]
+
+	if s, ok := r.(io.Seeker); ok {
+		if _, err := s.Seek(target, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+	} else if _, err := io.CopyN(ioutil.Discard, r, target); err != nil {
+		return nil, 0, err
+	}
+
+	return io.MultiReader(bytes.NewReader(idx.Prefix), r), row - i*idx.Interval, nil
+}
+
+// ReadBodyIndex loads the body index written into the directory of the
+// dataset version at dsPath, returning ErrNoBodyIndex if the version has none
+func ReadBodyIndex(ctx context.Context, store cafs.Filestore, dsPath string) (*BodyIndex, error) {
+	f, err := store.Get(ctx, PackageFilePath(dsPath, PackageFileBodyIndex))
+	if err == cafs.ErrNotFound {
+		return nil, ErrNoBodyIndex
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	idx := &BodyIndex{}
+	// stores that don't write versions as directories resolve paths within a
+	// version to the version's dataset file, which doesn't decode to an index
+	if err := json.NewDecoder(f).Decode(idx); err != nil || idx.Interval <= 0 {
+		return nil, ErrNoBodyIndex
+	}
+	return idx, nil
+}
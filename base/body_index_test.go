@@ -0,0 +1,101 @@
+package base
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestNewBodyIndex(t *testing.T) {
+	csvHeader := &dataset.Structure{Format: "csv", FormatConfig: map[string]interface{}{"headerRow": true}}
+	csvNoHeader := &dataset.Structure{Format: "csv"}
+	jsonSt := &dataset.Structure{Format: "json"}
+
+	cases := []struct {
+		description string
+		st          *dataset.Structure
+		body        string
+		interval    int
+		prefix      string
+		offsets     []int64
+		rows        int
+	}{
+		{"csv without header", csvNoHeader, "a,1\nb,2\nc,3\n", 1, "", []int64{0, 4, 8}, 3},
+		{"csv with header", csvHeader, "name,n\na,1\nb,2\nc,3\n", 2, "name,n\n", []int64{7, 15}, 3},
+		{"csv quoted newline", csvNoHeader, "\"a\nb\",1\nc,2", 1, "", []int64{0, 8}, 2},
+		{"csv blank lines", csvNoHeader, "a,1\n\nb,2\r\n\r\nc,3", 1, "", []int64{0, 5, 12}, 3},
+		{"json array", jsonSt, `[1, "a,]", [2,3], {"b":4}]`, 1, "[", []int64{1, 4, 11, 18}, 4},
+		{"json object", jsonSt, ` {"a": 1, "b\"": [1,2]}`, 1, "{", []int64{2, 10}, 2},
+		{"json empty array", jsonSt, `[ ]`, 1, "[", nil, 0},
+	}
+
+	for _, c := range cases {
+		idx, err := NewBodyIndex(strings.NewReader(c.body), c.st, c.interval)
+		if err != nil {
+			t.Errorf("case %q unexpected error: %s", c.description, err)
+			continue
+		}
+		if string(idx.Prefix) != c.prefix {
+			t.Errorf("case %q prefix mismatch. want: %q, got: %q", c.description, c.prefix, string(idx.Prefix))
+		}
+		if !reflect.DeepEqual(idx.Offsets, c.offsets) {
+			t.Errorf("case %q offsets mismatch. want: %v, got: %v", c.description, c.offsets, idx.Offsets)
+		}
+		if idx.Rows != c.rows {
+			t.Errorf("case %q rows mismatch. want: %d, got: %d", c.description, c.rows, idx.Rows)
+		}
+		if idx.Size != int64(len(c.body)) {
+			t.Errorf("case %q size mismatch. want: %d, got: %d", c.description, len(c.body), idx.Size)
+		}
+	}
+
+	if _, err := NewBodyIndex(strings.NewReader(""), &dataset.Structure{Format: "cbor"}, 1); err != ErrBodyIndexUnsupported {
+		t.Errorf("expected cbor to be unsupported. got: %v", err)
+	}
+	if _, err := NewBodyIndex(strings.NewReader("5"), jsonSt, 1); err == nil {
+		t.Error("expected scalar json body to error")
+	}
+}
+
+func TestBodyIndexSeek(t *testing.T) {
+	body := "name,n\na,1\nb,2\nc,3\nd,4\ne,5\n"
+	st := &dataset.Structure{Format: "csv", FormatConfig: map[string]interface{}{"headerRow": true}}
+	idx, err := NewBodyIndex(strings.NewReader(body), st, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		row    int
+		expect string
+		skip   int
+	}{
+		{0, body, 0},
+		{1, "name,n\na,1\nb,2\nc,3\nd,4\ne,5\n", 1},
+		{2, "name,n\nc,3\nd,4\ne,5\n", 0},
+		{3, "name,n\nc,3\nd,4\ne,5\n", 1},
+		{4, "name,n\ne,5\n", 0},
+		{9, "name,n\ne,5\n", 5},
+	}
+
+	for _, c := range cases {
+		rdr, skip, err := idx.Seek(strings.NewReader(body), c.row)
+		if err != nil {
+			t.Errorf("row %d unexpected error: %s", c.row, err)
+			continue
+		}
+		data, err := ioutil.ReadAll(rdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.expect {
+			t.Errorf("row %d body mismatch. want: %q, got: %q", c.row, c.expect, string(data))
+		}
+		if skip != c.skip {
+			t.Errorf("row %d skip mismatch. want: %d, got: %d", c.row, c.skip, skip)
+		}
+	}
+}
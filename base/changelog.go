@@ -0,0 +1,172 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qri-io/qri/repo"
+)
+
+// ChangelogEntry is a single dataset version in a changelog
+type ChangelogEntry struct {
+	// Version is the position of this version in dataset history, starting
+	// at 1 for the first version
+	Version   int       `json:"version"`
+	Path      string    `json:"path"`
+	Timestamp time.Time `json:"timestamp"`
+	Title     string    `json:"title,omitempty"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// ChangelogGrouping enumerates ways to group changelog entries
+type ChangelogGrouping string
+
+const (
+	// ChangelogByDate groups changelog entries by commit date
+	ChangelogByDate = ChangelogGrouping("date")
+	// ChangelogByVersion gives each version a changelog section
+	ChangelogByVersion = ChangelogGrouping("version")
+)
+
+// ChangelogFormatMarkdown is the markdown changelog format
+const ChangelogFormatMarkdown = "md"
+
+// Changelog lists the versions of a dataset newest first
+func Changelog(ctx context.Context, r repo.Repo, ref repo.DatasetRef) ([]ChangelogEntry, error) {
+	versions, err := DatasetHistory(ctx, r, ref, -1, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ChangelogEntry, len(versions))
+	for i, v := range versions {
+		e := ChangelogEntry{
+			Version: len(versions) - i,
+			Path:    v.Path,
+		}
+		if v.Dataset != nil && v.Dataset.Commit != nil {
+			e.Timestamp = v.Dataset.Commit.Timestamp
+			e.Title = v.Dataset.Commit.Title
+			e.Message = v.Dataset.Commit.Message
+		}
+		entries[i] = e
+	}
+	return entries, nil
+}
+
+// ChangelogRange limits newest-first changelog entries to the range of
+// versions from & to, inclusive. Range bounds can be a version number
+// (starting at 1 for the first version) or a version path. Empty bounds
+// extend the range to the start or end of history
+func ChangelogRange(entries []ChangelogEntry, from, to string) (_ []ChangelogEntry, err error) {
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	first, last := 1, len(entries)
+	if from != "" {
+		if first, err = changelogVersion(entries, from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if last, err = changelogVersion(entries, to); err != nil {
+			return nil, err
+		}
+	}
+	if first > last {
+		return nil, fmt.Errorf("invalid version range: %s is newer than %s", from, to)
+	}
+
+	// entries are newest first, version n is at index len - n
+	return entries[len(entries)-last : len(entries)-first+1], nil
+}
+
+// changelogVersion resolves a version number or path to a version number
+func changelogVersion(entries []ChangelogEntry, v string) (int, error) {
+	if n, err := strconv.Atoi(v); err == nil {
+		if n < 1 || n > len(entries) {
+			return 0, fmt.Errorf("version %d out of range. dataset has %d versions", n, len(entries))
+		}
+		return n, nil
+	}
+	for _, e := range entries {
+		if e.Path == v {
+			return e.Version, nil
+		}
+	}
+	return 0, fmt.Errorf("version %q not found in dataset history", v)
+}
+
+// CheckChangelogFormat errors if a changelog can't be rendered with the given
+// grouping & format
+func CheckChangelogFormat(group ChangelogGrouping, format string) error {
+	switch format {
+	case ChangelogFormatMarkdown, "":
+	default:
+		return fmt.Errorf("unsupported changelog format %q", format)
+	}
+	switch group {
+	case ChangelogByDate, ChangelogByVersion, "":
+	default:
+		return fmt.Errorf("unknown changelog grouping %q. must be one of: date, version", group)
+	}
+	return nil
+}
+
+// FormatChangelog renders changelog entries as text in the given format,
+// grouping entries by date or version. title heads the changelog
+func FormatChangelog(title string, entries []ChangelogEntry, group ChangelogGrouping, format string) (string, error) {
+	if err := CheckChangelogFormat(group, format); err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# Changelog: %s\n", title)
+
+	switch group {
+	case ChangelogByDate, "":
+		date := ""
+		for _, e := range entries {
+			if d := e.Timestamp.UTC().Format("2006-01-02"); d != date {
+				date = d
+				fmt.Fprintf(buf, "\n## %s\n\n", date)
+			}
+			fmt.Fprintf(buf, "- %s (v%d)\n", changelogTitle(e), e.Version)
+			if e.Message != "" {
+				fmt.Fprintf(buf, "%s\n", indent(e.Message, "  "))
+			}
+		}
+	case ChangelogByVersion:
+		for _, e := range entries {
+			fmt.Fprintf(buf, "\n## v%d (%s)\n\n", e.Version, e.Timestamp.UTC().Format("2006-01-02"))
+			fmt.Fprintf(buf, "%s\n", changelogTitle(e))
+			if e.Message != "" {
+				fmt.Fprintf(buf, "\n%s\n", e.Message)
+			}
+		}
+	}
+
+	return buf.String(), nil
+}
+
+func changelogTitle(e ChangelogEntry) string {
+	if e.Title == "" {
+		return "(no title)"
+	}
+	return e.Title
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = prefix + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,115 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChangelog(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	ref := addCitiesDataset(t, r)
+	head := updateCitiesDataset(t, r)
+
+	all, err := Changelog(ctx, r, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 entries, got: %d", len(all))
+	}
+	entries := all
+	if entries[0].Version != 2 || entries[0].Path != head.Path {
+		t.Errorf("expected newest entry first. got: %v", entries[0])
+	}
+	if entries[1].Title == "" {
+		t.Error("expected entries to have commit titles")
+	}
+
+	if entries, err = ChangelogRange(all, "1", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != ref.Path {
+		t.Errorf("expected range 1..1 to return only the first version. got: %v", entries)
+	}
+
+	if entries, err = ChangelogRange(all, head.Path, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Version != 2 {
+		t.Errorf("expected range from head path to return only the head version. got: %v", entries)
+	}
+
+	bad := []struct {
+		from, to, err string
+	}{
+		{"3", "", "version 3 out of range. dataset has 2 versions"},
+		{"", "/ipfs/nope", `version "/ipfs/nope" not found in dataset history`},
+		{"2", "1", "invalid version range: 2 is newer than 1"},
+	}
+	for _, c := range bad {
+		if _, err := ChangelogRange(all, c.from, c.to); err == nil || err.Error() != c.err {
+			t.Errorf("range %q..%q error mismatch. expected: %q, got: %v", c.from, c.to, c.err, err)
+		}
+	}
+}
+
+func TestFormatChangelog(t *testing.T) {
+	entries := []ChangelogEntry{
+		{Version: 3, Timestamp: time.Date(2019, 2, 1, 12, 0, 0, 0, time.UTC), Title: "add february data", Message: "new rows\nfixed typos"},
+		{Version: 2, Timestamp: time.Date(2019, 1, 1, 18, 0, 0, 0, time.UTC), Title: "update meta"},
+		{Version: 1, Timestamp: time.Date(2019, 1, 1, 9, 0, 0, 0, time.UTC), Title: "created dataset"},
+	}
+
+	got, err := FormatChangelog("peer/ds", entries, ChangelogByDate, "md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `# Changelog: peer/ds
+
+## 2019-02-01
+
+- add february data (v3)
+  new rows
+  fixed typos
+
+## 2019-01-01
+
+- update meta (v2)
+- created dataset (v1)
+`
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("by date result mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = FormatChangelog("peer/ds", entries[:2], ChangelogByVersion, "md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = `# Changelog: peer/ds
+
+## v3 (2019-02-01)
+
+add february data
+
+new rows
+fixed typos
+
+## v2 (2019-01-01)
+
+update meta
+`
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("by version result mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := FormatChangelog("peer/ds", entries, ChangelogByDate, "html"); err == nil {
+		t.Error("expected unsupported format to error")
+	}
+	if _, err := FormatChangelog("peer/ds", entries, ChangelogGrouping("author"), "md"); err == nil {
+		t.Error("expected unknown grouping to error")
+	}
+}
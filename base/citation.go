@@ -0,0 +1,106 @@
+package base
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/qri-io/dataset"
+)
+
+// CitationsAccessedKey is the arbitrary meta key that records the date each
+// citation URL was accessed. dataset.Citation has no accessed field, keeping
+// dates in meta avoids changing the citation model
+const CitationsAccessedKey = "citationsAccessed"
+
+// CitationDateFormat is the layout of citation accessed dates
+const CitationDateFormat = "2006-01-02"
+
+// ValidateCitation checks a citation has a title & an absolute http(s) url
+func ValidateCitation(c *dataset.Citation) error {
+	if c == nil {
+		return fmt.Errorf("citation is required")
+	}
+	if c.Name == "" {
+		return fmt.Errorf("citation title is required")
+	}
+	if c.URL == "" {
+		return fmt.Errorf("citation url is required")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid citation url: %s", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid citation url %q: must be an absolute http or https url", c.URL)
+	}
+	return nil
+}
+
+// AddCitation validates & adds a citation to dataset metadata, recording the
+// date it was accessed. A citation with the same url is replaced
+func AddCitation(md *dataset.Meta, c *dataset.Citation, accessed time.Time) error {
+	if md == nil {
+		return fmt.Errorf("meta is required")
+	}
+	if err := ValidateCitation(c); err != nil {
+		return err
+	}
+
+	citations := []*dataset.Citation{}
+	for _, existing := range md.Citations {
+		if existing != nil && existing.URL != c.URL {
+			citations = append(citations, existing)
+		}
+	}
+	md.Citations = append(citations, c)
+
+	if !accessed.IsZero() {
+		dates := CitationsAccessed(md)
+		dates[c.URL] = accessed.Format(CitationDateFormat)
+		md.Meta()[CitationsAccessedKey] = dates
+	}
+	return nil
+}
+
+// RemoveCitation removes the citation with the given url from dataset
+// metadata, erroring if no such citation exists
+func RemoveCitation(md *dataset.Meta, citationURL string) error {
+	if md == nil {
+		return fmt.Errorf("meta is required")
+	}
+
+	citations := []*dataset.Citation{}
+	for _, c := range md.Citations {
+		if c != nil && c.URL != citationURL {
+			citations = append(citations, c)
+		}
+	}
+	if len(citations) == len(md.Citations) {
+		return fmt.Errorf("no citation with url %q", citationURL)
+	}
+	md.Citations = citations
+
+	dates := CitationsAccessed(md)
+	delete(dates, citationURL)
+	if len(dates) == 0 {
+		delete(md.Meta(), CitationsAccessedKey)
+	} else {
+		md.Meta()[CitationsAccessedKey] = dates
+	}
+	return nil
+}
+
+// CitationsAccessed returns a map of citation url to accessed date
+func CitationsAccessed(md *dataset.Meta) map[string]interface{} {
+	dates := map[string]interface{}{}
+	if md == nil {
+		return dates
+	}
+	if existing, ok := md.Meta()[CitationsAccessedKey].(map[string]interface{}); ok {
+		for k, v := range existing {
+			dates[k] = v
+		}
+	}
+	return dates
+}
@@ -0,0 +1,89 @@
+package base
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsviz"
+)
+
+func TestValidateCitation(t *testing.T) {
+	cases := []struct {
+		c   *dataset.Citation
+		err string
+	}{
+		{nil, "citation is required"},
+		{&dataset.Citation{URL: "https://example.com"}, "citation title is required"},
+		{&dataset.Citation{Name: "ex"}, "citation url is required"},
+		{&dataset.Citation{Name: "ex", URL: "ftp://example.com"}, `invalid citation url "ftp://example.com": must be an absolute http or https url`},
+		{&dataset.Citation{Name: "ex", URL: "https://example.com/data.csv"}, ""},
+	}
+	for i, c := range cases {
+		err := ValidateCitation(c.c)
+		if (err == nil && c.err != "") || (err != nil && err.Error() != c.err) {
+			t.Errorf("case %d error mismatch. want: %q, got: %v", i, c.err, err)
+		}
+	}
+}
+
+func TestAddRemoveCitation(t *testing.T) {
+	md := &dataset.Meta{}
+	accessed := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := AddCitation(md, &dataset.Citation{Name: "a", URL: "https://a.com"}, accessed); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddCitation(md, &dataset.Citation{Name: "b", URL: "https://b.com"}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	// adding a url again replaces the existing citation
+	if err := AddCitation(md, &dataset.Citation{Name: "a2", URL: "https://a.com"}, accessed); err != nil {
+		t.Fatal(err)
+	}
+	if len(md.Citations) != 2 || md.Citations[1].Name != "a2" {
+		t.Errorf("unexpected citations: %v", md.Citations)
+	}
+	dates := CitationsAccessed(md)
+	if len(dates) != 1 || dates["https://a.com"] != "2019-10-01" {
+		t.Errorf("unexpected accessed dates: %v", dates)
+	}
+
+	if err := RemoveCitation(md, "https://c.com"); err == nil {
+		t.Error("expected removing a missing citation to error")
+	}
+	if err := RemoveCitation(md, "https://a.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(md.Citations) != 1 || md.Citations[0].URL != "https://b.com" {
+		t.Errorf("unexpected citations after remove: %v", md.Citations)
+	}
+	if _, ok := md.Meta()[CitationsAccessedKey]; ok {
+		t.Error("expected empty accessed dates to be dropped from meta")
+	}
+}
+
+func TestDefaultTemplateCitations(t *testing.T) {
+	md := &dataset.Meta{Title: "cited"}
+	if err := AddCitation(md, &dataset.Citation{Name: "Source A", URL: "https://a.com"}, time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	ds := &dataset.Dataset{Meta: md, Commit: &dataset.Commit{Title: "initial commit"}}
+	MaybeAddDefaultViz(ds)
+
+	data, err := dsviz.Render(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, err := ioutil.ReadAll(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{`<a href="https://a.com">Source A</a>`, "accessed 2019-10-01"} {
+		if !strings.Contains(string(html), expect) {
+			t.Errorf("expected rendered output to contain %q. got:\n%s", expect, string(html))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package base
+
+import (
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// compressionExts maps file extensions to the compression scheme they imply
+var compressionExts = map[string]string{
+	".gz":  "gzip",
+	".bz2": "bzip2",
+}
+
+// TrimCompressionExt removes a trailing compression extension from a
+// filename, returning the trimmed name & the compression scheme. filenames
+// without a compression extension are returned unchanged with an empty scheme.
+// "data.csv.gz" becomes "data.csv", "gzip"
+func TrimCompressionExt(filename string) (string, string) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if compression, ok := compressionExts[ext]; ok {
+		return filename[:len(filename)-len(ext)], compression
+	}
+	return filename, ""
+}
+
+// DecompressReader wraps a reader in a decompressor for the given compression
+// scheme. read errors from the returned reader identify the body as corrupt.
+// if r is an io.Closer, closing the returned reader closes r
+func DecompressReader(r io.Reader, compression string) (io.Reader, error) {
+	switch compression {
+	case "":
+		return r, nil
+	case "gzip":
+		gzr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading gzip body: %s", err)
+		}
+		return decompressErrReader{r: gzr, src: r, compression: compression}, nil
+	case "bzip2":
+		return decompressErrReader{r: bzip2.NewReader(r), src: r, compression: compression}, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", compression)
+	}
+}
+
+// decompressErrReader annotates decompression errors, so a corrupt or
+// truncated body doesn't surface as a confusing parse error
+type decompressErrReader struct {
+	r           io.Reader
+	src         io.Reader
+	compression string
+}
+
+func (d decompressErrReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("reading %s body: file is corrupt or truncated: %s", d.compression, err)
+	}
+	return n, err
+}
+
+func (d decompressErrReader) Close() error {
+	if closer, ok := d.src.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
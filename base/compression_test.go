@@ -0,0 +1,133 @@
+package base
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestTrimCompressionExt(t *testing.T) {
+	cases := []struct {
+		in, name, compression string
+	}{
+		{"data.csv", "data.csv", ""},
+		{"data.csv.gz", "data.csv", "gzip"},
+		{"data.JSON.GZ", "data.JSON", "gzip"},
+		{"data.json.bz2", "data.json", "bzip2"},
+		{"data.gz.csv", "data.gz.csv", ""},
+	}
+	for _, c := range cases {
+		name, compression := TrimCompressionExt(c.in)
+		if name != c.name || compression != c.compression {
+			t.Errorf("case %q mismatch. want: %q %q, got: %q %q", c.in, c.name, c.compression, name, compression)
+		}
+	}
+}
+
+func TestDecompressReader(t *testing.T) {
+	body := "a,b\n1,2\n"
+	bz2Body, err := hex.DecodeString("425a6839314159265359bf87407f00000359000010000430003000200030c00869b28823278bb9229c28485fc3a03f80")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzBody := gzipBytes(t, body)
+
+	good := []struct {
+		compression string
+		data        []byte
+	}{
+		{"", []byte(body)},
+		{"gzip", gzBody},
+		{"bzip2", bz2Body},
+	}
+	for _, c := range good {
+		r, err := DecompressReader(bytes.NewReader(c.data), c.compression)
+		if err != nil {
+			t.Errorf("case %q unexpected error: %s", c.compression, err)
+			continue
+		}
+		got, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Errorf("case %q unexpected read error: %s", c.compression, err)
+			continue
+		}
+		if string(got) != body {
+			t.Errorf("case %q body mismatch. want: %q, got: %q", c.compression, body, string(got))
+		}
+	}
+
+	if _, err := DecompressReader(strings.NewReader(body), "gzip"); err == nil {
+		t.Error("expected non-gzip data to error")
+	}
+
+	r, err := DecompressReader(bytes.NewReader(gzBody[:len(gzBody)-6]), "gzip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadAll(r); err == nil || !strings.Contains(err.Error(), "corrupt or truncated") {
+		t.Errorf("expected truncated gzip read to error. got: %v", err)
+	}
+
+	if _, err := DecompressReader(strings.NewReader(body), "zip"); err == nil {
+		t.Error("expected unsupported compression to error")
+	}
+}
+
+func TestDatasetBodyFileCompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestDatasetBodyFileCompressed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	body := "a,b\n1,2\n"
+	path := filepath.Join(dir, "body.csv.gz")
+	if err := ioutil.WriteFile(path, gzipBytes(t, body), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := DatasetBodyFile(context.Background(), nil, &dataset.Dataset{BodyPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if file.FileName() != "body.csv" {
+		t.Errorf("filename mismatch. want: %q, got: %q", "body.csv", file.FileName())
+	}
+	got, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("body mismatch. want: %q, got: %q", body, string(got))
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json.gz")
+	if err := ioutil.WriteFile(corrupt, []byte("not gzip"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DatasetBodyFile(context.Background(), nil, &dataset.Dataset{BodyPath: corrupt}); err == nil {
+		t.Error("expected corrupt gzip body to error")
+	}
+}
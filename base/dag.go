@@ -90,3 +90,50 @@ func NewDAGInfo(ctx context.Context, store cafs.Filestore, ng ipld.NodeGetter, p
 	}
 	return info, nil
 }
+
+// DAGDelta describes blocks present in one DAG but not another
+type DAGDelta struct {
+	// number of blocks in the right DAG that are absent from the left DAG
+	Blocks int `json:"blocks"`
+	// total size in bytes of blocks in the right DAG absent from the left DAG
+	Size uint64 `json:"size"`
+}
+
+// NewDAGDelta compares the DAGs rooted at left and right paths, counting blocks
+// in the right DAG that are not present in the left DAG & summing their sizes
+func NewDAGDelta(ctx context.Context, ng ipld.NodeGetter, left, right string) (*DAGDelta, error) {
+	lid, err := cid.Parse(left)
+	if err != nil {
+		return nil, err
+	}
+	rid, err := cid.Parse(right)
+	if err != nil {
+		return nil, err
+	}
+
+	lm, err := dag.NewManifest(ctx, ng, lid)
+	if err != nil {
+		return nil, err
+	}
+	rinfo, err := dag.NewInfo(ctx, ng, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	present := map[string]bool{}
+	for _, id := range lm.Nodes {
+		present[id] = true
+	}
+
+	delta := &DAGDelta{}
+	for i, id := range rinfo.Manifest.Nodes {
+		if present[id] {
+			continue
+		}
+		delta.Blocks++
+		if i < len(rinfo.Sizes) {
+			delta.Size += rinfo.Sizes[i]
+		}
+	}
+	return delta, nil
+}
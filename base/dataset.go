@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -32,6 +33,12 @@ func OpenDataset(ctx context.Context, fsys qfs.Filesystem, ds *dataset.Dataset)
 			return
 		}
 	}
+	return OpenDatasetScripts(ctx, fsys, ds)
+}
+
+// OpenDatasetScripts is OpenDataset without the body, opening the transform &
+// viz scripts of a dataset
+func OpenDatasetScripts(ctx context.Context, fsys qfs.Filesystem, ds *dataset.Dataset) (err error) {
 	if ds.Transform != nil && ds.Transform.ScriptFile() == nil {
 		if err = ds.Transform.OpenScriptFile(ctx, fsys); err != nil {
 			log.Debug(err)
@@ -170,8 +177,10 @@ func ListDatasets(ctx context.Context, r repo.Repo, term string, limit, offset i
 
 // CreateDataset uses dsfs to add a dataset to a repo's store, updating all
 // references within the repo if successful. CreateDataset is a lower-level
-// component of github.com/qri-io/qri/actions.CreateDataset
-func CreateDataset(ctx context.Context, r repo.Repo, streams ioes.IOStreams, ds, dsPrev *dataset.Dataset, dryRun, pin, force, shouldRender bool) (ref repo.DatasetRef, err error) {
+// component of github.com/qri-io/qri/actions.CreateDataset. Bodies of at least
+// bodyIndexMinSize bytes get a body index written into the version's
+// directory, a negative bodyIndexMinSize turns indexing off
+func CreateDataset(ctx context.Context, r repo.Repo, streams ioes.IOStreams, ds, dsPrev *dataset.Dataset, dryRun, pin, force, shouldRender bool, bodyIndexMinSize int64) (ref repo.DatasetRef, err error) {
 	var (
 		pro     *profile.Profile
 		path    string
@@ -187,7 +196,12 @@ func CreateDataset(ctx context.Context, r repo.Repo, streams ioes.IOStreams, ds,
 		return
 	}
 
-	if path, err = dsfs.CreateDataset(ctx, r.Store(), ds, dsPrev, r.PrivateKey(), pin, force, shouldRender); err != nil {
+	if dryRun {
+		// dry runs are written to a store that's thrown away
+		bodyIndexMinSize = -1
+	}
+	store := newPackageStore(r.Store(), ds, bodyIndexMinSize)
+	if path, err = dsfs.CreateDataset(ctx, store, ds, dsPrev, r.PrivateKey(), pin, force, shouldRender); err != nil {
 		return
 	}
 	if ds.PreviousPath != "" && ds.PreviousPath != "/" {
@@ -356,7 +370,7 @@ func InlineJSONBody(ds *dataset.Dataset) error {
 		Schema: in.Schema,
 	})
 
-	data, err := ConvertBodyFile(file, in, st, 0, 0, true)
+	data, _, err := ConvertBodyFile(file, in, st, nil, 0, 0, true)
 	if err != nil {
 		log.Errorf("converting body file to JSON: %s", err)
 		return fmt.Errorf("converting body file to JSON: %s", err)
@@ -367,8 +381,10 @@ func InlineJSONBody(ds *dataset.Dataset) error {
 }
 
 // ConvertBodyFile takes an input file & structure, and converts a specified selection
-// to the structure specified by out
-func ConvertBodyFile(file qfs.File, in, out *dataset.Structure, limit, offset int, all bool) (data []byte, err error) {
+// to the structure specified by out. when where is non-nil only rows that match
+// the filter are selected, limit & offset apply to matching rows. ConvertBodyFile
+// also returns the number of entries written
+func ConvertBodyFile(file qfs.File, in, out *dataset.Structure, where *RowFilter, limit, offset int, all bool) (data []byte, entries int, err error) {
 	buf := &bytes.Buffer{}
 
 	w, err := dsio.NewEntryWriter(out, buf)
@@ -393,6 +409,9 @@ func ConvertBodyFile(file qfs.File, in, out *dataset.Structure, limit, offset in
 		return
 	}
 
+	if where != nil {
+		rr = &FilteredReader{EntryReader: rr, Filter: where}
+	}
 	if !all {
 		rr = &dsio.PagedReader{
 			Reader: rr,
@@ -400,25 +419,47 @@ func ConvertBodyFile(file qfs.File, in, out *dataset.Structure, limit, offset in
 			Offset: offset,
 		}
 	}
-	err = dsio.Copy(rr, w)
+	cw := &countingEntryWriter{EntryWriter: w}
+	err = dsio.Copy(rr, cw)
 
 	if err := w.Close(); err != nil {
-		return nil, fmt.Errorf("error closing row buffer: %s", err.Error())
+		return nil, 0, fmt.Errorf("error closing row buffer: %s", err.Error())
 	}
 
-	return buf.Bytes(), nil
+	return buf.Bytes(), cw.count, nil
+}
+
+// countingEntryWriter counts entries as they're written
+type countingEntryWriter struct {
+	dsio.EntryWriter
+	count int
+}
+
+// WriteEntry writes an entry to the wrapped writer, counting it
+func (w *countingEntryWriter) WriteEntry(ent dsio.Entry) error {
+	if err := w.EntryWriter.WriteEntry(ent); err != nil {
+		return err
+	}
+	w.count++
+	return nil
 }
 
 // DatasetBodyFile creates a streaming data file from a Dataset using the following precedence:
 // * ds.BodyBytes not being nil (requires ds.Structure.Format be set to know data format)
 // * ds.BodyPath being a url
 // * ds.BodyPath being a path on the local filesystem
+// bodies with a .gz or .bz2 extension are decompressed as they're read, ndjson
+// bodies are converted to json arrays
 // TODO - consider moving this func to some other package. maybe actions?
 func DatasetBodyFile(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset) (qfs.File, error) {
 	if ds.BodyBytes != nil {
 		if ds.Structure == nil || ds.Structure.Format == "" {
 			return nil, fmt.Errorf("specifying bodyBytes requires format be specified in dataset.structure")
 		}
+		if IsNDJSONFormat(ds.Structure.Format) {
+			ds.Structure.Format = dataset.JSONDataFormat.String()
+			return qfs.NewMemfileReader("body.json", NDJSONToJSONReader(bytes.NewReader(ds.BodyBytes))), nil
+		}
 		return qfs.NewMemfileBytes(fmt.Sprintf("body.%s", ds.Structure.Format), ds.BodyBytes), nil
 	}
 
@@ -442,17 +483,40 @@ func DatasetBodyFile(ctx context.Context, store cafs.Filestore, ds *dataset.Data
 			return nil, fmt.Errorf("invalid status code fetching body url: %d", res.StatusCode)
 		}
 
-		return qfs.NewMemfileReader(filename, res.Body), nil
+		filename, compression := TrimCompressionExt(filename)
+		body, err := DecompressReader(res.Body, compression)
+		if err != nil {
+			res.Body.Close()
+			return nil, fmt.Errorf("body file: %s", err.Error())
+		}
+		filename, body = ndjsonBodyAsJSON(filename, body)
+		return qfs.NewMemfileReader(filename, body), nil
 	}
 
 	if strings.HasPrefix(ds.BodyPath, "/ipfs") || strings.HasPrefix(ds.BodyPath, "/cafs") || strings.HasPrefix(ds.BodyPath, "/map") {
 		return store.Get(ctx, ds.BodyPath)
 	}
 
+	file, err := os.Open(ds.BodyPath)
+	if err != nil {
+		return nil, fmt.Errorf("body file: %s", err.Error())
+	}
+
+	// compressed bodies are decompressed on read & stored uncompressed, the
+	// filename drops the compression extension so format detection sees the
+	// real format
+	filename, compression := TrimCompressionExt(filepath.Base(ds.BodyPath))
+	body, err := DecompressReader(file, compression)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("body file: %s", err.Error())
+	}
+
 	// convert yaml input to json as a hack to support yaml input for now
-	ext := strings.ToLower(filepath.Ext(ds.BodyPath))
+	ext := strings.ToLower(filepath.Ext(filename))
 	if ext == ".yaml" || ext == ".yml" {
-		yamlBody, err := ioutil.ReadFile(ds.BodyPath)
+		defer file.Close()
+		yamlBody, err := ioutil.ReadAll(body)
 		if err != nil {
 			return nil, fmt.Errorf("body file: %s", err.Error())
 		}
@@ -461,16 +525,21 @@ func DatasetBodyFile(ctx context.Context, store cafs.Filestore, ds *dataset.Data
 			return nil, fmt.Errorf("converting yaml body to json: %s", err.Error())
 		}
 
-		filename := fmt.Sprintf("%s.json", strings.TrimSuffix(filepath.Base(ds.BodyPath), ext))
+		filename = fmt.Sprintf("%s.json", strings.TrimSuffix(filename, filepath.Ext(filename)))
 		return qfs.NewMemfileBytes(filename, jsonBody), nil
 	}
 
-	file, err := os.Open(ds.BodyPath)
-	if err != nil {
-		return nil, fmt.Errorf("body file: %s", err.Error())
-	}
+	filename, body = ndjsonBodyAsJSON(filename, body)
+	return qfs.NewMemfileReader(filename, body), nil
+}
 
-	return qfs.NewMemfileReader(filepath.Base(ds.BodyPath), file), nil
+// ndjsonBodyAsJSON converts bodies with an ndjson filename extension to json
+// arrays, renaming the file so format detection sees json
+func ndjsonBodyAsJSON(filename string, body io.Reader) (string, io.Reader) {
+	if !IsNDJSONFilename(filename) {
+		return filename, body
+	}
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ".json", NDJSONToJSONReader(body)
 }
 
 // ConvertBodyFormat rewrites a body from a source format to a destination format.
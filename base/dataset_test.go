@@ -99,11 +99,11 @@ func TestCreateDataset(t *testing.T) {
 	}
 	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[]")))
 
-	if _, err := CreateDataset(ctx, r, streams, &dataset.Dataset{}, &dataset.Dataset{}, false, true, false, true); err == nil {
+	if _, err := CreateDataset(ctx, r, streams, &dataset.Dataset{}, &dataset.Dataset{}, false, true, false, true, 0); err == nil {
 		t.Error("expected bad dataset to error")
 	}
 
-	ref, err := CreateDataset(ctx, r, streams, ds, &dataset.Dataset{}, false, true, false, true)
+	ref, err := CreateDataset(ctx, r, streams, ds, &dataset.Dataset{}, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
@@ -121,7 +121,7 @@ func TestCreateDataset(t *testing.T) {
 
 	prev := ref.Dataset
 
-	ref, err = CreateDataset(ctx, r, streams, ds, prev, false, true, false, true)
+	ref, err = CreateDataset(ctx, r, streams, ds, prev, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err.Error())
 	}
@@ -137,12 +137,12 @@ func TestCreateDataset(t *testing.T) {
 	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[]")))
 	prev = ref.Dataset
 
-	if ref, err = CreateDataset(ctx, r, streams, ds, prev, false, true, false, true); err == nil {
+	if ref, err = CreateDataset(ctx, r, streams, ds, prev, false, true, false, true, 0); err == nil {
 		t.Error("expected unchanged dataset with no force flag to error")
 	}
 
 	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[]")))
-	if ref, err = CreateDataset(ctx, r, streams, ds, prev, false, true, true, true); err != nil {
+	if ref, err = CreateDataset(ctx, r, streams, ds, prev, false, true, true, true, 0); err != nil {
 		t.Errorf("unexpected force-save error: %s", err)
 	}
 }
@@ -369,7 +369,7 @@ func TestDatasetPinning(t *testing.T) {
 		return
 	}
 
-	ref2, err := CreateDataset(ctx, r, streams, tc.Input, nil, false, false, false, true)
+	ref2, err := CreateDataset(ctx, r, streams, tc.Input, nil, false, false, false, true, 0)
 	if err != nil {
 		t.Error(err.Error())
 		return
@@ -0,0 +1,121 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qri/repo"
+)
+
+// DuplicateMatch selects what content two datasets must share to count as
+// duplicates
+type DuplicateMatch string
+
+const (
+	// DuplicatesByBody matches datasets with the same body
+	DuplicatesByBody = DuplicateMatch("body")
+	// DuplicatesByDataset matches datasets with the same body & components,
+	// ignoring commit details like the title & timestamp
+	DuplicatesByDataset = DuplicateMatch("dataset")
+)
+
+// ParseDuplicateMatch reads a DuplicateMatch from a string. An empty string
+// defaults to DuplicatesByBody
+func ParseDuplicateMatch(s string) (DuplicateMatch, error) {
+	switch s {
+	case "", string(DuplicatesByBody):
+		return DuplicatesByBody, nil
+	case string(DuplicatesByDataset):
+		return DuplicatesByDataset, nil
+	}
+	return "", fmt.Errorf("invalid duplicate match %q, must be one of: body, dataset", s)
+}
+
+// DuplicateCluster is a group of datasets that share content
+type DuplicateCluster struct {
+	// Key is the content the datasets share. the body path when matching by
+	// body, a list of component paths when matching by dataset
+	Key  string            `json:"key"`
+	Refs []repo.DatasetRef `json:"refs"`
+}
+
+// FindDuplicates groups the latest version of each dataset in a repo by
+// content. Content is compared using the paths datasets were stored with,
+// bodies are never re-hashed. Clusters of two or more datasets are returned,
+// largest first
+func FindDuplicates(ctx context.Context, r repo.Repo, match DuplicateMatch) ([]DuplicateCluster, error) {
+	num, err := r.RefCount()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := r.References(0, num)
+	if err != nil {
+		return nil, fmt.Errorf("error getting dataset list: %s", err)
+	}
+
+	groups := map[string][]repo.DatasetRef{}
+	for _, ref := range refs {
+		if ref.Path == "" {
+			continue
+		}
+		ds, err := dsfs.LoadDatasetRefs(ctx, r.Store(), ref.Path)
+		if err != nil {
+			log.Debugf("loading %s: %s", ref.AliasString(), err)
+			return nil, fmt.Errorf("error loading dataset %s: %s", ref.AliasString(), err)
+		}
+		key := duplicateKey(ds, match)
+		if key == "" {
+			continue
+		}
+		if err := repo.CanonicalizeProfile(r, &ref); err != nil {
+			return nil, err
+		}
+		groups[key] = append(groups[key], ref)
+	}
+
+	clusters := []DuplicateCluster{}
+	for key, refs := range groups {
+		if len(refs) < 2 {
+			continue
+		}
+		sort.Slice(refs, func(i, j int) bool { return refs[i].AliasString() < refs[j].AliasString() })
+		clusters = append(clusters, DuplicateCluster{Key: key, Refs: refs})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Refs) != len(clusters[j].Refs) {
+			return len(clusters[i].Refs) > len(clusters[j].Refs)
+		}
+		return clusters[i].Refs[0].AliasString() < clusters[j].Refs[0].AliasString()
+	})
+	return clusters, nil
+}
+
+// duplicateKey returns the content key for a dataset loaded without
+// dereferencing its components. Datasets without a body have no key
+func duplicateKey(ds *dataset.Dataset, match DuplicateMatch) string {
+	if ds.BodyPath == "" {
+		return ""
+	}
+	if match != DuplicatesByDataset {
+		return ds.BodyPath
+	}
+
+	paths := []string{ds.BodyPath, "", "", "", ""}
+	if ds.Structure != nil {
+		paths[1] = ds.Structure.Path
+	}
+	if ds.Meta != nil {
+		paths[2] = ds.Meta.Path
+	}
+	if ds.Transform != nil {
+		paths[3] = ds.Transform.Path
+	}
+	if ds.Viz != nil {
+		paths[4] = ds.Viz.Path
+	}
+	return strings.Join(paths, ",")
+}
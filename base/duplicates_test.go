@@ -0,0 +1,90 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dstest"
+	"github.com/qri-io/ioes"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	addCitiesDataset(t, r)
+	addFlourinatedCompoundsDataset(t, r)
+
+	tc, err := dstest.NewTestCaseFromDir(testdataPath("cities"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveCopy := func(name string, meta *dataset.Meta) {
+		st := &dataset.Structure{}
+		st.Assign(tc.Input.Structure)
+		ds := &dataset.Dataset{
+			Name:      name,
+			Meta:      meta,
+			Structure: st,
+			Commit:    &dataset.Commit{Title: "copy of cities"},
+		}
+		ds.SetBodyFile(tc.BodyFile())
+		if _, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), ds, nil, false, true, false, true, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// same body & components under another name
+	md := &dataset.Meta{}
+	md.Assign(tc.Input.Meta)
+	saveCopy("cities_copy", md)
+	// same body, different meta
+	saveCopy("cities_retitled", &dataset.Meta{Title: "not the same title"})
+
+	clusters, err := FindDuplicates(ctx, r, DuplicatesByBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf("expected 1 body cluster, got: %d", len(clusters))
+	}
+	if names := clusterNames(clusters[0]); names != "cities,cities_copy,cities_retitled" {
+		t.Errorf("body cluster mismatch, got: %s", names)
+	}
+
+	clusters, err = FindDuplicates(ctx, r, DuplicatesByDataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf("expected 1 dataset cluster, got: %d", len(clusters))
+	}
+	if names := clusterNames(clusters[0]); names != "cities,cities_copy" {
+		t.Errorf("dataset cluster mismatch, got: %s", names)
+	}
+}
+
+func TestParseDuplicateMatch(t *testing.T) {
+	for s, expect := range map[string]DuplicateMatch{"": DuplicatesByBody, "body": DuplicatesByBody, "dataset": DuplicatesByDataset} {
+		got, err := ParseDuplicateMatch(s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", s, err)
+		}
+		if got != expect {
+			t.Errorf("%q: expected %q, got %q", s, expect, got)
+		}
+	}
+	if _, err := ParseDuplicateMatch("commit"); err == nil {
+		t.Error("expected invalid match to error")
+	}
+}
+
+func clusterNames(c DuplicateCluster) string {
+	names := ""
+	for i, ref := range c.Refs {
+		if i > 0 {
+			names += ","
+		}
+		names += ref.Name
+	}
+	return names
+}
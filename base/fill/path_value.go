@@ -108,8 +108,18 @@ func findTargetAtPath(steps []string, place reflect.Value) (reflect.Value, strin
 		if place.IsNil() {
 			place.Set(reflect.MakeMap(place.Type()))
 		}
+		// Maps of pointers can be stepped into, allocating missing values.
 		// TODO: Handle case where `rest` has more steps and `val` is a struct: more
 		// recursive is needed.
+		if len(rest) > 0 && place.Type().Elem().Kind() == reflect.Ptr {
+			key := reflect.ValueOf(s)
+			elem := place.MapIndex(key)
+			if !elem.IsValid() || elem.IsNil() {
+				elem = reflect.New(place.Type().Elem().Elem())
+				place.SetMapIndex(key, elem)
+			}
+			return findTargetAtPath(rest, elem)
+		}
 		return place, s, nil
 	} else if place.Kind() == reflect.Slice {
 		num, err := coerceToInt(s)
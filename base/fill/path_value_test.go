@@ -172,6 +172,23 @@ func TestFillPathValue(t *testing.T) {
 		t.Errorf("expected: c.Things[\"frog\"] should be \"ribbit\"")
 	}
 
+	// Map of pointers to structs
+	d := Directory{}
+	err = SetPathValue("entries.a.num", "3", &d)
+	if err != nil {
+		panic(err)
+	}
+	err = SetPathValue("entries.a.things.frog", "ribbit", &d)
+	if err != nil {
+		panic(err)
+	}
+	if d.Entries["a"] == nil || d.Entries["a"].Num != 3 {
+		t.Errorf("expected: d.Entries[\"a\"].Num should be 3")
+	}
+	if (*d.Entries["a"].Things)["frog"] != "ribbit" {
+		t.Errorf("expected: d.Entries[\"a\"].Things[\"frog\"] should be \"ribbit\"")
+	}
+
 	// Error
 	c = Collection{}
 	err = SetPathValue("sub.num", "abc", &c)
@@ -145,6 +145,24 @@ func putValueToPlace(val interface{}, place reflect.Value, collector *ErrorColle
 			// If map is nil, nothing more to do.
 			return
 		}
+		// Maps with struct values fill each value recursively.
+		if elemKind := place.Type().Elem().Kind(); elemKind == reflect.Struct || elemKind == reflect.Ptr {
+			component := toStringMap(val)
+			if component == nil {
+				collector.Add(&FieldError{Want: "map", Got: reflect.TypeOf(val).Name(), Val: val})
+				return
+			}
+			create := reflect.MakeMap(place.Type())
+			for k, v := range component {
+				elem := reflect.Indirect(reflect.New(place.Type().Elem()))
+				collector.PushField(k)
+				putValueToPlace(v, elem, collector)
+				collector.PopField()
+				create.SetMapIndex(reflect.ValueOf(k), elem)
+			}
+			place.Set(create)
+			return
+		}
 		ms, ok := val.(map[string]interface{})
 		if ok {
 			// Special case map[string]string, convert values to strings.
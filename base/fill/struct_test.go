@@ -775,6 +775,46 @@ func TestFillPointerToMap(t *testing.T) {
 	}
 }
 
+type Directory struct {
+	Entries map[string]*SubElement
+}
+
+func TestFillMapOfPointers(t *testing.T) {
+	jsonData := `{
+  "Entries": {
+    "a": {"Num": 1, "Things": {"frog": "ribbit"}},
+    "b": {"Num": 2}
+  }
+}`
+
+	data := make(map[string]interface{})
+	err := json.Unmarshal([]byte(jsonData), &data)
+	if err != nil {
+		panic(err)
+	}
+
+	var d Directory
+	err = Struct(data, &d)
+	if err != nil {
+		panic(err)
+	}
+
+	if len(d.Entries) != 2 {
+		t.Fatalf("expected: 2 entries, got: %d", len(d.Entries))
+	}
+	if d.Entries["a"].Num != 1 || (*d.Entries["a"].Things)["frog"] != "ribbit" {
+		t.Errorf("expected: d.Entries[\"a\"] should be filled, got: %v", d.Entries["a"])
+	}
+	if d.Entries["b"].Num != 2 {
+		t.Errorf("expected: d.Entries[\"b\"].Num should be 2, got: %d", d.Entries["b"].Num)
+	}
+
+	data = map[string]interface{}{"Entries": map[string]interface{}{"a": "apple"}}
+	if err = Struct(data, &Directory{}); err == nil {
+		t.Error("expected: filling a struct value from a string should error")
+	}
+}
+
 func TestFillInterface(t *testing.T) {
 	// Any can be a float
 	jsonData := `{
@@ -0,0 +1,92 @@
+package base
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qri/repo"
+)
+
+// ErrNoGC is returned when garbage collecting a store that can't remove
+// stored content
+var ErrNoGC = fmt.Errorf("store doesn't support garbage collection")
+
+// GarbageCollector is an optional interface for stores that can remove
+// content nothing refers to. CollectGarbage deletes everything that isn't
+// reachable from a key in keep or pinned, returning the number of bytes
+// reclaimed
+type GarbageCollector interface {
+	CollectGarbage(ctx context.Context, keep []string) (reclaimed int64, err error)
+}
+
+// HasFunc reports if content is stored locally
+type HasFunc func(ctx context.Context, path string) (bool, error)
+
+// GCRoots lists store paths that garbage collection must keep: every dataset
+// version in the history of a reference in the repo, and files attached to
+// those versions. Histories are followed until has reports a version isn't
+// stored locally, so collection never reaches out to the network for
+// versions the repo doesn't hold. When latestOnly is true only the latest
+// version of each reference is kept, matching the "latest" pin policy
+func GCRoots(ctx context.Context, r repo.Repo, has HasFunc, latestOnly bool) ([]string, error) {
+	num, err := r.RefCount()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := r.References(0, num)
+	if err != nil {
+		return nil, fmt.Errorf("error getting dataset list: %s", err)
+	}
+
+	seen := map[string]bool{}
+	roots := []string{}
+	for _, ref := range refs {
+		path := ref.Path
+		for path != "" && !seen[path] {
+			seen[path] = true
+			if local, err := has(ctx, path); err != nil {
+				return nil, err
+			} else if !local {
+				log.Debugf("%s version %s isn't stored locally, skipping the rest of its history", ref.AliasString(), path)
+				break
+			}
+			roots = append(roots, path)
+
+			ds, err := dsfs.LoadDataset(ctx, r.Store(), path)
+			if err != nil {
+				log.Debugf("loading %s version %s: %s", ref.AliasString(), path, err.Error())
+				break
+			}
+			for _, attached := range Attachments(ds) {
+				roots = append(roots, attached)
+			}
+			if latestOnly {
+				break
+			}
+			path = ds.PreviousPath
+		}
+	}
+	return roots, nil
+}
+
+// GC removes content from the repo store that isn't part of a dataset
+// version in the history of a reference & isn't pinned, returning the number
+// of bytes reclaimed. When latestOnly is true, versions before the latest
+// are only kept if they're pinned. Stores that can't remove content return
+// ErrNoGC
+func GC(ctx context.Context, r repo.Repo, latestOnly bool) (reclaimed int64, err error) {
+	gc, ok := r.Store().(GarbageCollector)
+	if !ok {
+		return 0, ErrNoGC
+	}
+	if err = StoreWritable(r.Store()); err != nil {
+		return 0, err
+	}
+
+	keep, err := GCRoots(ctx, r, r.Store().Has, latestOnly)
+	if err != nil {
+		return 0, err
+	}
+	return gc.CollectGarbage(ctx, keep)
+}
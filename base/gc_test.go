@@ -0,0 +1,59 @@
+package base
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGCRoots(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	first := addCitiesDataset(t, r)
+	second := updateCitiesDataset(t, r)
+
+	roots, err := GCRoots(ctx, r, r.Store().Has, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, path := range roots {
+		got[path] = true
+	}
+	for _, path := range []string{first.Path, second.Path} {
+		if !got[path] {
+			t.Errorf("expected roots to include version %s. got: %v", path, roots)
+		}
+	}
+
+	// versions that aren't stored locally end the walk through history
+	headOnly := func(ctx context.Context, path string) (bool, error) {
+		return path == second.Path, nil
+	}
+	if roots, err = GCRoots(ctx, r, headOnly, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range roots {
+		if path == first.Path {
+			t.Errorf("expected roots to skip version %s that isn't stored locally", first.Path)
+		}
+	}
+
+	// latest-only roots stop at the head of each history
+	if roots, err = GCRoots(ctx, r, r.Store().Has, true); err != nil {
+		t.Fatal(err)
+	}
+	got = map[string]bool{}
+	for _, path := range roots {
+		got[path] = true
+	}
+	if !got[second.Path] || got[first.Path] {
+		t.Errorf("expected latest-only roots to include only version %s. got: %v", second.Path, roots)
+	}
+}
+
+func TestGCUnsupportedStore(t *testing.T) {
+	r := newTestRepo(t)
+	if _, err := GC(context.Background(), r, false); err != ErrNoGC {
+		t.Errorf("expected map store to return ErrNoGC, got: %v", err)
+	}
+}
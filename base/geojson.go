@@ -0,0 +1,319 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/qri-io/dataset"
+)
+
+// ErrNoGeometry indicates a dataset body has no columns geometry can be read from
+var ErrNoGeometry = fmt.Errorf("dataset has no identifiable geometry: expected latitude & longitude columns or a WKT geometry column")
+
+var (
+	geoLatColumns = map[string]bool{"lat": true, "latitude": true}
+	geoLonColumns = map[string]bool{"lon": true, "lng": true, "long": true, "longitude": true}
+	geoWKTColumns = map[string]bool{"wkt": true, "geometry": true, "geom": true, "the_geom": true}
+)
+
+// GeoJSONFeatureCollection is a GeoJSON FeatureCollection object
+type GeoJSONFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []*GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a GeoJSON Feature object
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   *GeoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSONGeometry is a GeoJSON geometry object
+type GeoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// geoColumns records the columns geometry is read from
+type geoColumns struct {
+	lat, lon, wkt string
+}
+
+func (gc geoColumns) isGeometry(col string) bool {
+	return col == gc.lat || col == gc.lon || col == gc.wkt
+}
+
+// findGeoColumns picks geometry columns from a list of column names,
+// preferring latitude & longitude columns over a WKT column
+func findGeoColumns(names []string) (gc geoColumns, err error) {
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		switch {
+		case geoLatColumns[lower] && gc.lat == "":
+			gc.lat = name
+		case geoLonColumns[lower] && gc.lon == "":
+			gc.lon = name
+		case geoWKTColumns[lower] && gc.wkt == "":
+			gc.wkt = name
+		}
+	}
+	if gc.lat != "" && gc.lon != "" {
+		gc.wkt = ""
+		return gc, nil
+	}
+	if gc.wkt != "" {
+		gc.lat, gc.lon = "", ""
+		return gc, nil
+	}
+	return gc, ErrNoGeometry
+}
+
+// SchemaColumnNames returns the titles of columns in a tabular schema, or the
+// property names of an object-row schema
+func SchemaColumnNames(st *dataset.Structure) []string {
+	if st == nil || st.Schema == nil {
+		return nil
+	}
+	items, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var names []string
+	if cols, ok := items["items"].([]interface{}); ok {
+		for _, col := range cols {
+			c, _ := col.(map[string]interface{})
+			title, _ := c["title"].(string)
+			names = append(names, title)
+		}
+	} else if props, ok := items["properties"].(map[string]interface{}); ok {
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	return names
+}
+
+// ConvertJSONBodyToGeoJSON converts a JSON array body into a GeoJSON
+// FeatureCollection, mapping each row to a feature. Geometry is read from
+// latitude & longitude columns or a WKT geometry column, all other columns
+// become feature properties. Rows with missing or unparsable geometry produce
+// features with null geometry. Returns ErrNoGeometry when no geometry columns
+// can be identified
+func ConvertJSONBodyToGeoJSON(st *dataset.Structure, body []byte) ([]byte, error) {
+	rows := []interface{}{}
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("geojson conversion requires a body that is an array of rows: %s", err)
+	}
+
+	names := SchemaColumnNames(st)
+	if len(names) == 0 && len(rows) > 0 {
+		if obj, ok := rows[0].(map[string]interface{}); ok {
+			for name := range obj {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+		}
+	}
+	gc, err := findGeoColumns(names)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]*GeoJSONFeature, 0, len(rows))}
+	for i, row := range rows {
+		values := map[string]interface{}{}
+		switch r := row.(type) {
+		case []interface{}:
+			for j, v := range r {
+				if j < len(names) {
+					values[names[j]] = v
+				}
+			}
+		case map[string]interface{}:
+			values = r
+		default:
+			return nil, fmt.Errorf("row %d: expected an array or object, got %T", i, row)
+		}
+
+		f := &GeoJSONFeature{Type: "Feature", Properties: map[string]interface{}{}}
+		for name, v := range values {
+			if !gc.isGeometry(name) {
+				f.Properties[name] = v
+			}
+		}
+		if gc.wkt != "" {
+			if s, ok := values[gc.wkt].(string); ok {
+				if f.Geometry, err = ParseWKT(s); err != nil {
+					log.Debugf("row %d: %s", i, err)
+				}
+			}
+		} else {
+			lat, latOk := geoFloat(values[gc.lat])
+			lon, lonOk := geoFloat(values[gc.lon])
+			if latOk && lonOk {
+				f.Geometry = &GeoJSONGeometry{Type: "Point", Coordinates: []float64{lon, lat}}
+			}
+		}
+		fc.Features = append(fc.Features, f)
+	}
+
+	return json.Marshal(fc)
+}
+
+func geoFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// wktTypes maps upper-case WKT geometry types to GeoJSON geometry types
+var wktTypes = map[string]string{
+	"POINT":           "Point",
+	"LINESTRING":      "LineString",
+	"POLYGON":         "Polygon",
+	"MULTIPOINT":      "MultiPoint",
+	"MULTILINESTRING": "MultiLineString",
+	"MULTIPOLYGON":    "MultiPolygon",
+}
+
+// ParseWKT parses a well-known-text geometry into a GeoJSON geometry.
+// EMPTY geometries return a nil geometry. GEOMETRYCOLLECTION isn't supported
+func ParseWKT(s string) (*GeoJSONGeometry, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return r == '(' || unicode.IsSpace(r) })
+	if i < 0 {
+		i = len(s)
+	}
+	wktType := strings.ToUpper(s[:i])
+	geoType, ok := wktTypes[wktType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported WKT geometry type: %q", s[:i])
+	}
+
+	rest := strings.TrimSpace(s[i:])
+	// drop dimension markers, GeoJSON positions carry their own dimensions
+	for _, dim := range []string{"ZM", "Z", "M"} {
+		if strings.HasPrefix(strings.ToUpper(rest), dim) {
+			rest = strings.TrimSpace(rest[len(dim):])
+			break
+		}
+	}
+	if strings.ToUpper(rest) == "EMPTY" {
+		return nil, nil
+	}
+
+	p := &wktParser{s: rest}
+	coords, err := p.parseList()
+	if err != nil {
+		return nil, fmt.Errorf("invalid WKT %s: %s", wktType, err)
+	}
+	if p.skipSpace(); p.pos != len(p.s) {
+		return nil, fmt.Errorf("invalid WKT %s: unexpected trailing text %q", wktType, p.s[p.pos:])
+	}
+
+	switch geoType {
+	case "Point":
+		if len(coords) != 1 {
+			return nil, fmt.Errorf("invalid WKT POINT: expected a single position")
+		}
+		return &GeoJSONGeometry{Type: geoType, Coordinates: coords[0]}, nil
+	case "MultiPoint":
+		// MULTIPOINT allows positions with or without wrapping parens
+		for i, c := range coords {
+			if l, ok := c.([]interface{}); ok && len(l) == 1 {
+				coords[i] = l[0]
+			}
+		}
+	}
+	return &GeoJSONGeometry{Type: geoType, Coordinates: coords}, nil
+}
+
+// wktParser reads nested, parenthesized WKT coordinate lists
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// parseList reads a parenthesized, comma-separated list whose elements are
+// either nested lists or positions
+func (p *wktParser) parseList() ([]interface{}, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '(' {
+		return nil, fmt.Errorf("expected '(' at position %d", p.pos)
+	}
+	p.pos++
+
+	var list []interface{}
+	for {
+		p.skipSpace()
+		if p.pos < len(p.s) && p.s[p.pos] == '(' {
+			l, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, l)
+		} else {
+			pos, err := p.parsePosition()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, pos)
+		}
+
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("unexpected end of input")
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return list, nil
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", p.s[p.pos], p.pos)
+		}
+	}
+}
+
+// parsePosition reads space-separated numbers up to the next ',' or ')'
+func (p *wktParser) parsePosition() ([]float64, error) {
+	end := strings.IndexAny(p.s[p.pos:], ",)")
+	if end < 0 {
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+	fields := strings.Fields(p.s[p.pos : p.pos+end])
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("position at %d needs at least two coordinates", p.pos)
+	}
+	pos := make([]float64, len(fields))
+	for i, f := range fields {
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coordinate %q", f)
+		}
+		pos[i] = n
+	}
+	p.pos += end
+	return pos, nil
+}
@@ -0,0 +1,119 @@
+package base
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestParseWKT(t *testing.T) {
+	cases := []struct {
+		wkt    string
+		expect string
+	}{
+		{"POINT (30 10)", `{"type":"Point","coordinates":[30,10]}`},
+		{"point(30 10 5)", `{"type":"Point","coordinates":[30,10,5]}`},
+		{"POINT Z (30 10 5)", `{"type":"Point","coordinates":[30,10,5]}`},
+		{"LINESTRING (30 10, 10 30, 40 40)", `{"type":"LineString","coordinates":[[30,10],[10,30],[40,40]]}`},
+		{"POLYGON ((30 10, 40 40, 20 40, 30 10))", `{"type":"Polygon","coordinates":[[[30,10],[40,40],[20,40],[30,10]]]}`},
+		{"MULTIPOINT ((10 40), (40 30))", `{"type":"MultiPoint","coordinates":[[10,40],[40,30]]}`},
+		{"MULTIPOINT (10 40, 40 30)", `{"type":"MultiPoint","coordinates":[[10,40],[40,30]]}`},
+		{"MULTIPOLYGON (((30 20, 45 40, 10 40, 30 20)))", `{"type":"MultiPolygon","coordinates":[[[[30,20],[45,40],[10,40],[30,20]]]]}`},
+		{"POINT EMPTY", `null`},
+	}
+	for _, c := range cases {
+		geom, err := ParseWKT(c.wkt)
+		if err != nil {
+			t.Errorf("case %q unexpected error: %s", c.wkt, err)
+			continue
+		}
+		data, err := json.Marshal(geom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.expect {
+			t.Errorf("case %q mismatch. want: %s, got: %s", c.wkt, c.expect, string(data))
+		}
+	}
+
+	bad := []string{
+		"CIRCLE (1 2)",
+		"POINT (1)",
+		"POINT (1 2, 3 4)",
+		"LINESTRING (1 2, 3 4",
+		"POINT (1 2) extra",
+		"POINT (a b)",
+	}
+	for _, s := range bad {
+		if _, err := ParseWKT(s); err == nil {
+			t.Errorf("expected %q to error", s)
+		}
+	}
+}
+
+func TestConvertJSONBodyToGeoJSON(t *testing.T) {
+	tabular := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name"},
+					map[string]interface{}{"title": "Latitude"},
+					map[string]interface{}{"title": "Longitude"},
+				},
+			},
+		},
+	}
+	wktSt := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name"},
+					map[string]interface{}{"title": "the_geom"},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		description string
+		st          *dataset.Structure
+		body        string
+		expect      string
+	}{
+		{"tabular lat lon", tabular, `[["a",10,20],["b","1.5","2.5"],["c","",""]]`,
+			`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[20,10]},"properties":{"name":"a"}},{"type":"Feature","geometry":{"type":"Point","coordinates":[2.5,1.5]},"properties":{"name":"b"}},{"type":"Feature","geometry":null,"properties":{"name":"c"}}]}`},
+		{"tabular wkt", wktSt, `[["a","POINT (1 2)"],["b","not wkt"]]`,
+			`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}},{"type":"Feature","geometry":null,"properties":{"name":"b"}}]}`},
+		{"object rows without schema", nil, `[{"lng":1,"lat":2,"n":3}]`,
+			`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"n":3}}]}`},
+		{"empty body", tabular, `[]`, `{"type":"FeatureCollection","features":[]}`},
+	}
+	for _, c := range cases {
+		got, err := ConvertJSONBodyToGeoJSON(c.st, []byte(c.body))
+		if err != nil {
+			t.Errorf("case %q unexpected error: %s", c.description, err)
+			continue
+		}
+		var a, b interface{}
+		json.Unmarshal(got, &a)
+		json.Unmarshal([]byte(c.expect), &b)
+		if !reflect.DeepEqual(a, b) {
+			t.Errorf("case %q mismatch.\nwant: %s\ngot:  %s", c.description, c.expect, string(got))
+		}
+	}
+
+	if _, err := ConvertJSONBodyToGeoJSON(nil, []byte(`[{"city":"toronto"}]`)); err != ErrNoGeometry {
+		t.Errorf("expected ErrNoGeometry, got: %v", err)
+	}
+	if _, err := ConvertJSONBodyToGeoJSON(nil, []byte(`{"a":1}`)); err == nil {
+		t.Error("expected object body to error")
+	}
+}
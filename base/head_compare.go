@@ -0,0 +1,73 @@
+package base
+
+import (
+	"context"
+
+	"github.com/qri-io/qri/repo"
+)
+
+const (
+	// HeadsMatch indicates local & remote heads are the same version
+	HeadsMatch = "match"
+	// HeadLocalAhead indicates the local history contains the remote head,
+	// and has newer versions
+	HeadLocalAhead = "local-ahead"
+	// HeadRemoteAhead indicates the remote head isn't in local history, the
+	// remote has versions that haven't been pulled
+	HeadRemoteAhead = "remote-ahead"
+	// HeadLocalMissing indicates the dataset only exists on the remote
+	HeadLocalMissing = "local-missing"
+	// HeadRemoteMissing indicates the dataset only exists locally
+	HeadRemoteMissing = "remote-missing"
+)
+
+// HeadComparison describes how a local dataset head relates to the head of the
+// same dataset on a remote
+type HeadComparison struct {
+	Ref        string `json:"ref"`
+	LocalPath  string `json:"localPath,omitempty"`
+	RemotePath string `json:"remotePath,omitempty"`
+	Status     string `json:"status"`
+}
+
+// Match returns true if local & remote heads are the same version
+func (hc HeadComparison) Match() bool {
+	return hc.Status == HeadsMatch
+}
+
+// CompareHeads compares the local head of a dataset to a remote head. a nil
+// local or remote reference means the dataset doesn't exist on that side.
+// It's an error for both references to be nil
+func CompareHeads(ctx context.Context, r repo.Repo, local, remote *repo.DatasetRef) (*HeadComparison, error) {
+	switch {
+	case local == nil && remote == nil:
+		return nil, repo.ErrNotFound
+	case local == nil:
+		return &HeadComparison{Ref: remote.AliasString(), RemotePath: remote.Path, Status: HeadLocalMissing}, nil
+	case remote == nil:
+		return &HeadComparison{Ref: local.AliasString(), LocalPath: local.Path, Status: HeadRemoteMissing}, nil
+	}
+
+	hc := &HeadComparison{
+		Ref:        local.AliasString(),
+		LocalPath:  local.Path,
+		RemotePath: remote.Path,
+		Status:     HeadRemoteAhead,
+	}
+	if local.Path == remote.Path {
+		hc.Status = HeadsMatch
+		return hc, nil
+	}
+
+	versions, err := DatasetHistory(ctx, r, *local, -1, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Path == remote.Path {
+			hc.Status = HeadLocalAhead
+			break
+		}
+	}
+	return hc, nil
+}
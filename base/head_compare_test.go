@@ -0,0 +1,46 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/qri/repo"
+)
+
+func TestCompareHeads(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	first := addCitiesDataset(t, r)
+	head := updateCitiesDataset(t, r)
+	other := &repo.DatasetRef{Peername: head.Peername, Name: head.Name, Path: "/map/QmNotInHistory"}
+
+	cases := []struct {
+		description   string
+		local, remote *repo.DatasetRef
+		status        string
+	}{
+		{"same head", &head, &head, HeadsMatch},
+		{"remote has an older version", &head, &first, HeadLocalAhead},
+		{"local has an older version", &first, &head, HeadRemoteAhead},
+		{"remote head unknown locally", &head, other, HeadRemoteAhead},
+		{"missing locally", nil, &head, HeadLocalMissing},
+		{"missing on remote", &head, nil, HeadRemoteMissing},
+	}
+	for _, c := range cases {
+		got, err := CompareHeads(ctx, r, c.local, c.remote)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.description, err)
+			continue
+		}
+		if got.Status != c.status {
+			t.Errorf("%s: status mismatch. expected: %q, got: %q", c.description, c.status, got.Status)
+		}
+		if got.Ref != "peer/cities" {
+			t.Errorf("%s: expected ref alias. got: %q", c.description, got.Ref)
+		}
+	}
+
+	if _, err := CompareHeads(ctx, r, nil, nil); err != repo.ErrNotFound {
+		t.Errorf("expected comparing two missing refs to return ErrNotFound. got: %v", err)
+	}
+}
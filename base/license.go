@@ -0,0 +1,62 @@
+package base
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// Licenses maps recognized SPDX license identifiers to their full names.
+// see https://spdx.org/licenses/ for the complete list
+var Licenses = map[string]string{
+	"0BSD":                "BSD Zero Clause License",
+	"AGPL-3.0-only":       "GNU Affero General Public License v3.0 only",
+	"AGPL-3.0-or-later":   "GNU Affero General Public License v3.0 or later",
+	"Apache-2.0":          "Apache License 2.0",
+	"BSD-2-Clause":        "BSD 2-Clause \"Simplified\" License",
+	"BSD-3-Clause":        "BSD 3-Clause \"New\" or \"Revised\" License",
+	"CC0-1.0":             "Creative Commons Zero v1.0 Universal",
+	"CC-BY-3.0":           "Creative Commons Attribution 3.0 Unported",
+	"CC-BY-4.0":           "Creative Commons Attribution 4.0 International",
+	"CC-BY-NC-4.0":        "Creative Commons Attribution Non Commercial 4.0 International",
+	"CC-BY-NC-SA-4.0":     "Creative Commons Attribution Non Commercial Share Alike 4.0 International",
+	"CC-BY-ND-4.0":        "Creative Commons Attribution No Derivatives 4.0 International",
+	"CC-BY-SA-3.0":        "Creative Commons Attribution Share Alike 3.0 Unported",
+	"CC-BY-SA-4.0":        "Creative Commons Attribution Share Alike 4.0 International",
+	"CDLA-Permissive-1.0": "Community Data License Agreement Permissive 1.0",
+	"CDLA-Sharing-1.0":    "Community Data License Agreement Sharing 1.0",
+	"GPL-2.0-only":        "GNU General Public License v2.0 only",
+	"GPL-2.0-or-later":    "GNU General Public License v2.0 or later",
+	"GPL-3.0-only":        "GNU General Public License v3.0 only",
+	"GPL-3.0-or-later":    "GNU General Public License v3.0 or later",
+	"LGPL-2.1-only":       "GNU Lesser General Public License v2.1 only",
+	"LGPL-3.0-only":       "GNU Lesser General Public License v3.0 only",
+	"MIT":                 "MIT License",
+	"MPL-2.0":             "Mozilla Public License 2.0",
+	"ODbL-1.0":            "Open Data Commons Open Database License v1.0",
+	"ODC-By-1.0":          "Open Data Commons Attribution License v1.0",
+	"OGL-UK-3.0":          "Open Government Licence v3.0",
+	"PDDL-1.0":            "Open Data Commons Public Domain Dedication & License 1.0",
+	"Unlicense":           "The Unlicense",
+}
+
+// ValidLicense checks id is a recognized SPDX license identifier, returning
+// the identifier in its canonical case
+func ValidLicense(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", fmt.Errorf("license is required")
+	}
+	for known := range Licenses {
+		if strings.EqualFold(known, id) {
+			return known, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized license %q. license must be a known SPDX identifier like \"CC-BY-4.0\" or \"MIT\"", id)
+}
+
+// HasLicense returns true if a dataset's metadata records a license
+func HasLicense(ds *dataset.Dataset) bool {
+	return ds != nil && ds.Meta != nil && ds.Meta.License != nil && ds.Meta.License.Type != ""
+}
@@ -0,0 +1,58 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestValidLicense(t *testing.T) {
+	good := []struct {
+		in, expect string
+	}{
+		{"MIT", "MIT"},
+		{"cc-by-4.0", "CC-BY-4.0"},
+		{" Apache-2.0 ", "Apache-2.0"},
+	}
+	for _, c := range good {
+		got, err := ValidLicense(c.in)
+		if err != nil {
+			t.Errorf("%q unexpected error: %s", c.in, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%q expected: %q, got: %q", c.in, c.expect, got)
+		}
+	}
+
+	bad := []struct {
+		in, err string
+	}{
+		{"", "license is required"},
+		{"not-a-license", `unrecognized license "not-a-license". license must be a known SPDX identifier like "CC-BY-4.0" or "MIT"`},
+	}
+	for _, c := range bad {
+		_, err := ValidLicense(c.in)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("%q expected error: %q, got: %v", c.in, c.err, err)
+		}
+	}
+}
+
+func TestHasLicense(t *testing.T) {
+	cases := []struct {
+		ds     *dataset.Dataset
+		expect bool
+	}{
+		{nil, false},
+		{&dataset.Dataset{}, false},
+		{&dataset.Dataset{Meta: &dataset.Meta{}}, false},
+		{&dataset.Dataset{Meta: &dataset.Meta{License: &dataset.License{URL: "https://example.com"}}}, false},
+		{&dataset.Dataset{Meta: &dataset.Meta{License: &dataset.License{Type: "MIT"}}}, true},
+	}
+	for i, c := range cases {
+		if got := HasLicense(c.ds); got != c.expect {
+			t.Errorf("case %d expected: %t, got: %t", i, c.expect, got)
+		}
+	}
+}
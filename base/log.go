@@ -67,6 +67,42 @@ func DatasetLog(ctx context.Context, r repo.Repo, ref repo.DatasetRef, limit, of
 	}
 }
 
+// DatasetHistory walks the history of a dataset, following previous paths
+// from ref. Unlike DatasetLog it isn't capped by a timeout: it returns the
+// requested history in full, or an error if any version can't be loaded.
+// a limit of zero or less walks to the first version. use ctx to bound the
+// time spent resolving versions that aren't stored locally
+func DatasetHistory(ctx context.Context, r repo.Repo, ref repo.DatasetRef, limit, offset int, loadDatasets bool) (rlog []repo.DatasetRef, err error) {
+	for {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var ds *dataset.Dataset
+		if loadDatasets {
+			ds, err = dsfs.LoadDataset(ctx, r.Store(), ref.Path)
+		} else {
+			ds, err = dsfs.LoadDatasetRefs(ctx, r.Store(), ref.Path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("loading dataset version %s: %s", ref.Path, err)
+		}
+		ref.Dataset = ds
+
+		if offset <= 0 {
+			rlog = append(rlog, ref)
+			if limit--; limit == 0 {
+				return rlog, nil
+			}
+		}
+		if ds.PreviousPath == "" {
+			return rlog, nil
+		}
+		ref.Path = ds.PreviousPath
+		offset--
+	}
+}
+
 // LogDiffResult is the result of comparing a set of references
 type LogDiffResult struct {
 	Head        repo.DatasetRef
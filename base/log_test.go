@@ -38,6 +38,45 @@ func TestDatasetLog(t *testing.T) {
 	}
 }
 
+func TestDatasetHistory(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	addCitiesDataset(t, r)
+	head := updateCitiesDataset(t, r)
+
+	history, err := DatasetHistory(ctx, r, head, -1, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history length mismatch. expected: %d, got: %d", 2, len(history))
+	}
+	if history[0].Path != head.Path {
+		t.Errorf("expected history to start at head. got: %s", history[0].Path)
+	}
+
+	prev := history[1].Path
+	history, err = DatasetHistory(ctx, r, head, 1, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Path != prev {
+		t.Errorf("expected offset history to contain the previous version. got: %v", history)
+	}
+
+	missing := head
+	missing.Path = "/map/QmMissing"
+	if _, err := DatasetHistory(ctx, r, missing, -1, 0, false); err == nil {
+		t.Error("expected history of a missing version to error")
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := DatasetHistory(canceled, r, head, -1, 0, false); err != context.Canceled {
+		t.Errorf("expected canceled context to error. got: %v", err)
+	}
+}
+
 func TestLogDiff(t *testing.T) {
 	ctx := context.Background()
 	r := newTestRepo(t)
@@ -0,0 +1,37 @@
+package base
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/qri/repo"
+)
+
+// MissingRefs lists references in a repo whose dataset version isn't in the
+// repo store. references without a path, like datasets linked to a directory
+// that have never been saved, aren't checked
+func MissingRefs(ctx context.Context, r repo.Repo) ([]repo.DatasetRef, error) {
+	num, err := r.RefCount()
+	if err != nil {
+		return nil, err
+	}
+	refs, err := r.References(0, num)
+	if err != nil {
+		return nil, fmt.Errorf("error getting dataset list: %s", err)
+	}
+
+	missing := []repo.DatasetRef{}
+	for _, ref := range refs {
+		if ref.Path == "" {
+			continue
+		}
+		has, err := r.Store().Has(ctx, ref.Path)
+		if err != nil {
+			return nil, fmt.Errorf("checking store for %s: %s", ref.AliasString(), err)
+		}
+		if !has {
+			missing = append(missing, ref)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,37 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/qri/repo"
+)
+
+func TestMissingRefs(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	ref := addCitiesDataset(t, r)
+
+	missing, err := MissingRefs(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing refs. got: %v", missing)
+	}
+
+	gone := repo.DatasetRef{Peername: ref.Peername, ProfileID: ref.ProfileID, Name: "gone", Path: "/map/QmNotInTheStore"}
+	linked := repo.DatasetRef{Peername: ref.Peername, ProfileID: ref.ProfileID, Name: "linked", FSIPath: "/path/to/linked"}
+	for _, r2 := range []repo.DatasetRef{gone, linked} {
+		if err := r.PutRef(r2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if missing, err = MissingRefs(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0].Name != "gone" {
+		t.Errorf("expected only ref 'gone' to be missing. got: %v", missing)
+	}
+}
@@ -0,0 +1,136 @@
+package base
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/qri-io/dataset/dsio"
+)
+
+// NDJSONFormat is a body format with one JSON value per line, also known as
+// JSON Lines. ndjson bodies are stored as json arrays, each line becoming one
+// entry, so a dataset's schema describes the shape of a single record
+const NDJSONFormat = "ndjson"
+
+// IsNDJSONFormat checks if a format string names newline-delimited json,
+// accepting both "ndjson" & "jsonl"
+func IsNDJSONFormat(format string) bool {
+	format = strings.ToLower(format)
+	return format == NDJSONFormat || format == "jsonl"
+}
+
+// IsNDJSONFilename checks a filename for an ndjson extension
+func IsNDJSONFilename(filename string) bool {
+	return IsNDJSONFormat(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+// NDJSONToJSONReader converts newline-delimited json into a json array as
+// it's read, without reading the whole body into memory. Blank lines are
+// skipped. Lines that aren't valid json produce a read error naming the line.
+// if r is an io.Closer, closing the returned reader closes r
+func NDJSONToJSONReader(r io.Reader) io.ReadCloser {
+	return &ndjsonArrayReader{src: r, br: bufio.NewReader(r)}
+}
+
+type ndjsonArrayReader struct {
+	src     io.Reader
+	br      *bufio.Reader
+	buf     bytes.Buffer
+	line    int
+	entries int
+	started bool
+	done    bool
+	err     error
+}
+
+func (nr *ndjsonArrayReader) Read(p []byte) (int, error) {
+	for nr.buf.Len() == 0 && !nr.done && nr.err == nil {
+		nr.err = nr.fill()
+	}
+	if nr.buf.Len() > 0 {
+		return nr.buf.Read(p)
+	}
+	if nr.err != nil {
+		return 0, nr.err
+	}
+	return 0, io.EOF
+}
+
+// fill reads one line from the source into the output buffer
+func (nr *ndjsonArrayReader) fill() error {
+	if !nr.started {
+		nr.started = true
+		nr.buf.WriteByte('[')
+	}
+
+	line, err := nr.br.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	nr.line++
+
+	if line = bytes.TrimSpace(line); len(line) > 0 {
+		if !json.Valid(line) {
+			return fmt.Errorf("reading ndjson body: line %d is not valid json", nr.line)
+		}
+		if nr.entries > 0 {
+			nr.buf.WriteByte(',')
+		}
+		nr.buf.Write(line)
+		nr.entries++
+	}
+
+	if err == io.EOF {
+		nr.buf.WriteByte(']')
+		nr.done = true
+	}
+	return nil
+}
+
+func (nr *ndjsonArrayReader) Close() error {
+	if closer, ok := nr.src.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// ConvertJSONBodyToNDJSON converts a JSON array body into newline-delimited
+// json, writing one array element per line
+func ConvertJSONBodyToNDJSON(body []byte) ([]byte, error) {
+	rows := []json.RawMessage{}
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("ndjson conversion requires a body that is an array of rows: %s", err)
+	}
+
+	buf := &bytes.Buffer{}
+	for _, row := range rows {
+		if err := json.Compact(buf, row); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteNDJSON writes each entry of a body to w as a line of json. entries of
+// object bodies are written without their keys
+func WriteNDJSON(r dsio.EntryReader, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for {
+		ent, err := r.ReadEntry()
+		if err != nil {
+			if err.Error() == "EOF" {
+				return nil
+			}
+			return err
+		}
+		if err := enc.Encode(ent.Value); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package base
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestNDJSONToJSONReader(t *testing.T) {
+	cases := []struct {
+		ndjson, expect string
+	}{
+		{"", `[]`},
+		{"{\"a\":1}", `[{"a":1}]`},
+		{"{\"a\":1}\n{\"a\":2}\n", `[{"a":1},{"a":2}]`},
+		{"{\"a\":1}\r\n\n  [1,2]  \n\"str\"\n", `[{"a":1},[1,2],"str"]`},
+	}
+	for i, c := range cases {
+		got, err := ioutil.ReadAll(NDJSONToJSONReader(strings.NewReader(c.ndjson)))
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if string(got) != c.expect {
+			t.Errorf("case %d mismatch. want: %s, got: %s", i, c.expect, string(got))
+		}
+	}
+
+	_, err := ioutil.ReadAll(NDJSONToJSONReader(strings.NewReader("{\"a\":1}\n{\"a\":\n")))
+	if expect := "reading ndjson body: line 2 is not valid json"; err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. want: %q, got: %v", expect, err)
+	}
+}
+
+func TestIsNDJSONFilename(t *testing.T) {
+	for name, expect := range map[string]bool{
+		"events.ndjson": true,
+		"events.JSONL":  true,
+		"events.json":   false,
+		"ndjson":        false,
+	} {
+		if got := IsNDJSONFilename(name); got != expect {
+			t.Errorf("%q: expected %t, got %t", name, expect, got)
+		}
+	}
+}
+
+func TestConvertJSONBodyToNDJSON(t *testing.T) {
+	got, err := ConvertJSONBodyToNDJSON([]byte(`[{"a": 1}, [1, 2], "str"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "{\"a\":1}\n[1,2]\n\"str\"\n"; string(got) != expect {
+		t.Errorf("mismatch. want: %q, got: %q", expect, string(got))
+	}
+
+	if _, err := ConvertJSONBodyToNDJSON([]byte(`{"a":1}`)); err == nil {
+		t.Error("expected converting an object body to error")
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	r, err := dsio.NewEntryReader(st, strings.NewReader(`[{"a":1},["b",2]]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := WriteNDJSON(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "{\"a\":1}\n[\"b\",2]\n"; buf.String() != expect {
+		t.Errorf("mismatch. want: %q, got: %q", expect, buf.String())
+	}
+}
@@ -0,0 +1,178 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+const (
+	// PackageFileBodyIndex is the name of the body index file in the directory
+	// of a dataset version
+	PackageFileBodyIndex = "bodyindex.json"
+	// PackageDirAttachments is the name of the directory holding file
+	// attachments in the directory of a dataset version
+	PackageDirAttachments = "attachments"
+)
+
+// PackageFilePath returns the path of a named file within the directory of
+// the dataset version at dsPath
+func PackageFilePath(dsPath, name string) string {
+	return strings.TrimSuffix(dsPath, "/"+dsfs.PackageFileDataset.String()) + "/" + name
+}
+
+// packageStore wraps a store, writing extra files into the directory of each
+// dataset version written through it. dsfs writes a fixed set of component
+// files, extra files are added alongside them in the same add, linking them
+// into the version's DAG so they travel with the dataset. Stores that don't
+// wrap added files in a directory store extra files unlinked
+type packageStore struct {
+	cafs.Filestore
+	// structure of the body being written, used to index it
+	st *dataset.Structure
+	// bodies smaller than this many bytes aren't indexed, a negative value
+	// turns indexing off
+	indexMinSize int64
+	// attachment name to store path
+	attachments map[string]string
+}
+
+// newPackageStore wraps store to add the file attachments & a body index of
+// ds to the version being written. attachments & structure are read from ds
+// before dsfs replaces its components with references
+func newPackageStore(store cafs.Filestore, ds *dataset.Dataset, indexMinSize int64) *packageStore {
+	return &packageStore{Filestore: store, st: ds.Structure, indexMinSize: indexMinSize, attachments: Attachments(ds)}
+}
+
+// NewAdder implements the cafs.Filestore interface
+func (ps *packageStore) NewAdder(pin, wrap bool) (cafs.Adder, error) {
+	inner, err := ps.Filestore.NewAdder(pin, wrap)
+	if err != nil {
+		return nil, err
+	}
+	a := &packageAdder{Adder: inner, store: ps, out: make(chan cafs.AddedFile, 9)}
+	go a.forward()
+	return a, nil
+}
+
+// packageAdder adds extra files to a dataset version, hiding them from the
+// caller so dsfs only sees the files it wrote
+type packageAdder struct {
+	cafs.Adder
+	store *packageStore
+	out   chan cafs.AddedFile
+	index *BodyIndex
+}
+
+// forward passes added files from the wrapped adder to the caller, dropping
+// extra files
+func (a *packageAdder) forward() {
+	for added := range a.Adder.Added() {
+		if added.Name == PackageFileBodyIndex || added.Name == PackageDirAttachments || strings.HasPrefix(added.Name, PackageDirAttachments+"/") {
+			continue
+		}
+		a.out <- added
+	}
+	close(a.out)
+}
+
+// AddFile implements the cafs.Adder interface, indexing the body as the
+// wrapped adder reads it
+func (a *packageAdder) AddFile(ctx context.Context, f qfs.File) error {
+	st := a.store.st
+	if a.store.indexMinSize < 0 || st == nil || f.FileName() != "body."+st.Format {
+		return a.Adder.AddFile(ctx, f)
+	}
+
+	pr, pw := io.Pipe()
+	var (
+		idx    *BodyIndex
+		idxErr error
+		done   = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		idx, idxErr = NewBodyIndex(pr, st, DefaultBodyIndexInterval)
+		// keep reading if indexing stops early so adding the body never blocks
+		io.Copy(ioutil.Discard, pr)
+	}()
+
+	cr := &countingReader{r: io.TeeReader(f, pw)}
+	err := a.Adder.AddFile(ctx, qfs.NewMemfileReader(f.FileName(), cr))
+	if err == nil {
+		// pass any of the body the adder didn't read to the index
+		_, err = io.Copy(ioutil.Discard, cr)
+	}
+	pw.CloseWithError(err)
+	<-done
+	if err != nil {
+		return err
+	}
+
+	if int64(cr.n) >= a.store.indexMinSize {
+		// indexing is an optimization for later reads, don't fail the write over it
+		if idxErr != nil && idxErr != ErrBodyIndexUnsupported {
+			log.Debugf("indexing body: %s", idxErr)
+		} else if idxErr == nil && len(idx.Offsets) > 1 {
+			// an index with a single offset can't skip anything
+			a.index = idx
+		}
+	}
+	return nil
+}
+
+// Added implements the cafs.Adder interface
+func (a *packageAdder) Added() chan cafs.AddedFile {
+	return a.out
+}
+
+// Close implements the cafs.Adder interface, adding extra files before the
+// wrapped adder closes the version's directory
+func (a *packageAdder) Close() error {
+	ctx := context.Background()
+	if len(a.store.attachments) > 0 {
+		names := make([]string, 0, len(a.store.attachments))
+		for name := range a.store.attachments {
+			names = append(names, name)
+		}
+		// directory entries must be added in a stable order for the version
+		// to hash the same way every time
+		sort.Strings(names)
+
+		files := make([]qfs.File, 0, len(names))
+		for _, name := range names {
+			f, err := a.store.Get(ctx, a.store.attachments[name])
+			if err != nil {
+				return fmt.Errorf("loading attachment %s: %s", name, err)
+			}
+			data, err := ioutil.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("loading attachment %s: %s", name, err)
+			}
+			files = append(files, qfs.NewMemfileBytes(name, data))
+		}
+		if err := a.Adder.AddFile(ctx, qfs.NewMemdir(PackageDirAttachments, files...)); err != nil {
+			return fmt.Errorf("adding attachments: %s", err)
+		}
+	}
+	if a.index != nil {
+		data, err := json.Marshal(a.index)
+		if err != nil {
+			return err
+		}
+		if err := a.Adder.AddFile(ctx, qfs.NewMemfileBytes(PackageFileBodyIndex, data)); err != nil {
+			return fmt.Errorf("adding body index: %s", err)
+		}
+	}
+	return a.Adder.Close()
+}
@@ -0,0 +1,78 @@
+package base
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/repo/kvstore"
+	"github.com/qri-io/qri/repo/profile"
+)
+
+func indexedTestDataset(rows int) *dataset.Dataset {
+	vals := make([]string, rows)
+	for i := range vals {
+		vals[i] = strconv.Itoa(i)
+	}
+	ds := &dataset.Dataset{
+		Name:      "indexed",
+		Commit:    &dataset.Commit{Title: "indexed"},
+		Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("["+strings.Join(vals, ",")+"]")))
+	return ds
+}
+
+func TestCreateDatasetBodyIndex(t *testing.T) {
+	ctx := context.Background()
+	// kvstore versions are written as directories, like IPFS
+	store := kvstore.NewFilestore("kv", kvstore.NewMemStore())
+	r, err := repo.NewMemRepo(testPeerProfile, store, qfs.NewMemFS(), profile.NewMemStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := DefaultBodyIndexInterval*2 + 1
+	ref, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), indexedTestDataset(rows), nil, false, true, false, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := ReadBodyIndex(ctx, store, ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.Rows != rows || len(idx.Offsets) != 3 {
+		t.Errorf("index mismatch. expected %d rows & 3 offsets, got: %d rows & %d offsets", rows, idx.Rows, len(idx.Offsets))
+	}
+
+	ds := indexedTestDataset(rows)
+	ds.Name = "unindexed"
+	unindexed, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), ds, nil, false, true, false, false, 1<<30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBodyIndex(ctx, store, unindexed.Path); err != ErrNoBodyIndex {
+		t.Errorf("expected body under the minimum size not to be indexed. got: %v", err)
+	}
+	if unindexed.Dataset.BodyPath != ref.Dataset.BodyPath {
+		t.Errorf("expected indexing not to change the body. got: %s != %s", unindexed.Dataset.BodyPath, ref.Dataset.BodyPath)
+	}
+
+	// stores that don't write versions as directories have no index
+	mr := newTestRepo(t)
+	if ref, err = CreateDataset(ctx, mr, ioes.NewDiscardIOStreams(), indexedTestDataset(rows), nil, false, true, false, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBodyIndex(ctx, mr.Store(), ref.Path); err != ErrNoBodyIndex {
+		t.Errorf("expected a store that doesn't write directories to have no index. got: %v", err)
+	}
+	if _, err := ReadBodyIndex(ctx, cafs.NewMapstore(), "/map/QmMissing"); err != ErrNoBodyIndex {
+		t.Errorf("expected a missing version to have no index. got: %v", err)
+	}
+}
@@ -53,6 +53,13 @@ func init() {
     <small class="path">{{ ds.path }}</small><br />
     {{ if ds.meta.license }}
         <p>License: <a href="{{ ds.meta.license.url }}">{{ ds.meta.license.type }}</a></p>
+    {{ end }}{{ if ds.meta.citations }}
+    <label>sources:</label>
+    <ul>
+      {{ range ds.meta.citations }}
+      <li><a href="{{ .url }}">{{ .name }}</a>{{ if ds.meta.citationsAccessed }}{{ with index ds.meta.citationsAccessed .url }} <small>accessed {{ . }}</small>{{ end }}{{ end }}</li>
+      {{ end }}
+    </ul>
     {{ end }}
   </div>
 </footer>`
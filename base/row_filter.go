@@ -0,0 +1,95 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qri/sql"
+)
+
+// RowFilter selects body rows with a boolean expression, evaluated the same
+// way as the WHERE clause of a sql query
+type RowFilter struct {
+	filter *sql.Filter
+	// keys are the object row keys the filter reads, nil when rows are arrays
+	// of titled columns
+	keys []string
+}
+
+// ParseRowFilter parses a filter expression written like a sql WHERE clause,
+// eg: pop > 1000000 AND city = 'toronto'. Array row columns are named by the
+// structure schema, object rows can filter on any key. Strings must be
+// single-quoted
+func ParseRowFilter(st *dataset.Structure, expr string) (*RowFilter, error) {
+	names := SchemaColumnNames(st)
+	if len(names) == 0 || objectRows(st) {
+		f, err := sql.ParseFilter(expr, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: %s", err)
+		}
+		return &RowFilter{filter: f, keys: f.Columns()}, nil
+	}
+
+	f, err := sql.ParseFilter(expr, names)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %s", err)
+	}
+	return &RowFilter{filter: f}, nil
+}
+
+// objectRows reports whether the structure schema describes rows as objects
+func objectRows(st *dataset.Structure) bool {
+	if st == nil || st.Schema == nil {
+		return false
+	}
+	items, _ := st.Schema["items"].(map[string]interface{})
+	return items["type"] == "object"
+}
+
+// Match reports whether a row passes the filter. Array rows only match
+// filters on titled columns, object rows only match filters on keys
+func (f *RowFilter) Match(row interface{}) (bool, error) {
+	switch r := row.(type) {
+	case []interface{}:
+		if f.keys != nil {
+			return false, nil
+		}
+		return f.filter.Match(r)
+	case map[string]interface{}:
+		if f.keys == nil {
+			return false, nil
+		}
+		vals := make([]interface{}, len(f.keys))
+		for i, key := range f.keys {
+			vals[i] = r[key]
+		}
+		return f.filter.Match(vals)
+	}
+	return false, nil
+}
+
+// FilteredReader is an EntryReader that skips entries that don't match a
+// row filter
+type FilteredReader struct {
+	dsio.EntryReader
+	Filter *RowFilter
+}
+
+// ReadEntry reads entries from the underlying reader until one matches the
+// filter
+func (r *FilteredReader) ReadEntry() (dsio.Entry, error) {
+	for {
+		ent, err := r.EntryReader.ReadEntry()
+		if err != nil {
+			return ent, err
+		}
+		ok, err := r.Filter.Match(ent.Value)
+		if err != nil {
+			return ent, fmt.Errorf("filtering rows: %s", err)
+		}
+		if ok {
+			return ent, nil
+		}
+	}
+}
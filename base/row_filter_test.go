@@ -0,0 +1,90 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+var rowFilterTabular = &dataset.Structure{
+	Format: "csv",
+	Schema: map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "city", "type": "string"},
+				map[string]interface{}{"title": "pop", "type": "integer"},
+			},
+		},
+	},
+}
+
+func TestParseRowFilter(t *testing.T) {
+	cases := []struct {
+		expr string
+		err  string
+	}{
+		{"city = 'toronto'", ""},
+		{"pop >= 100 AND city != 'new york'", ""},
+		{"city", ""},
+		{"=toronto", `invalid filter: unexpected "=" at position 0`},
+		{"state = 'ny'", `invalid filter: unknown column "state"`},
+		{"city = 'toronto", `invalid filter: unterminated quote starting at position 7`},
+	}
+	for _, c := range cases {
+		_, err := ParseRowFilter(rowFilterTabular, c.expr)
+		if c.err == "" && err != nil {
+			t.Errorf("%q unexpected error: %s", c.expr, err)
+		} else if c.err != "" && (err == nil || err.Error() != c.err) {
+			t.Errorf("%q error mismatch. expected: %q, got: %v", c.expr, c.err, err)
+		}
+	}
+}
+
+func TestRowFilterMatch(t *testing.T) {
+	toronto := []interface{}{"toronto", int64(40000000)}
+	chicago := []interface{}{"chicago", float64(300000)}
+
+	cases := []struct {
+		st     *dataset.Structure
+		expr   string
+		row    interface{}
+		expect bool
+	}{
+		{rowFilterTabular, "city = 'toronto'", toronto, true},
+		{rowFilterTabular, "city = 'toronto'", chicago, false},
+		{rowFilterTabular, "city <> 'toronto'", chicago, true},
+		{rowFilterTabular, "pop > 1000000", toronto, true},
+		{rowFilterTabular, "pop > 1000000", chicago, false},
+		{rowFilterTabular, "pop <= 300000 AND city LIKE 'chi%'", chicago, true},
+		{rowFilterTabular, "pop = 1", []interface{}{"short row"}, false},
+		{nil, "a = 1", map[string]interface{}{"a": float64(1)}, true},
+		{nil, "a IS NULL", map[string]interface{}{"b": "x"}, true},
+		{nil, "in_usa", map[string]interface{}{"in_usa": true}, true},
+		{nil, "a = 1", []interface{}{float64(1)}, false},
+	}
+	for _, c := range cases {
+		f, err := ParseRowFilter(c.st, c.expr)
+		if err != nil {
+			t.Errorf("%q unexpected error: %s", c.expr, err)
+			continue
+		}
+		got, err := f.Match(c.row)
+		if err != nil {
+			t.Errorf("%q match %v unexpected error: %s", c.expr, c.row, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%q match %v mismatch. expected: %t, got: %t", c.expr, c.row, c.expect, got)
+		}
+	}
+
+	f, err := ParseRowFilter(rowFilterTabular, "city > 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Match(toronto); err == nil {
+		t.Error("expected comparing a string column with a number to error")
+	}
+}
@@ -0,0 +1,122 @@
+package base
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/repo"
+)
+
+// SchemaChangeType enumerates the ways a column can change between versions
+type SchemaChangeType string
+
+const (
+	// SchemaColumnAdded indicates a column present in a version that wasn't in
+	// the version before it
+	SchemaColumnAdded = SchemaChangeType("added")
+	// SchemaColumnRemoved indicates a column dropped from the previous version
+	SchemaColumnRemoved = SchemaChangeType("removed")
+	// SchemaColumnTypeChanged indicates a column whose type differs from the
+	// previous version
+	SchemaColumnTypeChanged = SchemaChangeType("typeChanged")
+)
+
+// SchemaChange describes a change to a single column of a body schema
+type SchemaChange struct {
+	Change SchemaChangeType `json:"change"`
+	Column string           `json:"column"`
+	// From is the column type before the change, nil for added columns
+	From interface{} `json:"from,omitempty"`
+	// To is the column type after the change, nil for removed columns
+	To interface{} `json:"to,omitempty"`
+}
+
+// SchemaVersion is a dataset version that changed the body schema
+type SchemaVersion struct {
+	Path      string         `json:"path"`
+	Timestamp time.Time      `json:"timestamp"`
+	Title     string         `json:"title,omitempty"`
+	Changes   []SchemaChange `json:"changes"`
+}
+
+// SchemaHistory walks the history of a dataset, comparing the body schema of
+// each version to the one before it. Columns are the titles of a tabular
+// schema, or the property names of an object-row schema. SchemaHistory
+// returns versions that changed columns, newest first. The first version of a
+// dataset reports all of its columns as added
+func SchemaHistory(ctx context.Context, r repo.Repo, ref repo.DatasetRef) ([]SchemaVersion, error) {
+	versions, err := DatasetHistory(ctx, r, ref, -1, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []SchemaVersion{}
+	var prevNames []string
+	prevTypes := map[string]interface{}{}
+	// log is newest first, walk it oldest first
+	for i := len(versions) - 1; i >= 0; i-- {
+		ds := versions[i].Dataset
+		names, types := schemaColumnTypes(ds.Structure)
+		changes := diffSchemaColumns(prevNames, prevTypes, names, types)
+		prevNames, prevTypes = names, types
+		if len(changes) == 0 {
+			continue
+		}
+
+		sv := SchemaVersion{Path: versions[i].Path, Changes: changes}
+		if ds.Commit != nil {
+			sv.Timestamp = ds.Commit.Timestamp
+			sv.Title = ds.Commit.Title
+		}
+		history = append([]SchemaVersion{sv}, history...)
+	}
+	return history, nil
+}
+
+// schemaColumnTypes returns the names of columns in a body schema in schema
+// order, and a map of column name to type
+func schemaColumnTypes(st *dataset.Structure) ([]string, map[string]interface{}) {
+	names := SchemaColumnNames(st)
+	types := map[string]interface{}{}
+	if len(names) == 0 {
+		return names, types
+	}
+
+	items, _ := st.Schema["items"].(map[string]interface{})
+	if cols, ok := items["items"].([]interface{}); ok {
+		for _, col := range cols {
+			c, _ := col.(map[string]interface{})
+			title, _ := c["title"].(string)
+			types[title] = c["type"]
+		}
+	} else if props, ok := items["properties"].(map[string]interface{}); ok {
+		for name, prop := range props {
+			p, _ := prop.(map[string]interface{})
+			types[name] = p["type"]
+		}
+	}
+	return names, types
+}
+
+// diffSchemaColumns lists column changes between two versions of a schema.
+// removed columns are listed in previous order, followed by added & retyped
+// columns in next order
+func diffSchemaColumns(prevNames []string, prevTypes map[string]interface{}, names []string, types map[string]interface{}) []SchemaChange {
+	changes := []SchemaChange{}
+	for _, name := range prevNames {
+		if _, ok := types[name]; !ok {
+			changes = append(changes, SchemaChange{Change: SchemaColumnRemoved, Column: name, From: prevTypes[name]})
+		}
+	}
+	for _, name := range names {
+		prev, ok := prevTypes[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Change: SchemaColumnAdded, Column: name, To: types[name]})
+		} else if !reflect.DeepEqual(prev, types[name]) {
+			changes = append(changes, SchemaChange{Change: SchemaColumnTypeChanged, Column: name, From: prev, To: types[name]})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,57 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchemaHistory(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	ref := addCitiesDataset(t, r)
+	head := updateCitiesDataset(t, r)
+
+	history, err := SchemaHistory(ctx, r, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the update only changes meta, leaving the first version as the only
+	// schema change
+	if len(history) != 1 {
+		t.Fatalf("expected 1 schema version, got: %d", len(history))
+	}
+	if history[0].Path != ref.Path {
+		t.Errorf("path mismatch. expected: %s, got: %s", ref.Path, history[0].Path)
+	}
+	for _, c := range history[0].Changes {
+		if c.Change != SchemaColumnAdded {
+			t.Errorf("expected first version columns to be added, got: %s %s", c.Change, c.Column)
+		}
+	}
+	if len(history[0].Changes) == 0 {
+		t.Error("expected first version to add columns")
+	}
+}
+
+func TestDiffSchemaColumns(t *testing.T) {
+	prevNames := []string{"city", "pop", "avg_age"}
+	prevTypes := map[string]interface{}{"city": "string", "pop": "integer", "avg_age": "number"}
+	names := []string{"city", "pop", "in_usa"}
+	types := map[string]interface{}{"city": "string", "pop": "number", "in_usa": "boolean"}
+
+	expect := []SchemaChange{
+		{Change: SchemaColumnRemoved, Column: "avg_age", From: "number"},
+		{Change: SchemaColumnTypeChanged, Column: "pop", From: "integer", To: "number"},
+		{Change: SchemaColumnAdded, Column: "in_usa", To: "boolean"},
+	}
+	got := diffSchemaColumns(prevNames, prevTypes, names, types)
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := diffSchemaColumns(names, types, names, types); len(got) != 0 {
+		t.Errorf("expected no changes comparing a schema to itself, got: %v", got)
+	}
+}
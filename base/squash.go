@@ -0,0 +1,127 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/repo"
+)
+
+// SquashDataset rewrites the history of a dataset, replacing the latest n
+// versions with a single version that has the same content as the current
+// head. The squashed version builds on the version before the range, or
+// becomes a new root when n is -1 or covers the entire history. title &
+// message set the commit of the squashed version, defaulting to a summary of
+// the squashed commits. When unpin is true squashed versions that are no
+// longer in history are unpinned from the store. SquashDataset returns the
+// new head reference & the references that were squashed
+func SquashDataset(ctx context.Context, r repo.Repo, ref repo.DatasetRef, n int, title, message string, unpin bool) (head repo.DatasetRef, squashed []repo.DatasetRef, err error) {
+	if n == 0 || n == 1 || n < -1 {
+		return head, nil, fmt.Errorf("squash requires at least 2 versions, got: %d", n)
+	}
+
+	limit := -1
+	if n > 0 {
+		// read one extra version to find the version the squash builds on
+		limit = n + 1
+	}
+	history, err := DatasetHistory(ctx, r, ref, limit, 0, true)
+	if err != nil {
+		return head, nil, err
+	}
+
+	var prev *dataset.Dataset
+	prevPath := ""
+	squashed = history
+	if n > 0 && len(history) > n {
+		squashed = history[:n]
+		prevPath = history[n].Path
+		if prev, err = dsfs.LoadDataset(ctx, r.Store(), prevPath); err != nil {
+			return head, nil, err
+		}
+		if prev.BodyPath != "" {
+			var body qfs.File
+			if body, err = dsfs.LoadBody(ctx, r.Store(), prev); err != nil {
+				return head, nil, err
+			}
+			prev.SetBodyFile(body)
+		}
+	}
+	if len(squashed) < 2 {
+		return head, nil, fmt.Errorf("nothing to squash, dataset %s has %d version", ref.AliasString(), len(squashed))
+	}
+
+	ds, err := dsfs.LoadDataset(ctx, r.Store(), ref.Path)
+	if err != nil {
+		return head, nil, err
+	}
+	ds.Name = ref.Name
+	ds.Peername = ref.Peername
+	ds.Path = ""
+	ds.PreviousPath = prevPath
+	if title == "" {
+		title = fmt.Sprintf("squashed %d versions", len(squashed))
+		if message == "" {
+			message = squashMessage(squashed)
+		}
+	}
+	ds.Commit = &dataset.Commit{Title: title, Message: message}
+	if ds.BodyPath != "" {
+		body, err := dsfs.LoadBody(ctx, r.Store(), ds)
+		if err != nil {
+			return head, nil, err
+		}
+		ds.SetBodyFile(body)
+	}
+
+	pro, err := r.Profile()
+	if err != nil {
+		return head, nil, err
+	}
+	if err = OpenDataset(ctx, r.Filesystem(), ds); err != nil {
+		return head, nil, err
+	}
+	if err = InferValues(pro, ds); err != nil {
+		return head, nil, err
+	}
+
+	// squashed versions have the same content as the version they replace,
+	// so the save must be forced
+	if head, err = CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), ds, prev, false, true, true, false, DefaultBodyIndexMinSize); err != nil {
+		return head, nil, err
+	}
+
+	// creating the dataset replaces the reference, keep any link to a
+	// working directory
+	if ref.FSIPath != "" {
+		head.FSIPath = ref.FSIPath
+		if err = r.PutRef(head); err != nil {
+			return head, nil, err
+		}
+	}
+
+	if unpin {
+		for _, v := range squashed {
+			if err = UnpinDataset(ctx, r, v); err != nil && err != repo.ErrNotPinner {
+				return head, squashed, err
+			}
+		}
+	}
+	return head, squashed, nil
+}
+
+// squashMessage lists the commit titles of squashed versions, newest first
+func squashMessage(squashed []repo.DatasetRef) string {
+	titles := make([]string, 0, len(squashed))
+	for _, v := range squashed {
+		if v.Dataset != nil && v.Dataset.Commit != nil && v.Dataset.Commit.Title != "" {
+			titles = append(titles, "* "+v.Dataset.Commit.Title)
+		}
+	}
+	return strings.Join(titles, "\n")
+}
@@ -0,0 +1,95 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestSquashDataset(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	root := addCitiesDataset(t, r)
+	updateCitiesDataset(t, r)
+
+	// add a third version on top of the update
+	ref, err := r.GetRef(repo.DatasetRef{Peername: root.Peername, Name: root.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := dsfs.LoadDataset(ctx, r.Store(), ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds.Name = ref.Name
+	ds.Peername = ref.Peername
+	ds.PreviousPath = ref.Path
+	ds.Path = ""
+	ds.Meta.Title = "third title"
+	ds.Commit = nil
+	if err = OpenDataset(ctx, r.Store(), ds); err != nil {
+		t.Fatal(err)
+	}
+	pro, err := r.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = InferValues(pro, ds); err != nil {
+		t.Fatal(err)
+	}
+	third, err := CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), ds, nil, false, true, false, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := SquashDataset(ctx, r, third, 1, "", "", false); err == nil {
+		t.Error("expected squashing a single version to error")
+	}
+
+	head, squashed, err := SquashDataset(ctx, r, third, 2, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(squashed) != 2 {
+		t.Errorf("expected 2 squashed versions, got: %d", len(squashed))
+	}
+	if head.Dataset.PreviousPath != root.Path {
+		t.Errorf("expected squashed version to build on the root. want: %s, got: %s", root.Path, head.Dataset.PreviousPath)
+	}
+	if head.Dataset.Meta.Title != "third title" {
+		t.Errorf("expected squashed version to keep head content. got title: %q", head.Dataset.Meta.Title)
+	}
+	if head.Dataset.Commit.Title != "squashed 2 versions" {
+		t.Errorf("commit title mismatch. got: %q", head.Dataset.Commit.Title)
+	}
+
+	got, err := r.GetRef(repo.DatasetRef{Peername: root.Peername, Name: root.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Path != head.Path {
+		t.Errorf("expected head reference to be updated. want: %s, got: %s", head.Path, got.Path)
+	}
+
+	dlog, err := DatasetLog(ctx, r, head, 100, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dlog) != 2 {
+		t.Errorf("expected 2 versions after squash, got: %d", len(dlog))
+	}
+
+	head, _, err = SquashDataset(ctx, r, head, -1, "fresh start", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Dataset.PreviousPath != "" {
+		t.Errorf("expected squashing all versions to create a new root, got previous: %s", head.Dataset.PreviousPath)
+	}
+	if head.Dataset.Commit.Title != "fresh start" {
+		t.Errorf("commit title mismatch. got: %q", head.Dataset.Commit.Title)
+	}
+}
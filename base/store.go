@@ -0,0 +1,93 @@
+package base
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// ErrReadOnlyStore is returned when writing to a store that doesn't accept
+// writes
+var ErrReadOnlyStore = fmt.Errorf("store is read-only")
+
+// ReadOnlyStore is an optional interface for stores that can report if they
+// accept writes. stores that don't implement ReadOnlyStore are assumed to be
+// writable
+type ReadOnlyStore interface {
+	ReadOnly() bool
+}
+
+// StoreWritable checks if a store accepts writes, returning ErrReadOnlyStore
+// if it doesn't
+func StoreWritable(store cafs.Filestore) error {
+	if ros, ok := store.(ReadOnlyStore); ok && ros.ReadOnly() {
+		return ErrReadOnlyStore
+	}
+	return nil
+}
+
+// NewReadOnlyStore wraps a store, rejecting all writes with ErrReadOnlyStore.
+// the returned store implements cafs.Pinner & cafs.Fetcher, fetches pass
+// through to stores that support them, pins are rejected
+func NewReadOnlyStore(store cafs.Filestore) cafs.Filestore {
+	return readOnlyStore{store}
+}
+
+// readOnlyStore is a cafs.Filestore that rejects writes
+type readOnlyStore struct {
+	cafs.Filestore
+}
+
+var (
+	// compile-time assertion that readOnlyStore is a ReadOnlyStore
+	_ ReadOnlyStore = (*readOnlyStore)(nil)
+	// compile-time assertion that readOnlyStore is a cafs.Pinner
+	_ cafs.Pinner = (*readOnlyStore)(nil)
+	// compile-time assertion that readOnlyStore is a cafs.Fetcher
+	_ cafs.Fetcher = (*readOnlyStore)(nil)
+)
+
+// ReadOnly implements the ReadOnlyStore interface
+func (readOnlyStore) ReadOnly() bool {
+	return true
+}
+
+// Unwrap returns the store writes are rejected for
+func (s readOnlyStore) Unwrap() cafs.Filestore {
+	return s.Filestore
+}
+
+// Put implements the cafs.Filestore interface
+func (readOnlyStore) Put(ctx context.Context, file qfs.File, pin bool) (string, error) {
+	return "", ErrReadOnlyStore
+}
+
+// Delete implements the cafs.Filestore interface
+func (readOnlyStore) Delete(ctx context.Context, key string) error {
+	return ErrReadOnlyStore
+}
+
+// NewAdder implements the cafs.Filestore interface
+func (readOnlyStore) NewAdder(pin, wrap bool) (cafs.Adder, error) {
+	return nil, ErrReadOnlyStore
+}
+
+// Pin implements the cafs.Pinner interface
+func (readOnlyStore) Pin(ctx context.Context, key string, recursive bool) error {
+	return ErrReadOnlyStore
+}
+
+// Unpin implements the cafs.Pinner interface
+func (readOnlyStore) Unpin(ctx context.Context, key string, recursive bool) error {
+	return ErrReadOnlyStore
+}
+
+// Fetch implements the cafs.Fetcher interface
+func (s readOnlyStore) Fetch(ctx context.Context, source cafs.Source, key string) (qfs.File, error) {
+	if fetcher, ok := s.Filestore.(cafs.Fetcher); ok {
+		return fetcher.Fetch(ctx, source, key)
+	}
+	return nil, fmt.Errorf("store doesn't support fetching")
+}
@@ -0,0 +1,57 @@
+package base
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func TestReadOnlyStore(t *testing.T) {
+	ctx := context.Background()
+	store := cafs.NewMapstore()
+	if err := StoreWritable(store); err != nil {
+		t.Errorf("expected map store to be writable. got: %s", err)
+	}
+
+	path, err := store.Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("hello")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro := NewReadOnlyStore(store)
+	if err := StoreWritable(ro); err != ErrReadOnlyStore {
+		t.Errorf("expected ErrReadOnlyStore. got: %v", err)
+	}
+
+	if _, err := ro.Put(ctx, qfs.NewMemfileBytes("b.txt", []byte("world")), false); err != ErrReadOnlyStore {
+		t.Errorf("expected put to return ErrReadOnlyStore. got: %v", err)
+	}
+	if err := ro.Delete(ctx, path); err != ErrReadOnlyStore {
+		t.Errorf("expected delete to return ErrReadOnlyStore. got: %v", err)
+	}
+	if _, err := ro.NewAdder(false, false); err != ErrReadOnlyStore {
+		t.Errorf("expected new adder to return ErrReadOnlyStore. got: %v", err)
+	}
+
+	if err := ro.(cafs.Pinner).Pin(ctx, path, true); err != ErrReadOnlyStore {
+		t.Errorf("expected pin to return ErrReadOnlyStore. got: %v", err)
+	}
+	if _, err := ro.(cafs.Fetcher).Fetch(ctx, cafs.SourceAny, path); err == nil {
+		t.Error("expected fetch from a store that can't fetch to error")
+	}
+
+	f, err := ro.Get(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("expected reads to pass through. got: %q", string(data))
+	}
+}
@@ -0,0 +1,218 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/repo"
+)
+
+// LargeTransformInputSize is the total input size in bytes above which a
+// transform is considered large enough to confirm before running
+const LargeTransformInputSize = 500 << 20
+
+// TransformInput kinds
+const (
+	// TransformInputDataset is a qri dataset loaded by a transform
+	TransformInputDataset = "dataset"
+	// TransformInputURL is a web resource fetched by a transform
+	TransformInputURL = "url"
+)
+
+// TransformInput is a resource a transform is expected to read
+type TransformInput struct {
+	Kind string `json:"kind"`
+	// Ref is a dataset reference or url
+	Ref string `json:"ref"`
+	// Size is the input size in bytes, -1 if it can't be determined
+	Size int64 `json:"size"`
+	// Entries is the number of body entries for dataset inputs
+	Entries int `json:"entries,omitempty"`
+	// Error explains why the size of an input couldn't be determined
+	Error string `json:"error,omitempty"`
+}
+
+// TransformEstimate is a best-effort estimate of what a transform will read
+type TransformEstimate struct {
+	Inputs []TransformInput `json:"inputs"`
+	// TotalSize is the sum of known input sizes in bytes
+	TotalSize int64 `json:"totalSize"`
+	// Unknown is the number of inputs with an unknown size
+	Unknown int `json:"unknown"`
+}
+
+// Large reports whether a run should be confirmed before it starts, either
+// because inputs are large or because their size couldn't be determined
+func (e *TransformEstimate) Large() bool {
+	return e.TotalSize > LargeTransformInputSize || e.Unknown > 0
+}
+
+var (
+	loadDatasetCall = regexp.MustCompile(`load_dataset\(\s*["']([^"']+)["']`)
+	urlLiteral      = regexp.MustCompile(`["'](https?://[^"'\s]+)["']`)
+	// estimateHTTPClient sizes url inputs, using a short timeout because
+	// estimates shouldn't hold up a save
+	estimateHTTPClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// EstimateTransformInputs estimates the inputs a transform will read without
+// running it. Inputs are the resources recorded on the transform, plus
+// dataset references passed to load_dataset & urls written as string
+// literals in the script. Inputs built at runtime can't be found, so the
+// estimate is a lower bound. Dataset sizes come from their stored structure,
+// url sizes from the Content-Length of a HEAD request
+func EstimateTransformInputs(ctx context.Context, r repo.Repo, tf *dataset.Transform) (*TransformEstimate, error) {
+	if tf == nil {
+		return nil, fmt.Errorf("no transform to estimate")
+	}
+
+	datasets, err := TransformDependencies(tf)
+	if err != nil {
+		return nil, err
+	}
+	script, err := transformScript(tf)
+	if err != nil {
+		return nil, err
+	}
+	urls := map[string]bool{}
+	for _, m := range urlLiteral.FindAllSubmatch(script, -1) {
+		urls[string(m[1])] = true
+	}
+
+	est := &TransformEstimate{Inputs: []TransformInput{}}
+	add := func(in TransformInput) {
+		if in.Size < 0 {
+			est.Unknown++
+		} else {
+			est.TotalSize += in.Size
+		}
+		est.Inputs = append(est.Inputs, in)
+	}
+
+	// resources are recorded with resolved paths & scripts reference them by
+	// name, skip references that resolve to an input that's already counted
+	resolved := map[string]bool{}
+	for _, refstr := range datasets {
+		in, path := estimateDatasetInput(ctx, r, refstr)
+		if path != "" {
+			if resolved[path] {
+				continue
+			}
+			resolved[path] = true
+		}
+		add(in)
+	}
+	for _, u := range sortedKeys(urls) {
+		add(estimateURLInput(ctx, u))
+	}
+	return est, nil
+}
+
+// TransformDependencies lists the dataset references a transform reads in
+// sorted order: the resources recorded on the transform, plus references
+// passed to load_dataset in the script
+func TransformDependencies(tf *dataset.Transform) ([]string, error) {
+	if tf == nil {
+		return nil, nil
+	}
+	datasets := map[string]bool{}
+	for _, res := range tf.Resources {
+		if res != nil && res.Path != "" {
+			datasets[res.Path] = true
+		}
+	}
+
+	script, err := transformScript(tf)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range loadDatasetCall.FindAllSubmatch(script, -1) {
+		datasets[string(m[1])] = true
+	}
+	return sortedKeys(datasets), nil
+}
+
+// transformScript reads a transform script, replacing the script file so it
+// can still be read by the transform
+func transformScript(tf *dataset.Transform) ([]byte, error) {
+	if tf.ScriptBytes != nil {
+		return tf.ScriptBytes, nil
+	}
+	f := tf.ScriptFile()
+	if f == nil {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading transform script: %s", err)
+	}
+	tf.SetScriptFile(qfs.NewMemfileBytes(f.FileName(), data))
+	return data, nil
+}
+
+func estimateDatasetInput(ctx context.Context, r repo.Repo, refstr string) (TransformInput, string) {
+	in := TransformInput{Kind: TransformInputDataset, Ref: refstr, Size: -1}
+	ref, err := repo.ParseDatasetRef(refstr)
+	if err != nil {
+		in.Error = err.Error()
+		return in, ""
+	}
+	if err := repo.CanonicalizeDatasetRef(r, &ref); err != nil {
+		in.Error = fmt.Sprintf("not available locally: %s", err)
+		return in, ""
+	}
+	ds, err := dsfs.LoadDataset(ctx, r.Store(), ref.Path)
+	if err != nil {
+		in.Error = err.Error()
+		return in, ref.Path
+	}
+	if ds.Structure == nil || ds.Structure.Length == 0 {
+		in.Error = "dataset doesn't record a body size"
+		return in, ref.Path
+	}
+	in.Size = int64(ds.Structure.Length)
+	in.Entries = ds.Structure.Entries
+	return in, ref.Path
+}
+
+func estimateURLInput(ctx context.Context, u string) TransformInput {
+	in := TransformInput{Kind: TransformInputURL, Ref: u, Size: -1}
+	req, err := http.NewRequest("HEAD", u, nil)
+	if err != nil {
+		in.Error = err.Error()
+		return in
+	}
+	res, err := estimateHTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		in.Error = err.Error()
+		return in
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		in.Error = fmt.Sprintf("HEAD request returned status %d", res.StatusCode)
+		return in
+	}
+	if res.ContentLength < 0 {
+		in.Error = "server didn't report a size"
+		return in
+	}
+	in.Size = res.ContentLength
+	return in
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestEstimateTransformInputs(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+	ref := addCitiesDataset(t, r)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing.csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "2048")
+	}))
+	defer s.Close()
+
+	script := fmt.Sprintf(`
+def download(ctx):
+  return http.get("%s/data.csv")
+
+def transform(ds, ctx):
+  cities = load_dataset("peer/cities")
+  http.get('%s/missing.csv')
+`, s.URL, s.URL)
+
+	tf := &dataset.Transform{
+		Syntax: "starlark",
+		Resources: map[string]*dataset.TransformResource{
+			ref.Path: {Path: ref.String()},
+		},
+	}
+	tf.SetScriptFile(qfs.NewMemfileBytes("transform.star", []byte(script)))
+
+	est, err := EstimateTransformInputs(ctx, r, tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(est.Inputs) != 3 {
+		t.Fatalf("expected 3 inputs, got: %v", est.Inputs)
+	}
+
+	cities := est.Inputs[0]
+	if cities.Kind != TransformInputDataset || cities.Size <= 0 || cities.Entries == 0 {
+		t.Errorf("expected sized cities dataset input, got: %v", cities)
+	}
+	if data := est.Inputs[1]; data.Kind != TransformInputURL || data.Size != 2048 {
+		t.Errorf("expected url input sized from content-length, got: %v", data)
+	}
+	if missing := est.Inputs[2]; missing.Size != -1 || missing.Error == "" {
+		t.Errorf("expected missing url to have an unknown size, got: %v", missing)
+	}
+	if est.TotalSize != cities.Size+2048 || est.Unknown != 1 {
+		t.Errorf("totals mismatch. total: %d, unknown: %d", est.TotalSize, est.Unknown)
+	}
+	if !est.Large() {
+		t.Error("expected estimates with unknown inputs to be large")
+	}
+
+	// the script can still be read after estimating
+	if tf.ScriptFile() == nil {
+		t.Fatal("expected script file to be replaced")
+	}
+	if _, err := EstimateTransformInputs(ctx, r, tf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := EstimateTransformInputs(ctx, r, nil); err == nil {
+		t.Error("expected estimating a nil transform to error")
+	}
+}
+
+func TestTransformDependencies(t *testing.T) {
+	tf := &dataset.Transform{
+		Resources: map[string]*dataset.TransformResource{
+			"/map/QmCities": {Path: "peer/cities@/map/QmCities"},
+		},
+	}
+	tf.SetScriptFile(qfs.NewMemfileBytes("transform.star", []byte(`
+def transform(ds, ctx):
+  a = load_dataset("peer/cities")
+  b = load_dataset( 'peer/airports' )
+`)))
+
+	got, err := TransformDependencies(tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"peer/airports", "peer/cities", "peer/cities@/map/QmCities"}
+	if fmt.Sprintf("%v", got) != fmt.Sprintf("%v", expect) {
+		t.Errorf("dependencies mismatch. expected: %v, got: %v", expect, got)
+	}
+
+	if got, err := TransformDependencies(nil); err != nil || len(got) != 0 {
+		t.Errorf("expected no dependencies for a nil transform, got: %v, %v", got, err)
+	}
+}
@@ -0,0 +1,173 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/repo/profile"
+)
+
+// ErrNotInTrash indicates a dataset isn't in the trash
+var ErrNotInTrash = fmt.Errorf("dataset not found in trash")
+
+// TrashEntry is a soft-deleted dataset reference
+type TrashEntry struct {
+	Ref       repo.DatasetRef `json:"ref"`
+	TrashedAt time.Time       `json:"trashedAt"`
+}
+
+// trashRecord is the persisted form of a TrashEntry. profile IDs are stored
+// as raw bytes so any ID the refstore accepts round-trips
+type trashRecord struct {
+	Peername  string    `json:"peername"`
+	ProfileID []byte    `json:"profileID"`
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	TrashedAt time.Time `json:"trashedAt"`
+}
+
+// Trash holds soft-deleted dataset references, persisting them to a JSON
+// file. Content of trashed datasets stays pinned until swept from the trash.
+// Trash is safe for concurrent use
+type Trash struct {
+	lock sync.Mutex
+	path string
+}
+
+// NewTrash creates a trash that persists to the file at path
+func NewTrash(path string) *Trash {
+	return &Trash{path: path}
+}
+
+// List returns trashed datasets, most recently trashed first
+func (t *Trash) List() ([]TrashEntry, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.load()
+}
+
+// Put adds a reference to the trash, replacing any trashed dataset with the
+// same alias
+func (t *Trash) Put(ref repo.DatasetRef, trashedAt time.Time) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	entries, err := t.load()
+	if err != nil {
+		return err
+	}
+	entries, _ = removeTrashEntry(entries, ref.AliasString())
+	entries = append(entries, TrashEntry{Ref: ref, TrashedAt: trashedAt})
+	return t.save(entries)
+}
+
+// Take removes a trashed dataset by alias, returning it. Take returns
+// ErrNotInTrash if no dataset with the given alias is in the trash
+func (t *Trash) Take(alias string) (TrashEntry, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	entries, err := t.load()
+	if err != nil {
+		return TrashEntry{}, err
+	}
+	entries, taken := removeTrashEntry(entries, alias)
+	if taken == nil {
+		return TrashEntry{}, ErrNotInTrash
+	}
+	return *taken, t.save(entries)
+}
+
+// Sweep permanently deletes datasets that have been in the trash longer than
+// retention, unpinning their content. Sweep returns the swept entries
+func (t *Trash) Sweep(ctx context.Context, r repo.Repo, retention time.Duration, now time.Time) ([]TrashEntry, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	entries, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := []TrashEntry{}
+	swept := []TrashEntry{}
+	for _, e := range entries {
+		if now.Sub(e.TrashedAt) < retention {
+			keep = append(keep, e)
+			continue
+		}
+		if err := UnpinDataset(ctx, r, e.Ref); err != nil && err != repo.ErrNotPinner {
+			return nil, err
+		}
+		swept = append(swept, e)
+	}
+
+	if len(swept) == 0 {
+		return swept, nil
+	}
+	return swept, t.save(keep)
+}
+
+func (t *Trash) load() ([]TrashEntry, error) {
+	entries := []TrashEntry{}
+	data, err := ioutil.ReadFile(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	records := []trashRecord{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("reading trash: %s", err)
+	}
+	for _, rec := range records {
+		entries = append(entries, TrashEntry{
+			Ref: repo.DatasetRef{
+				Peername:  rec.Peername,
+				ProfileID: profile.ID(rec.ProfileID),
+				Name:      rec.Name,
+				Path:      rec.Path,
+			},
+			TrashedAt: rec.TrashedAt,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].TrashedAt.After(entries[j].TrashedAt)
+	})
+	return entries, nil
+}
+
+func (t *Trash) save(entries []TrashEntry) error {
+	records := make([]trashRecord, len(entries))
+	for i, e := range entries {
+		records[i] = trashRecord{
+			Peername:  e.Ref.Peername,
+			ProfileID: []byte(e.Ref.ProfileID),
+			Name:      e.Ref.Name,
+			Path:      e.Ref.Path,
+			TrashedAt: e.TrashedAt,
+		}
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.path, data, 0644)
+}
+
+func removeTrashEntry(entries []TrashEntry, alias string) ([]TrashEntry, *TrashEntry) {
+	for i, e := range entries {
+		if e.Ref.AliasString() == alias {
+			return append(entries[:i], entries[i+1:]...), &e
+		}
+	}
+	return entries, nil
+}
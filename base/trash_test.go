@@ -0,0 +1,90 @@
+package base
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qri-io/qri/repo"
+)
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "TestTrash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := newTestRepo(t)
+	ref := addCitiesDataset(t, r)
+
+	trash := NewTrash(filepath.Join(dir, "trash.json"))
+	entries, err := trash.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected new trash to be empty")
+	}
+
+	now := time.Now()
+	older := repo.DatasetRef{Peername: "peer", Name: "older", Path: "/map/QmOlder"}
+	if err := trash.Put(older, now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := trash.Put(ref, now); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = trash.List(); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Ref.AliasString() != ref.AliasString() {
+		t.Errorf("expected trash to list most recently trashed first. got: %v", entries)
+	}
+
+	// putting the same alias replaces the trashed reference
+	if err := trash.Put(older, now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = trash.List(); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected re-trashing an alias to replace the entry. got: %d entries", len(entries))
+	}
+
+	if _, err := trash.Take("peer/missing"); err != ErrNotInTrash {
+		t.Errorf("expected ErrNotInTrash, got: %v", err)
+	}
+	taken, err := trash.Take("peer/older")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if taken.Ref.Path != older.Path {
+		t.Errorf("taken path mismatch. want: %q, got: %q", older.Path, taken.Ref.Path)
+	}
+
+	swept, err := trash.Sweep(ctx, r, time.Hour, now.Add(30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(swept) != 0 {
+		t.Errorf("expected no entries within retention to be swept. got: %d", len(swept))
+	}
+	if swept, err = trash.Sweep(ctx, r, time.Hour, now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(swept) != 1 || swept[0].Ref.AliasString() != ref.AliasString() {
+		t.Errorf("expected %s to be swept. got: %v", ref.AliasString(), swept)
+	}
+	if entries, err = trash.List(); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected swept trash to be empty. got: %d entries", len(entries))
+	}
+}
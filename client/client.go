@@ -0,0 +1,109 @@
+// Package client is a typed Go wrapper for the qri JSON API. Client methods
+// mirror lib method signatures, accepting lib params & filling lib results,
+// but make HTTP requests to a qri node instead of operating on a local repo
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HTTPClient is hoisted here in case you'd like to use a different client
+// instance. by default we just use http.DefaultClient
+var HTTPClient = http.DefaultClient
+
+// Client makes requests to the JSON API of a qri node
+type Client struct {
+	addr       string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the qri node API at addr, eg:
+// "http://localhost:2503"
+func NewClient(addr string) *Client {
+	return &Client{
+		addr:       strings.TrimSuffix(addr, "/"),
+		httpClient: HTTPClient,
+	}
+}
+
+// envelope is the wrapper the api writes around all JSON responses
+type envelope struct {
+	Meta struct {
+		Code    int    `json:"code"`
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	} `json:"meta"`
+	Data       json.RawMessage `json:"data"`
+	Pagination struct {
+		Total int `json:"total"`
+	} `json:"pagination"`
+}
+
+// request describes a single api call
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   io.Reader
+	// contentType of body, defaults to application/json
+	contentType string
+}
+
+// do performs an api request, decoding response data into res. res may be
+// nil if response data isn't needed
+func (c *Client) do(r request, res interface{}) (*envelope, error) {
+	u := c.addr + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+
+	req, err := http.NewRequest(r.method, u, r.body)
+	if err != nil {
+		return nil, err
+	}
+	if r.body != nil {
+		if r.contentType == "" {
+			r.contentType = "application/json"
+		}
+		req.Header.Set("Content-Type", r.contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	env := &envelope{}
+	if err := json.NewDecoder(resp.Body).Decode(env); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("qri api: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("qri api: decoding response: %s", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if env.Meta.Error != "" {
+			return nil, fmt.Errorf("qri api: %s", env.Meta.Error)
+		}
+		return nil, fmt.Errorf("qri api: %s", resp.Status)
+	}
+
+	if res != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, res); err != nil {
+			return nil, fmt.Errorf("qri api: decoding response data: %s", err)
+		}
+	}
+	return env, nil
+}
+
+// refPath converts a dataset reference string into an api path, eg:
+// "peer/ds@/ipfs/Qm..." becomes "/peer/ds/at/ipfs/Qm..."
+func refPath(ref string) string {
+	return "/" + strings.Replace(strings.TrimPrefix(ref, "/"), "@", "/at", 1)
+}
@@ -0,0 +1,259 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/rev"
+)
+
+// apiResponse writes a response envelope like the qri api does
+func apiResponse(w http.ResponseWriter, code int, env map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(env)
+}
+
+func TestRefPath(t *testing.T) {
+	cases := []struct {
+		ref, expect string
+	}{
+		{"peer/cities", "/peer/cities"},
+		{"me/cities@/ipfs/QmFoo", "/me/cities/at/ipfs/QmFoo"},
+		{"/peer/cities", "/peer/cities"},
+	}
+	for _, c := range cases {
+		if got := refPath(c.ref); got != c.expect {
+			t.Errorf("refPath(%q) mismatch. expected: %q, got: %q", c.ref, c.expect, got)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	var got *http.Request
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		apiResponse(w, http.StatusOK, map[string]interface{}{
+			"meta": map[string]interface{}{"code": 200},
+			"data": []repo.DatasetRef{{Peername: "peer", Name: "cities"}},
+		})
+	}))
+	defer s.Close()
+
+	cli := NewClient(s.URL)
+	res := []repo.DatasetRef{}
+	if err := cli.List(&lib.ListParams{Peername: "peer", Term: "cit", Limit: 10, Offset: 20}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if got.URL.Path != "/list/peer" {
+		t.Errorf("path mismatch. expected: %q, got: %q", "/list/peer", got.URL.Path)
+	}
+	if q := got.URL.Query(); q.Get("term") != "cit" || q.Get("page") != "3" || q.Get("pageSize") != "10" {
+		t.Errorf("query mismatch. got: %q", got.URL.RawQuery)
+	}
+	if len(res) != 1 || res[0].AliasString() != "peer/cities" {
+		t.Errorf("result mismatch. got: %v", res)
+	}
+}
+
+func TestGet(t *testing.T) {
+	var path string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		apiResponse(w, http.StatusOK, map[string]interface{}{
+			"meta": map[string]interface{}{"code": 200},
+			"data": repo.DatasetRef{
+				Peername: "peer",
+				Name:     "cities",
+				Path:     "/ipfs/QmFoo",
+				Dataset:  &dataset.Dataset{Meta: &dataset.Meta{Title: "cities"}},
+			},
+		})
+	}))
+	defer s.Close()
+
+	cli := NewClient(s.URL + "/")
+	res := &lib.GetResult{}
+	if err := cli.Get(&lib.GetParams{Path: "peer/cities@/ipfs/QmFoo"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/peer/cities/at/ipfs/QmFoo" {
+		t.Errorf("path mismatch. expected: %q, got: %q", "/peer/cities/at/ipfs/QmFoo", path)
+	}
+	if res.Ref == nil || res.Ref.Path != "/ipfs/QmFoo" {
+		t.Errorf("expected result ref to be set, got: %v", res.Ref)
+	}
+	if res.Dataset == nil || res.Dataset.Meta == nil || res.Dataset.Meta.Title != "cities" {
+		t.Errorf("expected result dataset to be set, got: %v", res.Dataset)
+	}
+}
+
+func TestSave(t *testing.T) {
+	var (
+		got  *http.Request
+		sent = &dataset.Dataset{}
+	)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		json.NewDecoder(r.Body).Decode(sent)
+		apiResponse(w, http.StatusOK, map[string]interface{}{
+			"meta": map[string]interface{}{"code": 200, "message": "transform output"},
+			"data": repo.DatasetRef{Peername: "peer", Name: "cities", Path: "/ipfs/QmFoo"},
+		})
+	}))
+	defer s.Close()
+
+	cli := NewClient(s.URL)
+	out := &bytes.Buffer{}
+	p := &lib.SaveParams{
+		Ref:          "peer/cities",
+		Title:        "update title",
+		Dataset:      &dataset.Dataset{Meta: &dataset.Meta{Title: "cities"}},
+		DryRun:       true,
+		ShouldRender: true,
+		ScriptOutput: out,
+	}
+	res := &repo.DatasetRef{}
+	if err := cli.Save(p, res); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Method != "POST" || got.URL.Path != "/save/peer/cities" {
+		t.Errorf("request mismatch. got: %s %s", got.Method, got.URL.Path)
+	}
+	if q := got.URL.Query(); q.Get("dry_run") != "true" || q.Get("no_render") != "" {
+		t.Errorf("query mismatch. got: %q", got.URL.RawQuery)
+	}
+	if sent.Commit == nil || sent.Commit.Title != "update title" {
+		t.Errorf("expected commit title to be sent, got: %v", sent.Commit)
+	}
+	if p.Dataset.Commit != nil {
+		t.Error("save shouldn't modify the param dataset")
+	}
+	if out.String() != "transform output" {
+		t.Errorf("script output mismatch. expected: %q, got: %q", "transform output", out.String())
+	}
+	if res.Path != "/ipfs/QmFoo" {
+		t.Errorf("result path mismatch. expected: %q, got: %q", "/ipfs/QmFoo", res.Path)
+	}
+
+	if err := cli.Save(&lib.SaveParams{BodyPath: "body.csv"}, res); err == nil {
+		t.Error("expected saving a body path to error")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	var got *http.Request
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		apiResponse(w, http.StatusOK, map[string]interface{}{
+			"meta": map[string]interface{}{"code": 200},
+			"data": lib.RemoveResponse{Ref: "peer/cities", NumDeleted: -1},
+		})
+	}))
+	defer s.Close()
+
+	cli := NewClient(s.URL)
+	res := &lib.RemoveResponse{}
+	if err := cli.Remove(&lib.RemoveParams{Ref: "peer/cities", Revision: rev.Rev{Field: "ds", Gen: 1}}, res); err == nil {
+		t.Error("expected removing a single revision to error")
+	}
+	if err := cli.Remove(&lib.RemoveParams{Ref: "peer/cities", Revision: rev.NewAllRevisions()}, res); err != nil {
+		t.Fatal(err)
+	}
+	if got.URL.Path != "/remove/peer/cities" || got.URL.Query().Get("all") != "true" {
+		t.Errorf("request mismatch. got: %s", got.URL)
+	}
+	if res.Ref != "peer/cities" {
+		t.Errorf("result ref mismatch. expected: %q, got: %q", "peer/cities", res.Ref)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	var got *http.Request
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		apiResponse(w, http.StatusOK, map[string]interface{}{
+			"meta": map[string]interface{}{"code": 200},
+			"data": "ok",
+		})
+	}))
+	defer s.Close()
+
+	cli := NewClient(s.URL)
+	res := &repo.DatasetRef{}
+	if err := cli.Publish(&lib.PublicationParams{Ref: "peer/cities", RemoteName: "registry"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != "POST" || got.URL.Path != "/publish/peer/cities" || got.URL.Query().Get("remote") != "registry" {
+		t.Errorf("request mismatch. got: %s %s", got.Method, got.URL)
+	}
+	if !res.Published {
+		t.Error("expected result to be published")
+	}
+
+	if err := cli.Unpublish(&lib.PublicationParams{Ref: "peer/cities"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != "DELETE" {
+		t.Errorf("method mismatch. expected: DELETE, got: %s", got.Method)
+	}
+	if res.Published {
+		t.Error("expected result to be unpublished")
+	}
+}
+
+func TestLogPage(t *testing.T) {
+	var path string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		apiResponse(w, http.StatusOK, map[string]interface{}{
+			"meta":       map[string]interface{}{"code": 200},
+			"data":       []repo.DatasetRef{{Peername: "peer", Name: "cities", Path: "/ipfs/QmFoo"}},
+			"pagination": map[string]interface{}{"total": 12},
+		})
+	}))
+	defer s.Close()
+
+	cli := NewClient(s.URL)
+	res := &lib.LogPage{}
+	if err := cli.LogPage(&lib.LogParams{Ref: "peer/cities"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/history/peer/cities" {
+		t.Errorf("path mismatch. expected: %q, got: %q", "/history/peer/cities", path)
+	}
+	if res.Total != 12 || len(res.Versions) != 1 {
+		t.Errorf("result mismatch. expected 1 of 12 versions, got: %d of %d", len(res.Versions), res.Total)
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/plain" {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("bad gateway"))
+			return
+		}
+		apiResponse(w, http.StatusInternalServerError, map[string]interface{}{
+			"meta": map[string]interface{}{"code": 500, "error": "repo: not found"},
+		})
+	}))
+	defer s.Close()
+
+	cli := NewClient(s.URL)
+	err := cli.Get(&lib.GetParams{Path: "peer/missing"}, &lib.GetResult{})
+	if err == nil || err.Error() != "qri api: repo: not found" {
+		t.Errorf("error mismatch. expected: %q, got: %v", "qri api: repo: not found", err)
+	}
+	err = cli.Get(&lib.GetParams{Path: "plain"}, &lib.GetResult{})
+	if err == nil || err.Error() != "qri api: 502 Bad Gateway" {
+		t.Errorf("error mismatch. expected: %q, got: %v", "qri api: 502 Bad Gateway", err)
+	}
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/rev"
+)
+
+// List gets a page of dataset references. Setting Peername or ProfileID lists
+// a peer's datasets, Published lists only published datasets
+func (c *Client) List(p *lib.ListParams, res *[]repo.DatasetRef) error {
+	path := "/list"
+	if p.Published {
+		path = "/publish/"
+	} else if p.ProfileID != "" {
+		path = "/list/" + p.ProfileID.String()
+	} else if p.Peername != "" {
+		path = "/list/" + p.Peername
+	}
+
+	q := pageQuery(p)
+	if p.Term != "" {
+		q.Set("term", p.Term)
+	}
+
+	refs := []repo.DatasetRef{}
+	if _, err := c.do(request{method: "GET", path: path, query: q}, &refs); err != nil {
+		return err
+	}
+	*res = refs
+	return nil
+}
+
+// Get fetches a dataset. Only Path & UseFSI params are supported
+func (c *Client) Get(p *lib.GetParams, res *lib.GetResult) error {
+	if p.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+
+	q := url.Values{}
+	if p.UseFSI {
+		q.Set("fsi", "true")
+	}
+
+	ref := &repo.DatasetRef{}
+	if _, err := c.do(request{method: "GET", path: refPath(p.Path), query: q}, ref); err != nil {
+		return err
+	}
+	res.Ref = ref
+	res.Dataset = ref.Dataset
+	return nil
+}
+
+// Save creates a new dataset version. The node reads nothing from the local
+// filesystem, so all changes must be supplied in the Dataset param. BodyPath,
+// FilePaths & Attachments aren't supported
+func (c *Client) Save(p *lib.SaveParams, res *repo.DatasetRef) error {
+	if p.BodyPath != "" || len(p.FilePaths) > 0 || len(p.Attachments) > 0 {
+		return fmt.Errorf("saving files by path isn't supported over the api, set Dataset instead")
+	}
+
+	ds := &dataset.Dataset{}
+	if p.Dataset != nil {
+		ds.Assign(p.Dataset)
+	}
+	if p.Title != "" || p.Message != "" {
+		if ds.Commit == nil {
+			ds.Commit = &dataset.Commit{}
+		}
+		if p.Title != "" {
+			ds.Commit.Title = p.Title
+		}
+		if p.Message != "" {
+			ds.Commit.Message = p.Message
+		}
+	}
+
+	data, err := json.Marshal(ds)
+	if err != nil {
+		return err
+	}
+
+	path := "/save"
+	if p.Ref != "" {
+		path = "/save" + refPath(p.Ref)
+	}
+
+	q := url.Values{}
+	setBool(q, "private", p.Private)
+	setBool(q, "dry_run", p.DryRun)
+	setBool(q, "return_body", p.ReturnBody)
+	setBool(q, "force", p.Force)
+	setBool(q, "no_render", !p.ShouldRender)
+	setBool(q, "fsi", p.ReadFSI || p.WriteFSI)
+	if len(p.Secrets) > 0 {
+		secrets, err := json.Marshal(p.Secrets)
+		if err != nil {
+			return err
+		}
+		q.Set("secrets", string(secrets))
+	}
+
+	ref := repo.DatasetRef{}
+	env, err := c.do(request{method: "POST", path: path, query: q, body: bytes.NewReader(data)}, &ref)
+	if err != nil {
+		return err
+	}
+	if p.ScriptOutput != nil && env.Meta.Message != "" {
+		p.ScriptOutput.Write([]byte(env.Meta.Message))
+	}
+	*res = ref
+	return nil
+}
+
+// Remove deletes a dataset. The api always removes all versions of a dataset,
+// so Revision must be all generations
+func (c *Client) Remove(p *lib.RemoveParams, res *lib.RemoveResponse) error {
+	if p.Ref == "" {
+		return fmt.Errorf("reference is required")
+	}
+	if p.Revision.Gen != rev.AllGenerations {
+		return fmt.Errorf("only removing all revisions is supported over the api")
+	}
+	if p.Soft {
+		return fmt.Errorf("moving datasets to the trash isn't supported over the api")
+	}
+
+	q := url.Values{}
+	q.Set("all", "true")
+	setBool(q, "unlink", p.Unlink)
+	setBool(q, "files", p.DeleteFSIFiles)
+	setBool(q, "keep_files", p.KeepFiles)
+
+	_, err := c.do(request{method: "POST", path: "/remove" + refPath(p.Ref), query: q}, res)
+	return err
+}
+
+// pageQuery converts list params to the page & pageSize query params the api
+// reads
+func pageQuery(p *lib.ListParams) url.Values {
+	q := url.Values{}
+	if p.OrderBy != "" {
+		q.Set("orderBy", p.OrderBy)
+	}
+	if p.Limit > 0 {
+		q.Set("pageSize", strconv.Itoa(p.Limit))
+		q.Set("page", strconv.Itoa(p.Offset/p.Limit+1))
+	}
+	return q
+}
+
+// setBool adds a "true" query param when v is set
+func setBool(q url.Values, key string, v bool) {
+	if v {
+		q.Set(key, "true")
+	}
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+)
+
+// Log gets a page of dataset history
+func (c *Client) Log(p *lib.LogParams, res *[]repo.DatasetRef) error {
+	page := &lib.LogPage{}
+	if err := c.LogPage(p, page); err != nil {
+		return err
+	}
+	*res = page.Versions
+	return nil
+}
+
+// LogPage gets a page of dataset history, along with the total number of
+// versions
+func (c *Client) LogPage(p *lib.LogParams, res *lib.LogPage) error {
+	if p.Ref == "" {
+		return fmt.Errorf("reference is required")
+	}
+
+	q := pageQuery(&p.ListParams)
+	if !p.NewerThan.IsZero() {
+		q.Set("after", p.NewerThan.Format(time.RFC3339))
+	}
+	if !p.OlderThan.IsZero() {
+		q.Set("before", p.OlderThan.Format(time.RFC3339))
+	}
+
+	versions := []repo.DatasetRef{}
+	env, err := c.do(request{method: "GET", path: "/history" + refPath(p.Ref), query: q}, &versions)
+	if err != nil {
+		return err
+	}
+	res.Versions = versions
+	res.Total = env.Pagination.Total
+	return nil
+}
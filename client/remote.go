@@ -0,0 +1,40 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+)
+
+// Publish asks the node to push a dataset to a remote
+func (c *Client) Publish(p *lib.PublicationParams, res *repo.DatasetRef) error {
+	return c.publication("POST", p, res)
+}
+
+// Unpublish asks the node to remove a dataset from a remote
+func (c *Client) Unpublish(p *lib.PublicationParams, res *repo.DatasetRef) error {
+	return c.publication("DELETE", p, res)
+}
+
+// publication makes a /publish request. the api doesn't return the published
+// reference, so res is the parsed param reference with updated publish status
+func (c *Client) publication(method string, p *lib.PublicationParams, res *repo.DatasetRef) error {
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return fmt.Errorf("invalid reference: %s", err)
+	}
+
+	q := url.Values{}
+	if p.RemoteName != "" {
+		q.Set("remote", p.RemoteName)
+	}
+
+	if _, err := c.do(request{method: method, path: "/publish" + refPath(p.Ref), query: q}, nil); err != nil {
+		return err
+	}
+	ref.Published = method == "POST"
+	*res = ref
+	return nil
+}
@@ -25,18 +25,46 @@ func NewAddCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 Add retrieves a dataset owned by another peer and adds it to your repo. 
 The dataset reference of the dataset will remain the same, including 
 the name of the peer that originally added the dataset. You must have 
-` + "`qri connect`" + ` running in another terminal to use this command.`,
+` + "`qri connect`" + ` running in another terminal to use this command.
+
+Use --verify to re-hash every block of the added dataset once it's fetched.
+Datasets that are missing blocks or have corrupt blocks aren't added.
+
+Use --recursive to also add the datasets the added dataset's transform reads,
+and the datasets their transforms read, skipping any you already have.
+
+Use --lazy to add a dataset without its body, which can be large. Everything
+but the body is fetched, and ` + "`qri get body`" + ` errors until the body is
+fetched with --fetch-body.`,
 		Example: `  add a dataset named their_data, owned by other_peer:
-  $ qri add other_peer/their_data`,
+  $ qri add other_peer/their_data
+
+  add every dataset listed in a file, one reference per line:
+  $ cat refs.txt | qri add --stdin
+
+  add a dataset over an unreliable connection, checking it arrived intact:
+  $ qri add --verify other_peer/their_data
+
+  add a dataset along with every dataset its transform depends on:
+  $ qri add --recursive other_peer/their_data
+
+  add a dataset without its body, then fetch the body later:
+  $ qri add --lazy other_peer/their_data
+  $ qri add --fetch-body other_peer/their_data`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := o.Complete(f); err != nil {
+			if err := o.Complete(f, args); err != nil {
 				return err
 			}
-			return o.Run(args)
+			return o.Run()
 		},
 	}
 
 	cmd.Flags().StringVar(&o.LinkDir, "link", "", "path to directory to link dataset to")
+	cmd.Flags().BoolVar(&o.Stdin, "stdin", false, "read newline-separated dataset references from stdin")
+	cmd.Flags().BoolVar(&o.Verify, "verify", false, "re-hash the added dataset to confirm it was received intact")
+	cmd.Flags().BoolVar(&o.Recursive, "recursive", false, "also add datasets referenced by the dataset's transform")
+	cmd.Flags().BoolVar(&o.Lazy, "lazy", false, "add the dataset without fetching its body")
+	cmd.Flags().BoolVar(&o.FetchBody, "fetch-body", false, "fetch the body of a dataset added with --lazy")
 
 	return cmd
 }
@@ -44,12 +72,27 @@ the name of the peer that originally added the dataset. You must have
 // AddOptions encapsulates state for the add command
 type AddOptions struct {
 	ioes.IOStreams
+	Refs            []string
 	LinkDir         string
+	Stdin           bool
+	Verify          bool
+	Recursive       bool
+	Lazy            bool
+	FetchBody       bool
 	DatasetRequests *lib.DatasetRequests
 }
 
 // Complete adds any missing configuration that can only be added just before calling Run
-func (o *AddOptions) Complete(f Factory) (err error) {
+func (o *AddOptions) Complete(f Factory, args []string) (err error) {
+	o.Refs = args
+	if o.Stdin {
+		if len(args) > 0 {
+			return errStdinArgs
+		}
+		if o.Refs, err = readStdinRefs(o.In); err != nil {
+			return err
+		}
+	}
 	if o.DatasetRequests, err = f.DatasetRequests(); err != nil {
 		return
 	}
@@ -57,37 +100,61 @@ func (o *AddOptions) Complete(f Factory) (err error) {
 }
 
 // Run adds another peer's dataset to this user's repo
-func (o *AddOptions) Run(args []string) error {
+func (o *AddOptions) Run() error {
 	o.StartSpinner()
 	defer o.StopSpinner()
 
-	if len(args) > 1 && o.LinkDir != "" {
+	if len(o.Refs) > 1 && o.LinkDir != "" {
 		return fmt.Errorf("link flag can only be used with a single reference")
 	}
+	if o.FetchBody && (o.Lazy || o.LinkDir != "" || o.Verify || o.Recursive) {
+		return fmt.Errorf("fetch-body flag can't be combined with other add flags")
+	}
 
-	for _, arg := range args {
-		if o.LinkDir != "" {
-			abs, err := filepath.Abs(o.LinkDir)
-			if err != nil {
-				return err
-			}
-			o.LinkDir = abs
+	if o.LinkDir != "" {
+		abs, err := filepath.Abs(o.LinkDir)
+		if err != nil {
+			return err
 		}
+		o.LinkDir = abs
+	}
 
-		p := &lib.AddParams{
-			Ref:     arg,
-			LinkDir: o.LinkDir,
+	if o.Stdin {
+		return eachRef(o.Out, o.ErrOut, o.Refs, o.add)
+	}
+	for _, ref := range o.Refs {
+		if err := o.add(ref); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
+func (o *AddOptions) add(ref string) error {
+	if o.FetchBody {
 		res := repo.DatasetRef{}
-		if err := o.DatasetRequests.Add(p, &res); err != nil {
+		if err := o.DatasetRequests.FetchBody(&lib.FetchBodyParams{Ref: ref}, &res); err != nil {
 			return err
 		}
+		printInfo(o.Out, "Fetched the body of dataset %s", ref)
+		return nil
+	}
+
+	p := &lib.AddParams{
+		Ref:       ref,
+		LinkDir:   o.LinkDir,
+		Lazy:      o.Lazy,
+		Verify:    o.Verify,
+		Recursive: o.Recursive,
+	}
 
-		refStr := refStringer(res)
-		fmt.Fprintf(o.Out, "\n%s", refStr.String())
-		printInfo(o.Out, "Successfully added dataset %s", arg)
+	res := repo.DatasetRef{}
+	if err := o.DatasetRequests.Add(p, &res); err != nil {
+		return err
 	}
 
+	refStr := refStringer(res)
+	fmt.Fprintf(o.Out, "\n%s", refStr.String())
+	printInfo(o.Out, "Successfully added dataset %s", ref)
 	return nil
 }
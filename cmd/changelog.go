@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewChangelogCommand creates a new `qri changelog` cobra command for
+// rendering a dataset's commit history as release notes
+func NewChangelogCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &ChangelogOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Format dataset history as a changelog",
+		Long: `
+Changelog formats the commit titles & messages of a dataset's history as text,
+newest first. Entries are grouped by commit date by default, use
+` + "`--group-by version`" + ` to give each version its own section.
+
+Limit the changelog to a range of versions with ` + "`--from`" + ` & ` + "`--to`" + `. Both
+accept a version number, counting from 1 for the first version, or a version
+path. Ranges are inclusive.`,
+		Example: `  write a markdown changelog for b5/world_bank_population:
+  $ qri changelog b5/world_bank_population > CHANGELOG.md
+
+  show changes from the third version onward, one section per version:
+  $ qri changelog --from 3 --group-by version b5/world_bank_population`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Format, "format", "md", "changelog format. currently only md is supported")
+	cmd.Flags().StringVar(&o.GroupBy, "group-by", "date", "group entries by one of: date, version")
+	cmd.Flags().StringVar(&o.From, "from", "", "oldest version to include, as a version number or path")
+	cmd.Flags().StringVar(&o.To, "to", "", "newest version to include, as a version number or path")
+
+	return cmd
+}
+
+// ChangelogOptions encapsulates state for the changelog command
+type ChangelogOptions struct {
+	ioes.IOStreams
+
+	Refs    *RefSelect
+	Format  string
+	GroupBy string
+	From    string
+	To      string
+
+	LogRequests *lib.LogRequests
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *ChangelogOptions) Complete(f Factory, args []string) (err error) {
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1); err != nil {
+		return err
+	}
+	o.LogRequests, err = f.LogRequests()
+	return
+}
+
+// Run executes the changelog command
+func (o *ChangelogOptions) Run() error {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.ChangelogParams{
+		Ref:     o.Refs.Ref(),
+		Format:  o.Format,
+		GroupBy: o.GroupBy,
+		From:    o.From,
+		To:      o.To,
+	}
+
+	var res string
+	if err := o.LogRequests.Changelog(p, &res); err != nil {
+		if err == repo.ErrEmptyRef {
+			return lib.NewError(err, "please provide a dataset reference")
+		}
+		return err
+	}
+
+	fmt.Fprint(o.Out, res)
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestChangelog(t *testing.T) {
+	if err := confirmQriNotRunning(); err != nil {
+		t.Skip(err.Error())
+	}
+
+	r := NewTestRepoRoot(t, "qri_test_changelog")
+	defer r.Delete()
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	cmdR := r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri save --body=testdata/movies/body_ten.csv --title=initial me/test_movies"); err != nil {
+		t.Fatal(err)
+	}
+	cmdR = r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri save --body=testdata/movies/body_twenty.csv --title=more_movies --message=doubled me/test_movies"); err != nil {
+		t.Fatal(err)
+	}
+
+	cmdR = r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri changelog --group-by version me/test_movies"); err != nil {
+		t.Fatal(err)
+	}
+	output := r.GetOutput()
+	for _, expect := range []string{"# Changelog: test_peer/test_movies", "## v2", "more_movies\n\ndoubled", "## v1", "initial"} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected output to contain %q. got:\n%s", expect, output)
+		}
+	}
+
+	cmdR = r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri changelog --from 2 me/test_movies"); err != nil {
+		t.Fatal(err)
+	}
+	output = r.GetOutput()
+	if !strings.Contains(output, "- more_movies (v2)") || strings.Contains(output, "(v1)") {
+		t.Errorf("expected --from 2 to only include the second version. got:\n%s", output)
+	}
+}
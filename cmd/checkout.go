@@ -16,10 +16,21 @@ import (
 func NewCheckoutCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 	o := &CheckoutOptions{IOStreams: ioStreams}
 	cmd := &cobra.Command{
-		Use:     "checkout",
-		Short:   "checkout creates a linked directory and writes dataset files to that directory",
-		Long:    ``,
-		Example: ``,
+		Use:   "checkout",
+		Short: "checkout creates a linked directory and writes dataset files to that directory",
+		Long: `
+Checkout creates a directory named for a dataset, links it to the dataset, and
+writes dataset components to the directory as files.
+
+If the directory already exists and is linked to the dataset, checkout merges
+the requested version with any local changes. Edits that don't overlap with
+the incoming version are kept. Overlapping edits are written to files with
+conflict markers for manual resolution.`,
+		Example: `  check out a dataset:
+  $ qri checkout me/annual_pop
+
+  merge a newer version into a working directory with local changes:
+  $ qri checkout me/annual_pop@/ipfs/QmZmwEvqTNKzMDxJJ7rvz67fvh6eXYTuvt5h3ahXw3tR8z`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -65,7 +76,12 @@ func (o *CheckoutOptions) Run() (err error) {
 	if pos == -1 {
 		return fmt.Errorf("expect '/' in dataset ref")
 	}
-	folderName := varName.CreateVarNameFromString(ref[pos+1:])
+	name := ref[pos+1:]
+	if at := strings.Index(name, "@"); at != -1 {
+		// checking out a specific version uses the same directory
+		name = name[:at]
+	}
+	folderName := varName.CreateVarNameFromString(name)
 
 	if err = qfs.AbsPath(&folderName); err != nil {
 		return err
@@ -76,6 +92,11 @@ func (o *CheckoutOptions) Run() (err error) {
 	if err != nil {
 		return err
 	}
+	if res != "" {
+		// checkout merged into an existing working directory
+		printSuccess(o.Out, res)
+		return nil
+	}
 	printSuccess(o.Out, "created and linked working directory %s for existing dataset", folderName)
 	return nil
 }
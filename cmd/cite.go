@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewCiteCommand creates a new `qri cite` cobra command for managing dataset
+// citations
+func NewCiteCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &CiteOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "cite",
+		Short: "Add & remove links to the sources a dataset draws from",
+		Long: `
+Cite records the sources a dataset is built from as citations in the dataset's
+meta component. Each citation has a title, a url, and the date the source was
+accessed. Citations show up in 'qri get meta' and in rendered output.
+
+Adding or removing a citation commits a new version of the dataset.`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	add := &cobra.Command{
+		Use:   "add DATASET",
+		Short: "Cite a source",
+		Example: `  # cite a source accessed today
+  $ qri cite add me/annual_pop --url https://data.worldbank.org/indicator/SP.POP.TOTL --title "World Bank Population"
+
+  # cite a source accessed on a specific date
+  $ qri cite add me/annual_pop --url https://example.com/pop.csv --title "Population" --accessed 2019-10-01`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Add()
+		},
+	}
+
+	remove := &cobra.Command{
+		Use:     "remove DATASET",
+		Aliases: []string{"rm"},
+		Short:   "Remove a citation",
+		Example: `  # remove a citation by url
+  $ qri cite remove me/annual_pop --url https://example.com/pop.csv`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Remove()
+		},
+	}
+
+	add.Flags().StringVar(&o.URL, "url", "", "url of the cited source")
+	add.Flags().StringVar(&o.Title, "title", "", "title of the cited source")
+	add.Flags().StringVar(&o.Accessed, "accessed", "", "date the source was accessed as YYYY-MM-DD, defaults to today")
+	remove.Flags().StringVar(&o.URL, "url", "", "url of the citation to remove")
+
+	cmd.AddCommand(add, remove)
+	return cmd
+}
+
+// CiteOptions encapsulates state for the cite command
+type CiteOptions struct {
+	ioes.IOStreams
+
+	Ref      string
+	URL      string
+	Title    string
+	Accessed string
+
+	DatasetRequests *lib.DatasetRequests
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *CiteOptions) Complete(f Factory, args []string) (err error) {
+	if len(args) > 0 {
+		o.Ref = args[0]
+	}
+	o.DatasetRequests, err = f.DatasetRequests()
+	return
+}
+
+// Validate checks that all user input is valid
+func (o *CiteOptions) Validate() error {
+	if o.Ref == "" {
+		return lib.NewError(lib.ErrBadArgs, "please provide a dataset to cite sources on, for example:\n    $ qri cite add me/dataset_name --url https://example.com --title \"Example\"\nsee `qri cite --help` for more details")
+	}
+	if o.URL == "" {
+		return lib.NewError(lib.ErrBadArgs, "please provide the url of the citation with --url")
+	}
+	return nil
+}
+
+// Add executes the cite add command
+func (o *CiteOptions) Add() error {
+	p := &lib.CiteParams{
+		Ref:      o.Ref,
+		Title:    o.Title,
+		URL:      o.URL,
+		Accessed: o.Accessed,
+	}
+	res := repo.DatasetRef{}
+	if err := o.DatasetRequests.Cite(p, &res); err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "added citation %s to %s", o.URL, res.AliasString())
+	return nil
+}
+
+// Remove executes the cite remove command
+func (o *CiteOptions) Remove() error {
+	p := &lib.CiteParams{
+		Ref:    o.Ref,
+		URL:    o.URL,
+		Remove: true,
+	}
+	res := repo.DatasetRef{}
+	if err := o.DatasetRequests.Cite(p, &res); err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "removed citation %s from %s", o.URL, res.AliasString())
+	return nil
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qri-io/qri/lib"
+)
+
+func TestCiteValidate(t *testing.T) {
+	cases := []struct {
+		ref, url string
+		msg      string
+	}{
+		{"", "https://example.com", "please provide a dataset to cite sources on, for example:\n    $ qri cite add me/dataset_name --url https://example.com --title \"Example\"\nsee `qri cite --help` for more details"},
+		{"me/ds", "", "please provide the url of the citation with --url"},
+		{"me/ds", "https://example.com", ""},
+	}
+	for i, c := range cases {
+		opt := &CiteOptions{Ref: c.ref, URL: c.url}
+		err := opt.Validate()
+		if c.msg == "" {
+			if err != nil {
+				t.Errorf("case %d, unexpected error: %s", i, err)
+			}
+			continue
+		}
+		libErr, ok := err.(lib.Error)
+		if !ok {
+			t.Errorf("case %d, expected a lib.Error. got: %v", i, err)
+			continue
+		}
+		if libErr.Message() != c.msg {
+			t.Errorf("case %d, message mismatch. want: %q, got: %q", i, c.msg, libErr.Message())
+		}
+	}
+}
@@ -46,14 +46,17 @@ func Execute() {
 	// root context
 	ctx := context.Background()
 
-	root := NewQriCommand(ctx, EnvPathFactory, gen.NewCryptoSource(), ioes.NewStdIOStreams())
+	root, opt := newQriCommand(ctx, EnvPathFactory, gen.NewCryptoSource(), ioes.NewStdIOStreams())
 	// If the subcommand hits an error, don't show usage or the error, since we'll show
 	// the error message below, on our own. Usage is still shown if the subcommand
 	// is missing command-line arguments.
 	root.SilenceUsage = true
 	root.SilenceErrors = true
 	// Execute the subcommand
-	if err := root.Execute(); err != nil {
+	err := root.Execute()
+	// release resources held by the instance, like store lock files
+	opt.Teardown()
+	if err != nil {
 		printErr(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -814,6 +814,49 @@ func TestDiffRevisions(t *testing.T) {
 	}
 }
 
+// Test that diffing the size of two versions counts blocks the right version adds
+func TestDiffSize(t *testing.T) {
+	if err := confirmQriNotRunning(); err != nil {
+		t.Skip(err.Error())
+	}
+
+	// To keep hashes consistent, artificially specify the timestamp by overriding
+	// the dsfs.Timestamp func
+	prev := dsfs.Timestamp
+	defer func() { dsfs.Timestamp = prev }()
+	dsfs.Timestamp = func() time.Time { return time.Date(2001, 01, 01, 01, 01, 01, 01, time.UTC) }
+
+	r := NewTestRepoRoot(t, "qri_test_diff_size")
+	defer r.Delete()
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	cmdR := r.CreateCommandRunner(ctx)
+	err := executeCommand(cmdR, "qri save --body=testdata/movies/body_ten.csv me/test_movies")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	cmdR = r.CreateCommandRunner(ctx)
+	err = executeCommand(cmdR, "qri save --body=testdata/movies/body_twenty.csv me/test_movies")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	cmdR = r.CreateCommandRunner(ctx)
+	err = executeCommand(cmdR, "qri diff --size me/test_movies")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	output := r.GetOutput()
+	expect := "5 new blocks, 4.2 kB\n"
+	if output != expect {
+		t.Errorf("error, did not match actual:\n\"%v\"\nexpect:\n\"%v\"\n", output, expect)
+	}
+}
+
 // Test that diffing a dataset with only one version produces an error
 func TestDiffOnlyOneRevision(t *testing.T) {
 	if err := confirmQriNotRunning(); err != nil {
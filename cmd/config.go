@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/config/migrate"
 	"github.com/qri-io/qri/lib"
 	"github.com/spf13/cobra"
 )
@@ -42,7 +44,13 @@ https://github.com/qri-io/qri/blob/master/config/readme.md`,
   $ qri config set api.port 4444
 
   # disable rpc connections:
-  $ qri config set rpc.enabled false`,
+  $ qri config set rpc.enabled false
+
+  # check your config for errors
+  $ qri config validate
+
+  # upgrade your config to the latest revision
+  $ qri config migrate`,
 	}
 
 	get := &cobra.Command{
@@ -104,12 +112,52 @@ field checkout: https://github.com/qri-io/qri/blob/master/config/readme.md`,
 		},
 	}
 
+	validate := &cobra.Command{
+		Use:   "validate [PATH]",
+		Short: "check a configuration file for errors",
+		Long: `validate loads a configuration file & checks it for errors, listing
+each problem it finds by field. With no arguments validate checks the config
+of your qri repo. validate exits with a non-zero status if any checks fail.`,
+		Example: `  # validate your repo's config
+  qri config validate
+
+  # validate a config file before using it
+  qri config validate ./my_config.yaml`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return o.Validate(f.QriRepoPath(), args)
+		},
+	}
+
+	migrations := &cobra.Command{
+		Use:   "migrate",
+		Short: "run pending configuration migrations",
+		Long: `migrate upgrades your repo's configuration file to the latest revision,
+listing each migration it runs. qri runs migrations automatically when it
+starts, migrate is for running them explicitly, or with --dry-run for
+checking which migrations are pending without changing anything.`,
+		Example: `  # list pending migrations
+  qri config migrate --dry-run
+
+  # run pending migrations
+  qri config migrate`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return o.Migrate(f.QriRepoPath())
+		},
+	}
+
 	get.Flags().BoolVar(&o.WithPrivateKeys, "with-private-keys", false, "include private keys in export")
 	get.Flags().BoolVarP(&o.Concise, "concise", "c", false, "print output without indentation, only applies to json format")
 	get.Flags().StringVarP(&o.Format, "format", "f", "yaml", "data format to export. either json or yaml")
 	get.Flags().StringVarP(&o.Output, "output", "o", "", "path to export to")
 	cmd.AddCommand(get)
 	cmd.AddCommand(set)
+	cmd.AddCommand(validate)
+	migrations.Flags().BoolVar(&o.DryRun, "dry-run", false, "list pending migrations without running them")
+	cmd.AddCommand(migrations)
 
 	return cmd
 }
@@ -122,6 +170,7 @@ type ConfigOptions struct {
 	WithPrivateKeys bool
 	Concise         bool
 	Output          string
+	DryRun          bool
 
 	inst           *lib.Instance
 	ConfigMethods  *lib.ConfigMethods
@@ -225,6 +274,72 @@ func (o *ConfigOptions) Set(args []string) (err error) {
 	return nil
 }
 
+// Validate checks a configuration file for errors, defaulting to the config
+// file within repoPath
+func (o *ConfigOptions) Validate(repoPath string, args []string) error {
+	path := filepath.Join(repoPath, "config.yaml")
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	cfg, err := config.ReadFromFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %s", err)
+	}
+
+	var errs []error
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, cfg.CrossFieldErrors()...)
+
+	if len(errs) == 0 {
+		printSuccess(o.Out, "config is valid: %s", path)
+		return nil
+	}
+
+	for _, err := range errs {
+		fmt.Fprintf(o.ErrOut, "  %s\n", err)
+	}
+	return fmt.Errorf("config is invalid: %s. %d problem(s) found", path, len(errs))
+}
+
+// Migrate runs any pending migrations on the config file within repoPath,
+// writing the migrated config back to the file
+func (o *ConfigOptions) Migrate(repoPath string) error {
+	path := filepath.Join(repoPath, "config.yaml")
+	cfg, err := config.ReadFromFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %s", err)
+	}
+
+	pending := migrate.Pending(cfg)
+	if len(pending) == 0 {
+		printSuccess(o.Out, "config is up to date at revision %d", cfg.Revision)
+		return nil
+	}
+
+	if o.DryRun {
+		fmt.Fprintf(o.Out, "%d pending migration(s):\n", len(pending))
+		for _, m := range pending {
+			fmt.Fprintf(o.Out, "  %s\n", m.Name)
+		}
+		return nil
+	}
+
+	if _, err := migrate.RunMigrations(o.IOStreams, cfg); err != nil {
+		return err
+	}
+	if err := cfg.WriteToFile(path); err != nil {
+		return err
+	}
+	for _, m := range pending {
+		fmt.Fprintf(o.Out, "  ran %s\n", m.Name)
+	}
+	printSuccess(o.Out, "config migrated to revision %d", cfg.Revision)
+	return nil
+}
+
 func setPhotoPath(m *lib.ProfileMethods, proppath, filepath string) error {
 	f, err := loadFileIfPath(filepath)
 	if err != nil {
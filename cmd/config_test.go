@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/config"
+)
+
+func TestConfigValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestConfigValidate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := config.DefaultConfigForTesting().WriteToFile(filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := config.DefaultConfigForTesting()
+	bad.API.Enabled = true
+	bad.Webapp.Enabled = true
+	bad.Webapp.Port = bad.API.Port
+	badPath := filepath.Join(dir, "bad.yaml")
+	if err := bad.WriteToFile(badPath); err != nil {
+		t.Fatal(err)
+	}
+
+	streams, _, out, errs := ioes.NewTestIOStreams()
+	o := &ConfigOptions{IOStreams: streams}
+
+	if err := o.Validate(dir, []string{}); err != nil {
+		t.Errorf("expected repo config to be valid. got: %s", err)
+	}
+	if !strings.Contains(out.String(), "config is valid") {
+		t.Errorf("expected success message. got: %q", out.String())
+	}
+
+	if err := o.Validate(dir, []string{badPath}); err == nil {
+		t.Error("expected invalid config to error")
+	}
+	if !strings.Contains(errs.String(), "webapp.port: port") {
+		t.Errorf("expected field-level error output. got: %q", errs.String())
+	}
+
+	if err := o.Validate(dir, []string{filepath.Join(dir, "missing.yaml")}); err == nil {
+		t.Error("expected missing config file to error")
+	}
+}
+
+func TestConfigMigrate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestConfigMigrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	cfg := config.DefaultConfigForTesting()
+	cfg.Revision = 0
+	if err := cfg.WriteToFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	streams, _, out, _ := ioes.NewTestIOStreams()
+	o := &ConfigOptions{IOStreams: streams, DryRun: true}
+	if err := o.Migrate(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "1 pending migration(s)") {
+		t.Errorf("expected dry run to list pending migrations. got: %q", out.String())
+	}
+	if got, err := config.ReadFromFile(path); err != nil {
+		t.Fatal(err)
+	} else if got.Revision != 0 {
+		t.Errorf("expected dry run not to modify config. got revision: %d", got.Revision)
+	}
+
+	out.Reset()
+	o.DryRun = false
+	if err := o.Migrate(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "ran zero to one") {
+		t.Errorf("expected migrations that ran to be listed. got: %q", out.String())
+	}
+	if got, err := config.ReadFromFile(path); err != nil {
+		t.Fatal(err)
+	} else if got.Revision != config.CurrentConfigRevision {
+		t.Errorf("expected config to be written at revision %d. got: %d", config.CurrentConfigRevision, got.Revision)
+	}
+
+	out.Reset()
+	if err := o.Migrate(dir); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "config is up to date") {
+		t.Errorf("expected up to date message. got: %q", out.String())
+	}
+}
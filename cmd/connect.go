@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/api"
@@ -96,8 +100,23 @@ func (o *ConnectOptions) Complete(f Factory, args []string) (err error) {
 
 // Run executes the connect command with currently configured state
 func (o *ConnectOptions) Run() (err error) {
+	// stop serving on interrupt. the server drains in-flight requests before
+	// Serve returns, and the instance is torn down only after that
+	ctx, cancel := context.WithCancel(o.inst.Context())
+	defer cancel()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		select {
+		case <-sigs:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	s := api.New(o.inst)
-	err = s.Serve(o.inst.Context())
+	err = s.Serve(ctx)
 	if err != nil && err.Error() == "http: Server closed" {
 		return nil
 	}
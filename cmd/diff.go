@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
+	"github.com/dustin/go-humanize"
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/lib"
 	"github.com/spf13/cobra"
@@ -45,7 +47,16 @@ Each change has a path that locates it within the document`,
   $ qri diff a.json b.json
 
   diff a json & csv file
-  $ qri diff some_table.csv b.json`,
+  $ qri diff some_table.csv b.json
+
+  count changes to each component, without listing them:
+  $ qri diff --summary me/annual_pop
+
+  diff two large bodies, holding at most 512MB of data in memory:
+  $ qri diff --memory-limit 512MB body me/annual_pop
+
+  show how many new bytes one version adds over another:
+  $ qri diff --size me/annual_pop@/ipfs/QmA me/annual_pop@/ipfs/QmB`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -58,7 +69,9 @@ Each change has a path that locates it within the document`,
 	}
 
 	cmd.Flags().StringVarP(&o.Format, "format", "f", "pretty", "output format. one of [json,pretty]")
-	cmd.Flags().BoolVar(&o.Summary, "summary", false, "just output the summary")
+	cmd.Flags().BoolVar(&o.Summary, "summary", false, "only output counts of changes to each component")
+	cmd.Flags().BoolVar(&o.Size, "size", false, "output the count & size of blocks the right version adds over the left")
+	cmd.Flags().StringVar(&o.MemoryLimit, "memory-limit", "", "approximate memory limit for diffing bodies, eg: 512MB. larger bodies spill to disk & diff in segments")
 
 	return cmd
 }
@@ -71,6 +84,9 @@ type DiffOptions struct {
 	Selector string
 	Format   string
 	Summary  bool
+	Size     bool
+	// MemoryLimit is a human-readable size, eg: "512MB"
+	MemoryLimit string
 
 	DatasetRequests *lib.DatasetRequests
 }
@@ -96,10 +112,18 @@ func (o *DiffOptions) Run() (err error) {
 	printRefSelect(o.Out, o.Refs)
 
 	p := &lib.DiffParams{
-		Selector: o.Selector,
+		Selector:  o.Selector,
+		Summarize: o.Summary,
+	}
+	if o.MemoryLimit != "" {
+		limit, err := humanize.ParseBytes(o.MemoryLimit)
+		if err != nil {
+			return fmt.Errorf("invalid memory limit %q: %s", o.MemoryLimit, err)
+		}
+		p.MemoryLimit = int64(limit)
 	}
 
-	if o.Refs.IsLinked() {
+	if o.Refs.IsLinked() && !o.Size {
 		return o.RunLinkedFilesys(p)
 	} else if len(o.Refs.RefList()) == 1 {
 		p.RightPath = o.Refs.Ref()
@@ -108,17 +132,51 @@ func (o *DiffOptions) Run() (err error) {
 		p.RightPath = o.Refs.RefList()[1]
 	}
 
+	if o.Size {
+		return o.RunSize(p)
+	}
+
 	res := &lib.DiffResponse{}
 	if err = o.DatasetRequests.Diff(p, res); err != nil {
 		return err
 	}
 
+	return o.printDiff(res)
+}
+
+// printDiff writes a diff response in the configured format
+func (o *DiffOptions) printDiff(res *lib.DiffResponse) error {
+	if o.Summary {
+		if o.Format == "json" {
+			return json.NewEncoder(o.Out).Encode(res.Summary)
+		}
+		printDiffSummary(o.Out, res.Summary)
+		return nil
+	}
+
 	if o.Format == "json" {
-		json.NewEncoder(o.Out).Encode(res.Diff)
-		return
+		return json.NewEncoder(o.Out).Encode(res.Diff)
+	}
+	return printDiff(o.Out, res)
+}
+
+// RunSize prints the incremental block count & size of one version over another
+func (o *DiffOptions) RunSize(p *lib.DiffParams) (err error) {
+	sp := &lib.DeltaSizeParams{
+		LeftPath:  p.LeftPath,
+		RightPath: p.RightPath,
+	}
+	res := &lib.DAGDelta{}
+	if err = o.DatasetRequests.DeltaSize(sp, res); err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		return json.NewEncoder(o.Out).Encode(res)
 	}
 
-	return printDiff(o.Out, res, o.Summary)
+	fmt.Fprintf(o.Out, "%d new blocks, %s\n", res.Blocks, humanize.Bytes(res.Size))
+	return nil
 }
 
 // RunLinkedFilesys executes diff against a linked directory
@@ -169,5 +227,5 @@ func (o *DiffOptions) RunLinkedFilesys(p *lib.DiffParams) (err error) {
 	if err = o.DatasetRequests.MergeDiffs(&mergedResponse, responses, components); err != nil {
 		return err
 	}
-	return printDiff(o.Out, &mergedResponse, o.Summary)
+	return o.printDiff(&mergedResponse)
 }
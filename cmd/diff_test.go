@@ -84,6 +84,24 @@ func TestDiffRun(t *testing.T) {
 				Format:   "json",
 			},
 			`[{"type":"update","path":"/title","value":"example city data","originalValue":"example movie data"}]
+`,
+		},
+		{"diff summary",
+			&DiffOptions{
+				Refs:     NewListOfRefSelects([]string{"me/movies", "me/cities"}),
+				Selector: "meta",
+				Summary:  true,
+			},
+			"meta: 0 added, 0 deleted, 1 modified, 0 moved\n",
+		},
+		{"diff summary json output",
+			&DiffOptions{
+				Refs:     NewListOfRefSelects([]string{"me/movies", "me/cities"}),
+				Selector: "meta",
+				Summary:  true,
+				Format:   "json",
+			},
+			`{"meta":{"inserts":0,"deletes":0,"updates":1,"moves":0}}
 `,
 		},
 	}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCommand creates a new `qri doctor` cobra command for diagnosing
+// problems with a qri setup
+func NewDoctorCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &DoctorOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check your qri setup for problems",
+		Long: `
+Doctor runs a series of checks on your qri setup & reports problems it finds,
+with hints on how to fix them. Doctor checks:
+
+  config     the config is valid, with no conflicting fields
+  store      the configured store can be reached
+  repo       every dataset in the repo is in the store
+  registry   the configured registry can be reached
+  bootstrap  p2p bootstrap peers can be reached
+  ports      ports used by enabled services are available
+
+Each check passes, warns or fails. Warnings affect some qri features, failures
+mean qri won't work until they're fixed. Doctor exits with a non-zero status
+if any check fails.`,
+		Example: `  check your setup:
+  $ qri doctor`,
+		Annotations: map[string]string{
+			"group": "other",
+		},
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	return cmd
+}
+
+// DoctorOptions encapsulates state for the doctor command
+type DoctorOptions struct {
+	ioes.IOStreams
+
+	DoctorMethods *lib.DoctorMethods
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *DoctorOptions) Complete(f Factory) (err error) {
+	o.DoctorMethods, err = f.DoctorMethods()
+	return
+}
+
+// Run executes the doctor command
+func (o *DoctorOptions) Run() error {
+	m := o.DoctorMethods
+	checks := []func(*bool, *lib.DiagnosticCheck) error{
+		m.CheckConfig,
+		m.CheckStore,
+		m.CheckRepo,
+		m.CheckRegistry,
+		m.CheckBootstrap,
+		m.CheckPorts,
+	}
+
+	failed, warned := 0, 0
+	for _, check := range checks {
+		res := lib.DiagnosticCheck{}
+		if err := check(nil, &res); err != nil {
+			return err
+		}
+		switch res.Status {
+		case lib.CheckFail:
+			failed++
+		case lib.CheckWarn:
+			warned++
+		}
+		printDiagnosticCheck(o.Out, res)
+	}
+
+	fmt.Fprintln(o.Out)
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed, %d warning(s)", failed, warned)
+	}
+	if warned > 0 {
+		printWarning(o.Out, "no problems that stop qri from working, %d warning(s)", warned)
+		return nil
+	}
+	printSuccess(o.Out, "no problems found")
+	return nil
+}
+
+// printDiagnosticCheck writes a check result, indenting multi-line messages
+// & hints under the check name
+func printDiagnosticCheck(w io.Writer, c lib.DiagnosticCheck) {
+	status := color.New(color.FgGreen).Sprint(c.Status)
+	switch c.Status {
+	case lib.CheckWarn:
+		status = color.New(color.FgYellow).Sprint(c.Status)
+	case lib.CheckFail:
+		status = color.New(color.FgRed).Sprint(c.Status)
+	}
+
+	indent := "\n" + strings.Repeat(" ", 18)
+	fmt.Fprintf(w, "%s  %-10s  %s\n", status, c.Name, strings.Replace(c.Message, "\n", indent, -1))
+	if c.Hint != "" {
+		fmt.Fprintf(w, "%shint: %s\n", strings.Repeat(" ", 18), c.Hint)
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/qri-io/ioes"
+)
+
+func TestDoctorRun(t *testing.T) {
+	streams, _, out, _ := ioes.NewTestIOStreams()
+	setNoColor(true)
+
+	f, err := NewTestFactory()
+	if err != nil {
+		t.Fatalf("error creating new test factory: %s", err)
+	}
+	// keep checks off the network
+	f.config.Registry.Location = ""
+	f.config.P2P.Enabled = false
+	f.config.API.Enabled = false
+	f.config.RPC.Enabled = false
+	f.config.Webapp.Enabled = false
+
+	o := &DoctorOptions{IOStreams: streams}
+	if err := o.Complete(f); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Run(); err != nil {
+		t.Fatalf("expected warnings not to fail doctor. got: %s", err)
+	}
+	for _, expect := range []string{
+		"pass  config      config is valid",
+		"warn  registry    no registry configured, publishing & search are disabled",
+		"                  hint: run `qri config set registry.location https://registry.qri.cloud`",
+		"no problems that stop qri from working, 1 warning(s)",
+	} {
+		if !strings.Contains(out.String(), expect) {
+			t.Errorf("expected output to contain %q. got:\n%s", expect, out.String())
+		}
+	}
+
+	out.Reset()
+	f.config.API.Enabled = true
+	f.config.RPC.Enabled = true
+	f.config.RPC.Port = f.config.API.Port
+	expect := "1 check(s) failed, 1 warning(s)"
+	if err := o.Run(); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+	if !strings.Contains(out.String(), "fail  config      rpc.port: port") {
+		t.Errorf("expected failed config check in output. got:\n%s", out.String())
+	}
+}
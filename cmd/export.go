@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/lib"
@@ -24,12 +26,21 @@ Export gets datasets out of qri. By default it exports the dataset body, as ` +
 To export to a specific directory, use the --output flag.
 
 If you want an empty dataset that can be filled in with details to create a
-new dataset, use --blank.`,
+new dataset, use --blank.
+
+File attachments are listed by path in dataset metadata. Exporting with --zip
+also copies attachment files into an ` + "`attachments`" + ` directory of the archive.`,
 		Example: `  # export dataset
   qri export me/annual_pop
 
   # export to a specific directory
-  qri export -o ~/new_directory me/annual_pop`,
+  qri export -o ~/new_directory me/annual_pop
+
+  # export the body as csv, with column titles in the first row
+  qri export --format csv --header me/annual_pop
+
+  # export the body as newline-delimited json, one entry per line
+  qri export --format ndjson me/annual_pop`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -43,8 +54,9 @@ new dataset, use --blank.`,
 
 	cmd.Flags().BoolVarP(&o.Blank, "blank", "", false, "export a blank dataset YAML file, overrides all other flags except output")
 	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "path to write to, default is current directory")
-	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "format for the exported dataset, such as native, json, xlsx. default: json")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "format for the exported dataset, such as native, json, ndjson, csv, xlsx, arrow, feather. default: json")
 	cmd.Flags().BoolVarP(&o.Zipped, "zip", "z", false, "export as a zip file")
+	cmd.Flags().BoolVar(&o.Header, "header", false, "write column titles from the schema as the first row of csv exports")
 
 	return cmd
 }
@@ -58,14 +70,16 @@ type ExportOptions struct {
 	Output string
 	Format string
 	Zipped bool
+	Header bool
 
-	UsingRPC       bool
-	ExportRequests *lib.ExportRequests
+	UsingRPC        bool
+	ExportRequests  *lib.ExportRequests
+	DatasetRequests *lib.DatasetRequests
 }
 
 // Complete adds any missing configuration that can only be added just before calling Run
 func (o *ExportOptions) Complete(f Factory, args []string) (err error) {
-	if o.Refs, err = GetCurrentRefSelect(f, args, 1); err != nil {
+	if o.Refs, err = GetOptionalRefSelect(f, args, 1); err != nil {
 		if err != repo.ErrEmptyRef {
 			return err
 		}
@@ -73,7 +87,10 @@ func (o *ExportOptions) Complete(f Factory, args []string) (err error) {
 	if f.RPC() != nil {
 		return usingRPCError("export")
 	}
-	o.ExportRequests, err = f.ExportRequests()
+	if o.ExportRequests, err = f.ExportRequests(); err != nil {
+		return err
+	}
+	o.DatasetRequests, err = f.DatasetRequests()
 	return err
 }
 
@@ -101,6 +118,15 @@ func (o *ExportOptions) Run() error {
 		Output: path,
 		Format: format,
 		Zipped: o.Zipped,
+		Header: o.Header,
+	}
+
+	if o.Header {
+		if exportFormat(format, path) != "csv" {
+			printWarning(o.ErrOut, "--header only applies to csv exports, exporting without a header row")
+		} else if o.bodyFormat() == "json" {
+			printWarning(o.ErrOut, "--header doesn't apply to datasets with json bodies, exporting without a header row")
+		}
 	}
 
 	var fileWritten string
@@ -113,6 +139,31 @@ func (o *ExportOptions) Run() error {
 	return nil
 }
 
+// bodyFormat gives the format of the exported dataset's body, or an empty
+// string if it can't be read. export reports load errors itself
+func (o *ExportOptions) bodyFormat() string {
+	res := &lib.GetResult{}
+	if err := o.DatasetRequests.Get(&lib.GetParams{Path: o.Refs.Ref(), Selector: "structure"}, res); err != nil {
+		return ""
+	}
+	if res.Dataset == nil || res.Dataset.Structure == nil {
+		return ""
+	}
+	return res.Dataset.Structure.Format
+}
+
+// exportFormat gives the format an export will be written in, matching the
+// defaults lib.ExportRequests applies
+func exportFormat(format, output string) string {
+	if format != "" {
+		return format
+	}
+	if ext := filepath.Ext(output); ext != "" {
+		return strings.TrimPrefix(ext, ".")
+	}
+	return "json"
+}
+
 const blankYamlDataset = `# This file defines a qri dataset. Change this file, save it, then from a terminal run:
 # $ qri save --file=dataset.yaml
 # For more info check out https://qri.io/docs
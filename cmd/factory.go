@@ -37,6 +37,7 @@ type Factory interface {
 	SearchMethods() (*lib.SearchMethods, error)
 	RenderRequests() (*lib.RenderRequests, error)
 	FSIMethods() (*lib.FSIMethods, error)
+	DoctorMethods() (*lib.DoctorMethods, error)
 }
 
 // PathFactory is a function that returns paths to qri & ipfs repos
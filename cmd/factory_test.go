@@ -191,6 +191,11 @@ func (t TestFactory) FSIMethods() (*lib.FSIMethods, error) {
 	return lib.NewFSIMethods(t.inst), nil
 }
 
+// DoctorMethods generates a lib.DoctorMethods from internal state
+func (t TestFactory) DoctorMethods() (*lib.DoctorMethods, error) {
+	return lib.NewDoctorMethods(t.inst), nil
+}
+
 // SearchMethods generates a lib.SearchMethods from internal state
 func (t TestFactory) SearchMethods() (*lib.SearchMethods, error) {
 	return lib.NewSearchMethods(t.inst), nil
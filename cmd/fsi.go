@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"path/filepath"
 
 	"github.com/qri-io/ioes"
@@ -36,6 +35,19 @@ func NewFSICommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 	unlink := &cobra.Command{
 		Use:   "unlink",
 		Short: "unlink a .qri-ref",
+		Long: `
+Unlink breaks the connection between a dataset and a working directory created
+by qri init or qri checkout. The .qri-ref link file is removed from the
+directory and the dataset is no longer marked as linked in your repo. Files in
+the directory and versions of the dataset are left as they are.
+
+Run unlink without a dataset name from within a linked directory to unlink that
+directory.`,
+		Example: `  unlink the dataset linked to the current working directory:
+  $ qri fsi unlink
+
+  unlink a dataset from whichever directory it's linked to:
+  $ qri fsi unlink me/annual_pop`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := o.Complete(f, args); err != nil {
 				return err
@@ -60,9 +72,6 @@ type FSIOptions struct {
 // Complete adds any missing configuration that can only be added just before
 // calling Run
 func (o *FSIOptions) Complete(f Factory, args []string) (err error) {
-	if len(args) < 1 {
-		return fmt.Errorf("please provide the name of a dataset")
-	}
 	if o.Refs, err = GetCurrentRefSelect(f, args, -1); err != nil {
 		return
 	}
@@ -108,8 +117,7 @@ func (o *FSIOptions) Unlink() error {
 		}
 
 		if err := o.FSIMethods.Unlink(p, &res); err != nil {
-			printErr(o.ErrOut, err)
-			return nil
+			return err
 		}
 
 		printSuccess(o.Out, "unlinked: %s", res)
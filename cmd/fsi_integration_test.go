@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -142,6 +143,26 @@ run ` + "`qri save`" + ` to commit this dataset
 		t.Errorf("qri status (-want +got):\n%s", diff)
 	}
 
+	// Status as JSON, check that added components are listed with their files.
+	if err := fr.ExecCommand("qri status --json"); err != nil {
+		t.Fatalf(err.Error())
+	}
+	status := StatusJSON{}
+	if err := json.Unmarshal([]byte(fr.GetCommandOutput()), &status); err != nil {
+		t.Fatalf("expected json status output: %s", err)
+	}
+	if status.Clean {
+		t.Error("expected json status to be dirty")
+	}
+	gotComponents := []string{}
+	for _, si := range status.Components {
+		gotComponents = append(gotComponents, fmt.Sprintf("%s %s %s", si.Type, si.Component, filepath.Base(si.SourceFile)))
+	}
+	expectComponents := []string{"add meta meta.json", "add body body.csv"}
+	if diff := cmp.Diff(expectComponents, gotComponents); diff != "" {
+		t.Errorf("qri status --json components (-want +got):\n%s", diff)
+	}
+
 	// Save the new dataset.
 	if err := fr.ExecCommand("qri save"); err != nil {
 		t.Fatalf(err.Error())
@@ -183,7 +204,7 @@ func TestCheckoutSimpleStatus(t *testing.T) {
 
 	// Verify the directory contains the files that we expect.
 	dirContents := listDirectory(workDir)
-	expectContents := []string{".qri-ref", "body.json", "schema.json"}
+	expectContents := []string{".qri-ref", ".qri-version", "body.json", "schema.json"}
 	if diff := cmp.Diff(dirContents, expectContents); diff != "" {
 		t.Errorf("directory contents (-want +got):\n%s", diff)
 	}
@@ -262,7 +283,7 @@ func TestCheckoutWithStructure(t *testing.T) {
 
 	// Verify the directory contains the files that we expect.
 	dirContents := listDirectory(workPath)
-	expectContents := []string{".qri-ref", "body.csv", "dataset.json", "meta.json", "schema.json"}
+	expectContents := []string{".qri-ref", ".qri-version", "body.csv", "dataset.json", "meta.json", "schema.json"}
 	if diff := cmp.Diff(dirContents, expectContents); diff != "" {
 		t.Errorf("directory contents (-want +got):\n%s", diff)
 	}
@@ -363,7 +384,7 @@ func TestCheckoutAndModifySchema(t *testing.T) {
 
 	// Verify the directory contains the files that we expect.
 	dirContents := listDirectory(workPath)
-	expectContents := []string{".qri-ref", "body.csv", "dataset.json", "schema.json"}
+	expectContents := []string{".qri-ref", ".qri-version", "body.csv", "dataset.json", "schema.json"}
 	if diff := cmp.Diff(dirContents, expectContents); diff != "" {
 		t.Errorf("directory contents (-want +got):\n%s", diff)
 	}
@@ -826,7 +847,7 @@ func TestRestoreDeleteComponent(t *testing.T) {
 
 	// Verify the directory contains the files that we expect.
 	dirContents := listDirectory(workDir)
-	expectContents := []string{".qri-ref", "body.csv", "dataset.json", "schema.json"}
+	expectContents := []string{".qri-ref", ".qri-version", "body.csv", "dataset.json", "schema.json"}
 	if diff := cmp.Diff(dirContents, expectContents); diff != "" {
 		t.Errorf("directory contents (-want +got):\n%s", diff)
 	}
@@ -984,6 +1005,48 @@ run ` + "`qri save`" + ` to commit this dataset
 	}
 }
 
+// Test validate checks a linked directory's body against its schema
+func TestValidateLinkedDirectory(t *testing.T) {
+	fr := NewFSITestRunner(t, "qri_test_validate_linked_directory")
+	defer fr.Delete()
+
+	fr.CreateAndChdirToWorkDir("validate_me")
+
+	if err := fr.ExecCommand("qri init --name validate_me --format json"); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	schema := `{"type":"array","items":{"type":"array","items":[{"type":"integer"},{"type":"string"}]}}`
+	if err := ioutil.WriteFile("schema.json", []byte(schema), os.ModePerm); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if err := ioutil.WriteFile("body.json", []byte(`[[1,"one"],[2,"two"]]`), os.ModePerm); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	if err := fr.ExecCommand("qri validate"); err != nil {
+		t.Fatalf(err.Error())
+	}
+	if output := fr.GetCommandOutput(); !strings.Contains(output, "All good!") {
+		t.Errorf("expected valid body. got: %s", output)
+	}
+
+	if err := ioutil.WriteFile("body.json", []byte(`[[1,"one"],["two","two"]]`), os.ModePerm); err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// validating the directory by path must fail on the invalid row
+	fr.ChdirToRoot()
+	err := fr.ExecCommand("qri validate validate_me")
+	expectErr := "dataset is invalid. 1 problem(s) found"
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+	if output := fr.GetCommandOutput(); !strings.Contains(output, "row 1, column 0:") {
+		t.Errorf("expected output to describe the invalid row & column. got: %s", output)
+	}
+}
+
 func parseRefFromSave(output string) string {
 	pos := strings.Index(output, "saved: ")
 	ref := output[pos+7:]
@@ -26,7 +26,7 @@ func TestFSILinkingCommands(t *testing.T) {
 
 	// Verify the directory contains the files that we expect, including .qri-ref link file
 	dirContents := listDirectory(pwd)
-	expectContents := []string{".qri-ref", "body.csv", "meta.json", "schema.json"}
+	expectContents := []string{".qri-ref", ".qri-version", "body.csv", "meta.json", "schema.json"}
 	if diff := cmp.Diff(dirContents, expectContents); diff != "" {
 		t.Errorf("directory contents (-want +got):\n%s", diff)
 	}
@@ -55,4 +55,20 @@ func TestFSILinkingCommands(t *testing.T) {
 	if diff := cmp.Diff(dirContents, expectContents); diff != "" {
 		t.Errorf("directory contents (-want +got):\n%s", diff)
 	}
+
+	// Unlink from within the linked directory, without naming the dataset
+	if err := runner.ExecCommand("qri fsi unlink"); err != nil {
+		t.Errorf("unlinking working directory: %s", err.Error())
+	}
+
+	dirContents = listDirectory(pwd)
+	expectContents = []string{"body.csv", "meta.json", "schema.json"}
+	if diff := cmp.Diff(dirContents, expectContents); diff != "" {
+		t.Errorf("directory contents after unlinking (-want +got):\n%s", diff)
+	}
+
+	// Unlinking a dataset that isn't linked is an error
+	if err := runner.ExecCommand("qri fsi unlink me/save_and_unlink"); err == nil {
+		t.Error("expected unlinking an unlinked dataset to error")
+	}
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"regexp"
 
 	util "github.com/qri-io/apiutil"
@@ -38,7 +40,27 @@ dataset and its fields.`,
   qri get structure.length me/annual_pop
 
   # print the dataset body size for two different datasets
-  qri get structure.length me/annual_pop me/annual_gdp`,
+  qri get structure.length me/annual_pop me/annual_gdp
+
+  # print an estimate of the number of body entries without reading the
+  # whole body
+  qri get structure.entries --estimate me/annual_pop
+
+  # print body rows where the population is over one million
+  qri get body me/annual_pop --where "pop > 1000000"
+
+  # filters combine comparisons like a sql WHERE clause, strings are
+  # single-quoted
+  qri get body me/annual_pop --where "pop > 1000000 AND country = 'canada'"
+
+  # print the meta of every dataset listed in a file, one reference per line
+  cat refs.txt | qri get meta --stdin
+
+  # print the transform script to the console
+  qri get transform.script me/annual_pop
+
+  # write the transform script to annual_pop.star for editing
+  qri get transform.script me/annual_pop --output annual_pop`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -59,6 +81,10 @@ dataset and its fields.`,
 	cmd.Flags().IntVar(&o.PageSize, "page-size", -1, "for body, limit how many entries to get per page")
 	cmd.Flags().IntVar(&o.Page, "page", -1, "for body, page at which to get entries")
 	cmd.Flags().BoolVarP(&o.All, "all", "a", true, "for body, whether to get all entries")
+	cmd.Flags().BoolVar(&o.Stdin, "stdin", false, "read newline-separated dataset references from stdin")
+	cmd.Flags().BoolVar(&o.Estimate, "estimate", false, "for structure.entries, estimate from a sample of the body if the count isn't recorded")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "for transform.script, file to write the script to")
+	cmd.Flags().StringVar(&o.Where, "where", "", "for body, only get rows matching an expression written like a sql WHERE clause")
 
 	return cmd
 }
@@ -78,6 +104,20 @@ type GetOptions struct {
 	Pretty    bool
 	HasPretty bool
 
+	// Estimate allows estimating unrecorded entry counts
+	Estimate bool
+
+	// Where filters body rows
+	Where string
+
+	// Output is a file path to write a transform script to. the extension is
+	// added from the transform syntax if missing
+	Output string
+
+	// Stdin reads references from stdin into StdinRefs instead of using Refs
+	Stdin     bool
+	StdinRefs []string
+
 	DatasetRequests *lib.DatasetRequests
 }
 
@@ -96,7 +136,23 @@ func (o *GetOptions) Complete(f Factory, args []string) (err error) {
 			args = args[1:]
 		}
 	}
-	if o.Refs, err = GetCurrentRefSelect(f, args, -1); err != nil {
+	if o.Output != "" {
+		if o.Selector != "transform.script" && o.Selector != "tf.script" {
+			return fmt.Errorf("can only use --output flag when getting transform.script")
+		}
+		if o.Stdin {
+			return fmt.Errorf("can't combine --output with --stdin")
+		}
+	}
+
+	if o.Stdin {
+		if len(args) > 0 {
+			return errStdinArgs
+		}
+		if o.StdinRefs, err = readStdinRefs(o.In); err != nil {
+			return
+		}
+	} else if o.Refs, err = GetCurrentRefSelect(f, args, -1); err != nil {
 		return
 	}
 
@@ -119,6 +175,9 @@ func (o *GetOptions) Complete(f Factory, args []string) (err error) {
 		if !o.All {
 			return fmt.Errorf("can only use --all flag when getting body")
 		}
+		if o.Where != "" {
+			return fmt.Errorf("can only use --where flag when getting body")
+		}
 	}
 
 	return nil
@@ -126,8 +185,52 @@ func (o *GetOptions) Complete(f Factory, args []string) (err error) {
 
 // Run executes the get command
 func (o *GetOptions) Run() (err error) {
+	if o.Stdin {
+		return eachRef(o.Out, o.ErrOut, o.StdinRefs, func(ref string) error {
+			res, err := o.get(ref, false)
+			if err != nil {
+				return err
+			}
+			printInfo(o.Out, "%s:", ref)
+			o.Out.Write(append(res.Bytes, '\n'))
+			return nil
+		})
+	}
+
 	printRefSelect(o.Out, o.Refs)
+	// TODO(dlong): Restore ability to `get` from multiple datasets at once.
+	res, err := o.get(o.Refs.Ref(), o.Refs.IsLinked())
+	if err != nil {
+		return err
+	}
+
+	if res.Count != nil && !res.Count.Exact {
+		printWarning(o.ErrOut, "entry count is estimated from a sample of the body")
+	}
+
+	if o.Output != "" {
+		path := o.Output
+		if filepath.Ext(path) == "" {
+			syntax := ""
+			if res.Dataset != nil && res.Dataset.Transform != nil {
+				syntax = res.Dataset.Transform.Syntax
+			}
+			path += lib.TransformScriptExtension(syntax)
+		}
+		if err = ioutil.WriteFile(path, res.Bytes, 0644); err != nil {
+			return err
+		}
+		printSuccess(o.Out, "wrote transform script to %s", path)
+		return nil
+	}
+
+	buf := bytes.NewBuffer(res.Bytes)
+	buf.Write([]byte{'\n'})
+	printToPager(o.Out, buf)
+	return
+}
 
+func (o *GetOptions) get(ref string, useFSI bool) (*lib.GetResult, error) {
 	// Pretty maps to a key in the FormatConfig map.
 	var fc dataset.FormatConfig
 	if o.HasPretty {
@@ -138,24 +241,22 @@ func (o *GetOptions) Run() (err error) {
 
 	// convert Page and PageSize to Limit and Offset
 	page := util.NewPage(o.Page, o.PageSize)
-	// TODO(dlong): Restore ability to `get` from multiple datasets at once.
 	p := lib.GetParams{
-		Path:         o.Refs.Ref(),
+		Path:         ref,
 		Selector:     o.Selector,
-		UseFSI:       o.Refs.IsLinked(),
+		UseFSI:       useFSI,
 		Format:       o.Format,
 		FormatConfig: fc,
 		Offset:       page.Offset(),
 		Limit:        page.Limit(),
 		All:          o.All,
+
+		EstimateCount: o.Estimate,
+		Where:         o.Where,
 	}
-	res := lib.GetResult{}
-	if err = o.DatasetRequests.Get(&p, &res); err != nil {
-		return err
+	res := &lib.GetResult{}
+	if err := o.DatasetRequests.Get(&p, res); err != nil {
+		return nil, err
 	}
-
-	buf := bytes.NewBuffer(res.Bytes)
-	buf.Write([]byte{'\n'})
-	printToPager(o.Out, buf)
-	return
+	return res, nil
 }
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/qri-io/ioes"
@@ -66,3 +70,53 @@ func TestGetComplete(t *testing.T) {
 		ioReset(in, out, errs)
 	}
 }
+
+func TestGetTransformScriptOutput(t *testing.T) {
+	if err := confirmQriNotRunning(); err != nil {
+		t.Skip(err.Error())
+	}
+
+	r := NewTestRepoRoot(t, "qri_test_get_transform_script")
+	defer r.Delete()
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	cmdR := r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri save --file=testdata/movies/tf_123.star me/test_ds"); err != nil {
+		t.Fatal(err)
+	}
+
+	expect, err := ioutil.ReadFile("testdata/movies/tf_123.star")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmdR = r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri get transform.script me/test_ds"); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.GetOutput(); !strings.Contains(got, string(expect)) {
+		t.Errorf("expected script in output, got:\n%s", got)
+	}
+
+	// extension is added from the transform syntax
+	output := filepath.Join(r.rootPath, "script")
+	cmdR = r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri get tf.script me/test_ds --output "+output); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(output + ".star")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(expect) {
+		t.Errorf("script file mismatch.\nexpected:\n%s\ngot:\n%s", expect, got)
+	}
+
+	cmdR = r.CreateCommandRunner(ctx)
+	err = executeCommand(cmdR, "qri get meta me/test_ds --output "+output)
+	if err == nil || err.Error() != "can only use --output flag when getting transform.script" {
+		t.Errorf("expected --output with another selector to error, got: %v", err)
+	}
+}
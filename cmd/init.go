@@ -32,7 +32,8 @@ func NewInitCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 
 	cmd.Flags().StringVar(&o.Name, "name", "", "name of the dataset")
 	cmd.Flags().StringVar(&o.Format, "format", "", "format of dataset")
-	cmd.Flags().StringVar(&o.SourceBodyPath, "source-body-path", "", "path to the body file")
+	cmd.Flags().StringVar(&o.SourceBodyPath, "source-body-path", "", "path to the body file, xlsx and parquet files are converted to csv")
+	cmd.Flags().StringVar(&o.Sheet, "sheet", "", "name of the sheet to use when the source body is a workbook with many sheets")
 
 	return cmd
 }
@@ -44,6 +45,7 @@ type InitOptions struct {
 	Name           string
 	Format         string
 	SourceBodyPath string
+	Sheet          string
 	Mkdir          string
 
 	DatasetRequests *lib.DatasetRequests
@@ -80,7 +82,8 @@ func (o *InitOptions) Run() (err error) {
 		o.Name = inputText(o.ErrOut, o.In, "Name of new dataset", suggestedName)
 	}
 
-	if o.Format == "" {
+	// the source body determines the format
+	if o.Format == "" && o.SourceBodyPath == "" {
 		o.Format = inputText(o.ErrOut, o.In, "Format of dataset, csv or json", "csv")
 	}
 
@@ -90,6 +93,7 @@ func (o *InitOptions) Run() (err error) {
 		Format:         o.Format,
 		Name:           o.Name,
 		SourceBodyPath: o.SourceBodyPath,
+		Sheet:          o.Sheet,
 	}
 	var name string
 	if err = o.FSIMethods.InitDataset(p, &name); err != nil {
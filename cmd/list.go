@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	util "github.com/qri-io/apiutil"
 	"github.com/qri-io/dataset"
@@ -35,6 +36,12 @@ must have ` + "`qri connect`" + ` running in a separate terminal window.`,
   # show datasets with the substring "new" in their name
   qri list new
 
+  # show datasets that haven't been updated in the last year
+  qri list --older-than 1y
+
+  # show datasets updated since the start of 2019
+  qri list --newer-than 2019-01-01
+
   # to view the list of your peer's dataset,
   # in one terminal window:
   qri connect
@@ -58,6 +65,8 @@ must have ` + "`qri connect`" + ` running in a separate terminal window.`,
 	cmd.Flags().BoolVarP(&o.Published, "published", "p", false, "list only published datasets")
 	cmd.Flags().BoolVarP(&o.ShowNumVersions, "num-versions", "n", false, "show number of versions")
 	cmd.Flags().StringVar(&o.Peername, "peer", "", "peer whose datasets to list")
+	cmd.Flags().StringVar(&o.NewerThan, "newer-than", "", "only list datasets last committed after a date or duration ago, eg: 30d, 2019-01-31")
+	cmd.Flags().StringVar(&o.OlderThan, "older-than", "", "only list datasets last committed before a date or duration ago, eg: 1y, 2019-01-31")
 
 	return cmd
 }
@@ -73,6 +82,10 @@ type ListOptions struct {
 	Peername        string
 	Published       bool
 	ShowNumVersions bool
+	NewerThan       string
+	OlderThan       string
+
+	newerThan, olderThan time.Time
 
 	DatasetRequests *lib.DatasetRequests
 }
@@ -82,6 +95,19 @@ func (o *ListOptions) Complete(f Factory, args []string) (err error) {
 	if len(args) > 0 {
 		o.Term = args[0]
 	}
+
+	now := time.Now()
+	if o.NewerThan != "" {
+		if o.newerThan, err = lib.ParseTimeBound(o.NewerThan, now); err != nil {
+			return lib.NewError(lib.ErrBadArgs, fmt.Sprintf("invalid --newer-than value: %s", err))
+		}
+	}
+	if o.OlderThan != "" {
+		if o.olderThan, err = lib.ParseTimeBound(o.OlderThan, now); err != nil {
+			return lib.NewError(lib.ErrBadArgs, fmt.Sprintf("invalid --older-than value: %s", err))
+		}
+	}
+
 	o.DatasetRequests, err = f.DatasetRequests()
 	return
 }
@@ -99,6 +125,8 @@ func (o *ListOptions) Run() (err error) {
 		Offset:          page.Offset(),
 		Published:       o.Published,
 		ShowNumVersions: o.ShowNumVersions,
+		NewerThan:       o.newerThan,
+		OlderThan:       o.olderThan,
 	}
 	if err = o.DatasetRequests.List(p, &refs); err != nil {
 		return err
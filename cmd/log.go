@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 
 	util "github.com/qri-io/apiutil"
@@ -27,7 +28,10 @@ created the version) and a message explaining what changed. Log prints these
 details in order of occurrence, starting with the most recent known version, 
 working backwards in time.`,
 		Example: `  show log for the dataset b5/precip:
-  $ qri log b5/precip`,
+  $ qri log b5/precip
+
+  show one version per line, with a short hash, timestamp & title:
+  $ qri log --oneline b5/precip`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -42,6 +46,7 @@ working backwards in time.`,
 	// cmd.Flags().StringVarP(&o.Format, "format", "f", "", "set output format [json]")
 	cmd.Flags().IntVar(&o.PageSize, "page-size", 25, "page size of results, default 25")
 	cmd.Flags().IntVar(&o.Page, "page", 1, "page number of results, default 1")
+	cmd.Flags().BoolVar(&o.Oneline, "oneline", false, "print each version on a single line")
 
 	return cmd
 }
@@ -52,6 +57,7 @@ type LogOptions struct {
 
 	PageSize int
 	Page     int
+	Oneline  bool
 	Refs     *RefSelect
 
 	LogRequests *lib.LogRequests
@@ -89,6 +95,14 @@ func (o *LogOptions) Run() error {
 		return err
 	}
 
+	if o.Oneline {
+		buf := &bytes.Buffer{}
+		for _, r := range refs {
+			buf.WriteString(logOnelineStringer(r).String())
+		}
+		return printToPager(o.Out, buf)
+	}
+
 	items := make([]fmt.Stringer, len(refs))
 	for i, r := range refs {
 		items[i] = logStringer(r)
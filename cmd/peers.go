@@ -135,6 +135,45 @@ You must have ` + "`qri connect`" + ` running in another terminal.`,
 		},
 	}
 
+	gateCmd := func(use, short, list string, remove bool) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " PEER_ID",
+			Short: short,
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := o.Complete(f, args); err != nil {
+					return err
+				}
+				return o.Gate(list, remove)
+			},
+		}
+	}
+
+	block := gateCmd("block", "Prevent connections to & from a peer", "block", false)
+	block.Long = `
+Block adds a peer ID to your blocklist. Your node will never dial a blocked 
+peer, and connections from blocked peers are closed as soon as they open. 
+If your node is running, existing connections to the peer are closed 
+immediately.
+
+Blocked peers are stored in your config at p2p.blockedpeers.`
+	block.Example = `  # block a peer
+  $ qri peers block QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn`
+
+	unblock := gateCmd("unblock", "Remove a peer from your blocklist", "block", true)
+
+	allow := gateCmd("allow", "Restrict connections to a list of allowed peers", "allow", false)
+	allow.Long = `
+Allow adds a peer ID to your allowlist. Once your allowlist contains any
+peers, your node will only connect to allowed peers. Blocked peers are never
+connected to, even if they're also allowed.
+
+Allowed peers are stored in your config at p2p.allowedpeers.`
+	allow.Example = `  # only connect to a single peer
+  $ qri peers allow QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn`
+
+	disallow := gateCmd("disallow", "Remove a peer from your allowlist", "allow", true)
+
 	info.Flags().BoolVarP(&o.Verbose, "verbose", "v", false, "show verbose profile info")
 	info.Flags().StringVarP(&o.Format, "format", "", "yaml", "output format. formats: yaml, json")
 
@@ -144,7 +183,7 @@ You must have ` + "`qri connect`" + ` running in another terminal.`,
 	// list.Flags().IntVar(&o.PageSize, "page-size", 200, "max page size number of peers to show, default 200")
 	// list.Flags().IntVar(&o.Page, "page", 1, "page number of peers, default 1")
 
-	cmd.AddCommand(info, list, connect, disconnect)
+	cmd.AddCommand(info, list, connect, disconnect, block, unblock, allow, disallow)
 
 	return cmd
 }
@@ -161,8 +200,9 @@ type PeersOptions struct {
 	PageSize int
 	Page     int
 
-	UsingRPC     bool
-	PeerRequests *lib.PeerRequests
+	UsingRPC      bool
+	PeerRequests  *lib.PeerRequests
+	ConfigMethods *lib.ConfigMethods
 }
 
 // Complete adds any missing configuration that can only be added just before calling Run
@@ -171,7 +211,10 @@ func (o *PeersOptions) Complete(f Factory, args []string) (err error) {
 		o.Peername = args[0]
 	}
 	o.UsingRPC = f.RPC() != nil
-	o.PeerRequests, err = f.PeerRequests()
+	if o.PeerRequests, err = f.PeerRequests(); err != nil {
+		return
+	}
+	o.ConfigMethods, err = f.ConfigMethods()
 	return
 }
 
@@ -279,3 +322,29 @@ func (o *PeersOptions) Disconnect() (err error) {
 	printSuccess(o.Out, "disconnected")
 	return nil
 }
+
+// Gate adds or removes a peer from the block or allow list
+func (o *PeersOptions) Gate(list string, remove bool) (err error) {
+	p := &lib.PeerGateParams{
+		PeerID: o.Peername,
+		List:   list,
+		Remove: remove,
+	}
+	changed := false
+	if err = o.ConfigMethods.SetPeerGate(p, &changed); err != nil {
+		return err
+	}
+
+	listName := map[string]string{"block": "blocklist", "allow": "allowlist"}[list]
+	switch {
+	case !changed && remove:
+		printInfo(o.Out, "%s is not in your %s", o.Peername, listName)
+	case !changed:
+		printInfo(o.Out, "%s is already in your %s", o.Peername, listName)
+	case remove:
+		printSuccess(o.Out, "removed %s from your %s", o.Peername, listName)
+	default:
+		printSuccess(o.Out, "added %s to your %s", o.Peername, listName)
+	}
+	return nil
+}
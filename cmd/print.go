@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -166,24 +167,18 @@ func doesCommandExist(cmdName string) bool {
 	return true
 }
 
-func printDiff(w io.Writer, res *lib.DiffResponse, summaryOnly bool) (err error) {
+func printDiff(w io.Writer, res *lib.DiffResponse) (err error) {
 	var stats, text string
 	// TODO (b5): this reading from a package variable is pretty hacky :/
 	if color.NoColor {
 		stats = deepdiff.FormatPrettyStats(res.Stat)
-		if !summaryOnly {
-			text, err = deepdiff.FormatPretty(res.Diff)
-			if err != nil {
-				return err
-			}
+		if text, err = deepdiff.FormatPretty(res.Diff); err != nil {
+			return err
 		}
 	} else {
 		stats = deepdiff.FormatPrettyStatsColor(res.Stat)
-		if !summaryOnly {
-			text, err = deepdiff.FormatPrettyColor(res.Diff)
-			if err != nil {
-				return err
-			}
+		if text, err = deepdiff.FormatPrettyColor(res.Diff); err != nil {
+			return err
 		}
 	}
 	buf := bytes.NewBuffer([]byte(stats + "\n" + text))
@@ -191,6 +186,24 @@ func printDiff(w io.Writer, res *lib.DiffResponse, summaryOnly bool) (err error)
 	return nil
 }
 
+// printDiffSummary writes counts of changes to each component, one line per
+// component in alphabetical order
+func printDiffSummary(w io.Writer, summary map[string]*lib.DiffSummary) {
+	if len(summary) == 0 {
+		fmt.Fprintln(w, "no changes")
+		return
+	}
+	comps := make([]string, 0, len(summary))
+	for comp := range summary {
+		comps = append(comps, comp)
+	}
+	sort.Strings(comps)
+	for _, comp := range comps {
+		s := summary[comp]
+		fmt.Fprintf(w, "%s: %d added, %d deleted, %d modified, %d moved\n", comp, s.Inserts, s.Deletes, s.Updates, s.Moves)
+	}
+}
+
 func printRefSelect(w io.Writer, refset *RefSelect) {
 	if refset.IsExplicit() {
 		return
@@ -28,7 +28,10 @@ to a published dataset will be immediately visible to connected peers.
   $ qri publish -unpublish me/dataset
 
   # publish a few dataset on p2p only
-  $ qri publish --no-registry me/dataset_2`,
+  $ qri publish --no-registry me/dataset_2
+
+  # publish every dataset listed in a file, one reference per line
+  $ cat refs.txt | qri publish --stdin`,
 		Annotations: map[string]string{
 			"group": "network",
 		},
@@ -44,6 +47,7 @@ to a published dataset will be immediately visible to connected peers.
 	cmd.Flags().BoolVarP(&o.NoRegistry, "no-registry", "", false, "don't publish to registry")
 	cmd.Flags().BoolVarP(&o.NoPin, "no-pin", "", false, "don't pin dataset to registry")
 	cmd.Flags().StringVarP(&o.RemoteName, "remote", "", "", "name of remote to publish to")
+	cmd.Flags().BoolVar(&o.Stdin, "stdin", false, "read newline-separated dataset references from stdin")
 
 	return cmd
 }
@@ -57,6 +61,7 @@ type PublishOptions struct {
 	NoRegistry bool
 	NoPin      bool
 	RemoteName string
+	Stdin      bool
 
 	DatasetRequests *lib.DatasetRequests
 	RemoteMethods   *lib.RemoteMethods
@@ -65,6 +70,14 @@ type PublishOptions struct {
 // Complete adds any missing configuration that can only be added just before calling Run
 func (o *PublishOptions) Complete(f Factory, args []string) (err error) {
 	o.Refs = args
+	if o.Stdin {
+		if len(args) > 0 {
+			return errStdinArgs
+		}
+		if o.Refs, err = readStdinRefs(o.In); err != nil {
+			return err
+		}
+	}
 	if o.DatasetRequests, err = f.DatasetRequests(); err != nil {
 		return err
 	}
@@ -74,23 +87,33 @@ func (o *PublishOptions) Complete(f Factory, args []string) (err error) {
 
 // Run executes the publish command
 func (o *PublishOptions) Run() error {
-	var res repo.DatasetRef
+	if o.Stdin {
+		return eachRef(o.Out, o.ErrOut, o.Refs, o.publish)
+	}
 	for _, ref := range o.Refs {
-		p := lib.PublicationParams{
-			Ref:        ref,
-			RemoteName: o.RemoteName,
+		if err := o.publish(ref); err != nil {
+			return err
 		}
-		if o.Unpublish {
-			if err := o.RemoteMethods.Unpublish(&p, &res); err != nil {
-				return err
-			}
-			printInfo(o.Out, "unpublished dataset %s", res)
-		} else {
-			if err := o.RemoteMethods.Publish(&p, &res); err != nil {
-				return err
-			}
-			printInfo(o.Out, "published dataset %s", res)
+	}
+	return nil
+}
+
+func (o *PublishOptions) publish(ref string) error {
+	var res repo.DatasetRef
+	p := lib.PublicationParams{
+		Ref:        ref,
+		RemoteName: o.RemoteName,
+	}
+	if o.Unpublish {
+		if err := o.RemoteMethods.Unpublish(&p, &res); err != nil {
+			return err
 		}
+		printInfo(o.Out, "unpublished dataset %s", res)
+		return nil
+	}
+	if err := o.RemoteMethods.Publish(&p, &res); err != nil {
+		return err
 	}
+	printInfo(o.Out, "published dataset %s", res)
 	return nil
 }
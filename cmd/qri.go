@@ -16,6 +16,13 @@ import (
 
 // NewQriCommand represents the base command when called without any subcommands
 func NewQriCommand(ctx context.Context, pf PathFactory, generator gen.CryptoGenerator, ioStreams ioes.IOStreams) *cobra.Command {
+	cmd, _ := newQriCommand(ctx, pf, generator, ioStreams)
+	return cmd
+}
+
+// newQriCommand creates the base command, returning the options its
+// subcommands share
+func newQriCommand(ctx context.Context, pf PathFactory, generator gen.CryptoGenerator, ioStreams ioes.IOStreams) (*cobra.Command, *QriOptions) {
 	cmd := &cobra.Command{
 		Use:   "qri",
 		Short: "qri GDVCS CLI",
@@ -40,11 +47,14 @@ https://github.com/qri-io/qri/issues`,
 
 	cmd.AddCommand(
 		NewAddCommand(opt, ioStreams),
+		NewChangelogCommand(opt, ioStreams),
 		NewCheckoutCommand(opt, ioStreams),
+		NewCiteCommand(opt, ioStreams),
 		NewConfigCommand(opt, ioStreams),
 		NewConnectCommand(opt, ioStreams),
 		NewDAGCommand(opt, ioStreams),
 		NewDiffCommand(opt, ioStreams),
+		NewDoctorCommand(opt, ioStreams),
 		NewExportCommand(opt, ioStreams),
 		NewFSICommand(opt, ioStreams),
 		NewGetCommand(opt, ioStreams),
@@ -57,22 +67,27 @@ https://github.com/qri-io/qri/issues`,
 		NewRemoveCommand(opt, ioStreams),
 		NewRenameCommand(opt, ioStreams),
 		NewRenderCommand(opt, ioStreams),
+		NewRepoCommand(opt, ioStreams),
 		NewRestoreCommand(opt, ioStreams),
 		NewSaveCommand(opt, ioStreams),
+		NewSchemaCommand(opt, ioStreams),
 		NewSearchCommand(opt, ioStreams),
 		NewSetupCommand(opt, ioStreams),
+		NewSquashCommand(opt, ioStreams),
 		NewStatusCommand(opt, ioStreams),
+		NewTrashCommand(opt, ioStreams),
 		NewUseCommand(opt, ioStreams),
 		NewUpdateCommand(opt, ioStreams),
 		NewValidateCommand(opt, ioStreams),
 		NewVersionCommand(opt, ioStreams),
+		NewWhoamiCommand(opt, ioStreams),
 	)
 
 	for _, sub := range cmd.Commands() {
 		sub.SetUsageTemplate(defaultUsageTemplate)
 	}
 
-	return cmd
+	return cmd, opt
 }
 
 // QriOptions holds the Root Command State
@@ -125,6 +140,13 @@ func (o *QriOptions) Init() (err error) {
 	return
 }
 
+// Teardown releases resources held by the instance, if one was created
+func (o *QriOptions) Teardown() {
+	if o.inst != nil {
+		o.inst.Teardown()
+	}
+}
+
 // Instance returns the instance this options is using
 func (o *QriOptions) Instance() *lib.Instance {
 	if err := o.Init(); err != nil {
@@ -262,3 +284,12 @@ func (o *QriOptions) FSIMethods() (m *lib.FSIMethods, err error) {
 
 	return lib.NewFSIMethods(o.inst), nil
 }
+
+// DoctorMethods generates a lib.DoctorMethods from internal state
+func (o *QriOptions) DoctorMethods() (m *lib.DoctorMethods, err error) {
+	if err = o.Init(); err != nil {
+		return
+	}
+
+	return lib.NewDoctorMethods(o.inst), nil
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+)
+
+// pickerMaxRefs caps the number of datasets listed by the interactive picker
+const pickerMaxRefs = 50
+
+// refPicker is an optional Factory interface for interactively choosing a
+// dataset reference when a command requires one & none is provided
+type refPicker interface {
+	pickRef() (string, error)
+}
+
+// pickRef lists local datasets & asks the user to choose one. The picker only
+// runs when prompts are enabled & standard input is a terminal, returning
+// repo.ErrEmptyRef otherwise so scripted use keeps failing fast
+func (o *QriOptions) pickRef() (string, error) {
+	if o.NoPrompt || !isTerminal(o.In) {
+		return "", repo.ErrEmptyRef
+	}
+
+	dsr, err := o.DatasetRequests()
+	if err != nil {
+		return "", err
+	}
+	res := []repo.DatasetRef{}
+	if err := dsr.List(&lib.ListParams{Limit: pickerMaxRefs}, &res); err != nil {
+		return "", err
+	}
+	refs := make([]string, len(res))
+	for i, ref := range res {
+		refs[i] = ref.AliasString()
+	}
+
+	return pickDatasetRef(o.IOStreams, refs)
+}
+
+// pickDatasetRef prints a numbered list of refs & reads the number of the
+// chosen ref from the input stream
+func pickDatasetRef(streams ioes.IOStreams, refs []string) (string, error) {
+	if len(refs) == 0 {
+		return "", repo.ErrEmptyRef
+	}
+
+	printInfo(streams.ErrOut, "no dataset reference provided. choose a dataset:")
+	for i, ref := range refs {
+		printInfo(streams.ErrOut, "  %d. %s", i+1, ref)
+	}
+	printInfoNoEndline(streams.ErrOut, "dataset number: ")
+
+	line, err := bufio.NewReader(streams.In).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", repo.ErrEmptyRef
+	}
+
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 || n > len(refs) {
+		return "", lib.NewError(lib.ErrBadArgs, fmt.Sprintf("invalid selection %q. choose a number from 1 to %d", line, len(refs)))
+	}
+	return refs[n-1], nil
+}
+
+// isTerminal returns true if r is a file attached to an interactive terminal
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
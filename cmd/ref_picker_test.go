@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestPickDatasetRef(t *testing.T) {
+	refs := []string{"peer/cities", "peer/movies"}
+	cases := []struct {
+		input, expect, err string
+	}{
+		{"2\n", "peer/movies", ""},
+		{" 1 ", "peer/cities", ""},
+		{"\n", "", repo.ErrEmptyRef.Error()},
+		{"3\n", "", `invalid selection "3". choose a number from 1 to 2`},
+		{"movies\n", "", `invalid selection "movies". choose a number from 1 to 2`},
+	}
+
+	for _, c := range cases {
+		in := bytes.NewBufferString(c.input)
+		errOut := &bytes.Buffer{}
+		got, err := pickDatasetRef(ioes.NewIOStreams(in, &bytes.Buffer{}, errOut), refs)
+		if c.err != "" {
+			msg := ""
+			if libErr, ok := err.(lib.Error); ok {
+				msg = libErr.Message()
+			} else if err != nil {
+				msg = err.Error()
+			}
+			if msg != c.err {
+				t.Errorf("input %q error mismatch. expected: %q, got: %v", c.input, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("input %q unexpected error: %s", c.input, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("input %q expected: %q, got: %q", c.input, c.expect, got)
+		}
+		if !strings.Contains(errOut.String(), "  2. peer/movies") {
+			t.Errorf("expected picker to list refs. got:\n%s", errOut.String())
+		}
+	}
+
+	if _, err := pickDatasetRef(ioes.NewDiscardIOStreams(), nil); err != repo.ErrEmptyRef {
+		t.Errorf("expected picking from no refs to return ErrEmptyRef. got: %v", err)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	if isTerminal(&bytes.Buffer{}) {
+		t.Error("expected buffer to not be a terminal")
+	}
+	f, err := ioutil.TempFile("", "is_terminal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("expected regular file to not be a terminal")
+	}
+}
+
+func TestPickRefNonInteractive(t *testing.T) {
+	o := NewQriOptions(context.Background(), "", "", nil, ioes.NewIOStreams(&bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}))
+	if _, err := o.pickRef(); err != repo.ErrEmptyRef {
+		t.Errorf("expected non-terminal input to skip the picker with ErrEmptyRef. got: %v", err)
+	}
+	o.NoPrompt = true
+	if _, err := o.pickRef(); err != repo.ErrEmptyRef {
+		t.Errorf("expected --no-prompt to skip the picker with ErrEmptyRef. got: %v", err)
+	}
+}
+
+type pickingFactory struct {
+	TestFactory
+	picked string
+}
+
+func (f pickingFactory) pickRef() (string, error) {
+	return f.picked, nil
+}
+
+func TestGetCurrentRefSelectPicker(t *testing.T) {
+	tf, err := NewTestFactory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GetCurrentRefSelect(tf, nil, 1); err != repo.ErrEmptyRef {
+		t.Errorf("expected factory without a picker to return ErrEmptyRef. got: %v", err)
+	}
+
+	f := pickingFactory{TestFactory: tf, picked: "peer/movies"}
+	refs, err := GetCurrentRefSelect(f, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refs.Ref() != "peer/movies" {
+		t.Errorf("expected picked ref. got: %q", refs.Ref())
+	}
+	if refs, err = GetCurrentRefSelect(f, []string{"peer/cities"}, 1); err != nil || refs.Ref() != "peer/cities" {
+		t.Errorf("expected explicit ref to win over the picker. got: %v, %v", refs, err)
+	}
+	if _, err := GetOptionalRefSelect(f, nil, 1); err != repo.ErrEmptyRef {
+		t.Errorf("expected optional ref select to never pick. got: %v", err)
+	}
+}
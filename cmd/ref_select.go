@@ -105,9 +105,30 @@ func (r *RefSelect) String() string {
 // GetCurrentRefSelect returns the current reference selection. This could be explicitly provided
 // as command-line arguments, or could be determined by being in a linked directory, or could be
 // selected by the `use` command. This order is also the precendence, from most important to least.
+// When none of these provide a reference & qri is running in a terminal, the user is asked to
+// pick a dataset from a list.
 // This is the recommended method for command-line commands to get references, unless they have a
 // special way of interacting with datasets (for example, `qri status`).
 func GetCurrentRefSelect(f Factory, args []string, allowed int) (*RefSelect, error) {
+	refs, err := GetOptionalRefSelect(f, args, allowed)
+	if err != repo.ErrEmptyRef {
+		return refs, err
+	}
+	// Ask the user to pick a dataset, if the factory supports it
+	if p, ok := f.(refPicker); ok {
+		ref, err := p.pickRef()
+		if err != nil {
+			return nil, err
+		}
+		return NewExplicitRefSelect(ref), nil
+	}
+	return nil, repo.ErrEmptyRef
+}
+
+// GetOptionalRefSelect returns the current reference selection like GetCurrentRefSelect, but never
+// asks the user to pick a dataset. Commands that can run without a reference should use this,
+// handling repo.ErrEmptyRef when no reference is selected
+func GetOptionalRefSelect(f Factory, args []string, allowed int) (*RefSelect, error) {
 	// TODO(dlong): Respect `allowed`, number of refs the command uses. -1 means any.
 	// TODO(dlong): For example, `get` allows -1, `diff` allows 2, `save` allows 1
 	// If reference is specified by the user provide command-line arguments, use that reference.
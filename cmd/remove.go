@@ -28,9 +28,27 @@ Keep in mind that by default your IPFS repo is capped at 10GB in size, if you
 adjust this cap using IPFS, qri will respect it.
 
 In the future we’ll add a flag that’ll force immediate removal of a dataset from
-both qri & IPFS. Promise.`,
+both qri & IPFS. Promise.
+
+Use --soft to move a dataset to the trash instead. Trashed datasets can be
+brought back with 'qri restore --from-trash' until they've been in the trash
+longer than the retention window. See 'qri trash --help' for details.
+
+Use --keep-files to remove a dataset that's linked to a working directory while
+leaving the files in that directory in place. The dataset is removed from your
+repo & the directory is no longer linked, but body, meta, schema & other
+component files stay on disk.`,
 		Example: `  remove a dataset named annual_pop:
-  $ qri remove me/annual_pop --all`,
+  $ qri remove me/annual_pop --all
+
+  move a dataset to the trash, keeping it recoverable:
+  $ qri remove me/annual_pop --soft
+
+  remove a linked dataset, leaving files in its working directory:
+  $ qri remove me/annual_pop --keep-files
+
+  remove every dataset listed in a file, one reference per line:
+  $ cat refs.txt | qri remove --all --stdin`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -46,6 +64,9 @@ both qri & IPFS. Promise.`,
 	cmd.Flags().BoolVarP(&o.All, "all", "a", false, "synonym for --revisions=all")
 	cmd.Flags().BoolVar(&o.DeleteFSIFiles, "files", false, "delete linked files in dataset directory")
 	cmd.Flags().BoolVar(&o.Unlink, "unlink", false, "break link to directory")
+	cmd.Flags().BoolVar(&o.KeepFiles, "keep-files", false, "remove the entire dataset & its directory link, leaving linked files on disk")
+	cmd.Flags().BoolVar(&o.Soft, "soft", false, "move the entire dataset to the trash instead of deleting it")
+	cmd.Flags().BoolVar(&o.Stdin, "stdin", false, "read newline-separated dataset references from stdin")
 
 	return cmd
 }
@@ -61,6 +82,9 @@ type RemoveOptions struct {
 	All            bool
 	DeleteFSIFiles bool
 	Unlink         bool
+	Soft           bool
+	KeepFiles      bool
+	Stdin          bool
 
 	DatasetRequests *lib.DatasetRequests
 }
@@ -68,10 +92,29 @@ type RemoveOptions struct {
 // Complete adds any missing configuration that can only be added just before calling Run
 func (o *RemoveOptions) Complete(f Factory, args []string) (err error) {
 	o.Args = args
+	if o.Stdin {
+		if len(args) > 0 {
+			return errStdinArgs
+		}
+		if o.Args, err = readStdinRefs(o.In); err != nil {
+			return err
+		}
+	}
 	if o.DatasetRequests, err = f.DatasetRequests(); err != nil {
 		return err
 	}
-	if o.All {
+	if o.Soft && o.RevisionsText != "" {
+		return lib.NewError(lib.ErrBadArgs, "--soft moves entire datasets to the trash, it can't be combined with --revisions")
+	}
+	if o.KeepFiles {
+		if o.RevisionsText != "" {
+			return lib.NewError(lib.ErrBadArgs, "--keep-files removes entire datasets, it can't be combined with --revisions")
+		}
+		if o.DeleteFSIFiles {
+			return lib.NewError(lib.ErrBadArgs, "--keep-files and --files can't be used together")
+		}
+	}
+	if o.All || o.Soft || o.KeepFiles {
 		o.Revision = rev.NewAllRevisions()
 	} else {
 		if o.RevisionsText == "" {
@@ -103,32 +146,49 @@ func (o *RemoveOptions) Validate() error {
 
 // Run executes the remove command
 func (o *RemoveOptions) Run() (err error) {
+	if o.Stdin {
+		return eachRef(o.Out, o.ErrOut, o.Args, o.remove)
+	}
 	for _, arg := range o.Args {
-		params := lib.RemoveParams{
-			Ref:            arg,
-			Revision:       o.Revision,
-			DeleteFSIFiles: o.DeleteFSIFiles,
-			Unlink:         o.Unlink,
-		}
-
-		res := lib.RemoveResponse{}
-		if err = o.DatasetRequests.Remove(&params, &res); err != nil {
-			if err.Error() == "repo: not found" {
-				return lib.NewError(err, fmt.Sprintf("could not find dataset '%s'", arg))
-			}
+		if err = o.remove(arg); err != nil {
 			return err
 		}
-		if res.NumDeleted == rev.AllGenerations {
-			printSuccess(o.Out, "removed entire dataset '%s'", res.Ref)
-		} else if res.NumDeleted != 0 {
-			printSuccess(o.Out, "removed %d revisions of dataset '%s'", res.NumDeleted, res.Ref)
-		}
-		if res.DeletedFSIFiles {
-			printSuccess(o.Out, "deleted dataset files")
-		}
-		if res.Unlinked {
-			printSuccess(o.Out, "removed dataset link")
+	}
+	return nil
+}
+
+func (o *RemoveOptions) remove(ref string) error {
+	params := lib.RemoveParams{
+		Ref:            ref,
+		Revision:       o.Revision,
+		DeleteFSIFiles: o.DeleteFSIFiles,
+		Unlink:         o.Unlink,
+		Soft:           o.Soft,
+		KeepFiles:      o.KeepFiles,
+	}
+
+	res := lib.RemoveResponse{}
+	if err := o.DatasetRequests.Remove(&params, &res); err != nil {
+		if err.Error() == "repo: not found" {
+			return lib.NewError(err, fmt.Sprintf("could not find dataset '%s'", ref))
 		}
+		return err
+	}
+	if res.Trashed {
+		printSuccess(o.Out, "moved dataset '%s' to the trash", res.Ref)
+	} else if res.NumDeleted == rev.AllGenerations {
+		printSuccess(o.Out, "removed entire dataset '%s'", res.Ref)
+	} else if res.NumDeleted != 0 {
+		printSuccess(o.Out, "removed %d revisions of dataset '%s'", res.NumDeleted, res.Ref)
+	}
+	if res.DeletedFSIFiles {
+		printSuccess(o.Out, "deleted dataset files")
+	}
+	if res.Unlinked {
+		printSuccess(o.Out, "removed dataset link")
+	}
+	if res.KeptFiles {
+		printSuccess(o.Out, "kept dataset files in %s", res.FSIPath)
 	}
 	return nil
 }
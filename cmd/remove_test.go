@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -183,3 +184,40 @@ func testSliceEqual(a, b []string) bool {
 
 	return true
 }
+
+func TestRemoveStdin(t *testing.T) {
+	streams, in, out, errs := ioes.NewTestIOStreams()
+	setNoColor(true)
+
+	f, err := NewTestFactory()
+	if err != nil {
+		t.Fatalf("error creating new test factory: %s", err)
+	}
+
+	opt := &RemoveOptions{IOStreams: streams, Stdin: true}
+	if err := opt.Complete(f, []string{"me/movies"}); err != errStdinArgs {
+		t.Errorf("expected combining args with --stdin to error, got: %v", err)
+	}
+
+	in.WriteString("me/movies\n\nme/bad_dataset\n# comment\nme/cities\n")
+	opt = &RemoveOptions{IOStreams: streams, Stdin: true, All: true}
+	if err := opt.Complete(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"me/movies", "me/bad_dataset", "me/cities"}; !testSliceEqual(expect, opt.Args) {
+		t.Fatalf("refs mismatch. expected: %v, got: %v", expect, opt.Args)
+	}
+
+	err = opt.Run()
+	if err == nil || err.Error() != "1 of 3 references failed" {
+		t.Errorf("expected a failure summary error, got: %v", err)
+	}
+	if !strings.Contains(errs.String(), "me/bad_dataset: could not find dataset 'me/bad_dataset'") {
+		t.Errorf("expected per-ref error output, got: %q", errs.String())
+	}
+	for _, expect := range []string{"removed entire dataset 'peer/movies", "removed entire dataset 'peer/cities", "processed 3 references: 2 succeeded, 1 failed"} {
+		if !strings.Contains(out.String(), expect) {
+			t.Errorf("expected output to contain %q, got: %q", expect, out.String())
+		}
+	}
+}
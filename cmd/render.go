@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/lib"
@@ -71,21 +72,19 @@ func (o *RenderOptions) Complete(f Factory, args []string) (err error) {
 
 // Run executes the render command
 func (o *RenderOptions) Run() (err error) {
-	var template []byte
+	p := &lib.RenderParams{
+		Ref:            o.Refs.Ref(),
+		TemplateFormat: "html",
+	}
 
 	if o.Template != "" {
-		template, err = ioutil.ReadFile(o.Template)
-		if err != nil {
+		// resolve the template path here, render may run in a daemon with a
+		// different working directory
+		if p.TemplatePath, err = filepath.Abs(o.Template); err != nil {
 			return err
 		}
 	}
 
-	p := &lib.RenderParams{
-		Ref:            o.Refs.Ref(),
-		Template:       template,
-		TemplateFormat: "html",
-	}
-
 	res := []byte{}
 	if err = o.RenderRequests.Render(p, &res); err != nil {
 		if err == repo.ErrEmptyRef {
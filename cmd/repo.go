@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewRepoCommand creates a new `qri repo` cobra command for inspecting the
+// local repository
+func NewRepoCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &RepoOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Inspect your local repository",
+		Annotations: map[string]string{
+			"group": "other",
+		},
+	}
+
+	duplicates := &cobra.Command{
+		Use:   "duplicates",
+		Short: "List datasets that share the same content",
+		Long: `
+Duplicates scans the latest version of every dataset in your repo & groups
+datasets with the same content. Content is compared using the paths datasets
+were stored with, so bodies aren't re-read.
+
+By default datasets with the same body are duplicates. Use --by dataset to
+only match datasets that also share the same meta, structure, transform & viz.
+Commit details like title & timestamp are ignored either way.`,
+		Example: `  list datasets with the same body:
+  $ qri repo duplicates
+
+  list datasets that are the same in every component:
+  $ qri repo duplicates --by dataset`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f); err != nil {
+				return err
+			}
+			return o.Duplicates()
+		},
+	}
+	duplicates.Flags().StringVar(&o.By, "by", "body", "what counts as a duplicate [body, dataset]")
+
+	cmd.AddCommand(duplicates)
+	return cmd
+}
+
+// RepoOptions encapsulates state for the repo command
+type RepoOptions struct {
+	ioes.IOStreams
+
+	By string
+
+	DatasetRequests *lib.DatasetRequests
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *RepoOptions) Complete(f Factory) (err error) {
+	o.DatasetRequests, err = f.DatasetRequests()
+	return
+}
+
+// Duplicates executes the repo duplicates command
+func (o *RepoOptions) Duplicates() error {
+	res := []lib.DuplicateCluster{}
+	if err := o.DatasetRequests.Duplicates(&lib.DuplicatesParams{By: o.By}, &res); err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		printInfo(o.Out, "no duplicate datasets")
+		return nil
+	}
+
+	items := make([]fmt.Stringer, len(res))
+	for i, c := range res {
+		items[i] = duplicateClusterStringer(c)
+	}
+	return printItems(o.Out, items, 0)
+}
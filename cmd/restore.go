@@ -6,6 +6,7 @@ import (
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
 	"github.com/spf13/cobra"
 )
 
@@ -13,10 +14,11 @@ import (
 func NewRestoreCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 	o := &RestoreOptions{IOStreams: ioStreams}
 	cmd := &cobra.Command{
-		Use:     "restore",
-		Short:   "restore returns part or all of a dataset to a previous state",
-		Long:    ``,
-		Example: ``,
+		Use:   "restore",
+		Short: "restore returns part or all of a dataset to a previous state",
+		Long:  ``,
+		Example: `  restore a dataset removed with qri remove --soft:
+  $ qri restore --from-trash me/annual_pop`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -24,10 +26,15 @@ func NewRestoreCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 			if err := o.Complete(f, args); err != nil {
 				return err
 			}
+			if o.FromTrash {
+				return o.RunFromTrash()
+			}
 			return o.Run()
 		},
 	}
 
+	cmd.Flags().BoolVar(&o.FromTrash, "from-trash", false, "restore a dataset removed with qri remove --soft")
+
 	return cmd
 }
 
@@ -38,12 +45,23 @@ type RestoreOptions struct {
 	Refs          *RefSelect
 	Path          string
 	ComponentName string
+	FromTrash     bool
 
-	FSIMethods *lib.FSIMethods
+	FSIMethods      *lib.FSIMethods
+	DatasetRequests *lib.DatasetRequests
 }
 
 // Complete configures the restore command
 func (o *RestoreOptions) Complete(f Factory, args []string) (err error) {
+	if o.FromTrash {
+		if len(args) != 1 {
+			return lib.NewError(lib.ErrBadArgs, "please provide the name of exactly one dataset to restore from the trash, for example:\n    $ qri restore --from-trash me/dataset_name")
+		}
+		o.Refs = NewExplicitRefSelect(args[0])
+		o.DatasetRequests, err = f.DatasetRequests()
+		return err
+	}
+
 	dsRefList := []string{}
 	o.Path = ""
 	o.ComponentName = ""
@@ -118,3 +136,18 @@ func (o *RestoreOptions) Run() (err error) {
 	// TODO(dlong): Print message when both component and path are specified.
 	return nil
 }
+
+// RunFromTrash restores a soft-deleted dataset from the trash
+func (o *RestoreOptions) RunFromTrash() error {
+	refstr := o.Refs.Ref()
+	res := repo.DatasetRef{}
+	if err := o.DatasetRequests.RestoreFromTrash(&refstr, &res); err != nil {
+		if err == lib.ErrNotInTrash {
+			return lib.NewError(err, fmt.Sprintf("'%s' isn't in the trash. see `qri trash list` for trashed datasets", refstr))
+		}
+		return err
+	}
+
+	printSuccess(o.Out, "restored dataset %s from the trash", res.AliasString())
+	return nil
+}
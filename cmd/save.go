@@ -34,7 +34,23 @@ When you make an update and save a dataset that you originally added from a diff
 peer, the dataset gets renamed from ` + "`peers_name/dataset_name`" + ` to ` + "`my_name/dataset_name`" + `.
 
 The ` + "`--message`" + `" and ` + "`--title`" + ` flags allow you to add a 
-commit message and title to the save.`,
+commit message and title to the save.
+
+The ` + "`--template`" + ` flag fills in structure and meta from a named template
+when those components aren't otherwise provided, which keeps datasets
+consistent across a team. Templates are dataset files (json or yaml) stored in
+the templates directory of your qri repo, named after the template.
+
+The ` + "`--license`" + ` flag records a license in dataset metadata. Licenses must
+be a recognized SPDX identifier like CC-BY-4.0 or MIT. Some remotes reject
+datasets that don't carry a license.
+
+The ` + "`--attach`" + ` flag attaches auxiliary files like a codebook or data
+dictionary to the dataset. Attachments are named after their filename, carry
+forward to later versions, and are listed in metadata under "attachments".
+Attaching a file with the same name as an existing attachment replaces it. List
+attachments with ` + "`qri get attachments`" + `. Diffs show changed attachments
+as changes to the attachment's path in meta.`,
 		Example: `  # save updated data to dataset annual_pop:
   qri save --body /path/to/data.csv me/annual_pop
 
@@ -42,7 +58,19 @@ commit message and title to the save.`,
   qri save --file /path/to/dataset.yaml me/annual_pop
   
   # re-execute a dataset that has a transform:
-  qri save me/tf_dataset`,
+  qri save me/tf_dataset
+
+  # save data using the structure & meta from $QRI_PATH/templates/census.yaml:
+  qri save --body /path/to/data.csv --template census me/annual_pop
+
+  # save data, licensing the dataset under Creative Commons Attribution 4.0:
+  qri save --body /path/to/data.csv --license CC-BY-4.0 me/annual_pop
+
+  # attach a codebook to a dataset:
+  qri save --attach codebook.pdf me/annual_pop
+
+  # list the inputs a transform will read, confirming large runs before saving:
+  qri save --file transform.star --estimate me/tf_dataset`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -61,6 +89,9 @@ commit message and title to the save.`,
 	cmd.Flags().StringVarP(&o.Title, "title", "t", "", "title of commit message for save")
 	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "commit message for save")
 	cmd.Flags().StringVarP(&o.BodyPath, "body", "", "", "path to file or url of data to add as dataset contents")
+	cmd.Flags().StringVar(&o.Template, "template", "", "name of a template to fill structure & meta from")
+	cmd.Flags().StringVar(&o.License, "license", "", "SPDX identifier of the license to record in meta, eg: CC-BY-4.0")
+	cmd.Flags().StringSliceVar(&o.Attachments, "attach", nil, "file to attach to the dataset, may be repeated")
 	cmd.Flags().StringVarP(&o.Recall, "recall", "", "", "restore revisions from dataset history")
 	// cmd.Flags().BoolVarP(&o.ShowValidation, "show-validation", "s", false, "display a list of validation errors upon adding")
 	cmd.Flags().StringSliceVar(&o.Secrets, "secrets", nil, "transform secrets as comma separated key,value,key,value,... sequence")
@@ -68,6 +99,7 @@ commit message and title to the save.`,
 	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "simulate saving a dataset")
 	cmd.Flags().BoolVar(&o.Force, "force", false, "force a new commit, even if no changes are detected")
 	cmd.Flags().BoolVarP(&o.KeepFormat, "keep-format", "k", false, "convert incoming data to stored data format")
+	cmd.Flags().BoolVar(&o.Estimate, "estimate", false, "estimate transform inputs before running, confirming large runs")
 	cmd.Flags().BoolVarP(&o.NoRender, "no-render", "n", false, "don't store a rendered version of the the vizualization ")
 
 	return cmd
@@ -81,6 +113,10 @@ type SaveOptions struct {
 	FilePaths []string
 	BodyPath  string
 	Recall    string
+	Template  string
+	License   string
+
+	Attachments []string
 
 	Title   string
 	Message string
@@ -93,6 +129,7 @@ type SaveOptions struct {
 	KeepFormat     bool
 	Force          bool
 	NoRender       bool
+	Estimate       bool
 	Secrets        []string
 
 	DatasetRequests *lib.DatasetRequests
@@ -119,6 +156,11 @@ func (o *SaveOptions) Complete(f Factory, args []string) (err error) {
 			return
 		}
 	}
+	for i := range o.Attachments {
+		if err = qfs.AbsPath(&o.Attachments[i]); err != nil {
+			return
+		}
+	}
 
 	if err := qfs.AbsPath(&o.BodyPath); err != nil {
 		return fmt.Errorf("body file: %s", err)
@@ -154,6 +196,9 @@ func (o *SaveOptions) Run() (err error) {
 		ReadFSI:             o.UsingFSI,
 		WriteFSI:            o.UsingFSI,
 		FilePaths:           o.FilePaths,
+		Template:            o.Template,
+		License:             o.License,
+		Attachments:         o.Attachments,
 		Private:             false,
 		Publish:             o.Publish,
 		DryRun:              o.DryRun,
@@ -164,6 +209,20 @@ func (o *SaveOptions) Run() (err error) {
 		ShouldRender:        !o.NoRender,
 	}
 
+	if o.Estimate {
+		est := &lib.TransformEstimate{}
+		if err = o.DatasetRequests.EstimateTransform(p, est); err != nil {
+			return err
+		}
+		o.StopSpinner()
+		fmt.Fprint(o.ErrOut, transformEstimateStringer(*est).String())
+		if est.Large() && !confirm(o.ErrOut, o.In, "this transform may read a lot of data. continue?", false) {
+			printInfo(o.ErrOut, "save cancelled")
+			return
+		}
+		o.StartSpinner()
+	}
+
 	if o.Secrets != nil {
 		// Stop the spinner so the user can see the prompt, and the answer they type will
 		// not be erased. Output the message to error stream in case stdout is captured.
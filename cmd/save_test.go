@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -192,3 +194,30 @@ func TestSaveRun(t *testing.T) {
 		}
 	}
 }
+
+func TestSaveEstimate(t *testing.T) {
+	if err := confirmQriNotRunning(); err != nil {
+		t.Skip(err.Error())
+	}
+
+	r := NewTestRepoRoot(t, "qri_test_save_estimate")
+	defer r.Delete()
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	cmdR := r.CreateCommandRunner(ctx)
+	if err := executeCommand(cmdR, "qri save --file=testdata/movies/tf_123.star --estimate me/test_ds"); err != nil {
+		t.Fatal(err)
+	}
+	output := r.GetOutput()
+	if !strings.Contains(output, "transform inputs: 0") || !strings.Contains(output, "dataset saved") {
+		t.Errorf("expected estimate to print before saving, got:\n%s", output)
+	}
+
+	cmdR = r.CreateCommandRunner(ctx)
+	err := executeCommand(cmdR, "qri save --body=testdata/movies/body_ten.csv --estimate me/test_ds")
+	if libErr, ok := err.(lib.Error); !ok || libErr.Message() != "no transform to estimate" {
+		t.Errorf("expected estimating a save without a transform to error, got: %v", err)
+	}
+}
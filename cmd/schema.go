@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+
+	util "github.com/qri-io/apiutil"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/qri-io/qri/repo"
+	"github.com/spf13/cobra"
+)
+
+// NewSchemaCommand creates a new `qri schema` cobra command for inspecting
+// dataset body schemas
+func NewSchemaCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &SchemaOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Inspect dataset body schemas",
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	history := &cobra.Command{
+		Use:   "history",
+		Short: "Show how the columns of a dataset body changed over time",
+		Long: `
+History walks a dataset's versions, comparing the body schema of each version
+to the one before it. Each version that added a column, removed a column, or
+changed the type of a column is listed with its changes, starting with the
+most recent.`,
+		Example: `  show schema history for the dataset b5/precip:
+  $ qri schema history b5/precip`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.History()
+		},
+	}
+	history.Flags().IntVar(&o.PageSize, "page-size", 25, "page size of results, default 25")
+	history.Flags().IntVar(&o.Page, "page", 1, "page number of results, default 1")
+
+	cmd.AddCommand(history)
+	return cmd
+}
+
+// SchemaOptions encapsulates state for the schema command
+type SchemaOptions struct {
+	ioes.IOStreams
+
+	PageSize int
+	Page     int
+	Refs     *RefSelect
+
+	LogRequests *lib.LogRequests
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *SchemaOptions) Complete(f Factory, args []string) (err error) {
+	if o.Refs, err = GetCurrentRefSelect(f, args, 1); err != nil {
+		return err
+	}
+	o.LogRequests, err = f.LogRequests()
+	return
+}
+
+// History executes the schema history command
+func (o *SchemaOptions) History() error {
+	printRefSelect(o.Out, o.Refs)
+
+	page := util.NewPage(o.Page, o.PageSize)
+	p := &lib.LogParams{
+		Ref: o.Refs.Ref(),
+		ListParams: lib.ListParams{
+			Limit:  page.Limit(),
+			Offset: page.Offset(),
+		},
+	}
+
+	res := []lib.SchemaVersion{}
+	if err := o.LogRequests.SchemaHistory(p, &res); err != nil {
+		if err == repo.ErrEmptyRef {
+			return lib.NewError(err, "please provide a dataset reference")
+		}
+		return err
+	}
+
+	if len(res) == 0 {
+		printInfo(o.Out, "no schema changes")
+		return nil
+	}
+
+	items := make([]fmt.Stringer, len(res))
+	for i, v := range res {
+		items[i] = schemaVersionStringer(v)
+	}
+	return printItems(o.Out, items, page.Offset())
+}
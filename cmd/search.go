@@ -24,7 +24,10 @@ Search datasets & peers that match your query. Search pings the qri registry.
 Any dataset that has been published to the registry is available for search.`,
 		Example: `
   # search 
-  $ qri search "annual population"`,
+  $ qri search "annual population"
+
+  # print results as json, for use in scripts
+  $ qri search --json "annual population"`,
 		Annotations: map[string]string{
 			"group": "network",
 		},
@@ -40,6 +43,7 @@ Any dataset that has been published to the registry is available for search.`,
 	}
 
 	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "set output format [json]")
+	cmd.Flags().BoolVar(&o.JSON, "json", false, "print results as a json array of ref, title, description & score objects")
 	cmd.Flags().IntVar(&o.PageSize, "page-size", 25, "page size of results, default 25")
 	cmd.Flags().IntVar(&o.Page, "page", 1, "page number of results, default 1")
 
@@ -52,6 +56,7 @@ type SearchOptions struct {
 
 	Query    string
 	Format   string
+	JSON     bool
 	PageSize int
 	Page     int
 	// Reindex bool
@@ -73,6 +78,9 @@ func (o *SearchOptions) Validate() error {
 	if o.Query == "" {
 		return lib.NewError(lib.ErrBadArgs, "please provide search parameters, for example:\n    $ qri search census\n    $ qri search 'census 2018'\nsee `qri search --help` for more information")
 	}
+	if o.JSON && o.Format != "" {
+		return lib.NewError(lib.ErrBadArgs, "--json and --format can't be used together")
+	}
 	return nil
 }
 
@@ -98,6 +106,16 @@ func (o *SearchOptions) Run() (err error) {
 		return err
 	}
 
+	if o.JSON {
+		data, err := json.MarshalIndent(lib.SummarizeSearchResults(results), "", "  ")
+		if err != nil {
+			return err
+		}
+		o.StopSpinner()
+		fmt.Fprintln(o.Out, string(data))
+		return nil
+	}
+
 	// o.StopSpinner()
 	switch o.Format {
 	case "":
@@ -159,6 +159,56 @@ func TestSearchRun(t *testing.T) {
 	}
 }
 
+func TestSearchRunJSON(t *testing.T) {
+	streams, _, out, _ := ioes.NewTestIOStreams()
+	setNoColor(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(mockResponse)
+	}))
+	rc := regclient.NewClient(&regclient.Config{Location: server.URL})
+
+	f, err := NewTestFactoryInstanceOptions(lib.OptRegistryClient(rc))
+	if err != nil {
+		t.Fatalf("error creating new test factory: %s", err)
+	}
+	sr, err := f.SearchMethods()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opt := &SearchOptions{
+		IOStreams:     streams,
+		Query:         "joke",
+		JSON:          true,
+		SearchMethods: sr,
+	}
+	if err := opt.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := opt.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `[
+  {
+    "ref": "nuun/nuun@/ipfs/QmZEnjt3Y5RxXsoZyufJfFzcogicBEwfaimJSyDuC7nySA",
+    "title": "this is a d",
+    "description": "",
+    "score": 0.75
+  }
+]
+`
+	if expect != out.String() {
+		t.Errorf("output mismatch. Expected: '%s', Got: '%s'", expect, out.String())
+	}
+
+	opt.Format = "json"
+	if err := opt.Validate(); err == nil {
+		t.Error("expected using --json and --format together to error")
+	}
+}
+
 var mockResponse = []byte(`{"data":[
 	{
 		"commit": {
@@ -177,6 +227,7 @@ var mockResponse = []byte(`{"data":[
 		"path": "/ipfs/QmZEnjt3Y5RxXsoZyufJfFzcogicBEwfaimJSyDuC7nySA",
 		"peername": "nuun",
 		"qri": "ds:0",
+		"score": 0.75,
 		"structure": {
 			"entries": 3,
 			"format": "csv",
@@ -223,6 +274,7 @@ var jsonSearchResponse = `[
         "length": 36,
         "qri": "st:0"
       }
-    }
+    },
+    "Score": 0.75
   }
 ]`
@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/qri-io/doggos"
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/config"
@@ -31,6 +33,10 @@ Setup does a few things:
 - provisions a new qri ID
 - create an IPFS repository if one doesn’t exist
 
+Use ` + "`--ipfs-api`" + ` to connect to an IPFS daemon that's already running instead
+of creating an IPFS repository. qri will store datasets through the daemon's
+HTTP API.
+
 This command is automatically run if you invoke any Qri command without first 
 running setup. If setup has already been run, by default Qri won’t let you 
 overwrite this info.
@@ -38,7 +44,10 @@ overwrite this info.
 Use the ` + "`--remove`" + ` to remove your Qri repo. This deletes your entire repo, 
 including all your datasets, and de-registers your peername from the registry.`,
 		Example: `  run setup with a peername of your choosing:
-  $ qri setup --peername=your_great_peername`,
+  $ qri setup --peername=your_great_peername
+
+  use an IPFS daemon that's already running:
+  $ qri setup --ipfs-api /ip4/127.0.0.1/tcp/5001`,
 		Annotations: map[string]string{
 			"group": "other",
 		},
@@ -56,6 +65,7 @@ including all your datasets, and de-registers your peername from the registry.`,
 	cmd.Flags().BoolVarP(&o.Remove, "remove", "", false, "permanently remove qri, overrides all setup options")
 	cmd.Flags().StringVarP(&o.Registry, "registry", "", "", "override default registry URL, set to 'none' to remove registry")
 	cmd.Flags().StringVarP(&o.Peername, "peername", "", "", "choose your desired peername")
+	cmd.Flags().StringVarP(&o.IPFSAPI, "ipfs-api", "", "", "multiaddr or url of a running IPFS daemon's API to use instead of creating an IPFS repo")
 	cmd.Flags().StringVarP(&o.IPFSConfigData, "ipfs-config", "", "", "json-encoded configuration data, specify a filepath with '@' prefix")
 	cmd.Flags().StringVarP(&o.ConfigData, "config-data", "", "", "json-encoded configuration data, specify a filepath with '@' prefix")
 
@@ -74,6 +84,7 @@ type SetupOptions struct {
 	Registry       string
 	IPFSConfigData string
 	ConfigData     string
+	IPFSAPI        string
 
 	QriRepoPath string
 	IpfsFsPath  string
@@ -174,6 +185,19 @@ func (o *SetupOptions) DoSetup(f Factory) (err error) {
 		cfg.Registry.Location = o.Registry
 	}
 
+	if o.IPFSAPI != "" {
+		url, err := ipfsAPIURL(o.IPFSAPI)
+		if err != nil {
+			return err
+		}
+		// attach to the running daemon, there's no IPFS repo to create
+		cfg.Store = &config.Store{
+			Type:    "ipfs_http",
+			Options: map[string]interface{}{"url": url},
+		}
+		o.IPFS = false
+	}
+
 	p := lib.SetupParams{
 		Config:         cfg,
 		QriRepoPath:    o.QriRepoPath,
@@ -214,6 +238,39 @@ func QRIRepoInitialized(path string) bool {
 	return !os.IsNotExist(err)
 }
 
+// ipfsAPIURL converts the address of an IPFS HTTP API into a url. addresses
+// may be multiaddrs, eg: /ip4/127.0.0.1/tcp/5001, or urls
+func ipfsAPIURL(addr string) (string, error) {
+	if !strings.HasPrefix(addr, "/") {
+		return addr, nil
+	}
+
+	maddr, err := ma.NewMultiaddr(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid IPFS API address %q: %s", addr, err)
+	}
+	var host, port string
+	for _, c := range ma.Split(maddr) {
+		p := c.Protocols()[0]
+		val, err := c.ValueForProtocol(p.Code)
+		if err != nil {
+			return "", err
+		}
+		switch p.Name {
+		case "ip4", "dns4", "dns6", "dns":
+			host = val
+		case "ip6":
+			host = "[" + val + "]"
+		case "tcp":
+			port = val
+		}
+	}
+	if host == "" || port == "" {
+		return "", fmt.Errorf("IPFS API address %q must include a host and tcp port", addr)
+	}
+	return fmt.Sprintf("http://%s:%s", host, port), nil
+}
+
 func mapEnvVars(vars map[string]*string) {
 	for envVar, value := range vars {
 		envVal := os.Getenv(envVar)
@@ -22,3 +22,31 @@ func TestSetupComplete(t *testing.T) {
 
 	opt.Complete(f, nil)
 }
+
+func TestIPFSAPIURL(t *testing.T) {
+	cases := []struct {
+		addr, expect, err string
+	}{
+		{"/ip4/127.0.0.1/tcp/5001", "http://127.0.0.1:5001", ""},
+		{"/ip6/::1/tcp/5001", "http://[::1]:5001", ""},
+		{"http://localhost:5001", "http://localhost:5001", ""},
+		{"/ip4/127.0.0.1", "", `IPFS API address "/ip4/127.0.0.1" must include a host and tcp port`},
+	}
+
+	for _, c := range cases {
+		got, err := ipfsAPIURL(c.addr)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("%q error mismatch. expected: %q, got: %v", c.addr, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q unexpected error: %s", c.addr, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%q url mismatch. expected: %q, got: %q", c.addr, c.expect, got)
+		}
+	}
+}
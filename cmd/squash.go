@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewSquashCommand creates a new `qri squash` cobra command for collapsing
+// dataset history
+func NewSquashCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &SquashOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "squash",
+		Short: "Collapse dataset versions into a single version",
+		Long: `
+Squash rewrites the history of a dataset, replacing the latest versions with a
+single version that has the same content as the current one. Use it to clean up
+a long run of experimental saves before publishing.
+
+By default squash collapses the entire history into a new first version. Use
+--revisions to squash only the latest n versions, keeping everything before
+them.
+
+Squashing changes the path of the latest version. Anyone who has added your
+dataset will still have the old history, so avoid squashing versions you've
+already published.
+
+Squashed versions stay in your store until they're garbage collected. Use
+--unpin to unpin them right away.`,
+		Example: `  squash the entire history of a dataset into one version:
+  $ qri squash me/annual_pop
+
+  squash the last 5 versions, giving the result a commit title:
+  $ qri squash me/annual_pop --revisions 5 --title "add 2019 figures"`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().IntVarP(&o.Revisions, "revisions", "r", 0, "number of latest versions to squash, default squashes all versions")
+	cmd.Flags().StringVarP(&o.Title, "title", "t", "", "title of the squashed commit")
+	cmd.Flags().StringVarP(&o.Message, "message", "m", "", "message of the squashed commit")
+	cmd.Flags().BoolVar(&o.Unpin, "unpin", false, "unpin squashed versions from the store")
+
+	return cmd
+}
+
+// SquashOptions encapsulates state for the squash command
+type SquashOptions struct {
+	ioes.IOStreams
+
+	Ref       string
+	Revisions int
+	Title     string
+	Message   string
+	Unpin     bool
+
+	DatasetRequests *lib.DatasetRequests
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *SquashOptions) Complete(f Factory, args []string) (err error) {
+	if len(args) > 0 {
+		o.Ref = args[0]
+	}
+	o.DatasetRequests, err = f.DatasetRequests()
+	return
+}
+
+// Validate checks that all user input is valid
+func (o *SquashOptions) Validate() error {
+	if o.Ref == "" {
+		return lib.NewError(lib.ErrBadArgs, "please provide the name of a dataset to squash")
+	}
+	if o.Revisions < 0 || o.Revisions == 1 {
+		return lib.NewError(lib.ErrBadArgs, "--revisions must be at least 2")
+	}
+	return nil
+}
+
+// Run executes the squash command
+func (o *SquashOptions) Run() error {
+	p := &lib.SquashParams{
+		Ref:       o.Ref,
+		Revisions: o.Revisions,
+		Title:     o.Title,
+		Message:   o.Message,
+		Unpin:     o.Unpin,
+	}
+	res := &lib.SquashResponse{}
+	if err := o.DatasetRequests.Squash(p, res); err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "squashed %d versions of dataset %s", res.NumSquashed, res.Ref.AliasString())
+	printInfo(o.Out, "new path: %s", res.Ref.Path)
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+)
+
+func TestSquashComplete(t *testing.T) {
+	streams, _, _, _ := ioes.NewTestIOStreams()
+	setNoColor(true)
+
+	f, err := NewTestFactory()
+	if err != nil {
+		t.Fatalf("error creating new test factory: %s", err)
+	}
+
+	opt := &SquashOptions{IOStreams: streams}
+	if err := opt.Complete(f, []string{"me/movies"}); err != nil {
+		t.Fatal(err)
+	}
+	if opt.Ref != "me/movies" {
+		t.Errorf("ref mismatch. expected: %q, got: %q", "me/movies", opt.Ref)
+	}
+	if opt.DatasetRequests == nil {
+		t.Error("expected DatasetRequests to be set")
+	}
+}
+
+func TestSquashValidate(t *testing.T) {
+	cases := []struct {
+		ref       string
+		revisions int
+		err       string
+	}{
+		{"", 0, "please provide the name of a dataset to squash"},
+		{"me/movies", 1, "--revisions must be at least 2"},
+		{"me/movies", -3, "--revisions must be at least 2"},
+		{"me/movies", 0, ""},
+		{"me/movies", 2, ""},
+	}
+	for i, c := range cases {
+		opt := &SquashOptions{Ref: c.ref, Revisions: c.revisions}
+		err := opt.Validate()
+		if c.err == "" {
+			if err != nil {
+				t.Errorf("case %d unexpected error: %s", i, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("case %d expected error: %q", i, c.err)
+			continue
+		}
+		libErr, ok := err.(lib.Error)
+		if !ok {
+			t.Errorf("case %d expected a lib.Error, got: %T", i, err)
+			continue
+		}
+		if libErr.Message() != c.err {
+			t.Errorf("case %d error message mismatch. expected: %q, got: %q", i, c.err, libErr.Message())
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/fsi"
 	"github.com/qri-io/qri/lib"
 	"github.com/spf13/cobra"
@@ -15,10 +17,25 @@ import (
 func NewStatusCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 	o := &StatusOptions{IOStreams: ioStreams}
 	cmd := &cobra.Command{
-		Use:     "status",
-		Short:   "Show status of working directory",
-		Long:    ``,
-		Example: ``,
+		Use:   "status",
+		Short: "Show status of working directory",
+		Long: `
+Status shows changes to a linked working directory that haven't been saved.
+
+With ` + "`--remote`" + `, status instead compares the local head of a dataset to
+its head on a remote, reporting if they match and which side is ahead if they
+don't. Use this to confirm a mirror is in sync.
+
+With ` + "`--json`" + `, status prints the state of each component & the file it's
+read from as JSON, for scripts & editor integrations.`,
+		Example: `  # show changes to the dataset linked to the current directory:
+  qri status
+
+  # show changes to the linked working directory as JSON:
+  qri status --json
+
+  # check if a local dataset matches the head on the "origin" remote:
+  qri status --remote origin me/annual_pop`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -26,6 +43,9 @@ func NewStatusCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 			if err := o.Complete(f, args); err != nil {
 				return err
 			}
+			if o.Remote != "" {
+				return o.RunRemote()
+			}
 			if !o.Refs.IsLinked() {
 				return o.RunAtVersion()
 			}
@@ -34,6 +54,8 @@ func NewStatusCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&o.ShowMtime, "show-mtime", false, "whether to show mtime for each component")
+	cmd.Flags().StringVar(&o.Remote, "remote", "", "name of a remote to compare the dataset head against")
+	cmd.Flags().BoolVar(&o.JSON, "json", false, "print component status as json")
 
 	return cmd
 }
@@ -44,8 +66,11 @@ type StatusOptions struct {
 
 	Refs      *RefSelect
 	ShowMtime bool
+	Remote    string
+	JSON      bool
 
-	FSIMethods *lib.FSIMethods
+	FSIMethods    *lib.FSIMethods
+	RemoteMethods *lib.RemoteMethods
 }
 
 // Complete adds any missing configuration that can only be added just before calling Run
@@ -55,6 +80,14 @@ func (o *StatusOptions) Complete(f Factory, args []string) (err error) {
 		return err
 	}
 
+	if o.Remote != "" {
+		if o.JSON {
+			return fmt.Errorf("--json can't be combined with --remote")
+		}
+		o.RemoteMethods, err = f.RemoteMethods()
+		return err
+	}
+
 	o.FSIMethods, err = f.FSIMethods()
 	return
 }
@@ -64,15 +97,24 @@ const ColumnPositionForMtime = 40
 
 // Run executes the status command
 func (o *StatusOptions) Run() (err error) {
-	printRefSelect(o.ErrOut, o.Refs)
+	if !o.JSON {
+		printRefSelect(o.ErrOut, o.Refs)
+	}
 
 	res := []lib.StatusItem{}
 	dir := o.Refs.Dir()
 	if err := o.FSIMethods.Status(&dir, &res); err != nil {
+		if o.JSON {
+			return err
+		}
 		printErr(o.ErrOut, err)
 		return nil
 	}
 
+	if o.JSON {
+		return o.printJSON(dir, res)
+	}
+
 	clean := true
 	valid := true
 	for _, si := range res {
@@ -116,18 +158,97 @@ func (o *StatusOptions) Run() (err error) {
 
 // RunAtVersion displays status for a reference at a specific version
 func (o *StatusOptions) RunAtVersion() (err error) {
-	printRefSelect(o.ErrOut, o.Refs)
+	if !o.JSON {
+		printRefSelect(o.ErrOut, o.Refs)
+	}
 
 	res := []lib.StatusItem{}
 	ref := o.Refs.Ref()
 	if err := o.FSIMethods.StatusAtVersion(&ref, &res); err != nil {
+		if o.JSON {
+			return err
+		}
 		printErr(o.ErrOut, err)
 		return nil
 	}
 
+	if o.JSON {
+		return o.printJSON("", res)
+	}
+
 	for _, si := range res {
 		printInfo(o.Out, fmt.Sprintf("  %s: %s", si.Component, si.Type))
 	}
 
 	return nil
 }
+
+// StatusJSON is the JSON output of the status command
+type StatusJSON struct {
+	Ref string `json:"ref"`
+	// Dir is the linked working directory, empty when showing status at a
+	// version
+	Dir string `json:"dir,omitempty"`
+	// Clean is true when no component has changes
+	Clean      bool             `json:"clean"`
+	Components []lib.StatusItem `json:"components"`
+}
+
+// printJSON writes status items as JSON
+func (o *StatusOptions) printJSON(dir string, items []lib.StatusItem) error {
+	res := StatusJSON{
+		Ref:        o.Refs.Ref(),
+		Dir:        dir,
+		Clean:      true,
+		Components: items,
+	}
+	for _, si := range items {
+		if si.Type != fsi.STUnmodified {
+			res.Clean = false
+		}
+	}
+	if res.Components == nil {
+		res.Components = []lib.StatusItem{}
+	}
+
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(o.Out, string(data))
+	return nil
+}
+
+// RunRemote compares the local head of a dataset to the head on a remote
+func (o *StatusOptions) RunRemote() (err error) {
+	printRefSelect(o.ErrOut, o.Refs)
+
+	p := &lib.CompareHeadParams{
+		Ref:        o.Refs.Ref(),
+		RemoteName: o.Remote,
+	}
+	res := &lib.HeadComparison{}
+	if err = o.RemoteMethods.CompareHead(p, res); err != nil {
+		return err
+	}
+
+	switch res.Status {
+	case base.HeadsMatch:
+		printSuccess(o.Out, "%s is up to date with %s", res.Ref, o.Remote)
+	case base.HeadLocalAhead:
+		printInfo(o.Out, "%s is ahead of %s. run `qri publish` to update the remote", res.Ref, o.Remote)
+	case base.HeadRemoteAhead:
+		printWarning(o.Out, "%s is behind %s. run `qri add` to fetch the latest version", res.Ref, o.Remote)
+	case base.HeadLocalMissing:
+		printWarning(o.Out, "%s exists on %s, but not locally", res.Ref, o.Remote)
+	case base.HeadRemoteMissing:
+		printWarning(o.Out, "%s doesn't exist on %s", res.Ref, o.Remote)
+	}
+	if res.LocalPath != "" {
+		printInfo(o.Out, "  local:  %s", res.LocalPath)
+	}
+	if res.RemotePath != "" {
+		printInfo(o.Out, "  remote: %s", res.RemotePath)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qri-io/ioes"
+)
+
+func TestStatusRemoteComplete(t *testing.T) {
+	streams, _, _, _ := ioes.NewTestIOStreams()
+	f, err := NewTestFactory()
+	if err != nil {
+		t.Fatalf("error creating new test factory: %s", err)
+	}
+
+	o := &StatusOptions{IOStreams: streams, Remote: "origin"}
+	if err := o.Complete(f, []string{"me/movies"}); err != nil {
+		t.Fatal(err)
+	}
+	if o.RemoteMethods == nil {
+		t.Error("expected --remote to set RemoteMethods")
+	}
+	if o.FSIMethods != nil {
+		t.Error("expected --remote to skip FSIMethods")
+	}
+
+	expect := `remote name "origin" not found`
+	if err := o.RunRemote(); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/qri/lib"
+)
+
+// readStdinRefs reads newline-separated dataset references from r. Blank lines
+// & lines starting with "#" are skipped
+func readStdinRefs(r io.Reader) ([]string, error) {
+	if r == nil {
+		return nil, lib.NewError(lib.ErrBadArgs, "no input to read references from")
+	}
+
+	refs := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading references: %s", err)
+	}
+	if len(refs) == 0 {
+		return nil, lib.NewError(lib.ErrBadArgs, "no dataset references read from stdin")
+	}
+	return refs, nil
+}
+
+// eachRef calls process for every ref, writing failures to errOut & carrying
+// on with the next ref. Once all refs are processed a summary is written to
+// out. eachRef returns an error if any ref failed so the command exits with a
+// non-zero status
+func eachRef(out, errOut io.Writer, refs []string, process func(ref string) error) error {
+	failed := 0
+	for _, ref := range refs {
+		if err := process(ref); err != nil {
+			failed++
+			msg := err.Error()
+			if e, ok := err.(lib.Error); ok && e.Message() != "" {
+				msg = e.Message()
+			}
+			printErr(errOut, fmt.Errorf("%s: %s", ref, msg))
+		}
+	}
+
+	if failed == 0 {
+		printSuccess(out, "processed %d references", len(refs))
+		return nil
+	}
+	printWarning(out, "processed %d references: %d succeeded, %d failed", len(refs), len(refs)-failed, failed)
+	return fmt.Errorf("%d of %d references failed", failed, len(refs))
+}
+
+// errStdinArgs is returned when ref arguments are combined with --stdin
+var errStdinArgs = lib.NewError(lib.ErrBadArgs, "can't combine dataset reference arguments with --stdin")
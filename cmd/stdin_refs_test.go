@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/ioes"
+)
+
+func TestReadStdinRefs(t *testing.T) {
+	refs, err := readStdinRefs(strings.NewReader("me/a\n  me/b  \r\n\n# skipped\nme/c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"me/a", "me/b", "me/c"}; !testSliceEqual(expect, refs) {
+		t.Errorf("refs mismatch. expected: %v, got: %v", expect, refs)
+	}
+
+	if _, err := readStdinRefs(strings.NewReader("\n# nothing here\n")); err == nil {
+		t.Error("expected empty input to error")
+	}
+}
+
+func TestEachRef(t *testing.T) {
+	streams, _, out, errs := ioes.NewTestIOStreams()
+	setNoColor(true)
+
+	processed := []string{}
+	err := eachRef(streams.Out, streams.ErrOut, []string{"me/a", "me/b", "me/c"}, func(ref string) error {
+		processed = append(processed, ref)
+		if ref == "me/b" {
+			return errors.New("oh noes")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Error("expected a failed ref to return an error")
+	}
+	if len(processed) != 3 {
+		t.Errorf("expected processing to continue past failures, processed: %v", processed)
+	}
+	if expect := "me/b: oh noes\n"; errs.String() != expect {
+		t.Errorf("error output mismatch. expected: %q, got: %q", expect, errs.String())
+	}
+	if expect := "processed 3 references: 2 succeeded, 1 failed\n"; out.String() != expect {
+		t.Errorf("output mismatch. expected: %q, got: %q", expect, out.String())
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/lib"
 	"github.com/qri-io/qri/repo"
@@ -65,6 +66,9 @@ func (r refStringer) String() string {
 	if ds != nil && ds.Meta != nil && ds.Meta.Title != "" {
 		fmt.Fprintf(w, "\n%s", ds.Meta.Title)
 	}
+	if base.HasLicense(ds) {
+		fmt.Fprintf(w, "\nlicense: %s", ds.Meta.License.Type)
+	}
 	if r.FSIPath != "" {
 		fmt.Fprintf(w, "\nlinked: %s", path(r.FSIPath))
 	} else if r.Path != "" {
@@ -159,6 +163,27 @@ func (l logStringer) String() string {
 	return w.String()
 }
 
+// logOnelineStringer formats a dataset version as a single line of history
+type logOnelineStringer repo.DatasetRef
+
+// String assumes Path, Timestamp and Title are present
+func (l logOnelineStringer) String() string {
+	path := color.New(color.FgGreen).SprintFunc()
+	dsr := repo.DatasetRef(l)
+	return fmt.Sprintf("%s %s %s\n", path(shortHash(dsr.Path)), dsr.Dataset.Commit.Timestamp.Format("Jan _2 15:04:05"), dsr.Dataset.Commit.Title)
+}
+
+// shortHash abbreviates a dataset path to the last 7 characters of its hash.
+// the start of a hash is the same for all paths from the same store, so the
+// end is used instead
+func shortHash(path string) string {
+	hash := path[strings.LastIndex(path, "/")+1:]
+	if len(hash) > 7 {
+		return hash[len(hash)-7:]
+	}
+	return hash
+}
+
 type jobStringer cron.Job
 
 // String assumes Name, Type, Periodicity, and PrevRunStart are present
@@ -196,6 +221,15 @@ func (j finishedJobStringer) String() string {
 		}
 	}
 
+	// dry runs don't change anything, flag them so they aren't mistaken for
+	// real updates
+	if job := cron.Job(j); job.DryRun() {
+		if j.RunError == "" {
+			msg = "dataset would be updated"
+		}
+		msg = "dry run | " + msg
+	}
+
 	fmt.Fprintf(w, "%s\n%s | %s\n", name(j.Name), humanize.Time(j.PrevRunStart), msg)
 	if j.RepoPath != "" {
 		fmt.Fprintf(w, "\nrepo: %s\n", j.RepoPath)
@@ -211,3 +245,88 @@ func oneLiner(str string, maxLen int) string {
 	}
 	return str
 }
+
+type trashEntryStringer lib.TrashEntry
+
+func (e trashEntryStringer) String() string {
+	w := &strings.Builder{}
+	title := color.New(color.FgGreen, color.Bold).SprintFunc()
+	path := color.New(color.Faint).SprintFunc()
+
+	fmt.Fprintf(w, "%s", title(e.Ref.AliasString()))
+	fmt.Fprintf(w, "\n%s", path(e.Ref.Path))
+	fmt.Fprintf(w, "\nremoved %s", humanize.Time(e.TrashedAt))
+	fmt.Fprintf(w, "\n\n")
+	return w.String()
+}
+
+type schemaVersionStringer lib.SchemaVersion
+
+func (v schemaVersionStringer) String() string {
+	w := &strings.Builder{}
+	path := color.New(color.FgGreen).SprintFunc()
+	added := color.New(color.FgGreen).SprintFunc()
+	removed := color.New(color.FgRed).SprintFunc()
+	changed := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Fprintf(w, "%s\n", path("path:   "+v.Path))
+	fmt.Fprintf(w, "Date:   %s\n", v.Timestamp.Format("Jan _2 15:04:05"))
+	if v.Title != "" {
+		fmt.Fprintf(w, "\n    %s\n", v.Title)
+	}
+	fmt.Fprintf(w, "\n")
+	for _, c := range v.Changes {
+		switch c.Change {
+		case lib.SchemaColumnAdded:
+			fmt.Fprintf(w, "    %s %s: %v\n", added("+"), c.Column, c.To)
+		case lib.SchemaColumnRemoved:
+			fmt.Fprintf(w, "    %s %s: %v\n", removed("-"), c.Column, c.From)
+		case lib.SchemaColumnTypeChanged:
+			fmt.Fprintf(w, "    %s %s: %v -> %v\n", changed("~"), c.Column, c.From, c.To)
+		}
+	}
+	fmt.Fprintf(w, "\n")
+	return w.String()
+}
+
+type duplicateClusterStringer lib.DuplicateCluster
+
+func (c duplicateClusterStringer) String() string {
+	w := &strings.Builder{}
+	title := color.New(color.FgGreen, color.Bold).SprintFunc()
+	path := color.New(color.Faint).SprintFunc()
+
+	fmt.Fprintf(w, "%s\n", title(fmt.Sprintf("%d datasets", len(c.Refs))))
+	for _, ref := range c.Refs {
+		fmt.Fprintf(w, "    %s %s\n", ref.AliasString(), path(ref.Path))
+	}
+	fmt.Fprintf(w, "\n")
+	return w.String()
+}
+
+type transformEstimateStringer lib.TransformEstimate
+
+func (e transformEstimateStringer) String() string {
+	w := &strings.Builder{}
+	title := color.New(color.Bold).SprintFunc()
+	warn := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Fprintf(w, "%s\n", title(fmt.Sprintf("transform inputs: %d", len(e.Inputs))))
+	for _, in := range e.Inputs {
+		if in.Size < 0 {
+			fmt.Fprintf(w, "    %s %s %s\n", in.Kind, in.Ref, warn("unknown size: "+in.Error))
+			continue
+		}
+		fmt.Fprintf(w, "    %s %s %s", in.Kind, in.Ref, humanize.Bytes(uint64(in.Size)))
+		if in.Entries > 0 {
+			fmt.Fprintf(w, ", %d entries", in.Entries)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	fmt.Fprintf(w, "total: %s", humanize.Bytes(uint64(e.TotalSize)))
+	if e.Unknown > 0 {
+		fmt.Fprintf(w, " %s", warn(fmt.Sprintf("plus %d input(s) of unknown size", e.Unknown)))
+	}
+	fmt.Fprintf(w, "\n")
+	return w.String()
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/lib"
 	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/update/cron"
 )
 
 func TestPeerStringer(t *testing.T) {
@@ -103,6 +104,19 @@ func TestRefStringer(t *testing.T) {
 				},
 			}, "\u001b[32;1mpeer/ds_name\u001b[0m\nDataset Title\n\u001b[2m/network/hash\u001b[0m\n10 B, 10 entries, 10 errors, 10 versions\n\n",
 		},
+		{"RefStringer - licensed",
+			&repo.DatasetRef{
+				Name:     "ds_name",
+				Peername: "peer",
+				Path:     "/network/hash",
+				Dataset: &dataset.Dataset{
+					Meta: &dataset.Meta{
+						Title:   "Dataset Title",
+						License: &dataset.License{Type: "CC-BY-4.0"},
+					},
+				},
+			}, "\u001b[32;1mpeer/ds_name\u001b[0m\nDataset Title\nlicense: CC-BY-4.0\n\u001b[2m/network/hash\u001b[0m\n\n",
+		},
 		{"RefStringer - only peername & name",
 			&repo.DatasetRef{
 				Peername: "peer",
@@ -160,6 +174,42 @@ func TestLogStringer(t *testing.T) {
 	}
 }
 
+func TestLogOnelineStringer(t *testing.T) {
+	setNoColor(true)
+	defer setNoColor(false)
+
+	ref := repo.DatasetRef{
+		Peername: "peer",
+		Path:     "/ipfs/QmPRjfgUFrH1GxBqujJ3sEvwV3gzHdux1j4g8SLyjbhwot",
+		Dataset: &dataset.Dataset{
+			Commit: &dataset.Commit{
+				Timestamp: time.Date(2001, 01, 01, 01, 01, 01, 01, time.UTC),
+				Title:     "commit title",
+				Message:   "commit message",
+			},
+		},
+	}
+	expect := "yjbhwot Jan  1 01:01:01 commit title\n"
+	if got := logOnelineStringer(ref).String(); got != expect {
+		t.Errorf("expected: %q, got: %q", expect, got)
+	}
+}
+
+func TestShortHash(t *testing.T) {
+	cases := []struct {
+		path, expect string
+	}{
+		{"/ipfs/QmPRjfgUFrH1GxBqujJ3sEvwV3gzHdux1j4g8SLyjbhwot", "yjbhwot"},
+		{"/map/QmHash", "QmHash"},
+		{"", ""},
+	}
+	for _, c := range cases {
+		if got := shortHash(c.path); got != c.expect {
+			t.Errorf("shortHash(%q) mismatch. expected: %q, got: %q", c.path, c.expect, got)
+		}
+	}
+}
+
 func TestJobStringer(t *testing.T) {
 	// NB: JobStringer is tough to write tests for at the moment
 	// thanks to printing in local timezones
@@ -193,3 +243,78 @@ func TestJobStringer(t *testing.T) {
 		}
 	}
 }
+
+func TestFinishedJobStringer(t *testing.T) {
+	setNoColor(true)
+	defer setNoColor(false)
+
+	job := lib.Job{Name: "Job", Type: cron.JTDataset}
+	if str := finishedJobStringer(job).String(); !strings.Contains(str, "dataset updated") {
+		t.Errorf("expected '%s' to contain 'dataset updated'", str)
+	}
+
+	job.Options = &cron.DatasetOptions{DryRun: true}
+	if str := finishedJobStringer(job).String(); !strings.Contains(str, "dry run | dataset would be updated") {
+		t.Errorf("expected '%s' to flag a dry run", str)
+	}
+
+	job.RunError = "no changes to save"
+	if str := finishedJobStringer(job).String(); !strings.Contains(str, "dry run | no changes to save") {
+		t.Errorf("expected '%s' to flag a failed dry run", str)
+	}
+}
+
+func TestSchemaVersionStringer(t *testing.T) {
+	setNoColor(true)
+	defer setNoColor(false)
+
+	v := lib.SchemaVersion{
+		Path:      "/ipfs/QmSchema",
+		Timestamp: time.Date(2001, 01, 01, 01, 01, 01, 01, time.UTC),
+		Title:     "add in_usa column",
+		Changes: []lib.SchemaChange{
+			{Change: lib.SchemaColumnRemoved, Column: "avg_age", From: "number"},
+			{Change: lib.SchemaColumnTypeChanged, Column: "pop", From: "integer", To: "number"},
+			{Change: lib.SchemaColumnAdded, Column: "in_usa", To: "boolean"},
+		},
+	}
+	expect := "path:   /ipfs/QmSchema\nDate:   Jan  1 01:01:01\n\n    add in_usa column\n\n    - avg_age: number\n    ~ pop: integer -> number\n    + in_usa: boolean\n\n"
+	if got := schemaVersionStringer(v).String(); got != expect {
+		t.Errorf("result mismatch.\nexpected:\n%q\ngot:\n%q", expect, got)
+	}
+}
+
+func TestDuplicateClusterStringer(t *testing.T) {
+	setNoColor(true)
+	defer setNoColor(false)
+
+	c := lib.DuplicateCluster{
+		Key: "/ipfs/QmBody",
+		Refs: []repo.DatasetRef{
+			{Peername: "peer", Name: "cities", Path: "/ipfs/QmOne"},
+			{Peername: "peer", Name: "cities_copy", Path: "/ipfs/QmTwo"},
+		},
+	}
+	expect := "2 datasets\n    peer/cities /ipfs/QmOne\n    peer/cities_copy /ipfs/QmTwo\n\n"
+	if got := duplicateClusterStringer(c).String(); got != expect {
+		t.Errorf("result mismatch.\nexpected:\n%q\ngot:\n%q", expect, got)
+	}
+}
+
+func TestTransformEstimateStringer(t *testing.T) {
+	setNoColor(true)
+	defer setNoColor(false)
+
+	e := lib.TransformEstimate{
+		Inputs: []lib.TransformInput{
+			{Kind: "dataset", Ref: "peer/cities", Size: 2048, Entries: 5},
+			{Kind: "url", Ref: "https://example.com/data.csv", Size: -1, Error: "server didn't report a size"},
+		},
+		TotalSize: 2048,
+		Unknown:   1,
+	}
+	expect := "transform inputs: 2\n    dataset peer/cities 2.0 kB, 5 entries\n    url https://example.com/data.csv unknown size: server didn't report a size\ntotal: 2.0 kB plus 1 input(s) of unknown size\n"
+	if got := transformEstimateStringer(e).String(); got != expect {
+		t.Errorf("result mismatch.\nexpected:\n%q\ngot:\n%q", expect, got)
+	}
+}
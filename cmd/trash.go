@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+
+	util "github.com/qri-io/apiutil"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewTrashCommand creates a new `qri trash` cobra command for working with
+// soft-deleted datasets
+func NewTrashCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &TrashOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Work with datasets removed with qri remove --soft",
+		Long: `
+Datasets removed with 'qri remove --soft' are moved to the trash instead of
+being deleted. Trashed datasets keep their content until they've been in the
+trash longer than the retention window, which defaults to 30 days & is set
+with the repo.trashretention config field.
+
+Use 'qri restore --from-trash' to bring a dataset back.`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	list := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List datasets in the trash",
+		Example: `  # list trashed datasets
+  $ qri trash list`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.List()
+		},
+	}
+	list.Flags().IntVar(&o.PageSize, "page-size", 25, "page size of results, default 25")
+	list.Flags().IntVar(&o.Page, "page", 1, "page number of results, default 1")
+
+	sweep := &cobra.Command{
+		Use:   "sweep",
+		Short: "Permanently remove datasets past the trash retention window",
+		Long: `
+Sweep permanently removes datasets that have been in the trash longer than the
+retention window. Qri sweeps the trash automatically when it's used, sweep
+forces a sweep right away.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Sweep()
+		},
+	}
+
+	cmd.AddCommand(list, sweep)
+	return cmd
+}
+
+// TrashOptions encapsulates state for the trash command
+type TrashOptions struct {
+	ioes.IOStreams
+
+	PageSize int
+	Page     int
+
+	DatasetRequests *lib.DatasetRequests
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *TrashOptions) Complete(f Factory, args []string) (err error) {
+	o.DatasetRequests, err = f.DatasetRequests()
+	return
+}
+
+// List executes the trash list command
+func (o *TrashOptions) List() error {
+	page := util.NewPage(o.Page, o.PageSize)
+	p := &lib.ListParams{
+		Limit:  page.Limit(),
+		Offset: page.Offset(),
+	}
+
+	res := []lib.TrashEntry{}
+	if err := o.DatasetRequests.ListTrash(p, &res); err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		printInfo(o.Out, "trash is empty")
+		return nil
+	}
+
+	items := make([]fmt.Stringer, len(res))
+	for i, e := range res {
+		items[i] = trashEntryStringer(e)
+	}
+	return printItems(o.Out, items, page.Offset())
+}
+
+// Sweep executes the trash sweep command
+func (o *TrashOptions) Sweep() error {
+	in := true
+	res := []lib.TrashEntry{}
+	if err := o.DatasetRequests.SweepTrash(&in, &res); err != nil {
+		return err
+	}
+
+	for _, e := range res {
+		printSuccess(o.Out, "permanently removed %s", e.Ref.AliasString())
+	}
+	if len(res) == 0 {
+		printInfo(o.Out, "no datasets past the trash retention window")
+	}
+	return nil
+}
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/jsonschema"
-	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/fsi"
 	"github.com/qri-io/qri/lib"
 	"github.com/qri-io/qri/repo"
 	"github.com/spf13/cobra"
@@ -53,6 +57,11 @@ will affect a dataset before saving changes to a dataset.
 You can get the current schema of a dataset by running the ` + "`qri get structure.schema`" + `
 command.
 
+Validate also works in a directory linked to a dataset, checking the body file
+against the schema in the directory, or pass the path to a linked directory.
+Validate exits with a non-zero status when it finds errors, which makes it
+useful for checking data in CI.
+
 Note: --body and --schema flags will override the dataset if both flags are provided.`,
 		Example: `  # show errors in an existing dataset:
   qri validate b5/comics
@@ -61,7 +70,13 @@ Note: --body and --schema flags will override the dataset if both flags are prov
   qri validate --body new_data.csv me/annual_pop
 
   # validate data against a new schema
-  qri validate --body data.csv --schema schema.json`,
+  qri validate --body data.csv --schema schema.json
+
+  # validate the files in a linked directory
+  qri validate ./annual_pop
+
+  # validate a large csv body using 4 workers
+  qri validate --workers 4 --body big_data.csv me/annual_pop`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := o.Complete(f, args); err != nil {
 				return err
@@ -74,6 +89,7 @@ Note: --body and --schema flags will override the dataset if both flags are prov
 	// cmd.Flags().StringVarP(&o.URL, "url", "u", "", "url to file to initialize from")
 	cmd.Flags().StringVarP(&o.BodyFilepath, "body", "b", "", "body file to validate")
 	cmd.Flags().StringVarP(&o.SchemaFilepath, "schema", "", "", "json schema file to use for validation")
+	cmd.Flags().IntVar(&o.Workers, "workers", 1, "number of csv body chunks to validate in parallel")
 
 	return cmd
 }
@@ -86,6 +102,7 @@ type ValidateOptions struct {
 	BodyFilepath   string
 	SchemaFilepath string
 	URL            string
+	Workers        int
 
 	DatasetRequests *lib.DatasetRequests
 }
@@ -96,7 +113,18 @@ func (o *ValidateOptions) Complete(f Factory, args []string) (err error) {
 		return
 	}
 
-	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	// a directory argument that's linked to a dataset validates the working
+	// directory's files
+	if len(args) > 0 {
+		if dir, e := filepath.Abs(args[0]); e == nil {
+			if ref, ok := fsi.GetLinkedFilesysRef(dir); ok {
+				o.Refs = NewLinkedDirectoryRefSelect(ref, dir)
+				return nil
+			}
+		}
+	}
+
+	o.Refs, err = GetOptionalRefSelect(f, args, 1)
 	if err == repo.ErrEmptyRef {
 		// It is not an error to call validate without a dataset reference. Might be
 		// validating a body file against a schema file directly.
@@ -110,6 +138,7 @@ func (o *ValidateOptions) Complete(f Factory, args []string) (err error) {
 func (o *ValidateOptions) Run() (err error) {
 	var (
 		bodyFile, schemaFile *os.File
+		linkedSchema         []byte
 	)
 
 	printRefSelect(o.Out, o.Refs)
@@ -118,22 +147,20 @@ func (o *ValidateOptions) Run() (err error) {
 	defer o.StopSpinner()
 
 	if o.Refs.IsLinked() {
+		ds, fileMap, problems, err := fsi.ReadDir(o.Refs.Dir())
+		if err != nil {
+			return err
+		}
 		if o.BodyFilepath == "" {
-			// TODO(dlong): FSI should determine the filename by looking for each known file
-			// extension.
-			if _, err := os.Stat("body.json"); !os.IsNotExist(err) {
-				o.BodyFilepath = "body.json"
-			}
-			if _, err := os.Stat("body.csv"); !os.IsNotExist(err) {
-				o.BodyFilepath = "body.csv"
-			}
-			if err = qfs.AbsPath(&o.BodyFilepath); err != nil {
-				return err
+			// bodies that fail to parse are listed as problems, validate them anyway
+			if st, ok := fileMap["body"]; ok {
+				o.BodyFilepath = st.Path
+			} else if st, ok := problems["body"]; ok {
+				o.BodyFilepath = st.Path
 			}
 		}
-		if o.SchemaFilepath == "" {
-			o.SchemaFilepath = "schema.json"
-			if err = qfs.AbsPath(&o.SchemaFilepath); err != nil {
+		if o.SchemaFilepath == "" && ds.Structure != nil && ds.Structure.Schema != nil {
+			if linkedSchema, err = json.Marshal(ds.Structure.Schema); err != nil {
 				return err
 			}
 		}
@@ -156,6 +183,7 @@ func (o *ValidateOptions) Run() (err error) {
 		// TODO: restore
 		// URL:          addDsURL,
 		BodyFilename: filepath.Base(o.BodyFilepath),
+		Workers:      o.Workers,
 	}
 
 	// this is because passing nil to interfaces is bad
@@ -165,6 +193,8 @@ func (o *ValidateOptions) Run() (err error) {
 	}
 	if schemaFile != nil {
 		p.Schema = schemaFile
+	} else if linkedSchema != nil {
+		p.Schema = bytes.NewReader(linkedSchema)
 	}
 
 	res := []jsonschema.ValError{}
@@ -180,7 +210,27 @@ func (o *ValidateOptions) Run() (err error) {
 	}
 
 	for i, err := range res {
-		fmt.Fprintf(o.Out, "%d: %s\n", i, err.Error())
+		fmt.Fprintf(o.Out, "%d: %s\n", i, valErrorString(err))
+	}
+	return fmt.Errorf("dataset is invalid. %d problem(s) found", len(res))
+}
+
+// valErrorString describes a validation error by the row & column of the body
+// entry it refers to, falling back to the property path for errors that
+// aren't within a row
+func valErrorString(err jsonschema.ValError) string {
+	path := strings.Split(strings.TrimPrefix(err.PropertyPath, "/"), "/")
+	if _, e := strconv.Atoi(path[0]); e != nil {
+		return err.Error()
+	}
+
+	msg := strings.TrimPrefix(err.Error(), err.PropertyPath+": ")
+	switch len(path) {
+	case 1:
+		return fmt.Sprintf("row %s: %s", path[0], msg)
+	case 2:
+		return fmt.Sprintf("row %s, column %s: %s", path[0], path[1], msg)
+	default:
+		return err.Error()
 	}
-	return nil
 }
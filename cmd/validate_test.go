@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/qri-io/ioes"
+	"github.com/qri-io/jsonschema"
 	"github.com/qri-io/qri/lib"
 )
 
@@ -89,7 +90,7 @@ func TestValidateRun(t *testing.T) {
 		{"bad args", "", "", "", "", "", "bad arguments provided", "please provide a dataset name, or a supply the --body and --schema flags with file paths"},
 		// TODO: add back when we again support validating from a URL
 		// {"", "", "", "url", "", "bad arguments provided", "if you are validating data from a url, please include a dataset name or supply the --schema flag with a file path that Qri can validate against"},
-		{"movie problems", "peer/movies", "", "", "", movieOutput, "", ""},
+		{"movie problems", "peer/movies", "", "", "", movieOutput, "dataset is invalid. 4 problem(s) found", ""},
 		{"dataset not found", "peer/bad_dataset", "", "", "", "", "cannot find dataset: peer/bad_dataset@QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt", ""},
 		{"body file not found", "", "bad/filepath", "testdata/days_of_week_schema.json", "", "", "open " + path + "/bad/filepath: no such file or directory", "error opening body file: could not open " + path + "/bad/filepath: no such file or directory"},
 		{"schema file not found", "", "testdata/days_of_week.csv", "bad/schema_filepath", "", "", "open " + path + "/bad/schema_filepath: no such file or directory", "error opening schema file: could not open " + path + "/bad/schema_filepath: no such file or directory"},
@@ -142,8 +143,25 @@ func TestValidateRun(t *testing.T) {
 	}
 }
 
-var movieOutput = `0: /4/1: "" type should be integer
-1: /199/1: "" type should be integer
-2: /206/1: "" type should be integer
-3: /1510/1: "" type should be integer
+var movieOutput = `0: row 4, column 1: "" type should be integer
+1: row 199, column 1: "" type should be integer
+2: row 206, column 1: "" type should be integer
+3: row 1510, column 1: "" type should be integer
 `
+
+func TestValErrorString(t *testing.T) {
+	cases := []struct {
+		err    jsonschema.ValError
+		expect string
+	}{
+		{jsonschema.ValError{PropertyPath: "/4/1", Message: "type should be integer"}, "row 4, column 1: type should be integer"},
+		{jsonschema.ValError{PropertyPath: "/4/title", Message: "type should be string"}, "row 4, column title: type should be string"},
+		{jsonschema.ValError{PropertyPath: "/4", Message: "type should be array"}, "row 4: type should be array"},
+		{jsonschema.ValError{PropertyPath: "/title", Message: "type should be string"}, "/title: type should be string"},
+	}
+	for i, c := range cases {
+		if got := valErrorString(c.err); got != c.expect {
+			t.Errorf("case %d: expected: %q, got: %q", i, c.expect, got)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/lib"
 	"github.com/spf13/cobra"
@@ -8,20 +13,75 @@ import (
 
 // NewVersionCommand creates a new `qri version` cobra command that prints the current qri version
 func NewVersionCommand(_ Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &VersionOptions{IOStreams: ioStreams}
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version number",
 		Long: `
 Qri uses semantic versioning.
 
+Version prints the version number. Use --verbose for the git commit, go
+version, p2p protocol tag & versions of key dependencies qri was built with,
+or --json for the same details as JSON. Include these details in bug reports.
+
 For updates & further information check https://github.com/qri-io/qri/releases`,
 		Annotations: map[string]string{
 			"group": "other",
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			printInfo(ioStreams.Out, lib.VersionNumber)
-			return nil
+			return o.Run()
 		},
 	}
+
+	cmd.Flags().BoolVarP(&o.Verbose, "verbose", "v", false, "print build details")
+	cmd.Flags().BoolVar(&o.JSON, "json", false, "print build details as json")
 	return cmd
 }
+
+// VersionOptions encapsulates state for the version command
+type VersionOptions struct {
+	ioes.IOStreams
+
+	Verbose bool
+	JSON    bool
+}
+
+// Run executes the version command
+func (o *VersionOptions) Run() error {
+	v := lib.Version()
+
+	if o.JSON {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+		return nil
+	}
+
+	if !o.Verbose {
+		printInfo(o.Out, v.Version)
+		return nil
+	}
+
+	printVersionInfo(o.Out, v)
+	return nil
+}
+
+func printVersionInfo(w io.Writer, v *lib.VersionInfo) {
+	fmt.Fprintf(w, "qri version:  %s\n", v.Version)
+	fmt.Fprintf(w, "git commit:   %s\n", v.GitCommit)
+	fmt.Fprintf(w, "go version:   %s\n", v.GoVersion)
+	fmt.Fprintf(w, "platform:     %s\n", v.Platform)
+	fmt.Fprintf(w, "protocol tag: %s\n", v.ProtocolTag)
+
+	mods := make([]string, 0, len(v.Dependencies))
+	for mod := range v.Dependencies {
+		mods = append(mods, mod)
+	}
+	sort.Strings(mods)
+	fmt.Fprintln(w, "dependencies:")
+	for _, mod := range mods {
+		fmt.Fprintf(w, "  %s %s\n", mod, v.Dependencies[mod])
+	}
+}
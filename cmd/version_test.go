@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+)
+
+func TestVersionRun(t *testing.T) {
+	streams, _, out, _ := ioes.NewTestIOStreams()
+	o := &VersionOptions{IOStreams: streams}
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(out.String()); got != lib.VersionNumber {
+		t.Errorf("expected bare version number. got: %q", got)
+	}
+
+	out.Reset()
+	o.Verbose = true
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"qri version:  " + lib.VersionNumber, "protocol tag: qri/", "github.com/ipfs/go-ipfs"} {
+		if !strings.Contains(out.String(), expect) {
+			t.Errorf("expected verbose output to contain %q. got:\n%s", expect, out.String())
+		}
+	}
+
+	out.Reset()
+	o.JSON = true
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	v := &lib.VersionInfo{}
+	if err := json.Unmarshal(out.Bytes(), v); err != nil {
+		t.Fatalf("expected json output: %s", err)
+	}
+	if v.Version != lib.VersionNumber || v.ProtocolTag == "" || v.GoVersion == "" {
+		t.Errorf("incomplete json version info: %v", v)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewWhoamiCommand creates a new `qri whoami` cobra command that prints the
+// active profile
+func NewWhoamiCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &WhoamiOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the active profile",
+		Long: `
+Whoami prints the peername & profile ID qri is acting as, and the registry
+this qri node uses, if any. Use --json for the same details as JSON.`,
+		Annotations: map[string]string{
+			"group": "other",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.JSON, "json", false, "print profile details as json")
+	return cmd
+}
+
+// WhoamiOptions encapsulates state for the whoami command
+type WhoamiOptions struct {
+	ioes.IOStreams
+
+	JSON bool
+
+	ProfileMethods *lib.ProfileMethods
+	Config         *config.Config
+}
+
+// Whoami describes the active profile
+type Whoami struct {
+	Peername  string `json:"peername"`
+	ProfileID string `json:"profileID"`
+	// Registry is the location of the configured registry, empty if no
+	// registry is configured
+	Registry string `json:"registry"`
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *WhoamiOptions) Complete(f Factory) (err error) {
+	if o.ProfileMethods, err = f.ProfileMethods(); err != nil {
+		return err
+	}
+	o.Config, err = f.Config()
+	return err
+}
+
+// Run executes the whoami command
+func (o *WhoamiOptions) Run() error {
+	var (
+		in  = true
+		res = &config.ProfilePod{}
+	)
+	if err := o.ProfileMethods.GetProfile(&in, res); err != nil {
+		return err
+	}
+
+	w := Whoami{
+		Peername:  res.Peername,
+		ProfileID: res.ID,
+	}
+	if o.Config != nil && o.Config.Registry != nil {
+		w.Registry = o.Config.Registry.Location
+	}
+
+	if o.JSON {
+		data, err := json.MarshalIndent(w, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(o.Out, "peername:   %s\n", w.Peername)
+	fmt.Fprintf(o.Out, "profile ID: %s\n", w.ProfileID)
+	if w.Registry == "" {
+		fmt.Fprintln(o.Out, "registry:   none")
+	} else {
+		fmt.Fprintf(o.Out, "registry:   %s\n", w.Registry)
+	}
+	return nil
+}
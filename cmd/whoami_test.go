@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/config"
+)
+
+func TestWhoamiRun(t *testing.T) {
+	streams, _, out, _ := ioes.NewTestIOStreams()
+
+	f, err := NewTestFactory()
+	if err != nil {
+		t.Fatalf("error creating new test factory: %s", err)
+	}
+
+	o := &WhoamiOptions{IOStreams: streams}
+	if err := o.Complete(f); err != nil {
+		t.Fatal(err)
+	}
+	o.Config = o.Config.Copy()
+	o.Config.Registry = nil
+
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"peername:   peer\n", "profile ID: ", "registry:   none\n"} {
+		if !strings.Contains(out.String(), expect) {
+			t.Errorf("expected output to contain %q. got:\n%s", expect, out.String())
+		}
+	}
+
+	out.Reset()
+	o.Config.Registry = &config.Registry{Location: "https://registry.qri.cloud"}
+	o.JSON = true
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	w := &Whoami{}
+	if err := json.Unmarshal(out.Bytes(), w); err != nil {
+		t.Fatalf("expected json output: %s", err)
+	}
+	if w.Peername != "peer" || w.ProfileID == "" {
+		t.Errorf("incomplete json profile details: %v", w)
+	}
+	if w.Registry != "https://registry.qri.cloud" {
+		t.Errorf("registry mismatch. got: %q", w.Registry)
+	}
+}
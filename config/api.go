@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"time"
 
@@ -11,6 +12,10 @@ import (
 // DefaultAPIPort is local the port webapp serves on by default
 var DefaultAPIPort = 2503
 
+// DefaultAPIShutdownTimeout is the number of seconds the api waits for
+// in-flight requests to finish when shutting down, if no timeout is configured
+var DefaultAPIShutdownTimeout = 30
+
 // API holds configuration for the qri JSON api
 type API struct {
 	Enabled bool `json:"enabled"`
@@ -34,16 +39,42 @@ type API struct {
 	URLRoot string `json:"urlroot"`
 	// TLS enables https via letsEyncrypt
 	TLS bool `json:"tls"`
+	// CertFile & KeyFile are paths to a TLS certificate & matching private
+	// key. when both are set the api serves https using them, taking
+	// precedence over letsEncrypt
+	CertFile string `json:"certfile,omitempty"`
+	KeyFile  string `json:"keyfile,omitempty"`
 	// Time in seconds to stop the server after,
 	// default 0 means keep alive indefinitely
 	DisconnectAfter int `json:"disconnectafter,omitempty"`
 	// if true, requests that have X-Forwarded-Proto: http will be redirected
 	// to their https variant
 	ProxyForceHTTPS bool `json:"proxyforcehttps"`
-	// support CORS signing from a list of origins
+	// TrustedProxies lists the addresses or CIDR ranges of proxies in front of
+	// the api. the X-Forwarded-For header is only read from requests made by a
+	// trusted proxy
+	TrustedProxies []string `json:"trustedproxies,omitempty"`
+	// AllowedOrigins lists origins browsers may make cross-origin requests
+	// from. "*" allows any origin
 	AllowedOrigins []string `json:"allowedorigins"`
 	// whether to allow requests from addresses other than localhost
 	ServeRemoteTraffic bool `json:"serveremotetraffic"`
+	// RateLimit is the number of requests per minute a single client may make,
+	// default 0 means no limit
+	RateLimit int `json:"ratelimit,omitempty"`
+	// RateLimitBurst is the number of requests a client may make at once before
+	// being limited. defaults to RateLimit when unset
+	RateLimitBurst int `json:"ratelimitburst,omitempty"`
+	// ShutdownTimeout is the number of seconds to wait for in-flight requests
+	// to finish when the server shuts down. default 0 waits
+	// DefaultAPIShutdownTimeout seconds
+	ShutdownTimeout int `json:"shutdowntimeout,omitempty"`
+	// PhotoMaxSize is the largest profile photo upload accepted, in bytes.
+	// default 0 uses the built-in limit
+	PhotoMaxSize int64 `json:"photomaxsize,omitempty"`
+	// PosterMaxSize is the largest poster photo upload accepted, in bytes.
+	// default 0 uses the built-in limit
+	PosterMaxSize int64 `json:"postermaxsize,omitempty"`
 }
 
 // Validate validates all fields of api returning all errors found.
@@ -75,6 +106,14 @@ func (a API) Validate() error {
         "description": "Enables https via letsEncrypt",
         "type": "boolean"
       },
+      "certfile": {
+        "description": "Path to a TLS certificate file. When set with keyfile the api serves https",
+        "type": "string"
+      },
+      "keyfile": {
+        "description": "Path to the private key file for certfile",
+        "type": "string"
+      },
       "disconnectafter": {
         "description": "time in seconds to stop the server after",
         "type": "integer"
@@ -83,6 +122,38 @@ func (a API) Validate() error {
         "description": "When true, requests that have X-Forwarded-Proto: http will be redirected to their https variant",
         "type": "boolean"
       },
+      "trustedproxies": {
+        "description": "Addresses or CIDR ranges of proxies allowed to set X-Forwarded-For",
+        "type": "array",
+        "items": {
+          "type": "string"
+        }
+      },
+      "ratelimit": {
+        "description": "Number of requests per minute a single client may make. 0 means no limit",
+        "type": "integer",
+        "minimum": 0
+      },
+      "ratelimitburst": {
+        "description": "Number of requests a client may make at once before being limited. defaults to ratelimit",
+        "type": "integer",
+        "minimum": 0
+      },
+      "shutdowntimeout": {
+        "description": "Number of seconds to wait for in-flight requests to finish when shutting down. 0 uses the default",
+        "type": "integer",
+        "minimum": 0
+      },
+      "photomaxsize": {
+        "description": "Largest profile photo upload accepted, in bytes. 0 uses the default",
+        "type": "integer",
+        "minimum": 0
+      },
+      "postermaxsize": {
+        "description": "Largest poster photo upload accepted, in bytes. 0 uses the default",
+        "type": "integer",
+        "minimum": 0
+      },
       "allowedorigins": {
         "description": "Support CORS signing from a list of origins",
         "type": "array",
@@ -92,9 +163,41 @@ func (a API) Validate() error {
       }
     }
   }`)
+	if (a.CertFile == "") != (a.KeyFile == "") {
+		return fmt.Errorf("api certfile and keyfile must be set together")
+	}
+	for _, p := range a.TrustedProxies {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			return fmt.Errorf("api trustedproxies: %q isn't an IP address or CIDR range", p)
+		}
+	}
 	return validate(schema, &a)
 }
 
+// TrustsProxy returns true if addr is the IP address of a trusted proxy
+func (a *API) TrustsProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, p := range a.TrustedProxies {
+		if _, cidr, err := net.ParseCIDR(p); err == nil {
+			if cidr.Contains(ip) {
+				return true
+			}
+		} else if ip.Equal(net.ParseIP(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeTLSFiles returns true if the api is configured to serve https using
+// certificate & key files
+func (a *API) ServeTLSFiles() bool {
+	return a.CertFile != "" && a.KeyFile != ""
+}
+
 // DefaultAPI returns the default configuration details
 func DefaultAPI() *API {
 	return &API{
@@ -118,13 +221,33 @@ func (a *API) Copy() *API {
 		ReadOnly:           a.ReadOnly,
 		URLRoot:            a.URLRoot,
 		TLS:                a.TLS,
+		CertFile:           a.CertFile,
+		KeyFile:            a.KeyFile,
 		DisconnectAfter:    a.DisconnectAfter,
 		ProxyForceHTTPS:    a.ProxyForceHTTPS,
 		ServeRemoteTraffic: a.ServeRemoteTraffic,
+		RateLimit:          a.RateLimit,
+		RateLimitBurst:     a.RateLimitBurst,
+		ShutdownTimeout:    a.ShutdownTimeout,
+		PhotoMaxSize:       a.PhotoMaxSize,
+		PosterMaxSize:      a.PosterMaxSize,
 	}
 	if a.AllowedOrigins != nil {
 		res.AllowedOrigins = make([]string, len(a.AllowedOrigins))
 		reflect.Copy(reflect.ValueOf(res.AllowedOrigins), reflect.ValueOf(a.AllowedOrigins))
 	}
+	if a.TrustedProxies != nil {
+		res.TrustedProxies = make([]string, len(a.TrustedProxies))
+		copy(res.TrustedProxies, a.TrustedProxies)
+	}
 	return res
 }
+
+// ShutdownWait returns how long the server waits for in-flight requests to
+// finish when shutting down
+func (a *API) ShutdownWait() time.Duration {
+	if a.ShutdownTimeout > 0 {
+		return time.Second * time.Duration(a.ShutdownTimeout)
+	}
+	return time.Second * time.Duration(DefaultAPIShutdownTimeout)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestAPIValidate(t *testing.T) {
@@ -12,6 +13,60 @@ func TestAPIValidate(t *testing.T) {
 	}
 }
 
+func TestAPIValidateRateLimit(t *testing.T) {
+	a := DefaultAPI()
+	a.RateLimit = 120
+	a.RateLimitBurst = 20
+	if err := a.Validate(); err != nil {
+		t.Errorf("error validating rate limited api: %s", err)
+	}
+	a.RateLimit = -1
+	if err := a.Validate(); err == nil {
+		t.Error("expected negative rate limit to fail validation")
+	}
+}
+
+func TestAPIValidateTLSFiles(t *testing.T) {
+	a := DefaultAPI()
+	a.CertFile = "/etc/qri/cert.pem"
+	if err := a.Validate(); err == nil {
+		t.Error("expected certfile without keyfile to fail validation")
+	}
+	if a.ServeTLSFiles() {
+		t.Error("expected ServeTLSFiles to be false without a keyfile")
+	}
+	a.KeyFile = "/etc/qri/key.pem"
+	if err := a.Validate(); err != nil {
+		t.Errorf("error validating api with tls files: %s", err)
+	}
+	if !a.ServeTLSFiles() {
+		t.Error("expected ServeTLSFiles to be true")
+	}
+}
+
+func TestAPITrustedProxies(t *testing.T) {
+	a := DefaultAPI()
+	a.TrustedProxies = []string{"10.0.0.1", "192.168.0.0/16"}
+	if err := a.Validate(); err != nil {
+		t.Errorf("error validating trusted proxies: %s", err)
+	}
+	for addr, expect := range map[string]bool{
+		"10.0.0.1":    true,
+		"192.168.4.2": true,
+		"10.0.0.2":    false,
+		"not-an-ip":   false,
+	} {
+		if got := a.TrustsProxy(addr); got != expect {
+			t.Errorf("TrustsProxy(%q): expected %t, got %t", addr, expect, got)
+		}
+	}
+
+	a.TrustedProxies = []string{"proxy.local"}
+	if err := a.Validate(); err == nil {
+		t.Error("expected a hostname trusted proxy to fail validation")
+	}
+}
+
 func TestAPICopy(t *testing.T) {
 	cases := []struct {
 		description string
@@ -25,6 +80,24 @@ func TestAPICopy(t *testing.T) {
 			ProxyForceHTTPS:    true,
 			ServeRemoteTraffic: true,
 		}},
+		{"rate limits", &API{
+			RateLimit:      60,
+			RateLimitBurst: 10,
+		}},
+		{"trusted proxies", &API{
+			TrustedProxies: []string{"10.0.0.1"},
+		}},
+		{"tls files", &API{
+			CertFile: "cert.pem",
+			KeyFile:  "key.pem",
+		}},
+		{"shutdown timeout", &API{
+			ShutdownTimeout: 5,
+		}},
+		{"upload limits", &API{
+			PhotoMaxSize:  500000,
+			PosterMaxSize: 4000000,
+		}},
 	}
 	for i, c := range cases {
 		cpy := c.api.Copy()
@@ -41,3 +114,14 @@ func TestAPICopy(t *testing.T) {
 		}
 	}
 }
+
+func TestAPIShutdownWait(t *testing.T) {
+	a := DefaultAPI()
+	if got, expect := a.ShutdownWait(), time.Second*time.Duration(DefaultAPIShutdownTimeout); got != expect {
+		t.Errorf("default shutdown wait mismatch. expected: %s, got: %s", expect, got)
+	}
+	a.ShutdownTimeout = 5
+	if got := a.ShutdownWait(); got != time.Second*5 {
+		t.Errorf("shutdown wait mismatch. expected: 5s, got: %s", got)
+	}
+}
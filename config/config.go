@@ -20,6 +20,8 @@ const CurrentConfigRevision = 1
 // Config encapsulates all configuration details for qri
 type Config struct {
 	path string
+	// values expanded from environment variable references when read
+	interpolated map[string]interpolation
 
 	Revision int
 	Profile  *ProfilePod
@@ -31,6 +33,8 @@ type Config struct {
 	Registry *Registry
 	Remotes  *Remotes
 	Remote   *Remote
+	// RemoteAuth holds credentials for remotes, keyed by remote name
+	RemoteAuth *RemoteAuth
 
 	CLI     *CLI
 	API     *API
@@ -97,7 +101,9 @@ func (cfg Config) SummaryString() (summary string) {
 	return summary
 }
 
-// ReadFromFile reads a YAML configuration file from path
+// ReadFromFile reads a YAML configuration file from path. References to
+// environment variables in string values, written as ${VAR} or
+// ${VAR:-default}, are expanded. Use $$ for a literal $
 func ReadFromFile(path string) (*Config, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -109,7 +115,15 @@ func ReadFromFile(path string) (*Config, error) {
 		return nil, err
 	}
 
+	interpolated, err := interpolateFields(fields, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{path: path}
+	if len(interpolated) > 0 {
+		cfg.interpolated = interpolated
+	}
 	if err = fill.Struct(fields, cfg); err != nil {
 		return nil, err
 	}
@@ -127,7 +141,9 @@ func (cfg Config) Path() string {
 	return cfg.path
 }
 
-// WriteToFile encodes a configration to YAML and writes it to path
+// WriteToFile encodes a configration to YAML and writes it to path. Values
+// read from environment variables are written back as the references they
+// were expanded from
 func (cfg Config) WriteToFile(path string) error {
 	// Never serialize the address mapping to the configuration file.
 	prev := cfg.Profile.PeerIDs
@@ -136,10 +152,18 @@ func (cfg Config) WriteToFile(path string) error {
 	cfg.Profile.PeerIDs = nil
 	defer func() { cfg.Profile.PeerIDs = prev }()
 
-	data, err := yaml.Marshal(cfg)
+	data, err := json.Marshal(cfg)
 	if err != nil {
 		return err
 	}
+	if len(cfg.interpolated) > 0 {
+		if data, err = restoreTemplates(data, cfg.interpolated); err != nil {
+			return err
+		}
+	}
+	if data, err = yaml.JSONToYAML(data); err != nil {
+		return err
+	}
 
 	return ioutil.WriteFile(path, data, 06777)
 }
@@ -239,6 +263,80 @@ func (cfg Config) Validate() error {
 	return nil
 }
 
+// FieldError is a validation error attributed to a specific config field
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// CrossFieldErrors checks for conflicts between config fields that are each
+// valid on their own, like two enabled services listening on the same port.
+// Unlike Validate, CrossFieldErrors reports every problem it finds
+func (cfg Config) CrossFieldErrors() []error {
+	var errs []error
+
+	type service struct {
+		field   string
+		enabled bool
+		port    int
+	}
+	var services []service
+	if cfg.API != nil {
+		services = append(services, service{"api.port", cfg.API.Enabled, cfg.API.Port})
+	}
+	if cfg.RPC != nil {
+		services = append(services, service{"rpc.port", cfg.RPC.Enabled, cfg.RPC.Port})
+	}
+	if cfg.Webapp != nil {
+		services = append(services, service{"webapp.port", cfg.Webapp.Enabled, cfg.Webapp.Port})
+	}
+	for i, a := range services {
+		if !a.enabled {
+			continue
+		}
+		for _, b := range services[i+1:] {
+			if b.enabled && a.port == b.port {
+				errs = append(errs, FieldError{b.field, fmt.Sprintf("port %d is already used by %s", b.port, a.field)})
+			}
+		}
+	}
+
+	if cfg.Remote != nil && cfg.Remote.Enabled && (cfg.P2P == nil || !cfg.P2P.Enabled) {
+		errs = append(errs, FieldError{"remote.enabled", "acting as a remote requires p2p.enabled to be true"})
+	}
+	// bitswap hands blocks to any connected peer without checking access
+	// lists, so restricted remotes may only connect to trusted peers
+	if cfg.Remote.Restricted() && cfg.P2P != nil && cfg.P2P.Enabled && len(cfg.P2P.AllowedPeers) == 0 {
+		errs = append(errs, FieldError{"p2p.allowedpeers", "remote allow & deny lists require p2p.allowedpeers to be set, peers can fetch any block over bitswap"})
+	}
+
+	if cfg.Store != nil && cfg.Store.Type == "ipfs_http" {
+		if url, ok := cfg.Store.Options["url"].(string); !ok || url == "" {
+			errs = append(errs, FieldError{"store.options.url", "ipfs_http store requires a url option"})
+		}
+	}
+	if cfg.Store != nil && cfg.Store.Type == "badger" {
+		if path, _ := cfg.Store.Options["path"].(string); path == "" && cfg.Store.Path == "" {
+			errs = append(errs, FieldError{"store.options.path", "badger store requires a path option"})
+		}
+	}
+	if cfg.Store != nil && cfg.Store.Type == "gcs" {
+		if bucket, _ := cfg.Store.Options["bucket"].(string); bucket == "" {
+			errs = append(errs, FieldError{"store.options.bucket", "gcs store requires a bucket option"})
+		}
+	}
+	if cfg.Store != nil && cfg.Store.Type == "ipfs" && cfg.Store.ReadOnly() {
+		errs = append(errs, FieldError{"store.options.readonly", "ipfs store doesn't support read-only mode, use an ipfs_http store instead"})
+	}
+
+	return errs
+}
+
 // Copy returns a deep copy of the Config struct
 func (cfg *Config) Copy() *Config {
 	res := &Config{
@@ -247,6 +345,12 @@ func (cfg *Config) Copy() *Config {
 	if cfg.path != "" {
 		res.path = cfg.path
 	}
+	if cfg.interpolated != nil {
+		res.interpolated = make(map[string]interpolation, len(cfg.interpolated))
+		for path, interp := range cfg.interpolated {
+			res.interpolated[path] = interp
+		}
+	}
 	if cfg.Profile != nil {
 		res.Profile = cfg.Profile.Copy()
 	}
@@ -283,6 +387,9 @@ func (cfg *Config) Copy() *Config {
 	if cfg.Remotes != nil {
 		res.Remotes = cfg.Remotes.Copy()
 	}
+	if cfg.RemoteAuth != nil {
+		res.RemoteAuth = cfg.RemoteAuth.Copy()
+	}
 	if cfg.Logging != nil {
 		res.Logging = cfg.Logging.Copy()
 	}
@@ -299,17 +406,26 @@ func (cfg *Config) WithoutPrivateValues() *Config {
 
 	res.Profile.PrivKey = ""
 	res.P2P.PrivKey = ""
+	res.RemoteAuth = nil
 
 	return res
 }
 
 // WithPrivateValues returns a deep copy of the receiver with the private values from
-// the *Config passed in from the params
+// the *Config passed in from the params. sections missing from either config
+// are left as-is
 func (cfg *Config) WithPrivateValues(p *Config) *Config {
 	res := cfg.Copy()
 
-	res.Profile.PrivKey = p.Profile.PrivKey
-	res.P2P.PrivKey = p.P2P.PrivKey
+	if res.Profile != nil && p.Profile != nil {
+		res.Profile.PrivKey = p.Profile.PrivKey
+	}
+	if res.P2P != nil && p.P2P != nil {
+		res.P2P.PrivKey = p.P2P.PrivKey
+	}
+	if res.RemoteAuth == nil && p.RemoteAuth != nil {
+		res.RemoteAuth = p.RemoteAuth.Copy()
+	}
 
 	return res
 }
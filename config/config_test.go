@@ -128,6 +128,8 @@ func TestConfigSet(t *testing.T) {
 		{"p2p.qribootstrapaddrs.0", "wahoo", ""},
 		{"p2p.qribootstrapaddrs.0", false, "at \"p2p.qribootstrapaddrs.0\": need string, got bool: false"},
 		{"logging.levels.qriapi", "debug", ""},
+		{"remoteauth.origin.token", "secret", ""},
+		{"remoteauth.origin.headers.X-Api-Key", "key", ""},
 	}
 
 	for i, c := range cases {
@@ -224,3 +226,99 @@ func TestConfigCopy(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigPrivateRemoteAuth(t *testing.T) {
+	cfg := DefaultConfigForTesting()
+	cfg.RemoteAuth = &RemoteAuth{"origin": {Token: "secret"}}
+
+	public := cfg.WithoutPrivateValues()
+	if public.RemoteAuth != nil {
+		t.Errorf("expected remote credentials to be removed, got: %v", public.RemoteAuth)
+	}
+	if cfg.RemoteAuth == nil {
+		t.Error("removing private values shouldn't modify the original config")
+	}
+
+	restored := public.WithPrivateValues(cfg)
+	if creds, ok := restored.RemoteAuth.Get("origin"); !ok || creds.Token != "secret" {
+		t.Errorf("expected remote credentials to be restored, got: %v", restored.RemoteAuth)
+	}
+}
+
+func TestConfigCrossFieldErrors(t *testing.T) {
+	if errs := DefaultConfigForTesting().CrossFieldErrors(); len(errs) != 0 {
+		t.Errorf("expected default config to have no cross-field errors. got: %v", errs)
+	}
+
+	cfg := DefaultConfigForTesting()
+	cfg.API.Enabled = true
+	cfg.RPC.Enabled = true
+	cfg.RPC.Port = cfg.API.Port
+	cfg.Remote = &Remote{Enabled: true}
+	cfg.P2P.Enabled = false
+	cfg.Store.Type = "ipfs_http"
+	cfg.Store.Options = nil
+
+	expect := []string{
+		fmt.Sprintf("rpc.port: port %d is already used by api.port", cfg.API.Port),
+		"remote.enabled: acting as a remote requires p2p.enabled to be true",
+		"store.options.url: ipfs_http store requires a url option",
+	}
+	errs := cfg.CrossFieldErrors()
+	if len(errs) != len(expect) {
+		t.Fatalf("error count mismatch. want: %d, got: %d %v", len(expect), len(errs), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != expect[i] {
+			t.Errorf("error %d mismatch. want: %q, got: %q", i, expect[i], err.Error())
+		}
+	}
+
+	// disabled services don't conflict
+	cfg.RPC.Enabled = false
+	for _, err := range cfg.CrossFieldErrors() {
+		if fe, ok := err.(FieldError); ok && fe.Field == "rpc.port" {
+			t.Errorf("expected disabled rpc not to conflict. got: %s", err)
+		}
+	}
+
+	cfg = DefaultConfigForTesting()
+	cfg.Store.Options = map[string]interface{}{StoreOptionReadOnly: true}
+	errs = cfg.CrossFieldErrors()
+	if len(errs) != 1 || errs[0].(FieldError).Field != "store.options.readonly" {
+		t.Errorf("expected read-only ipfs store error. got: %v", errs)
+	}
+
+	cfg = DefaultConfigForTesting()
+	cfg.Store = &Store{Type: "badger"}
+	errs = cfg.CrossFieldErrors()
+	if len(errs) != 1 || errs[0].Error() != "store.options.path: badger store requires a path option" {
+		t.Errorf("expected badger store path error. got: %v", errs)
+	}
+	cfg.Store.Options = map[string]interface{}{"path": "/path/to/store"}
+	if errs = cfg.CrossFieldErrors(); len(errs) != 0 {
+		t.Errorf("expected no errors. got: %v", errs)
+	}
+
+	cfg = DefaultConfigForTesting()
+	cfg.Store = &Store{Type: "gcs"}
+	errs = cfg.CrossFieldErrors()
+	if len(errs) != 1 || errs[0].Error() != "store.options.bucket: gcs store requires a bucket option" {
+		t.Errorf("expected gcs store bucket error. got: %v", errs)
+	}
+	cfg.Store.Options = map[string]interface{}{"bucket": "qri-datasets"}
+	if errs = cfg.CrossFieldErrors(); len(errs) != 0 {
+		t.Errorf("expected no errors. got: %v", errs)
+	}
+
+	cfg = DefaultConfigForTesting()
+	cfg.Remote = &Remote{Enabled: true, Allow: []string{"b5/*"}}
+	errs = cfg.CrossFieldErrors()
+	if len(errs) != 1 || errs[0].(FieldError).Field != "p2p.allowedpeers" {
+		t.Errorf("expected restricted remote allowed peers error. got: %v", errs)
+	}
+	cfg.P2P.AllowedPeers = []string{"QmNX9nSos8sRFvqGTwdEme6LQ8R1eJ8EuFgW32F9jjp2Pb"}
+	if errs = cfg.CrossFieldErrors(); len(errs) != 0 {
+		t.Errorf("expected no errors. got: %v", errs)
+	}
+}
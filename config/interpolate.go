@@ -0,0 +1,200 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// InterpolationExcludedPaths lists lower-case config paths that are never
+// interpolated. Private keys are excluded so they can't be swapped out through
+// the environment
+var InterpolationExcludedPaths = map[string]bool{
+	"profile.privkey": true,
+	"p2p.privkey":     true,
+}
+
+// interpolation records a config value that was expanded from a template
+type interpolation struct {
+	Template string
+	Value    string
+}
+
+// interpolateFields expands environment variable references in the string
+// values of a decoded config file in place. ${VAR} is replaced with the value
+// of VAR & is an error if VAR isn't set. ${VAR:-default} falls back to default
+// when VAR is unset or empty. $$ is a literal $. interpolateFields returns the
+// expanded values keyed by dot-separated path
+func interpolateFields(fields map[string]interface{}, lookup func(string) (string, bool)) (map[string]interpolation, error) {
+	expanded := map[string]interpolation{}
+	err := interpolateValue(fields, "", lookup, expanded)
+	return expanded, err
+}
+
+func interpolateValue(val interface{}, path string, lookup func(string) (string, bool), expanded map[string]interpolation) error {
+	if InterpolationExcludedPaths[strings.ToLower(path)] {
+		return nil
+	}
+
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := joinPath(path, key)
+			if s, ok := child.(string); ok {
+				if InterpolationExcludedPaths[strings.ToLower(childPath)] {
+					continue
+				}
+				res, err := interpolateString(s, lookup)
+				if err != nil {
+					return fmt.Errorf("config %s: %s", childPath, err)
+				}
+				if res != s {
+					v[key] = res
+					expanded[childPath] = interpolation{Template: s, Value: res}
+				}
+				continue
+			}
+			if err := interpolateValue(child, childPath, lookup, expanded); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			childPath := joinPath(path, strconv.Itoa(i))
+			if s, ok := child.(string); ok {
+				res, err := interpolateString(s, lookup)
+				if err != nil {
+					return fmt.Errorf("config %s: %s", childPath, err)
+				}
+				if res != s {
+					v[i] = res
+					expanded[childPath] = interpolation{Template: s, Value: res}
+				}
+				continue
+			}
+			if err := interpolateValue(child, childPath, lookup, expanded); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// interpolateString expands environment variable references in a single
+// string. a $ that doesn't start a reference or escape is kept as-is
+func interpolateString(s string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference in %q", s)
+			}
+			ref := s[i+2 : i+2+end]
+			name, def, hasDefault := ref, "", false
+			if idx := strings.Index(ref, ":-"); idx >= 0 {
+				name, def, hasDefault = ref[:idx], ref[idx+2:], true
+			}
+			if !isEnvVarName(name) {
+				return "", fmt.Errorf("invalid variable name %q", name)
+			}
+
+			val, ok := lookup(name)
+			if hasDefault && val == "" {
+				val = def
+			} else if !ok {
+				return "", fmt.Errorf("undefined environment variable %q", name)
+			}
+			b.WriteString(val)
+			i += 2 + end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
+
+func isEnvVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// restoreTemplates swaps interpolated values in an encoded config back to the
+// templates they were expanded from, so writing a config doesn't persist the
+// environment. values that have been changed since the config was read are
+// kept
+func restoreTemplates(data []byte, expanded map[string]interpolation) ([]byte, error) {
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for path, interp := range expanded {
+		setIfValue(fields, strings.Split(path, "."), interp.Value, interp.Template)
+	}
+	return json.Marshal(fields)
+}
+
+// setIfValue sets the string at a path in decoded json to replace if it
+// currently equals value. map keys are matched case-insensitively
+func setIfValue(val interface{}, steps []string, value, replace string) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if !strings.EqualFold(key, steps[0]) {
+				continue
+			}
+			if len(steps) == 1 {
+				if child == value {
+					v[key] = replace
+				}
+				return
+			}
+			setIfValue(child, steps[1:], value, replace)
+			return
+		}
+	case []interface{}:
+		i, err := strconv.Atoi(steps[0])
+		if err != nil || i < 0 || i >= len(v) {
+			return
+		}
+		if len(steps) == 1 {
+			if v[i] == value {
+				v[i] = replace
+			}
+			return
+		}
+		setIfValue(v[i], steps[1:], value, replace)
+	}
+}
+
+// lookupEnv reads environment variables for interpolation
+var lookupEnv = os.LookupEnv
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestInterpolateString(t *testing.T) {
+	lookup := testLookup(map[string]string{
+		"HOST":  "registry.qri.io",
+		"PORT":  "2503",
+		"EMPTY": "",
+	})
+
+	cases := []struct {
+		in, expect, err string
+	}{
+		{"no references", "no references", ""},
+		{"${HOST}", "registry.qri.io", ""},
+		{"https://${HOST}:${PORT}/", "https://registry.qri.io:2503/", ""},
+		{"${MISSING:-fallback}", "fallback", ""},
+		{"${EMPTY:-fallback}", "fallback", ""},
+		{"${HOST:-fallback}", "registry.qri.io", ""},
+		{"${MISSING:-}", "", ""},
+		{"${EMPTY}", "", ""},
+		{"$${HOST}", "${HOST}", ""},
+		{"cost: $$5", "cost: $5", ""},
+		{"lone $ sign$", "lone $ sign$", ""},
+		{"$HOST", "$HOST", ""},
+		{"${MISSING}", "", `undefined environment variable "MISSING"`},
+		{"${HOST", "", `unterminated variable reference in "${HOST"`},
+		{"${1HOST}", "", `invalid variable name "1HOST"`},
+		{"${}", "", `invalid variable name ""`},
+	}
+
+	for _, c := range cases {
+		got, err := interpolateString(c.in, lookup)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("%q: error mismatch. expected: %q, got: %v", c.in, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.in, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("%q: expected %q, got %q", c.in, c.expect, got)
+		}
+	}
+}
+
+func TestInterpolateFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"registry": map[string]interface{}{"location": "${REGISTRY}"},
+		"p2p": map[string]interface{}{
+			"privkey":           "${REGISTRY}",
+			"qribootstrapaddrs": []interface{}{"/dns4/${REGISTRY}", "/ip4/127.0.0.1"},
+		},
+		"profile": map[string]interface{}{"PrivKey": "${REGISTRY}"},
+	}
+	expanded, err := interpolateFields(fields, testLookup(map[string]string{"REGISTRY": "example.com"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fields["registry"].(map[string]interface{})["location"]; got != "example.com" {
+		t.Errorf("expected registry location to be interpolated, got: %v", got)
+	}
+	p2p := fields["p2p"].(map[string]interface{})
+	if got := p2p["qribootstrapaddrs"].([]interface{})[0]; got != "/dns4/example.com" {
+		t.Errorf("expected array values to be interpolated, got: %v", got)
+	}
+	if got := p2p["privkey"]; got != "${REGISTRY}" {
+		t.Errorf("expected p2p private key to be excluded, got: %v", got)
+	}
+	if got := fields["profile"].(map[string]interface{})["PrivKey"]; got != "${REGISTRY}" {
+		t.Errorf("expected profile private key to be excluded regardless of case, got: %v", got)
+	}
+	if len(expanded) != 2 {
+		t.Errorf("expected 2 expanded values, got: %v", expanded)
+	}
+
+	fields = map[string]interface{}{"remote": map[string]interface{}{"address": "${NOPE}"}}
+	_, err = interpolateFields(fields, testLookup(nil))
+	if err == nil || err.Error() != `config remote.address: undefined environment variable "NOPE"` {
+		t.Errorf("expected undefined variable error naming the path, got: %v", err)
+	}
+}
+
+func TestReadFromFileInterpolation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qri_test_config_interpolation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	prev := lookupEnv
+	defer func() { lookupEnv = prev }()
+	lookupEnv = testLookup(map[string]string{"QRI_TEST_REGISTRY": "https://registry.example.com"})
+
+	data, err := ioutil.ReadFile("testdata/default.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	data = append(data, []byte("registry:\n  location: ${QRI_TEST_REGISTRY}\n")...)
+	if err := ioutil.WriteFile(path, data, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ReadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Registry.Location != "https://registry.example.com" {
+		t.Errorf("expected interpolated registry location, got: %q", cfg.Registry.Location)
+	}
+
+	// writing keeps the reference instead of the value from the environment
+	if err := cfg.Copy().WriteToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	written, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), "${QRI_TEST_REGISTRY}") {
+		t.Errorf("expected written config to keep the variable reference, got:\n%s", written)
+	}
+
+	// changed values are written as-is
+	cfg.Registry.Location = "https://other.example.com"
+	if err := cfg.WriteToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if written, err = ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(written), "${QRI_TEST_REGISTRY}") || !strings.Contains(string(written), "https://other.example.com") {
+		t.Errorf("expected written config to contain the changed value, got:\n%s", written)
+	}
+
+	lookupEnv = testLookup(nil)
+	if err := ioutil.WriteFile(path, []byte("registry:\n  location: ${QRI_TEST_UNSET}\n"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFromFile(path); err == nil {
+		t.Error("expected undefined variable to error")
+	}
+}
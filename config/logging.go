@@ -6,6 +6,8 @@ import "github.com/qri-io/jsonschema"
 type Logging struct {
 	// Levels is a map of package_name : log_level (one of [info, error, debug, warn])
 	Levels map[string]string `json:"levels"`
+	// Format is the log output format, one of [text, json]. empty means text
+	Format string `json:"format,omitempty"`
 }
 
 // DefaultLogging produces a new default logging configuration
@@ -41,6 +43,11 @@ func (l Logging) Validate() error {
             ]
           }
         }
+      },
+      "format": {
+        "description": "Output format for logs",
+        "type": "string",
+        "enum": ["", "text", "json"]
       }
     }
   }`)
@@ -49,7 +56,7 @@ func (l Logging) Validate() error {
 
 // Copy returns a deep copy of a Logging struct
 func (l *Logging) Copy() *Logging {
-	res := &Logging{}
+	res := &Logging{Format: l.Format}
 	if l.Levels != nil {
 		res.Levels = map[string]string{}
 		for key, value := range l.Levels {
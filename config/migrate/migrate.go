@@ -6,12 +6,42 @@ import (
 	"github.com/qri-io/qri/config"
 )
 
+// Migration upgrades a configuration from one revision to the next
+type Migration struct {
+	// Name is a short description of the migration
+	Name string
+	// From is the revision this migration applies to. migrated configurations
+	// have revision From+1
+	From int
+	// Migrate modifies a configuration in place
+	Migrate func(cfg *config.Config) error
+}
+
+// Migrations lists all configuration migrations in the order they're applied
+var Migrations = []Migration{
+	{Name: "zero to one: update qri bootstrap addresses", From: 0, Migrate: ZeroToOne},
+}
+
+// Pending lists the migrations that need to run to bring a configuration to
+// the current revision
+func Pending(cfg *config.Config) []Migration {
+	var pending []Migration
+	for _, m := range Migrations {
+		if m.From >= cfg.Revision && m.From < config.CurrentConfigRevision {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
 // RunMigrations checks to see if any migrations runs them
 func RunMigrations(streams ioes.IOStreams, cfg *config.Config) (migrated bool, err error) {
 	if cfg.Revision != config.CurrentConfigRevision {
 		streams.PrintErr("migrating configuration...")
-		if err := ZeroToOne(cfg); err != nil {
-			return false, err
+		for _, m := range Pending(cfg) {
+			if err := m.Migrate(cfg); err != nil {
+				return false, err
+			}
 		}
 		streams.PrintErr("done!\n")
 		return true, nil
@@ -49,6 +49,17 @@ type P2P struct {
 
 	// Enable AutoNAT service. unless you're hosting a server, leave this as false
 	AutoNAT bool `json:"autoNAT"`
+
+	// BlockedPeers lists peer IDs this node will never connect to
+	BlockedPeers []string `json:"blockedpeers,omitempty"`
+	// AllowedPeers restricts connections to only the listed peer IDs. an empty
+	// list allows connections to any peer that isn't blocked
+	AllowedPeers []string `json:"allowedpeers,omitempty"`
+
+	// MaxMessageSize is the largest qri protocol message in bytes this node
+	// will read from a peer. Larger messages are rejected & the stream is
+	// dropped. zero uses the default of 8MiB
+	MaxMessageSize int64 `json:"maxmessagesize,omitempty"`
 }
 
 // DefaultP2P generates a p2p struct with only bootstrap addresses set
@@ -165,6 +176,25 @@ func (cfg P2P) Validate() error {
         "items": {
           "type": "string"
         }
+      },
+      "blockedpeers": {
+        "description": "List of peer IDs this node will never connect to",
+        "type": "array",
+        "items": {
+          "type": "string"
+        }
+      },
+      "allowedpeers": {
+        "description": "List of peer IDs this node is restricted to connecting with. An empty list allows any peer that isn't blocked",
+        "type": "array",
+        "items": {
+          "type": "string"
+        }
+      },
+      "maxmessagesize": {
+        "description": "Largest qri protocol message in bytes this node will read from a peer. 0 uses the default",
+        "type": "integer",
+        "minimum": 0
       }
     }
   }`)
@@ -181,6 +211,7 @@ func (cfg *P2P) Copy() *P2P {
 		Port:               cfg.Port,
 		ProfileReplication: cfg.ProfileReplication,
 		HTTPGatewayAddr:    cfg.HTTPGatewayAddr,
+		MaxMessageSize:     cfg.MaxMessageSize,
 	}
 
 	if cfg.QriBootstrapAddrs != nil {
@@ -193,5 +224,15 @@ func (cfg *P2P) Copy() *P2P {
 		reflect.Copy(reflect.ValueOf(res.BootstrapAddrs), reflect.ValueOf(cfg.BootstrapAddrs))
 	}
 
+	if cfg.BlockedPeers != nil {
+		res.BlockedPeers = make([]string, len(cfg.BlockedPeers))
+		reflect.Copy(reflect.ValueOf(res.BlockedPeers), reflect.ValueOf(cfg.BlockedPeers))
+	}
+
+	if cfg.AllowedPeers != nil {
+		res.AllowedPeers = make([]string, len(cfg.AllowedPeers))
+		reflect.Copy(reflect.ValueOf(res.AllowedPeers), reflect.ValueOf(cfg.AllowedPeers))
+	}
+
 	return res
 }
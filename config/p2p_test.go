@@ -36,6 +36,12 @@ func TestP2PValidate(t *testing.T) {
 	if err != nil {
 		t.Errorf("error validating default p2p: %s", err)
 	}
+
+	p2p := DefaultP2PForTesting()
+	p2p.MaxMessageSize = -1
+	if err := p2p.Validate(); err == nil {
+		t.Error("expected negative max message size to be invalid")
+	}
 }
 
 func TestP2PCopy(t *testing.T) {
@@ -43,6 +49,11 @@ func TestP2PCopy(t *testing.T) {
 		p2p *P2P
 	}{
 		{DefaultP2PForTesting()},
+		{func() *P2P {
+			p := DefaultP2PForTesting()
+			p.MaxMessageSize = 1024
+			return p
+		}()},
 	}
 	for i, c := range cases {
 		cpy := c.p2p.Copy()
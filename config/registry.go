@@ -1,12 +1,20 @@
 package config
 
 import (
+	"time"
+
 	"github.com/qri-io/jsonschema"
 )
 
 // Registry encapsulates configuration options for centralized qri registries
 type Registry struct {
 	Location string `json:"location"`
+	// Fallbacks lists registry locations to try in order when Location can't
+	// be reached, for use with registry mirrors
+	Fallbacks []string `json:"fallbacks,omitempty"`
+	// TimeoutSeconds limits how long a single request to a registry location
+	// may take. zero means no limit
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
 }
 
 // DefaultRegistry generates a new default registry instance
@@ -29,6 +37,18 @@ func (cfg Registry) Validate() error {
       "location": {
         "description": "the",
         "type": "string"
+      },
+      "fallbacks": {
+        "description": "registry locations to try when location can't be reached",
+        "type": "array",
+        "items": {
+          "type": "string"
+        }
+      },
+      "timeoutSeconds": {
+        "description": "seconds to wait on a registry request before giving up, zero for no limit",
+        "type": "integer",
+        "minimum": 0
       }
     }
   }`)
@@ -38,7 +58,29 @@ func (cfg Registry) Validate() error {
 // Copy makes a deep copy of the Registry struct
 func (cfg *Registry) Copy() *Registry {
 	res := &Registry{
-		Location: cfg.Location,
+		Location:       cfg.Location,
+		TimeoutSeconds: cfg.TimeoutSeconds,
+	}
+	if cfg.Fallbacks != nil {
+		res.Fallbacks = make([]string, len(cfg.Fallbacks))
+		copy(res.Fallbacks, cfg.Fallbacks)
 	}
 	return res
 }
+
+// Locations lists all configured registry locations in the order they should
+// be tried, skipping empty entries
+func (cfg *Registry) Locations() []string {
+	var locs []string
+	for _, loc := range append([]string{cfg.Location}, cfg.Fallbacks...) {
+		if loc != "" {
+			locs = append(locs, loc)
+		}
+	}
+	return locs
+}
+
+// Timeout gives TimeoutSeconds as a duration
+func (cfg *Registry) Timeout() time.Duration {
+	return time.Duration(cfg.TimeoutSeconds) * time.Second
+}
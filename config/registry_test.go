@@ -10,6 +10,12 @@ func TestRegistryValidate(t *testing.T) {
 	if err != nil {
 		t.Errorf("error validating default registry: %s", err)
 	}
+
+	r := DefaultRegistry()
+	r.TimeoutSeconds = -1
+	if err := r.Validate(); err == nil {
+		t.Error("expected negative timeout to fail validation")
+	}
 }
 
 func TestRegistryCopy(t *testing.T) {
@@ -17,6 +23,8 @@ func TestRegistryCopy(t *testing.T) {
 		registry *Registry
 	}{
 		{DefaultRegistry()},
+		{&Registry{Location: "https://registry.qri.cloud", Fallbacks: []string{"https://mirror.example.com"}}},
+		{&Registry{Location: "https://registry.qri.cloud", TimeoutSeconds: 30}},
 	}
 	for i, c := range cases {
 		cpy := c.registry.Copy()
@@ -31,3 +39,20 @@ func TestRegistryCopy(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistryLocations(t *testing.T) {
+	cases := []struct {
+		registry *Registry
+		expect   []string
+	}{
+		{&Registry{}, nil},
+		{DefaultRegistry(), []string{"https://registry.qri.cloud"}},
+		{&Registry{Location: "a", Fallbacks: []string{"", "b", "c"}}, []string{"a", "b", "c"}},
+		{&Registry{Fallbacks: []string{"b"}}, []string{"b"}},
+	}
+	for i, c := range cases {
+		if got := c.registry.Locations(); !reflect.DeepEqual(c.expect, got) {
+			t.Errorf("case %d: expected: %v, got: %v", i, c.expect, got)
+		}
+	}
+}
@@ -19,6 +19,24 @@ type Remote struct {
 	RequireAllBlocks bool `json:"requireallblocks"`
 	// allow clients to request unpins for their own pushes
 	AllowRemoves bool `json:"allowremoves"`
+	// reject pushes of datasets that don't record a license in metadata
+	RequireLicense bool `json:"requirelicense"`
+	// number of blocks to request at once when pulling datasets from HTTP
+	// remotes, zero uses the default
+	PullConcurrency int `json:"pullconcurrency,omitempty"`
+	// dataset references this remote will serve, in "peername/name" form.
+	// Patterns may use glob syntax, eg: "b5/*". When set, all other datasets
+	// are refused
+	Allow []string `json:"allow,omitempty"`
+	// dataset references this remote won't serve. Deny takes precedence over
+	// Allow
+	Deny []string `json:"deny,omitempty"`
+}
+
+// Restricted reports whether the remote limits the datasets it serves with
+// allow or deny patterns
+func (cfg *Remote) Restricted() bool {
+	return cfg != nil && cfg.Enabled && (len(cfg.Allow) > 0 || len(cfg.Deny) > 0)
 }
 
 // Validate validates all fields of render returning all errors found.
@@ -50,6 +68,16 @@ func (cfg *Remote) Copy() *Remote {
 		AcceptTimeoutMs:  cfg.AcceptTimeoutMs,
 		RequireAllBlocks: cfg.RequireAllBlocks,
 		AllowRemoves:     cfg.AllowRemoves,
+		RequireLicense:   cfg.RequireLicense,
+		PullConcurrency:  cfg.PullConcurrency,
+	}
+	if cfg.Allow != nil {
+		res.Allow = make([]string, len(cfg.Allow))
+		copy(res.Allow, cfg.Allow)
+	}
+	if cfg.Deny != nil {
+		res.Deny = make([]string, len(cfg.Deny))
+		copy(res.Deny, cfg.Deny)
 	}
 
 	return res
@@ -18,6 +18,9 @@ func TestRemoteCopy(t *testing.T) {
 		remote *Remote
 	}{
 		{&Remote{}},
+		{&Remote{AcceptSizeMax: -1, RequireLicense: true}},
+		{&Remote{PullConcurrency: 16}},
+		{&Remote{Allow: []string{"b5/*"}, Deny: []string{"b5/secret"}}},
 	}
 	for i, c := range cases {
 		cpy := c.remote.Copy()
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/http"
 )
 
 // Remotes encapsulates configuration options for remotes
@@ -31,3 +32,56 @@ func (r *Remotes) Copy() *Remotes {
 	}
 	return (*Remotes)(&c)
 }
+
+// RemoteAuth maps remote names to credentials attached to HTTP requests made
+// to that remote, for remotes behind an authenticating proxy or API gateway
+type RemoteAuth map[string]*RemoteCredentials
+
+// RemoteCredentials are sent with every HTTP request to a remote
+type RemoteCredentials struct {
+	// Token is sent as a bearer token in the Authorization header
+	Token string `json:"token,omitempty"`
+	// Headers are added to every request. an Authorization header set here
+	// takes precedence over Token
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Get retrieves credentials for a remote name
+func (a *RemoteAuth) Get(name string) (*RemoteCredentials, bool) {
+	if a == nil {
+		return nil, false
+	}
+	c, ok := (*a)[name]
+	return c, ok && c != nil
+}
+
+// Copy creates a deep copy of a RemoteAuth map
+func (a *RemoteAuth) Copy() *RemoteAuth {
+	c := make(RemoteAuth, len(*a))
+	for name, creds := range *a {
+		if creds == nil {
+			continue
+		}
+		cp := &RemoteCredentials{Token: creds.Token}
+		if creds.Headers != nil {
+			cp.Headers = make(map[string]string, len(creds.Headers))
+			for k, v := range creds.Headers {
+				cp.Headers[k] = v
+			}
+		}
+		c[name] = cp
+	}
+	return &c
+}
+
+// Header gives credentials as HTTP request headers
+func (c *RemoteCredentials) Header() http.Header {
+	h := http.Header{}
+	if c.Token != "" {
+		h.Set("Authorization", "Bearer "+c.Token)
+	}
+	for k, v := range c.Headers {
+		h.Set(k, v)
+	}
+	return h
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"reflect"
 
+	"github.com/qri-io/iso8601"
 	"github.com/qri-io/jsonschema"
 )
 
@@ -11,6 +13,14 @@ type Repo struct {
 	Middleware []string `json:"middleware"`
 	Type       string   `json:"type"`
 	Path       string   `json:"path,omitempty"`
+	// TrashRetention is an ISO 8601 duration (eg. "P30D") soft-deleted datasets
+	// are kept in the trash for before being permanently removed. empty uses
+	// the default of 30 days
+	TrashRetention string `json:"trashretention,omitempty"`
+	// BodyIndexMinSize is the smallest body size in bytes that gets a row
+	// index when saved, speeding up reads of deep pages. zero uses the default
+	// of 1MB, a negative value turns off body indexing
+	BodyIndexMinSize int64 `json:"bodyindexminsize,omitempty"`
 }
 
 // DefaultRepo creates & returns a new default repo configuration
@@ -44,16 +54,34 @@ func (cfg Repo) Validate() error {
           "fs",
           "mem"
         ]
+      },
+      "trashretention": {
+        "description": "ISO 8601 duration soft-deleted datasets are kept in the trash for",
+        "type": "string"
+      },
+      "bodyindexminsize": {
+        "description": "smallest body size in bytes indexed on save, negative to turn off indexing",
+        "type": "integer"
       }
     }
   }`)
-	return validate(schema, &cfg)
+	if err := validate(schema, &cfg); err != nil {
+		return err
+	}
+	if cfg.TrashRetention != "" {
+		if _, err := iso8601.ParseDuration(cfg.TrashRetention); err != nil {
+			return fmt.Errorf("trashretention: invalid ISO 8601 duration %q: %s", cfg.TrashRetention, err)
+		}
+	}
+	return nil
 }
 
 // Copy returns a deep copy of the Repo struct
 func (cfg *Repo) Copy() *Repo {
 	res := &Repo{
-		Type: cfg.Type,
+		Type:             cfg.Type,
+		TrashRetention:   cfg.TrashRetention,
+		BodyIndexMinSize: cfg.BodyIndexMinSize,
 	}
 	if cfg.Middleware != nil {
 		res.Middleware = make([]string, len(cfg.Middleware))
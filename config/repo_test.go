@@ -10,6 +10,22 @@ func TestRepoValidate(t *testing.T) {
 	if err != nil {
 		t.Errorf("error validating default repo: %s", err)
 	}
+
+	r := DefaultRepo()
+	r.TrashRetention = "P7D"
+	if err := r.Validate(); err != nil {
+		t.Errorf("error validating repo with trash retention: %s", err)
+	}
+	r.TrashRetention = "7 days"
+	if err := r.Validate(); err == nil {
+		t.Error("expected invalid trashretention to error")
+	}
+
+	r = DefaultRepo()
+	r.BodyIndexMinSize = -1
+	if err := r.Validate(); err != nil {
+		t.Errorf("error validating repo with body indexing turned off: %s", err)
+	}
 }
 
 func TestRepoCopy(t *testing.T) {
@@ -17,6 +33,8 @@ func TestRepoCopy(t *testing.T) {
 	// actually copies over correctly (ie, deeply)
 	r := DefaultRepo()
 	r.Middleware = []string{"firstMiddleware"}
+	r.TrashRetention = "P30D"
+	r.BodyIndexMinSize = 4096
 
 	cases := []struct {
 		repo *Repo
@@ -9,6 +9,10 @@ type Store struct {
 	Path    string                 `json:"path,omitempty"`
 }
 
+// StoreOptionReadOnly is the store option that marks a store as read-only.
+// saves to a read-only store fail up front instead of partway through writing
+const StoreOptionReadOnly = "readonly"
+
 // DefaultStore returns a new default Store configuration
 func DefaultStore() *Store {
 	return &Store{
@@ -34,7 +38,9 @@ func (cfg Store) Validate() error {
         "enum": [
 					"ipfs",
 					"ipfs_http",
-					"map"
+					"map",
+					"badger",
+					"gcs"
         ]
       }
     }
@@ -42,11 +48,72 @@ func (cfg Store) Validate() error {
 	return validate(schema, &cfg)
 }
 
+// ReadOnly returns true if the store is configured to reject writes
+func (cfg *Store) ReadOnly() bool {
+	if cfg == nil {
+		return false
+	}
+	ro, _ := cfg.Options[StoreOptionReadOnly].(bool)
+	return ro
+}
+
+// StoreOptionCacheSize is the store option that sets the number of bytes of
+// recently read blocks an ipfs_http store keeps in memory. zero disables the
+// cache
+const StoreOptionCacheSize = "cacheSize"
+
+// CacheSize gives the configured block cache size in bytes, zero if the
+// option is missing or isn't a positive number
+func (cfg *Store) CacheSize() int64 {
+	if cfg == nil {
+		return 0
+	}
+	var size int64
+	switch v := cfg.Options[StoreOptionCacheSize].(type) {
+	case int:
+		size = int64(v)
+	case int64:
+		size = v
+	case float64:
+		size = int64(v)
+	}
+	if size < 0 {
+		return 0
+	}
+	return size
+}
+
+// StoreOptionPinPolicy is the store option that controls which versions of a
+// dataset stay pinned
+const StoreOptionPinPolicy = "pinPolicy"
+
+const (
+	// PinPolicyAll keeps every version of a dataset pinned. this is the default
+	PinPolicyAll = "all"
+	// PinPolicyLatest keeps only the latest version of a dataset pinned. when
+	// a new version is saved or pushed, the prior version is unpinned. blocks
+	// the versions share stay pinned by the new version
+	PinPolicyLatest = "latest"
+)
+
+// PinPolicy gives the configured pin policy, PinPolicyAll if the option is
+// missing or unrecognized
+func (cfg *Store) PinPolicy() string {
+	if cfg == nil {
+		return PinPolicyAll
+	}
+	if p, _ := cfg.Options[StoreOptionPinPolicy].(string); p == PinPolicyLatest {
+		return PinPolicyLatest
+	}
+	return PinPolicyAll
+}
+
 // Copy returns a deep copy of the Store struct
 func (cfg *Store) Copy() *Store {
 	res := &Store{
 		Type:    cfg.Type,
 		Options: cfg.Options,
+		Path:    cfg.Path,
 	}
 
 	return res
@@ -17,6 +17,8 @@ func TestStoreCopy(t *testing.T) {
 		store *Store
 	}{
 		{DefaultStore()},
+		{&Store{Type: "badger", Path: "/path/to/store"}},
+		{&Store{Type: "gcs", Options: map[string]interface{}{"bucket": "qri-datasets"}}},
 	}
 	for i, c := range cases {
 		cpy := c.store.Copy()
@@ -31,3 +33,60 @@ func TestStoreCopy(t *testing.T) {
 		}
 	}
 }
+
+func TestStoreReadOnly(t *testing.T) {
+	cases := []struct {
+		store  *Store
+		expect bool
+	}{
+		{nil, false},
+		{DefaultStore(), false},
+		{&Store{Type: "map", Options: map[string]interface{}{"readonly": false}}, false},
+		{&Store{Type: "map", Options: map[string]interface{}{"readonly": "true"}}, false},
+		{&Store{Type: "map", Options: map[string]interface{}{"readonly": true}}, true},
+	}
+	for i, c := range cases {
+		if got := c.store.ReadOnly(); got != c.expect {
+			t.Errorf("case %d: expected %t, got %t", i, c.expect, got)
+		}
+	}
+}
+
+func TestStoreCacheSize(t *testing.T) {
+	cases := []struct {
+		store  *Store
+		expect int64
+	}{
+		{nil, 0},
+		{DefaultStore(), 0},
+		{&Store{Type: "ipfs_http", Options: map[string]interface{}{"cacheSize": 500}}, 500},
+		{&Store{Type: "ipfs_http", Options: map[string]interface{}{"cacheSize": int64(1 << 30)}}, 1 << 30},
+		{&Store{Type: "ipfs_http", Options: map[string]interface{}{"cacheSize": float64(250)}}, 250},
+		{&Store{Type: "ipfs_http", Options: map[string]interface{}{"cacheSize": "500"}}, 0},
+		{&Store{Type: "ipfs_http", Options: map[string]interface{}{"cacheSize": -1}}, 0},
+	}
+	for i, c := range cases {
+		if got := c.store.CacheSize(); got != c.expect {
+			t.Errorf("case %d: expected %d, got %d", i, c.expect, got)
+		}
+	}
+}
+
+func TestStorePinPolicy(t *testing.T) {
+	cases := []struct {
+		store  *Store
+		expect string
+	}{
+		{nil, PinPolicyAll},
+		{DefaultStore(), PinPolicyAll},
+		{&Store{Type: "ipfs", Options: map[string]interface{}{"pinPolicy": "latest"}}, PinPolicyLatest},
+		{&Store{Type: "ipfs", Options: map[string]interface{}{"pinPolicy": "all"}}, PinPolicyAll},
+		{&Store{Type: "ipfs", Options: map[string]interface{}{"pinPolicy": "sometimes"}}, PinPolicyAll},
+		{&Store{Type: "ipfs", Options: map[string]interface{}{"pinPolicy": true}}, PinPolicyAll},
+	}
+	for i, c := range cases {
+		if got := c.store.PinPolicy(); got != c.expect {
+			t.Errorf("case %d: expected %q, got %q", i, c.expect, got)
+		}
+	}
+}
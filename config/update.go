@@ -1,12 +1,23 @@
 package config
 
-import "github.com/qri-io/jsonschema"
+import (
+	"fmt"
+
+	"github.com/qri-io/iso8601"
+	"github.com/qri-io/jsonschema"
+)
 
 // Update configures a Remote Procedure Call (Update) listener
 type Update struct {
 	Type      string `json:"type"`
 	Daemonize bool   `json:"daemonize"`
 	Address   string `json:"address"`
+	// LogMaxAge is an ISO 8601 duration (eg. "P30D"). logged update runs older
+	// than LogMaxAge are pruned. empty keeps runs regardless of age
+	LogMaxAge string `json:"logmaxage,omitempty"`
+	// LogMaxRuns is the number of logged runs to keep for each update job,
+	// pruning older runs. zero keeps all runs
+	LogMaxRuns int `json:"logmaxruns,omitempty"`
 }
 
 // DefaultUpdateAddress is the local address Update serves on by default
@@ -43,18 +54,37 @@ func (cfg Update) Validate() error {
       "address": {
         "description": "address service will listen and dial on for inter-process communication",
         "type": "string"
+      },
+      "logmaxage": {
+        "description": "ISO 8601 duration logged update runs are kept for",
+        "type": "string"
+      },
+      "logmaxruns": {
+        "description": "number of logged runs kept for each update job",
+        "type": "integer",
+        "minimum": 0
       }
     }
   }`)
-	return validate(schema, &cfg)
+	if err := validate(schema, &cfg); err != nil {
+		return err
+	}
+	if cfg.LogMaxAge != "" {
+		if _, err := iso8601.ParseDuration(cfg.LogMaxAge); err != nil {
+			return fmt.Errorf("logmaxage: invalid ISO 8601 duration %q: %s", cfg.LogMaxAge, err)
+		}
+	}
+	return nil
 }
 
 // Copy makes a deep copy of the Update struct
 func (cfg *Update) Copy() *Update {
 	res := &Update{
-		Type:      cfg.Type,
-		Daemonize: cfg.Daemonize,
-		Address:   cfg.Address,
+		Type:       cfg.Type,
+		Daemonize:  cfg.Daemonize,
+		Address:    cfg.Address,
+		LogMaxAge:  cfg.LogMaxAge,
+		LogMaxRuns: cfg.LogMaxRuns,
 	}
 
 	return res
@@ -10,6 +10,24 @@ func TestUpdateValidate(t *testing.T) {
 	if err != nil {
 		t.Errorf("error validating default update: %s", err)
 	}
+
+	cfg := DefaultUpdate()
+	cfg.LogMaxAge = "P30D"
+	cfg.LogMaxRuns = 10
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("error validating update with log retention: %s", err)
+	}
+
+	cfg.LogMaxAge = "30 days"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected invalid logmaxage to error")
+	}
+
+	cfg.LogMaxAge = ""
+	cfg.LogMaxRuns = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected negative logmaxruns to error")
+	}
 }
 
 func TestUpdateCopy(t *testing.T) {
@@ -17,6 +35,7 @@ func TestUpdateCopy(t *testing.T) {
 		rpc *Update
 	}{
 		{DefaultUpdate()},
+		{&Update{Type: "fs", Daemonize: true, Address: DefaultUpdateAddress, LogMaxAge: "P1W", LogMaxRuns: 5}},
 	}
 	for i, c := range cases {
 		cpy := c.rpc.Copy()
@@ -11,20 +11,20 @@ import (
 )
 
 // GetBody is an FSI version of actions.GetBody
-func GetBody(dirPath string, format dataset.DataFormat, fcfg dataset.FormatConfig, offset, limit int, all bool) ([]byte, error) {
+func GetBody(dirPath string, format dataset.DataFormat, fcfg dataset.FormatConfig, where *base.RowFilter, offset, limit int, all bool) ([]byte, int, error) {
 	ds, mapping, _, err := ReadDir(dirPath)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	bodyFileStat, ok := mapping["body"]
 	if !ok {
-		return nil, fmt.Errorf("no body found")
+		return nil, 0, fmt.Errorf("no body found")
 	}
 
 	f, err := os.Open(bodyFileStat.Path)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	defer f.Close()
@@ -40,5 +40,5 @@ func GetBody(dirPath string, format dataset.DataFormat, fcfg dataset.FormatConfi
 	}
 	st.Assign(ds.Structure, assign)
 
-	return base.ConvertBodyFile(file, ds.Structure, st, limit, offset, all)
+	return base.ConvertBodyFile(file, ds.Structure, st, where, limit, offset, all)
 }
@@ -37,6 +37,11 @@ var (
 // or not
 const QriRefFilename = ".qri-ref"
 
+// QriVersionFilename is the name of the file that records the dataset version
+// files in a linked folder were last written from. Checkout uses the recorded
+// version as the common ancestor when merging local changes
+const QriVersionFilename = ".qri-version"
+
 // GetLinkedFilesysRef returns whether a directory is linked to a
 // dataset in your repo, and the reference to that dataset.
 func GetLinkedFilesysRef(dir string) (string, bool) {
@@ -148,6 +153,9 @@ func (fsi *FSI) Unlink(dirPath, refStr string) error {
 	if removeLinkErr := removeLinkFile(dirPath); removeLinkErr != nil {
 		log.Debugf("removing link file: %s", removeLinkErr.Error())
 	}
+	if removeVersionErr := os.Remove(filepath.Join(dirPath, QriVersionFilename)); removeVersionErr != nil && !os.IsNotExist(removeVersionErr) {
+		log.Debugf("removing version file: %s", removeVersionErr.Error())
+	}
 
 	defer func() {
 		// always attempt to remove the directory, ignoring "directory not empty" errors
@@ -183,6 +191,37 @@ func (fsi *FSI) getRepoRef(refStr string) (ref repo.DatasetRef, err error) {
 	return fsi.repo.GetRef(ref)
 }
 
+// ModifyLinkReference rewrites the dataset reference a linked directory
+// declares, for keeping the link intact when a dataset is renamed. it's an
+// error if dir isn't linked
+func ModifyLinkReference(dir, refStr string) error {
+	if _, ok := GetLinkedFilesysRef(dir); !ok {
+		return fmt.Errorf("%s: %s", ErrNoLink, dir)
+	}
+	return writeLinkFile(dir, refStr)
+}
+
+// WriteVersionFile records the path of the dataset version files in a linked
+// directory were written from
+func WriteVersionFile(dir, path string) error {
+	filepath := filepath.Join(dir, QriVersionFilename)
+	if err := ioutil.WriteFile(filepath, []byte(path), os.ModePerm); err != nil {
+		return err
+	}
+	return setFileHidden(filepath)
+}
+
+// ReadVersionFile returns the recorded path of the dataset version files in a
+// linked directory were written from, and whether a version is recorded
+func ReadVersionFile(dir string) (string, bool) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, QriVersionFilename))
+	if err != nil {
+		return "", false
+	}
+	path := strings.TrimSpace(string(data))
+	return path, path != ""
+}
+
 func writeLinkFile(dir, linkstr string) error {
 	filepath := filepath.Join(dir, QriRefFilename)
 	if err := ioutil.WriteFile(filepath, []byte(linkstr), os.ModePerm); err != nil {
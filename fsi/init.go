@@ -1,11 +1,13 @@
 package fsi
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/repo"
 )
 
@@ -16,6 +18,9 @@ type InitParams struct {
 	Format         string
 	Mkdir          string
 	SourceBodyPath string
+	// Sheet is the name of the sheet to use when the source body is a workbook
+	// with more than one sheet
+	Sheet string
 }
 
 // InitDataset creates a new dataset
@@ -60,15 +65,33 @@ func (fsi *FSI) InitDataset(p InitParams) (name string, err error) {
 
 	// Derive format from --source-body-path if provided.
 	if p.Format == "" && p.SourceBodyPath != "" {
-		ext := filepath.Ext(p.SourceBodyPath)
+		trimmed, _ := base.TrimCompressionExt(p.SourceBodyPath)
+		ext := filepath.Ext(trimmed)
 		if len(ext) > 0 {
 			p.Format = ext[1:]
 		}
 	}
 
+	// ndjson bodies are stored as json arrays, one entry per line
+	ndjson := base.IsNDJSONFormat(p.Format)
+	if ndjson {
+		p.Format = "json"
+	}
+
+	// Spreadsheet & columnar source bodies are converted to csv
+	var tabularRows [][]string
+	if p.SourceBodyPath != "" {
+		if tabularRows, err = readTabularSourceBody(p.SourceBodyPath, p.Sheet); err != nil {
+			return "", err
+		}
+		if tabularRows != nil {
+			p.Format = "csv"
+		}
+	}
+
 	// Validate dataset format
 	if p.Format != "csv" && p.Format != "json" {
-		return "", fmt.Errorf("invalid format \"%s\", only \"csv\" and \"json\" accepted", p.Format)
+		return "", fmt.Errorf("invalid format \"%s\", only \"csv\", \"json\" and \"ndjson\" accepted, or \"xlsx\" and \"parquet\" source bodies", p.Format)
 	}
 
 	// Create the link file, containing the dataset reference.
@@ -89,7 +112,16 @@ func (fsi *FSI) InitDataset(p InitParams) (name string, err error) {
 	}
 
 	var bodyBytes []byte
-	if p.SourceBodyPath == "" {
+	if tabularRows != nil {
+		// Write a structure with a schema inferred from the header row
+		var structure []byte
+		if bodyBytes, structure, err = tabularBody(tabularRows); err != nil {
+			return "", err
+		}
+		if err := ioutil.WriteFile(filepath.Join(targetPath, "structure.json"), structure, os.ModePerm); err != nil {
+			return name, err
+		}
+	} else if p.SourceBodyPath == "" {
 		// Create a skeleton body file.
 		if p.Format == "csv" {
 			bodyBytes = []byte("one,two,3\nfour,five,6")
@@ -102,9 +134,21 @@ func (fsi *FSI) InitDataset(p InitParams) (name string, err error) {
 		}
 	} else {
 		// Create body file by reading the sourcefile.
-		if bodyBytes, err = ioutil.ReadFile(p.SourceBodyPath); err != nil {
+		if bodyBytes, err = readSourceBody(p.SourceBodyPath, ndjson); err != nil {
 			return "", err
 		}
+		// Write a schema inferred from the keys of array-of-object json bodies
+		if p.Format == "json" {
+			if schema := objectArraySchema(bodyBytes); schema != nil {
+				data, err := json.MarshalIndent(schema, "", " ")
+				if err != nil {
+					return name, err
+				}
+				if err := ioutil.WriteFile(filepath.Join(targetPath, "schema.json"), data, os.ModePerm); err != nil {
+					return name, err
+				}
+			}
+		}
 	}
 	bodyFilename := filepath.Join(targetPath, fmt.Sprintf("body.%s", p.Format))
 	if err := ioutil.WriteFile(bodyFilename, bodyBytes, os.ModePerm); err != nil {
@@ -114,6 +158,26 @@ func (fsi *FSI) InitDataset(p InitParams) (name string, err error) {
 	return name, err
 }
 
+// readSourceBody reads a body file, decompressing it if the filename has a
+// compression extension. ndjson bodies are converted to a json array
+func readSourceBody(path string, ndjson bool) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	_, compression := base.TrimCompressionExt(path)
+	r, err := base.DecompressReader(f, compression)
+	if err != nil {
+		return nil, err
+	}
+	if ndjson {
+		r = base.NDJSONToJSONReader(r)
+	}
+	return ioutil.ReadAll(r)
+}
+
 func canInitDir(dir string) error {
 	if _, err := os.Stat(filepath.Join(dir, QriRefFilename)); !os.IsNotExist(err) {
 		return fmt.Errorf("working directory is already linked, .qri-ref exists")
@@ -122,6 +186,9 @@ func canInitDir(dir string) error {
 		// TODO(dlong): Instead, import the meta.json file for the new dataset
 		return fmt.Errorf("cannot initialize new dataset, meta.json exists")
 	}
+	if _, err := os.Stat(filepath.Join(dir, "structure.json")); !os.IsNotExist(err) {
+		return fmt.Errorf("cannot initialize new dataset, structure.json exists")
+	}
 	if _, err := os.Stat(filepath.Join(dir, "schema.json")); !os.IsNotExist(err) {
 		// TODO(dlong): Instead, import the schema.json file for the new dataset
 		return fmt.Errorf("cannot initialize new dataset, schema.json exists")
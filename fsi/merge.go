@@ -0,0 +1,596 @@
+package fsi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/qri-io/dataset"
+)
+
+const (
+	// MergeMarkerLocal opens a conflict, followed by the working directory's
+	// side of the conflict
+	MergeMarkerLocal = "<<<<<<< local"
+	// MergeMarkerSeparator separates the local & incoming sides of a conflict
+	MergeMarkerSeparator = "======="
+	// MergeMarkerIncoming closes a conflict, preceded by the incoming side
+	MergeMarkerIncoming = ">>>>>>> incoming"
+)
+
+// maxLineMergeCells caps the size of line-based merges. Body files whose
+// line counts multiply past this limit are merged as a single unit instead
+var maxLineMergeCells = 25000000
+
+// MergeConflictError reports files a merge left conflict markers in
+type MergeConflictError struct {
+	Files []string
+}
+
+// Error implements the error interface
+func (e MergeConflictError) Error() string {
+	return fmt.Sprintf("merge conflicts in %s. resolve the conflict markers in each file, then save", strings.Join(e.Files, ", "))
+}
+
+// MergeResult describes the outcome of merging an incoming version into a
+// working directory
+type MergeResult struct {
+	// Updated lists files changed by the merge
+	Updated []string
+	// Conflicts lists files with conflict markers that must be resolved
+	Conflicts []string
+}
+
+// mergeFiles lists component files a merge considers, body files are handled
+// separately
+var mergeFiles = []string{"dataset", "meta", "schema"}
+
+// Merge performs a three-way merge in a linked working directory. base is the
+// version the working directory files were last written from, incoming is the
+// version being checked out. Components changed only in the incoming version
+// are updated, local edits that don't overlap with incoming changes are kept.
+// meta, structure & schema files merge field-by-field, body files merge
+// line-by-line. Overlapping edits are written with conflict markers for
+// manual resolution, & reported in the returned result. base & incoming body
+// files must be open
+func Merge(dir string, base, incoming *dataset.Dataset) (*MergeResult, error) {
+	_, _, problems, err := ReadDir(dir)
+	if err != nil && err != ErrNoDatasetFiles {
+		return nil, err
+	}
+	for cmp, stat := range problems {
+		return nil, fmt.Errorf("cannot merge: %s file %s has problems, fix or restore it first", cmp, filepath.Base(stat.Path))
+	}
+
+	baseDir, err := writeMergeSide(base)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(baseDir)
+	incomingDir, err := writeMergeSide(incoming)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(incomingDir)
+
+	res := &MergeResult{}
+	for _, name := range mergeFiles {
+		if err := mergeJSONFile(res, name, dir, baseDir, incomingDir); err != nil {
+			return nil, err
+		}
+	}
+	if err := mergeBodyFile(res, dir, baseDir, incomingDir); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// writeMergeSide writes the files checkout would create for a dataset to a
+// temporary directory
+func writeMergeSide(ds *dataset.Dataset) (string, error) {
+	dir, err := ioutil.TempDir("", "qri_merge")
+	if err != nil {
+		return "", err
+	}
+	if ds != nil {
+		if err := WriteComponents(ds, dir); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// mergeSide is one of three versions of a component file
+type mergeSide struct {
+	path string
+	data []byte
+}
+
+func (s *mergeSide) exists() bool { return s != nil }
+
+// readMergeSide finds a component file in dir, checking json & yaml variants
+func readMergeSide(dir, name string) (*mergeSide, error) {
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		path := filepath.Join(dir, name+ext)
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			return &mergeSide{path: path, data: data}, nil
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// mergeJSONFile merges a component file field-by-field
+func mergeJSONFile(res *MergeResult, name, dir, baseDir, incomingDir string) error {
+	base, err := readMergeSide(baseDir, name)
+	if err != nil {
+		return err
+	}
+	local, err := readMergeSide(dir, name)
+	if err != nil {
+		return err
+	}
+	incoming, err := readMergeSide(incomingDir, name)
+	if err != nil {
+		return err
+	}
+
+	baseObj, err := decodeMergeObject(base)
+	if err != nil {
+		return err
+	}
+	localObj, err := decodeMergeObject(local)
+	if err != nil {
+		return err
+	}
+	incomingObj, err := decodeMergeObject(incoming)
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(localObj, incomingObj) || reflect.DeepEqual(baseObj, incomingObj) {
+		// nothing incoming to merge
+		return nil
+	}
+
+	path := filepath.Join(dir, name+".json")
+	if local.exists() {
+		path = local.path
+	}
+
+	if reflect.DeepEqual(baseObj, localObj) {
+		// no local changes, take the incoming file
+		if !incoming.exists() {
+			res.Updated = append(res.Updated, filepath.Base(path))
+			return os.Remove(path)
+		}
+		res.Updated = append(res.Updated, filepath.Base(path))
+		return ioutil.WriteFile(path, incoming.data, os.ModePerm)
+	}
+
+	merged, conflicts := mergeObjects(baseObj, localObj, incomingObj)
+	var data []byte
+	if len(conflicts) > 0 {
+		data = renderConflictObject(merged, conflicts)
+		res.Conflicts = append(res.Conflicts, filepath.Base(path))
+	} else if len(merged) == 0 {
+		res.Updated = append(res.Updated, filepath.Base(path))
+		return os.Remove(path)
+	} else {
+		if data, err = json.MarshalIndent(merged, "", " "); err != nil {
+			return err
+		}
+		res.Updated = append(res.Updated, filepath.Base(path))
+	}
+	return ioutil.WriteFile(path, data, os.ModePerm)
+}
+
+// decodeMergeObject parses a component file as a JSON object, treating a
+// missing file as an empty object
+func decodeMergeObject(s *mergeSide) (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	if !s.exists() {
+		return obj, nil
+	}
+	data := s.data
+	if ext := filepath.Ext(s.path); ext == ".yaml" || ext == ".yml" {
+		var err error
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("reading %s: %s", filepath.Base(s.path), err)
+		}
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("reading %s: %s", filepath.Base(s.path), err)
+	}
+	if obj == nil {
+		obj = map[string]interface{}{}
+	}
+	return obj, nil
+}
+
+// fieldConflict is a field changed differently in local & incoming versions.
+// a nil value means the field was removed
+type fieldConflict struct {
+	local, incoming interface{}
+}
+
+// mergeObjects three-way merges the top-level fields of JSON objects,
+// returning the merged object & conflicting fields
+func mergeObjects(base, local, incoming map[string]interface{}) (map[string]interface{}, map[string]fieldConflict) {
+	merged := map[string]interface{}{}
+	conflicts := map[string]fieldConflict{}
+
+	keys := map[string]bool{}
+	for _, obj := range []map[string]interface{}{base, local, incoming} {
+		for key := range obj {
+			keys[key] = true
+		}
+	}
+
+	for key := range keys {
+		b, bOk := base[key]
+		l, lOk := local[key]
+		in, inOk := incoming[key]
+
+		switch {
+		case lOk == inOk && reflect.DeepEqual(l, in):
+			if lOk {
+				merged[key] = l
+			}
+		case bOk == lOk && reflect.DeepEqual(b, l):
+			if inOk {
+				merged[key] = in
+			}
+		case bOk == inOk && reflect.DeepEqual(b, in):
+			if lOk {
+				merged[key] = l
+			}
+		default:
+			conflicts[key] = fieldConflict{local: l, incoming: in}
+		}
+	}
+	return merged, conflicts
+}
+
+// renderConflictObject writes a JSON object with conflict markers around
+// conflicting fields
+func renderConflictObject(merged map[string]interface{}, conflicts map[string]fieldConflict) []byte {
+	keys := make([]string, 0, len(merged)+len(conflicts))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	for key := range conflicts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	field := func(buf *bytes.Buffer, key string, value interface{}, last bool) {
+		if value == nil {
+			return
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.MarshalIndent(value, " ", " ")
+		buf.WriteString(" ")
+		buf.Write(k)
+		buf.WriteString(": ")
+		buf.Write(v)
+		if !last {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("{\n")
+	for i, key := range keys {
+		last := i == len(keys)-1
+		if c, ok := conflicts[key]; ok {
+			buf.WriteString(MergeMarkerLocal + "\n")
+			field(buf, key, c.local, last)
+			buf.WriteString(MergeMarkerSeparator + "\n")
+			field(buf, key, c.incoming, last)
+			buf.WriteString(MergeMarkerIncoming + "\n")
+			continue
+		}
+		field(buf, key, merged[key], last)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// readBodySide finds the body file in dir
+func readBodySide(dir string) (*mergeSide, error) {
+	for _, name := range []string{"body.csv", "body.json"} {
+		path := filepath.Join(dir, name)
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			return &mergeSide{path: path, data: data}, nil
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// mergeBodyFile merges body files line-by-line
+func mergeBodyFile(res *MergeResult, dir, baseDir, incomingDir string) error {
+	base, err := readBodySide(baseDir)
+	if err != nil {
+		return err
+	}
+	local, err := readBodySide(dir)
+	if err != nil {
+		return err
+	}
+	incoming, err := readBodySide(incomingDir)
+	if err != nil {
+		return err
+	}
+
+	if sameBodySide(local, incoming) || sameBodySide(base, incoming) {
+		return nil
+	}
+
+	if sameBodySide(base, local) {
+		// no local changes, take the incoming body
+		if local.exists() {
+			if err := os.Remove(local.path); err != nil {
+				return err
+			}
+		}
+		if !incoming.exists() {
+			res.Updated = append(res.Updated, filepath.Base(local.path))
+			return nil
+		}
+		path := filepath.Join(dir, filepath.Base(incoming.path))
+		res.Updated = append(res.Updated, filepath.Base(path))
+		return ioutil.WriteFile(path, incoming.data, os.ModePerm)
+	}
+
+	// both sides changed the body
+	var path string
+	if local.exists() {
+		path = local.path
+	} else {
+		path = filepath.Join(dir, filepath.Base(incoming.path))
+	}
+
+	var merged []byte
+	conflict := false
+	if local.exists() && incoming.exists() && filepath.Base(local.path) == filepath.Base(incoming.path) {
+		baseData := []byte{}
+		if base.exists() && filepath.Base(base.path) == filepath.Base(local.path) {
+			baseData = base.data
+		}
+		merged, conflict = MergeLines(baseData, local.data, incoming.data)
+	} else {
+		// body removed on one side, or formats differ. conflict on the whole file
+		merged = wholeConflict(local, incoming)
+		conflict = true
+	}
+
+	if conflict {
+		res.Conflicts = append(res.Conflicts, filepath.Base(path))
+	} else {
+		res.Updated = append(res.Updated, filepath.Base(path))
+	}
+	return ioutil.WriteFile(path, merged, os.ModePerm)
+}
+
+func sameBodySide(a, b *mergeSide) bool {
+	if !a.exists() || !b.exists() {
+		return a.exists() == b.exists()
+	}
+	return filepath.Base(a.path) == filepath.Base(b.path) && bytes.Equal(a.data, b.data)
+}
+
+func wholeConflict(local, incoming *mergeSide) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(MergeMarkerLocal + "\n")
+	if local.exists() {
+		buf.Write(ensureTrailingNewline(local.data))
+	}
+	buf.WriteString(MergeMarkerSeparator + "\n")
+	if incoming.exists() {
+		buf.Write(ensureTrailingNewline(incoming.data))
+	}
+	buf.WriteString(MergeMarkerIncoming + "\n")
+	return buf.Bytes()
+}
+
+func ensureTrailingNewline(data []byte) []byte {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		return append(data, '\n')
+	}
+	return data
+}
+
+// MergeLines performs a three-way, line-based merge of text, returning the
+// merged text & whether any conflicts were written with conflict markers
+func MergeLines(base, local, incoming []byte) ([]byte, bool) {
+	b, l, in := splitLines(base), splitLines(local), splitLines(incoming)
+	if len(b)*len(l) > maxLineMergeCells || len(b)*len(in) > maxLineMergeCells {
+		return wholeConflict(&mergeSide{data: local}, &mergeSide{data: incoming}), true
+	}
+
+	toLocal := lcsMatches(b, l)
+	toIncoming := lcsMatches(b, in)
+
+	buf := &bytes.Buffer{}
+	conflict := false
+	writeLines := func(lines []string) {
+		for _, line := range lines {
+			buf.WriteString(line)
+		}
+	}
+
+	var i, j, k int
+	for {
+		// find the next base line that's unchanged on both sides
+		next := -1
+		for n := i; n < len(b); n++ {
+			if toLocal[n] >= j && toIncoming[n] >= k {
+				next = n
+				break
+			}
+		}
+
+		var bEnd, lEnd, inEnd int
+		if next < 0 {
+			bEnd, lEnd, inEnd = len(b), len(l), len(in)
+		} else {
+			bEnd, lEnd, inEnd = next, toLocal[next], toIncoming[next]
+		}
+
+		bChunk, lChunk, inChunk := b[i:bEnd], l[j:lEnd], in[k:inEnd]
+		switch {
+		case linesEqual(lChunk, inChunk):
+			writeLines(lChunk)
+		case linesEqual(bChunk, lChunk):
+			writeLines(inChunk)
+		case linesEqual(bChunk, inChunk):
+			writeLines(lChunk)
+		default:
+			conflict = true
+			buf.WriteString(MergeMarkerLocal + "\n")
+			writeLines(terminateLines(lChunk))
+			buf.WriteString(MergeMarkerSeparator + "\n")
+			writeLines(terminateLines(inChunk))
+			buf.WriteString(MergeMarkerIncoming + "\n")
+		}
+
+		if next < 0 {
+			break
+		}
+		buf.WriteString(b[next])
+		i, j, k = next+1, toLocal[next]+1, toIncoming[next]+1
+	}
+	return buf.Bytes(), conflict
+}
+
+// splitLines splits text into lines, keeping line endings
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// terminateLines ensures the last line of a conflict side ends in a newline
+// so conflict markers start on their own line
+func terminateLines(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	cp := append([]string{}, lines...)
+	cp[len(cp)-1] += "\n"
+	return cp
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lcsMatches maps each line in a to the index of the line it matches in b
+// under a longest common subsequence alignment, or -1 for unmatched lines.
+// lcsMatches uses Hirschberg's algorithm, holding two rows of LCS lengths at a
+// time instead of the full table
+func lcsMatches(a, b []string) []int {
+	matches := make([]int, len(a))
+	for i := range matches {
+		matches[i] = -1
+	}
+	alignLCS(a, b, 0, 0, matches)
+	return matches
+}
+
+// alignLCS sets matches for lines of a against lines of b, where a & b start
+// at line ai & bj of the full inputs. a is split in half, and b is split where
+// the halves' common subsequences meet, aligning each half in turn
+func alignLCS(a, b []string, ai, bj int, matches []int) {
+	// a common prefix & suffix are always part of a longest alignment
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		matches[ai] = bj
+		a, b = a[1:], b[1:]
+		ai++
+		bj++
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		matches[ai+len(a)-1] = bj + len(b) - 1
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return
+	}
+	if len(a) == 1 {
+		for j, line := range b {
+			if line == a[0] {
+				matches[ai] = bj + j
+				return
+			}
+		}
+		return
+	}
+
+	mid := len(a) / 2
+	head := lcsLengths(a[:mid], b, false)
+	tail := lcsLengths(a[mid:], b, true)
+	split, longest := 0, -1
+	for j := 0; j <= len(b); j++ {
+		if l := head[j] + tail[len(b)-j]; l > longest {
+			split, longest = j, l
+		}
+	}
+	alignLCS(a[:mid], b[:split], ai, bj, matches)
+	alignLCS(a[mid:], b[split:], ai+mid, bj+split, matches)
+}
+
+// lcsLengths gives the length of the longest common subsequence of a & each
+// prefix of b, indexed by prefix length. when reverse is true both inputs are
+// read back to front, giving lengths for each suffix of b instead
+func lcsLengths(a, b []string, reverse bool) []int {
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		x := a[i]
+		if reverse {
+			x = a[len(a)-1-i]
+		}
+		for j := 1; j <= len(b); j++ {
+			y := b[j-1]
+			if reverse {
+				y = b[len(b)-j]
+			}
+			if x == y {
+				cur[j] = prev[j-1] + 1
+			} else if prev[j] >= cur[j-1] {
+				cur[j] = prev[j]
+			} else {
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
@@ -0,0 +1,187 @@
+package fsi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestMergeLines(t *testing.T) {
+	cases := []struct {
+		description                   string
+		base, local, incoming, expect string
+		conflict                      bool
+	}{
+		{"no changes",
+			"a\nb\nc\n", "a\nb\nc\n", "a\nb\nc\n", "a\nb\nc\n", false},
+		{"local change only",
+			"a\nb\nc\n", "a\nB\nc\n", "a\nb\nc\n", "a\nB\nc\n", false},
+		{"incoming change only",
+			"a\nb\nc\n", "a\nb\nc\n", "a\nb\nC\n", "a\nb\nC\n", false},
+		{"non-overlapping changes",
+			"a\nb\nc\nd\n", "A\nb\nc\nd\n", "a\nb\nc\nD\n", "A\nb\nc\nD\n", false},
+		{"same change on both sides",
+			"a\nb\nc\n", "a\nB\nc\n", "a\nB\nc\n", "a\nB\nc\n", false},
+		{"local append, incoming insert",
+			"a\nb\n", "a\nb\nc\n", "z\na\nb\n", "z\na\nb\nc\n", false},
+		{"conflicting change",
+			"a\nb\nc\n", "a\nlocal\nc\n", "a\nincoming\nc\n",
+			"a\n<<<<<<< local\nlocal\n=======\nincoming\n>>>>>>> incoming\nc\n", true},
+		{"conflicting change without trailing newline",
+			"a\nb", "a\nlocal", "a\nincoming",
+			"a\n<<<<<<< local\nlocal\n=======\nincoming\n>>>>>>> incoming\n", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, conflict := MergeLines([]byte(c.base), []byte(c.local), []byte(c.incoming))
+			if conflict != c.conflict {
+				t.Errorf("conflict mismatch. expected: %t, got: %t", c.conflict, conflict)
+			}
+			if string(got) != c.expect {
+				t.Errorf("result mismatch.\nexpected:\n%s\ngot:\n%s", c.expect, got)
+			}
+		})
+	}
+}
+
+func TestLCSMatches(t *testing.T) {
+	cases := []struct {
+		a, b   string
+		length int
+	}{
+		{"", "a b", 0},
+		{"a b c", "a b c", 3},
+		{"a b c d", "b d", 2},
+		{"x a y b z c", "a q b r c", 3},
+		{"a b c b d a b", "b d c a b a", 4},
+		{"a a a", "a a", 2},
+	}
+	for i, c := range cases {
+		a, b := strings.Fields(c.a), strings.Fields(c.b)
+		matches := lcsMatches(a, b)
+		length, last := 0, -1
+		for ai, bj := range matches {
+			if bj < 0 {
+				continue
+			}
+			if bj <= last || a[ai] != b[bj] {
+				t.Errorf("case %d: line %d matched line %d out of order or to a different line", i, ai, bj)
+			}
+			last = bj
+			length++
+		}
+		if length != c.length {
+			t.Errorf("case %d: expected %d matching lines, got %d", i, c.length, length)
+		}
+	}
+}
+
+func TestMergeObjects(t *testing.T) {
+	base := map[string]interface{}{"title": "base", "keywords": []interface{}{"a"}, "license": "cc"}
+	local := map[string]interface{}{"title": "local", "keywords": []interface{}{"a"}, "license": "cc"}
+	incoming := map[string]interface{}{"title": "base", "keywords": []interface{}{"a", "b"}, "description": "new"}
+
+	merged, conflicts := mergeObjects(base, local, incoming)
+	if len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got: %v", conflicts)
+	}
+	if merged["title"] != "local" {
+		t.Errorf("expected local title to be kept, got: %v", merged["title"])
+	}
+	if kw, ok := merged["keywords"].([]interface{}); !ok || len(kw) != 2 {
+		t.Errorf("expected incoming keywords, got: %v", merged["keywords"])
+	}
+	if merged["description"] != "new" {
+		t.Errorf("expected incoming description, got: %v", merged["description"])
+	}
+	if _, ok := merged["license"]; ok {
+		t.Errorf("expected license removed by incoming version to be removed")
+	}
+
+	local["license"] = "mit"
+	_, conflicts = mergeObjects(base, local, incoming)
+	if _, ok := conflicts["license"]; !ok || len(conflicts) != 1 {
+		t.Errorf("expected license conflict, got: %v", conflicts)
+	}
+}
+
+func mergeTestDataset(title, body string) *dataset.Dataset {
+	ds := &dataset.Dataset{
+		Meta: &dataset.Meta{Title: title},
+		Structure: &dataset.Structure{
+			Format: "csv",
+			Schema: dataset.BaseSchemaArray,
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+	return ds
+}
+
+func TestMerge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qri_test_fsi_merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := WriteComponents(mergeTestDataset("base", "a,1\nb,2\nc,3\n"), dir); err != nil {
+		t.Fatal(err)
+	}
+	// local change to the last body row
+	if err := ioutil.WriteFile(filepath.Join(dir, "body.csv"), []byte("a,1\nb,2\nc,30\n"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	base := mergeTestDataset("base", "a,1\nb,2\nc,3\n")
+	incoming := mergeTestDataset("incoming", "a,10\nb,2\nc,3\n")
+	res, err := Merge(dir, base, incoming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 0 {
+		t.Errorf("expected no conflicts, got: %v", res.Conflicts)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(dir, "body.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "a,10\nb,2\nc,30\n"; string(body) != expect {
+		t.Errorf("body mismatch. expected:\n%s\ngot:\n%s", expect, body)
+	}
+	meta, err := ioutil.ReadFile(filepath.Join(dir, "meta.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(meta), `"incoming"`) {
+		t.Errorf("expected incoming meta title, got:\n%s", meta)
+	}
+
+	// conflicting edit to the same row
+	if err := ioutil.WriteFile(filepath.Join(dir, "body.csv"), []byte("a,100\nb,2\nc,30\n"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	base = mergeTestDataset("incoming", "a,10\nb,2\nc,3\n")
+	incoming = mergeTestDataset("incoming", "a,11\nb,2\nc,3\n")
+	res, err = Merge(dir, base, incoming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Conflicts) != 1 || res.Conflicts[0] != "body.csv" {
+		t.Fatalf("expected body.csv conflict, got: %v", res.Conflicts)
+	}
+	body, err = ioutil.ReadFile(filepath.Join(dir, "body.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "<<<<<<< local\na,100\n=======\na,11\n>>>>>>> incoming\nb,2\nc,30\n"
+	if string(body) != expect {
+		t.Errorf("body mismatch. expected:\n%s\ngot:\n%s", expect, body)
+	}
+}
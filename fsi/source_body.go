@@ -0,0 +1,302 @@
+package fsi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// readTabularSourceBody reads rows from source body formats that can't be used
+// as a body directly, like spreadsheets & columnar files. The first row holds
+// column names. returns nil rows if the source isn't a tabular format
+func readTabularSourceBody(path, sheet string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		return readXLSXRows(path, sheet)
+	case ".parquet":
+		return readParquetRows(path)
+	}
+	return nil, nil
+}
+
+// readXLSXRows reads all rows from a workbook sheet. workbooks with more than
+// one sheet require a sheet name
+func readXLSXRows(path, sheet string) ([][]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening xlsx file: %s", err)
+	}
+
+	if sheet == "" {
+		sheets := f.GetSheetMap()
+		if len(sheets) != 1 {
+			names := make([]string, 0, len(sheets))
+			for _, name := range sheets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("workbook has %d sheets, specify one of: %s", len(sheets), strings.Join(names, ", "))
+		}
+		for _, name := range sheets {
+			sheet = name
+		}
+	} else if f.GetSheetIndex(sheet) == 0 {
+		return nil, fmt.Errorf("sheet %q not found in workbook", sheet)
+	}
+
+	rows := f.GetRows(sheet)
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("sheet %q is empty", sheet)
+	}
+
+	// rows end at their last non-empty cell, pad them to the width of the
+	// header so every row has the same number of columns
+	width := len(rows[0])
+	for i, row := range rows {
+		for len(row) > width && row[len(row)-1] == "" {
+			row = row[:len(row)-1]
+		}
+		if len(row) > width {
+			return nil, fmt.Errorf("row %d has %d cells, header row only has %d", i+1, len(row), width)
+		}
+		for len(row) < width {
+			row = append(row, "")
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// readParquetRows reads all rows of a parquet file. only flat columns are
+// supported
+func readParquetRows(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	pf := &parquetFile{File: f}
+	defer pf.Close()
+
+	pr, err := reader.NewParquetReader(pf, nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("reading parquet file: %s", err)
+	}
+	defer pr.ReadStop()
+
+	num := pr.GetNumRows()
+	cols := pr.SchemaHandler.ValueColumns
+	rows := make([][]string, num+1)
+	for i := range rows {
+		rows[i] = make([]string, len(cols))
+	}
+
+	for i, col := range cols {
+		exPath := common.StrToPath(pr.SchemaHandler.InPathToExPath[col])
+		rows[0][i] = exPath[len(exPath)-1]
+
+		vals, _, _, err := pr.ReadColumnByIndex(int64(i), num)
+		if err != nil {
+			return nil, fmt.Errorf("reading parquet column %q: %s", rows[0][i], err)
+		}
+		if int64(len(vals)) != num {
+			return nil, fmt.Errorf("parquet column %q is nested, only flat columns are supported", rows[0][i])
+		}
+		for j, v := range vals {
+			if v != nil {
+				rows[j+1][i] = fmt.Sprint(v)
+			}
+		}
+	}
+	return rows, nil
+}
+
+// parquetFile adapts an *os.File to the source.ParquetFile interface
+type parquetFile struct {
+	*os.File
+}
+
+// Open implements the source.ParquetFile interface
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = f.Name()
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetFile{File: file}, nil
+}
+
+// Create implements the source.ParquetFile interface. parquet files are only
+// read
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, fmt.Errorf("parquet files are read-only")
+}
+
+// tabularBody encodes rows as a csv body, and a structure that describes it.
+// column titles come from the first row, column types are inferred from the
+// values of the rest
+func tabularBody(rows [][]string) (body, structure []byte, err error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err = w.WriteAll(rows); err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]interface{}, len(rows[0]))
+	for i, title := range rows[0] {
+		items[i] = map[string]interface{}{"title": title, "type": columnType(rows[1:], i)}
+	}
+	structure, err = json.MarshalIndent(map[string]interface{}{
+		"format":       "csv",
+		"formatConfig": map[string]interface{}{"headerRow": true},
+		"schema": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": items,
+			},
+		},
+	}, "", " ")
+	return buf.Bytes(), structure, err
+}
+
+// columnType infers the JSON schema type of a column of string cells. columns
+// where every non-empty cell is an integer, number or boolean get that type,
+// integers widening to numbers. anything else is a string
+func columnType(rows [][]string, i int) string {
+	t := ""
+	for _, row := range rows {
+		if i >= len(row) || row[i] == "" {
+			continue
+		}
+		cell := row[i]
+		switch {
+		case isInteger(cell):
+			if t == "" {
+				t = "integer"
+			} else if t != "integer" && t != "number" {
+				return "string"
+			}
+		case isNumber(cell):
+			if t == "" || t == "integer" {
+				t = "number"
+			} else if t != "number" {
+				return "string"
+			}
+		case strings.EqualFold(cell, "true") || strings.EqualFold(cell, "false"):
+			if t == "" {
+				t = "boolean"
+			} else if t != "boolean" {
+				return "string"
+			}
+		default:
+			return "string"
+		}
+	}
+	if t == "" {
+		return "string"
+	}
+	return t
+}
+
+func isInteger(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+func isNumber(s string) bool {
+	f, err := strconv.ParseFloat(s, 64)
+	return err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// objectArraySchema infers a schema for a json body that's an array of
+// objects, with a property for every key that appears in any object. It
+// returns nil for bodies that aren't a non-empty array of objects.
+// Properties have a type when all non-null values share one, integers
+// widening to numbers, & allow null when any value is null
+func objectArraySchema(body []byte) map[string]interface{} {
+	var rows []interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// keep numbers as json.Number to tell integers from floats
+	dec.UseNumber()
+	if err := dec.Decode(&rows); err != nil || len(rows) == 0 {
+		// not an array, leave validation to the body reader
+		return nil
+	}
+
+	types := map[string]map[string]bool{}
+	for _, row := range rows {
+		obj, ok := row.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for key, v := range obj {
+			if types[key] == nil {
+				types[key] = map[string]bool{}
+			}
+			types[key][jsonSchemaType(v)] = true
+		}
+	}
+
+	props := map[string]interface{}{}
+	for key, seen := range types {
+		prop := map[string]interface{}{}
+		nullable := seen["null"]
+		delete(seen, "null")
+		if len(seen) == 2 && seen["integer"] && seen["number"] {
+			delete(seen, "integer")
+		}
+		if len(seen) == 1 {
+			for t := range seen {
+				if nullable {
+					prop["type"] = []interface{}{t, "null"}
+				} else {
+					prop["type"] = t
+				}
+			}
+		}
+		props[key] = prop
+	}
+
+	return map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":       "object",
+			"properties": props,
+		},
+	}
+}
+
+// jsonSchemaType gives the JSON schema type of a decoded value
+func jsonSchemaType(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,209 @@
+package fsi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+)
+
+func TestInitDatasetXLSXSourceBody(t *testing.T) {
+	paths := NewTmpPaths()
+	defer paths.Close()
+
+	wb := excelize.NewFile()
+	wb.SetCellValue("Sheet1", "A1", "city")
+	wb.SetCellValue("Sheet1", "B1", "pop")
+	wb.SetCellValue("Sheet1", "A2", "toronto")
+	wb.SetCellValue("Sheet1", "B2", 2731571)
+	// ragged rows are padded to the header width
+	wb.SetCellValue("Sheet1", "A3", "montreal")
+	sourcePath := filepath.Join(paths.homeDir, "cities.xlsx")
+	if err := wb.SaveAs(sourcePath); err != nil {
+		t.Fatal(err)
+	}
+
+	fsi := NewFSI(paths.testRepo)
+	p := InitParams{
+		Dir:            paths.firstDir,
+		Name:           "cities_xlsx",
+		SourceBodyPath: sourcePath,
+	}
+	if _, err := fsi.InitDataset(p); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(paths.firstDir, "body.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "city,pop\ntoronto,2731571\nmontreal,\n"
+	if string(body) != expect {
+		t.Errorf("body mismatch. expected: %q, got: %q", expect, string(body))
+	}
+
+	ds, _, _, err := ReadDir(paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Structure == nil || ds.Structure.Format != "csv" {
+		t.Fatalf("expected csv structure. got: %v", ds.Structure)
+	}
+	items := ds.Structure.Schema["items"].(map[string]interface{})["items"].([]interface{})
+	if len(items) != 2 || items[0].(map[string]interface{})["title"] != "city" {
+		t.Errorf("expected schema to be inferred from headers. got: %v", items)
+	}
+	if typ := items[1].(map[string]interface{})["type"]; typ != "integer" {
+		t.Errorf("expected pop column to be inferred as integer. got: %v", typ)
+	}
+
+	// workbooks with many sheets require a sheet name
+	wb.NewSheet("Sheet2")
+	wb.SetCellValue("Sheet2", "A1", "country")
+	if err := wb.SaveAs(sourcePath); err != nil {
+		t.Fatal(err)
+	}
+	p.Dir = paths.secondDir
+	p.Name = "countries_xlsx"
+	expectErr := "workbook has 2 sheets, specify one of: Sheet1, Sheet2"
+	if _, err := fsi.InitDataset(p); err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+
+	p.Sheet = "Sheet2"
+	if _, err := fsi.InitDataset(p); err != nil {
+		t.Fatal(err)
+	}
+	body, err = ioutil.ReadFile(filepath.Join(paths.secondDir, "body.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(body), "country") {
+		t.Errorf("expected body from Sheet2. got: %q", string(body))
+	}
+}
+
+func TestInitDatasetJSONObjectArraySchema(t *testing.T) {
+	paths := NewTmpPaths()
+	defer paths.Close()
+
+	sourcePath := filepath.Join(paths.homeDir, "cities.json")
+	data := `[{"city":"toronto","pop":2731571},{"city":"new york","pop":null,"in_usa":true}]`
+	if err := ioutil.WriteFile(sourcePath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fsi := NewFSI(paths.testRepo)
+	p := InitParams{
+		Dir:            paths.firstDir,
+		Name:           "cities_json",
+		SourceBodyPath: sourcePath,
+	}
+	if _, err := fsi.InitDataset(p); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, _, _, err := ReadDir(paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Structure == nil || ds.Structure.Schema == nil {
+		t.Fatalf("expected a schema to be inferred. got: %v", ds.Structure)
+	}
+	items, _ := ds.Structure.Schema["items"].(map[string]interface{})
+	props, _ := items["properties"].(map[string]interface{})
+	expect := map[string]interface{}{
+		"city":   map[string]interface{}{"type": "string"},
+		"pop":    map[string]interface{}{"type": []interface{}{"integer", "null"}},
+		"in_usa": map[string]interface{}{"type": "boolean"},
+	}
+	if !reflect.DeepEqual(expect, props) {
+		t.Errorf("schema properties mismatch.\nwant: %v\ngot:  %v", expect, props)
+	}
+
+	// bodies that aren't arrays of objects don't get a schema
+	sourcePath = filepath.Join(paths.homeDir, "rows.json")
+	if err := ioutil.WriteFile(sourcePath, []byte(`[[1,2],[3,4]]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p = InitParams{
+		Dir:            paths.secondDir,
+		Name:           "rows_json",
+		SourceBodyPath: sourcePath,
+	}
+	if _, err := fsi.InitDataset(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.secondDir, "schema.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no schema.json for an array of arrays body")
+	}
+}
+
+func TestInitDatasetNDJSONSourceBody(t *testing.T) {
+	paths := NewTmpPaths()
+	defer paths.Close()
+
+	sourcePath := filepath.Join(paths.homeDir, "events.jsonl")
+	data := "{\"event\":\"start\",\"at\":1}\n\n{\"event\":\"stop\",\"at\":2}\n"
+	if err := ioutil.WriteFile(sourcePath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fsi := NewFSI(paths.testRepo)
+	p := InitParams{
+		Dir:            paths.firstDir,
+		Name:           "events",
+		SourceBodyPath: sourcePath,
+	}
+	if _, err := fsi.InitDataset(p); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(paths.firstDir, "body.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := `[{"event":"start","at":1},{"event":"stop","at":2}]`; string(body) != expect {
+		t.Errorf("body mismatch.\nwant: %s\ngot:  %s", expect, string(body))
+	}
+
+	ds, _, _, err := ReadDir(paths.firstDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Structure == nil || ds.Structure.Schema == nil {
+		t.Fatalf("expected a per-record schema to be inferred. got: %v", ds.Structure)
+	}
+	items, _ := ds.Structure.Schema["items"].(map[string]interface{})
+	props, _ := items["properties"].(map[string]interface{})
+	if _, ok := props["event"]; !ok {
+		t.Errorf("expected record schema to describe the event field. got: %v", props)
+	}
+}
+
+func TestColumnType(t *testing.T) {
+	cases := []struct {
+		cells  []string
+		expect string
+	}{
+		{[]string{"1", "", "2"}, "integer"},
+		{[]string{"1", "2.5"}, "number"},
+		{[]string{"TRUE", "false"}, "boolean"},
+		{[]string{"1", "true"}, "string"},
+		{[]string{"NaN"}, "string"},
+		{[]string{""}, "string"},
+	}
+	for i, c := range cases {
+		rows := make([][]string, len(c.cells))
+		for j, cell := range c.cells {
+			rows[j] = []string{cell}
+		}
+		if got := columnType(rows, 0); got != c.expect {
+			t.Errorf("case %d type mismatch. expected: %s, got: %s", i, c.expect, got)
+		}
+	}
+}
@@ -14,18 +14,27 @@ replace (
 )
 
 require (
+	cloud.google.com/go/storage v1.0.0
+	github.com/360EntSecGroup-Skylar/excelize v1.4.1
+	github.com/apache/arrow/go/arrow v0.0.0-20191024131854-af6fa24be0db
 	github.com/beme/abide v0.0.0-20181227202223-4c487ef9d895
 	github.com/boltdb/bolt v1.3.1 // indirect
+	github.com/dgraph-io/badger v2.0.0-rc.2+incompatible
 	github.com/dustin/go-humanize v1.0.0
 	github.com/fatih/color v1.7.0
 	github.com/ghodss/yaml v1.0.0
 	github.com/gofrs/flock v0.7.1 // indirect
 	github.com/google/flatbuffers v1.11.0
-	github.com/google/go-cmp v0.3.0
+	github.com/google/go-cmp v0.4.0
+	github.com/gorilla/websocket v1.4.0
+	github.com/hashicorp/golang-lru v0.5.1
 	github.com/ipfs/go-cid v0.0.2
 	github.com/ipfs/go-ipfs v0.4.21
+	github.com/ipfs/go-ipfs-chunker v0.0.1
 	github.com/ipfs/go-ipld-format v0.0.2
 	github.com/ipfs/go-log v0.0.1
+	github.com/ipfs/go-merkledag v0.0.3
+	github.com/ipfs/go-unixfs v0.0.6
 	github.com/ipfs/interface-go-ipfs-core v0.0.8
 	github.com/libp2p/go-libp2p v0.0.28
 	github.com/libp2p/go-libp2p-circuit v0.0.8
@@ -58,6 +67,8 @@ require (
 	github.com/sirupsen/logrus v1.2.0
 	github.com/spf13/cobra v0.0.5
 	github.com/theckman/go-flock v0.7.1
+	github.com/whyrusleeping/go-logging v0.0.0-20170515211332-0457bb6b88fc
+	github.com/xitongsys/parquet-go v1.5.1
 	go.starlark.net v0.0.0-20190528202925-30ae18b8564f
 	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
 	golang.org/x/sys v0.0.0-20190626221950-04f50cda93cb
@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/repo"
+)
+
+// CiteParams defines parameters for adding or removing a dataset citation
+type CiteParams struct {
+	// dataset reference to cite sources on
+	Ref string
+	// Title of the cited source, required when adding
+	Title string
+	// URL of the cited source. citations are identified by url
+	URL string
+	// Accessed is the date the source was accessed as YYYY-MM-DD. defaults to
+	// today when adding a citation
+	Accessed string
+	// Remove the citation with URL instead of adding one
+	Remove bool
+}
+
+// Cite adds or removes a citation in a dataset's meta component, committing
+// the change as a new version
+func (r *DatasetRequests) Cite(p *CiteParams, res *repo.DatasetRef) (err error) {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.Cite", p, res)
+	}
+	ctx := context.TODO()
+
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return err
+	}
+	if err = repo.CanonicalizeDatasetRef(r.node.Repo, &ref); err != nil {
+		return err
+	}
+	if ref.FSIPath != "" {
+		return fmt.Errorf("%s is linked to a working directory, edit citations in meta.json instead", ref.AliasString())
+	}
+	if err = base.ReadDataset(ctx, r.node.Repo, &ref); err != nil {
+		return err
+	}
+
+	md := &dataset.Meta{}
+	md.Assign(ref.Dataset.Meta)
+	md.DropDerivedValues()
+
+	var title string
+	if p.Remove {
+		if err = base.RemoveCitation(md, p.URL); err != nil {
+			return err
+		}
+		title = fmt.Sprintf("remove citation: %s", p.URL)
+	} else {
+		accessed := time.Now()
+		if p.Accessed != "" {
+			if accessed, err = time.Parse(base.CitationDateFormat, p.Accessed); err != nil {
+				return fmt.Errorf("invalid accessed date %q: expected a date like 2019-01-31", p.Accessed)
+			}
+		}
+		c := &dataset.Citation{Name: p.Title, URL: p.URL}
+		if err = base.AddCitation(md, c, accessed); err != nil {
+			return err
+		}
+		title = fmt.Sprintf("add citation: %s", p.Title)
+	}
+
+	sp := &SaveParams{
+		Ref:   ref.AliasString(),
+		Title: title,
+		Dataset: &dataset.Dataset{
+			Peername: ref.Peername,
+			Name:     ref.Name,
+			Meta:     md,
+		},
+	}
+	return r.Save(sp, res)
+}
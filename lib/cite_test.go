@@ -0,0 +1,76 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/p2p"
+	"github.com/qri-io/qri/repo"
+	testrepo "github.com/qri-io/qri/repo/test"
+)
+
+func TestDatasetRequestsCite(t *testing.T) {
+	ctx := context.Background()
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewDatasetRequests(node, nil)
+
+	bad := []struct {
+		description string
+		params      CiteParams
+		err         string
+	}{
+		{"missing title", CiteParams{Ref: "me/movies", URL: "https://example.com"}, "citation title is required"},
+		{"relative url", CiteParams{Ref: "me/movies", Title: "ex", URL: "example.com"}, `invalid citation url "example.com": must be an absolute http or https url`},
+		{"bad date", CiteParams{Ref: "me/movies", Title: "ex", URL: "https://example.com", Accessed: "last week"}, `invalid accessed date "last week": expected a date like 2019-01-31`},
+		{"remove missing", CiteParams{Ref: "me/movies", URL: "https://example.com", Remove: true}, `no citation with url "https://example.com"`},
+	}
+	for _, c := range bad {
+		res := &repo.DatasetRef{}
+		if err := req.Cite(&c.params, res); err == nil || err.Error() != c.err {
+			t.Errorf("case %q error mismatch. want: %q, got: %v", c.description, c.err, err)
+		}
+	}
+
+	p := &CiteParams{Ref: "me/movies", Title: "IMDB", URL: "https://www.imdb.com", Accessed: "2019-10-01"}
+	res := &repo.DatasetRef{}
+	if err := req.Cite(p, res); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.ReadDataset(ctx, mr, res); err != nil {
+		t.Fatal(err)
+	}
+	md := res.Dataset.Meta
+	if md == nil || len(md.Citations) != 1 || md.Citations[0].Name != "IMDB" || md.Citations[0].URL != "https://www.imdb.com" {
+		t.Fatalf("expected saved meta to include citation. got: %v", md)
+	}
+	if got := base.CitationsAccessed(md)["https://www.imdb.com"]; got != "2019-10-01" {
+		t.Errorf("accessed date mismatch. want: %q, got: %v", "2019-10-01", got)
+	}
+	if res.Dataset.Commit.Title != "add citation: IMDB" {
+		t.Errorf("commit title mismatch. got: %q", res.Dataset.Commit.Title)
+	}
+
+	p = &CiteParams{Ref: "me/movies", URL: "https://www.imdb.com", Remove: true}
+	res = &repo.DatasetRef{}
+	if err := req.Cite(p, res); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.ReadDataset(ctx, mr, res); err != nil {
+		t.Fatal(err)
+	}
+	if md := res.Dataset.Meta; md != nil && len(md.Citations) != 0 {
+		t.Errorf("expected citation to be removed. got: %v", md.Citations)
+	}
+	if dates := base.CitationsAccessed(res.Dataset.Meta); len(dates) != 0 {
+		t.Errorf("expected accessed dates to be removed. got: %v", dates)
+	}
+}
@@ -3,8 +3,10 @@ package lib
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/ghodss/yaml"
+	peer "github.com/libp2p/go-libp2p-peer"
 	"github.com/qri-io/qri/config"
 )
 
@@ -74,15 +76,76 @@ func (m *ConfigMethods) GetConfig(p *GetConfigParams, res *[]byte) (err error) {
 	return nil
 }
 
-// SetConfig validates, updates and saves the config
+// SetConfig validates, updates and saves the config. private values can't be
+// changed, any in the update are replaced with the current private values
 func (m *ConfigMethods) SetConfig(update *config.Config, set *bool) (err error) {
 	if m.inst.rpc != nil {
 		return m.inst.rpc.Call("ConfigMethods.SetConfig", update, set)
 	}
 
+	update = update.WithPrivateValues(m.inst.cfg)
 	if err = update.Validate(); err != nil {
 		return fmt.Errorf("validating config: %s", err)
 	}
 
 	return m.inst.ChangeConfig(update)
 }
+
+// PeerGateParams defines parameters for adding or removing a peer from the
+// p2p block & allow lists
+type PeerGateParams struct {
+	// PeerID is a base58-encoded peer ID, optionally prefixed with "/ipfs/"
+	PeerID string
+	// List is the gate list to modify, either "block" or "allow"
+	List string
+	// Remove the peer from the list instead of adding it
+	Remove bool
+}
+
+// SetPeerGate adds or removes a peer ID from the p2p block or allow list,
+// saving the updated configuration. A running node applies the change
+// immediately, closing connections to newly-gated peers
+func (m *ConfigMethods) SetPeerGate(p *PeerGateParams, changed *bool) (err error) {
+	if m.inst.rpc != nil {
+		return m.inst.rpc.Call("ConfigMethods.SetPeerGate", p, changed)
+	}
+
+	idStr := strings.TrimPrefix(p.PeerID, "/ipfs/")
+	if _, err := peer.IDB58Decode(idStr); err != nil {
+		return fmt.Errorf("invalid peer id %q: %s", p.PeerID, err)
+	}
+
+	cfg := m.inst.cfg.Copy()
+	if cfg.P2P == nil {
+		return fmt.Errorf("p2p is not configured")
+	}
+
+	var list *[]string
+	switch p.List {
+	case "block":
+		list = &cfg.P2P.BlockedPeers
+	case "allow":
+		list = &cfg.P2P.AllowedPeers
+	default:
+		return fmt.Errorf("unknown peer gate list %q, must be 'block' or 'allow'", p.List)
+	}
+
+	var updated []string
+	for _, id := range *list {
+		if id != idStr {
+			updated = append(updated, id)
+		}
+	}
+	if !p.Remove {
+		updated = append(updated, idStr)
+	}
+
+	*changed = len(updated) != len(*list)
+	if !*changed {
+		return nil
+	}
+
+	*list = updated
+	var set bool
+	return m.SetConfig(cfg, &set)
+}
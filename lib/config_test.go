@@ -67,4 +67,56 @@ func TestSetConfig(t *testing.T) {
 	if !bytes.Equal(res, []byte(`"@qri_io"`)) {
 		t.Errorf("response mismatch. got %s", string(res))
 	}
+
+	// updates without private values keep the current ones
+	privKey := cfg.P2P.PrivKey
+	if err := m.SetConfig(cfg.WithoutPrivateValues(), &set); err != nil {
+		t.Fatal(err)
+	}
+	if inst.Config().P2P.PrivKey != privKey || inst.Config().Profile.PrivKey != cfg.Profile.PrivKey {
+		t.Error("expected private values to be kept")
+	}
+}
+
+func TestSetPeerGate(t *testing.T) {
+	cfg := config.DefaultConfigForTesting()
+	inst := NewInstanceFromConfigAndNode(cfg, nil)
+	m := NewConfigMethods(inst)
+
+	id := "QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn"
+	changed := false
+
+	if err := m.SetPeerGate(&PeerGateParams{PeerID: "/ipfs/" + id, List: "block"}, &changed); err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("expected adding a peer to change the blocklist")
+	}
+	if blocked := inst.Config().P2P.BlockedPeers; len(blocked) != 1 || blocked[0] != id {
+		t.Errorf("blocklist mismatch. got: %v", blocked)
+	}
+
+	if err := m.SetPeerGate(&PeerGateParams{PeerID: id, List: "block"}, &changed); err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("expected adding a blocked peer twice not to change the blocklist")
+	}
+
+	if err := m.SetPeerGate(&PeerGateParams{PeerID: id, List: "block", Remove: true}, &changed); err != nil {
+		t.Fatal(err)
+	}
+	if !changed || len(inst.Config().P2P.BlockedPeers) != 0 {
+		t.Errorf("expected peer to be removed from blocklist. got: %v", inst.Config().P2P.BlockedPeers)
+	}
+
+	bad := []*PeerGateParams{
+		{PeerID: "not_a_peer_id", List: "block"},
+		{PeerID: id, List: "deny"},
+	}
+	for _, p := range bad {
+		if err := m.SetPeerGate(p, &changed); err == nil {
+			t.Errorf("expected params %v to error", p)
+		}
+	}
 }
@@ -0,0 +1,112 @@
+package lib
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// connectionCheckInterval is how often a connected instance checks it still
+	// has peers
+	connectionCheckInterval = time.Second * 30
+	// reconnectMinBackoff is the wait between the first reconnection attempts.
+	// the wait doubles with each failed attempt
+	reconnectMinBackoff = time.Second
+	// reconnectMaxBackoff caps the wait between reconnection attempts
+	reconnectMaxBackoff = time.Minute * 5
+)
+
+// connectionSupervisor watches network connectivity, trying to reconnect with
+// exponential backoff when connections drop
+type connectionSupervisor struct {
+	checkInterval time.Duration
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+
+	// connected reports if there's a working network connection
+	connected func() bool
+	// reconnect begins an attempt to re-establish connections
+	reconnect func() error
+	// onReconnect is called once connections are back after a drop
+	onReconnect func() error
+}
+
+// superviseConnection starts a supervisor for the instance node, running
+// until either ctx or the instance context is cancelled
+func (inst *Instance) superviseConnection(ctx context.Context) {
+	s := connectionSupervisor{
+		checkInterval: connectionCheckInterval,
+		minBackoff:    reconnectMinBackoff,
+		maxBackoff:    reconnectMaxBackoff,
+		connected:     inst.node.HasPeers,
+		reconnect:     inst.node.Reconnect,
+		onReconnect:   inst.resetRemoteClient,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-inst.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	go s.run(ctx)
+}
+
+// resetRemoteClient replaces the instance remote client with one that uses
+// the current node connection
+func (inst *Instance) resetRemoteClient() error {
+	cli, err := newRemoteClient(inst.node, inst.cfg, inst.repoPath)
+	if err != nil {
+		return err
+	}
+	inst.remoteClientLk.Lock()
+	inst.remoteClient = cli
+	inst.remoteClientLk.Unlock()
+	return nil
+}
+
+// run checks connectivity until ctx is cancelled
+func (s connectionSupervisor) run(ctx context.Context) {
+	wait := s.checkInterval
+	backoff := s.minBackoff
+	dropped := false
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		if s.connected() {
+			if dropped {
+				log.Info("network connection re-established")
+				if err := s.onReconnect(); err != nil {
+					log.Errorf("reconnecting: %s", err)
+				}
+				dropped = false
+				backoff = s.minBackoff
+			}
+			t.Reset(s.checkInterval)
+			continue
+		}
+
+		if !dropped {
+			log.Info("lost network connection, reconnecting")
+			dropped = true
+		}
+		if err := s.reconnect(); err != nil {
+			log.Debugf("reconnect attempt: %s", err)
+		}
+
+		t.Reset(backoff)
+		if backoff *= 2; backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package lib
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConnectionSupervisor(t *testing.T) {
+	var (
+		lk          sync.Mutex
+		connected   = true
+		attempts    []time.Time
+		reconnected = make(chan struct{}, 1)
+	)
+
+	s := connectionSupervisor{
+		checkInterval: time.Millisecond * 10,
+		minBackoff:    time.Millisecond * 10,
+		maxBackoff:    time.Millisecond * 40,
+		connected: func() bool {
+			lk.Lock()
+			defer lk.Unlock()
+			return connected
+		},
+		reconnect: func() error {
+			lk.Lock()
+			defer lk.Unlock()
+			attempts = append(attempts, time.Now())
+			// come back online after a handful of attempts
+			if len(attempts) == 5 {
+				connected = true
+			}
+			return nil
+		},
+		onReconnect: func() error {
+			reconnected <- struct{}{}
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(time.Millisecond * 30)
+	lk.Lock()
+	if len(attempts) != 0 {
+		t.Errorf("expected no reconnect attempts while connected, got: %d", len(attempts))
+	}
+	connected = false
+	lk.Unlock()
+
+	select {
+	case <-reconnected:
+	case <-time.After(time.Second * 2):
+		t.Fatal("timed out waiting for reconnect")
+	}
+
+	lk.Lock()
+	if len(attempts) != 5 {
+		t.Errorf("expected 5 reconnect attempts, got: %d", len(attempts))
+	}
+	// waits between attempts double from minBackoff, up to maxBackoff:
+	// 10ms, 20ms, 40ms, 40ms
+	if len(attempts) == 5 {
+		if wait := attempts[2].Sub(attempts[1]); wait < s.minBackoff*2 {
+			t.Errorf("expected backoff to grow. want at least %s, got: %s", s.minBackoff*2, wait)
+		}
+		if wait := attempts[4].Sub(attempts[3]); wait < s.maxBackoff {
+			t.Errorf("expected backoff to reach max. want at least %s, got: %s", s.maxBackoff, wait)
+		}
+	}
+	lk.Unlock()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("supervisor didn't stop after context cancellation")
+	}
+}
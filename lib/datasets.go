@@ -7,6 +7,8 @@ import (
 	"io"
 	"io/ioutil"
 	"net/rpc"
+	"path/filepath"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/qri-io/dag"
@@ -16,6 +18,7 @@ import (
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/actions"
 	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/fsi"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
@@ -77,12 +80,105 @@ func (r *DatasetRequests) List(p *ListParams, res *[]repo.DatasetRef) error {
 		p.Offset = 0
 	}
 
-	replies, err := actions.ListDatasets(ctx, r.node, ds, p.Term, p.Limit, p.Offset, p.RPC, p.Published, p.ShowNumVersions)
+	filterTime := !p.NewerThan.IsZero() || !p.OlderThan.IsZero()
+	limit, offset := p.Limit, p.Offset
+	if filterTime {
+		// commit times are only known after datasets are loaded, so time-filtered
+		// lists must load all datasets & paginate after filtering
+		num, err := r.node.Repo.RefCount()
+		if err != nil {
+			return err
+		}
+		limit, offset = num, 0
+	}
+
+	replies, err := actions.ListDatasets(ctx, r.node, ds, p.Term, limit, offset, p.RPC, p.Published, p.ShowNumVersions)
+	if err != nil {
+		return err
+	}
+
+	if filterTime {
+		replies = filterRefsByCommitTime(replies, p.NewerThan, p.OlderThan)
+		if p.Offset >= len(replies) {
+			replies = []repo.DatasetRef{}
+		} else {
+			replies = replies[p.Offset:]
+		}
+		if p.Limit < len(replies) {
+			replies = replies[:p.Limit]
+		}
+	}
 
 	*res = replies
-	return err
+	return nil
+}
+
+// filterRefsByCommitTime drops references with a latest commit timestamp that
+// isn't after newerThan or isn't before olderThan. zero-valued times are
+// ignored. References without a loaded commit are dropped
+func filterRefsByCommitTime(refs []repo.DatasetRef, newerThan, olderThan time.Time) []repo.DatasetRef {
+	filtered := make([]repo.DatasetRef, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Dataset == nil || ref.Dataset.Commit == nil {
+			continue
+		}
+		ts := ref.Dataset.Commit.Timestamp
+		if !newerThan.IsZero() && !ts.After(newerThan) {
+			continue
+		}
+		if !olderThan.IsZero() && !ts.Before(olderThan) {
+			continue
+		}
+		filtered = append(filtered, ref)
+	}
+	return filtered
 }
 
+// bodyCountDir returns the directory body counts are cached in, returning the
+// empty string if this instance has no repo path
+func (r *DatasetRequests) bodyCountDir() string {
+	if r.inst == nil || r.inst.repoPath == "" {
+		return ""
+	}
+	return filepath.Join(r.inst.repoPath, "bodycount")
+}
+
+// DefaultBodyIndexMinSize is the smallest body size in bytes that gets a row
+// index on save when repo.bodyindexminsize isn't configured
+const DefaultBodyIndexMinSize = base.DefaultBodyIndexMinSize
+
+// bodyIndexMinSize reads the body indexing threshold from config, returning
+// -1 if body indexing is turned off
+func (r *DatasetRequests) bodyIndexMinSize() int64 {
+	if r.inst == nil || r.inst.cfg == nil || r.inst.cfg.Repo == nil || r.inst.cfg.Repo.BodyIndexMinSize == 0 {
+		return DefaultBodyIndexMinSize
+	}
+	if r.inst.cfg.Repo.BodyIndexMinSize < 0 {
+		return -1
+	}
+	return r.inst.cfg.Repo.BodyIndexMinSize
+}
+
+// GeoJSONFormat is a body format that emits geographic datasets as a GeoJSON
+// FeatureCollection, only valid when getting a body
+const GeoJSONFormat = "geojson"
+
+// ErrNoGeometry is returned when getting a GeoJSON body of a dataset with no
+// identifiable geometry columns
+var ErrNoGeometry = base.ErrNoGeometry
+
+// ArrowFormat is a body format that emits tabular datasets as an Arrow IPC
+// stream, only valid when getting a body
+const ArrowFormat = "arrow"
+
+// FeatherFormat is a body format that emits tabular datasets as an Arrow IPC
+// file (Feather V2), only valid when getting a body
+const FeatherFormat = "feather"
+
+// NDJSONFormat is a body format that emits each entry of a body as a line of
+// json, "jsonl" is accepted as an alias. only valid when getting a body
+const NDJSONFormat = base.NDJSONFormat
+
 // GetParams defines parameters for looking up the body of a dataset
 type GetParams struct {
 	// Path to get, this will often be a dataset reference like me/dataset
@@ -97,6 +193,14 @@ type GetParams struct {
 
 	Limit, Offset int
 	All           bool
+
+	// EstimateCount allows getting structure.entries to extrapolate from the
+	// start of the body instead of reading all of it when the entry count
+	// isn't recorded
+	EstimateCount bool
+	// Where filters body rows with an expression written like a sql WHERE
+	// clause, eg: "pop > 1000000 AND city = 'toronto'"
+	Where string
 }
 
 // GetResult combines data with it's hashed path
@@ -104,8 +208,15 @@ type GetResult struct {
 	Ref     *repo.DatasetRef `json:"ref"`
 	Dataset *dataset.Dataset `json:"data"`
 	Bytes   []byte           `json:"bytes"`
+	// Count is set when getting structure.entries or structure.length
+	Count *BodyCount `json:"count,omitempty"`
+	// Entries is the number of body entries in Bytes, set when getting the body
+	Entries int `json:"entries,omitempty"`
 }
 
+// BodyCount is the size of a dataset body
+type BodyCount = base.BodyCount
+
 // Get retrieves datasets and components for a given reference. If p.Ref is provided, it is
 // used to load the dataset, otherwise p.Path is parsed to create a reference. The
 // dataset will be loaded from the local repo if available, or by asking peers for it.
@@ -132,8 +243,13 @@ func (r *DatasetRequests) Get(p *GetParams, res *GetResult) (err error) {
 			return fmt.Errorf("loading linked dataset: %s", err)
 		}
 	} else {
-		ds, err = dsfs.LoadDataset(ctx, r.node.Repo.Store(), ref.Path)
-		if err != nil {
+		// datasets linked to a working directory are always stored locally,
+		// resolve without touching the network
+		if ref.FSIPath != "" {
+			if ds, err = actions.ResolveDatasetRefLocal(ctx, r.node, ref); err != nil {
+				return err
+			}
+		} else if ds, err = dsfs.LoadDataset(ctx, r.node.Repo.Store(), ref.Path); err != nil {
 			return fmt.Errorf("loading dataset: %s", err)
 		}
 	}
@@ -143,7 +259,16 @@ func (r *DatasetRequests) Get(p *GetParams, res *GetResult) (err error) {
 	res.Ref = ref
 	res.Dataset = ds
 
-	if err = base.OpenDataset(ctx, r.node.Repo.Filesystem(), ds); err != nil {
+	if ref.BodyMissing && !p.UseFSI {
+		// the body of a lazily-added dataset isn't stored locally, don't go
+		// looking for it
+		if p.Selector == "body" {
+			return NewError(ErrBodyMissing, fmt.Sprintf("the body of %s hasn't been fetched, fetch it with `qri add --fetch-body %s`", ref.AliasString(), ref.AliasString()))
+		}
+		if err = base.OpenDatasetScripts(ctx, r.node.Repo.Filesystem(), ds); err != nil {
+			return
+		}
+	} else if err = base.OpenDataset(ctx, r.node.Repo.Filesystem(), ds); err != nil {
 		return
 	}
 
@@ -152,27 +277,65 @@ func (r *DatasetRequests) Get(p *GetParams, res *GetResult) (err error) {
 		if !p.All && (p.Limit < 0 || p.Offset < 0) {
 			return fmt.Errorf("invalid limit / offset settings")
 		}
-		df, err := dataset.ParseDataFormatString(p.Format)
+		requested := p.Format
+		if base.IsNDJSONFormat(requested) {
+			requested = NDJSONFormat
+		}
+		format, fcfg := requested, p.FormatConfig
+		if format == GeoJSONFormat || format == ArrowFormat || format == FeatherFormat || format == NDJSONFormat {
+			// geojson, arrow & ndjson are built from the json body
+			format, fcfg = "json", nil
+		}
+		df, err := dataset.ParseDataFormatString(format)
 		if err != nil {
 			return err
 		}
 
+		var where *base.RowFilter
+		if p.Where != "" {
+			if where, err = base.ParseRowFilter(ds.Structure, p.Where); err != nil {
+				return err
+			}
+		}
+
 		var bufData []byte
 		if p.UseFSI {
-			if bufData, err = fsi.GetBody(ref.FSIPath, df, p.FormatConfig, p.Offset, p.Limit, p.All); err != nil {
+			if bufData, res.Entries, err = fsi.GetBody(ref.FSIPath, df, fcfg, where, p.Offset, p.Limit, p.All); err != nil {
 				return err
 			}
 		} else {
-			if bufData, err = actions.GetBody(r.node, ds, df, p.FormatConfig, p.Limit, p.Offset, p.All); err != nil {
+			if bufData, res.Entries, err = actions.GetBody(r.node, ds, true, df, fcfg, where, p.Limit, p.Offset, p.All); err != nil {
+				return err
+			}
+		}
+
+		switch requested {
+		case GeoJSONFormat:
+			if bufData, err = base.ConvertJSONBodyToGeoJSON(ds.Structure, bufData); err != nil {
+				return err
+			}
+		case ArrowFormat:
+			if bufData, err = base.ConvertJSONBodyToArrow(ds.Structure, bufData); err != nil {
+				return err
+			}
+		case FeatherFormat:
+			if bufData, err = base.ConvertJSONBodyToFeather(ds.Structure, bufData); err != nil {
+				return err
+			}
+		case NDJSONFormat:
+			if bufData, err = base.ConvertJSONBodyToNDJSON(bufData); err != nil {
 				return err
 			}
 		}
 
 		res.Bytes = bufData
 		return err
-	} else if p.Selector == "transform.script" && ds.Transform != nil && ds.Transform.ScriptFile() != nil {
+	} else if p.Selector == "transform.script" || p.Selector == "tf.script" {
 		// `qri get transform.script` loads the transform script, as a special case
 		// TODO (b5): this is a hack that should be generalized
+		if ds.Transform == nil || ds.Transform.ScriptFile() == nil {
+			return fmt.Errorf("dataset %s has no transform script", ref.AliasString())
+		}
 		res.Bytes, err = ioutil.ReadAll(ds.Transform.ScriptFile())
 		return err
 	} else if p.Selector == "viz.script" && ds.Viz != nil && ds.Viz.ScriptFile() != nil {
@@ -184,10 +347,32 @@ func (r *DatasetRequests) Get(p *GetParams, res *GetResult) (err error) {
 		res.Bytes, err = ioutil.ReadAll(ds.Viz.RenderedFile())
 		return err
 	} else {
+		if p.Selector == "structure.entries" || p.Selector == "structure.length" {
+			// counts are often missing from the structure, fill them in from the
+			// cheapest available source. stored bodies are immutable, linked bodies
+			// are not, so only counts of stored bodies are cached
+			dir, store := r.bodyCountDir(), r.node.Repo.Store()
+			if p.UseFSI {
+				dir, store = "", nil
+			}
+			if ds.Structure == nil {
+				return fmt.Errorf("dataset %s has no structure", ref.AliasString())
+			}
+			if res.Count, err = base.CountBody(ctx, store, ds, dir, p.EstimateCount); err != nil {
+				return err
+			}
+			ds.Structure.Entries = res.Count.Entries
+			ds.Structure.Length = res.Count.Length
+		}
+
 		var value interface{}
 		if p.Selector == "" {
 			// `qri get` without a selector loads only the dataset head
 			value = res.Dataset
+		} else if p.Selector == "attachments" {
+			// `qri get attachments` lists file attachments, which are stored in
+			// arbitrary metadata that selectors can't reach
+			value = base.Attachments(res.Dataset)
 		} else {
 			// `qri get <selector>` loads only the applicable component / field
 			value, err = base.ApplyPath(res.Dataset, p.Selector)
@@ -219,6 +404,16 @@ func (r *DatasetRequests) Get(p *GetParams, res *GetResult) (err error) {
 	}
 }
 
+// TransformScriptExtension returns the file extension for a transform script
+// written in the given syntax, including the leading "."
+func TransformScriptExtension(syntax string) string {
+	switch syntax {
+	case "", "starlark":
+		return ".star"
+	}
+	return "." + syntax
+}
+
 // SaveParams encapsulates arguments to Save
 type SaveParams struct {
 	// dataset supplies params directly, all other param fields override values
@@ -235,6 +430,13 @@ type SaveParams struct {
 	BodyPath string
 	// absolute path or URL to the list of dataset files or components to load
 	FilePaths []string
+	// name of a registered dataset template to fill structure & meta from when
+	// those components aren't otherwise provided
+	Template string
+	// SPDX identifier of the license to record in dataset metadata
+	License string
+	// local files to attach to the dataset, named by their base filename
+	Attachments []string
 	// secrets for transform execution
 	Secrets map[string]string
 	// optional writer to have transform script record standard output to
@@ -300,6 +502,11 @@ func (r *DatasetRequests) Save(p *SaveParams, res *repo.DatasetRef) (err error)
 	if p.Private {
 		return fmt.Errorf("option to make dataset private not yet implimented, refer to https://github.com/qri-io/qri/issues/291 for updates")
 	}
+	if !p.DryRun {
+		if err := base.StoreWritable(r.node.Repo.Store()); err != nil {
+			return NewError(err, "cannot save: the configured store is read-only")
+		}
+	}
 
 	ref, err := repo.ParseDatasetRef(p.Ref)
 	if err != nil {
@@ -370,6 +577,42 @@ func (r *DatasetRequests) Save(p *SaveParams, res *repo.DatasetRef) (err error)
 		ds = dsf
 	}
 
+	if p.Template != "" {
+		dir := r.templatesDir()
+		if dir == "" {
+			return fmt.Errorf("dataset templates require a qri repo directory")
+		}
+		tmpl, err := ReadDatasetTemplate(dir, p.Template)
+		if err != nil {
+			return err
+		}
+		applyDatasetTemplate(ds, tmpl)
+	}
+
+	if p.License != "" {
+		license, err := base.ValidLicense(p.License)
+		if err != nil {
+			return err
+		}
+		if ds.Meta == nil {
+			ds.Meta = &dataset.Meta{}
+		}
+		ds.Meta.License = &dataset.License{Type: license}
+	}
+
+	if len(p.Attachments) > 0 {
+		var prev *dataset.Dataset
+		prevRef := repo.DatasetRef{Peername: ds.Peername, Name: ds.Name}
+		if err := repo.CanonicalizeDatasetRef(r.node.Repo, &prevRef); err == nil && prevRef.Path != "" {
+			if prev, err = dsfs.LoadDataset(ctx, r.node.Repo.Store(), prevRef.Path); err != nil {
+				return err
+			}
+		}
+		if err = base.AddAttachments(ctx, r.node.Repo.Store(), ds, prev, p.Attachments); err != nil {
+			return err
+		}
+	}
+
 	if ds.Name == "" {
 		return fmt.Errorf("name is required")
 	}
@@ -399,6 +642,10 @@ func (r *DatasetRequests) Save(p *SaveParams, res *repo.DatasetRef) (err error)
 		ConvertFormatToPrev: p.ConvertFormatToPrev,
 		Force:               p.Force,
 		ShouldRender:        p.ShouldRender,
+		BodyIndexMinSize:    r.bodyIndexMinSize(),
+	}
+	if r.inst != nil && r.inst.Config() != nil {
+		switches.UnpinPrevious = r.inst.Config().Store.PinPolicy() == config.PinPolicyLatest
 	}
 	ref, err = actions.SaveDataset(ctx, r.node, ds, p.Secrets, p.ScriptOutput, switches)
 	if err != nil {
@@ -412,6 +659,12 @@ func (r *DatasetRequests) Save(p *SaveParams, res *repo.DatasetRef) (err error)
 		if err = r.node.Repo.PutRef(ref); err != nil {
 			return err
 		}
+		if (p.ReadFSI || p.WriteFSI) && !p.DryRun {
+			// working directory files now match the saved version
+			if err = fsi.WriteVersionFile(fsiPath, ref.Path); err != nil {
+				return err
+			}
+		}
 	}
 
 	if p.ReturnBody {
@@ -489,15 +742,29 @@ func (r *DatasetRequests) Rename(p *RenameParams, res *repo.DatasetRef) (err err
 		return fmt.Errorf("current name is required to rename a dataset")
 	}
 
-	if err := actions.ModifyDataset(r.node, &p.Current, &p.New, true /*isRename*/); err != nil {
+	current, renamed := p.Current, p.New
+	// a linked directory must be able to follow the rename, check before
+	// changing anything
+	if err = repo.CanonicalizeDatasetRef(r.node.Repo, &current); err == nil && current.FSIPath != "" {
+		if _, ok := fsi.GetLinkedFilesysRef(current.FSIPath); !ok {
+			return fmt.Errorf("%s: %s", fsi.ErrNoLink, current.FSIPath)
+		}
+	}
+
+	if err := actions.ModifyDataset(r.node, &current, &renamed, true /*isRename*/); err != nil {
 		return err
 	}
+	if renamed.FSIPath != "" {
+		if err = fsi.ModifyLinkReference(renamed.FSIPath, renamed.AliasString()); err != nil {
+			return err
+		}
+	}
 
-	if err = actions.DatasetHead(ctx, r.node, &p.New); err != nil {
+	if err = actions.DatasetHead(ctx, r.node, &renamed); err != nil {
 		log.Debug(err.Error())
 		return err
 	}
-	*res = p.New
+	*res = renamed
 	return nil
 }
 
@@ -507,14 +774,19 @@ type RemoveParams struct {
 	Revision       rev.Rev
 	Unlink         bool // If true, break any FSI link
 	DeleteFSIFiles bool // If true, delete tracked files from the designated FSI link
+	Soft           bool // If true, move the dataset to the trash instead of deleting it
+	KeepFiles      bool // If true, remove the dataset & its FSI link, leaving linked files on disk
 }
 
 // RemoveResponse gives the results of a remove
 type RemoveResponse struct {
 	Ref             string
 	NumDeleted      int
-	Unlinked        bool // true if the remove unlinked an FSI-linked dataset
-	DeletedFSIFiles bool // true if the remove deleted FSI-linked files
+	Unlinked        bool   // true if the remove unlinked an FSI-linked dataset
+	DeletedFSIFiles bool   // true if the remove deleted FSI-linked files
+	Trashed         bool   // true if the dataset was moved to the trash
+	KeptFiles       bool   // true if linked files were left in place
+	FSIPath         string // linked directory files were kept in, if KeptFiles
 }
 
 // Remove a dataset entirely or remove a certain number of revisions
@@ -547,6 +819,22 @@ func (r *DatasetRequests) Remove(p *RemoveParams, res *RemoveResponse) error {
 	if ref.FSIPath == "" && p.DeleteFSIFiles {
 		return fmt.Errorf("can't delete files, dataset is not linked to a directory")
 	}
+	if p.Soft && p.Revision.Gen != rev.AllGenerations {
+		return fmt.Errorf("soft delete can only remove entire datasets, not individual versions")
+	}
+	if p.KeepFiles {
+		if ref.FSIPath == "" {
+			return fmt.Errorf("can't keep files, dataset is not linked to a directory")
+		}
+		if p.DeleteFSIFiles {
+			return fmt.Errorf("can't both keep & delete linked files")
+		}
+		if p.Revision.Gen != rev.AllGenerations && !noHistory {
+			return fmt.Errorf("keeping files removes the entire dataset, not individual versions")
+		}
+		// dropping the dataset from the repo always drops the link
+		p.Unlink = true
+	}
 
 	if ref.FSIPath != "" {
 		if p.DeleteFSIFiles {
@@ -563,6 +851,10 @@ func (r *DatasetRequests) Remove(p *RemoveParams, res *RemoveResponse) error {
 			}
 			res.Unlinked = true
 		}
+		if p.KeepFiles {
+			res.KeptFiles = true
+			res.FSIPath = ref.FSIPath
+		}
 	}
 
 	if noHistory {
@@ -578,6 +870,20 @@ func (r *DatasetRequests) Remove(p *RemoveParams, res *RemoveResponse) error {
 			res.Unlinked = true
 		}
 
+		if p.Soft {
+			trash, err := r.trash()
+			if err != nil {
+				return err
+			}
+			if err := actions.TrashDataset(ctx, r.node, trash, &ref); err != nil {
+				return err
+			}
+			res.NumDeleted = rev.AllGenerations
+			res.Trashed = true
+			_, err = r.sweepTrash(ctx, trash)
+			return err
+		}
+
 		// Delete entire dataset for all generations.
 		if err := actions.DeleteDataset(ctx, r.node, &ref); err != nil {
 			return err
@@ -619,6 +925,15 @@ type AddParams struct {
 	Ref        string
 	LinkDir    string
 	RemoteAddr string // remote to attempt to pull from
+	// Lazy adds a dataset without fetching its body, use FetchBody to
+	// get the body later. lazy adds require a remote
+	Lazy bool
+	// Verify re-hashes every block of the added dataset, confirming the pull
+	// is complete & intact
+	Verify bool
+	// Recursive also adds the upstream datasets read by the transforms of the
+	// added dataset & each of its dependencies
+	Recursive bool
 }
 
 // Add adds an existing dataset to a peer's repository
@@ -637,10 +952,27 @@ func (r *DatasetRequests) Add(p *AddParams, res *repo.DatasetRef) (err error) {
 		p.RemoteAddr = r.inst.cfg.Registry.Location
 	}
 
-	if err = actions.AddDataset(ctx, r.node, r.inst.RemoteClient(), p.RemoteAddr, &ref); err != nil {
+	if p.Lazy {
+		if p.LinkDir != "" {
+			return NewError(ErrBadArgs, "cannot link a dataset to a directory without fetching its body")
+		}
+		if p.Verify {
+			return NewError(ErrBadArgs, "cannot verify a dataset without fetching its body")
+		}
+		err = actions.AddDatasetPartial(ctx, r.node, r.inst.RemoteClient(), p.RemoteAddr, &ref)
+	} else {
+		err = actions.AddDataset(ctx, r.node, r.inst.RemoteClient(), p.RemoteAddr, &ref, p.Verify)
+	}
+	if err != nil {
 		return err
 	}
 
+	if p.Recursive {
+		if _, err = actions.AddDatasetDependencies(ctx, r.node, r.inst.RemoteClient(), p.RemoteAddr, ref, p.Lazy); err != nil {
+			return err
+		}
+	}
+
 	*res = ref
 
 	if p.LinkDir != "" {
@@ -658,6 +990,81 @@ func (r *DatasetRequests) Add(p *AddParams, res *repo.DatasetRef) (err error) {
 	return nil
 }
 
+// ErrAttachmentNotFound is returned when a dataset has no attachment by a
+// given name
+var ErrAttachmentNotFound = base.ErrAttachmentNotFound
+
+// AttachmentParams defines parameters for reading a dataset file attachment
+type AttachmentParams struct {
+	Ref  string
+	Name string
+}
+
+// Attachment reads the contents of a file attached to a dataset
+func (r *DatasetRequests) Attachment(p *AttachmentParams, res *[]byte) (err error) {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.Attachment", p, res)
+	}
+	ctx := context.TODO()
+
+	if p.Name == "" {
+		return NewError(ErrBadArgs, "attachment name is required")
+	}
+	ref, err := base.ToDatasetRef(p.Ref, r.node.Repo, false)
+	if err != nil {
+		return err
+	}
+	ds, err := dsfs.LoadDataset(ctx, r.node.Repo.Store(), ref.Path)
+	if err != nil {
+		return fmt.Errorf("loading dataset: %s", err)
+	}
+
+	f, err := base.OpenAttachment(ctx, r.node.Repo.Store(), ds, p.Name)
+	if err == ErrAttachmentNotFound {
+		return NewError(err, fmt.Sprintf("dataset %s has no attachment named %q", ref.AliasString(), p.Name))
+	} else if err != nil {
+		return err
+	}
+	*res, err = ioutil.ReadAll(f)
+	return err
+}
+
+// ErrBodyMissing is returned when reading the body of a dataset that was added
+// without one
+var ErrBodyMissing = fmt.Errorf("dataset body hasn't been fetched")
+
+// FetchBodyParams encapsulates parameters for fetching the body of a dataset
+// that was added lazily
+type FetchBodyParams struct {
+	Ref        string
+	RemoteAddr string // remote to fetch from, defaults to the registry
+}
+
+// FetchBody fetches the body of a lazily-added dataset from a remote, storing
+// it locally. fetching the body of a dataset that's already stored is a no-op
+func (r *DatasetRequests) FetchBody(p *FetchBodyParams, res *repo.DatasetRef) (err error) {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.FetchBody", p, res)
+	}
+	ctx := context.TODO()
+
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return err
+	}
+
+	if p.RemoteAddr == "" && r.inst != nil && r.inst.cfg.Registry != nil {
+		p.RemoteAddr = r.inst.cfg.Registry.Location
+	}
+
+	if err = actions.FetchDatasetBody(ctx, r.node, r.inst.RemoteClient(), p.RemoteAddr, &ref); err != nil {
+		return err
+	}
+
+	*res = ref
+	return nil
+}
+
 // ValidateDatasetParams defines parameters for dataset
 // data validation
 type ValidateDatasetParams struct {
@@ -666,6 +1073,9 @@ type ValidateDatasetParams struct {
 	BodyFilename string
 	Body         io.Reader
 	Schema       io.Reader
+	// Workers sets the number of CSV body chunks to validate in parallel.
+	// values less than two validate the body sequentially
+	Workers int
 }
 
 // Validate gives a dataset of errors and issues for a given dataset
@@ -700,7 +1110,7 @@ func (r *DatasetRequests) Validate(p *ValidateDatasetParams, errors *[]jsonschem
 		}
 	}
 
-	*errors, err = actions.Validate(ctx, r.node, ref, body, schema)
+	*errors, err = actions.Validate(ctx, r.node, ref, body, schema, p.Workers)
 	return
 }
 
@@ -1,6 +1,7 @@
 package lib
 
 import (
+	"archive/zip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -13,8 +14,10 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ghodss/yaml"
+	"github.com/google/go-cmp/cmp"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsfs"
 	"github.com/qri-io/dataset/dsio"
@@ -24,6 +27,7 @@ import (
 	"github.com/qri-io/qfs/cafs"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/fsi"
 	"github.com/qri-io/qri/p2p"
 	p2ptest "github.com/qri-io/qri/p2p/test"
 	"github.com/qri-io/qri/repo"
@@ -164,6 +168,105 @@ func TestDatasetRequestsForceSave(t *testing.T) {
 	}
 }
 
+func TestDatasetRequestsSaveLicense(t *testing.T) {
+	node := newTestQriNode(t)
+	ref := addCitiesDataset(t, node)
+	r := NewDatasetRequests(node, nil)
+
+	res := &repo.DatasetRef{}
+	err := r.Save(&SaveParams{Ref: ref.AliasString(), License: "not-a-license"}, res)
+	expect := `unrecognized license "not-a-license". license must be a known SPDX identifier like "CC-BY-4.0" or "MIT"`
+	if err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+
+	if err := r.Save(&SaveParams{Ref: ref.AliasString(), License: "cc-by-4.0"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if !base.HasLicense(res.Dataset) || res.Dataset.Meta.License.Type != "CC-BY-4.0" {
+		t.Errorf("expected saved dataset to have license CC-BY-4.0. got meta: %v", res.Dataset.Meta)
+	}
+	if res.Dataset.Meta.Title == "" {
+		t.Error("expected setting a license to keep existing meta fields")
+	}
+}
+
+func TestDatasetRequestsSaveAttachments(t *testing.T) {
+	node := newTestQriNode(t)
+	ref := addCitiesDataset(t, node)
+	r := NewDatasetRequests(node, nil)
+
+	dir, err := ioutil.TempDir("", "save_attachments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	codebook := filepath.Join(dir, "codebook.pdf")
+	dictionary := filepath.Join(dir, "dictionary.csv")
+	ioutil.WriteFile(codebook, []byte("codebook"), 0644)
+	ioutil.WriteFile(dictionary, []byte("field,description"), 0644)
+
+	res := &repo.DatasetRef{}
+	if err := r.Save(&SaveParams{Ref: ref.AliasString(), Attachments: []string{codebook}}, res); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Save(&SaveParams{Ref: ref.AliasString(), Attachments: []string{dictionary}}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Dataset.Meta.Title == "" {
+		t.Error("expected attaching files to keep existing meta fields")
+	}
+
+	got := &GetResult{}
+	if err := r.Get(&GetParams{Path: ref.AliasString(), Selector: "attachments", Format: "json"}, got); err != nil {
+		t.Fatal(err)
+	}
+	att := map[string]string{}
+	if err := json.Unmarshal(got.Bytes, &att); err != nil {
+		t.Fatal(err)
+	}
+	if len(att) != 2 || att["codebook.pdf"] == "" || att["dictionary.csv"] == "" {
+		t.Errorf("expected both attachments to be listed. got: %v", att)
+	}
+
+	var data []byte
+	if err := r.Attachment(&AttachmentParams{Ref: ref.AliasString(), Name: "codebook.pdf"}, &data); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "codebook" {
+		t.Errorf("attachment contents mismatch. got: %q", string(data))
+	}
+
+	err = r.Attachment(&AttachmentParams{Ref: ref.AliasString(), Name: "missing.pdf"}, &data)
+	expect := `dataset peer/cities has no attachment named "missing.pdf"`
+	if libErr, ok := err.(Error); !ok || libErr.Message() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+
+	exportDir, err := ioutil.TempDir("", "export_attachments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(exportDir)
+	var written string
+	exp := NewExportRequests(node, nil)
+	if err := exp.Export(&ExportParams{Ref: ref.AliasString(), TargetDir: exportDir, Zipped: true, Format: "json"}, &written); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.OpenReader(filepath.Join(exportDir, written))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	names := []string{}
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	if diff := cmp.Diff([]string{"attachments/codebook.pdf", "attachments/dictionary.csv", "dataset.json"}, names); diff != "" {
+		t.Errorf("zip contents mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestDatasetRequestsSaveRecall(t *testing.T) {
 	node := newTestQriNode(t)
 	ref := addNowTransformDataset(t, node)
@@ -267,6 +370,9 @@ func TestDatasetRequestsList(t *testing.T) {
 		}
 	}
 
+	distantPast := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	distantFuture := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+
 	cases := []struct {
 		description string
 		p           *ListParams
@@ -282,6 +388,11 @@ func TestDatasetRequestsList(t *testing.T) {
 		{"list datasets - limit 2 offset 5", &ListParams{OrderBy: "", Limit: 2, Offset: 5}, []repo.DatasetRef{}, ""},
 		{"list datasets - order by timestamp", &ListParams{OrderBy: "timestamp", Limit: 30, Offset: 0}, []repo.DatasetRef{cities, counter, craigslist, movies, sitemap}, ""},
 		{"list datasets - peername 'me'", &ListParams{Peername: "me", OrderBy: "timestamp", Limit: 30, Offset: 0}, []repo.DatasetRef{cities, counter, craigslist, movies, sitemap}, ""},
+		{"list datasets - newer than distant past", &ListParams{Limit: 30, NewerThan: distantPast}, []repo.DatasetRef{cities, counter, craigslist, movies, sitemap}, ""},
+		{"list datasets - newer than distant future", &ListParams{Limit: 30, NewerThan: distantFuture}, []repo.DatasetRef{}, ""},
+		{"list datasets - older than distant past", &ListParams{Limit: 30, OlderThan: distantPast}, []repo.DatasetRef{}, ""},
+		{"list datasets - older than distant future limit 2 offset 2", &ListParams{Limit: 2, Offset: 2, OlderThan: distantFuture}, []repo.DatasetRef{craigslist, movies}, ""},
+		{"list datasets - time range & term", &ListParams{Term: "c", Limit: 30, NewerThan: distantPast, OlderThan: distantFuture}, []repo.DatasetRef{cities, counter, craigslist}, ""},
 		// TODO: re-enable {&ListParams{OrderBy: "name", Limit: 30, Offset: 0}, []*repo.DatasetRef{cities, counter, movies}, ""},
 	}
 
@@ -386,6 +497,7 @@ func TestDatasetRequestsGet(t *testing.T) {
 	moviesBodyFile := moviesDs.BodyFile()
 	reader := dsio.NewCSVReader(moviesDs.Structure, moviesBodyFile)
 	moviesBody := mustBeArray(base.ReadEntries(reader))
+	firstTitle := moviesBody[0].([]interface{})[0].(string)
 
 	prettyJSONConfig, _ := dataset.NewJSONOptions(map[string]interface{}{"pretty": true})
 	nonprettyJSONConfig, _ := dataset.NewJSONOptions(map[string]interface{}{"pretty": false})
@@ -462,6 +574,26 @@ func TestDatasetRequestsGet(t *testing.T) {
 			&GetParams{Path: "peer/movies", Selector: "body", Format: "json",
 				Limit: 2, Offset: 10, All: false}, bodyToString(moviesBody[10:12])},
 
+		{"body filtered by title",
+			&GetParams{Path: "peer/movies", Selector: "body", Format: "json",
+				Where: fmt.Sprintf("title = '%s'", strings.Replace(firstTitle, "'", "''", -1)), All: true}, bodyToString(moviesBody[:1])},
+
+		{"body filtered with no matching rows",
+			&GetParams{Path: "peer/movies", Selector: "body", Format: "json",
+				Where: "duration > 100000000", All: true}, "[]"},
+
+		{"body filtered on an unknown column",
+			&GetParams{Path: "peer/movies", Selector: "body", Format: "json",
+				Where: "rating = 5", All: true}, `invalid filter: unknown column "rating"`},
+
+		{"body as ndjson",
+			&GetParams{Path: "peer/movies", Selector: "body", Format: "ndjson",
+				Limit: 2, Offset: 0, All: false}, bodyToNDJSONString(moviesBody[:2])},
+
+		{"body as jsonl",
+			&GetParams{Path: "peer/movies", Selector: "body", Format: "jsonl",
+				Limit: 2, Offset: 10, All: false}, bodyToNDJSONString(moviesBody[10:12])},
+
 		{"head non-pretty json",
 			&GetParams{Path: "peer/movies", Format: "json", FormatConfig: nonprettyJSONConfig},
 			componentToString(setDatasetName(moviesDs, "peer/movies"), "non-pretty json")},
@@ -470,6 +602,10 @@ func TestDatasetRequestsGet(t *testing.T) {
 			&GetParams{Path: "peer/movies", Selector: "body", Format: "json",
 				FormatConfig: prettyJSONConfig, Limit: 3, Offset: 0, All: false},
 			bodyToPrettyString(moviesBody[:3])},
+
+		{"transform script of dataset without a transform",
+			&GetParams{Path: "peer/movies", Selector: "transform.script"},
+			"dataset peer/movies has no transform script"},
 	}
 
 	req := NewDatasetRequests(node, nil)
@@ -490,6 +626,80 @@ func TestDatasetRequestsGet(t *testing.T) {
 	}
 }
 
+func TestDatasetRequestsGetTransformScript(t *testing.T) {
+	node := newTestQriNode(t)
+	ref := addNowTransformDataset(t, node)
+	req := NewDatasetRequests(node, nil)
+
+	expect, err := ioutil.ReadFile("testdata/now_tf/transform.star")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sel := range []string{"transform.script", "tf.script"} {
+		got := &GetResult{}
+		if err := req.Get(&GetParams{Path: ref.AliasString(), Selector: sel}, got); err != nil {
+			t.Fatalf("selector %q: %s", sel, err)
+		}
+		if string(got.Bytes) != string(expect) {
+			t.Errorf("selector %q: script mismatch.\nexpected:\n%s\ngot:\n%s", sel, expect, got.Bytes)
+		}
+		if got.Dataset.Transform == nil || got.Dataset.Transform.Syntax != "starlark" {
+			t.Errorf("selector %q: expected result dataset to include the transform syntax", sel)
+		}
+	}
+}
+
+func TestDatasetRequestsGetCount(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewDatasetRequests(node, nil)
+
+	for _, sel := range []string{"structure.entries", "structure.length"} {
+		got := &GetResult{}
+		if err := req.Get(&GetParams{Path: "peer/movies", Selector: sel, Format: "json"}, got); err != nil {
+			t.Fatalf("selector %q: %s", sel, err)
+		}
+		if got.Count == nil || !got.Count.Exact {
+			t.Fatalf("selector %q: expected an exact count, got: %v", sel, got.Count)
+		}
+		expect := got.Count.Entries
+		if sel == "structure.length" {
+			expect = got.Count.Length
+		}
+		if string(got.Bytes) != strconv.Itoa(expect) {
+			t.Errorf("selector %q: expected %d, got: %s", sel, expect, got.Bytes)
+		}
+	}
+
+	got := &GetResult{}
+	if err := req.Get(&GetParams{Path: "peer/movies", Selector: "structure.format"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != nil {
+		t.Errorf("expected other selectors not to count the body")
+	}
+}
+
+func TestTransformScriptExtension(t *testing.T) {
+	cases := map[string]string{
+		"":         ".star",
+		"starlark": ".star",
+		"sql":      ".sql",
+	}
+	for syntax, expect := range cases {
+		if got := TransformScriptExtension(syntax); got != expect {
+			t.Errorf("syntax %q: expected %q, got %q", syntax, expect, got)
+		}
+	}
+}
+
 func setDatasetName(ds *dataset.Dataset, name string) *dataset.Dataset {
 	parts := strings.Split(name, "/")
 	ds.Peername = parts[0]
@@ -530,6 +740,14 @@ func bodyToString(component interface{}) string {
 	return string(bytes)
 }
 
+func bodyToNDJSONString(rows []interface{}) string {
+	str := ""
+	for _, row := range rows {
+		str += bodyToString(row) + "\n"
+	}
+	return str
+}
+
 func bodyToPrettyString(component interface{}) string {
 	bytes, err := json.MarshalIndent(component, "", " ")
 	if err != nil {
@@ -627,6 +845,82 @@ func TestDatasetRequestsRename(t *testing.T) {
 	}
 }
 
+func TestDatasetRequestsRenameLinked(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	inst := NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+
+	tmpDir, err := ioutil.TempDir("", "TestDatasetRequestsRenameLinked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dir := filepath.Join(tmpDir, "movies")
+	var out string
+	if err := NewFSIMethods(inst).Checkout(&CheckoutParams{Dir: dir, Ref: "me/movies"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	req := NewDatasetRequestsInstance(inst)
+	p := &RenameParams{
+		Current: repo.DatasetRef{Peername: "me", Name: "movies"},
+		New:     repo.DatasetRef{Peername: "me", Name: "cities"},
+	}
+	if err := req.Rename(p, &repo.DatasetRef{}); err == nil {
+		t.Error("expected renaming to an existing name to fail")
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, fsi.QriRefFilename)); string(data) != "peer/movies" {
+		t.Errorf("expected failed rename to leave link untouched. got: %q", string(data))
+	}
+
+	linkFile := filepath.Join(dir, fsi.QriRefFilename)
+	if err := os.Rename(linkFile, linkFile+".bak"); err != nil {
+		t.Fatal(err)
+	}
+	p.New = repo.DatasetRef{Peername: "me", Name: "linked_movies"}
+	if err := req.Rename(p, &repo.DatasetRef{}); err == nil {
+		t.Error("expected renaming a dataset with a broken link to fail")
+	}
+	if p.Current.Path != "" || p.New.Path != "" {
+		t.Errorf("expected failed rename to leave params untouched. got: %v, %v", p.Current, p.New)
+	}
+	if _, err := mr.GetRef(repo.DatasetRef{Peername: "peer", Name: "movies"}); err != nil {
+		t.Errorf("expected failed rename to keep the dataset name. got: %s", err)
+	}
+	if err := os.Rename(linkFile+".bak", linkFile); err != nil {
+		t.Fatal(err)
+	}
+
+	p.New = repo.DatasetRef{Peername: "me", Name: "linked_movies"}
+	res := &repo.DatasetRef{}
+	if err := req.Rename(p, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Path == "" {
+		t.Error("expected renamed ref to include a path")
+	}
+	if res.FSIPath != dir {
+		t.Errorf("expected renamed ref to keep fsi link. expected: %q, got: %q", dir, res.FSIPath)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, fsi.QriRefFilename)); string(data) != "peer/linked_movies" {
+		t.Errorf("expected link file to be updated. got: %q", string(data))
+	}
+	stored, err := mr.GetRef(repo.DatasetRef{Peername: "peer", Name: "linked_movies"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.FSIPath != dir {
+		t.Errorf("expected stored ref to keep fsi link. got: %q", stored.FSIPath)
+	}
+}
+
 func TestDatasetRequestsRemove(t *testing.T) {
 	mr, err := testrepo.NewTestRepo()
 	if err != nil {
@@ -690,6 +984,16 @@ func TestDatasetRequestsRemove(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// link sitemap with a checkout
+	sitemapDir := filepath.Join(datasetsDir, "sitemap")
+	checkoutp = &CheckoutParams{
+		Dir: sitemapDir,
+		Ref: "me/sitemap",
+	}
+	if err := fsim.Checkout(checkoutp, &out); err != nil {
+		t.Fatal(err)
+	}
+
 	badCases := []struct {
 		err    string
 		params RemoveParams
@@ -700,6 +1004,9 @@ func TestDatasetRequestsRemove(t *testing.T) {
 		{"invalid number of revisions to delete: 0", RemoveParams{Ref: "peer/movies", Revision: rev.Rev{Field: "ds", Gen: 0}}},
 		{"cannot unlink, dataset is not linked to a directory", RemoveParams{Ref: "peer/movies", Revision: allRevs, Unlink: true}},
 		{"can't delete files, dataset is not linked to a directory", RemoveParams{Ref: "peer/movies", Revision: allRevs, DeleteFSIFiles: true}},
+		{"can't keep files, dataset is not linked to a directory", RemoveParams{Ref: "peer/movies", Revision: allRevs, KeepFiles: true}},
+		{"can't both keep & delete linked files", RemoveParams{Ref: "peer/sitemap", Revision: allRevs, KeepFiles: true, DeleteFSIFiles: true}},
+		{"keeping files removes the entire dataset, not individual versions", RemoveParams{Ref: "peer/sitemap", Revision: rev.Rev{Field: "ds", Gen: 1}, KeepFiles: true}},
 	}
 
 	for _, c := range badCases {
@@ -741,6 +1048,10 @@ func TestDatasetRequestsRemove(t *testing.T) {
 			RemoveParams{Ref: noHistoryName, Revision: rev.Rev{Field: "ds", Gen: 0}, DeleteFSIFiles: true},
 			RemoveResponse{NumDeleted: 0, Unlinked: true, DeletedFSIFiles: true},
 		},
+		{"all generations of peer/sitemap, remove link, keep files",
+			RemoveParams{Ref: "peer/sitemap", Revision: allRevs, KeepFiles: true},
+			RemoveResponse{NumDeleted: -1, Unlinked: true, KeptFiles: true},
+		},
 	}
 
 	for _, c := range goodCases {
@@ -761,8 +1072,19 @@ func TestDatasetRequestsRemove(t *testing.T) {
 			if c.res.DeletedFSIFiles != res.DeletedFSIFiles {
 				t.Errorf("res.DeletedFSIFiles mismatch. want %t, got %t", c.res.DeletedFSIFiles, res.DeletedFSIFiles)
 			}
+			if c.res.KeptFiles != res.KeptFiles {
+				t.Errorf("res.KeptFiles mismatch. want %t, got %t", c.res.KeptFiles, res.KeptFiles)
+			}
 		})
 	}
+
+	// kept files stay on disk without a link file
+	if _, err := os.Stat(filepath.Join(sitemapDir, "body.json")); err != nil {
+		t.Errorf("expected sitemap body file to be kept: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(sitemapDir, fsi.QriRefFilename)); !os.IsNotExist(err) {
+		t.Errorf("expected sitemap link file to be removed, got: %v", err)
+	}
 }
 
 func TestDatasetRequestsAdd(t *testing.T) {
@@ -772,6 +1094,9 @@ func TestDatasetRequestsAdd(t *testing.T) {
 		err string
 	}{
 		{&AddParams{Ref: "abc/hash###"}, nil, "node is not online and no registry is configured"},
+		{&AddParams{Ref: "abc/hash###", Lazy: true, LinkDir: "/path/to/dir"}, nil, "cannot link a dataset to a directory without fetching its body"},
+		{&AddParams{Ref: "abc/hash###", Lazy: true}, nil, "adding a dataset without its body requires a remote"},
+		{&AddParams{Ref: "abc/hash###", Lazy: true, Verify: true}, nil, "cannot verify a dataset without fetching its body"},
 	}
 
 	mr, err := testrepo.NewTestRepo()
@@ -788,6 +1113,9 @@ func TestDatasetRequestsAdd(t *testing.T) {
 		got := &repo.DatasetRef{}
 		err := req.Add(c.p, got)
 
+		if libErr, ok := err.(Error); ok {
+			err = fmt.Errorf("%s", libErr.Message())
+		}
 		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
 			t.Errorf("case %d error mismatch: expected: %s, got: %s", i, c.err, err)
 			continue
@@ -795,6 +1123,56 @@ func TestDatasetRequestsAdd(t *testing.T) {
 	}
 }
 
+func TestDatasetRequestsFetchBody(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewDatasetRequests(node, nil)
+
+	// fetching the body of a fully-stored dataset is a no-op
+	got := &repo.DatasetRef{}
+	if err := req.FetchBody(&FetchBodyParams{Ref: "peer/movies"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Path == "" || got.BodyMissing {
+		t.Errorf("expected resolved ref with body present. got: %#v", got)
+	}
+
+	ref, err := mr.GetRef(repo.DatasetRef{Peername: "peer", Name: "cities"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref.BodyMissing = true
+	if err := mr.PutRef(ref); err != nil {
+		t.Fatal(err)
+	}
+
+	// reading the missing body errors, other components can still be read
+	getRes := &GetResult{}
+	err = req.Get(&GetParams{Path: "peer/cities", Selector: "body"}, getRes)
+	if e, ok := err.(Error); !ok || e.err != ErrBodyMissing {
+		t.Errorf("expected getting a missing body to return ErrBodyMissing, got: %v", err)
+	}
+	if err := req.Get(&GetParams{Path: "peer/cities", Selector: "meta"}, getRes); err != nil {
+		t.Errorf("expected getting the meta of a dataset without a body to succeed, got: %s", err)
+	}
+
+	err = req.FetchBody(&FetchBodyParams{Ref: "peer/cities"}, got)
+	expect := "fetching a dataset body requires a remote"
+	if err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+
+	if err := req.FetchBody(&FetchBodyParams{Ref: "peer/not_a_dataset"}, got); err == nil {
+		t.Error("expected fetching the body of an unknown dataset to error")
+	}
+}
+
 func TestDatasetRequestsAddP2P(t *testing.T) {
 	// Matches what is used to generate the test peers.
 	datasets := []string{"movies", "cities", "counter", "craigslist", "sitemap"}
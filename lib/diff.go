@@ -11,6 +11,8 @@ import (
 
 	"github.com/qri-io/deepdiff"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/actions"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
 )
@@ -30,18 +32,87 @@ type DiffParams struct {
 	// Format       string
 	// FormatConfig dataset.FormatConfig
 
+	// Selector picks a component to diff on both sides, eg: "meta" or
+	// "structure.schema"
 	Selector string
+	// LeftSelector & RightSelector pick components to diff on each side,
+	// overriding Selector
+	LeftSelector, RightSelector string
 
 	Limit, Offset int
 	All           bool
+
+	// MemoryLimit is an approximate cap in bytes on the memory used to diff
+	// two bodies, including deepdiff's trees. Bodies larger than their share
+	// of the limit are spilled to temp files & diffed in aligned segments.
+	// 0 means no limit
+	MemoryLimit int64
+
+	// Summarize replaces deltas in the response with counts of changes to
+	// each dataset component
+	Summarize bool
 }
 
 // DiffResponse is the result of a call to diff
 type DiffResponse struct {
-	Stat *DiffStat   `json:"stat,omitempty"`
-	Diff []*Delta    `json:"diff,omitempty"`
-	A    interface{} `json:"b,omitempty"`
-	B    interface{} `json:"a,omitempty"`
+	Stat    *DiffStat               `json:"stat,omitempty"`
+	Diff    []*Delta                `json:"diff,omitempty"`
+	Summary map[string]*DiffSummary `json:"summary,omitempty"`
+	A       interface{}             `json:"b,omitempty"`
+	B       interface{}             `json:"a,omitempty"`
+}
+
+// DiffSummary counts the changes made to a dataset component
+type DiffSummary struct {
+	Inserts int `json:"inserts"`
+	Deletes int `json:"deletes"`
+	Updates int `json:"updates"`
+	Moves   int `json:"moves"`
+}
+
+// add sums the counts of another summary into s
+func (s *DiffSummary) add(b *DiffSummary) {
+	s.Inserts += b.Inserts
+	s.Deletes += b.Deletes
+	s.Updates += b.Updates
+	s.Moves += b.Moves
+}
+
+// summarizeDiff counts deltas by the component they change. When component
+// is empty deltas are from a whole-dataset diff & the component is the
+// first segment of each delta path
+func summarizeDiff(deltas []*Delta, component string) map[string]*DiffSummary {
+	summary := map[string]*DiffSummary{}
+	for _, d := range deltas {
+		comp := component
+		if comp == "" {
+			comp = strings.SplitN(strings.TrimPrefix(d.Path, "/"), "/", 2)[0]
+		}
+		s, ok := summary[comp]
+		if !ok {
+			s = &DiffSummary{}
+			summary[comp] = s
+		}
+		switch d.Type {
+		case deepdiff.DTInsert:
+			s.Inserts++
+		case deepdiff.DTDelete:
+			s.Deletes++
+		case deepdiff.DTUpdate:
+			s.Updates++
+		case deepdiff.DTMove:
+			s.Moves++
+		}
+	}
+	return summary
+}
+
+// summarize replaces response deltas & data with a summary
+func (res *DiffResponse) summarize(component string) {
+	res.Summary = summarizeDiff(res.Diff, component)
+	res.Diff = nil
+	res.A = nil
+	res.B = nil
 }
 
 // Diff computes the diff of two datasets
@@ -67,11 +138,31 @@ func (r *DatasetRequests) Diff(p *DiffParams, res *DiffResponse) (err error) {
 		return
 	}
 
+	leftSelector, rightSelector := p.Selector, p.Selector
+	if p.LeftSelector != "" {
+		leftSelector = p.LeftSelector
+	}
+	if p.RightSelector != "" {
+		rightSelector = p.RightSelector
+	}
+
+	if p.MemoryLimit > 0 && isBodyDiff(p.LeftPath, leftSelector) && isBodyDiff(p.RightPath, rightSelector) {
+		_res, err := r.diffBodiesLimited(ctx, p.LeftPath, p.RightPath, p.MemoryLimit)
+		if err != nil {
+			return err
+		}
+		if p.Summarize {
+			_res.summarize("body")
+		}
+		*res = *_res
+		return nil
+	}
+
 	var leftData, rightData interface{}
-	if leftData, err = r.loadDiffData(ctx, p.LeftPath, p.Selector); err != nil {
+	if leftData, err = r.loadDiffData(ctx, p.LeftPath, leftSelector); err != nil {
 		return
 	}
-	if rightData, err = r.loadDiffData(ctx, p.RightPath, p.Selector); err != nil {
+	if rightData, err = r.loadDiffData(ctx, p.RightPath, rightSelector); err != nil {
 		return
 	}
 
@@ -84,11 +175,74 @@ func (r *DatasetRequests) Diff(p *DiffParams, res *DiffResponse) (err error) {
 	if _res.Diff, err = deepdiff.Diff(leftData, rightData, deepdiff.OptionSetStats(_res.Stat)); err != nil {
 		return
 	}
+	if p.Summarize {
+		comp := diffComponent(leftSelector, rightSelector)
+		if comp == "" && !repo.IsRefString(p.LeftPath) && !repo.IsRefString(p.RightPath) {
+			// local files are bodies
+			comp = "body"
+		}
+		_res.summarize(comp)
+	}
 
 	*res = _res
 	return
 }
 
+// diffComponent names the component compared by a pair of selectors, empty
+// for whole-dataset diffs
+func diffComponent(leftSelector, rightSelector string) string {
+	if leftSelector == rightSelector {
+		return leftSelector
+	}
+	return leftSelector + ":" + rightSelector
+}
+
+// DAGDelta is an alias for base.DAGDelta, describing blocks present in one
+// dataset version but not another
+type DAGDelta = base.DAGDelta
+
+// DeltaSizeParams defines parameters for calculating the incremental size of
+// one dataset version over another
+type DeltaSizeParams struct {
+	LeftPath, RightPath string
+}
+
+// DeltaSize calculates the number & total size of blocks introduced by the
+// version at RightPath that are not present in the version at LeftPath. If
+// LeftPath is empty, the version prior to RightPath is used. DeltaSize requires
+// an IPFS-backed store
+func (r *DatasetRequests) DeltaSize(p *DeltaSizeParams, res *DAGDelta) (err error) {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.DeltaSize", p, res)
+	}
+
+	if err = completeDiffRefs(r.node, &p.LeftPath, &p.RightPath); err != nil {
+		return
+	}
+
+	paths := make([]string, 2)
+	for i, refstr := range []string{p.LeftPath, p.RightPath} {
+		ref, err := repo.ParseDatasetRef(refstr)
+		if err != nil {
+			return err
+		}
+		if err = repo.CanonicalizeDatasetRef(r.node.Repo, &ref); err != nil {
+			return err
+		}
+		if ref.Path == "" {
+			return fmt.Errorf("dataset '%s' has no versions", refstr)
+		}
+		paths[i] = ref.Path
+	}
+
+	delta, err := actions.DAGDelta(r.node, paths[0], paths[1])
+	if err != nil {
+		return err
+	}
+	*res = *delta
+	return nil
+}
+
 func completeDiffRefs(node *p2p.QriNode, left, right *string) (err error) {
 	// fail if neither argument is given
 	if *left == "" && *right == "" {
@@ -199,6 +353,16 @@ func (r *DatasetRequests) MergeDiffs(merged *DiffResponse, inputs []DiffResponse
 			inp.Diff[j].Path = comps[i] + "/" + d.Path
 		}
 		merged.Diff = append(merged.Diff, inp.Diff...)
+		if inp.Summary != nil {
+			if merged.Summary == nil {
+				merged.Summary = map[string]*DiffSummary{}
+			}
+			s := &DiffSummary{}
+			for _, cs := range inp.Summary {
+				s.add(cs)
+			}
+			merged.Summary[comps[i]] = s
+		}
 	}
 	return nil
 }
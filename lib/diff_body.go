@@ -0,0 +1,462 @@
+package lib
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/deepdiff"
+	"github.com/qri-io/qri/repo"
+)
+
+// isBodyDiff reports whether a diff side selects a dataset body. Paths that
+// aren't references are local body files
+func isBodyDiff(path, selector string) bool {
+	return !repo.IsRefString(path) || selector == "body"
+}
+
+// diffTreeOverhead approximates how many times more memory deepdiff uses
+// for the trees it builds than the encoded entries they're built from
+const diffTreeOverhead = 8
+
+// diffBodiesLimited diffs two array bodies, keeping entries & the trees
+// deepdiff builds from them under roughly limit bytes of memory. Each body is
+// buffered in memory up to its share of the limit & spilled to a temp file
+// beyond it. When neither body spills the result is the same as an unlimited
+// diff. Otherwise bodies are split into segments that are aligned by content
+// & diffed one pair at a time, which produces deltas in the same format but
+// can't detect moves between segments, and A & B are left empty
+func (r *DatasetRequests) diffBodiesLimited(ctx context.Context, leftPath, rightPath string, limit int64) (*DiffResponse, error) {
+	sideLimit := limit / (2 * diffTreeOverhead)
+
+	left, err := r.bufferDiffBody(ctx, leftPath, sideLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer left.Close()
+
+	right, err := r.bufferDiffBody(ctx, rightPath, sideLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer right.Close()
+
+	res := &DiffResponse{Stat: &deepdiff.Stats{}}
+	if !left.spilled() && !right.spilled() {
+		if res.A, err = left.values(); err != nil {
+			return nil, err
+		}
+		if res.B, err = right.values(); err != nil {
+			return nil, err
+		}
+		res.Diff, err = deepdiff.Diff(res.A, res.B, deepdiff.OptionSetStats(res.Stat))
+		return res, err
+	}
+
+	log.Debugf("diffing %s & %s in aligned segments of about %d bytes", leftPath, rightPath, sideLimit)
+	if res.Diff, err = diffSegments(left, right, sideLimit, res.Stat); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// entrySource returns JSON-encoded body entries in order, and io.EOF when
+// there are no more entries
+type entrySource func() ([]byte, error)
+
+// bufferDiffBody reads all entries of the body at path into a spill buffer
+func (r *DatasetRequests) bufferDiffBody(ctx context.Context, path string, limit int64) (*spillBuffer, error) {
+	next, closeBody, err := r.openDiffBody(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody()
+
+	buf := &spillBuffer{limit: limit}
+	for {
+		data, err := next()
+		if err == io.EOF {
+			return buf, nil
+		}
+		if err == nil {
+			err = buf.add(data)
+		}
+		if err != nil {
+			buf.Close()
+			return nil, err
+		}
+	}
+}
+
+// openDiffBody opens a streaming source of body entries for a dataset
+// reference or a local json or csv file
+func (r *DatasetRequests) openDiffBody(ctx context.Context, path string) (entrySource, func() error, error) {
+	if repo.IsRefString(path) {
+		ref, err := repo.ParseDatasetRef(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = repo.CanonicalizeDatasetRef(r.node.Repo, &ref); err != nil {
+			return nil, nil, err
+		}
+		ds, err := dsfs.LoadDataset(ctx, r.node.Repo.Store(), ref.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ds.Structure == nil {
+			return nil, nil, fmt.Errorf("dataset %s has no structure", ref.AliasString())
+		}
+		if err = ds.OpenBodyFile(ctx, r.node.Repo.Filesystem()); err != nil {
+			return nil, nil, err
+		}
+		file := ds.BodyFile()
+		rdr, err := dsio.NewEntryReader(ds.Structure, file)
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return dsioEntrySource(rdr), file.Close, nil
+	}
+
+	file, err := r.node.Repo.Filesystem().Get(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(file.FileName())); ext {
+	case ".json":
+		st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+		rdr, err := dsio.NewEntryReader(st, file)
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return dsioEntrySource(rdr), file.Close, nil
+	case ".csv":
+		rdr := csv.NewReader(file)
+		next := func() ([]byte, error) {
+			rec, err := rdr.Read()
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(rec)
+		}
+		return next, file.Close, nil
+	default:
+		file.Close()
+		return nil, nil, fmt.Errorf("unrecognized file extension: %s", ext)
+	}
+}
+
+// dsioEntrySource encodes entries read from an array body
+func dsioEntrySource(rdr dsio.EntryReader) entrySource {
+	return func() ([]byte, error) {
+		ent, err := rdr.ReadEntry()
+		if err != nil {
+			if err.Error() == "EOF" {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		if ent.Key != "" {
+			return nil, fmt.Errorf("diffing with a memory limit requires array bodies")
+		}
+		return json.Marshal(ent.Value)
+	}
+}
+
+// spillBuffer holds encoded body entries in memory, moving them to a temp
+// file once their total size exceeds limit
+type spillBuffer struct {
+	limit int64
+	// count & size of all entries added
+	count int
+	size  int64
+	mem   [][]byte
+	file  *os.File
+	w     *bufio.Writer
+}
+
+func (b *spillBuffer) add(data []byte) error {
+	b.count++
+	b.size += int64(len(data))
+	if b.file == nil {
+		b.mem = append(b.mem, data)
+		if b.size <= b.limit {
+			return nil
+		}
+		return b.spill()
+	}
+	return b.write(data)
+}
+
+// spill moves buffered entries to a temp file
+func (b *spillBuffer) spill() (err error) {
+	if b.file, err = ioutil.TempFile("", "qri_diff_"); err != nil {
+		return err
+	}
+	b.w = bufio.NewWriter(b.file)
+	for _, data := range b.mem {
+		if err = b.write(data); err != nil {
+			return err
+		}
+	}
+	b.mem = nil
+	return nil
+}
+
+// write adds an entry to the temp file. encoded JSON never contains a raw
+// newline, so entries are newline-delimited
+func (b *spillBuffer) write(data []byte) error {
+	if _, err := b.w.Write(data); err != nil {
+		return err
+	}
+	return b.w.WriteByte('\n')
+}
+
+func (b *spillBuffer) spilled() bool {
+	return b.file != nil
+}
+
+// values decodes all entries held in memory
+func (b *spillBuffer) values() ([]interface{}, error) {
+	vals := make([]interface{}, len(b.mem))
+	for i, data := range b.mem {
+		if err := json.Unmarshal(data, &vals[i]); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// entries reads back buffered entries from memory or the temp file
+func (b *spillBuffer) entries() (entrySource, error) {
+	if b.file == nil {
+		i := 0
+		return func() ([]byte, error) {
+			if i == len(b.mem) {
+				return nil, io.EOF
+			}
+			i++
+			return b.mem[i-1], nil
+		}, nil
+	}
+
+	if err := b.w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	rdr := bufio.NewReader(b.file)
+	return func() ([]byte, error) {
+		line, err := rdr.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		return line[:len(line)-1], nil
+	}, nil
+}
+
+// Close removes the temp file if the buffer spilled
+func (b *spillBuffer) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	b.file.Close()
+	return os.Remove(b.file.Name())
+}
+
+// diffSegments diffs two buffered bodies one pair of aligned segments at a
+// time. Segments end at boundary entries picked by hashing each entry, so an
+// insert or delete only changes the segment it's in. Boundaries are matched
+// in order, every pair of matched boundaries ends a pair of segments that's
+// diffed on its own. Delta paths are offset to the position of each segment
+// within the body & stats are summed across segments
+func diffSegments(left, right *spillBuffer, segmentSize int64, stat *deepdiff.Stats) ([]*Delta, error) {
+	// segment lengths vary, aim for segments well under the size limit
+	every := uint64(1)
+	if entries := left.count + right.count; entries > 0 {
+		if avg := (left.size + right.size) / int64(entries); avg > 0 && segmentSize/(4*avg) > 1 {
+			every = uint64(segmentSize / (4 * avg))
+		}
+	}
+
+	leftBounds, err := segmentBoundaries(left, every)
+	if err != nil {
+		return nil, err
+	}
+	rightBounds, err := segmentBoundaries(right, every)
+	if err != nil {
+		return nil, err
+	}
+	ends := alignBoundaries(leftBounds, rightBounds)
+	// the last segments run to the end of both bodies
+	ends = append(ends, [2]int{left.count - 1, right.count - 1})
+
+	leftEntries, err := left.entries()
+	if err != nil {
+		return nil, err
+	}
+	rightEntries, err := right.entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		deltas              []*Delta
+		leftNext, rightNext int
+		offset, segments    int
+	)
+	for _, end := range ends {
+		leftSeg, err := readSegment(leftEntries, end[0]+1-leftNext)
+		if err != nil {
+			return nil, err
+		}
+		rightSeg, err := readSegment(rightEntries, end[1]+1-rightNext)
+		if err != nil {
+			return nil, err
+		}
+		leftNext, rightNext = end[0]+1, end[1]+1
+		if len(leftSeg) == 0 && len(rightSeg) == 0 {
+			continue
+		}
+
+		ss := &deepdiff.Stats{}
+		d, err := deepdiff.Diff(leftSeg, rightSeg, deepdiff.OptionSetStats(ss))
+		if err != nil {
+			return nil, err
+		}
+		// deltas apply in order, by the time a segment's deltas apply all
+		// earlier segments match the right side
+		for _, delta := range d {
+			delta.Path = offsetDeltaPath(delta.Path, offset)
+			delta.SourcePath = offsetDeltaPath(delta.SourcePath, offset)
+		}
+		deltas = append(deltas, d...)
+
+		stat.Left += ss.Left
+		stat.Right += ss.Right
+		stat.LeftWeight += ss.LeftWeight
+		stat.RightWeight += ss.RightWeight
+		stat.Inserts += ss.Inserts
+		stat.Updates += ss.Updates
+		stat.Deletes += ss.Deletes
+		stat.Moves += ss.Moves
+
+		offset += len(rightSeg)
+		segments++
+	}
+
+	// each segment counts a root array, the whole body only has one
+	if segments > 1 {
+		stat.Left -= segments - 1
+		stat.Right -= segments - 1
+	}
+	return deltas, nil
+}
+
+// boundary is an entry that ends a segment
+type boundary struct {
+	hash  uint64
+	index int
+}
+
+// segmentBoundaries lists the entries of a body that end a segment, picking
+// on average one in every entries by hash
+func segmentBoundaries(b *spillBuffer, every uint64) ([]boundary, error) {
+	next, err := b.entries()
+	if err != nil {
+		return nil, err
+	}
+	var bounds []boundary
+	for i := 0; ; i++ {
+		data, err := next()
+		if err == io.EOF {
+			return bounds, nil
+		} else if err != nil {
+			return nil, err
+		}
+		h := fnv.New64a()
+		h.Write(data)
+		if sum := h.Sum64(); sum%every == 0 {
+			bounds = append(bounds, boundary{hash: sum, index: i})
+		}
+	}
+}
+
+// alignBoundaries matches boundaries of the left & right bodies in order,
+// returning the entry indexes of matched pairs. Each left boundary is matched
+// to the first unmatched right boundary with the same hash
+func alignBoundaries(left, right []boundary) [][2]int {
+	positions := map[uint64][]int{}
+	for i, b := range right {
+		positions[b.hash] = append(positions[b.hash], i)
+	}
+
+	var ends [][2]int
+	next := 0
+	for _, lb := range left {
+		ps := positions[lb.hash]
+		// positions are ascending, skip any before the last match
+		for len(ps) > 0 && ps[0] < next {
+			ps = ps[1:]
+		}
+		positions[lb.hash] = ps
+		if len(ps) == 0 {
+			continue
+		}
+		ends = append(ends, [2]int{lb.index, right[ps[0]].index})
+		next = ps[0] + 1
+	}
+	return ends
+}
+
+// readSegment decodes the next n entries from a source
+func readSegment(next entrySource, n int) ([]interface{}, error) {
+	seg := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		data, err := next()
+		if err != nil {
+			return nil, err
+		}
+		var v interface{}
+		if err = json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		seg = append(seg, v)
+	}
+	return seg, nil
+}
+
+// offsetDeltaPath adds offset to the leading index of a delta path, eg:
+// "/2/name" with an offset of 10 becomes "/12/name"
+func offsetDeltaPath(path string, offset int) string {
+	if offset == 0 || path == "" {
+		return path
+	}
+	prefix, seg, rest := "", path, ""
+	if strings.HasPrefix(seg, "/") {
+		prefix, seg = "/", seg[1:]
+	}
+	if i := strings.IndexByte(seg, '/'); i >= 0 {
+		seg, rest = seg[:i], seg[i:]
+	}
+	i, err := strconv.Atoi(seg)
+	if err != nil {
+		return path
+	}
+	return prefix + strconv.Itoa(i+offset) + rest
+}
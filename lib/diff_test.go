@@ -1,12 +1,19 @@
 package lib
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/qri-io/dataset/dsfs"
 	"github.com/qri-io/dataset/dstest"
+	"github.com/qri-io/deepdiff"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
@@ -118,4 +125,228 @@ func TestDatasetRequestsDiff(t *testing.T) {
 			t.Errorf("%d %s delta length mismatch. want: %d got: %d", i, c.description, c.DeltaLen, len(res.Diff))
 		}
 	}
+
+	// per-side selectors match a shared selector
+	shared := &DiffResponse{}
+	if err := req.Diff(&DiffParams{LeftPath: dsRef1.String(), RightPath: dsRef2.String(), Selector: "structure"}, shared); err != nil {
+		t.Fatal(err)
+	}
+	sides := &DiffResponse{}
+	if err := req.Diff(&DiffParams{LeftPath: dsRef1.String(), RightPath: dsRef2.String(), LeftSelector: "structure", RightSelector: "structure"}, sides); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(shared.Stat, sides.Stat) {
+		t.Errorf("expected per-side selectors to match shared selector.\nwant: %v\ngot: %v", shared.Stat, sides.Stat)
+	}
+
+	// each side can select a different component
+	res := &DiffResponse{}
+	if err := req.Diff(&DiffParams{LeftPath: dsRef1.String(), RightPath: dsRef2.String(), LeftSelector: "structure.format", RightSelector: "structure.schema"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.A != "csv" {
+		t.Errorf("expected left side to be structure.format. got: %v", res.A)
+	}
+	if len(res.Diff) == 0 {
+		t.Errorf("expected diff between different components")
+	}
+}
+
+func TestDatasetRequestsDiffMemoryLimit(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewDatasetRequests(node, nil)
+
+	left, right := "testdata/jobs_by_automation/body.csv", "testdata/jobs_by_automation_2/body.csv"
+	expect := &DiffResponse{}
+	if err := req.Diff(&DiffParams{LeftPath: left, RightPath: right}, expect); err != nil {
+		t.Fatal(err)
+	}
+	paths := func(res *DiffResponse) []string {
+		ps := make([]string, len(res.Diff))
+		for i, d := range res.Diff {
+			ps[i] = fmt.Sprintf("%s %s", d.Type, d.Path)
+		}
+		sort.Strings(ps)
+		return ps
+	}
+
+	// bodies under the limit diff in memory, matching an unlimited diff
+	res := &DiffResponse{}
+	if err := req.Diff(&DiffParams{LeftPath: left, RightPath: right, MemoryLimit: 1 << 20}, res); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expect.Stat, res.Stat) {
+		t.Errorf("diffStat mismatch.\nwant: %v\ngot: %v", expect.Stat, res.Stat)
+	}
+	if res.A == nil || res.B == nil {
+		t.Errorf("expected in-memory diff to return both sides")
+	}
+
+	// bodies over the limit spill to disk & diff in aligned segments
+	res = &DiffResponse{}
+	if err := req.Diff(&DiffParams{LeftPath: left, RightPath: right, MemoryLimit: 512}, res); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(paths(expect), paths(res)) {
+		t.Errorf("chunked delta mismatch.\nwant: %v\ngot: %v", paths(expect), paths(res))
+	}
+	if res.Stat.Updates != expect.Stat.Updates || res.Stat.Inserts != expect.Stat.Inserts || res.Stat.Deletes != expect.Stat.Deletes {
+		t.Errorf("chunked diffStat mismatch.\nwant: %v\ngot: %v", expect.Stat, res.Stat)
+	}
+	if res.A != nil || res.B != nil {
+		t.Errorf("expected chunked diff not to return bodies")
+	}
+
+	err = req.Diff(&DiffParams{LeftPath: "testdata/now_tf/input.dataset.json", RightPath: right, MemoryLimit: 512}, res)
+	if err == nil {
+		t.Errorf("expected memory-limited diff of an object body to error")
+	}
+}
+
+func TestDatasetRequestsDiffMemoryLimitAligned(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewDatasetRequests(node, nil)
+
+	tmpDir, err := ioutil.TempDir("", "TestDatasetRequestsDiffMemoryLimitAligned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// right inserts a row near the start & deletes one near the end, shifting
+	// every row in between
+	var leftRows, rightRows []interface{}
+	leftCSV, rightCSV := &bytes.Buffer{}, &bytes.Buffer{}
+	for i := 0; i < 200; i++ {
+		if i == 5 {
+			rightRows = append(rightRows, []interface{}{"inserted"})
+			rightCSV.WriteString("inserted\n")
+		}
+		row := fmt.Sprintf("row_%d", i)
+		leftRows = append(leftRows, []interface{}{row})
+		leftCSV.WriteString(row + "\n")
+		if i != 150 {
+			rightRows = append(rightRows, []interface{}{row})
+			rightCSV.WriteString(row + "\n")
+		}
+	}
+	left, right := filepath.Join(tmpDir, "left.csv"), filepath.Join(tmpDir, "right.csv")
+	if err := ioutil.WriteFile(left, leftCSV.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(right, rightCSV.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res := &DiffResponse{}
+	if err := req.Diff(&DiffParams{LeftPath: left, RightPath: right, MemoryLimit: 4096}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.A != nil || res.B != nil {
+		t.Fatalf("expected bodies to spill")
+	}
+	// stats count the row & its value
+	if len(res.Diff) != 2 || res.Stat.Inserts != 2 || res.Stat.Deletes != 2 || res.Stat.Updates != 0 {
+		t.Errorf("expected only the inserted & deleted rows to change. got: %v %d deltas", res.Stat, len(res.Diff))
+	}
+
+	patched := interface{}(leftRows)
+	if err := deepdiff.Patch(&patched, res.Diff); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(patched, interface{}(rightRows)) {
+		t.Errorf("expected patching left with the diff to produce right")
+	}
+}
+
+func TestSummarizeDiff(t *testing.T) {
+	deltas := []*Delta{
+		{Type: deepdiff.DTUpdate, Path: "/meta/title"},
+		{Type: deepdiff.DTInsert, Path: "/meta/keywords/0"},
+		{Type: deepdiff.DTDelete, Path: "/body/3"},
+		{Type: deepdiff.DTMove, Path: "/body/1"},
+	}
+	got := summarizeDiff(deltas, "")
+	expect := map[string]*DiffSummary{
+		"meta": {Inserts: 1, Updates: 1},
+		"body": {Deletes: 1, Moves: 1},
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("summary mismatch.\nwant: %v\ngot: %v", expect, got)
+	}
+
+	got = summarizeDiff(deltas, "structure")
+	if len(got) != 1 || *got["structure"] != (DiffSummary{Inserts: 1, Updates: 1, Deletes: 1, Moves: 1}) {
+		t.Errorf("expected all deltas to count toward the selected component. got: %v", got)
+	}
+}
+
+func TestOffsetDeltaPath(t *testing.T) {
+	cases := []struct {
+		path   string
+		offset int
+		expect string
+	}{
+		{"/2/name", 10, "/12/name"},
+		{"/3", 4, "/7"},
+		{"/3", 0, "/3"},
+		{"/name/0", 4, "/name/0"},
+		{"", 4, ""},
+	}
+	for _, c := range cases {
+		if got := offsetDeltaPath(c.path, c.offset); got != c.expect {
+			t.Errorf("offsetDeltaPath(%q, %d) mismatch. expected: %q, got: %q", c.path, c.offset, c.expect, got)
+		}
+	}
+}
+
+func TestDatasetRequestsDeltaSize(t *testing.T) {
+	mr, refs, err := testrepo.NewTestRepoWithHistory()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewDatasetRequests(node, nil)
+
+	badCases := []struct {
+		description string
+		Left, Right string
+		err         string
+	}{
+		{"no references", "", "", repo.ErrEmptyRef.Error()},
+		{"non-ipfs store", refs[1].String(), refs[0].String(), "computing delta size requires an IPFS-backed store: not using IPFS"},
+	}
+
+	for i, c := range badCases {
+		p := &DeltaSizeParams{
+			LeftPath:  c.Left,
+			RightPath: c.Right,
+		}
+		res := &DAGDelta{}
+		err := req.DeltaSize(p, res)
+		if err == nil {
+			t.Errorf("%d. %s expected error, got nil", i, c.description)
+			continue
+		}
+		if c.err != err.Error() {
+			t.Errorf("%d. %s error mismatch. want: %s got: %s", i, c.description, c.err, err.Error())
+		}
+	}
 }
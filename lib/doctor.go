@@ -0,0 +1,326 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/p2p"
+)
+
+const (
+	// CheckPass indicates a diagnostic check found no problems
+	CheckPass = "pass"
+	// CheckWarn indicates a problem that some qri features will hit, but
+	// doesn't stop qri from working
+	CheckWarn = "warn"
+	// CheckFail indicates a critical problem, qri won't work until it's fixed
+	CheckFail = "fail"
+)
+
+// DiagnosticCheck is the result of checking one part of a qri setup
+type DiagnosticCheck struct {
+	Name    string
+	Status  string
+	Message string
+	// Hint describes how to fix a problem, empty when a check passes
+	Hint string
+}
+
+// Failed returns true if a check found a critical problem
+func (c DiagnosticCheck) Failed() bool {
+	return c.Status == CheckFail
+}
+
+// doctorTimeout is the amount of time checks that contact other services
+// wait for a response
+var doctorTimeout = time.Second * 5
+
+// DoctorMethods diagnoses problems with a qri setup
+type DoctorMethods struct {
+	inst *Instance
+}
+
+// NewDoctorMethods creates a diagnostics handle from an instance
+func NewDoctorMethods(inst *Instance) *DoctorMethods {
+	return &DoctorMethods{inst: inst}
+}
+
+// CoreRequestsName implements the Requests interface
+func (m DoctorMethods) CoreRequestsName() string { return "doctor" }
+
+// CheckConfig checks the configuration is valid, and fields don't conflict
+func (m *DoctorMethods) CheckConfig(in *bool, res *DiagnosticCheck) error {
+	if m.inst.rpc != nil {
+		return m.inst.rpc.Call("DoctorMethods.CheckConfig", in, res)
+	}
+
+	*res = DiagnosticCheck{Name: "config", Status: CheckPass, Message: "config is valid"}
+	cfg := m.inst.cfg
+	if cfg == nil {
+		res.Status = CheckFail
+		res.Message = "no config found"
+		res.Hint = "run `qri setup` to create a config"
+		return nil
+	}
+
+	var problems []string
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	for _, err := range cfg.CrossFieldErrors() {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		res.Status = CheckFail
+		res.Message = strings.Join(problems, "\n")
+		res.Hint = "fix config fields with `qri config set`"
+	}
+	return nil
+}
+
+// CheckStore checks the configured store can be reached. stores are
+// connected to when an instance is created, only stores with deferred
+// initialization are retried
+func (m *DoctorMethods) CheckStore(in *bool, res *DiagnosticCheck) error {
+	if m.inst.rpc != nil {
+		return m.inst.rpc.Call("DoctorMethods.CheckStore", in, res)
+	}
+
+	storeType := "unknown"
+	if m.inst.cfg != nil && m.inst.cfg.Store != nil {
+		storeType = m.inst.cfg.Store.Type
+	}
+	*res = DiagnosticCheck{Name: "store", Status: CheckPass, Message: fmt.Sprintf("%s store is reachable", storeType)}
+
+	if m.inst.store == nil {
+		res.Status = CheckFail
+		res.Message = "no store configured"
+		res.Hint = "run `qri setup` to create a store"
+		return nil
+	}
+
+	if ls, ok := m.inst.store.(*lazyStore); ok {
+		if _, err := ls.init(); err != nil {
+			res.Status = CheckFail
+			res.Message = err.Error()
+			res.Hint = "make sure the IPFS daemon at store.options.url is running"
+			return nil
+		}
+	}
+
+	if err := base.StoreWritable(m.inst.store); err != nil {
+		res.Status = CheckWarn
+		res.Message = fmt.Sprintf("%s store is read-only, datasets can't be saved", storeType)
+		res.Hint = "run `qri config set store.options.readonly false` to allow saving"
+	}
+	return nil
+}
+
+// CheckRepo checks every dataset reference in the repo points to a version
+// that's in the store
+func (m *DoctorMethods) CheckRepo(in *bool, res *DiagnosticCheck) error {
+	if m.inst.rpc != nil {
+		return m.inst.rpc.Call("DoctorMethods.CheckRepo", in, res)
+	}
+
+	*res = DiagnosticCheck{Name: "repo", Status: CheckPass, Message: "all datasets are in the store"}
+	if m.inst.repo == nil {
+		res.Status = CheckFail
+		res.Message = "no repo found"
+		res.Hint = "run `qri setup` to create a repo"
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.inst.ctx, doctorTimeout)
+	defer cancel()
+	missing, err := base.MissingRefs(ctx, m.inst.repo)
+	if err != nil {
+		res.Status = CheckWarn
+		res.Message = fmt.Sprintf("couldn't check datasets against the store: %s", err)
+		return nil
+	}
+	if len(missing) > 0 {
+		names := make([]string, len(missing))
+		for i, ref := range missing {
+			names[i] = ref.AliasString()
+		}
+		res.Status = CheckWarn
+		res.Message = fmt.Sprintf("%d dataset(s) missing from the store: %s", len(missing), strings.Join(names, ", "))
+		res.Hint = "re-fetch datasets with `qri add`, or drop them with `qri remove`"
+	}
+	return nil
+}
+
+// CheckRegistry checks the configured registry can be reached
+func (m *DoctorMethods) CheckRegistry(in *bool, res *DiagnosticCheck) error {
+	if m.inst.rpc != nil {
+		return m.inst.rpc.Call("DoctorMethods.CheckRegistry", in, res)
+	}
+
+	cfg := m.inst.cfg
+	if cfg == nil || cfg.Registry == nil || cfg.Registry.Location == "" {
+		*res = DiagnosticCheck{
+			Name:    "registry",
+			Status:  CheckWarn,
+			Message: "no registry configured, publishing & search are disabled",
+			Hint:    "run `qri config set registry.location https://registry.qri.cloud`",
+		}
+		return nil
+	}
+
+	location := cfg.Registry.Location
+	*res = DiagnosticCheck{Name: "registry", Status: CheckPass, Message: fmt.Sprintf("registry %s is reachable", location)}
+	cli := &http.Client{Timeout: doctorTimeout}
+	resp, err := cli.Get(location)
+	if err != nil {
+		res.Status = CheckWarn
+		res.Message = fmt.Sprintf("registry %s is unreachable: %s", location, err)
+		res.Hint = "check your internet connection & registry.location config value"
+		return nil
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// CheckBootstrap checks p2p bootstrap peers can be reached. bootstrap
+// addresses are only dialed, no p2p connection is made
+func (m *DoctorMethods) CheckBootstrap(in *bool, res *DiagnosticCheck) error {
+	if m.inst.rpc != nil {
+		return m.inst.rpc.Call("DoctorMethods.CheckBootstrap", in, res)
+	}
+
+	cfg := m.inst.cfg
+	if cfg == nil || cfg.P2P == nil || !cfg.P2P.Enabled {
+		*res = DiagnosticCheck{Name: "bootstrap", Status: CheckPass, Message: "p2p is disabled"}
+		return nil
+	}
+
+	addrs := append(append([]string{}, cfg.P2P.QriBootstrapAddrs...), cfg.P2P.BootstrapAddrs...)
+	if len(addrs) == 0 {
+		*res = DiagnosticCheck{
+			Name:    "bootstrap",
+			Status:  CheckWarn,
+			Message: "no bootstrap addresses configured, this node won't find other peers",
+			Hint:    "add addresses to p2p.qribootstrapaddrs",
+		}
+		return nil
+	}
+
+	maddrs, err := p2p.ParseMultiaddrs(addrs)
+	if err != nil {
+		*res = DiagnosticCheck{
+			Name:    "bootstrap",
+			Status:  CheckWarn,
+			Message: fmt.Sprintf("invalid bootstrap address: %s", err),
+			Hint:    "fix addresses in p2p.qribootstrapaddrs & p2p.bootstrapaddrs",
+		}
+		return nil
+	}
+
+	reachable := 0
+	wg := sync.WaitGroup{}
+	lk := sync.Mutex{}
+	for _, maddr := range maddrs {
+		wg.Add(1)
+		go func(maddr ma.Multiaddr) {
+			defer wg.Done()
+			if dialable(maddr) {
+				lk.Lock()
+				reachable++
+				lk.Unlock()
+			}
+		}(maddr)
+	}
+	wg.Wait()
+
+	*res = DiagnosticCheck{
+		Name:    "bootstrap",
+		Status:  CheckPass,
+		Message: fmt.Sprintf("%d of %d bootstrap peers are reachable", reachable, len(maddrs)),
+	}
+	if reachable == 0 {
+		res.Status = CheckWarn
+		res.Hint = "check your internet connection, a firewall may be blocking outbound connections"
+	}
+	return nil
+}
+
+// dialable checks if a TCP connection can be opened to a multiaddr
+func dialable(maddr ma.Multiaddr) bool {
+	var host string
+	for _, p := range maddr.Protocols() {
+		switch p.Name {
+		case "ip4", "ip6", "dns4", "dns6":
+			host, _ = maddr.ValueForProtocol(p.Code)
+		}
+	}
+	port, err := maddr.ValueForProtocol(ma.P_TCP)
+	if host == "" || err != nil {
+		return false
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), doctorTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// CheckPorts checks the ports enabled services listen on are available.
+// CheckPorts always runs in the calling process, when connected to a running
+// qri node ports are expected to be in use
+func (m *DoctorMethods) CheckPorts(in *bool, res *DiagnosticCheck) error {
+	if m.inst.rpc != nil {
+		*res = DiagnosticCheck{Name: "ports", Status: CheckPass, Message: "ports are in use by a running qri node"}
+		return nil
+	}
+
+	*res = DiagnosticCheck{Name: "ports", Status: CheckPass, Message: "ports are available"}
+	cfg := m.inst.cfg
+	if cfg == nil {
+		return nil
+	}
+
+	type service struct {
+		field   string
+		enabled bool
+		port    int
+	}
+	var services []service
+	if cfg.API != nil {
+		services = append(services, service{"api.port", cfg.API.Enabled, cfg.API.Port})
+	}
+	if cfg.RPC != nil {
+		services = append(services, service{"rpc.port", cfg.RPC.Enabled, cfg.RPC.Port})
+	}
+	if cfg.Webapp != nil {
+		services = append(services, service{"webapp.port", cfg.Webapp.Enabled, cfg.Webapp.Port})
+	}
+
+	var inUse []string
+	for _, s := range services {
+		if !s.enabled {
+			continue
+		}
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+		if err != nil {
+			inUse = append(inUse, fmt.Sprintf("%s %d", s.field, s.port))
+			continue
+		}
+		l.Close()
+	}
+	if len(inUse) > 0 {
+		res.Status = CheckWarn
+		res.Message = fmt.Sprintf("ports in use by another program: %s", strings.Join(inUse, ", "))
+		res.Hint = "stop the other program, or pick a different port with `qri config set`"
+	}
+	return nil
+}
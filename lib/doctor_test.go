@@ -0,0 +1,116 @@
+package lib
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestDoctorMethodsChecks(t *testing.T) {
+	node := newTestQriNode(t)
+	ref := addCitiesDataset(t, node)
+	cfg := config.DefaultConfigForTesting()
+	inst := NewInstanceFromConfigAndNode(cfg, node)
+	m := NewDoctorMethods(inst)
+
+	regServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer regServer.Close()
+	cfg.Registry.Location = regServer.URL
+
+	// occupied by a peer that's reachable for bootstrapping
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+	cfg.P2P.Enabled = true
+	cfg.P2P.BootstrapAddrs = nil
+	cfg.P2P.QriBootstrapAddrs = []string{fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/ipfs/%s", port, cfg.P2P.PeerID)}
+	cfg.API.Enabled = false
+	cfg.RPC.Enabled = false
+	cfg.Webapp.Enabled = false
+
+	checks := []func(*bool, *DiagnosticCheck) error{
+		m.CheckConfig,
+		m.CheckStore,
+		m.CheckRepo,
+		m.CheckRegistry,
+		m.CheckBootstrap,
+		m.CheckPorts,
+	}
+	for _, check := range checks {
+		res := DiagnosticCheck{}
+		if err := check(nil, &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != CheckPass {
+			t.Errorf("expected %s check to pass. got: %s %q", res.Name, res.Status, res.Message)
+		}
+	}
+
+	// break things
+	cfg.RPC.Enabled = true
+	cfg.RPC.Port = port
+	cfg.Registry.Location = ""
+	l.Close()
+	inst.store = base.NewReadOnlyStore(inst.store)
+	missing := repo.DatasetRef{Peername: ref.Peername, ProfileID: ref.ProfileID, Name: "missing", Path: "/map/QmNotInTheStore"}
+	if err := node.Repo.PutRef(missing); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		check   func(*bool, *DiagnosticCheck) error
+		status  string
+		message string
+	}{
+		{m.CheckConfig, CheckPass, "config is valid"},
+		{m.CheckStore, CheckWarn, "map store is read-only, datasets can't be saved"},
+		{m.CheckRepo, CheckWarn, "1 dataset(s) missing from the store: peer/missing"},
+		{m.CheckRegistry, CheckWarn, "no registry configured, publishing & search are disabled"},
+		{m.CheckBootstrap, CheckWarn, "0 of 1 bootstrap peers are reachable"},
+	}
+	cfg.Store.Type = "map"
+	for i, c := range cases {
+		res := DiagnosticCheck{}
+		if err := c.check(nil, &res); err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+		if res.Status != c.status || res.Message != c.message {
+			t.Errorf("case %d: expected %s %q. got: %s %q", i, c.status, c.message, res.Status, res.Message)
+		}
+		if res.Status != CheckPass && res.Hint == "" {
+			t.Errorf("case %d: expected a remediation hint", i)
+		}
+	}
+
+	cfg.API.Enabled = true
+	cfg.API.Port = port
+	res := DiagnosticCheck{}
+	if err := m.CheckConfig(nil, &res); err != nil {
+		t.Fatal(err)
+	}
+	if !res.Failed() || !strings.Contains(res.Message, "is already used by api.port") {
+		t.Errorf("expected config check to fail on port conflict. got: %s %q", res.Status, res.Message)
+	}
+
+	occupied, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer occupied.Close()
+	if err := m.CheckPorts(nil, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != CheckWarn || !strings.Contains(res.Message, fmt.Sprintf("api.port %d", port)) {
+		t.Errorf("expected ports check to warn about api port. got: %s %q", res.Status, res.Message)
+	}
+}
@@ -0,0 +1,37 @@
+package lib
+
+import (
+	"context"
+
+	"github.com/qri-io/qri/base"
+)
+
+// DuplicateCluster is a group of datasets that share content
+type DuplicateCluster = base.DuplicateCluster
+
+// DuplicatesParams defines parameters for finding duplicate datasets
+type DuplicatesParams struct {
+	// By selects what counts as a duplicate, one of "body" or "dataset".
+	// defaults to "body"
+	By string
+}
+
+// Duplicates finds datasets in the repo that share the same content
+func (r *DatasetRequests) Duplicates(p *DuplicatesParams, res *[]DuplicateCluster) error {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.Duplicates", p, res)
+	}
+	ctx := context.TODO()
+
+	match, err := base.ParseDuplicateMatch(p.By)
+	if err != nil {
+		return NewError(ErrBadArgs, err.Error())
+	}
+
+	clusters, err := base.FindDuplicates(ctx, r.node.Repo, match)
+	if err != nil {
+		return err
+	}
+	*res = clusters
+	return nil
+}
@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestDatasetRequestsDuplicates(t *testing.T) {
+	node := newTestQriNode(t)
+	r := NewDatasetRequests(node, nil)
+
+	body := []byte(`[{"a":1},{"a":2}]`)
+	for _, name := range []string{"me/dupe_one", "me/dupe_two"} {
+		p := &SaveParams{
+			Ref: name,
+			Dataset: &dataset.Dataset{
+				Meta:      &dataset.Meta{Title: name},
+				Structure: &dataset.Structure{Format: "json"},
+				BodyPath:  "body.json",
+				BodyBytes: body,
+			},
+		}
+		if err := r.Save(p, &repo.DatasetRef{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res := []DuplicateCluster{}
+	if err := r.Duplicates(&DuplicatesParams{}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || len(res[0].Refs) != 2 {
+		t.Fatalf("expected one cluster of two datasets, got: %v", res)
+	}
+
+	// metas differ, so the datasets aren't duplicates as a whole
+	if err := r.Duplicates(&DuplicatesParams{By: "dataset"}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Errorf("expected no dataset clusters, got: %v", res)
+	}
+
+	if err := r.Duplicates(&DuplicatesParams{By: "nope"}, &res); err == nil {
+		t.Error("expected invalid match to error")
+	}
+}
@@ -2,6 +2,7 @@ package lib
 
 import (
 	"archive/zip"
+	"compress/flate"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,14 +11,16 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
 	"github.com/qri-io/dataset/dsio"
-	"github.com/qri-io/dataset/dsutil"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
@@ -52,6 +55,27 @@ type ExportParams struct {
 	Output    string
 	Format    string
 	Zipped    bool
+	// Components limits a zip export to the named dataset components, one of
+	// [body, commit, meta, structure, transform, viz]. empty exports the full
+	// dataset
+	Components []string
+	// Header writes column titles from the dataset schema as the first row of
+	// csv exports. other formats & datasets with json bodies ignore Header
+	Header bool
+}
+
+// exportZipCompressionLevel is the deflate level used for every zip export
+// entry. Keeping it fixed is part of making zip exports reproducible
+const exportZipCompressionLevel = flate.DefaultCompression
+
+// exportComponents is the set of components that can be selected for export
+var exportComponents = map[string]bool{
+	"body":      true,
+	"commit":    true,
+	"meta":      true,
+	"structure": true,
+	"transform": true,
+	"viz":       true,
 }
 
 // Export exports a dataset in the specified format
@@ -96,6 +120,17 @@ func (r *ExportRequests) Export(p *ExportParams, fileWritten *string) (err error
 		}
 	}
 
+	if len(p.Components) > 0 {
+		if format != "zip" {
+			return fmt.Errorf("selecting components is only supported for zip exports")
+		}
+		for _, name := range p.Components {
+			if !exportComponents[name] {
+				return fmt.Errorf("unknown component: \"%s\"", name)
+			}
+		}
+	}
+
 	if p.Output == "" || isDirectory(p.Output) {
 		// If output is blank or a directory, derive filename from repo name and commit timestamp.
 		baseName, err := GenerateFilename(ds, format)
@@ -143,27 +178,41 @@ func (r *ExportRequests) Export(p *ExportParams, fileWritten *string) (err error
 		return fmt.Errorf("already exists: \"%s\"", *fileWritten)
 	}
 
-	// Create output writer.
-	var writer io.Writer
-	writer, err = os.Create(outputPath)
+	// Create output writer. Partially written exports are removed on failure
+	out, err := os.Create(outputPath)
 	if err != nil {
 		return err
 	}
-
-	// If outputting a wrapped zip file, create the zip wrapper.
-	if p.Zipped && format != "zip" {
-		zipWriter := zip.NewWriter(writer)
-
-		writer, err = zipWriter.Create(fmt.Sprintf("dataset.%s", format))
+	defer func() {
+		out.Close()
 		if err != nil {
-			return err
+			os.Remove(outputPath)
 		}
-
-		defer func() {
-			zipWriter.Close()
-		}()
+	}()
+
+	store := r.node.Repo.Store()
+	switch {
+	case format == "zip" && len(p.Components) > 0:
+		return writeDeterministicZip(componentZipEntries(ds, ref.String(), p.Components), out)
+	case format == "zip":
+		return writeDeterministicZip(datasetZipEntries(ctx, store, ds, ref.String()), out)
+	case p.Zipped:
+		// If outputting a wrapped zip file, the dataset file sits alongside any
+		// file attachments
+		entries := append(attachmentZipEntries(ctx, store, ds), zipEntry{
+			name: fmt.Sprintf("dataset.%s", format),
+			write: func(w io.Writer) error {
+				return writeExportFormat(ds, format, p.Header, w)
+			},
+		})
+		return writeDeterministicZip(entries, out)
+	default:
+		return writeExportFormat(ds, format, p.Header, out)
 	}
+}
 
+// writeExportFormat writes a dataset to writer in a non-zip export format
+func writeExportFormat(ds *dataset.Dataset, format string, header bool, writer io.Writer) error {
 	// Create entry reader.
 	reader, err := dsio.NewEntryReader(ds.Structure, ds.BodyFile())
 	if err != nil {
@@ -222,6 +271,24 @@ func (r *ExportRequests) Export(p *ExportParams, fileWritten *string) (err error
 		}
 		return nil
 
+	case "csv":
+		st := &dataset.Structure{
+			Format: "csv",
+			Schema: ds.Structure.Schema,
+		}
+		if header && csvHeader(ds.Structure) {
+			st.FormatConfig = map[string]interface{}{"headerRow": true}
+		}
+		w, err := dsio.NewEntryWriter(st, writer)
+		if err != nil {
+			return err
+		}
+
+		if err := dsio.Copy(reader, w); err != nil {
+			return err
+		}
+		return w.Close()
+
 	case "xlsx":
 		st := &dataset.Structure{
 			Format: "xlsx",
@@ -239,20 +306,230 @@ func (r *ExportRequests) Export(p *ExportParams, fileWritten *string) (err error
 		}
 		return w.Close()
 
-	case "zip":
+	case NDJSONFormat, "jsonl":
+		// ndjson exports are body-only, streaming one entry per line
+		return base.WriteNDJSON(reader, writer)
 
-		store := r.node.Repo.Store()
-		if err = dsutil.WriteZipArchive(ctx, store, ds, "json", ref.String(), writer); err != nil {
+	case ArrowFormat, FeatherFormat:
+		bodyEntries, err := base.ReadEntries(reader)
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(bodyEntries)
+		if err != nil {
 			return err
 		}
 
-		return nil
+		var data []byte
+		if format == ArrowFormat {
+			data, err = base.ConvertJSONBodyToArrow(ds.Structure, body)
+		} else {
+			data, err = base.ConvertJSONBodyToFeather(ds.Structure, body)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(data)
+		return err
 
 	default:
 		return fmt.Errorf("unknown file format \"%s\"", format)
 	}
 }
 
+// zipEntry is a single file in a zip export. write is called with the entry's
+// writer once the entry has been added to the archive
+type zipEntry struct {
+	name  string
+	write func(w io.Writer) error
+}
+
+// writeDeterministicZip writes entries to w as a zip archive, writing the
+// same bytes for the same entries every time. Entries are sorted by name,
+// modification times are left zeroed & contents are compressed at a fixed level
+func writeDeterministicZip(entries []zipEntry, w io.Writer) error {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	zw := zip.NewWriter(w)
+	zw.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(out, exportZipCompressionLevel)
+	})
+	for _, e := range entries {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if err := e.write(fw); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// datasetZipEntries lists the entries of a full dataset zip export: the
+// dataset as json, a reference to it, any transform & viz scripts and the body
+// in its stored format
+func datasetZipEntries(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset, ref string) []zipEntry {
+	entries := []zipEntry{
+		jsonZipEntry("dataset.json", ds),
+		{
+			name: "ref.txt",
+			write: func(w io.Writer) error {
+				_, err := io.WriteString(w, ref)
+				return err
+			},
+		},
+	}
+	if ds.Transform != nil && ds.Transform.ScriptPath != "" {
+		entries = append(entries, storeZipEntry(ctx, store, "transform.star", ds.Transform.ScriptPath))
+	}
+	if ds.Viz != nil {
+		if ds.Viz.ScriptPath != "" {
+			entries = append(entries, storeZipEntry(ctx, store, "viz.html", ds.Viz.ScriptPath))
+		}
+		if ds.Viz.RenderedPath != "" {
+			entries = append(entries, storeZipEntry(ctx, store, "index.html", ds.Viz.RenderedPath))
+		}
+	}
+	return append(entries, zipEntry{
+		name: fmt.Sprintf("body.%s", ds.Structure.Format),
+		write: func(w io.Writer) error {
+			f, err := dsfs.LoadBody(ctx, store, ds)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		},
+	})
+}
+
+// attachmentZipEntries lists the file attachments of a dataset as zip entries
+// in an "attachments" directory
+func attachmentZipEntries(ctx context.Context, store cafs.Filestore, ds *dataset.Dataset) []zipEntry {
+	names := base.AttachmentNames(ds)
+	entries := make([]zipEntry, 0, len(names))
+	for _, name := range names {
+		name := name
+		entries = append(entries, zipEntry{
+			name: path.Join("attachments", name),
+			write: func(w io.Writer) error {
+				f, err := base.OpenAttachment(ctx, store, ds, name)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				_, err = io.Copy(w, f)
+				return err
+			},
+		})
+	}
+	return entries
+}
+
+// componentManifest describes the contents of a component zip export
+type componentManifest struct {
+	Ref string `json:"ref"`
+	// Components maps component names to the zip entry that holds them
+	Components map[string]string `json:"components"`
+}
+
+// componentZipEntries lists the entries of a zip export holding only the named
+// components of a dataset, plus a manifest.json listing what was included. the
+// body is written in its stored format as body.[format], all other components
+// are written as [component].json. components the dataset doesn't have are
+// left out of the archive & the manifest
+func componentZipEntries(ds *dataset.Dataset, ref string, components []string) []zipEntry {
+	var entries []zipEntry
+	manifest := componentManifest{Ref: ref, Components: map[string]string{}}
+	for _, name := range components {
+		if _, ok := manifest.Components[name]; ok {
+			continue
+		}
+
+		if name == "body" {
+			if ds.Structure == nil || ds.BodyFile() == nil {
+				continue
+			}
+			entry := fmt.Sprintf("body.%s", ds.Structure.Format)
+			entries = append(entries, zipEntry{
+				name: entry,
+				write: func(w io.Writer) error {
+					_, err := io.Copy(w, ds.BodyFile())
+					return err
+				},
+			})
+			manifest.Components[name] = entry
+			continue
+		}
+
+		var component interface{}
+		switch name {
+		case "commit":
+			if ds.Commit != nil {
+				component = ds.Commit
+			}
+		case "meta":
+			if ds.Meta != nil {
+				component = ds.Meta
+			}
+		case "structure":
+			if ds.Structure != nil {
+				component = ds.Structure
+			}
+		case "transform":
+			if ds.Transform != nil {
+				component = ds.Transform
+			}
+		case "viz":
+			if ds.Viz != nil {
+				component = ds.Viz
+			}
+		}
+		if component == nil {
+			continue
+		}
+
+		entry := fmt.Sprintf("%s.json", name)
+		entries = append(entries, jsonZipEntry(entry, component))
+		manifest.Components[name] = entry
+	}
+
+	return append(entries, jsonZipEntry("manifest.json", manifest))
+}
+
+// jsonZipEntry is a zip entry holding v encoded as indented JSON
+func jsonZipEntry(name string, v interface{}) zipEntry {
+	return zipEntry{
+		name: name,
+		write: func(w io.Writer) error {
+			data, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		},
+	}
+}
+
+// storeZipEntry is a zip entry holding the contents of a file in store
+func storeZipEntry(ctx context.Context, store cafs.Filestore, name, filePath string) zipEntry {
+	return zipEntry{
+		name: name,
+		write: func(w io.Writer) error {
+			f, err := store.Get(ctx, filePath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		},
+	}
+}
+
 func isDirectory(path string) bool {
 	st, err := os.Stat(path)
 	if err != nil {
@@ -284,3 +561,16 @@ func GenerateFilename(ds *dataset.Dataset, format string) (string, error) {
 		ts.Hour(), ts.Minute(), ts.Second())
 	return fmt.Sprintf("%s-%s_-_%s.%s", ds.Peername, ds.Name, timeText, format), nil
 }
+
+// csvHeader reports whether a csv export of a dataset body can have a header
+// row, which needs array rows with column titles. json bodies never get one
+func csvHeader(st *dataset.Structure) bool {
+	if st.Format == "json" {
+		return false
+	}
+	items, _ := st.Schema["items"].(map[string]interface{})
+	if _, ok := items["items"].([]interface{}); !ok {
+		return false
+	}
+	return len(base.SchemaColumnNames(st)) > 0
+}
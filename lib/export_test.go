@@ -1,11 +1,15 @@
 package lib
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -77,6 +81,17 @@ func TestExport(t *testing.T) {
 
 		{"export zip", ExportParams{Ref: "peer/sitemap", Format: "zip", Zipped: true},
 			"peer-sitemap_-_0001-01-01-00-00-00.zip"},
+
+		{"export ndjson", ExportParams{Ref: "peer/movies", Format: "ndjson"},
+			"peer-movies_-_0001-01-01-00-00-00.ndjson"},
+
+		{"set output name, jsonl", ExportParams{Ref: "peer/movies", Output: "ds.jsonl"}, "ds.jsonl"},
+
+		{"components need zip", ExportParams{Ref: "peer/movies", Format: "json", Components: []string{"body"}},
+			"selecting components is only supported for zip exports"},
+
+		{"unknown component", ExportParams{Ref: "peer/movies", Format: "zip", Components: []string{"readme"}},
+			"unknown component: \"readme\""},
 	}
 
 	for _, c := range cases {
@@ -113,6 +128,281 @@ func TestExport(t *testing.T) {
 	}
 }
 
+func TestExportNDJSON(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewExportRequests(node, nil)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "export_ndjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var fileWritten string
+	p := &ExportParams{Ref: "peer/movies", Format: "ndjson", TargetDir: tmpDir}
+	if err := req.Export(p, &fileWritten); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(tmpDir, fileWritten))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := &GetResult{}
+	if err := NewDatasetRequests(node, nil).Get(&GetParams{Path: "peer/movies", Selector: "body", Format: "json", All: true}, res); err != nil {
+		t.Fatal(err)
+	}
+	rows := []json.RawMessage{}
+	if err := json.Unmarshal(res.Bytes, &rows); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != len(rows) {
+		t.Fatalf("expected one line per body entry. want: %d, got: %d", len(rows), len(lines))
+	}
+	for i, line := range lines {
+		if line != string(rows[i]) {
+			t.Errorf("line %d mismatch. want: %s, got: %s", i, rows[i], line)
+		}
+	}
+}
+
+func TestExportComponents(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewExportRequests(node, nil)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "export_components")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var fileWritten string
+	p := &ExportParams{
+		Ref:        "peer/movies",
+		TargetDir:  tmpDir,
+		Format:     "zip",
+		Components: []string{"meta", "body", "viz", "body"},
+	}
+	if err := req.Export(p, &fileWritten); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.OpenReader(filepath.Join(tmpDir, fileWritten))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	var names []string
+	manifest := componentManifest{}
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if f.Name != "manifest.json" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = json.NewDecoder(rc).Decode(&manifest)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the test dataset has no viz, so it's left out
+	expectNames := []string{"body.csv", "manifest.json", "meta.json"}
+	if !reflect.DeepEqual(expectNames, names) {
+		t.Errorf("zip entries mismatch. expected: %v, got: %v", expectNames, names)
+	}
+	expectComponents := map[string]string{"body": "body.csv", "meta": "meta.json"}
+	if !reflect.DeepEqual(expectComponents, manifest.Components) {
+		t.Errorf("manifest components mismatch. expected: %v, got: %v", expectComponents, manifest.Components)
+	}
+	if manifest.Ref == "" {
+		t.Error("expected manifest to include a ref")
+	}
+}
+
+func TestExportZipDeterministic(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewExportRequests(node, nil)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "export_deterministic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cases := []ExportParams{
+		{Ref: "peer/movies", Format: "zip"},
+		{Ref: "peer/movies", Format: "zip", Components: []string{"meta", "body"}},
+		{Ref: "peer/movies", Format: "json", Zipped: true},
+	}
+	for i, c := range cases {
+		var archives [][]byte
+		for j := 0; j < 2; j++ {
+			p := c
+			p.TargetDir = tmpDir
+			p.Output = fmt.Sprintf("case_%d_%d.%s", i, j, c.Format)
+			var fileWritten string
+			if err := req.Export(&p, &fileWritten); err != nil {
+				t.Fatalf("case %d: %s", i, err)
+			}
+			data, err := ioutil.ReadFile(filepath.Join(tmpDir, fileWritten))
+			if err != nil {
+				t.Fatal(err)
+			}
+			archives = append(archives, data)
+			// make sure time passes between exports
+			time.Sleep(time.Millisecond * 10)
+		}
+
+		if !bytes.Equal(archives[0], archives[1]) {
+			t.Errorf("case %d: exporting twice produced different archives", i)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(archives[0]), int64(len(archives[0])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		prev := ""
+		for _, f := range zr.File {
+			if f.Name < prev {
+				t.Errorf("case %d: expected entries sorted by name, %q comes after %q", i, f.Name, prev)
+			}
+			prev = f.Name
+			if f.ModifiedTime != 0 || f.ModifiedDate != 0 {
+				t.Errorf("case %d: expected entry %q to have a zero modification time", i, f.Name)
+			}
+		}
+	}
+}
+
+func TestExportRemovesFileOnError(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewExportRequests(node, nil)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "export_remove_on_error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	p := &ExportParams{Ref: "peer/movies", TargetDir: tmpDir, Output: "movies.nope", Format: "nope"}
+	var fileWritten string
+	if err := req.Export(p, &fileWritten); err == nil {
+		t.Fatal("expected exporting an unknown format to fail")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "movies.nope")); !os.IsNotExist(err) {
+		t.Errorf("expected failed export to be removed, stat error: %v", err)
+	}
+}
+
+func TestExportCSVHeader(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	req := NewExportRequests(node, nil)
+
+	tmpDir, err := ioutil.TempDir(os.TempDir(), "export_csv_header")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cases := []struct {
+		output string
+		header bool
+		expect string
+	}{
+		{"header.csv", true, "city,pop,avg_age,in_usa\ntoronto,40000000,55.5,false\n"},
+		{"no_header.csv", false, "toronto,40000000,55.5,false\n"},
+	}
+
+	for _, c := range cases {
+		var fileWritten string
+		p := &ExportParams{Ref: "peer/cities", TargetDir: tmpDir, Output: c.output, Header: c.header}
+		if err := req.Export(p, &fileWritten); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(tmpDir, fileWritten))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(data), c.expect) {
+			t.Errorf("export %s mismatch. expected to start with: %q, got: %q", c.output, c.expect, string(data))
+		}
+	}
+}
+
+func TestCSVHeader(t *testing.T) {
+	arrayRows := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "array",
+			"items": []interface{}{map[string]interface{}{"title": "city"}},
+		},
+	}
+	objectRows := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"city": map[string]interface{}{}},
+		},
+	}
+	cases := []struct {
+		st     *dataset.Structure
+		expect bool
+	}{
+		{&dataset.Structure{Format: "csv", Schema: arrayRows}, true},
+		{&dataset.Structure{Format: "json", Schema: arrayRows}, false},
+		{&dataset.Structure{Format: "cbor", Schema: objectRows}, false},
+		{&dataset.Structure{Format: "csv", Schema: dataset.BaseSchemaArray}, false},
+	}
+	for i, c := range cases {
+		if got := csvHeader(c.st); got != c.expect {
+			t.Errorf("case %d: expected %t, got: %t", i, c.expect, got)
+		}
+	}
+}
+
 func readDataset(path string, ds *dataset.Dataset) error {
 	file, err := os.Open(path)
 	if err != nil {
@@ -135,7 +425,7 @@ func readDataset(path string, ds *dataset.Dataset) error {
 		if err != nil {
 			return err
 		}
-	case ".xlsx":
+	case ".xlsx", ".ndjson", ".jsonl":
 		return fmt.Errorf("SKIP")
 	case ".zip":
 		// TODO: Instead, unzip the file, and inspect the dataset contents.
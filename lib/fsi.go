@@ -72,13 +72,62 @@ func (m *FSIMethods) UpdateLink(p *LinkParams, res *string) (err error) {
 	return err
 }
 
-// Unlink rmeoves a connection between a working drirectory and a dataset history
+// Unlink removes a connection between a working directory and a dataset
+// history, deleting the link file & clearing the dataset's FSI path. Files in
+// the directory & versions in the repo are left intact. If Dir is empty the
+// directory the dataset is linked to is unlinked, if Ref is empty the dataset
+// Dir is linked to is unlinked
 func (m *FSIMethods) Unlink(p *LinkParams, res *string) (err error) {
+	if p.Dir != "" {
+		if p.Dir, err = filepath.Abs(p.Dir); err != nil {
+			return err
+		}
+	}
+
 	if m.inst.rpc != nil {
 		return m.inst.rpc.Call("FSIMethods.Unlink", p, res)
 	}
 
-	return m.inst.fsi.Unlink(p.Dir, p.Ref)
+	if p.Ref == "" {
+		if p.Dir == "" {
+			return repo.ErrEmptyRef
+		}
+		linked, ok := fsi.GetLinkedFilesysRef(p.Dir)
+		if !ok {
+			return fmt.Errorf("%s: %s", fsi.ErrNoLink, p.Dir)
+		}
+		p.Ref = linked
+	}
+
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return err
+	}
+	if err = repo.CanonicalizeDatasetRef(m.inst.repo, &ref); err != nil && err != repo.ErrNoHistory {
+		return err
+	}
+
+	if p.Dir == "" {
+		if ref.FSIPath == "" {
+			return fmt.Errorf("%s: %s", fsi.ErrNoLink, ref.AliasString())
+		}
+		p.Dir = ref.FSIPath
+	} else if linked, ok := fsi.GetLinkedFilesysRef(p.Dir); ok {
+		// refuse to break the link of a directory linked to some other dataset
+		linkedRef, err := repo.ParseDatasetRef(linked)
+		if err == nil {
+			err = repo.CanonicalizeDatasetRef(m.inst.repo, &linkedRef)
+		}
+		if (err == nil || err == repo.ErrNoHistory) && linkedRef.AliasString() != ref.AliasString() {
+			return fmt.Errorf("%s is linked to %s, not %s", p.Dir, linkedRef.AliasString(), ref.AliasString())
+		}
+	}
+
+	if err = m.inst.fsi.Unlink(p.Dir, ref.AliasString()); err != nil {
+		return err
+	}
+	*res = ref.AliasString()
+	return nil
 }
 
 // StatusItem is an alias for an fsi.StatusItem
@@ -131,7 +180,14 @@ type CheckoutParams struct {
 	Ref string
 }
 
-// Checkout method writes a dataset to a directory as individual files.
+// MergeConflictError reports working directory files checkout left conflict
+// markers in
+type MergeConflictError = fsi.MergeConflictError
+
+// Checkout method writes a dataset to a directory as individual files. If the
+// directory is already linked to the dataset, the requested version is merged
+// with any local changes, writing conflict markers where edits overlap &
+// returning a MergeConflictError
 func (m *FSIMethods) Checkout(p *CheckoutParams, out *string) (err error) {
 	if m.inst.rpc != nil {
 		return m.inst.rpc.Call("FSIMethods.Checkout", p, out)
@@ -141,11 +197,6 @@ func (m *FSIMethods) Checkout(p *CheckoutParams, out *string) (err error) {
 	// TODO(dlong): Fail if Dir is "", should be required to specify a location. Should probably
 	// only allow absolute paths. Add tests.
 
-	// If directory exists, error.
-	if _, err = os.Stat(p.Dir); !os.IsNotExist(err) {
-		return fmt.Errorf("directory with name \"%s\" already exists", p.Dir)
-	}
-
 	// Handle the ref to checkout.
 	ref := &repo.DatasetRef{}
 	if p.Ref == "" {
@@ -159,15 +210,18 @@ func (m *FSIMethods) Checkout(p *CheckoutParams, out *string) (err error) {
 		return
 	}
 
+	// If directory exists, merge into it if it's linked to this dataset, otherwise error.
+	if _, err = os.Stat(p.Dir); !os.IsNotExist(err) {
+		if linked, ok := fsi.GetLinkedFilesysRef(p.Dir); ok && linked == ref.AliasString() {
+			return m.checkoutMerge(ctx, p.Dir, ref, out)
+		}
+		return fmt.Errorf("directory with name \"%s\" already exists", p.Dir)
+	}
+
 	// Load dataset that is being checked out.
-	ds, err := dsfs.LoadDataset(ctx, m.inst.repo.Store(), ref.Path)
+	ds, err := m.loadCheckoutDataset(ctx, ref)
 	if err != nil {
-		return fmt.Errorf("error loading dataset")
-	}
-	ds.Name = ref.Name
-	ds.Peername = ref.Peername
-	if err = base.OpenDataset(ctx, m.inst.repo.Filesystem(), ds); err != nil {
-		return
+		return err
 	}
 
 	// Create a directory.
@@ -181,8 +235,68 @@ func (m *FSIMethods) Checkout(p *CheckoutParams, out *string) (err error) {
 	}
 
 	// Write components of the dataset to the working directory.
-	err = fsi.WriteComponents(ds, p.Dir)
-	return err
+	if err = fsi.WriteComponents(ds, p.Dir); err != nil {
+		return err
+	}
+	return fsi.WriteVersionFile(p.Dir, ref.Path)
+}
+
+// checkoutMerge merges the version of a dataset at ref into a working
+// directory. The version the directory was last written from is the common
+// ancestor of local changes, falling back to the dataset head
+func (m *FSIMethods) checkoutMerge(ctx context.Context, dir string, ref *repo.DatasetRef, out *string) error {
+	basePath, ok := fsi.ReadVersionFile(dir)
+	if !ok {
+		head, err := m.inst.repo.GetRef(repo.DatasetRef{Peername: ref.Peername, Name: ref.Name})
+		if err != nil {
+			return err
+		}
+		basePath = head.Path
+	}
+	if basePath == ref.Path {
+		*out = fmt.Sprintf("%s is already checked out", ref.String())
+		return nil
+	}
+
+	var baseDs *dataset.Dataset
+	if basePath != "" {
+		var err error
+		if baseDs, err = m.loadCheckoutDataset(ctx, &repo.DatasetRef{Peername: ref.Peername, Name: ref.Name, Path: basePath}); err != nil {
+			return err
+		}
+	}
+	incoming, err := m.loadCheckoutDataset(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	res, err := fsi.Merge(dir, baseDs, incoming)
+	if err != nil {
+		return err
+	}
+	// local files now descend from the incoming version, conflicts or not
+	if err = fsi.WriteVersionFile(dir, ref.Path); err != nil {
+		return err
+	}
+	if len(res.Conflicts) > 0 {
+		return MergeConflictError{Files: res.Conflicts}
+	}
+	*out = fmt.Sprintf("merged %s, %d file(s) updated", ref.String(), len(res.Updated))
+	return nil
+}
+
+// loadCheckoutDataset loads & opens the dataset at a reference's path
+func (m *FSIMethods) loadCheckoutDataset(ctx context.Context, ref *repo.DatasetRef) (*dataset.Dataset, error) {
+	ds, err := dsfs.LoadDataset(ctx, m.inst.repo.Store(), ref.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading dataset")
+	}
+	ds.Name = ref.Name
+	ds.Peername = ref.Peername
+	if err = base.OpenDataset(ctx, m.inst.repo.Filesystem(), ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
 }
 
 // FSIWriteParams encapsultes arguments for writing to an FSI-linked directory
@@ -325,7 +439,7 @@ func (m *FSIMethods) Restore(p *RestoreParams, out *string) (err error) {
 			if err != nil {
 				return err
 			}
-			bufData, err := actions.GetBody(m.inst.node, ds, df, fcfg, -1, -1, true)
+			bufData, _, err := actions.GetBody(m.inst.node, ds, false, df, fcfg, nil, -1, -1, true)
 			if err != nil {
 				return err
 			}
@@ -409,7 +523,7 @@ func (m *FSIMethods) FSIDatasetBody(p *FSIBodyParams, res *[]byte) error {
 		return err
 	}
 
-	*res, err = fsi.GetBody(ref.FSIPath, df, p.FormatConfig, p.Offset, p.Limit, p.All)
+	*res, _, err = fsi.GetBody(ref.FSIPath, df, p.FormatConfig, nil, p.Offset, p.Limit, p.All)
 	return err
 }
 
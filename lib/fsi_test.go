@@ -12,6 +12,7 @@ import (
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/p2p"
+	"github.com/qri-io/qri/repo"
 	testrepo "github.com/qri-io/qri/repo/test"
 )
 
@@ -140,3 +141,87 @@ func TestFSIMethodsWrite(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckoutMerge(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	inst := NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	methods := NewFSIMethods(inst)
+	req := NewDatasetRequestsInstance(inst)
+
+	tmpDir, err := ioutil.TempDir("", "TestCheckoutMerge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeBody := func(path, body string) {
+		if err := ioutil.WriteFile(path, []byte(body), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bodyPath := filepath.Join(tmpDir, "body.csv")
+	writeBody(bodyPath, "a,1\nb,2\nc,3\n")
+	if err := req.Save(&SaveParams{Ref: "me/merge_test", BodyPath: bodyPath}, &repo.DatasetRef{}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(tmpDir, "merge_test")
+	var out string
+	if err := methods.Checkout(&CheckoutParams{Dir: dir, Ref: "me/merge_test"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// checking out the same version again is a no-op
+	if err := methods.Checkout(&CheckoutParams{Dir: dir, Ref: "me/merge_test"}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// edit the working directory, then save a new version without it
+	writeBody(filepath.Join(dir, "body.csv"), "a,1\nb,2\nc,30\n")
+	writeBody(bodyPath, "a,10\nb,2\nc,3\n")
+	if err := req.Save(&SaveParams{Ref: "me/merge_test", BodyPath: bodyPath}, &repo.DatasetRef{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := methods.Checkout(&CheckoutParams{Dir: dir, Ref: "me/merge_test"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "body.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "a,10\nb,2\nc,30\n"; string(data) != expect {
+		t.Errorf("merged body mismatch. expected:\n%s\ngot:\n%s", expect, data)
+	}
+
+	// conflicting edits to the same row
+	writeBody(filepath.Join(dir, "body.csv"), "a,100\nb,2\nc,30\n")
+	writeBody(bodyPath, "a,11\nb,2\nc,3\n")
+	if err := req.Save(&SaveParams{Ref: "me/merge_test", BodyPath: bodyPath}, &repo.DatasetRef{}); err != nil {
+		t.Fatal(err)
+	}
+	err = methods.Checkout(&CheckoutParams{Dir: dir, Ref: "me/merge_test"}, &out)
+	if _, ok := err.(MergeConflictError); !ok {
+		t.Fatalf("expected merge conflict error, got: %v", err)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(dir, "body.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "<<<<<<< local\na,100\n=======\na,11\n>>>>>>> incoming\nb,2\nc,30\n"; string(data) != expect {
+		t.Errorf("conflicted body mismatch. expected:\n%s\ngot:\n%s", expect, data)
+	}
+
+	// directories linked to other datasets can't be checked out over
+	if err := methods.Checkout(&CheckoutParams{Dir: dir, Ref: "me/cities"}, &out); err == nil {
+		t.Error("expected checkout over a directory linked to another dataset to error")
+	}
+}
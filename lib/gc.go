@@ -0,0 +1,106 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-ipfs/core/coreapi"
+	"github.com/ipfs/go-ipfs/core/corerepo"
+	gc "github.com/ipfs/go-ipfs/pin/gc"
+	"github.com/ipfs/interface-go-ipfs-core/options"
+	"github.com/ipfs/interface-go-ipfs-core/path"
+	ipfs "github.com/qri-io/qfs/cafs/ipfs"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+)
+
+// GCResult reports the outcome of garbage collection
+type GCResult struct {
+	// Reclaimed is the number of bytes removed from the store
+	Reclaimed int64 `json:"reclaimed"`
+}
+
+// GC removes blocks from the store that aren't part of the history of any
+// dataset in the repo. When the store's pin policy keeps only the latest
+// version, earlier versions are removed unless something else pins them. IPFS
+// stores keep referenced versions that are stored locally without pinning
+// them, other stores must implement base.GarbageCollector
+func (inst *Instance) GC(ctx context.Context) (*GCResult, error) {
+	if inst.rpc != nil {
+		return nil, fmt.Errorf("garbage collection can't run over RPC, stop the qri daemon & try again")
+	}
+	if inst.repo == nil {
+		return nil, fmt.Errorf("no repo configured")
+	}
+	latestOnly := inst.cfg != nil && inst.cfg.Store.PinPolicy() == config.PinPolicyLatest
+
+	store, ok := inst.repo.Store().(*ipfs.Filestore)
+	if !ok {
+		reclaimed, err := base.GC(ctx, inst.repo, latestOnly)
+		if err != nil {
+			return nil, err
+		}
+		return &GCResult{Reclaimed: reclaimed}, nil
+	}
+
+	// an offline API only reads local blocks, versions that aren't stored
+	// locally are skipped instead of fetched from the network
+	capi, err := coreapi.NewCoreAPI(store.Node(), options.Api.Offline(true))
+	if err != nil {
+		return nil, err
+	}
+	local := func(ctx context.Context, p string) (bool, error) {
+		_, err := capi.Block().Stat(ctx, path.New(p))
+		return err == nil, nil
+	}
+
+	roots, err := base.GCRoots(ctx, inst.repo, local, latestOnly)
+	if err != nil {
+		return nil, err
+	}
+	node := store.Node()
+	// collection keeps everything reachable from best-effort roots that's
+	// stored locally, without adding to the pinset
+	keep, err := corerepo.BestEffortRoots(node.FilesRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range roots {
+		id, err := rootCid(p)
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, id)
+	}
+
+	before, err := corerepo.RepoStat(ctx, node)
+	if err != nil {
+		return nil, fmt.Errorf("reading ipfs repo stats: %s", err)
+	}
+	removed := gc.GC(ctx, node.Blockstore, node.Repo.Datastore(), node.Pinning, keep)
+	if err := corerepo.CollectResult(ctx, removed, nil); err != nil {
+		return nil, fmt.Errorf("collecting ipfs garbage: %s", err)
+	}
+	after, err := corerepo.RepoStat(ctx, node)
+	if err != nil {
+		return nil, fmt.Errorf("reading ipfs repo stats: %s", err)
+	}
+
+	res := &GCResult{}
+	if before.RepoSize > after.RepoSize {
+		res.Reclaimed = int64(before.RepoSize - after.RepoSize)
+	}
+	return res, nil
+}
+
+// rootCid gives the content identifier of the root of an IPFS path
+func rootCid(p string) (cid.Cid, error) {
+	root := strings.SplitN(strings.TrimPrefix(p, "/ipfs/"), "/", 2)[0]
+	id, err := cid.Decode(root)
+	if err != nil {
+		return cid.Cid{}, fmt.Errorf("invalid ipfs path %q: %s", p, err)
+	}
+	return id, nil
+}
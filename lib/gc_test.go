@@ -0,0 +1,88 @@
+package lib
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestInstanceGC(t *testing.T) {
+	ctx := context.Background()
+
+	inst := &Instance{}
+	if _, err := inst.GC(ctx); err == nil {
+		t.Error("expected an instance without a repo to error")
+	}
+
+	node := newTestQriNode(t)
+	inst = NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	if _, err := inst.GC(ctx); err != base.ErrNoGC {
+		t.Errorf("expected map store to return ErrNoGC, got: %v", err)
+	}
+}
+
+func TestInstanceGCLatestPinPolicy(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "gc_latest_pin_policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store = &config.Store{Type: "badger", Options: map[string]interface{}{
+		"path":                      dir,
+		config.StoreOptionPinPolicy: config.PinPolicyLatest,
+	}}
+	cfg.Repo.Type = "mem"
+	inst, err := NewInstance(ctx, dir, OptConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inst.Teardown()
+
+	r := NewDatasetRequestsInstance(inst)
+	versions := []*repo.DatasetRef{}
+	for _, body := range []string{`[{"a":1}]`, `[{"a":2}]`} {
+		ref := &repo.DatasetRef{}
+		p := &SaveParams{
+			Ref: "me/gc_me",
+			Dataset: &dataset.Dataset{
+				Structure: &dataset.Structure{Format: "json"},
+				BodyPath:  "body.json",
+				BodyBytes: []byte(body),
+			},
+		}
+		if err := r.Save(p, ref); err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, ref)
+	}
+	first, second := versions[0], versions[1]
+
+	res, err := inst.GC(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Reclaimed == 0 {
+		t.Error("expected collection to reclaim the first version")
+	}
+
+	store := inst.Repo().Store()
+	if has, err := store.Has(ctx, first.Path); err != nil || has {
+		t.Errorf("expected first version to be collected. got has: %t, err: %v", has, err)
+	}
+	if _, err := store.Get(ctx, first.Dataset.BodyPath); err == nil {
+		t.Error("expected first version body to be collected")
+	}
+	if _, err := dsfs.LoadDataset(ctx, store, second.Path); err != nil {
+		t.Errorf("expected latest version to be kept. got: %s", err)
+	}
+}
@@ -0,0 +1,71 @@
+package lib
+
+import (
+	"context"
+
+	coreiface "github.com/ipfs/interface-go-ipfs-core"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// healthCheckKey is looked up to check a store responds. it doesn't need to
+// exist, only the store's answer matters
+const healthCheckKey = "/health-check"
+
+// Health reports whether an instance is ready to serve requests
+type Health struct {
+	// StoreReachable is true when the content-addressed store responds
+	StoreReachable bool `json:"storeReachable"`
+	// StoreError explains why the store couldn't be reached
+	StoreError string `json:"storeError,omitempty"`
+	// Online is true when the p2p node is connected to the network
+	Online bool `json:"online"`
+	// PeerCount is the number of peers the node is connected to
+	PeerCount int `json:"peerCount"`
+}
+
+// Ready reports whether the instance can serve requests. A node that's
+// offline can still serve local data, so only the store is required
+func (h *Health) Ready() bool {
+	return h.StoreReachable
+}
+
+// Health checks the store & network status of the instance, pinging the store
+// with a cheap request
+func (inst *Instance) Health(ctx context.Context) *Health {
+	h := &Health{}
+
+	if inst.store == nil {
+		h.StoreError = "no store configured"
+	} else if err := pingStore(ctx, inst.store); err != nil {
+		h.StoreError = err.Error()
+	} else {
+		h.StoreReachable = true
+	}
+
+	if inst.node != nil {
+		h.Online = inst.node.Online
+		h.PeerCount = len(inst.node.ConnectedPeers())
+	}
+	return h
+}
+
+// pingStore checks a store responds. IPFS stores are asked for their node's
+// key, other stores are asked if they have healthCheckKey. lazy stores are
+// initialized first, wrapped stores are pinged through
+func pingStore(ctx context.Context, store cafs.Filestore) error {
+	switch s := store.(type) {
+	case *lazyStore:
+		inner, err := s.init()
+		if err != nil {
+			return err
+		}
+		return pingStore(ctx, inner)
+	case interface{ Unwrap() cafs.Filestore }:
+		return pingStore(ctx, s.Unwrap())
+	case interface{ IPFSCoreAPI() coreiface.CoreAPI }:
+		_, err := s.IPFSCoreAPI().Key().Self(ctx)
+		return err
+	}
+	_, err := store.Has(ctx, healthCheckKey)
+	return err
+}
@@ -0,0 +1,46 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/qfs/cafs"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+)
+
+func TestInstanceHealth(t *testing.T) {
+	ctx := context.Background()
+
+	inst := &Instance{}
+	h := inst.Health(ctx)
+	if h.Ready() || h.StoreError == "" {
+		t.Errorf("expected an instance without a store not to be ready. got: %v", h)
+	}
+
+	node := newTestQriNode(t)
+	inst = NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	h = inst.Health(ctx)
+	if !h.Ready() {
+		t.Errorf("expected map store to be reachable. got: %v", h)
+	}
+	if h.Online || h.PeerCount != 0 {
+		t.Errorf("expected offline test node without peers. got: %v", h)
+	}
+}
+
+func TestInstanceHealthStores(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store = &config.Store{Type: "ipfs_http", Options: map[string]interface{}{"url": "http://127.0.0.1:1"}}
+	inst := &Instance{store: newLazyStore(ctx, cfg)}
+	if h := inst.Health(ctx); h.Ready() || h.StoreError == "" {
+		t.Errorf("expected unreachable ipfs_http store not to be ready. got: %v", h)
+	}
+
+	inst = &Instance{store: base.NewReadOnlyStore(cafs.NewMapstore())}
+	if h := inst.Health(ctx); !h.Ready() {
+		t.Errorf("expected read-only map store to be reachable. got: %v", h)
+	}
+}
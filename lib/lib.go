@@ -7,14 +7,17 @@ import (
 	"context"
 	"encoding/gob"
 	"fmt"
+	"io"
 	"net"
 	"net/rpc"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	golog "github.com/ipfs/go-log"
+	coreiface "github.com/ipfs/interface-go-ipfs-core"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qfs"
@@ -24,6 +27,7 @@ import (
 	"github.com/qri-io/qfs/httpfs"
 	"github.com/qri-io/qfs/localfs"
 	"github.com/qri-io/qfs/muxfs"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/config/migrate"
 	"github.com/qri-io/qri/fsi"
@@ -32,7 +36,9 @@ import (
 	regmock "github.com/qri-io/qri/registry/regserver/mock"
 	"github.com/qri-io/qri/remote"
 	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/repo/badgerstore"
 	fsrepo "github.com/qri-io/qri/repo/fs"
+	"github.com/qri-io/qri/repo/gcsstore"
 	"github.com/qri-io/qri/repo/profile"
 	"github.com/qri-io/qri/update"
 	"github.com/qri-io/qri/update/cron"
@@ -76,6 +82,8 @@ func Receivers(inst *Instance) []Methods {
 		NewRenderRequests(r, nil),
 		NewUpdateMethods(inst),
 		NewFSIMethods(inst),
+		NewDoctorMethods(inst),
+		NewSQLMethods(inst),
 	}
 }
 
@@ -106,6 +114,10 @@ type InstanceOptions struct {
 
 	// use OptRemoteOptions to set this
 	remoteOptsFunc func(*remote.Options)
+	// use OptLogLevel to set this
+	logLevels map[string]string
+	// use OptProfile to set this
+	profile *profile.Profile
 }
 
 // Option is a function that manipulates config details when fed to New(). Fields on
@@ -213,6 +225,15 @@ func OptQriNode(node *p2p.QriNode) Option {
 	}
 }
 
+// OptStore supplies a content-addressed filestore, overriding any configured
+// store
+func OptStore(store cafs.Filestore) Option {
+	return func(o *InstanceOptions) error {
+		o.store = store
+		return nil
+	}
+}
+
 // OptRegistryClient overrides any configured registry client
 func OptRegistryClient(cli *regclient.Client) Option {
 	return func(o *InstanceOptions) error {
@@ -221,6 +242,50 @@ func OptRegistryClient(cli *regclient.Client) Option {
 	}
 }
 
+// OptProfile sets the repo's active profile once the repo is constructed,
+// overriding the configured profile without changing config. The profile
+// must have a private key. It has no effect on instances that connect to
+// another qri process over RPC
+func OptProfile(pro *profile.Profile) Option {
+	return func(o *InstanceOptions) error {
+		if pro == nil {
+			return fmt.Errorf("profile is required")
+		}
+		if pro.PrivKey == nil {
+			return fmt.Errorf("profile %q has no private key", pro.Peername)
+		}
+		o.profile = pro
+		return nil
+	}
+}
+
+// logLevels lists the levels accepted by OptLogLevel, from most to least
+// severe
+var logLevels = []string{"critical", "error", "warning", "notice", "info", "debug"}
+
+// OptLogLevel sets the log level for a package's logger, eg: "qrip2p" or
+// "lib". Levels set with OptLogLevel take precedence over configured levels
+func OptLogLevel(pkg, level string) Option {
+	return func(o *InstanceOptions) error {
+		valid := false
+		for _, l := range logLevels {
+			if strings.ToLower(level) == l {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid log level %q, must be one of: %s", level, strings.Join(logLevels, ", "))
+		}
+
+		if o.logLevels == nil {
+			o.logLevels = map[string]string{}
+		}
+		o.logLevels[pkg] = level
+		return nil
+	}
+}
+
 // NewInstance creates a new Qri Instance, if no Option funcs are provided,
 // New uses a default set of Option funcs. Any Option functions passed to this
 // function must check whether their fields are nil or not.
@@ -280,9 +345,18 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 		for name, level := range cfg.Logging.Levels {
 			golog.SetLogLevel(name, level)
 		}
+		if err = setLogFormat(cfg.Logging.Format); err != nil {
+			return
+		}
+	}
+	for name, level := range o.logLevels {
+		if err = golog.SetLogLevel(name, level); err != nil {
+			err = fmt.Errorf("setting %s log level: %s", name, err)
+			return
+		}
 	}
 
-	if inst.cron, err = newCron(cfg, inst.repoPath); err != nil {
+	if inst.cron, err = newCron(cfg, inst.repoPath, newUpdateFactory(inst)); err != nil {
 		log.Error("initializing cron:", err.Error())
 		return nil, fmt.Errorf("newCron: %s", err)
 	}
@@ -302,7 +376,11 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 	if o.store != nil {
 		inst.store = o.store
 	} else if inst.store == nil {
-		if inst.store, err = newStore(ctx, cfg); err != nil {
+		if deferrableStoreType(cfg.Store.Type) {
+			// stores backed by an external process may be unavailable at startup,
+			// defer connecting so commands that don't need a store still work
+			inst.store = newLazyStore(ctx, cfg)
+		} else if inst.store, err = newStore(ctx, cfg); err != nil {
 			log.Error("intializing store:", err.Error())
 			return nil, fmt.Errorf("newStore: %s", err)
 		}
@@ -330,6 +408,12 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 		}
 	}
 
+	if o.profile != nil && inst.repo != nil {
+		if err = inst.repo.SetProfile(o.profile); err != nil {
+			return nil, fmt.Errorf("setting profile: %s", err)
+		}
+	}
+
 	if inst.repo != nil {
 		inst.fsi = fsi.NewFSI(inst.repo)
 
@@ -349,7 +433,7 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 		inst.node.LocalStreams = o.Streams
 
 		if _, e := inst.node.IPFSCoreAPI(); e == nil {
-			if inst.remoteClient, err = remote.NewClient(inst.node); err != nil {
+			if inst.remoteClient, err = newRemoteClient(inst.node, inst.cfg, inst.repoPath); err != nil {
 				log.Error("initializing remote client:", err.Error())
 				return
 			}
@@ -360,7 +444,11 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 				o.remoteOptsFunc = func(*remote.Options) {}
 			}
 
-			if inst.remote, err = remote.NewRemote(inst.node, cfg.Remote, o.remoteOptsFunc); err != nil {
+			pinPolicy := func(ro *remote.Options) {
+				ro.UnpinPrevious = cfg.Store.PinPolicy() == config.PinPolicyLatest
+			}
+
+			if inst.remote, err = remote.NewRemote(inst.node, cfg.Remote, pinPolicy, o.remoteOptsFunc); err != nil {
 				log.Error("intializing remote:", err.Error())
 				return
 			}
@@ -395,6 +483,18 @@ func loadIPFSPluginsOnce(path string) error {
 }
 
 func newStore(ctx context.Context, cfg *config.Config) (store cafs.Filestore, err error) {
+	if store, err = openStore(ctx, cfg); err != nil {
+		return nil, err
+	}
+	// stores that can open in read-only mode handle the option themselves
+	if _, ok := store.(base.ReadOnlyStore); !ok && cfg.Store.ReadOnly() {
+		store = base.NewReadOnlyStore(store)
+	}
+	return store, nil
+}
+
+// openStore creates the configured store type
+func openStore(ctx context.Context, cfg *config.Config) (store cafs.Filestore, err error) {
 	switch cfg.Store.Type {
 	case "ipfs":
 		path := cfg.Store.Path
@@ -429,18 +529,267 @@ func newStore(ctx context.Context, cfg *config.Config) (store cafs.Filestore, er
 		if !ok {
 			return nil, fmt.Errorf("ipfs_http 'url' option must be a string")
 		}
-		return ipfs_http.New(urlStr)
+		fs, err := ipfs_http.New(urlStr)
+		if err != nil {
+			return nil, err
+		}
+		if size := cfg.Store.CacheSize(); size > 0 {
+			return newCachedIPFSHTTPStore(fs, size)
+		}
+		return fs, nil
 	case "map":
 		return cafs.NewMapstore(), nil
+	case "badger":
+		path, _ := cfg.Store.Options["path"].(string)
+		if path == "" {
+			path = cfg.Store.Path
+		}
+		if path == "" {
+			return nil, fmt.Errorf("badger store requires 'path' option")
+		}
+		return badgerstore.NewFilestore(path, cfg.Store.ReadOnly())
+	case "gcs":
+		bucket, _ := cfg.Store.Options["bucket"].(string)
+		if bucket == "" {
+			return nil, fmt.Errorf("gcs store requires 'bucket' option")
+		}
+		prefix, _ := cfg.Store.Options["prefix"].(string)
+		return gcsstore.NewFilestore(ctx, bucket, prefix)
 	default:
 		return nil, fmt.Errorf("unknown store type: %s", cfg.Store.Type)
 	}
 }
 
+// cachedIPFSHTTPStore is an ipfs_http store that reads files through an
+// in-memory block cache, skipping the round-trip to the IPFS daemon for cached
+// blocks. All other store methods are passed through to the wrapped store
+type cachedIPFSHTTPStore struct {
+	*ipfs_http.Filestore
+	blocks *base.BlockCache
+}
+
+// compile-time assertions that cachedIPFSHTTPStore keeps the pinning &
+// fetching of the store it wraps
+var (
+	_ cafs.Pinner  = cachedIPFSHTTPStore{}
+	_ cafs.Fetcher = cachedIPFSHTTPStore{}
+)
+
+func newCachedIPFSHTTPStore(fs *ipfs_http.Filestore, size int64) (cafs.Filestore, error) {
+	blocks, err := base.NewBlockCache(fs.IPFSCoreAPI().Dag(), size)
+	if err != nil {
+		return nil, err
+	}
+	return cachedIPFSHTTPStore{Filestore: fs, blocks: blocks}, nil
+}
+
+// Get implements the cafs.Filestore interface
+func (s cachedIPFSHTTPStore) Get(ctx context.Context, key string) (qfs.File, error) {
+	f, err := s.blocks.File(ctx, key)
+	if f == nil && err == nil {
+		return s.Filestore.Get(ctx, key)
+	}
+	return f, err
+}
+
+// deferrableStoreType returns true for store types that depend on an external
+// process, and can have their initialization deferred until first use
+func deferrableStoreType(storeType string) bool {
+	return storeType == "ipfs_http"
+}
+
+// storeConnectTimeout is the amount of time a lazyStore waits for the process
+// backing its store to respond before considering it unreachable
+var storeConnectTimeout = time.Second * 2
+
+// lazyStore is a cafs.Filestore that defers connecting to the configured store
+// until a store method is called, retrying on each call until the store
+// responds. lazyStore lets an instance start when a store can't be reached,
+// returning a store-specific error only when a store operation is attempted
+type lazyStore struct {
+	ctx context.Context
+	cfg *config.Config
+
+	lk        sync.Mutex
+	store     cafs.Filestore
+	connected bool
+}
+
+// compile-time assertions that lazyStore is a cafs.Filestore, and forwards
+// pinning & fetching to the stores it wraps
+var (
+	_ cafs.Filestore = (*lazyStore)(nil)
+	_ cafs.Pinner    = (*lazyStore)(nil)
+	_ cafs.Fetcher   = (*lazyStore)(nil)
+)
+
+func newLazyStore(ctx context.Context, cfg *config.Config) *lazyStore {
+	return &lazyStore{ctx: ctx, cfg: cfg}
+}
+
+// init returns the underlying store, initializing it & confirming it responds
+// if necessary
+func (s *lazyStore) init() (cafs.Filestore, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+
+	store, err := s.construct()
+	if err != nil {
+		return nil, err
+	}
+	if s.connected {
+		return store, nil
+	}
+
+	// constructing a store backed by an external process doesn't contact it,
+	// ping the store before handing it back
+	ctx, cancel := context.WithTimeout(s.ctx, storeConnectTimeout)
+	defer cancel()
+	if err := pingStore(ctx, store); err != nil {
+		return nil, fmt.Errorf("%s store is unavailable: %s", s.cfg.Store.Type, err)
+	}
+	s.connected = true
+	return store, nil
+}
+
+// construct creates the underlying store without contacting it. callers must
+// hold the lock
+func (s *lazyStore) construct() (cafs.Filestore, error) {
+	if s.store != nil {
+		return s.store, nil
+	}
+	store, err := newStore(s.ctx, s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s store is unavailable: %s", s.cfg.Store.Type, err)
+	}
+	s.store = store
+	return store, nil
+}
+
+// Put implements the cafs.Filestore interface
+func (s *lazyStore) Put(ctx context.Context, file qfs.File, pin bool) (string, error) {
+	store, err := s.init()
+	if err != nil {
+		return "", err
+	}
+	return store.Put(ctx, file, pin)
+}
+
+// Get implements the cafs.Filestore interface
+func (s *lazyStore) Get(ctx context.Context, key string) (qfs.File, error) {
+	store, err := s.init()
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(ctx, key)
+}
+
+// Has implements the cafs.Filestore interface
+func (s *lazyStore) Has(ctx context.Context, key string) (bool, error) {
+	store, err := s.init()
+	if err != nil {
+		return false, err
+	}
+	return store.Has(ctx, key)
+}
+
+// Delete implements the cafs.Filestore interface
+func (s *lazyStore) Delete(ctx context.Context, key string) error {
+	store, err := s.init()
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, key)
+}
+
+// NewAdder implements the cafs.Filestore interface
+func (s *lazyStore) NewAdder(pin, wrap bool) (cafs.Adder, error) {
+	store, err := s.init()
+	if err != nil {
+		return nil, err
+	}
+	return store.NewAdder(pin, wrap)
+}
+
+// ReadOnly implements the base.ReadOnlyStore interface, reporting configured
+// write capability without initializing the store
+func (s *lazyStore) ReadOnly() bool {
+	return s.cfg.Store.ReadOnly()
+}
+
+// Pin implements the cafs.Pinner interface
+func (s *lazyStore) Pin(ctx context.Context, key string, recursive bool) error {
+	store, err := s.init()
+	if err != nil {
+		return err
+	}
+	if pinner, ok := store.(cafs.Pinner); ok {
+		return pinner.Pin(ctx, key, recursive)
+	}
+	return fmt.Errorf("store doesn't support pinning")
+}
+
+// Unpin implements the cafs.Pinner interface
+func (s *lazyStore) Unpin(ctx context.Context, key string, recursive bool) error {
+	store, err := s.init()
+	if err != nil {
+		return err
+	}
+	if pinner, ok := store.(cafs.Pinner); ok {
+		return pinner.Unpin(ctx, key, recursive)
+	}
+	return fmt.Errorf("store doesn't support pinning")
+}
+
+// Fetch implements the cafs.Fetcher interface
+func (s *lazyStore) Fetch(ctx context.Context, source cafs.Source, key string) (qfs.File, error) {
+	store, err := s.init()
+	if err != nil {
+		return nil, err
+	}
+	if fetcher, ok := store.(cafs.Fetcher); ok {
+		return fetcher.Fetch(ctx, source, key)
+	}
+	return nil, fmt.Errorf("store doesn't support fetching")
+}
+
+// IPFSCoreAPI exposes the IPFS core API of the underlying store, connecting
+// to the store if necessary. IPFSCoreAPI returns nil if the store can't be
+// reached or isn't backed by IPFS
+func (s *lazyStore) IPFSCoreAPI() coreiface.CoreAPI {
+	store, err := s.init()
+	if err != nil {
+		log.Debug(err.Error())
+		return nil
+	}
+	if apier, ok := store.(interface{ IPFSCoreAPI() coreiface.CoreAPI }); ok {
+		return apier.IPFSCoreAPI()
+	}
+	return nil
+}
+
+// PathPrefix implements the cafs.Filestore interface, returning the prefix
+// of the underlying store without connecting to it. a store that can't be
+// constructed has no prefix
+func (s *lazyStore) PathPrefix() string {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	store, err := s.construct()
+	if err != nil {
+		log.Debug(err.Error())
+		return ""
+	}
+	return store.PathPrefix()
+}
+
 func newRegClient(ctx context.Context, cfg *config.Config) (rc *regclient.Client) {
-	if cfg.Registry != nil {
-		switch cfg.Registry.Location {
-		case "mock":
+	if cfg.Registry == nil {
+		return nil
+	}
+
+	locations := cfg.Registry.Locations()
+	for _, loc := range locations {
+		if loc == "mock" {
 			cli, server := regmock.NewMockServerRegistry(regmock.NewMemRegistry())
 			log.Infof("mock registry serving at: '%s'", server.URL)
 			go func() {
@@ -448,16 +797,17 @@ func newRegClient(ctx context.Context, cfg *config.Config) (rc *regclient.Client
 				server.Close()
 			}()
 			return cli
-		case "":
-			return rc
-		default:
-			return regclient.NewClient(&regclient.Config{
-				Location: cfg.Registry.Location,
-			})
 		}
 	}
+	if len(locations) == 0 {
+		return rc
+	}
 
-	return nil
+	return regclient.NewClient(&regclient.Config{
+		Location:  locations[0],
+		Fallbacks: locations[1:],
+		Timeout:   cfg.Registry.Timeout(),
+	})
 }
 
 func newRepo(path string, cfg *config.Config, store cafs.Filestore) (r repo.Repo, err error) {
@@ -486,12 +836,31 @@ func newFilesystem(cfg *config.Config, store cafs.Filestore) (qfs.Filesystem, er
 	if ipfss, ok := store.(*ipfs.Filestore); ok {
 		mux["ipfs"] = ipfss
 	}
+	if gcss, ok := store.(*gcsstore.Filestore); ok {
+		mux["gcs"] = gcss
+	}
 
 	fsys := muxfs.NewMux(mux)
 	return fsys, nil
 }
 
-func newCron(cfg *config.Config, repoPath string) (cron.Scheduler, error) {
+// newRemoteClient creates a remote client that records the progress of pushes
+// within the repo directory & sends configured credentials to remotes
+func newRemoteClient(node *p2p.QriNode, cfg *config.Config, repoPath string) (*remote.Client, error) {
+	return remote.NewClient(node, func(o *remote.ClientOptions) {
+		if repoPath != "" {
+			o.PushStateDir = filepath.Join(repoPath, "pushes")
+		}
+		if cfg != nil {
+			o.Headers = remote.Headers(cfg)
+			if cfg.Remote != nil && cfg.Remote.PullConcurrency > 0 {
+				o.PullConcurrency = cfg.Remote.PullConcurrency
+			}
+		}
+	})
+}
+
+func newCron(cfg *config.Config, repoPath string, factory cron.RunJobFactory) (cron.Scheduler, error) {
 	updateCfg := cfg.Update
 	if updateCfg == nil {
 		updateCfg = config.DefaultUpdate()
@@ -519,7 +888,13 @@ func newCron(cfg *config.Config, repoPath string) (cron.Scheduler, error) {
 		return nil, fmt.Errorf("unknown cron type: %s", updateCfg.Type)
 	}
 
-	svc := cron.NewCron(jobStore, logStore, update.Factory)
+	retention, err := update.LogRetention(updateCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := cron.NewCron(jobStore, logStore, factory)
+	svc.SetLogRetention(retention)
 	return svc, nil
 }
 
@@ -563,12 +938,16 @@ type Instance struct {
 	store   cafs.Filestore
 	node    *p2p.QriNode
 
-	qfs          qfs.Filesystem
-	cron         cron.Scheduler
-	fsi          *fsi.FSI
-	remote       *remote.Remote
-	remoteClient *remote.Client
-	registry     *regclient.Client
+	qfs      qfs.Filesystem
+	cron     cron.Scheduler
+	fsi      *fsi.FSI
+	remote   *remote.Remote
+	registry *regclient.Client
+
+	// remoteClient is replaced when the node reconnects, guarded by
+	// remoteClientLk
+	remoteClientLk sync.Mutex
+	remoteClient   *remote.Client
 
 	rpc *rpc.Client
 }
@@ -585,11 +964,14 @@ func (inst *Instance) Connect(ctx context.Context) (err error) {
 	// old instance, we run into issues where the online instance can't "see"
 	// the additions. We fix that by re-initializing the client with the new
 	// instance
-	if inst.remoteClient, err = remote.NewClient(inst.node); err != nil {
+	if err = inst.resetRemoteClient(); err != nil {
 		log.Debugf("initializing remote client: %s", err.Error())
 		return
 	}
 
+	// watch for dropped connections, reconnecting with backoff
+	inst.superviseConnection(ctx)
+
 	// chriswhong/usgs_earthquakes
 	return nil
 }
@@ -615,6 +997,9 @@ func (inst *Instance) ChangeConfig(cfg *config.Config) (err error) {
 	}
 
 	inst.cfg = cfg
+	if inst.node != nil && cfg.P2P != nil {
+		inst.node.UpdatePeerGate(cfg.P2P)
+	}
 	return nil
 }
 
@@ -668,10 +1053,18 @@ func (inst *Instance) RemoteClient() *remote.Client {
 	if inst == nil {
 		return nil
 	}
+	inst.remoteClientLk.Lock()
+	defer inst.remoteClientLk.Unlock()
 	return inst.remoteClient
 }
 
 // Teardown destroys the instance, releasing reserved resources
 func (inst *Instance) Teardown() {
 	inst.teardown()
+	// stores backed by a local database hold a lock until closed
+	if closer, ok := inst.store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Debugf("closing store: %s", err)
+		}
+	}
 }
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	crypto "github.com/libp2p/go-libp2p-crypto"
@@ -15,6 +19,7 @@ import (
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qfs/cafs"
 	"github.com/qri-io/qri/actions"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/config"
 	libtest "github.com/qri-io/qri/lib/test"
 	"github.com/qri-io/qri/p2p"
@@ -80,6 +85,177 @@ func TestNewInstance(t *testing.T) {
 	}
 }
 
+func TestOptLogLevel(t *testing.T) {
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store.Type = "map"
+	cfg.Repo.Type = "mem"
+
+	if _, err := NewInstance(context.Background(), os.TempDir(), OptConfig(cfg), OptLogLevel("lib", "DEBUG")); err != nil {
+		t.Error(err)
+	}
+
+	expect := `invalid log level "loud", must be one of: critical, error, warning, notice, info, debug`
+	if _, err := NewInstance(context.Background(), os.TempDir(), OptConfig(cfg), OptLogLevel("lib", "loud")); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
+
+func TestOptProfile(t *testing.T) {
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store.Type = "map"
+	cfg.Repo.Type = "mem"
+
+	pro := &profile.Profile{
+		ID:       testPeerProfile.ID,
+		Peername: "tenant",
+		PrivKey:  testPeerProfile.PrivKey,
+	}
+	inst, err := NewInstance(context.Background(), os.TempDir(), OptConfig(cfg), OptProfile(pro))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := inst.Repo().Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Peername != "tenant" {
+		t.Errorf("active profile mismatch. expected: %q, got: %q", "tenant", got.Peername)
+	}
+	if cfg.Profile.Peername == "tenant" {
+		t.Error("expected OptProfile not to modify config")
+	}
+
+	expect := `profile "nokey" has no private key`
+	if _, err := NewInstance(context.Background(), os.TempDir(), OptConfig(cfg), OptProfile(&profile.Profile{Peername: "nokey"})); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+	if _, err := NewInstance(context.Background(), os.TempDir(), OptConfig(cfg), OptProfile(nil)); err == nil {
+		t.Error("expected a nil profile to error")
+	}
+}
+
+func TestOptStore(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store.Type = "map"
+	cfg.Repo.Type = "mem"
+
+	store := cafs.NewMapstore()
+	key, err := store.Put(ctx, qfs.NewMemfileBytes("hello.txt", []byte("hello")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst, err := NewInstance(ctx, os.TempDir(), OptConfig(cfg), OptStore(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.store != store {
+		t.Error("expected instance to use the supplied store")
+	}
+	if has, err := inst.Repo().Store().Has(ctx, key); err != nil || !has {
+		t.Errorf("expected repo store to contain pre-populated key. got: %t, %v", has, err)
+	}
+}
+
+func TestNewInstanceUnreachableStore(t *testing.T) {
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store.Type = "ipfs_http"
+	// nothing should be listening on this port
+	cfg.Store.Options = map[string]interface{}{"url": "http://127.0.0.1:1"}
+	cfg.Repo.Type = "mem"
+
+	ctx := context.Background()
+	inst, err := NewInstance(ctx, os.TempDir(), OptConfig(cfg))
+	if err != nil {
+		t.Fatalf("expected instance construction to tolerate an unreachable store. got: %s", err)
+	}
+	if _, ok := inst.store.(*lazyStore); !ok {
+		t.Fatalf("expected ipfs_http store to be deferred. got: %T", inst.store)
+	}
+
+	// operations that don't touch the store work
+	data := []byte{}
+	if err := NewConfigMethods(inst).GetConfig(&GetConfigParams{Field: "store.type", Format: "json", Concise: true}, &data); err != nil {
+		t.Fatalf("expected config get to work with an unreachable store. got: %s", err)
+	}
+	if string(data) != `"ipfs_http"` {
+		t.Errorf("config value mismatch. expected: %q, got: %q", `"ipfs_http"`, string(data))
+	}
+
+	// store operations return a store-specific error
+	_, err = inst.Repo().Store().Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("foo")), false)
+	if err == nil {
+		t.Fatal("expected store operation to error")
+	}
+	if !strings.HasPrefix(err.Error(), "ipfs_http store is unavailable") {
+		t.Errorf("expected a store-specific error. got: %s", err)
+	}
+}
+
+func TestNewInstanceIPFSHTTPStore(t *testing.T) {
+	// mock IPFS daemon that answers identity requests
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/id" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ID":"` + testPeerProfile.ID + `"}`))
+	}))
+	defer s.Close()
+
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store.Type = "ipfs_http"
+	cfg.Store.Options = map[string]interface{}{"url": s.URL}
+	cfg.Repo.Type = "mem"
+
+	ctx := context.Background()
+	inst, err := NewInstance(ctx, os.TempDir(), OptConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := inst.store.(*lazyStore); !ok {
+		t.Fatalf("expected ipfs_http store to be deferred. got: %T", inst.store)
+	}
+	if _, err := inst.Node().IPFSCoreAPI(); err != nil {
+		t.Errorf("expected a reachable ipfs_http store to provide the IPFS core API. got: %s", err)
+	}
+	if inst.RemoteClient() == nil {
+		t.Error("expected a remote client for a reachable ipfs_http store")
+	}
+}
+
+func TestLazyStore(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store = &config.Store{Type: "map"}
+	ls := newLazyStore(ctx, cfg)
+
+	if ls.PathPrefix() != "map" {
+		t.Errorf("expected path prefix of the underlying store. got: %q", ls.PathPrefix())
+	}
+	key, err := ls.Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("foo")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ls.Pin(ctx, key, true); err != nil {
+		t.Errorf("expected pin to be forwarded. got: %s", err)
+	}
+	// map stores without a network can't fetch
+	expect := "this store cannot fetch from remote sources"
+	if _, err := ls.Fetch(ctx, cafs.SourceAny, key); err == nil || err.Error() != expect {
+		t.Errorf("expected fetch to be forwarded. got: %v", err)
+	}
+
+	cfg = config.DefaultConfigForTesting()
+	cfg.Store = &config.Store{Type: "ipfs_http"}
+	expect = "ipfs_http store is unavailable: ipfs_http store requires 'url' option"
+	if err := newLazyStore(ctx, cfg).Pin(ctx, key, true); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
+
 func TestNewDefaultInstance(t *testing.T) {
 	prevIPFSEnvLocation := os.Getenv("IPFS_PATH")
 	prevDefaultIPFSLocation := defaultIPFSLocation
@@ -140,7 +316,7 @@ func TestReceivers(t *testing.T) {
 	inst := &Instance{node: node, cfg: cfg}
 
 	reqs := Receivers(inst)
-	expect := 11
+	expect := 13
 	if len(reqs) != expect {
 		t.Errorf("unexpected number of receivers returned. expected: %d. got: %d\nhave you added/removed a receiver?", expect, len(reqs))
 		return
@@ -186,3 +362,73 @@ func addNowTransformDataset(t *testing.T, node *p2p.QriNode) repo.DatasetRef {
 	}
 	return ref
 }
+
+func TestNewStoreReadOnly(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store = &config.Store{Type: "map"}
+	store, err := newStore(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := base.StoreWritable(store); err != nil {
+		t.Errorf("expected store to be writable. got: %s", err)
+	}
+
+	cfg.Store.Options = map[string]interface{}{config.StoreOptionReadOnly: true}
+	if store, err = newStore(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.StoreWritable(store); err != base.ErrReadOnlyStore {
+		t.Errorf("expected ErrReadOnlyStore. got: %v", err)
+	}
+	if err := base.StoreWritable(newLazyStore(ctx, cfg)); err != base.ErrReadOnlyStore {
+		t.Errorf("expected lazy store to report read-only without initializing. got: %v", err)
+	}
+}
+
+func TestNewStoreBadger(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "badger_store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := config.DefaultConfigForTesting()
+	cfg.Store = &config.Store{Type: "badger"}
+	expect := "badger store requires 'path' option"
+	if _, err := newStore(ctx, cfg); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+
+	cfg.Store.Options = map[string]interface{}{"path": dir}
+	store, err := newStore(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.(cafs.Pinner); !ok {
+		t.Error("expected badger store to be a cafs.Pinner")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "LOCK")); err != nil {
+		t.Errorf("expected open store to hold a lock file. got: %s", err)
+	}
+
+	inst := &Instance{teardown: func() {}, store: store}
+	inst.Teardown()
+	if _, err := os.Stat(filepath.Join(dir, "LOCK")); !os.IsNotExist(err) {
+		t.Errorf("expected teardown to remove lock file. got: %v", err)
+	}
+
+	cfg.Store.Options[config.StoreOptionReadOnly] = true
+	if store, err = newStore(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer store.(io.Closer).Close()
+	if err := base.StoreWritable(store); err != base.ErrReadOnlyStore {
+		t.Errorf("expected read-only badger store. got: %v", err)
+	}
+	if _, ok := store.(cafs.Pinner); !ok {
+		t.Error("expected read-only badger store to remain a cafs.Pinner")
+	}
+}
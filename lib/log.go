@@ -6,6 +6,7 @@ import (
 	"net/rpc"
 
 	"github.com/qri-io/qri/actions"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
 )
@@ -33,7 +34,8 @@ func NewLogRequests(node *p2p.QriNode, cli *rpc.Client) *LogRequests {
 	}
 }
 
-// LogParams defines parameters for the Log method
+// LogParams defines parameters for the Log method. ListParams NewerThan &
+// OlderThan limit history to versions committed in a range of time
 type LogParams struct {
 	ListParams
 	// Reference to data to fetch history for
@@ -45,17 +47,90 @@ func (r *LogRequests) Log(params *LogParams, res *[]repo.DatasetRef) (err error)
 	if r.cli != nil {
 		return r.cli.Call("LogRequests.Log", params, res)
 	}
+
+	if !params.NewerThan.IsZero() || !params.OlderThan.IsZero() {
+		page := &LogPage{}
+		if err = r.LogPage(params, page); err != nil {
+			return err
+		}
+		*res = page.Versions
+		return nil
+	}
+
 	ctx := context.TODO()
+	ref, err := r.logRef(params)
+	if err != nil {
+		return err
+	}
+
+	*res, err = actions.DatasetLog(ctx, r.node, ref, params.Limit, params.Offset)
+	return
+}
+
+// LogPage is a page of dataset history
+type LogPage struct {
+	Versions []repo.DatasetRef
+	// Total is the number of versions that match the request, across all pages
+	Total int
+}
+
+// LogPage returns a page of the history of changes for a given dataset, along
+// with the total number of versions. Unlike Log, LogPage reads the entire
+// history of a dataset
+func (r *LogRequests) LogPage(params *LogParams, res *LogPage) (err error) {
+	if r.cli != nil {
+		return r.cli.Call("LogRequests.LogPage", params, res)
+	}
+	ctx := context.TODO()
+
+	ref, err := r.logRef(params)
+	if err != nil {
+		return err
+	}
+
+	history, err := actions.DatasetHistory(ctx, r.node, ref)
+	if err != nil {
+		return err
+	}
+
+	var versions []repo.DatasetRef
+	for _, v := range history {
+		if v.Dataset != nil && v.Dataset.Commit != nil {
+			ts := v.Dataset.Commit.Timestamp
+			if !params.NewerThan.IsZero() && !ts.After(params.NewerThan) {
+				continue
+			}
+			if !params.OlderThan.IsZero() && !ts.Before(params.OlderThan) {
+				continue
+			}
+		}
+		versions = append(versions, v)
+	}
 
+	res.Total = len(versions)
+	if params.Offset > len(versions) {
+		params.Offset = len(versions)
+	}
+	versions = versions[params.Offset:]
+	if params.Limit < len(versions) {
+		versions = versions[:params.Limit]
+	}
+	res.Versions = versions
+	return nil
+}
+
+// logRef resolves the reference log params refer to, setting default paging
+// values
+func (r *LogRequests) logRef(params *LogParams) (ref repo.DatasetRef, err error) {
 	if params.Ref == "" {
-		return repo.ErrEmptyRef
+		return ref, repo.ErrEmptyRef
 	}
-	ref, err := repo.ParseDatasetRef(params.Ref)
+	ref, err = repo.ParseDatasetRef(params.Ref)
 	if err != nil {
-		return fmt.Errorf("'%s' is not a valid dataset reference", params.Ref)
+		return ref, fmt.Errorf("'%s' is not a valid dataset reference", params.Ref)
 	}
 	if err = repo.CanonicalizeDatasetRef(r.node.Repo, &ref); err != nil {
-		return
+		return ref, err
 	}
 
 	// ensure valid limit value
@@ -66,7 +141,121 @@ func (r *LogRequests) Log(params *LogParams, res *[]repo.DatasetRef) (err error)
 	if params.Offset < 0 {
 		params.Offset = 0
 	}
+	return ref, nil
+}
 
-	*res, err = actions.DatasetLog(ctx, r.node, ref, params.Limit, params.Offset)
-	return
+// SchemaVersion is a dataset version that changed the body schema
+type SchemaVersion = base.SchemaVersion
+
+// SchemaChange describes a change to a single column of a body schema
+type SchemaChange = base.SchemaChange
+
+const (
+	// SchemaColumnAdded indicates a column was added
+	SchemaColumnAdded = base.SchemaColumnAdded
+	// SchemaColumnRemoved indicates a column was removed
+	SchemaColumnRemoved = base.SchemaColumnRemoved
+	// SchemaColumnTypeChanged indicates a column's type changed
+	SchemaColumnTypeChanged = base.SchemaColumnTypeChanged
+)
+
+// SchemaHistory returns a timeline of changes to the columns of a dataset's
+// body schema, newest first. Only versions that added, removed, or changed the
+// type of a column are included
+func (r *LogRequests) SchemaHistory(params *LogParams, res *[]SchemaVersion) (err error) {
+	if r.cli != nil {
+		return r.cli.Call("LogRequests.SchemaHistory", params, res)
+	}
+	ctx := context.TODO()
+
+	if params.Ref == "" {
+		return repo.ErrEmptyRef
+	}
+	ref, err := repo.ParseDatasetRef(params.Ref)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid dataset reference", params.Ref)
+	}
+	if err = repo.CanonicalizeDatasetRef(r.node.Repo, &ref); err != nil {
+		return
+	}
+	if ref.Path == "" {
+		return repo.ErrNoHistory
+	}
+
+	history, err := base.SchemaHistory(ctx, r.node.Repo, ref)
+	if err != nil {
+		return err
+	}
+
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+	if params.Offset > len(history) {
+		params.Offset = len(history)
+	}
+	history = history[params.Offset:]
+	if params.Limit > 0 && params.Limit < len(history) {
+		history = history[:params.Limit]
+	}
+	*res = history
+	return nil
+}
+
+// ChangelogEntry is a single dataset version in a changelog
+type ChangelogEntry = base.ChangelogEntry
+
+// ChangelogParams defines parameters for the Changelog method
+type ChangelogParams struct {
+	// Reference to the dataset to build a changelog for
+	Ref string
+	// Format of the changelog text, currently only "md" (markdown)
+	Format string
+	// GroupBy is one of "date" or "version", defaults to "date"
+	GroupBy string
+	// From & To limit the changelog to a range of versions, inclusive. Either
+	// can be a version number starting at 1 for the first version, or a
+	// version path
+	From, To string
+}
+
+// Changelog renders the commit titles & messages of a dataset's history as
+// formatted text, newest first
+func (r *LogRequests) Changelog(params *ChangelogParams, res *string) (err error) {
+	if r.cli != nil {
+		return r.cli.Call("LogRequests.Changelog", params, res)
+	}
+	ctx := context.TODO()
+
+	if params.Ref == "" {
+		return repo.ErrEmptyRef
+	}
+	group := base.ChangelogGrouping(params.GroupBy)
+	if err := base.CheckChangelogFormat(group, params.Format); err != nil {
+		return NewError(ErrBadArgs, err.Error())
+	}
+	ref, err := repo.ParseDatasetRef(params.Ref)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid dataset reference", params.Ref)
+	}
+	if err = repo.CanonicalizeDatasetRef(r.node.Repo, &ref); err != nil {
+		return
+	}
+	if ref.Path == "" {
+		return repo.ErrNoHistory
+	}
+
+	entries, err := base.Changelog(ctx, r.node.Repo, ref)
+	if err != nil {
+		return err
+	}
+	if entries, err = base.ChangelogRange(entries, params.From, params.To); err != nil {
+		return NewError(ErrBadArgs, err.Error())
+	}
+
+	text, err := base.FormatChangelog(ref.AliasString(), entries, group, params.Format)
+	if err != nil {
+		return err
+	}
+	*res = text
+	return nil
 }
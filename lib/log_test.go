@@ -1,8 +1,15 @@
 package lib
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
@@ -66,3 +73,176 @@ func TestHistoryRequestsLog(t *testing.T) {
 		}
 	}
 }
+
+func TestHistoryRequestsLogPage(t *testing.T) {
+	mr, refs, err := testrepo.NewTestRepoWithHistory()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	firstRef := refs[0].String()
+	future := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		description string
+		p           *LogParams
+		versions    int
+		total       int
+	}{
+		{"log page - default",
+			&LogParams{Ref: firstRef}, 5, 5},
+		{"log page - offset 3 limit 3",
+			&LogParams{Ref: firstRef, ListParams: ListParams{Offset: 3, Limit: 3}}, 2, 5},
+		{"log page - offset past end",
+			&LogParams{Ref: firstRef, ListParams: ListParams{Offset: 10, Limit: 3}}, 0, 5},
+		{"log page - newer than future",
+			&LogParams{Ref: firstRef, ListParams: ListParams{NewerThan: future}}, 0, 0},
+		{"log page - older than future",
+			&LogParams{Ref: firstRef, ListParams: ListParams{OlderThan: future, Limit: 2}}, 2, 5},
+	}
+
+	req := NewLogRequests(node, nil)
+	for _, c := range cases {
+		got := &LogPage{}
+		if err := req.LogPage(c.p, got); err != nil {
+			t.Errorf("case '%s' unexpected error: %s", c.description, err)
+			continue
+		}
+		if len(got.Versions) != c.versions {
+			t.Errorf("case '%s' versions length mismatch. expected: %d, got: %d", c.description, c.versions, len(got.Versions))
+		}
+		if got.Total != c.total {
+			t.Errorf("case '%s' total mismatch. expected: %d, got: %d", c.description, c.total, got.Total)
+		}
+	}
+}
+
+func TestSchemaHistory(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	inst := NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	dsr := NewDatasetRequestsInstance(inst)
+
+	tmpDir, err := ioutil.TempDir("", "TestSchemaHistory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tabular := func(cols ...string) map[string]interface{} {
+		items := []interface{}{}
+		for i := 0; i < len(cols); i += 2 {
+			items = append(items, map[string]interface{}{"title": cols[i], "type": cols[i+1]})
+		}
+		return map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": items},
+		}
+	}
+	versions := []struct {
+		body   string
+		schema map[string]interface{}
+	}{
+		{"nyc,8000000\ntoronto,2700000\n", tabular("city", "string", "pop", "integer")},
+		{"nyc,8000001\ntoronto,2700001\n", tabular("city", "string", "pop", "integer")},
+		{"nyc,8000001,true\ntoronto,2700001,false\n", tabular("city", "string", "pop", "integer", "in_usa", "boolean")},
+	}
+	for i, v := range versions {
+		path := filepath.Join(tmpDir, fmt.Sprintf("body_%d.csv", i))
+		if err := ioutil.WriteFile(path, []byte(v.body), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		p := &SaveParams{
+			Ref:      "me/schema_history",
+			BodyPath: path,
+			Dataset: &dataset.Dataset{
+				Structure: &dataset.Structure{Format: "csv", Schema: v.schema},
+			},
+		}
+		if err := dsr.Save(p, &repo.DatasetRef{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req := NewLogRequests(node, nil)
+	if err := req.SchemaHistory(&LogParams{}, &[]SchemaVersion{}); err != repo.ErrEmptyRef {
+		t.Errorf("expected empty ref to error with ErrEmptyRef, got: %v", err)
+	}
+
+	got := []SchemaVersion{}
+	if err := req.SchemaHistory(&LogParams{Ref: "me/schema_history"}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 schema versions, got: %d", len(got))
+	}
+
+	latest := got[0].Changes
+	if len(latest) != 1 || latest[0].Column != "in_usa" || latest[0].Change != SchemaColumnAdded {
+		t.Errorf("expected latest version to add in_usa column, got: %v", latest)
+	}
+
+	got = []SchemaVersion{}
+	if err := req.SchemaHistory(&LogParams{Ref: "me/schema_history", ListParams: ListParams{Offset: 1, Limit: 1}}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Changes) != 2 {
+		t.Errorf("expected paged result to contain the first version adding 2 columns, got: %v", got)
+	}
+}
+
+func TestLogRequestsChangelog(t *testing.T) {
+	mr, refs, err := testrepo.NewTestRepoWithHistory()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	r := NewLogRequests(node, nil)
+	head := refs[0].AliasString()
+
+	var res string
+	if err := r.Changelog(&ChangelogParams{}, &res); err != repo.ErrEmptyRef {
+		t.Errorf("expected empty ref to error with ErrEmptyRef. got: %v", err)
+	}
+
+	if err := r.Changelog(&ChangelogParams{Ref: head, GroupBy: "version"}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(res, "# Changelog: peer/logtest\n") {
+		t.Errorf("expected changelog to start with a title heading. got:\n%s", res)
+	}
+	if got := strings.Count(res, "\n## v"); got != len(refs) {
+		t.Errorf("expected %d version headings, got: %d\n%s", len(refs), got, res)
+	}
+
+	if err := r.Changelog(&ChangelogParams{Ref: head, GroupBy: "version", From: "2", To: "3"}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res, "## v3") || !strings.Contains(res, "## v2") || strings.Contains(res, "## v1") || strings.Contains(res, "## v4") {
+		t.Errorf("expected range 2..3 to include only versions 2 & 3. got:\n%s", res)
+	}
+
+	err = r.Changelog(&ChangelogParams{Ref: head, Format: "html"}, &res)
+	if libErr, ok := err.(Error); !ok || libErr.Message() != `unsupported changelog format "html"` {
+		t.Errorf("expected unsupported format error. got: %v", err)
+	}
+
+	err = r.Changelog(&ChangelogParams{Ref: head, From: "3", To: "2"}, &res)
+	if libErr, ok := err.(Error); !ok || libErr.Error() != ErrBadArgs.Error() {
+		t.Errorf("expected an inverted range to be a bad arguments error. got: %v", err)
+	}
+}
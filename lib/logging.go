@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	logging "github.com/whyrusleeping/go-logging"
+)
+
+// setLogFormat switches the output format of all golog loggers. "text" and
+// the empty string leave golog's default human-readable format in place
+func setLogFormat(format string) error {
+	switch format {
+	case "", "text":
+		return nil
+	case "json":
+		logging.SetFormatter(jsonLogFormatter{})
+		return nil
+	default:
+		return fmt.Errorf("unknown log format: %q", format)
+	}
+}
+
+// jsonLogFormatter formats log records as single-line JSON objects
+type jsonLogFormatter struct{}
+
+// jsonLogRecord is the JSON representation of a log record
+type jsonLogRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Logger    string    `json:"logger"`
+	Message   string    `json:"message"`
+}
+
+// Format implements the logging.Formatter interface
+func (jsonLogFormatter) Format(calldepth int, r *logging.Record, w io.Writer) error {
+	data, err := json.Marshal(jsonLogRecord{
+		Timestamp: r.Time,
+		Level:     strings.ToLower(r.Level.String()),
+		Logger:    r.Module,
+		Message:   r.Message(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	logging "github.com/whyrusleeping/go-logging"
+)
+
+func TestSetLogFormat(t *testing.T) {
+	for _, format := range []string{"", "text"} {
+		if err := setLogFormat(format); err != nil {
+			t.Errorf("format %q: unexpected error: %s", format, err)
+		}
+	}
+	if err := setLogFormat("xml"); err == nil {
+		t.Error("expected unknown format to error")
+	}
+}
+
+func TestJSONLogFormatter(t *testing.T) {
+	// records can only be created by logging, capture one in memory
+	mem := logging.NewMemoryBackend(1)
+	logger := logging.MustGetLogger("qriapi")
+	logger.SetBackend(logging.AddModuleLevel(mem))
+	logger.Criticalf("listening on %d", 2503)
+	r := mem.Head().Record
+	r.Time = r.Time.UTC().Truncate(time.Second)
+
+	buf := &bytes.Buffer{}
+	if err := (jsonLogFormatter{}).Format(0, r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("\n")) {
+		t.Errorf("expected a single line. got: %q", buf.String())
+	}
+
+	got := jsonLogRecord{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	expect := jsonLogRecord{
+		Timestamp: r.Time,
+		Level:     "critical",
+		Logger:    "qriapi",
+		Message:   "listening on 2503",
+	}
+	if got != expect {
+		t.Errorf("record mismatch. expected: %v, got: %v", expect, got)
+	}
+}
@@ -1,7 +1,11 @@
 package lib
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	util "github.com/qri-io/apiutil"
 	"github.com/qri-io/dag"
@@ -29,6 +33,12 @@ type ListParams struct {
 	Published bool
 	// ShowNumVersions only applies to listing datasets
 	ShowNumVersions bool
+	// NewerThan only applies to listing datasets & history, limiting results
+	// to versions committed after this time. ignored if zero
+	NewerThan time.Time
+	// OlderThan only applies to listing datasets & history, limiting results
+	// to versions committed before this time. ignored if zero
+	OlderThan time.Time
 }
 
 // NewListParams creates a ListParams from page & pagesize, pages are 1-indexed
@@ -69,6 +79,48 @@ func (lp ListParams) Page() util.Page {
 	return util.NewPage(number, size)
 }
 
+// ParseTimeBound interprets a string as a point in time, either as an absolute
+// date or as a duration before now. Absolute dates can be RFC3339 timestamps
+// or YYYY-MM-DD dates. Durations are any string accepted by
+// time.ParseDuration, or a whole number followed by one of the units
+// "d" (days), "w" (weeks), or "y" (years), eg: "30d", "1y"
+func ParseTimeBound(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("time bound is required")
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+
+	unit := s[len(s)-1:]
+	switch unit {
+	case "d", "w", "y":
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("invalid duration: %q", s)
+		}
+		switch unit {
+		case "d":
+			return now.AddDate(0, 0, -n), nil
+		case "w":
+			return now.AddDate(0, 0, -7*n), nil
+		default:
+			return now.AddDate(-n, 0, 0), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid time: %q. expected a date like 2019-01-31 or a duration like 30d", s)
+	}
+	return now.Add(-d), nil
+}
+
 // PushParams holds parameters for pushing daginfo to remotes
 type PushParams struct {
 	Ref           string
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func ListParamsEqual(a, b ListParams) error {
@@ -67,3 +68,56 @@ func TestPage(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	good := []struct {
+		input  string
+		expect time.Time
+	}{
+		{"30d", time.Date(2019, 9, 1, 12, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2019, 9, 17, 12, 0, 0, 0, time.UTC)},
+		{"1y", time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)},
+		{"0d", now},
+		{"36h", time.Date(2019, 9, 30, 0, 0, 0, 0, time.UTC)},
+		{"90m", time.Date(2019, 10, 1, 10, 30, 0, 0, time.UTC)},
+		{" 1d ", time.Date(2019, 9, 30, 12, 0, 0, 0, time.UTC)},
+		{"2019-01-31", time.Date(2019, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"2019-01-31T10:00:00Z", time.Date(2019, 1, 31, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for i, c := range good {
+		got, err := ParseTimeBound(c.input, now)
+		if err != nil {
+			t.Errorf("case %d %q unexpected error: %s", i, c.input, err)
+			continue
+		}
+		if !got.Equal(c.expect) {
+			t.Errorf("case %d %q result mismatch. expected: %s, got: %s", i, c.input, c.expect, got)
+		}
+	}
+
+	bad := []struct {
+		input, err string
+	}{
+		{"", "time bound is required"},
+		{"d", `invalid duration: "d"`},
+		{"-3d", `invalid duration: "-3d"`},
+		{"1.5y", `invalid duration: "1.5y"`},
+		{"-1h", `invalid time: "-1h". expected a date like 2019-01-31 or a duration like 30d`},
+		{"soon", `invalid time: "soon". expected a date like 2019-01-31 or a duration like 30d`},
+		{"2019-13-01", `invalid time: "2019-13-01". expected a date like 2019-01-31 or a duration like 30d`},
+	}
+
+	for i, c := range bad {
+		_, err := ParseTimeBound(c.input, now)
+		if err == nil {
+			t.Errorf("case %d %q expected error, got nil", i, c.input)
+			continue
+		}
+		if err.Error() != c.err {
+			t.Errorf("case %d %q error mismatch. expected: %q, got: %q", i, c.input, c.err, err.Error())
+		}
+	}
+}
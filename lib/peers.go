@@ -76,6 +76,21 @@ func (d *PeerRequests) ConnectedIPFSPeers(limit *int, peers *[]string) error {
 	return nil
 }
 
+// ConnectionInfo describes open connections, including latency, direction &
+// qri protocol support for each peer
+func (d *PeerRequests) ConnectionInfo(limit *int, res *[]p2p.PeerConnection) error {
+	if d.cli != nil {
+		return d.cli.Call("PeerRequests.ConnectionInfo", limit, res)
+	}
+
+	conns := d.qriNode.ConnectionInfo(context.TODO())
+	if *limit > 0 && len(conns) > *limit {
+		conns = conns[:*limit]
+	}
+	*res = conns
+	return nil
+}
+
 // ConnectedQriProfiles lists profiles we're currently connected to
 func (d *PeerRequests) ConnectedQriProfiles(limit *int, peers *[]*config.ProfilePod) (err error) {
 	if d.cli != nil {
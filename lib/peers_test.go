@@ -113,6 +113,20 @@ func TestConnectedIPFSPeers(t *testing.T) {
 	}
 }
 
+func TestConnectionInfo(t *testing.T) {
+	node := newTestQriNode(t)
+	req := NewPeerRequests(node, nil)
+
+	limit := 100
+	got := []p2p.PeerConnection{}
+	if err := req.ConnectionInfo(&limit, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no connections, got: %d", len(got))
+	}
+}
+
 func TestInfo(t *testing.T) {
 	// TODO - we're going to need an IPFS network simulation to test this properly
 	cases := []struct {
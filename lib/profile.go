@@ -1,12 +1,18 @@
 package lib
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"image"
+	// register decoders for image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
 	"io/ioutil"
 	"net/http"
 
+	"github.com/dustin/go-humanize"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/registry"
@@ -184,6 +190,66 @@ func (m *ProfileMethods) ProfilePhoto(req *config.ProfilePod, res *[]byte) (err
 	return
 }
 
+// ImageLimits bounds the size & dimensions of an uploaded profile image
+type ImageLimits struct {
+	// MaxSize is the largest accepted file, in bytes
+	MaxSize   int64
+	MaxWidth  int
+	MaxHeight int
+}
+
+var (
+	// DefaultPhotoLimits bounds profile photo uploads
+	DefaultPhotoLimits = ImageLimits{MaxSize: 250000, MaxWidth: 1024, MaxHeight: 1024}
+	// DefaultPosterLimits bounds poster photo uploads
+	DefaultPosterLimits = ImageLimits{MaxSize: 2000000, MaxWidth: 4096, MaxHeight: 2048}
+)
+
+// PhotoLimits gives the limits for profile photo uploads, using any max size
+// set in api configuration
+func PhotoLimits(cfg *config.Config) ImageLimits {
+	l := DefaultPhotoLimits
+	if cfg != nil && cfg.API != nil && cfg.API.PhotoMaxSize > 0 {
+		l.MaxSize = cfg.API.PhotoMaxSize
+	}
+	return l
+}
+
+// PosterLimits gives the limits for poster photo uploads, using any max size
+// set in api configuration
+func PosterLimits(cfg *config.Config) ImageLimits {
+	l := DefaultPosterLimits
+	if cfg != nil && cfg.API != nil && cfg.API.PosterMaxSize > 0 {
+		l.MaxSize = cfg.API.PosterMaxSize
+	}
+	return l
+}
+
+// ValidateImage checks image data is a png or jpeg file within limits,
+// returning the detected mime type
+func ValidateImage(data []byte, l ImageLimits) (mimetype string, err error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("file is empty")
+	}
+	if int64(len(data)) > l.MaxSize {
+		return "", fmt.Errorf("file size too large. max size is %s", humanize.Bytes(uint64(l.MaxSize)))
+	}
+
+	mimetype = http.DetectContentType(data)
+	if mimetype != "image/jpeg" && mimetype != "image/png" {
+		return "", fmt.Errorf("invalid file format. only .jpg & .png images allowed")
+	}
+
+	img, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid image: %s", err)
+	}
+	if img.Width > l.MaxWidth || img.Height > l.MaxHeight {
+		return "", fmt.Errorf("image is %dx%d pixels. max size is %dx%d", img.Width, img.Height, l.MaxWidth, l.MaxHeight)
+	}
+	return mimetype, nil
+}
+
 // FileParams defines parameters for Files as arguments to lib methods
 type FileParams struct {
 	// Url      string    // url to download data from. either Url or Data is required
@@ -210,15 +276,8 @@ func (m *ProfileMethods) SetProfilePhoto(p *FileParams, res *config.ProfilePod)
 		log.Debug(err.Error())
 		return fmt.Errorf("error reading file data: %s", err.Error())
 	}
-	if len(data) > 250000 {
-		return fmt.Errorf("file size too large. max size is 250kb")
-	} else if len(data) == 0 {
-		return fmt.Errorf("data file is empty")
-	}
-
-	mimetype := http.DetectContentType(data)
-	if mimetype != "image/jpeg" {
-		return fmt.Errorf("invalid file format. only .jpg images allowed")
+	if _, err := ValidateImage(data, PhotoLimits(m.inst.cfg)); err != nil {
+		return err
 	}
 
 	// TODO - if file extension is .jpg / .jpeg ipfs does weird shit that makes this not work
@@ -303,15 +362,8 @@ func (m *ProfileMethods) SetPosterPhoto(p *FileParams, res *config.ProfilePod) e
 		return fmt.Errorf("error reading file data: %s", err.Error())
 	}
 
-	if len(data) > 2000000 {
-		return fmt.Errorf("file size too large. max size is 2Mb")
-	} else if len(data) == 0 {
-		return fmt.Errorf("file is empty")
-	}
-
-	mimetype := http.DetectContentType(data)
-	if mimetype != "image/jpeg" {
-		return fmt.Errorf("invalid file format. only .jpg images allowed")
+	if _, err := ValidateImage(data, PosterLimits(m.inst.cfg)); err != nil {
+		return err
 	}
 
 	// TODO - if file extension is .jpg / .jpeg ipfs does weird shit that makes this not work
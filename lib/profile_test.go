@@ -1,7 +1,10 @@
 package lib
 
 import (
+	"bytes"
 	"encoding/json"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -249,8 +252,8 @@ func TestProfileRequestsSetProfilePhoto(t *testing.T) {
 		err     string
 	}{
 		{"", "", "file is required"},
-		{"testdata/ink_big_photo.jpg", "", "file size too large. max size is 250kb"},
-		{"testdata/q_bang.svg", "", "invalid file format. only .jpg images allowed"},
+		{"testdata/ink_big_photo.jpg", "", "file size too large. max size is 250 kB"},
+		{"testdata/q_bang.svg", "", "invalid file format. only .jpg & .png images allowed"},
 		{"testdata/rico_400x400.jpg", "/map/QmRdexT18WuAKVX3vPusqmJTWLeNSeJgjmMbaF5QLGHna1", ""},
 	}
 
@@ -302,8 +305,8 @@ func TestProfileRequestsSetPosterPhoto(t *testing.T) {
 		err     string
 	}{
 		{"", "", "file is required"},
-		{"testdata/ink_big_photo.jpg", "", "file size too large. max size is 250kb"},
-		{"testdata/q_bang.svg", "", "invalid file format. only .jpg images allowed"},
+		{"testdata/ink_big_photo.jpg", "", "file size too large. max size is 2.0 MB"},
+		{"testdata/q_bang.svg", "", "invalid file format. only .jpg & .png images allowed"},
 		{"testdata/rico_poster_1500x500.jpg", "/map/QmdJgfxj4rocm88PLeEididS7V2cc9nQosA46RpvAnWvDL", ""},
 	}
 
@@ -333,15 +336,66 @@ func TestProfileRequestsSetPosterPhoto(t *testing.T) {
 		}
 
 		res := &config.ProfilePod{}
-		err := m.SetProfilePhoto(p, res)
+		err := m.SetPosterPhoto(p, res)
 		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
 			t.Errorf("case %d error mismatch. expected: %s, got: %s", i, c.err, err.Error())
 			continue
 		}
 
-		if c.respath != res.Photo {
-			t.Errorf("case %d profile hash mismatch. expected: %s, got: %s", i, c.respath, res.Photo)
+		if c.respath != res.Poster {
+			t.Errorf("case %d poster hash mismatch. expected: %s, got: %s", i, c.respath, res.Poster)
 			continue
 		}
 	}
 }
+
+func TestValidateImage(t *testing.T) {
+	encodePNG := func(w, h int) []byte {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	limits := ImageLimits{MaxSize: 1 << 20, MaxWidth: 100, MaxHeight: 50}
+	cases := []struct {
+		description string
+		data        []byte
+		mimetype    string
+		err         string
+	}{
+		{"empty", nil, "", "file is empty"},
+		{"too large", make([]byte, limits.MaxSize+1), "", "file size too large. max size is 1.0 MB"},
+		{"not an image", []byte("a,b,c\n1,2,3\n"), "", "invalid file format. only .jpg & .png images allowed"},
+		{"too wide", encodePNG(101, 10), "", "image is 101x10 pixels. max size is 100x50"},
+		{"too tall", encodePNG(10, 51), "", "image is 10x51 pixels. max size is 100x50"},
+		{"png", encodePNG(100, 50), "image/png", ""},
+	}
+
+	for _, c := range cases {
+		mimetype, err := ValidateImage(c.data, limits)
+		if !(err == nil && c.err == "" || err != nil && err.Error() == c.err) {
+			t.Errorf("case '%s' error mismatch. expected: '%s', got: '%v'", c.description, c.err, err)
+			continue
+		}
+		if mimetype != c.mimetype {
+			t.Errorf("case '%s' mimetype mismatch. expected: '%s', got: '%s'", c.description, c.mimetype, mimetype)
+		}
+	}
+}
+
+func TestPhotoLimits(t *testing.T) {
+	cfg := config.DefaultConfigForTesting()
+	if got := PhotoLimits(cfg); got != DefaultPhotoLimits {
+		t.Errorf("expected default photo limits, got: %v", got)
+	}
+	cfg.API.PhotoMaxSize = 500
+	cfg.API.PosterMaxSize = 1000
+	if got := PhotoLimits(cfg).MaxSize; got != 500 {
+		t.Errorf("photo max size mismatch. expected: 500, got: %d", got)
+	}
+	if got := PosterLimits(cfg).MaxSize; got != 1000 {
+		t.Errorf("poster max size mismatch. expected: 1000, got: %d", got)
+	}
+}
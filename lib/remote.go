@@ -2,8 +2,10 @@ package lib
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/qri-io/qri/actions"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/remote"
 	"github.com/qri-io/qri/repo"
 )
@@ -21,13 +23,22 @@ type RemoteMethods struct {
 func NewRemoteMethods(inst *Instance) *RemoteMethods {
 	return &RemoteMethods{
 		inst: inst,
-		cli:  inst.remoteClient,
+		cli:  inst.RemoteClient(),
 	}
 }
 
 // CoreRequestsName implements the Requests interface
 func (*RemoteMethods) CoreRequestsName() string { return "remote" }
 
+// client returns the instance remote client, which is replaced when the
+// instance reconnects, falling back to the client these methods were made with
+func (r *RemoteMethods) client() *remote.Client {
+	if cli := r.inst.RemoteClient(); cli != nil {
+		return cli
+	}
+	return r.cli
+}
+
 // PublicationParams encapsulates parmeters for dataset publication
 type PublicationParams struct {
 	Ref        string
@@ -60,7 +71,7 @@ func (r *RemoteMethods) Publish(p *PublicationParams, res *repo.DatasetRef) erro
 	// TODO (b5) - need contexts yo
 	ctx := context.TODO()
 
-	if err = r.cli.PushDataset(ctx, ref, addr); err != nil {
+	if err = r.client().PushDataset(ctx, ref, addr); err != nil {
 		return err
 	}
 
@@ -92,7 +103,7 @@ func (r *RemoteMethods) Unpublish(p *PublicationParams, res *repo.DatasetRef) er
 	// TODO (b5) - need contexts yo
 	ctx := context.TODO()
 
-	if err := r.cli.RemoveDataset(ctx, ref, addr); err != nil {
+	if err := r.client().RemoveDataset(ctx, ref, addr); err != nil {
 		return err
 	}
 
@@ -114,6 +125,65 @@ func (r *RemoteMethods) PullDataset(p *PublicationParams, res *bool) error {
 	// TODO (b5) - need contexts yo
 	ctx := context.TODO()
 
-	err = r.cli.PullDataset(ctx, &ref, p.RemoteName)
+	err = r.client().PullDataset(ctx, &ref, p.RemoteName)
 	return err
 }
+
+// HeadComparison describes how a local dataset head relates to a remote head
+type HeadComparison = base.HeadComparison
+
+// CompareHeadParams encapsulates parameters for comparing a local dataset head
+// to a remote
+type CompareHeadParams struct {
+	Ref        string
+	RemoteName string
+}
+
+// CompareHead resolves the head of a dataset on a remote and compares it to the
+// local head, reporting if they match & which side is ahead if they don't
+func (r *RemoteMethods) CompareHead(p *CompareHeadParams, res *HeadComparison) error {
+	if r.inst.rpc != nil {
+		return r.inst.rpc.Call("RemoteMethods.CompareHead", p, res)
+	}
+
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return err
+	}
+
+	addr, err := remote.Address(r.inst.Config(), p.RemoteName)
+	if err != nil {
+		return err
+	}
+
+	// TODO (b5) - need contexts yo
+	ctx := context.TODO()
+
+	var local, rem *repo.DatasetRef
+	localRef := ref
+	if err = repo.CanonicalizeDatasetRef(r.inst.Repo(), &localRef); err == nil {
+		local = &localRef
+	} else if err != repo.ErrNotFound {
+		return err
+	}
+
+	// remotes resolve by human-friendly name. canonicalizing replaces aliases
+	// like "me" with a peername, even if the dataset doesn't exist locally
+	remoteRef := repo.DatasetRef{Peername: localRef.Peername, Name: ref.Name}
+	if err = r.client().ResolveHeadRef(ctx, &remoteRef, addr); err == nil {
+		rem = &remoteRef
+	} else if err != remote.ErrNotFound {
+		return err
+	}
+
+	if local == nil && rem == nil {
+		return NewError(repo.ErrNotFound, fmt.Sprintf("dataset %s doesn't exist locally or on remote %q", ref.AliasString(), p.RemoteName))
+	}
+
+	hc, err := base.CompareHeads(ctx, r.inst.Repo(), local, rem)
+	if err != nil {
+		return err
+	}
+	*res = *hc
+	return nil
+}
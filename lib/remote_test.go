@@ -1,5 +1,77 @@
 package lib
 
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/remote"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestRemoteMethodsCompareHead(t *testing.T) {
+	node := newTestQriNode(t)
+	ref := addCitiesDataset(t, node)
+
+	// serve remote heads the way a remote's /remote/refs endpoint does
+	remoteHeads := map[string]string{"cities": ref.Path, "movies": "/map/QmRemoteOnly"}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ok := remoteHeads[r.FormValue("name")]
+		if !ok || r.FormValue("peername") != ref.Peername {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(repo.DatasetRef{Peername: r.FormValue("peername"), Name: r.FormValue("name"), Path: path})
+	}))
+	defer s.Close()
+
+	cfg := config.DefaultConfigForTesting()
+	cfg.Remotes = &config.Remotes{"origin": s.URL}
+	m := NewRemoteMethods(NewInstanceFromConfigAndNode(cfg, node))
+	// resolving heads is a plain HTTP request, no IPFS-backed client is needed
+	m.cli = &remote.Client{}
+
+	cases := []struct {
+		ref, status string
+	}{
+		{"me/cities", base.HeadsMatch},
+		{"me/movies", base.HeadLocalMissing},
+	}
+	for _, c := range cases {
+		res := &HeadComparison{}
+		if err := m.CompareHead(&CompareHeadParams{Ref: c.ref, RemoteName: "origin"}, res); err != nil {
+			t.Errorf("%s: unexpected error: %s", c.ref, err)
+			continue
+		}
+		if res.Status != c.status {
+			t.Errorf("%s: status mismatch. expected: %q, got: %q", c.ref, c.status, res.Status)
+		}
+	}
+
+	delete(remoteHeads, "cities")
+	res := &HeadComparison{}
+	if err := m.CompareHead(&CompareHeadParams{Ref: "me/cities", RemoteName: "origin"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != base.HeadRemoteMissing || res.LocalPath != ref.Path {
+		t.Errorf("expected dataset missing on remote. got: %#v", res)
+	}
+
+	err := m.CompareHead(&CompareHeadParams{Ref: "me/not_a_dataset", RemoteName: "origin"}, res)
+	expect := `dataset me/not_a_dataset doesn't exist locally or on remote "origin"`
+	if libErr, ok := err.(Error); !ok || libErr.Message() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+
+	expect = `remote name "nope" not found`
+	if err := m.CompareHead(&CompareHeadParams{Ref: "me/cities", RemoteName: "nope"}, res); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
+
 // func TestRemote(t *testing.T) {
 // 	cfg := config.DefaultConfigForTesting()
 // 	rc, _ := regmock.NewMockServer()
@@ -3,6 +3,7 @@ package lib
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/rpc"
 
 	"github.com/qri-io/qri/base"
@@ -35,8 +36,12 @@ func (RenderRequests) CoreRequestsName() string { return "render" }
 
 // RenderParams defines parameters for the Render method
 type RenderParams struct {
-	Ref            string
-	Template       []byte
+	Ref      string
+	Template []byte
+	// TemplatePath is the path to a local template file to render with, read
+	// when Template is empty. Relative paths resolve from the working
+	// directory of the process handling the request
+	TemplatePath   string
 	TemplateFormat string
 }
 
@@ -58,6 +63,13 @@ func (r *RenderRequests) Render(p *RenderParams, res *[]byte) (err error) {
 		return err
 	}
 
-	*res, err = base.Render(ctx, r.repo, ref, p.Template)
+	template := p.Template
+	if len(template) == 0 && p.TemplatePath != "" {
+		if template, err = ioutil.ReadFile(p.TemplatePath); err != nil {
+			return fmt.Errorf("reading template: %s", err.Error())
+		}
+	}
+
+	*res, err = base.Render(ctx, r.repo, ref, template)
 	return err
 }
@@ -1,10 +1,12 @@
 package lib
 
 import (
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/rpc"
+	"os"
 	"testing"
 
 	"github.com/qri-io/qri/base"
@@ -48,6 +50,16 @@ func TestRenderRequestsRender(t *testing.T) {
 	base.DefaultTemplate = `<html><h1>{{.Peername}}/{{.Name}}</h1></html>`
 	defer func() { base.DefaultTemplate = prevDefaultTemplate }()
 
+	tmplFile, err := ioutil.TempFile("", "render_template")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmplFile.Name())
+	if _, err := tmplFile.WriteString("<h2>{{ .Meta.Title }}</h2>"); err != nil {
+		t.Fatal(err)
+	}
+	tmplFile.Close()
+
 	cases := []struct {
 		description string
 		params      *RenderParams
@@ -75,6 +87,22 @@ func TestRenderRequestsRender(t *testing.T) {
 				Ref:      "me/movies",
 				Template: []byte("{{ .BadTemplateBooPlzFail"),
 			}, nil, `parsing template: template: index.html:1: unclosed action`},
+		{"template file",
+			&RenderParams{
+				Ref:          "me/movies",
+				TemplatePath: tmplFile.Name(),
+			}, []byte("<h2>example movie data</h2>"), ""},
+		{"template bytes take precedence over template file",
+			&RenderParams{
+				Ref:          "me/movies",
+				Template:     []byte("{{ .Meta.Title }}"),
+				TemplatePath: tmplFile.Name(),
+			}, []byte("example movie data"), ""},
+		{"missing template file",
+			&RenderParams{
+				Ref:          "me/movies",
+				TemplatePath: "/path/to/missing/template.html",
+			}, nil, "reading template: open /path/to/missing/template.html: no such file or directory"},
 		{"default template",
 			&RenderParams{
 				Ref: "me/movies",
@@ -3,6 +3,7 @@ package lib
 import (
 	"fmt"
 
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/qri/registry/regclient"
 	"github.com/qri-io/qri/repo"
 )
@@ -33,6 +34,9 @@ type SearchResult struct {
 	Type, ID string
 	URL      string
 	Value    interface{}
+	// Score is the relevance the registry gave the result, 0 if it doesn't
+	// report one
+	Score float64 `json:",omitempty"`
 }
 
 // Search queries for items on qri related to given parameters
@@ -54,16 +58,18 @@ func (m *SearchMethods) Search(p *SearchParams, results *[]SearchResult) error {
 		Offset:      p.Offset,
 	}
 
-	regResults, err := reg.Search(params)
+	regResults, err := reg.SearchResults(params)
 	if err != nil {
 		return err
 	}
 
 	searchResults := make([]SearchResult, len(regResults))
-	for i, result := range regResults {
+	for i, r := range regResults {
+		result := r.Dataset
 		searchResults[i].Type = "dataset"
 		searchResults[i].ID = result.Path
 		searchResults[i].Value = result
+		searchResults[i].Score = r.Score
 
 		// TODO (b5) - this is cloud specific, should be generalized
 		if m.inst.Config().Registry.Location == "https://registry.qri.cloud" {
@@ -73,3 +79,34 @@ func (m *SearchMethods) Search(p *SearchParams, results *[]SearchResult) error {
 	*results = searchResults
 	return nil
 }
+
+// SearchResultSummary is a flat description of a dataset search result for
+// structured output
+type SearchResultSummary struct {
+	Ref         string  `json:"ref"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Score       float64 `json:"score"`
+}
+
+// SummarizeSearchResults condenses dataset search results into summaries,
+// in the order the registry ranked them. Score is the registry's score for
+// the result, 0 if it doesn't report one
+func SummarizeSearchResults(results []SearchResult) []SearchResultSummary {
+	summaries := make([]SearchResultSummary, 0, len(results))
+	for _, r := range results {
+		ds, ok := r.Value.(*dataset.Dataset)
+		if !ok || ds == nil {
+			continue
+		}
+
+		ref := repo.DatasetRef{Peername: ds.Peername, Name: ds.Name, Path: ds.Path}
+		sum := SearchResultSummary{Ref: ref.String(), Score: r.Score}
+		if ds.Meta != nil {
+			sum.Title = ds.Meta.Title
+			sum.Description = ds.Meta.Description
+		}
+		summaries = append(summaries, sum)
+	}
+	return summaries
+}
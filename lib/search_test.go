@@ -3,8 +3,10 @@ package lib
 import (
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/registry/regclient"
@@ -42,6 +44,35 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+func TestSummarizeSearchResults(t *testing.T) {
+	results := []SearchResult{
+		{Type: "dataset", Value: &dataset.Dataset{
+			Peername: "nuun",
+			Name:     "census_2018",
+			Path:     "/ipfs/QmFoo",
+			Meta: &dataset.Meta{
+				Title:       "US Census",
+				Description: "population counts by county",
+				Keywords:    []string{"demographics"},
+			},
+		}, Score: 0.5},
+		{Type: "dataset", Value: &dataset.Dataset{
+			Peername: "nuun",
+			Name:     "cities",
+			Path:     "/ipfs/QmBar",
+		}},
+	}
+
+	got := SummarizeSearchResults(results)
+	expect := []SearchResultSummary{
+		{Ref: "nuun/census_2018@/ipfs/QmFoo", Title: "US Census", Description: "population counts by county", Score: 0.5},
+		{Ref: "nuun/cities@/ipfs/QmBar"},
+	}
+	if !reflect.DeepEqual(expect, got) {
+		t.Errorf("summary mismatch.\nwant: %v\ngot:  %v", expect, got)
+	}
+}
+
 var mockResponse = []byte(`{"data":[
   {
     "Type": "dataset",
@@ -0,0 +1,133 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/sql"
+)
+
+// SQLMethods runs SQL queries against dataset bodies
+type SQLMethods struct {
+	inst *Instance
+}
+
+// NewSQLMethods creates SQLMethods from a qri Instance
+func NewSQLMethods(inst *Instance) *SQLMethods {
+	return &SQLMethods{inst: inst}
+}
+
+// CoreRequestsName implements the Requests interface
+func (m SQLMethods) CoreRequestsName() string { return "sql" }
+
+// SQLQueryParams defines parameters for the Exec method
+type SQLQueryParams struct {
+	Query string `json:"q"`
+	// Format of the results, one of "json" or "csv". defaults to json
+	Format string `json:"format,omitempty"`
+	// Ctx cancels the query, defaults to the instance context
+	Ctx context.Context `json:"-"`
+}
+
+// Exec runs a SELECT query, writing results to res in the requested format
+func (m *SQLMethods) Exec(p *SQLQueryParams, res *[]byte) error {
+	if m.inst.rpc != nil {
+		return m.inst.rpc.Call("SQLMethods.Exec", p, res)
+	}
+	if p == nil {
+		return fmt.Errorf("error: sql params cannot be nil")
+	}
+	var write func(*sql.Result, io.Writer) error
+	switch p.Format {
+	case "", "json":
+		write = (*sql.Result).WriteJSON
+	case "csv":
+		write = (*sql.Result).WriteCSV
+	default:
+		return fmt.Errorf("invalid sql result format %q, must be json or csv", p.Format)
+	}
+	ctx := p.Ctx
+	if ctx == nil {
+		ctx = m.inst.Context()
+	}
+
+	sel, err := sql.Parse(p.Query)
+	if err != nil {
+		return err
+	}
+
+	r := m.inst.Repo()
+	ref, err := base.ToDatasetRef(sel.From, r, false)
+	if err != nil {
+		return err
+	}
+	ds, err := dsfs.LoadDataset(ctx, r.Store(), ref.Path)
+	if err != nil {
+		return fmt.Errorf("loading dataset: %s", err)
+	}
+	if err = base.OpenDataset(ctx, r.Filesystem(), ds); err != nil {
+		return err
+	}
+
+	columns := base.SchemaColumnNames(ds.Structure)
+	if len(columns) == 0 {
+		return fmt.Errorf("%s can't be queried with SQL: body must be tabular with named columns", ref.AliasString())
+	}
+	er, err := dsio.NewEntryReader(ds.Structure, ds.BodyFile())
+	if err != nil {
+		return err
+	}
+	defer ds.BodyFile().Close()
+
+	result, err := sel.Exec(columns, &entryRowReader{ctx: ctx, er: er, columns: columns})
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err = write(result, buf); err != nil {
+		return err
+	}
+	*res = buf.Bytes()
+	return nil
+}
+
+// entryRowReader adapts a dsio.EntryReader to sql.RowReader, reading object
+// rows into column order
+type entryRowReader struct {
+	ctx     context.Context
+	er      dsio.EntryReader
+	columns []string
+}
+
+// ReadRow implements the sql.RowReader interface, stopping once the query is
+// cancelled
+func (r *entryRowReader) ReadRow() ([]interface{}, error) {
+	if err := r.ctx.Err(); err != nil {
+		return nil, err
+	}
+	ent, err := r.er.ReadEntry()
+	if err != nil {
+		if err.Error() == "EOF" {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	switch v := ent.Value.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		row := make([]interface{}, len(r.columns))
+		for i, col := range r.columns {
+			row[i] = v[col]
+		}
+		return row, nil
+	}
+	return nil, fmt.Errorf("row %d isn't an array or object", ent.Index)
+}
@@ -0,0 +1,71 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/p2p"
+	testrepo "github.com/qri-io/qri/repo/test"
+)
+
+func TestSQLMethodsExec(t *testing.T) {
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	m := NewSQLMethods(NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node))
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		description string
+		params      SQLQueryParams
+		expect      string
+		err         string
+	}{
+		{"json results",
+			SQLQueryParams{Query: "SELECT city, pop FROM me/cities WHERE pop > 1000000 LIMIT 2"},
+			`[{"city":"toronto","pop":40000000},{"city":"new york","pop":8500000}]`, ""},
+		{"csv results",
+			SQLQueryParams{Query: "SELECT count(*) AS n FROM me/cities WHERE in_usa", Format: "csv"},
+			"n\n4\n", ""},
+		{"ordering",
+			SQLQueryParams{Query: "SELECT city FROM me/cities ORDER BY pop"},
+			"", "parsing query: ORDER BY isn't supported"},
+		{"bad query",
+			SQLQueryParams{Query: "DELETE FROM me/cities"},
+			"", `parsing query: expected SELECT, got "DELETE"`},
+		{"unknown column",
+			SQLQueryParams{Query: "SELECT town FROM me/cities"},
+			"", `unknown column "town"`},
+		{"bad format",
+			SQLQueryParams{Query: "SELECT city FROM me/cities", Format: "xlsx"},
+			"", `invalid sql result format "xlsx", must be json or csv`},
+		{"cancelled",
+			SQLQueryParams{Query: "SELECT city FROM me/cities", Ctx: cancelled},
+			"", "context canceled"},
+	}
+
+	for _, c := range cases {
+		res := []byte{}
+		err := m.Exec(&c.params, &res)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("case '%s' error mismatch. expected: %q, got: %v", c.description, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case '%s' unexpected error: %s", c.description, err)
+			continue
+		}
+		if string(res) != c.expect {
+			t.Errorf("case '%s' result mismatch.\nexpected: %q\ngot:      %q", c.description, c.expect, string(res))
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/repo"
+)
+
+// SquashParams defines parameters for squashing dataset history
+type SquashParams struct {
+	Ref string
+	// Revisions is the number of latest versions to squash into one. 0
+	// squashes the entire history into a new root version
+	Revisions int
+	// Title & Message set the commit of the squashed version. default title &
+	// message summarize the squashed commits
+	Title   string
+	Message string
+	// Unpin removes squashed versions from the store
+	Unpin bool
+}
+
+// SquashResponse is the result of squashing dataset history
+type SquashResponse struct {
+	Ref         repo.DatasetRef
+	NumSquashed int
+}
+
+// Squash rewrites a dataset's history, collapsing the latest versions into a
+// single version with the same content as the current head
+func (r *DatasetRequests) Squash(p *SquashParams, res *SquashResponse) error {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.Squash", p, res)
+	}
+	ctx := context.TODO()
+
+	if p.Revisions < 0 || p.Revisions == 1 {
+		return NewError(ErrBadArgs, fmt.Sprintf("invalid number of revisions to squash: %d", p.Revisions))
+	}
+
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return err
+	}
+	if err = repo.CanonicalizeDatasetRef(r.node.Repo, &ref); err != nil {
+		return err
+	}
+
+	pro, err := r.node.Repo.Profile()
+	if err != nil {
+		return err
+	}
+	if ref.Peername != pro.Peername {
+		return fmt.Errorf("can only squash the history of your own datasets")
+	}
+	head, err := r.node.Repo.GetRef(repo.DatasetRef{Peername: ref.Peername, Name: ref.Name})
+	if err != nil {
+		return err
+	}
+	if ref.Path != head.Path {
+		return fmt.Errorf("can only squash history starting from the latest version")
+	}
+
+	n := p.Revisions
+	if n == 0 {
+		n = -1
+	}
+	squashedRef, squashed, err := base.SquashDataset(ctx, r.node.Repo, head, n, p.Title, p.Message, p.Unpin)
+	if err != nil {
+		return err
+	}
+
+	res.Ref = squashedRef
+	res.NumSquashed = len(squashed)
+	return nil
+}
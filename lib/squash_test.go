@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestDatasetRequestsSquash(t *testing.T) {
+	node := newTestQriNode(t)
+	r := NewDatasetRequests(node, nil)
+
+	for _, title := range []string{"one", "two", "three"} {
+		p := &SaveParams{
+			Ref: "me/squash_me",
+			Dataset: &dataset.Dataset{
+				Meta:      &dataset.Meta{Title: title},
+				Structure: &dataset.Structure{Format: "json"},
+				BodyPath:  "body.json",
+				BodyBytes: []byte(`[{"a":1},{"a":2}]`),
+			},
+		}
+		if err := r.Save(p, &repo.DatasetRef{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res := &SquashResponse{}
+	if err := r.Squash(&SquashParams{Ref: "me/squash_me", Revisions: 1}, res); err == nil {
+		t.Error("expected squashing one revision to error")
+	}
+	if err := r.Squash(&SquashParams{Ref: "me/squash_me", Revisions: 2, Title: "two & three"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.NumSquashed != 2 {
+		t.Errorf("expected 2 squashed versions, got: %d", res.NumSquashed)
+	}
+	if res.Ref.Dataset.Meta.Title != "three" {
+		t.Errorf("expected squashed version to keep head content. got title: %q", res.Ref.Dataset.Meta.Title)
+	}
+	if res.Ref.Dataset.Commit.Title != "two & three" {
+		t.Errorf("commit title mismatch. got: %q", res.Ref.Dataset.Commit.Title)
+	}
+
+	log := []repo.DatasetRef{}
+	if err := r.Squash(&SquashParams{Ref: "me/squash_me"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.NumSquashed != 2 {
+		t.Errorf("expected squashing all versions to squash 2 versions, got: %d", res.NumSquashed)
+	}
+	lp := &LogParams{Ref: "me/squash_me", ListParams: ListParams{Limit: 10}}
+	if err := NewLogRequests(node, nil).Log(lp, &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 1 {
+		t.Errorf("expected a single version after squashing all history, got: %d", len(log))
+	}
+}
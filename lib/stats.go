@@ -0,0 +1,90 @@
+package lib
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/ipfs/go-ipfs/core/corerepo"
+	"github.com/qri-io/qfs/cafs"
+	ipfs "github.com/qri-io/qfs/cafs/ipfs"
+)
+
+// Stats reports on the size of an instance's store & repo. Any figure the
+// backing store, repo, or scheduler can't report is set to -1
+type Stats struct {
+	// BlockCount is the number of blocks in the store
+	BlockCount int64 `json:"blockCount"`
+	// StoreSize is the total size of the store in bytes
+	StoreSize int64 `json:"storeSize"`
+	// DatasetCount is the number of dataset references in the repo
+	DatasetCount int64 `json:"datasetCount"`
+	// CronJobCount is the number of scheduled update jobs
+	CronJobCount int64 `json:"cronJobCount"`
+}
+
+// sizedStore is a store that can report its size on disk
+type sizedStore interface {
+	Size() (int64, error)
+}
+
+// mapStoreSize sums the size of all files in a map store
+func mapStoreSize(store *cafs.MapStore) (size int64) {
+	for _, f := range store.Files {
+		if file := f.File(); !file.IsDirectory() {
+			data, err := ioutil.ReadAll(file)
+			if err != nil {
+				continue
+			}
+			size += int64(len(data))
+		}
+	}
+	return size
+}
+
+// Stats collects runtime metrics for the instance
+func (inst *Instance) Stats(ctx context.Context) (*Stats, error) {
+	s := &Stats{
+		BlockCount:   -1,
+		StoreSize:    -1,
+		DatasetCount: -1,
+		CronJobCount: -1,
+	}
+
+	switch store := inst.store.(type) {
+	case *ipfs.Filestore:
+		stat, err := corerepo.RepoStat(ctx, store.Node())
+		if err != nil {
+			log.Debugf("getting ipfs repo stats: %s", err)
+			break
+		}
+		s.BlockCount = int64(stat.NumObjects)
+		s.StoreSize = int64(stat.RepoSize)
+	case *cafs.MapStore:
+		s.BlockCount = int64(len(store.Files))
+		s.StoreSize = mapStoreSize(store)
+	case sizedStore:
+		if size, err := store.Size(); err == nil {
+			s.StoreSize = size
+		} else {
+			log.Debugf("getting store size: %s", err)
+		}
+	}
+
+	if inst.repo != nil {
+		if n, err := inst.repo.RefCount(); err == nil {
+			s.DatasetCount = int64(n)
+		} else {
+			log.Debugf("counting repo references: %s", err)
+		}
+	}
+
+	if inst.cron != nil {
+		if jobs, err := inst.cron.ListJobs(ctx, 0, -1); err == nil {
+			s.CronJobCount = int64(len(jobs))
+		} else {
+			log.Debugf("listing cron jobs: %s", err)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,54 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qri-io/iso8601"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/update/cron"
+)
+
+func TestInstanceStats(t *testing.T) {
+	ctx := context.Background()
+
+	inst := &Instance{}
+	s, err := inst.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := Stats{BlockCount: -1, StoreSize: -1, DatasetCount: -1, CronJobCount: -1}
+	if *s != expect {
+		t.Errorf("expected empty instance to report unknown stats. got: %v", s)
+	}
+
+	node := newTestQriNode(t)
+	addCitiesDataset(t, node)
+	inst = NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+
+	p, err := iso8601.ParseRepeatingInterval("R/P1D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := &cron.MemJobStore{}
+	if err := jobs.PutJob(ctx, &cron.Job{Name: "me/cities", Type: cron.JTDataset, Periodicity: p}); err != nil {
+		t.Fatal(err)
+	}
+	inst.cron = cron.NewCron(jobs, &cron.MemJobStore{}, nil)
+
+	if s, err = inst.Stats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.BlockCount <= 0 {
+		t.Errorf("expected map store to report blocks. got: %d", s.BlockCount)
+	}
+	if s.StoreSize <= 0 {
+		t.Errorf("expected map store to report size. got: %d", s.StoreSize)
+	}
+	if s.DatasetCount != 1 {
+		t.Errorf("dataset count mismatch. expected: 1, got: %d", s.DatasetCount)
+	}
+	if s.CronJobCount != 1 {
+		t.Errorf("cron job count mismatch. expected: 1, got: %d", s.CronJobCount)
+	}
+}
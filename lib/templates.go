@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// datasetTemplateExts lists file extensions dataset templates are read from,
+// in order of precedence
+var datasetTemplateExts = []string{".json", ".yaml", ".yml"}
+
+// templatesDir returns the directory dataset templates are registered in,
+// returning the empty string if this instance has no repo path
+func (r *DatasetRequests) templatesDir() string {
+	if r.inst == nil || r.inst.repoPath == "" {
+		return ""
+	}
+	return filepath.Join(r.inst.repoPath, "templates")
+}
+
+// ReadDatasetTemplate loads a named dataset template from a templates
+// directory. A template is a dataset file like those accepted by
+// `qri save --file`, named [name].json or [name].yaml. Only the structure &
+// meta components of a template are used
+func ReadDatasetTemplate(dir, name string) (*dataset.Dataset, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+
+	for _, ext := range datasetTemplateExts {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		ds, err := ReadDatasetFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading template %q: %s", name, err)
+		}
+		if ds.Structure == nil && ds.Meta == nil {
+			return nil, fmt.Errorf("template %q has no structure or meta", name)
+		}
+		return &dataset.Dataset{Structure: ds.Structure, Meta: ds.Meta}, nil
+	}
+
+	available, _ := ListDatasetTemplates(dir)
+	if len(available) == 0 {
+		return nil, fmt.Errorf("template %q not found. add templates to %s", name, dir)
+	}
+	return nil, fmt.Errorf("template %q not found. available templates: %s", name, strings.Join(available, ", "))
+}
+
+// ListDatasetTemplates lists the names of templates in a templates directory
+func ListDatasetTemplates(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	names := []string{}
+	for _, fi := range infos {
+		if fi.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(fi.Name()))
+		for _, tExt := range datasetTemplateExts {
+			name := strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))
+			if ext == tExt && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// applyDatasetTemplate fills the structure & meta components of ds from a
+// template when they aren't provided. Explicitly provided components are
+// left as-is
+func applyDatasetTemplate(ds, tmpl *dataset.Dataset) {
+	if ds.Structure == nil && tmpl.Structure != nil {
+		ds.Structure = &dataset.Structure{}
+		ds.Structure.Assign(tmpl.Structure)
+	}
+	if ds.Meta == nil && tmpl.Meta != nil {
+		ds.Meta = &dataset.Meta{}
+		ds.Meta.Assign(tmpl.Meta)
+	}
+}
@@ -0,0 +1,117 @@
+package lib
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/p2p"
+	"github.com/qri-io/qri/repo"
+	testrepo "github.com/qri-io/qri/repo/test"
+)
+
+func writeTemplateFiles(t *testing.T, dir string, files map[string]string) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadDatasetTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestReadDatasetTemplate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTemplateFiles(t, dir, map[string]string{
+		"census.yaml": "meta:\n  title: census\n  keywords: [census]\nstructure:\n  format: csv\n",
+		"empty.json":  `{"commit":{"title":"nope"}}`,
+		"notes.txt":   "not a template",
+	})
+
+	names, err := ListDatasetTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"census", "empty"}) {
+		t.Errorf("template names mismatch. got: %v", names)
+	}
+
+	tmpl, err := ReadDatasetTemplate(dir, "census")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Meta == nil || tmpl.Meta.Title != "census" || tmpl.Structure == nil || tmpl.Structure.Format != "csv" {
+		t.Errorf("unexpected template: %v", tmpl)
+	}
+
+	bad := []struct {
+		name, err string
+	}{
+		{"../census", `invalid template name "../census"`},
+		{"missing", `template "missing" not found. available templates: census, empty`},
+		{"empty", `template "empty" has no structure or meta`},
+	}
+	for _, c := range bad {
+		if _, err := ReadDatasetTemplate(dir, c.name); err == nil || err.Error() != c.err {
+			t.Errorf("case %q error mismatch. want: %q, got: %v", c.name, c.err, err)
+		}
+	}
+}
+
+func TestSaveWithTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestSaveWithTemplate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeTemplateFiles(t, filepath.Join(dir, "templates"), map[string]string{
+		"team.json": `{"meta":{"title":"team standard","keywords":["team"]}}`,
+	})
+	bodyPath := filepath.Join(dir, "body.json")
+	writeTemplateFiles(t, dir, map[string]string{"body.json": `[[1,2],[3,4]]`})
+	metaPath := filepath.Join(dir, "meta.json")
+	writeTemplateFiles(t, dir, map[string]string{"meta.json": `{"meta":{"title":"explicit"}}`})
+
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	inst := NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	inst.repoPath = dir
+	req := NewDatasetRequestsInstance(inst)
+
+	res := &repo.DatasetRef{}
+	if err := req.Save(&SaveParams{Ref: "me/templated", BodyPath: bodyPath, Template: "team"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Dataset.Meta == nil || res.Dataset.Meta.Title != "team standard" || !reflect.DeepEqual(res.Dataset.Meta.Keywords, []string{"team"}) {
+		t.Errorf("expected template meta to be applied. got: %v", res.Dataset.Meta)
+	}
+
+	// explicit components override the template
+	res = &repo.DatasetRef{}
+	if err := req.Save(&SaveParams{Ref: "me/explicit", BodyPath: bodyPath, FilePaths: []string{metaPath}, Template: "team"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Dataset.Meta == nil || res.Dataset.Meta.Title != "explicit" || res.Dataset.Meta.Keywords != nil {
+		t.Errorf("expected explicit meta to override template. got: %v", res.Dataset.Meta)
+	}
+
+	if err := req.Save(&SaveParams{Ref: "me/missing", BodyPath: bodyPath, Template: "missing"}, &repo.DatasetRef{}); err == nil {
+		t.Error("expected saving with a missing template to error")
+	}
+}
@@ -0,0 +1,157 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/actions"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/repo"
+)
+
+// DefaultTransformPreviewTimeout is how long a transform preview may run when
+// no timeout is given
+const DefaultTransformPreviewTimeout = 30 * time.Second
+
+// PreviewTransformParams defines parameters for previewing a transform
+type PreviewTransformParams struct {
+	// Ref is the dataset the transform runs against
+	Ref string
+	// Transform to run. if nil, the transform attached to the current version
+	// of Ref is run. ScriptPath may be a local file path
+	Transform *dataset.Transform
+	// Secrets to pass to the transform
+	Secrets map[string]string
+	// Timeout caps how long the transform may run, defaults to
+	// DefaultTransformPreviewTimeout
+	Timeout time.Duration
+}
+
+// PreviewTransformResponse is the result of previewing a transform
+type PreviewTransformResponse struct {
+	// Dataset is the dataset the transform produced, with body inlined as JSON
+	Dataset *dataset.Dataset `json:"dataset"`
+	// Output is anything the transform script printed
+	Output string `json:"output"`
+}
+
+// PreviewTransform runs a transform against the current version of a dataset
+// & returns the resulting dataset without saving it. Nothing is written to
+// the store or the dataset log
+func (r *DatasetRequests) PreviewTransform(p *PreviewTransformParams, res *PreviewTransformResponse) (err error) {
+	// absolutize local script paths before a possible trip over RPC to another local process
+	if p.Transform != nil && p.Transform.ScriptPath != "" && p.Transform.ScriptBytes == nil {
+		if err = qfs.AbsPath(&p.Transform.ScriptPath); err != nil {
+			return err
+		}
+	}
+
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.PreviewTransform", p, res)
+	}
+
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return err
+	}
+	if err = repo.CanonicalizeProfile(r.node.Repo, &ref); err != nil {
+		return err
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTransformPreviewTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	defer cancel()
+
+	changes := &dataset.Dataset{
+		Peername:  ref.Peername,
+		Name:      ref.Name,
+		Transform: p.Transform,
+	}
+	output := &bytes.Buffer{}
+	ds, err := actions.PreviewTransform(ctx, r.node, changes, p.Secrets, output)
+	if err != nil {
+		return err
+	}
+
+	if err = base.OpenDataset(ctx, r.node.Repo.Filesystem(), ds); err != nil {
+		return err
+	}
+	if ds.BodyFile() != nil && ds.Structure != nil {
+		if err = base.InlineJSONBody(ds); err != nil {
+			return err
+		}
+	}
+
+	*res = PreviewTransformResponse{
+		Dataset: ds,
+		Output:  output.String(),
+	}
+	return nil
+}
+
+// TransformEstimate is a best-effort estimate of what a transform will read
+type TransformEstimate = base.TransformEstimate
+
+// TransformInput is a resource a transform is expected to read
+type TransformInput = base.TransformInput
+
+// EstimateTransform estimates the inputs the transform in a save would read,
+// without running it. The transform is found the same way Save finds it: from
+// p.Dataset, p.FilePaths, or a recalled version
+func (r *DatasetRequests) EstimateTransform(p *SaveParams, res *TransformEstimate) (err error) {
+	// absolutize file paths before a possible trip over RPC to another local process
+	if err = p.AbsolutizePaths(); err != nil {
+		return err
+	}
+
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.EstimateTransform", p, res)
+	}
+	ctx := context.TODO()
+
+	var tf *dataset.Transform
+	if p.Dataset != nil && p.Dataset.Transform != nil {
+		tf = p.Dataset.Transform
+	}
+	if len(p.FilePaths) > 0 {
+		dsf, err := ReadDatasetFiles(p.FilePaths...)
+		if err != nil {
+			return err
+		}
+		if dsf.Transform != nil {
+			tf = dsf.Transform
+		}
+	}
+	if tf == nil && p.Recall != "" {
+		ref, err := repo.ParseDatasetRef(p.Ref)
+		if err != nil {
+			return err
+		}
+		recall, err := actions.Recall(ctx, r.node, p.Recall, ref)
+		if err != nil {
+			return err
+		}
+		tf = recall.Transform
+	}
+	if tf == nil {
+		return NewError(ErrBadArgs, "no transform to estimate")
+	}
+	if tf.ScriptFile() == nil && tf.ScriptBytes == nil && tf.ScriptPath != "" {
+		if err = tf.OpenScriptFile(ctx, r.node.Repo.Filesystem()); err != nil {
+			return err
+		}
+	}
+
+	est, err := base.EstimateTransformInputs(ctx, r.node.Repo, tf)
+	if err != nil {
+		return err
+	}
+	*res = *est
+	return nil
+}
@@ -0,0 +1,93 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestPreviewTransform(t *testing.T) {
+	node := newTestQriNode(t)
+	ref := addNowTransformDataset(t, node)
+	r := NewDatasetRequests(node, nil)
+
+	res := &PreviewTransformResponse{}
+	if err := r.PreviewTransform(&PreviewTransformParams{Ref: ref.AliasString()}, res); err != nil {
+		t.Fatal(err)
+	}
+	raw, ok := res.Dataset.Body.(json.RawMessage)
+	if !ok {
+		t.Fatalf("expected inlined json body, got: %T", res.Dataset.Body)
+	}
+	body := []interface{}{}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != 2 {
+		t.Errorf("expected preview to append one entry to the body. got %d entries", len(body))
+	}
+	if res.Dataset.PreviousPath != ref.Path {
+		t.Errorf("expected previous path to be %q, got: %q", ref.Path, res.Dataset.PreviousPath)
+	}
+
+	head, err := node.Repo.GetRef(repo.DatasetRef{Peername: ref.Peername, Name: ref.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Path != ref.Path {
+		t.Errorf("expected preview not to save. head moved from %q to %q", ref.Path, head.Path)
+	}
+
+	tf := &dataset.Transform{
+		Syntax:      "starlark",
+		ScriptBytes: []byte("def transform(ds, ctx):\n  print('previewing')\n  ds.set_body([['a']])\n"),
+	}
+	res = &PreviewTransformResponse{}
+	if err := r.PreviewTransform(&PreviewTransformParams{Ref: ref.AliasString(), Transform: tf}, res); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res.Output, "previewing") {
+		t.Errorf("expected script output to be captured, got: %q", res.Output)
+	}
+
+	if err := r.PreviewTransform(&PreviewTransformParams{Ref: "me/not_a_dataset"}, res); err == nil {
+		t.Error("expected previewing a missing dataset to error")
+	}
+}
+
+func TestEstimateTransform(t *testing.T) {
+	node := newTestQriNode(t)
+	ref := addNowTransformDataset(t, node)
+	r := NewDatasetRequests(node, nil)
+
+	tf := &dataset.Transform{
+		Syntax:      "starlark",
+		ScriptBytes: []byte(fmt.Sprintf("def transform(ds, ctx):\n  ds.set_body(load_dataset(%q).get_body())\n", ref.AliasString())),
+	}
+	res := &TransformEstimate{}
+	if err := r.EstimateTransform(&SaveParams{Ref: "me/estimated", Dataset: &dataset.Dataset{Transform: tf}}, res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Inputs) != 1 || res.Inputs[0].Ref != ref.AliasString() {
+		t.Fatalf("expected one dataset input, got: %v", res.Inputs)
+	}
+	if res.Inputs[0].Size <= 0 || res.Large() {
+		t.Errorf("expected a small, sized input. got: %v", res.Inputs[0])
+	}
+
+	// recalling the transform of the previous version
+	if err := r.EstimateTransform(&SaveParams{Ref: ref.AliasString(), Recall: "tf"}, res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Inputs) != 0 {
+		t.Errorf("expected no inputs, got: %v", res.Inputs)
+	}
+
+	if err := r.EstimateTransform(&SaveParams{Ref: ref.AliasString()}, res); err == nil {
+		t.Error("expected estimating a save without a transform to error")
+	}
+}
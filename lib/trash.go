@@ -0,0 +1,144 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/qri-io/iso8601"
+	"github.com/qri-io/qri/actions"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/repo"
+)
+
+// TrashEntry is a soft-deleted dataset reference
+type TrashEntry = base.TrashEntry
+
+// ErrNotInTrash indicates a dataset isn't in the trash
+var ErrNotInTrash = base.ErrNotInTrash
+
+// DefaultTrashRetention is how long soft-deleted datasets are kept in the
+// trash when repo.trashretention isn't configured
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// trash returns the trash soft-deleted datasets are kept in
+func (r *DatasetRequests) trash() (*base.Trash, error) {
+	if r.inst == nil || r.inst.repoPath == "" {
+		return nil, fmt.Errorf("soft delete requires a repo path to store the trash")
+	}
+	return base.NewTrash(filepath.Join(r.inst.repoPath, "trash.json")), nil
+}
+
+// trashRetention reads the trash retention window from config
+func (r *DatasetRequests) trashRetention() (time.Duration, error) {
+	if r.inst == nil || r.inst.cfg == nil || r.inst.cfg.Repo == nil || r.inst.cfg.Repo.TrashRetention == "" {
+		return DefaultTrashRetention, nil
+	}
+	d, err := iso8601.ParseDuration(r.inst.cfg.Repo.TrashRetention)
+	if err != nil {
+		return 0, fmt.Errorf("invalid repo.trashretention %q: %s", r.inst.cfg.Repo.TrashRetention, err)
+	}
+	return d.Duration, nil
+}
+
+// sweepTrash permanently removes datasets that have outlived the retention
+// window
+func (r *DatasetRequests) sweepTrash(ctx context.Context, trash *base.Trash) ([]TrashEntry, error) {
+	retention, err := r.trashRetention()
+	if err != nil {
+		return nil, err
+	}
+	swept, err := trash.Sweep(ctx, r.node.Repo, retention, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(swept) > 0 {
+		log.Debugf("swept %d dataset(s) from trash", len(swept))
+	}
+	return swept, nil
+}
+
+// ListTrash lists soft-deleted datasets, most recently removed first.
+// Datasets past the trash retention window are swept before listing
+func (r *DatasetRequests) ListTrash(p *ListParams, res *[]TrashEntry) error {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.ListTrash", p, res)
+	}
+	ctx := context.TODO()
+
+	trash, err := r.trash()
+	if err != nil {
+		return err
+	}
+	if _, err = r.sweepTrash(ctx, trash); err != nil {
+		return err
+	}
+
+	entries, err := trash.List()
+	if err != nil {
+		return err
+	}
+
+	if p.Offset > len(entries) {
+		p.Offset = len(entries)
+	}
+	entries = entries[p.Offset:]
+	if p.Limit > 0 && p.Limit < len(entries) {
+		entries = entries[:p.Limit]
+	}
+	*res = entries
+	return nil
+}
+
+// RestoreFromTrash restores a soft-deleted dataset by reference, putting it
+// back in the repo under its original name
+func (r *DatasetRequests) RestoreFromTrash(refstr *string, res *repo.DatasetRef) error {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.RestoreFromTrash", refstr, res)
+	}
+	ctx := context.TODO()
+
+	ref, err := repo.ParseDatasetRef(*refstr)
+	if err != nil {
+		return err
+	}
+	if err = repo.CanonicalizeProfile(r.node.Repo, &ref); err != nil {
+		return err
+	}
+
+	trash, err := r.trash()
+	if err != nil {
+		return err
+	}
+	if _, err = r.sweepTrash(ctx, trash); err != nil {
+		return err
+	}
+
+	restored, err := actions.RestoreTrashedDataset(ctx, r.node, trash, ref.AliasString())
+	if err != nil {
+		return err
+	}
+	*res = restored
+	return nil
+}
+
+// SweepTrash permanently removes soft-deleted datasets that have been in the
+// trash longer than the retention window, unpinning their content
+func (r *DatasetRequests) SweepTrash(in *bool, res *[]TrashEntry) error {
+	if r.cli != nil {
+		return r.cli.Call("DatasetRequests.SweepTrash", in, res)
+	}
+	ctx := context.TODO()
+
+	trash, err := r.trash()
+	if err != nil {
+		return err
+	}
+	swept, err := r.sweepTrash(ctx, trash)
+	if err != nil {
+		return err
+	}
+	*res = swept
+	return nil
+}
@@ -0,0 +1,149 @@
+package lib
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/p2p"
+	"github.com/qri-io/qri/repo"
+	testrepo "github.com/qri-io/qri/repo/test"
+	"github.com/qri-io/qri/rev"
+)
+
+func TestSoftRemoveAndRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestSoftRemoveAndRestore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	inst := NewInstanceFromConfigAndNode(config.DefaultConfigForTesting(), node)
+	inst.repoPath = dir
+	req := NewDatasetRequestsInstance(inst)
+
+	// soft delete requires removing the entire dataset
+	if err := req.Remove(&RemoveParams{Ref: "me/movies", Revision: rev.Rev{Field: "ds", Gen: 1}, Soft: true}, &RemoveResponse{}); err == nil {
+		t.Error("expected soft removing individual versions to error")
+	}
+
+	res := RemoveResponse{}
+	if err := req.Remove(&RemoveParams{Ref: "me/movies", Revision: rev.NewAllRevisions(), Soft: true}, &res); err != nil {
+		t.Fatal(err)
+	}
+	if !res.Trashed {
+		t.Error("expected remove response to report dataset was trashed")
+	}
+	if _, err := mr.GetRef(repo.DatasetRef{Peername: "peer", Name: "movies"}); err != repo.ErrNotFound {
+		t.Errorf("expected soft removed dataset to be removed from the repo. got: %v", err)
+	}
+
+	entries := []TrashEntry{}
+	if err := req.ListTrash(&ListParams{}, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Ref.AliasString() != "peer/movies" {
+		t.Fatalf("expected trash to contain peer/movies. got: %v", entries)
+	}
+
+	missing := "me/not_trashed"
+	if err := req.RestoreFromTrash(&missing, &repo.DatasetRef{}); err != ErrNotInTrash {
+		t.Errorf("expected restoring a dataset that isn't trashed to return ErrNotInTrash. got: %v", err)
+	}
+
+	refstr := "me/movies"
+	restored := repo.DatasetRef{}
+	if err := req.RestoreFromTrash(&refstr, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Path != entries[0].Ref.Path {
+		t.Errorf("restored path mismatch. want: %q, got: %q", entries[0].Ref.Path, restored.Path)
+	}
+	if _, err := mr.GetRef(repo.DatasetRef{Peername: "peer", Name: "movies"}); err != nil {
+		t.Errorf("expected restored dataset to be back in the repo. got: %s", err)
+	}
+	if err := req.ListTrash(&ListParams{}, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected trash to be empty after restore. got: %d entries", len(entries))
+	}
+
+	// restoring fails when a dataset with the same name has since been created
+	if err := req.Remove(&RemoveParams{Ref: "me/movies", Revision: rev.NewAllRevisions(), Soft: true}, &RemoveResponse{}); err != nil {
+		t.Fatal(err)
+	}
+	renamed := repo.DatasetRef{}
+	if err := req.Rename(&RenameParams{Current: repo.DatasetRef{Peername: "me", Name: "cities"}, New: repo.DatasetRef{Peername: "me", Name: "movies"}}, &renamed); err != nil {
+		t.Fatal(err)
+	}
+	if err := req.RestoreFromTrash(&refstr, &restored); err == nil {
+		t.Error("expected restoring over an existing dataset to error")
+	}
+}
+
+func TestSweepTrash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestSweepTrash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatalf("error allocating test repo: %s", err.Error())
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cfg := config.DefaultConfigForTesting()
+	cfg.Repo.TrashRetention = "P7D"
+	inst := NewInstanceFromConfigAndNode(cfg, node)
+	inst.repoPath = dir
+	req := NewDatasetRequestsInstance(inst)
+
+	trash := base.NewTrash(filepath.Join(dir, "trash.json"))
+	old := repo.DatasetRef{Peername: "peer", Name: "old", Path: "/map/QmOld"}
+	recent := repo.DatasetRef{Peername: "peer", Name: "recent", Path: "/map/QmRecent"}
+	if err := trash.Put(old, time.Now().Add(-8*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := trash.Put(recent, time.Now().Add(-6*24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	in := true
+	swept := []TrashEntry{}
+	if err := req.SweepTrash(&in, &swept); err != nil {
+		t.Fatal(err)
+	}
+	if len(swept) != 1 || swept[0].Ref.AliasString() != "peer/old" {
+		t.Errorf("expected only peer/old to be swept. got: %v", swept)
+	}
+
+	entries := []TrashEntry{}
+	if err := req.ListTrash(&ListParams{}, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Ref.AliasString() != "peer/recent" {
+		t.Errorf("expected peer/recent to remain in trash. got: %v", entries)
+	}
+
+	cfg.Repo.TrashRetention = "soon"
+	if err := req.SweepTrash(&in, &swept); err == nil {
+		t.Error("expected invalid trash retention to error")
+	}
+}
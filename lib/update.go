@@ -2,10 +2,12 @@ package lib
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"time"
 
+	"github.com/qri-io/ioes"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/actions"
 	"github.com/qri-io/qri/base"
@@ -44,6 +46,10 @@ type ScheduleParams struct {
 	Periodicity string
 	RepoPath    string
 
+	// Transform schedules a run of the dataset's transform script within qri
+	// instead of a save of the dataset
+	Transform bool
+
 	// SaveParams only applies to dataset saves
 	SaveParams *SaveParams
 }
@@ -133,12 +139,16 @@ func (m *UpdateMethods) jobFromScheduleParams(ctx context.Context, p *SchedulePa
 			Force:               p.SaveParams.Force,
 			ConvertFormatToPrev: p.SaveParams.ConvertFormatToPrev,
 			ShouldRender:        p.SaveParams.ShouldRender,
+			DryRun:              p.SaveParams.DryRun,
 			Secrets:             p.SaveParams.Secrets,
 			// TODO (b5) not fully supported yet:
 			// Config: p.SaveParams.
 		}
 	}
 
+	if p.Transform {
+		return update.TransformToJob(ref.Dataset, p.Periodicity, o)
+	}
 	return update.DatasetToJob(ref.Dataset, p.Periodicity, o)
 }
 
@@ -200,6 +210,129 @@ func (m *UpdateMethods) Logs(p *ListParams, res *[]*Job) error {
 	return nil
 }
 
+// History lists the logged runs of a job by job name, newest first, with the
+// configured log retention policy applied
+func (m *UpdateMethods) History(name *string, res *[]*Job) error {
+	// this context is scoped to the scheduling request. currently not cancellable
+	// because our lib methods don't accept a context themselves
+	// TODO (b5): refactor RPC communication to use context
+	var ctx = context.Background()
+
+	jobs, err := m.inst.cron.JobHistory(ctx, *name)
+	if err != nil {
+		return err
+	}
+
+	*res = jobs
+	return nil
+}
+
+// Job run statuses
+const (
+	// JobRunRunning is the status of a run that hasn't stopped
+	JobRunRunning = "running"
+	// JobRunSucceeded is the status of a run that stopped without error
+	JobRunSucceeded = "succeeded"
+	// JobRunFailed is the status of a run that stopped with an error
+	JobRunFailed = "failed"
+	// JobRunUnknown is the status of a run that didn't record stopping within
+	// MaxRunDuration, like a run interrupted by a restart
+	JobRunUnknown = "unknown"
+)
+
+// MaxRunDuration is how long a run that hasn't recorded stopping is reported
+// as running
+const MaxRunDuration = time.Hour * 24
+
+// MaxRunOutput is the number of bytes of captured output included with a job
+// run. Longer output is truncated to its last MaxRunOutput bytes, where
+// errors usually are
+const MaxRunOutput = 64 << 10
+
+// JobRun is a logged run of a job with its duration & outcome
+type JobRun struct {
+	*Job
+	// DurationMs is the length of the run in milliseconds, 0 for runs that
+	// haven't stopped
+	DurationMs int64 `json:"durationMs"`
+	// Status is one of "running", "succeeded", "failed" or "unknown"
+	Status string `json:"status"`
+	// Output is the tail of the captured output of the run
+	Output string `json:"output,omitempty"`
+}
+
+// newJobRun describes a logged job run as of now
+func newJobRun(job *Job, now time.Time) *JobRun {
+	run := &JobRun{Job: job, Status: JobRunRunning}
+	if job.RunStop.IsZero() {
+		if now.Sub(job.RunStart) > MaxRunDuration {
+			run.Status = JobRunUnknown
+		}
+		return run
+	}
+
+	run.DurationMs = int64(job.RunStop.Sub(job.RunStart) / time.Millisecond)
+	run.Status = JobRunSucceeded
+	if job.RunError != "" {
+		run.Status = JobRunFailed
+	}
+	return run
+}
+
+// RunLogParams defines parameters for listing job runs
+type RunLogParams struct {
+	ListParams
+	// Output includes the captured output of each run
+	Output bool
+}
+
+// RunLogs lists logged job runs with their duration, status & optionally
+// captured output
+func (m *UpdateMethods) RunLogs(p *RunLogParams, res *[]*JobRun) error {
+	// this context is scoped to the scheduling request. currently not cancellable
+	// because our lib methods don't accept a context themselves
+	// TODO (b5): refactor RPC communication to use context
+	var ctx = context.Background()
+
+	jobs, err := m.inst.cron.ListLogs(ctx, p.Offset, p.Limit)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	runs := make([]*JobRun, len(jobs))
+	for i, job := range jobs {
+		runs[i] = newJobRun(job, now)
+		if p.Output {
+			if runs[i].Output, err = m.runOutput(ctx, job); err != nil {
+				return err
+			}
+		}
+	}
+
+	*res = runs
+	return nil
+}
+
+// runOutput reads the tail of a job run's log file. logged runs are named by
+// their log name
+func (m *UpdateMethods) runOutput(ctx context.Context, job *Job) (string, error) {
+	f, err := m.inst.cron.LogFile(ctx, job.Name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	if len(data) > MaxRunOutput {
+		data = data[len(data)-MaxRunOutput:]
+	}
+	return string(data), nil
+}
+
 // LogFile reads log file data for a given logName
 func (m *UpdateMethods) LogFile(logName *string, data *[]byte) error {
 	f, err := m.inst.cron.LogFile(context.Background(), *logName)
@@ -214,6 +347,35 @@ func (m *UpdateMethods) LogFile(logName *string, data *[]byte) error {
 	return err
 }
 
+// SchedulerHealth aliases cron.Health, describing the state of the update
+// scheduler
+type SchedulerHealth = cron.Health
+
+// HealthParams configures a scheduler health check
+type HealthParams struct {
+	// RecentRuns is the number of logged runs to report, defaults to
+	// cron.DefaultHealthRecentRuns
+	RecentRuns int
+}
+
+// Health reports the state of the update scheduler: whether it's running, the
+// number of scheduled jobs, recent run outcomes & jobs whose last run failed.
+// Health returns cron.ErrUnreachable if the scheduler can't be contacted
+func (m *UpdateMethods) Health(p *HealthParams, res *SchedulerHealth) error {
+	// this context is scoped to the health request. currently not cancellable
+	// because our lib methods don't accept a context themselves
+	// TODO (b5): refactor RPC communication to use context
+	var ctx = context.Background()
+
+	h, err := cron.CheckHealth(ctx, m.inst.cron, p.RecentRuns)
+	if err != nil {
+		return err
+	}
+
+	*res = *h
+	return nil
+}
+
 // ServiceStatus describes the current state of a service
 type ServiceStatus struct {
 	Name       string
@@ -267,7 +429,7 @@ func (m *UpdateMethods) ServiceStart(p *UpdateServiceStartParams, started *bool)
 	}
 
 	*started = true
-	return update.Start(p.Ctx, p.RepoPath, p.UpdateCfg, p.Daemonize)
+	return update.Start(p.Ctx, p.RepoPath, p.UpdateCfg, p.Daemonize, newServiceUpdateFactory(p.RepoPath))
 }
 
 // ServiceStop halts the scheduler
@@ -291,7 +453,7 @@ func (m *UpdateMethods) Run(p *Job, res *repo.DatasetRef) (err error) {
 			return
 		}
 		p.Type = cron.JTShellScript
-	} else {
+	} else if p.Type != cron.JTTransform {
 		p.Type = cron.JTDataset
 	}
 
@@ -306,30 +468,12 @@ func (m *UpdateMethods) Run(p *Job, res *repo.DatasetRef) (err error) {
 
 	switch p.Type {
 	case cron.JTDataset:
-		params := &SaveParams{
-			Ref: p.Name,
-		}
-		if o, ok := p.Options.(*cron.DatasetOptions); ok {
-			params = &SaveParams{
-				Ref:                 p.Name,
-				Title:               o.Title,
-				Message:             o.Message,
-				Recall:              o.Recall,
-				BodyPath:            o.BodyPath,
-				FilePaths:           o.FilePaths,
-				Publish:             o.Publish,
-				Force:               o.Force,
-				ConvertFormatToPrev: o.ConvertFormatToPrev,
-				ShouldRender:        o.ShouldRender,
-				Secrets:             o.Secrets,
-
-				// TODO (b5) not fully supported yet:
-				// Strict: o.Strict,
-				// Config: o.Config
-			}
-		}
 		*res = repo.DatasetRef{}
-		err = m.runDatasetUpdate(ctx, params, res)
+		err = m.runDatasetUpdate(ctx, jobSaveParams(p), res)
+
+	case cron.JTTransform:
+		*res = repo.DatasetRef{}
+		err = m.runTransformUpdate(ctx, jobSaveParams(p), res)
 
 	case cron.JTShellScript:
 		return update.JobToCmd(m.inst.streams, p).Run()
@@ -348,6 +492,34 @@ func (m *UpdateMethods) Run(p *Job, res *repo.DatasetRef) (err error) {
 	return m.inst.Repo().LogEvent(repo.ETCronJobRan, *res)
 }
 
+// jobSaveParams creates save parameters from a dataset or transform job
+func jobSaveParams(j *Job) *SaveParams {
+	params := &SaveParams{
+		Ref: j.Name,
+	}
+	if o, ok := j.Options.(*cron.DatasetOptions); ok {
+		params = &SaveParams{
+			Ref:                 j.Name,
+			Title:               o.Title,
+			Message:             o.Message,
+			Recall:              o.Recall,
+			BodyPath:            o.BodyPath,
+			FilePaths:           o.FilePaths,
+			Publish:             o.Publish,
+			Force:               o.Force,
+			ConvertFormatToPrev: o.ConvertFormatToPrev,
+			ShouldRender:        o.ShouldRender,
+			DryRun:              o.DryRun,
+			Secrets:             o.Secrets,
+
+			// TODO (b5) not fully supported yet:
+			// Strict: o.Strict,
+			// Config: o.Config
+		}
+	}
+	return params
+}
+
 func absolutizeJobFilepaths(j *Job) error {
 	if o, ok := j.Options.(*cron.DatasetOptions); ok {
 		if err := qfs.AbsPath(&o.BodyPath); err != nil {
@@ -388,3 +560,97 @@ func (m *UpdateMethods) runDatasetUpdate(ctx context.Context, p *SaveParams, res
 	dsr := NewDatasetRequestsInstance(m.inst)
 	return dsr.Save(p, res)
 }
+
+// runTransformUpdate re-runs the most recent transform script in a dataset's
+// history, saving the result as a new version
+func (m *UpdateMethods) runTransformUpdate(ctx context.Context, p *SaveParams, res *repo.DatasetRef) error {
+	ref, err := repo.ParseDatasetRef(p.Ref)
+	if err != nil {
+		return err
+	}
+	if err = repo.CanonicalizeDatasetRef(m.inst.node.Repo, &ref); err == repo.ErrNotFound {
+		return fmt.Errorf("unknown dataset '%s'. please add before updating", ref.AliasString())
+	} else if err != nil {
+		return err
+	}
+
+	recalled, err := actions.Recall(ctx, m.inst.node, "tf", ref)
+	if err != nil || recalled.Transform == nil {
+		return fmt.Errorf("dataset %s has no transform to run", ref.AliasString())
+	}
+
+	p.Recall = "tf"
+	dsr := NewDatasetRequestsInstance(m.inst)
+	return dsr.Save(p, res)
+}
+
+// newUpdateFactory creates a cron job runner factory that executes transform
+// jobs in-process with the given instance, delegating all other job types to
+// update.Factory
+func newUpdateFactory(inst *Instance) cron.RunJobFactory {
+	return transformJobFactory(func(context.Context) (*Instance, func(), error) {
+		return inst, func() {}, nil
+	})
+}
+
+// newServiceUpdateFactory creates a cron job runner factory for the update
+// service, which runs without an instance. Each transform job opens an
+// instance on the repo at repoPath for the duration of the run
+func newServiceUpdateFactory(repoPath string) cron.RunJobFactory {
+	return transformJobFactory(func(ctx context.Context) (*Instance, func(), error) {
+		inst, err := NewInstance(ctx, repoPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return inst, inst.Teardown, nil
+	})
+}
+
+// transformJobFactory creates a cron job runner factory that saves transform
+// jobs through lib with the instance returned by getInst. done is called once
+// the job finishes
+func transformJobFactory(getInst func(ctx context.Context) (inst *Instance, done func(), err error)) cron.RunJobFactory {
+	return func(ctx context.Context) cron.RunJobFunc {
+		runCmd := update.Factory(ctx)
+		return func(ctx context.Context, streams ioes.IOStreams, job *cron.Job) error {
+			if job.Type != cron.JTTransform {
+				return runCmd(ctx, streams, job)
+			}
+
+			inst, done, err := getInst(ctx)
+			if err != nil {
+				return err
+			}
+			defer done()
+			return runTransformJob(inst, streams, job)
+		}
+	}
+}
+
+// runTransformJob saves a new version of a job's dataset by re-running its
+// transform script
+func runTransformJob(inst *Instance, streams ioes.IOStreams, job *cron.Job) error {
+	log.Debugf("running transform update: %s", job.Name)
+	p := jobSaveParams(job)
+	p.Recall = "tf"
+	if inst.rpc == nil {
+		p.ScriptOutput = streams.Out
+	}
+
+	res := &repo.DatasetRef{}
+	if err := NewDatasetRequestsInstance(inst).Save(p, res); err != nil {
+		return err
+	}
+	if job.DryRun() && res.Dataset != nil {
+		data, err := json.Marshal(res.Dataset)
+		if err != nil {
+			return err
+		}
+		job.DryRunResult = string(data)
+		return nil
+	}
+	if r := inst.Repo(); r != nil {
+		return r.LogEvent(repo.ETCronJobRan, *res)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"testing"
 	"time"
 
@@ -88,6 +89,17 @@ func TestUpdateMethods(t *testing.T) {
 		t.Errorf("expected 1 log entry, got: %d", len(res))
 	}
 
+	runs := []*JobRun{}
+	if err := m.RunLogs(&RunLogParams{ListParams: ListParams{Offset: 0, Limit: -1}, Output: true}, &runs); err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 job run, got: %d", len(runs))
+	}
+	if runs[0].Status == JobRunRunning || runs[0].DurationMs < 0 {
+		t.Errorf("expected a stopped job run. got status %q, duration: %dms", runs[0].Status, runs[0].DurationMs)
+	}
+
 	if err := m.List(&ListParams{Offset: 0, Limit: -1}, &res); err != nil {
 		t.Fatal(err)
 	}
@@ -100,6 +112,20 @@ func TestUpdateMethods(t *testing.T) {
 		t.Error(err)
 	}
 
+	health := &SchedulerHealth{}
+	if err := m.Health(&HealthParams{}, health); err != nil {
+		t.Fatal(err)
+	}
+	if health.Running {
+		t.Error("expected scheduler to be stopped after its context completes")
+	}
+	if health.JobCount != 2 {
+		t.Errorf("expected health to report 2 jobs, got: %d", health.JobCount)
+	}
+	if len(health.RecentRuns) != 1 {
+		t.Errorf("expected health to report 1 recent run, got: %d", len(health.RecentRuns))
+	}
+
 	shName := "testdata/hello.sh"
 	var fin bool
 	if err := m.Unschedule(&shName, &fin); err != nil {
@@ -108,6 +134,26 @@ func TestUpdateMethods(t *testing.T) {
 
 }
 
+func TestJobRunStatus(t *testing.T) {
+	start := time.Date(2001, 1, 1, 1, 0, 0, 0, time.UTC)
+	cases := []struct {
+		job      *Job
+		status   string
+		duration int64
+	}{
+		{&Job{RunStart: start}, JobRunRunning, 0},
+		{&Job{RunStart: start.Add(-MaxRunDuration - time.Second)}, JobRunUnknown, 0},
+		{&Job{RunStart: start, RunStop: start.Add(1500 * time.Millisecond)}, JobRunSucceeded, 1500},
+		{&Job{RunStart: start, RunStop: start.Add(time.Second), RunError: "exit status 1"}, JobRunFailed, 1000},
+	}
+	for i, c := range cases {
+		run := newJobRun(c.job, start.Add(time.Minute))
+		if run.Status != c.status || run.DurationMs != c.duration {
+			t.Errorf("case %d mismatch. expected: %s %dms, got: %s %dms", i, c.status, c.duration, run.Status, run.DurationMs)
+		}
+	}
+}
+
 func TestUpdateServiceStart(t *testing.T) {
 	inst := &Instance{}
 	m := NewUpdateMethods(inst)
@@ -166,4 +212,50 @@ func TestDatasetMethodsRun(t *testing.T) {
 	if err := m.Run(&Job{Name: res.AliasString(), Type: cron.JTDataset /* ReturnBody: true */}, res); err != nil {
 		t.Error(err)
 	}
+
+	if err := m.Run(&Job{Name: res.AliasString(), Type: cron.JTTransform}, res); err != nil {
+		t.Errorf("transform update error: %s", err)
+	}
+	if err := m.Run(&Job{Name: "me/movies", Type: cron.JTTransform}, res); err == nil {
+		t.Error("expected transform update of a dataset without a transform to error")
+	}
+}
+
+func TestUpdateFactoryTransform(t *testing.T) {
+	node := newTestQriNode(t)
+	inst := &Instance{node: node, repo: node.Repo}
+	ref := addNowTransformDataset(t, node)
+
+	// transform jobs run through lib, a qri binary must not be required
+	prevPath := os.Getenv("PATH")
+	os.Setenv("PATH", "")
+	defer os.Setenv("PATH", prevPath)
+	if _, err := exec.LookPath("qri"); err == nil {
+		t.Fatal("expected no qri binary on PATH")
+	}
+
+	run := newUpdateFactory(inst)(context.Background())
+	job := &Job{Name: ref.AliasString(), Type: cron.JTTransform}
+	if err := run(context.Background(), ioes.NewDiscardIOStreams(), job); err != nil {
+		t.Fatal(err)
+	}
+	saved := repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}
+	if err := repo.CanonicalizeDatasetRef(node.Repo, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Path == ref.Path {
+		t.Error("expected transform job to save a new version")
+	}
+
+	m := NewUpdateMethods(inst)
+	if _, err := m.jobFromScheduleParams(context.Background(), &ScheduleParams{Name: "me/movies", Periodicity: "R/P1D", Transform: true}); err == nil {
+		t.Error("expected scheduling a transform job for a dataset without a transform to error")
+	}
+	job, err := m.jobFromScheduleParams(context.Background(), &ScheduleParams{Name: ref.AliasString(), Periodicity: "R/P1D", Transform: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Type != cron.JTTransform {
+		t.Errorf("expected job type %q, got: %q", cron.JTTransform, job.Type)
+	}
 }
@@ -0,0 +1,64 @@
+package lib
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/qri-io/qri/p2p"
+)
+
+// GitCommit is the git commit qri was built from. It's set at build time with
+// linker flags, see the build target in the project Makefile
+var GitCommit = ""
+
+// versionDependencies are the modules reported in VersionInfo.Dependencies
+var versionDependencies = []string{
+	"github.com/ipfs/go-ipfs",
+	"github.com/libp2p/go-libp2p",
+	"github.com/qri-io/dataset",
+	"github.com/qri-io/qfs",
+}
+
+// VersionInfo describes the build of qri that's running
+type VersionInfo struct {
+	Version     string `json:"version"`
+	GitCommit   string `json:"gitCommit"`
+	GoVersion   string `json:"goVersion"`
+	Platform    string `json:"platform"`
+	ProtocolTag string `json:"protocolTag"`
+	// Dependencies maps module paths of key dependencies to their versions
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// Version returns details of the running qri build. Dependency versions are
+// read from module build info, & are reported as "unknown" when build info
+// isn't available
+func Version() *VersionInfo {
+	v := &VersionInfo{
+		Version:      VersionNumber,
+		GitCommit:    GitCommit,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		ProtocolTag:  p2p.QriServiceTag,
+		Dependencies: map[string]string{},
+	}
+	if v.GitCommit == "" {
+		v.GitCommit = "unknown"
+	}
+
+	for _, mod := range versionDependencies {
+		v.Dependencies[mod] = "unknown"
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if _, ok := v.Dependencies[dep.Path]; ok {
+				version := dep.Version
+				if dep.Replace != nil {
+					version = dep.Replace.Version
+				}
+				v.Dependencies[dep.Path] = version
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,38 @@
+package lib
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/qri-io/qri/p2p"
+)
+
+func TestVersion(t *testing.T) {
+	prev := GitCommit
+	defer func() { GitCommit = prev }()
+
+	GitCommit = ""
+	v := Version()
+	if v.Version != VersionNumber {
+		t.Errorf("version mismatch. want: %q, got: %q", VersionNumber, v.Version)
+	}
+	if v.GitCommit != "unknown" {
+		t.Errorf("expected missing git commit to be reported as unknown. got: %q", v.GitCommit)
+	}
+	if v.GoVersion != runtime.Version() {
+		t.Errorf("go version mismatch. want: %q, got: %q", runtime.Version(), v.GoVersion)
+	}
+	if v.ProtocolTag != p2p.QriServiceTag {
+		t.Errorf("protocol tag mismatch. want: %q, got: %q", p2p.QriServiceTag, v.ProtocolTag)
+	}
+	for _, mod := range versionDependencies {
+		if v.Dependencies[mod] == "" {
+			t.Errorf("expected a version for dependency %s", mod)
+		}
+	}
+
+	GitCommit = "abc1234"
+	if v = Version(); v.GitCommit != "abc1234" {
+		t.Errorf("git commit mismatch. want: %q, got: %q", "abc1234", v.GitCommit)
+	}
+}
@@ -0,0 +1,80 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	"github.com/qri-io/qri/repo"
+	"github.com/qri-io/qri/repo/profile"
+)
+
+// MtDatasetAnnounce announces a newly saved or published dataset to connected
+// qri peers, letting peers learn of datasets without polling event logs
+const MtDatasetAnnounce = MsgType("dataset_announce")
+
+// AnnounceDataset tells connected qri peers a dataset is available. Only
+// reference details are sent, local-only fields like FSIPath are dropped
+func (n *QriNode) AnnounceDataset(ctx context.Context, ref repo.DatasetRef) error {
+	pids := n.ConnectedQriPeerIDs()
+	log.Debugf("%s AnnounceDataset %s to %d peers", n.ID, ref, len(pids))
+	if len(pids) == 0 {
+		return nil
+	}
+
+	announced := repo.DatasetRef{
+		Peername:  ref.Peername,
+		ProfileID: ref.ProfileID,
+		Name:      ref.Name,
+		Path:      ref.Path,
+		Published: ref.Published,
+	}
+	msg, err := NewJSONBodyMessage(n.ID, MtDatasetAnnounce, announced)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := n.SendMessage(ctx, msg, nil, pids...); err != nil {
+			log.Debugf("send dataset announce message error: %s", err.Error())
+		}
+	}()
+	return nil
+}
+
+func (n *QriNode) handleDatasetAnnounce(ws *WrappedStream, msg Message) (hangup bool) {
+	hangup = true
+
+	ref := repo.DatasetRef{}
+	if err := json.Unmarshal(msg.Body, &ref); err != nil {
+		log.Debugf("%s %s", n.ID, err.Error())
+		return
+	}
+
+	if !n.announcedByOwner(msg.Initiator, ref) {
+		log.Debugf("%s ignoring announcement of %s from %s, peer doesn't own the dataset", n.ID, ref, msg.Initiator)
+		return
+	}
+
+	rc, ok := n.Repo.(repo.RefCacher)
+	if !ok {
+		return
+	}
+	if err := rc.RefCache().PutRef(ref); err != nil {
+		log.Debugf("%s caching announced ref %s: %s", n.ID, ref, err.Error())
+	}
+	return
+}
+
+// announcedByOwner checks a peer announcing a dataset is the dataset's
+// author, either directly or as a known peer of the author's profile
+func (n *QriNode) announcedByOwner(pid peer.ID, ref repo.DatasetRef) bool {
+	if ref.ProfileID == "" {
+		return false
+	}
+	if profile.ID(pid) == ref.ProfileID {
+		return true
+	}
+	pro, err := n.Repo.Profiles().PeerProfile(pid)
+	return err == nil && pro.ID == ref.ProfileID
+}
@@ -0,0 +1,100 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	p2ptest "github.com/qri-io/qri/p2p/test"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestAnnounceDataset(t *testing.T) {
+	ctx := context.Background()
+	f := p2ptest.NewTestNodeFactory(NewTestableQriNode)
+	testPeers, err := p2ptest.NewTestNetwork(ctx, f, 3)
+	if err != nil {
+		t.Fatalf("error creating network: %s", err.Error())
+	}
+	if err := p2ptest.ConnectQriNodes(ctx, testPeers); err != nil {
+		t.Fatalf("error connecting peers: %s", err.Error())
+	}
+	peers := asQriNodes(testPeers)
+
+	pro, err := peers[0].Repo.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := repo.DatasetRef{
+		Peername:  pro.Peername,
+		ProfileID: pro.ID,
+		Name:      "announced",
+		Path:      "/map/QmAnnounced",
+		FSIPath:   "/local/only/path",
+		Published: true,
+	}
+	if err := peers[0].AnnounceDataset(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range peers[1:] {
+		rc, ok := p.Repo.(repo.RefCacher)
+		if !ok {
+			t.Fatalf("expected test repo to be a RefCacher")
+		}
+
+		var got repo.DatasetRef
+		deadline := time.Now().Add(time.Second * 2)
+		for time.Now().Before(deadline) {
+			if got, err = rc.RefCache().GetRef(repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}); err == nil {
+				break
+			}
+			time.Sleep(time.Millisecond * 20)
+		}
+		if err != nil {
+			t.Errorf("%s expected announced ref to be cached. got: %s", p.ID, err)
+			continue
+		}
+		if got.Path != ref.Path {
+			t.Errorf("%s cached path mismatch. expected: %s, got: %s", p.ID, ref.Path, got.Path)
+		}
+		if got.FSIPath != "" {
+			t.Errorf("%s expected local-only fields not to be announced. got FSIPath: %s", p.ID, got.FSIPath)
+		}
+	}
+}
+
+func TestAnnounceDatasetRequiresOwner(t *testing.T) {
+	ctx := context.Background()
+	f := p2ptest.NewTestNodeFactory(NewTestableQriNode)
+	testPeers, err := p2ptest.NewTestNetwork(ctx, f, 2)
+	if err != nil {
+		t.Fatalf("error creating network: %s", err.Error())
+	}
+	if err := p2ptest.ConnectQriNodes(ctx, testPeers); err != nil {
+		t.Fatalf("error connecting peers: %s", err.Error())
+	}
+	peers := asQriNodes(testPeers)
+
+	// peer 0 announces a dataset in peer 1's name
+	pro, err := peers[1].Repo.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := repo.DatasetRef{
+		Peername:  pro.Peername,
+		ProfileID: pro.ID,
+		Name:      "forged",
+		Path:      "/map/QmForged",
+	}
+	msg, err := NewJSONBodyMessage(peers[0].ID, MtDatasetAnnounce, forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peers[1].handleDatasetAnnounce(nil, msg)
+
+	rc := peers[1].Repo.(repo.RefCacher)
+	if _, err := rc.RefCache().GetRef(repo.DatasetRef{Peername: forged.Peername, Name: forged.Name}); err != repo.ErrNotFound {
+		t.Errorf("expected announcement from a peer that doesn't own the dataset to be ignored. got: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 
@@ -23,6 +24,10 @@ func (n *QriNode) Bootstrap(boostrapAddrs []string, boostrapPeers chan pstore.Pe
 
 	pinfos := toPeerInfos(peers)
 	for _, p := range randomSubsetOfPeers(pinfos, 4) {
+		if n.PeerGated(p.ID) {
+			log.Infof("skipping gated bootstrap peer %s", p.ID.Pretty())
+			continue
+		}
 		go func(p pstore.PeerInfo) {
 			log.Debugf("boostrapping to: %s", p.ID.Pretty())
 			if err := n.host.Connect(context.Background(), p); err == nil {
@@ -47,6 +52,28 @@ func (n *QriNode) BootstrapIPFS() {
 	}
 }
 
+// Reconnect re-dials bootstrap & known peers for an online node that has lost
+// its connections. Dialing happens in the background, callers should check
+// for connected peers to know when the node is back on the network
+func (n *QriNode) Reconnect() error {
+	if !n.Online {
+		return fmt.Errorf("node is not online")
+	}
+
+	go n.DiscoverPeerstoreQriPeers(n.host.Peerstore())
+	go n.Bootstrap(n.cfg.QriBootstrapAddrs, make(chan pstore.PeerInfo, len(n.cfg.QriBootstrapAddrs)))
+	go n.BootstrapIPFS()
+	return nil
+}
+
+// HasPeers returns true if the node is connected to at least one peer
+func (n *QriNode) HasPeers() bool {
+	if !n.Online || n.host == nil {
+		return false
+	}
+	return len(n.host.Network().Peers()) > 0
+}
+
 // ParseMultiaddrs turns a slice of strings into a slice of Multiaddrs
 func ParseMultiaddrs(addrs []string) (maddrs []ma.Multiaddr, err error) {
 	maddrs = make([]ma.Multiaddr, len(addrs))
@@ -0,0 +1,108 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	net "github.com/libp2p/go-libp2p-net"
+	peer "github.com/libp2p/go-libp2p-peer"
+	ping "github.com/libp2p/go-libp2p/p2p/protocol/ping"
+)
+
+// PingTimeout is the longest ConnectionInfo waits for a ping response before
+// falling back to the last recorded latency for a peer
+var PingTimeout = time.Second * 2
+
+// PeerConnection describes an open connection to a peer
+type PeerConnection struct {
+	PeerID string `json:"peerID"`
+	// Addr is the multiaddress of the remote end of the connection
+	Addr string `json:"addr"`
+	// Direction is "inbound" if the peer opened the connection, "outbound" if
+	// we did, empty if unknown
+	Direction string `json:"direction"`
+	// QriSupport is true if the peer is known to speak the qri protocol
+	QriSupport bool `json:"qriSupport"`
+	// LatencyMs is the round trip time to the peer in milliseconds, zero if
+	// the peer couldn't be pinged & no latency has been recorded
+	LatencyMs float64 `json:"latencyMs,omitempty"`
+	// Tags lists connection manager tags for the peer
+	Tags map[string]int `json:"tags,omitempty"`
+}
+
+// ConnectionInfo describes each open connection, pinging connected peers
+// concurrently to measure latency
+func (n *QriNode) ConnectionInfo(ctx context.Context) []PeerConnection {
+	if n.host == nil {
+		return []PeerConnection{}
+	}
+
+	conns := n.host.Network().Conns()
+	res := make([]PeerConnection, len(conns))
+	wg := sync.WaitGroup{}
+	for i, c := range conns {
+		pid := c.RemotePeer()
+		res[i] = PeerConnection{
+			PeerID:     pid.Pretty(),
+			Addr:       c.RemoteMultiaddr().String(),
+			Direction:  connDirection(c.Stat().Direction),
+			QriSupport: n.supportsQri(pid),
+		}
+		if ti := n.host.ConnManager().GetTagInfo(pid); ti != nil && len(ti.Tags) > 0 {
+			res[i].Tags = ti.Tags
+		}
+
+		wg.Add(1)
+		go func(pc *PeerConnection, pid peer.ID) {
+			defer wg.Done()
+			pc.LatencyMs = durationMs(n.latency(ctx, pid))
+		}(&res[i], pid)
+	}
+	wg.Wait()
+
+	return res
+}
+
+// latency pings a peer, falling back to the peerstore's recorded average
+func (n *QriNode) latency(ctx context.Context, pid peer.ID) time.Duration {
+	ctx, cancel := context.WithTimeout(ctx, PingTimeout)
+	defer cancel()
+
+	select {
+	case res, ok := <-ping.Ping(ctx, n.host, pid):
+		if ok && res.Error == nil {
+			return res.RTT
+		}
+		if ok {
+			log.Debugf("pinging %s: %s", pid, res.Error)
+		}
+	case <-ctx.Done():
+	}
+	return n.host.Peerstore().LatencyEWMA(pid)
+}
+
+// supportsQri checks the peerstore flag set when upgrading connections
+func (n *QriNode) supportsQri(pid peer.ID) bool {
+	support, err := n.host.Peerstore().Get(pid, qriSupportKey)
+	if err != nil {
+		return false
+	}
+	ok, _ := support.(bool)
+	return ok
+}
+
+func connDirection(d net.Direction) string {
+	switch d {
+	case net.DirInbound:
+		return "inbound"
+	case net.DirOutbound:
+		return "outbound"
+	default:
+		return ""
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,44 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qri-io/qri/p2p/test"
+)
+
+func TestConnectionInfo(t *testing.T) {
+	ctx := context.Background()
+	f := p2ptest.NewTestNodeFactory(NewTestableQriNode)
+	testPeers, err := p2ptest.NewTestNetwork(ctx, f, 2)
+	if err != nil {
+		t.Fatalf("error creating network: %s", err.Error())
+	}
+	if err := p2ptest.ConnectQriNodes(ctx, testPeers); err != nil {
+		t.Fatalf("error connecting peers: %s", err.Error())
+	}
+	peers := asQriNodes(testPeers)
+
+	prevTimeout := PingTimeout
+	PingTimeout = time.Millisecond * 500
+	defer func() { PingTimeout = prevTimeout }()
+
+	conns := peers[0].ConnectionInfo(ctx)
+	if len(conns) != 1 {
+		t.Fatalf("expected 1 connection, got: %d", len(conns))
+	}
+	if conns[0].PeerID != peers[1].ID.Pretty() {
+		t.Errorf("peer id mismatch. expected: %s, got: %s", peers[1].ID.Pretty(), conns[0].PeerID)
+	}
+	if !conns[0].QriSupport {
+		t.Error("expected connected qri node to support the qri protocol")
+	}
+	if conns[0].Addr == "" {
+		t.Error("expected connection address to be set")
+	}
+
+	if got := (&QriNode{}).ConnectionInfo(ctx); len(got) != 0 {
+		t.Errorf("expected offline node to have no connections, got: %d", len(got))
+	}
+}
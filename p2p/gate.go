@@ -0,0 +1,88 @@
+package p2p
+
+import (
+	"fmt"
+	"sync"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	"github.com/qri-io/qri/config"
+)
+
+// ErrPeerGated is returned when connecting to a peer that's excluded by the
+// node's block or allow lists
+var ErrPeerGated = fmt.Errorf("peer is gated by block or allow list")
+
+// peerGate decides which peers a node may connect with
+type peerGate struct {
+	lk      sync.RWMutex
+	blocked map[peer.ID]bool
+	allowed map[peer.ID]bool
+}
+
+func newPeerGate(cfg *config.P2P) *peerGate {
+	g := &peerGate{}
+	g.update(cfg)
+	return g
+}
+
+// update replaces gate lists with those specified in cfg. invalid peer IDs are
+// logged & skipped
+func (g *peerGate) update(cfg *config.P2P) {
+	blocked := map[peer.ID]bool{}
+	allowed := map[peer.ID]bool{}
+	if cfg != nil {
+		addPeerIDs(blocked, cfg.BlockedPeers)
+		addPeerIDs(allowed, cfg.AllowedPeers)
+	}
+
+	g.lk.Lock()
+	g.blocked = blocked
+	g.allowed = allowed
+	g.lk.Unlock()
+}
+
+func addPeerIDs(set map[peer.ID]bool, ids []string) {
+	for _, idStr := range ids {
+		id, err := peer.IDB58Decode(idStr)
+		if err != nil {
+			log.Errorf("invalid peer id in p2p gate list %q: %s", idStr, err)
+			continue
+		}
+		set[id] = true
+	}
+}
+
+// gated returns true if connections to a peer aren't permitted. blocked peers
+// are always gated, a non-empty allow list gates all peers not on it
+func (g *peerGate) gated(id peer.ID) bool {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+
+	if g.blocked[id] {
+		return true
+	}
+	return len(g.allowed) > 0 && !g.allowed[id]
+}
+
+// PeerGated returns true if the node's block & allow lists prevent connecting
+// to a peer
+func (n *QriNode) PeerGated(id peer.ID) bool {
+	return n.gate.gated(id)
+}
+
+// UpdatePeerGate replaces the node's block & allow lists with those in cfg,
+// closing any open connections to peers that are now gated
+func (n *QriNode) UpdatePeerGate(cfg *config.P2P) {
+	n.gate.update(cfg)
+	if n.host == nil {
+		return
+	}
+	for _, conn := range n.host.Network().Conns() {
+		if pid := conn.RemotePeer(); n.gate.gated(pid) {
+			log.Infof("closing connection to gated peer %s", pid.Pretty())
+			if err := conn.Close(); err != nil {
+				log.Debugf("closing gated connection: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	pstore "github.com/libp2p/go-libp2p-peerstore"
+	"github.com/qri-io/qri/config"
+	p2ptest "github.com/qri-io/qri/p2p/test"
+)
+
+func TestPeerGate(t *testing.T) {
+	a := "QmUNLLsPACCz1vLxQVkXqqLX5R1X345qqfHbsf67hvA3Nn"
+	b := "QmeL2mdVka1eahKENjehK6tBxkkpk5dNQ1qMcgWi7Hrb4B"
+	c := "QmTRqTLbKndFC2rp6VzpyApxHCLrFV35setF1DQZaRWPVf"
+
+	cases := []struct {
+		description      string
+		blocked, allowed []string
+		expect           map[string]bool
+	}{
+		{"empty lists gate nothing", nil, nil, map[string]bool{a: false, b: false}},
+		{"blocked peers are gated", []string{a}, nil, map[string]bool{a: true, b: false}},
+		{"allow list gates others", nil, []string{a}, map[string]bool{a: false, b: true}},
+		{"block beats allow", []string{a}, []string{a, b}, map[string]bool{a: true, b: false, c: true}},
+		{"invalid ids are skipped", []string{"invalid"}, nil, map[string]bool{a: false}},
+	}
+
+	for _, c := range cases {
+		g := newPeerGate(&config.P2P{BlockedPeers: c.blocked, AllowedPeers: c.allowed})
+		for idStr, expect := range c.expect {
+			id, err := peer.IDB58Decode(idStr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := g.gated(id); got != expect {
+				t.Errorf("case %q peer %s gated mismatch. want: %t, got: %t", c.description, idStr, expect, got)
+			}
+		}
+	}
+}
+
+func TestConnectToGatedPeer(t *testing.T) {
+	ctx := context.Background()
+	f := p2ptest.NewTestNodeFactory(NewTestableQriNode)
+	testPeers, err := p2ptest.NewTestNetwork(ctx, f, 2)
+	if err != nil {
+		t.Fatalf("error creating network: %s", err.Error())
+	}
+	nodes := asQriNodes(testPeers)
+	a, b := nodes[0], nodes[1]
+	a.Host().Peerstore().AddAddrs(b.ID, b.Host().Addrs(), pstore.PermanentAddrTTL)
+
+	a.UpdatePeerGate(&config.P2P{BlockedPeers: []string{b.ID.Pretty()}})
+	if _, err := a.ConnectToPeer(ctx, PeerConnectionParams{PeerID: b.ID}); err != ErrPeerGated {
+		t.Errorf("expected dialing a blocked peer to return ErrPeerGated. got: %v", err)
+	}
+
+	// connections opened by a blocked peer are closed
+	if err := b.Host().Connect(ctx, a.SimplePeerInfo()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(a.Host().Network().ConnsToPeer(b.ID)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected connection from blocked peer to be closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	a.UpdatePeerGate(&config.P2P{})
+	if _, err := a.ConnectToPeer(ctx, PeerConnectionParams{PeerID: b.ID}); err != nil {
+		t.Errorf("expected unblocked peer to connect. got: %s", err)
+	}
+}
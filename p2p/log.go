@@ -5,7 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/qri-io/dataset/dsfs"
+	peer "github.com/libp2p/go-libp2p-peer"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/repo"
 )
 
@@ -18,15 +19,65 @@ const (
 
 // DatasetLogRequest encapsulates options for requesting dataset history
 type DatasetLogRequest struct {
-	Ref    repo.DatasetRef
+	Ref repo.DatasetRef
+	// Limit caps the number of versions returned, zero or less returns the
+	// full log
 	Limit  int
 	Offset int
 }
 
 // DatasetLogResponse encapsulates option for responding to a dataset history request
 type DatasetLogResponse struct {
+	// History lists dataset versions, newest first
 	History []repo.DatasetRef
-	Err     error
+	// Err is the error the responding peer encountered, if any
+	Err string
+}
+
+// RequestPeerDatasetLog fetches the commit history of a dataset from a
+// specific peer, ordered newest first
+func (n *QriNode) RequestPeerDatasetLog(ctx context.Context, pid peer.ID, p DatasetLogRequest) ([]repo.DatasetRef, error) {
+	log.Debugf("%s: RequestPeerDatasetLog", n.ID)
+
+	if pid == n.ID {
+		// requesting self isn't a network operation
+		return n.datasetLog(ctx, p)
+	}
+
+	req, err := NewJSONBodyMessage(n.ID, MtDatasetLog, p)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithHeaders("phase", "request")
+
+	// buffer the reply so a response arriving after ctx is done doesn't block
+	replies := make(chan Message, 1)
+	if err = n.SendMessage(ctx, req, replies, pid); err != nil {
+		return nil, err
+	}
+
+	var res Message
+	select {
+	case res = <-replies:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	logResponse := DatasetLogResponse{}
+	if err = json.Unmarshal(res.Body, &logResponse); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	switch logResponse.Err {
+	case "":
+		return logResponse.History, nil
+	case repo.ErrNotFound.Error():
+		return nil, repo.ErrNotFound
+	default:
+		return nil, fmt.Errorf("%s", logResponse.Err)
+	}
 }
 
 // RequestDatasetLog gets the log information of Peer's dataset
@@ -62,8 +113,8 @@ func (n *QriNode) RequestDatasetLog(ctx context.Context, ref repo.DatasetRef, li
 		if err = json.Unmarshal(msg.Body, &logResponse); err == nil {
 			// Expect any peer who responds with a non-empty history list to have the
 			// authoritative answer. Return as soon as such a response is received.
-			if logResponse.Err != nil {
-				log.Debugf("%s err: %s", pid, err.Error())
+			if logResponse.Err != "" {
+				log.Debugf("%s err: %s", pid, logResponse.Err)
 				continue
 			}
 			if len(logResponse.History) != 0 {
@@ -86,51 +137,28 @@ func (n *QriNode) handleDatasetLog(ws *WrappedStream, msg Message) (hangup bool)
 			return
 		}
 
-		ref := req.Ref
-		limit := req.Limit
-		offset := req.Offset
-
-		history := []repo.DatasetRef{}
-
-		err := repo.CanonicalizeDatasetRef(n.Repo, &ref)
-		if err == repo.ErrNotFound {
-			// non-local dataset, return early
-			sendDatasetLogReply(ws, msg, history, nil)
-			return
-		}
-
-		for {
-			dataset, err := dsfs.LoadDataset(context.TODO(), n.Repo.Store(), ref.Path)
-			if err != nil {
-				sendDatasetLogReply(ws, msg, history, err)
-				return
-			}
-			ref.Dataset = dataset
-
-			offset--
-			if offset > 0 {
-				ref.Path = ref.Dataset.PreviousPath
-				continue
-			}
-
-			history = append(history, ref)
-
-			limit--
-			if limit > 0 && ref.Dataset.PreviousPath != "" {
-				ref.Path = ref.Dataset.PreviousPath
-				continue
-			}
-			break
-		}
-
-		sendDatasetLogReply(ws, msg, history, nil)
+		history, err := n.datasetLog(context.TODO(), req)
+		sendDatasetLogReply(ws, msg, history, err)
 	}
 	return
 }
 
+// datasetLog reads the history of a local dataset
+func (n *QriNode) datasetLog(ctx context.Context, p DatasetLogRequest) ([]repo.DatasetRef, error) {
+	ref := p.Ref
+	if err := repo.CanonicalizeDatasetRef(n.Repo, &ref); err != nil {
+		return nil, err
+	}
+
+	return base.DatasetHistory(ctx, n.Repo, ref, p.Limit, p.Offset, true)
+}
+
 func sendDatasetLogReply(ws *WrappedStream, msg Message, history []repo.DatasetRef, err error) {
 	response := DatasetLogResponse{}
 	response.History = history
+	if err != nil {
+		response.Err = err.Error()
+	}
 	updated, err := msg.UpdateJSON(response)
 	if err != nil {
 		log.Debug(err.Error())
@@ -31,7 +31,7 @@ func TestRequestLogDiff(t *testing.T) {
 	}
 
 	// add a dataset to peer 4
-	ref, err := base.CreateDataset(ctx, peers[4].Repo, streams, tc.Input, nil, false, true, false, true)
+	ref, err := base.CreateDataset(ctx, peers[4].Repo, streams, tc.Input, nil, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -50,7 +50,7 @@ func TestRequestLogDiff(t *testing.T) {
 	update.Name = tc.Name
 
 	// add an update on peer 4
-	ref2, err := base.CreateDataset(ctx, peers[4].Repo, streams, update, tc.Input, false, true, false, true)
+	ref2, err := base.CreateDataset(ctx, peers[4].Repo, streams, update, tc.Input, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/p2p/test"
+	"github.com/qri-io/qri/repo"
 )
 
 func TestRequestDatasetLog(t *testing.T) {
@@ -29,7 +30,7 @@ func TestRequestDatasetLog(t *testing.T) {
 	}
 
 	// add a dataset to tim
-	ref, err := base.CreateDataset(ctx, peers[4].Repo, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true)
+	ref, err := base.CreateDataset(ctx, peers[4].Repo, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -58,3 +59,67 @@ func TestRequestDatasetLog(t *testing.T) {
 
 	// wg.Wait()
 }
+
+func TestRequestPeerDatasetLog(t *testing.T) {
+	ctx := context.Background()
+	factory := p2ptest.NewTestNodeFactory(NewTestableQriNode)
+	testPeers, err := p2ptest.NewTestDirNetwork(ctx, factory)
+	if err != nil {
+		t.Fatalf("error creating network: %s", err.Error())
+	}
+	if err := p2ptest.ConnectQriNodes(ctx, testPeers); err != nil {
+		t.Fatalf("error connecting peers: %s", err.Error())
+	}
+	peers := asQriNodes(testPeers)
+
+	tc, err := dstest.NewTestCaseFromDir("testdata/tim/craigslist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := base.CreateDataset(ctx, peers[4].Repo, ioes.NewDiscardIOStreams(), tc.Input, nil, false, true, false, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// requesting from self doesn't touch the network
+	refs, err := peers[4].RequestPeerDatasetLog(ctx, peers[4].ID, DatasetLogRequest{Ref: ref})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 1 || refs[0].Path != ref.Path {
+		t.Errorf("expected local log to hold version %s, got: %v", ref.Path, refs)
+	}
+
+	refs, err = peers[0].RequestPeerDatasetLog(ctx, peers[4].ID, DatasetLogRequest{Ref: ref})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 1 {
+		t.Fatalf("expected 1 version, got: %d", len(refs))
+	}
+	if refs[0].Path != ref.Path {
+		t.Errorf("version path mismatch. expected: %s, got: %s", ref.Path, refs[0].Path)
+	}
+	if refs[0].Dataset == nil || refs[0].Dataset.Commit == nil {
+		t.Error("expected version to include commit details")
+	}
+
+	refs, err = peers[0].RequestPeerDatasetLog(ctx, peers[4].ID, DatasetLogRequest{Ref: ref, Offset: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("expected offset past the first version to return no versions, got: %d", len(refs))
+	}
+
+	alias := repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}
+	if _, err = peers[0].RequestPeerDatasetLog(ctx, peers[1].ID, DatasetLogRequest{Ref: alias}); err != repo.ErrNotFound {
+		t.Errorf("expected peer without the dataset to respond with ErrNotFound, got: %v", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err = peers[0].RequestPeerDatasetLog(canceled, peers[4].ID, DatasetLogRequest{Ref: ref}); err == nil {
+		t.Error("expected request with a canceled context to error")
+	}
+}
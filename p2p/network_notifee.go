@@ -5,13 +5,20 @@ import (
 	ma "github.com/multiformats/go-multiaddr"
 )
 
-// a no-op implimentation of the Notifee interface
+// networkNotifee implements the Notifee interface, closing connections to
+// gated peers
 type networkNotifee struct {
 	node *QriNode
 }
 
 // Connected is called when a connection opened
-func (n networkNotifee) Connected(net net.Network, conn net.Conn) {}
+func (n networkNotifee) Connected(net net.Network, conn net.Conn) {
+	if pid := conn.RemotePeer(); n.node.PeerGated(pid) {
+		log.Infof("closing gated connection from peer %s", pid.Pretty())
+		// closing within a notification can deadlock the swarm, close async
+		go conn.Close()
+	}
+}
 
 // Disconnectec is called when a connection closed
 func (n networkNotifee) Disconnected(net net.Network, conn net.Conn) {}
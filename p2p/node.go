@@ -77,6 +77,9 @@ type QriNode struct {
 	// networkNotifee satisfies the net.Notifee interface
 	networkNotifee networkNotifee
 
+	// gate enforces peer block & allow lists
+	gate *peerGate
+
 	// TODO - waiting on next IPFS release
 	// autoNAT service
 	// autonat *autonat.AutoNATService
@@ -111,6 +114,7 @@ func NewQriNode(r repo.Repo, p2pconf *config.P2P) (node *QriNode, err error) {
 		// Make sure we always have proper IOStreams, this can be set
 		// later
 		LocalStreams: ioes.NewDiscardIOStreams(),
+		gate:         newPeerGate(p2pconf),
 	}
 	node.handlers = MakeHandlers(node)
 
@@ -267,7 +271,8 @@ func (n *QriNode) GetIPFSNamesys() (namesys.NameSystem, error) {
 	return ipfsn.Namesys, nil
 }
 
-// note: both ipfs_filestore and ipfs_http have this method
+// note: both ipfs_filestore and ipfs_http have this method. stores that wrap
+// an IPFS store may return a nil API when the wrapped store is unavailable
 type ipfsApier interface {
 	IPFSCoreAPI() coreiface.CoreAPI
 }
@@ -275,7 +280,9 @@ type ipfsApier interface {
 // IPFSCoreAPI returns a IPFS API interface instance
 func (n *QriNode) IPFSCoreAPI() (coreiface.CoreAPI, error) {
 	if apier, ok := n.Repo.Store().(ipfsApier); ok {
-		return apier.IPFSCoreAPI(), nil
+		if capi := apier.IPFSCoreAPI(); capi != nil {
+			return capi, nil
+		}
 	}
 	return nil, fmt.Errorf("not using IPFS")
 }
@@ -377,7 +384,7 @@ func (n *QriNode) SendMessage(ctx context.Context, msg Message, replies chan Mes
 		// tag this peer as supporting the qri protocol in the connection manager
 		n.host.ConnManager().TagPeer(peerID, qriSupportKey, qriSupportValue)
 
-		ws := WrapStream(s)
+		ws := n.wrapStream(s)
 		go n.handleStream(ws, replies)
 		if err := ws.sendMessage(msg); err != nil {
 			return err
@@ -390,7 +397,22 @@ func (n *QriNode) SendMessage(ctx context.Context, msg Message, replies chan Mes
 // QriStreamHandler is the handler we register with the multistream muxer
 func (n *QriNode) QriStreamHandler(s net.Stream) {
 	// defer s.Close()
-	n.handleStream(WrapStream(s), nil)
+	if pid := s.Conn().RemotePeer(); n.PeerGated(pid) {
+		log.Infof("rejecting qri stream from gated peer %s", pid.Pretty())
+		s.Reset()
+		return
+	}
+	n.handleStream(n.wrapStream(s), nil)
+}
+
+// wrapStream wraps a stream, limiting received messages to the configured
+// maximum message size
+func (n *QriNode) wrapStream(s net.Stream) *WrappedStream {
+	var max int64
+	if n.cfg != nil {
+		max = n.cfg.MaxMessageSize
+	}
+	return WrapStreamMaxSize(s, max)
 }
 
 // handleStream is a for loop which receives and handles messages
@@ -406,6 +428,11 @@ func (n *QriNode) handleStream(ws *WrappedStream, replies chan Message) {
 			if err.Error() == "EOF" {
 				break
 			}
+			if err == ErrMessageTooLarge {
+				// drop the connection without reading the rest of the message
+				ws.stream.Reset()
+				return
+			}
 			log.Debugf("error receiving message: %s", err.Error())
 			break
 		}
@@ -462,5 +489,6 @@ func MakeHandlers(n *QriNode) map[MsgType]HandlerFunc {
 		MtDatasetLog:        n.handleDatasetLog,
 		MtQriPeers:          n.handleQriPeers,
 		MtLogDiff:           n.handleLogDiff,
+		MtDatasetAnnounce:   n.handleDatasetAnnounce,
 	}
 }
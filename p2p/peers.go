@@ -176,6 +176,11 @@ func (n *QriNode) ConnectToPeer(ctx context.Context, p PeerConnectionParams) (*p
 		return nil, err
 	}
 
+	if n.PeerGated(pinfo.ID) {
+		log.Infof("refusing to dial gated peer %s", pinfo.ID.Pretty())
+		return nil, ErrPeerGated
+	}
+
 	if swarm, ok := n.host.Network().(*swarm.Swarm); ok {
 		// clear backoff b/c we're explicitly dialing this peer
 		swarm.Backoff().Clear(pinfo.ID)
@@ -32,6 +32,9 @@ func (n *QriNode) UpgradeToQriConnection(pinfo pstore.PeerInfo) error {
 	// bail early if we have seen this peer before
 	// OKAY
 	pid := pinfo.ID
+	if n.PeerGated(pid) {
+		return ErrPeerGated
+	}
 	log.Debugf("%s, attempting to upgrading %s to qri connection", n.ID, pid)
 	if _support, err := n.host.Peerstore().Get(pid, qriSupportKey); err == nil {
 		support, ok := _support.(bool)
@@ -132,7 +135,7 @@ func (n *QriNode) RequestNewPeers(ctx context.Context, peers []QriPeer) {
 	for _, p := range newPeers {
 		// TODO -
 		ID, err := peer.IDB58Decode(strings.TrimPrefix(p.PeerID, "/ipfs/"))
-		if err != nil {
+		if err != nil || n.PeerGated(ID) {
 			continue
 		}
 
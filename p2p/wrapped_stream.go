@@ -2,12 +2,22 @@ package p2p
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 
 	net "github.com/libp2p/go-libp2p-net"
 	multicodec "github.com/multiformats/go-multicodec"
 	json "github.com/multiformats/go-multicodec/json"
 )
 
+// DefaultMaxMessageSize is the largest encoded message, in bytes, a node will
+// read from a stream when no maximum is configured
+const DefaultMaxMessageSize = 8 << 20
+
+// ErrMessageTooLarge is returned when a message read from a stream exceeds the
+// maximum message size
+var ErrMessageTooLarge = fmt.Errorf("message exceeds maximum size")
+
 // HandlerFunc is the signature of a function that can handle p2p messages
 type HandlerFunc func(ws *WrappedStream, msg Message) (hangup bool)
 
@@ -20,6 +30,8 @@ type WrappedStream struct {
 	dec    multicodec.Decoder
 	w      *bufio.Writer
 	r      *bufio.Reader
+	// limit caps the number of bytes the decoder can read for each message
+	limit *messageLimitReader
 }
 
 // WrapStream takes a stream and complements it with r/w bufios and
@@ -27,14 +39,25 @@ type WrappedStream struct {
 // wrap.w.Write(). To encode something into it we can wrap.enc.Encode().
 // Finally, we should wrap.w.Flush() to actually send the data. Handling
 // incoming data works similarly with wrap.r.Read() for raw-reading and
-// wrap.dec.Decode() to decode.
+// wrap.dec.Decode() to decode. Messages larger than DefaultMaxMessageSize
+// are rejected
 func WrapStream(s net.Stream) *WrappedStream {
+	return WrapStreamMaxSize(s, DefaultMaxMessageSize)
+}
+
+// WrapStreamMaxSize wraps a stream, rejecting received messages larger than
+// maxSize bytes. a maxSize of zero or less uses DefaultMaxMessageSize
+func WrapStreamMaxSize(s net.Stream, maxSize int64) *WrappedStream {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxMessageSize
+	}
 	reader := bufio.NewReader(s)
 	writer := bufio.NewWriter(s)
+	limit := &messageLimitReader{r: reader, max: maxSize}
 	// This is where we pick our specific multicodec. In order to change the
 	// codec, we only need to change this place.
 	// See https://godoc.org/github.com/multiformats/go-multicodec/json
-	dec := json.Multicodec(false).Decoder(reader)
+	dec := json.Multicodec(false).Decoder(limit)
 	enc := json.Multicodec(false).Encoder(writer)
 	return &WrappedStream{
 		stream: s,
@@ -42,12 +65,17 @@ func WrapStream(s net.Stream) *WrappedStream {
 		w:      writer,
 		enc:    enc,
 		dec:    dec,
+		limit:  limit,
 	}
 }
 
 // receiveMessage reads and decodes a message from the stream
 func (ws *WrappedStream) receiveMessage() (msg Message, err error) {
-	err = ws.dec.Decode(&msg)
+	ws.limit.reset()
+	if err = ws.dec.Decode(&msg); err == ErrMessageTooLarge {
+		log.Errorf("%s rejected message from %s: %s of %d bytes", ws.stream.Conn().LocalPeer(), ws.stream.Conn().RemotePeer(), err, ws.limit.max)
+		return
+	}
 	msg.provider = ws.stream.Conn().RemotePeer()
 	log.Debugf("%s '%s' <- %s", ws.stream.Conn().LocalPeer(), msg.Type, ws.stream.Conn().RemotePeer())
 	return
@@ -61,3 +89,28 @@ func (ws *WrappedStream) sendMessage(msg Message) error {
 	log.Debugf("%s '%s' -> %s", ws.stream.Conn().LocalPeer(), msg.Type, ws.stream.Conn().RemotePeer())
 	return err
 }
+
+// messageLimitReader returns ErrMessageTooLarge once more than max bytes have
+// been read since the last reset, stopping a peer from making us buffer an
+// unbounded message
+type messageLimitReader struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func (l *messageLimitReader) reset() {
+	l.n = 0
+}
+
+func (l *messageLimitReader) Read(p []byte) (int, error) {
+	if l.n >= l.max {
+		return 0, ErrMessageTooLarge
+	}
+	if rem := l.max - l.n; int64(len(p)) > rem {
+		p = p[:rem]
+	}
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	return n, err
+}
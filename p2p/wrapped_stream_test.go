@@ -0,0 +1,68 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	p2ptest "github.com/qri-io/qri/p2p/test"
+)
+
+func TestMessageLimitReader(t *testing.T) {
+	l := &messageLimitReader{r: strings.NewReader(strings.Repeat("a", 20)), max: 8}
+	data, err := ioutil.ReadAll(l)
+	if err != ErrMessageTooLarge {
+		t.Errorf("expected reading past the limit to return ErrMessageTooLarge. got: %v", err)
+	}
+	if len(data) != 8 {
+		t.Errorf("expected to read exactly %d bytes before rejecting. got: %d", 8, len(data))
+	}
+
+	l.reset()
+	buf := make([]byte, 4)
+	if n, err := l.Read(buf); err != nil || n != 4 {
+		t.Errorf("expected reset to allow reading again. read %d bytes, err: %v", n, err)
+	}
+}
+
+func TestReceiveOversizedMessage(t *testing.T) {
+	ctx := context.Background()
+	f := p2ptest.NewTestNodeFactory(NewTestableQriNode)
+	testPeers, err := p2ptest.NewTestNetwork(ctx, f, 2)
+	if err != nil {
+		t.Fatalf("error creating network: %s", err.Error())
+	}
+	if err := p2ptest.ConnectQriNodes(ctx, testPeers); err != nil {
+		t.Fatalf("error connecting peers: %s", err.Error())
+	}
+	nodes := asQriNodes(testPeers)
+	a, b := nodes[0], nodes[1]
+	b.cfg.MaxMessageSize = 1024
+
+	request := func(body []byte) (Message, bool) {
+		replies := make(chan Message)
+		req := NewMessage(a.ID, MtEvents, body).WithHeaders("phase", "request")
+		if err := a.SendMessage(ctx, req, replies, b.ID); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case res := <-replies:
+			return res, true
+		case <-time.After(time.Second):
+			return Message{}, false
+		}
+	}
+
+	if _, ok := request([]byte(`{"Limit":1}`)); !ok {
+		t.Fatal("expected message under the size limit to get a reply")
+	}
+
+	// a valid events request, padded with whitespace past the size limit
+	padded := append([]byte(`{"Limit":1}`), bytes.Repeat([]byte(" "), 4096)...)
+	if res, ok := request(padded); ok {
+		t.Errorf("expected oversized message to be rejected. got reply: %v", res)
+	}
+}
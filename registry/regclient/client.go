@@ -2,8 +2,13 @@
 package regclient
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
+	"time"
 )
 
 var (
@@ -26,15 +31,96 @@ var (
 type Client struct {
 	cfg        *Config
 	httpClient *http.Client
+	active     *activeLocation
 }
 
 // Config encapsulates options for working with a registry
 type Config struct {
 	// Location is the URL base to call to
 	Location string
+	// Fallbacks are URL bases to try in order when a request to Location
+	// fails to connect
+	Fallbacks []string
+	// Timeout is the deadline for each request to a registry location,
+	// including reading the response. zero means no timeout
+	Timeout time.Duration
 }
 
 // NewClient creates a registry from a provided Registry configuration
 func NewClient(cfg *Config) *Client {
-	return &Client{cfg, HTTPClient}
+	return &Client{cfg, HTTPClient, &activeLocation{}}
+}
+
+// ActiveLocation returns the location of the registry that last answered a
+// request, empty if no request has succeeded
+func (c *Client) ActiveLocation() string {
+	if c == nil || c.active == nil {
+		return ""
+	}
+	c.active.lk.Lock()
+	defer c.active.lk.Unlock()
+	return c.active.location
+}
+
+// activeLocation tracks the registry location that last answered a request
+type activeLocation struct {
+	lk       sync.Mutex
+	location string
+}
+
+// locations lists the URL bases to try, in order
+func (c Client) locations() []string {
+	locs := []string{c.cfg.Location}
+	for _, loc := range c.cfg.Fallbacks {
+		if loc != "" {
+			locs = append(locs, loc)
+		}
+	}
+	return locs
+}
+
+// do performs a request against each configured registry location in order,
+// moving to the next location only when a connection can't be made or the
+// request times out. newReq is called once per attempted location. callers
+// must close the response body
+func (c Client) do(newReq func(location string) (*http.Request, error)) (res *http.Response, err error) {
+	for _, loc := range c.locations() {
+		var req *http.Request
+		if req, err = newReq(loc); err != nil {
+			return nil, err
+		}
+		cancel := func() {}
+		if c.cfg.Timeout > 0 {
+			var ctx context.Context
+			ctx, cancel = context.WithTimeout(req.Context(), c.cfg.Timeout)
+			req = req.WithContext(ctx)
+		}
+		if res, err = c.httpClient.Do(req); err != nil {
+			if req.Context().Err() == context.DeadlineExceeded {
+				err = fmt.Errorf("registry: request to %s timed out after %s", loc, c.cfg.Timeout)
+			}
+			cancel()
+			continue
+		}
+		res.Body = cancelOnClose{res.Body, cancel}
+		if c.active != nil {
+			c.active.lk.Lock()
+			c.active.location = loc
+			c.active.lk.Unlock()
+		}
+		return res, nil
+	}
+	return nil, err
+}
+
+// cancelOnClose releases a request context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
@@ -0,0 +1,93 @@
+package regclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qri-io/qri/registry"
+	"github.com/qri-io/qri/registry/regserver/handlers"
+)
+
+func TestClientFallbacks(t *testing.T) {
+	reg := registry.Registry{
+		Profiles: registry.NewMemProfiles(),
+		Search:   &registry.MockSearch{},
+	}
+	ts := httptest.NewServer(handlers.NewRoutes(reg))
+	defer ts.Close()
+
+	// a closed server refuses connections
+	down := httptest.NewServer(handlers.NewRoutes(reg))
+	down.Close()
+
+	c := NewClient(&Config{
+		Location:  down.URL,
+		Fallbacks: []string{"", ts.URL},
+	})
+	if loc := c.ActiveLocation(); loc != "" {
+		t.Errorf("expected no active location before a request. got: %q", loc)
+	}
+
+	if _, err := c.Search(&SearchParams{QueryString: "presidents", Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if loc := c.ActiveLocation(); loc != ts.URL {
+		t.Errorf("expected fallback registry to answer. expected: %q, got: %q", ts.URL, loc)
+	}
+
+	c = NewClient(&Config{Location: down.URL})
+	if _, err := c.Search(&SearchParams{QueryString: "presidents", Limit: 10}); err == nil {
+		t.Error("expected search with no reachable registry to error")
+	}
+	if loc := c.ActiveLocation(); loc != "" {
+		t.Errorf("expected no active location after failed request. got: %q", loc)
+	}
+
+	var nilClient *Client
+	if loc := nilClient.ActiveLocation(); loc != "" {
+		t.Errorf("expected nil client to have no active location. got: %q", loc)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	reg := registry.Registry{
+		Profiles: registry.NewMemProfiles(),
+		Search:   &registry.MockSearch{},
+	}
+	routes := handlers.NewRoutes(reg)
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+	ts := httptest.NewServer(routes)
+	defer ts.Close()
+
+	c := NewClient(&Config{Location: slow.URL, Timeout: 50 * time.Millisecond})
+	start := time.Now()
+	_, err := c.Search(&SearchParams{QueryString: "presidents", Limit: 10})
+	if err == nil {
+		t.Fatal("expected request to a slow registry to error")
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got: %s", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("expected request to give up near the timeout, took: %s", time.Since(start))
+	}
+
+	c = NewClient(&Config{Location: slow.URL, Fallbacks: []string{ts.URL}, Timeout: 50 * time.Millisecond})
+	if _, err := c.Search(&SearchParams{QueryString: "presidents", Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if loc := c.ActiveLocation(); loc != ts.URL {
+		t.Errorf("expected fallback to answer after timeout. expected: %q, got: %q", ts.URL, loc)
+	}
+}
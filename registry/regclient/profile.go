@@ -93,18 +93,21 @@ func (c Client) doJSONProfileReq(method string, p *registry.Profile) (*registry.
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, fmt.Sprintf("%s/registry/profile", c.cfg.Location), bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(func(location string) (*http.Request, error) {
+		req, err := http.NewRequest(method, fmt.Sprintf("%s/registry/profile", location), bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), "no such host") {
 			return nil, ErrNoRegistry
 		}
 		return nil, err
 	}
+	defer res.Body.Close()
 
 	// add response to an envelope
 	env := struct {
@@ -30,15 +30,18 @@ func (c Client) doJSONReputationReq(method string, rep *registry.Reputation) (*r
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, fmt.Sprintf("%s/registry/reputation", c.cfg.Location), bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(func(location string) (*http.Request, error) {
+		req, err := http.NewRequest(method, fmt.Sprintf("%s/registry/reputation", location), bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 
 	env := struct {
 		Data *registry.ReputationResponse
@@ -36,27 +36,45 @@ type SearchParams struct {
 	Offset      int
 }
 
+// SearchResult is a dataset returned by a registry search, along with the
+// relevance score the registry gave it. Score is 0 if the registry doesn't
+// report one
+type SearchResult struct {
+	Dataset *dataset.Dataset
+	Score   float64
+}
+
 // Search makes a registry search request
 func (c Client) Search(p *SearchParams) ([]*dataset.Dataset, error) {
+	results, err := c.SearchResults(p)
+	if err != nil {
+		return nil, err
+	}
+	datasets := make([]*dataset.Dataset, len(results))
+	for i, r := range results {
+		datasets[i] = r.Dataset
+	}
+	return datasets, nil
+}
+
+// SearchResults makes a registry search request, returning datasets with
+// their scores
+func (c Client) SearchResults(p *SearchParams) ([]*SearchResult, error) {
 	params := &registry.SearchParams{
 		Q: p.QueryString,
 		//Filters: p.Filters,
 		Limit:  p.Limit,
 		Offset: p.Offset,
 	}
-	results, err := c.doJSONSearchReq("GET", params)
-	if err != nil {
-		return nil, err
-	}
-	return results, nil
+	return c.doJSONSearchReq("GET", params)
 }
 
-func (c Client) prepPostReq(s *registry.SearchParams) (*http.Request, error) {
+func (c Client) prepPostReq(location string, s *registry.SearchParams) (*http.Request, error) {
 	data, err := json.Marshal(s)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/search", c.cfg.Location), bytes.NewReader(data))
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/search", location), bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +82,8 @@ func (c Client) prepPostReq(s *registry.SearchParams) (*http.Request, error) {
 	return req, nil
 }
 
-func (c Client) prepGetReq(s *registry.SearchParams) (*http.Request, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/search", c.cfg.Location), nil)
+func (c Client) prepGetReq(location string, s *registry.SearchParams) (*http.Request, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/search", location), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,32 +99,29 @@ func (c Client) prepGetReq(s *registry.SearchParams) (*http.Request, error) {
 	return req, nil
 }
 
-func (c Client) doJSONSearchReq(method string, s *registry.SearchParams) (results []*dataset.Dataset, err error) {
+func (c Client) doJSONSearchReq(method string, s *registry.SearchParams) (results []*SearchResult, err error) {
 	if c.cfg.Location == "" {
 		return nil, ErrNoRegistry
 	}
-	// req := &http.Request{}
-	var req *http.Request
-	switch method {
-	case "POST":
-		req, err = c.prepPostReq(s)
-	case "GET":
-		req, err = c.prepGetReq(s)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	res, err := c.httpClient.Do(req)
+	res, err := c.do(func(location string) (*http.Request, error) {
+		switch method {
+		case "POST":
+			return c.prepPostReq(location, s)
+		case "GET":
+			return c.prepGetReq(location, s)
+		}
+		return nil, fmt.Errorf("unsupported search method: %s", method)
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), "no such host") {
 			return nil, ErrNoRegistry
 		}
 		return nil, err
 	}
+	defer res.Body.Close()
 	// add response to an envelope
 	env := struct {
-		Data []*dataset.Dataset
+		Data []json.RawMessage
 		Meta struct {
 			Error  string
 			Status string
@@ -121,5 +136,21 @@ func (c Client) doJSONSearchReq(method string, s *registry.SearchParams) (result
 	if res.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("error %d: %s", res.StatusCode, env.Meta.Error)
 	}
-	return env.Data, nil
+
+	results = make([]*SearchResult, len(env.Data))
+	for i, data := range env.Data {
+		ds := &dataset.Dataset{}
+		if err := json.Unmarshal(data, ds); err != nil {
+			return nil, err
+		}
+		// registries that rank results add a score alongside dataset fields
+		score := struct {
+			Score float64 `json:"score"`
+		}{}
+		if err := json.Unmarshal(data, &score); err != nil {
+			return nil, err
+		}
+		results[i] = &SearchResult{Dataset: ds, Score: score.Score}
+	}
+	return results, nil
 }
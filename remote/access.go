@@ -0,0 +1,160 @@
+package remote
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qri-io/qri/repo"
+)
+
+// ErrAccessDenied is returned when a remote's access list doesn't permit
+// serving a requested dataset
+var ErrAccessDenied = fmt.Errorf("access to dataset denied")
+
+// accessList decides which datasets a remote serves. Patterns are dataset
+// references in "peername/name" form, and may use glob syntax, "b5/*"
+// matches every dataset belonging to b5
+type accessList struct {
+	allow []string
+	deny  []string
+}
+
+// newAccessList creates an access list, checking all patterns are valid
+func newAccessList(allow, deny []string) (accessList, error) {
+	for _, pattern := range append(append([]string{}, allow...), deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return accessList{}, fmt.Errorf("invalid remote access pattern %q: %s", pattern, err.Error())
+		}
+	}
+	return accessList{allow: allow, deny: deny}, nil
+}
+
+// Restricted reports whether the access list limits the datasets served
+func (a accessList) Restricted() bool {
+	return len(a.allow) > 0 || len(a.deny) > 0
+}
+
+// Allowed reports whether a dataset may be served. Denied patterns take
+// precedence over allowed patterns. When allowed patterns are set, only
+// datasets that match one are served
+func (a accessList) Allowed(ref repo.DatasetRef) bool {
+	if !a.Restricted() {
+		return true
+	}
+
+	// patterns match names, so requests that only give a path or cid are
+	// refused, even if the path belongs to a dataset that would be allowed
+	if ref.Peername == "" || ref.Name == "" {
+		return false
+	}
+	alias := ref.Peername + "/" + ref.Name
+	if matchAny(a.deny, alias) {
+		return false
+	}
+	return len(a.allow) == 0 || matchAny(a.allow, alias)
+}
+
+func matchAny(patterns []string, alias string) bool {
+	for _, pattern := range patterns {
+		// patterns may include a path, match on the alias portion only
+		if i := strings.Index(pattern, "@"); i >= 0 {
+			pattern = pattern[:i]
+		}
+		if ok, _ := path.Match(pattern, alias); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pullSessionTTL is how long a pull from a restricted remote may keep
+// fetching blocks of the manifest it was given
+const pullSessionTTL = time.Hour
+
+// maxPullSessions caps the number of unexpired pull sessions a remote keeps,
+// each session holds the block list of a full manifest
+const maxPullSessions = 1000
+
+// pullSessions tracks which blocks each pull from a restricted remote may
+// fetch. A pull starts when the remote hands out the manifest of a dataset
+// it's allowed to serve, and can then only fetch blocks in that manifest
+type pullSessions struct {
+	lk       sync.Mutex
+	next     int
+	sessions map[string]pullSession
+}
+
+type pullSession struct {
+	seq     int
+	blocks  map[string]struct{}
+	expires time.Time
+}
+
+// newPullSessionID creates a random, unguessable session identifier
+func newPullSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// add starts a session that may fetch a list of blocks, dropping expired
+// sessions. Once maxPullSessions are open the oldest sessions are evicted
+func (ps *pullSessions) add(sid string, blocks []string) {
+	ps.lk.Lock()
+	defer ps.lk.Unlock()
+
+	now := time.Now()
+	if ps.sessions == nil {
+		ps.sessions = map[string]pullSession{}
+	}
+	for id, sess := range ps.sessions {
+		if now.After(sess.expires) {
+			delete(ps.sessions, id)
+		}
+	}
+	for len(ps.sessions) >= maxPullSessions {
+		ps.evictOldest()
+	}
+
+	ps.next++
+	sess := pullSession{
+		seq:     ps.next,
+		blocks:  make(map[string]struct{}, len(blocks)),
+		expires: now.Add(pullSessionTTL),
+	}
+	for _, hash := range blocks {
+		sess.blocks[hash] = struct{}{}
+	}
+	ps.sessions[sid] = sess
+}
+
+// evictOldest drops the earliest started session. callers must hold the lock
+func (ps *pullSessions) evictOldest() {
+	oldest := ""
+	for id, sess := range ps.sessions {
+		if oldest == "" || sess.seq < ps.sessions[oldest].seq {
+			oldest = id
+		}
+	}
+	delete(ps.sessions, oldest)
+}
+
+// has checks if a session may fetch a block
+func (ps *pullSessions) has(sid, hash string) bool {
+	ps.lk.Lock()
+	defer ps.lk.Unlock()
+
+	sess, ok := ps.sessions[sid]
+	if !ok || time.Now().After(sess.expires) {
+		return false
+	}
+	_, ok = sess.blocks[hash]
+	return ok
+}
@@ -0,0 +1,88 @@
+package remote
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/qri-io/qri/repo"
+)
+
+func TestAccessListAllowed(t *testing.T) {
+	cases := []struct {
+		allow, deny []string
+		ref         repo.DatasetRef
+		expect      bool
+	}{
+		{nil, nil, repo.DatasetRef{Peername: "b5", Name: "world_bank"}, true},
+		{nil, nil, repo.DatasetRef{Path: "/ipfs/QmFoo"}, true},
+
+		{[]string{"b5/*"}, nil, repo.DatasetRef{Peername: "b5", Name: "world_bank"}, true},
+		{[]string{"b5/*"}, nil, repo.DatasetRef{Peername: "ramfox", Name: "world_bank"}, false},
+		{[]string{"b5/world_bank"}, nil, repo.DatasetRef{Peername: "b5", Name: "world_bank_2"}, false},
+		{[]string{"b5/world_bank@/ipfs/QmFoo"}, nil, repo.DatasetRef{Peername: "b5", Name: "world_bank"}, true},
+		{[]string{"b5/*"}, nil, repo.DatasetRef{Path: "/ipfs/QmFoo"}, false},
+
+		{nil, []string{"*/private_*"}, repo.DatasetRef{Peername: "b5", Name: "private_notes"}, false},
+		{nil, []string{"*/private_*"}, repo.DatasetRef{Peername: "b5", Name: "notes"}, true},
+		{nil, []string{"b5/*"}, repo.DatasetRef{Path: "/ipfs/QmFoo"}, false},
+
+		{[]string{"b5/*"}, []string{"b5/secret"}, repo.DatasetRef{Peername: "b5", Name: "secret"}, false},
+		{[]string{"b5/*"}, []string{"b5/secret"}, repo.DatasetRef{Peername: "b5", Name: "public"}, true},
+	}
+
+	for i, c := range cases {
+		acl, err := newAccessList(c.allow, c.deny)
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if got := acl.Allowed(c.ref); got != c.expect {
+			t.Errorf("case %d allow: %v deny: %v ref: %s. expected: %t, got: %t", i, c.allow, c.deny, c.ref, c.expect, got)
+		}
+	}
+}
+
+func TestNewAccessListInvalidPattern(t *testing.T) {
+	expect := `invalid remote access pattern "b5/[": syntax error in pattern`
+	if _, err := newAccessList(nil, []string{"b5/["}); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
+
+func TestPullSessions(t *testing.T) {
+	ps := pullSessions{}
+	ps.add("a", []string{"QmFoo", "QmBar"})
+
+	if !ps.has("a", "QmFoo") {
+		t.Error("expected session to have a listed block")
+	}
+	if ps.has("a", "QmBaz") {
+		t.Error("expected session not to have an unlisted block")
+	}
+	if ps.has("b", "QmFoo") {
+		t.Error("expected unknown session not to have blocks")
+	}
+	if ps.has("", "QmFoo") {
+		t.Error("expected empty session id not to have blocks")
+	}
+}
+
+func TestPullSessionsCap(t *testing.T) {
+	ps := pullSessions{}
+	for i := 0; i <= maxPullSessions; i++ {
+		ps.add(fmt.Sprintf("session_%d", i), []string{"QmFoo"})
+	}
+
+	if len(ps.sessions) != maxPullSessions {
+		t.Errorf("expected %d sessions, got: %d", maxPullSessions, len(ps.sessions))
+	}
+	if ps.has("session_0", "QmFoo") {
+		t.Error("expected oldest session to be evicted")
+	}
+	if !ps.has("session_1", "QmFoo") {
+		t.Error("expected second oldest session to be kept")
+	}
+	if !ps.has(fmt.Sprintf("session_%d", maxPullSessions), "QmFoo") {
+		t.Error("expected newest session to be kept")
+	}
+}
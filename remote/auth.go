@@ -0,0 +1,94 @@
+package remote
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qri-io/qri/config"
+)
+
+// Headers builds the HTTP headers to send to each configured remote from
+// remote credentials, keyed by remote address
+func Headers(cfg *config.Config) map[string]http.Header {
+	if cfg.RemoteAuth == nil {
+		return nil
+	}
+	headers := map[string]http.Header{}
+	for name := range *cfg.RemoteAuth {
+		creds, ok := cfg.RemoteAuth.Get(name)
+		if !ok {
+			continue
+		}
+		addr, err := Address(cfg, name)
+		if err != nil {
+			log.Debugf("skipping credentials for remote %q: %s", name, err)
+			continue
+		}
+		headers[addr] = creds.Header()
+	}
+	return headers
+}
+
+// newHTTPClient creates an HTTP client for requests to remotes that adds
+// headers to requests sent to each remote address
+func newHTTPClient(headers map[string]http.Header) *http.Client {
+	if len(headers) == 0 {
+		return &http.Client{}
+	}
+	return &http.Client{Transport: &headerTransport{headers: headers}}
+}
+
+// headerTransport is an http.RoundTripper that adds headers to requests
+// whose URL falls under a remote address
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]http.Header
+}
+
+// match finds headers for the remote address a URL is under, preferring the
+// longest matching address
+func (t *headerTransport) match(u *url.URL) http.Header {
+	var (
+		match   http.Header
+		longest = -1
+	)
+	for addr, h := range t.headers {
+		au, err := url.Parse(addr)
+		if err != nil || au.Scheme != u.Scheme || au.Host != u.Host {
+			continue
+		}
+		prefix := strings.TrimSuffix(au.Path, "/")
+		if u.Path != prefix && !strings.HasPrefix(u.Path, prefix+"/") {
+			continue
+		}
+		if len(prefix) > longest {
+			match, longest = h, len(prefix)
+		}
+	}
+	return match
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	h := t.match(req.URL)
+	if h == nil {
+		return base.RoundTrip(req)
+	}
+
+	// round trippers must not modify requests, send a copy
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+len(h))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	for k, v := range h {
+		r.Header[k] = v
+	}
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,130 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/repo"
+)
+
+func TestHeaders(t *testing.T) {
+	cfg := config.DefaultConfigForTesting()
+	if h := Headers(cfg); h != nil {
+		t.Errorf("expected no headers without remote auth, got: %v", h)
+	}
+
+	cfg.Remotes = &config.Remotes{"origin": "https://remote.example.com"}
+	cfg.RemoteAuth = &config.RemoteAuth{
+		"origin": {Token: "secret", Headers: map[string]string{"X-Api-Key": "key"}},
+		"gone":   {Token: "unused"},
+	}
+	h := Headers(cfg)
+	if len(h) != 1 {
+		t.Fatalf("expected headers for 1 remote, got: %v", h)
+	}
+	got := h["https://remote.example.com"]
+	if got.Get("Authorization") != "Bearer secret" || got.Get("X-Api-Key") != "key" {
+		t.Errorf("header mismatch. got: %v", got)
+	}
+}
+
+func TestHeaderTransport(t *testing.T) {
+	var got http.Header
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}))
+	defer s.Close()
+
+	cli := newHTTPClient(map[string]http.Header{
+		s.URL + "/gateway": {"Authorization": []string{"Bearer secret"}},
+	})
+
+	cases := []struct {
+		path, auth string
+	}{
+		{"/gateway/remote/dsync", "Bearer secret"},
+		{"/gateway", "Bearer secret"},
+		{"/gatewayz/remote/dsync", ""},
+		{"/remote/refs", ""},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest("GET", s.URL+c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cli.Do(req); err != nil {
+			t.Fatal(err)
+		}
+		if auth := got.Get("Authorization"); auth != c.auth {
+			t.Errorf("%s authorization mismatch. expected: %q, got: %q", c.path, c.auth, auth)
+		}
+		if req.Header.Get("Authorization") != "" {
+			t.Errorf("%s transport shouldn't modify the original request", c.path)
+		}
+	}
+}
+
+func TestResolveHeadRefSendsHeaders(t *testing.T) {
+	var auth string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(repo.DatasetRef{Peername: "peer", Name: "ds", Path: "/ipfs/QmFoo"})
+	}))
+	defer s.Close()
+
+	cli := newHTTPClient(map[string]http.Header{s.URL: {"Authorization": []string{"Bearer secret"}}})
+
+	ref := &repo.DatasetRef{Peername: "peer", Name: "ds"}
+	if err := resolveHeadRefHTTP(context.Background(), cli, ref, s.URL); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected refs request to carry authorization, got: %q", auth)
+	}
+	if ref.Path != "/ipfs/QmFoo" {
+		t.Errorf("expected ref path to resolve, got: %q", ref.Path)
+	}
+}
+
+func TestDsyncHTTPClientSendsHeaders(t *testing.T) {
+	var auth string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Query().Get("block") != "QmBlock" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("data"))
+	}))
+	defer s.Close()
+
+	rem := &dsyncHTTPClient{
+		URL:    s.URL + "/remote/dsync",
+		client: newHTTPClient(map[string]http.Header{s.URL: {"Authorization": []string{"Bearer secret"}}}),
+	}
+	data, err := rem.GetBlock(context.Background(), "QmBlock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "data" {
+		t.Errorf("block data mismatch. got: %q", string(data))
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected dsync request to carry authorization, got: %q", auth)
+	}
+	if http.DefaultClient.Transport != nil {
+		t.Errorf("remote headers shouldn't be installed on the default http client")
+	}
+
+	rem = &dsyncHTTPClient{URL: s.URL + "/remote/dsync", client: newHTTPClient(nil)}
+	if _, err := rem.GetBlock(context.Background(), "QmBlock"); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "" {
+		t.Errorf("expected client without headers to send no authorization, got: %q", auth)
+	}
+}
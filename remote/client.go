@@ -1,6 +1,7 @@
 package remote
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -11,11 +12,17 @@ import (
 	"strings"
 	"time"
 
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
 	coreiface "github.com/ipfs/interface-go-ipfs-core"
+	"github.com/ipfs/interface-go-ipfs-core/options"
+	"github.com/ipfs/interface-go-ipfs-core/path"
 	crypto "github.com/libp2p/go-libp2p-crypto"
 	peer "github.com/libp2p/go-libp2p-peer"
 	"github.com/multiformats/go-multihash"
+	"github.com/qri-io/dag"
 	"github.com/qri-io/dag/dsync"
+	"github.com/qri-io/dataset/dsfs"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
@@ -24,6 +31,9 @@ import (
 // ErrNoRemoteClient is returned when no client is allocated
 var ErrNoRemoteClient = fmt.Errorf("not configured to make remote requests")
 
+// ErrNotFound is returned when a remote doesn't have a requested dataset
+var ErrNotFound = fmt.Errorf("dataset not found on remote")
+
 // Address extracts the address of a remote from a configuration for a given
 // remote name
 func Address(cfg *config.Config, name string) (addr string, err error) {
@@ -34,22 +44,48 @@ func Address(cfg *config.Config, name string) (addr string, err error) {
 		return "", fmt.Errorf("no registry specifiied to use as default remote")
 	}
 
-	if dst, found := cfg.Remotes.Get(name); found {
-		return dst, nil
+	if cfg.Remotes != nil {
+		if dst, found := cfg.Remotes.Get(name); found {
+			return dst, nil
+		}
 	}
 
 	return "", fmt.Errorf(`remote name "%s" not found`, name)
 }
 
+// ClientOptions encapsulates runtime configuration for a client
+type ClientOptions struct {
+	// PushStateDir is a directory for recording the progress of pushes. When
+	// set, interrupted pushes to HTTP remotes can be resumed
+	PushStateDir string
+	// Headers are added to HTTP requests sent to remotes, keyed by remote
+	// address. Use Headers to build them from configured remote credentials
+	Headers map[string]http.Header
+	// PullConcurrency is the number of blocks to request at once when pulling
+	// from an HTTP remote. Defaults to DefaultPullConcurrency
+	PullConcurrency int
+}
+
 // Client issues requests to a remote
 type Client struct {
-	pk   crypto.PrivKey
-	ds   *dsync.Dsync
-	capi coreiface.CoreAPI
+	pk     crypto.PrivKey
+	ds     *dsync.Dsync
+	lng    ipld.NodeGetter
+	capi   coreiface.CoreAPI
+	pushes *pushStateStore
+	// http client for requests to HTTP remotes, adds configured headers
+	httpClient *http.Client
+	// number of blocks to request at once when pulling
+	pullConcurrency int
 }
 
 // NewClient creates a client
-func NewClient(node *p2p.QriNode) (*Client, error) {
+func NewClient(node *p2p.QriNode, opts ...func(o *ClientOptions)) (*Client, error) {
+	o := &ClientOptions{PullConcurrency: DefaultPullConcurrency}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	capi, err := node.IPFSCoreAPI()
 	if err != nil {
 		return nil, err
@@ -68,11 +104,18 @@ func NewClient(node *p2p.QriNode) (*Client, error) {
 		dsyncConfig.PinAPI = capi.Pin()
 	})
 
-	return &Client{
-		pk:   node.Repo.PrivateKey(),
-		ds:   ds,
-		capi: capi,
-	}, nil
+	c := &Client{
+		pk:              node.Repo.PrivateKey(),
+		ds:              ds,
+		lng:             lng,
+		capi:            capi,
+		httpClient:      newHTTPClient(o.Headers),
+		pullConcurrency: o.PullConcurrency,
+	}
+	if o.PushStateDir != "" {
+		c.pushes = &pushStateStore{dir: o.PushStateDir}
+	}
+	return c, nil
 }
 
 // CoreAPI exposes this client's CoreApi
@@ -88,6 +131,13 @@ func (c *Client) PushDataset(ctx context.Context, ref repo.DatasetRef, remoteAdd
 		return ErrNoRemoteClient
 	}
 	log.Debugf("pushing dataset %s to %s", ref.Path, remoteAddr)
+	if addressType(remoteAddr) == "http" {
+		if c.pushes != nil {
+			return c.pushDatasetResumable(ctx, ref, remoteAddr)
+		}
+		return c.pushDatasetHTTP(ctx, ref, remoteAddr)
+	}
+
 	push, err := c.ds.NewPush(ref.Path, remoteAddr+"/remote/dsync", true)
 	if err != nil {
 		return err
@@ -118,6 +168,107 @@ func (c *Client) PushDataset(ctx context.Context, ref repo.DatasetRef, remoteAdd
 	return push.Do(ctx)
 }
 
+// pushDatasetHTTP pushes a dataset to an HTTP remote
+func (c *Client) pushDatasetHTTP(ctx context.Context, ref repo.DatasetRef, remoteAddr string) error {
+	root, err := rootCid(ref.Path)
+	if err != nil {
+		return err
+	}
+
+	info, err := dag.NewInfo(ctx, c.lng, root)
+	if err != nil {
+		return err
+	}
+
+	push, err := dsync.NewPush(c.lng, info, c.dsyncRemote(remoteAddr), true)
+	if err != nil {
+		return err
+	}
+
+	params, err := sigParams(c.pk, ref)
+	if err != nil {
+		return err
+	}
+	push.SetMeta(params)
+
+	return push.Do(ctx)
+}
+
+// pushDatasetResumable pushes a dataset, recording blocks the remote has
+// received. If the push fails, the next push of the same dataset to the same
+// remote picks up where this one left off
+func (c *Client) pushDatasetResumable(ctx context.Context, ref repo.DatasetRef, remoteAddr string) error {
+	root, err := rootCid(ref.Path)
+	if err != nil {
+		return err
+	}
+
+	state, err := c.pushes.Get(ref.Path)
+	if err != nil {
+		return err
+	}
+	if state == nil || state.RemoteAddr != remoteAddr {
+		state = &PushState{Path: ref.Path, RemoteAddr: remoteAddr}
+	} else {
+		log.Debugf("found partial push of %s, %d blocks sent", ref.Path, len(state.Completed))
+	}
+
+	info, err := dag.NewInfo(ctx, c.lng, root)
+	if err != nil {
+		return err
+	}
+
+	rem := &resumableRemote{
+		DagSyncable: c.dsyncRemote(remoteAddr),
+		store:       *c.pushes,
+		state:       state,
+	}
+	push, err := dsync.NewPush(c.lng, info, rem, true)
+	if err != nil {
+		return err
+	}
+
+	params, err := sigParams(c.pk, ref)
+	if err != nil {
+		return err
+	}
+	push.SetMeta(params)
+
+	if err = push.Do(ctx); err != nil {
+		if saveErr := rem.save(); saveErr != nil {
+			log.Errorf("saving push state: %s", saveErr)
+		}
+		return err
+	}
+
+	return c.pushes.Delete(ref.Path)
+}
+
+// dsyncRemote creates a dsync client for an HTTP remote that sends requests
+// with this client's http client
+func (c *Client) dsyncRemote(remoteAddr string) *dsyncHTTPClient {
+	return &dsyncHTTPClient{URL: remoteAddr + "/remote/dsync", client: c.http()}
+}
+
+// http returns the client's http client, falling back to the default client
+// for clients that weren't created with NewClient
+func (c *Client) http() *http.Client {
+	if c.httpClient == nil {
+		return http.DefaultClient
+	}
+	return c.httpClient
+}
+
+// rootCid parses the root content identifier of a dataset path
+func rootCid(dsPath string) (cid.Cid, error) {
+	rootStr := strings.TrimPrefix(strings.TrimSuffix(dsPath, "/"+dsfs.PackageFileDataset.String()), "/ipfs/")
+	root, err := cid.Parse(rootStr)
+	if err != nil {
+		return cid.Cid{}, fmt.Errorf("invalid dataset path %q: %s", dsPath, err.Error())
+	}
+	return root, nil
+}
+
 // PullDataset fetches a dataset from a remote source
 func (c *Client) PullDataset(ctx context.Context, ref *repo.DatasetRef, remoteAddr string) error {
 	if c == nil {
@@ -138,6 +289,18 @@ func (c *Client) PullDataset(ctx context.Context, ref *repo.DatasetRef, remoteAd
 		return err
 	}
 
+	if addressType(remoteAddr) == "http" {
+		root, err := rootCid(ref.Path)
+		if err != nil {
+			return err
+		}
+		rem := c.dsyncRemote(remoteAddr)
+		if err = c.pullDAG(ctx, rem, root, params); err != nil {
+			return err
+		}
+		return c.capi.Pin().Add(ctx, path.IpfsPath(root))
+	}
+
 	pull, err := c.ds.NewPull(ref.Path, remoteAddr+"/remote/dsync", params)
 	if err != nil {
 		log.Error("creating pull: ", err)
@@ -147,6 +310,155 @@ func (c *Client) PullDataset(ctx context.Context, ref *repo.DatasetRef, remoteAd
 	return pull.Do(ctx)
 }
 
+// pullDAG fetches all blocks of a DAG that are missing from the local store
+// from an HTTP remote. Blocks in a DAG manifest don't depend on each other, so
+// they're requested concurrently, with at most pullConcurrency requests in
+// flight
+func (c *Client) pullDAG(ctx context.Context, rem *dsyncHTTPClient, id cid.Cid, meta map[string]string) error {
+	info, err := rem.GetDagInfo(ctx, id.String(), meta)
+	if err != nil {
+		log.Errorf("getting dag info: %s", err.Error())
+		return err
+	}
+
+	missing, err := dag.Missing(ctx, c.lng, info.Manifest)
+	if err != nil {
+		return err
+	}
+	log.Debugf("pulling %d of %d blocks for %s", len(missing.Nodes), len(info.Manifest.Nodes), id)
+
+	return fetchBlocks(ctx, rem, missing.Nodes, c.pullConcurrency, func(hash string, data []byte) error {
+		return c.putBlock(ctx, hash, data)
+	})
+}
+
+// putBlock writes block data to the local block store, checking the data
+// matches the hash it was requested by
+func (c *Client) putBlock(ctx context.Context, hash string, data []byte) error {
+	id, err := cid.Parse(hash)
+	if err != nil {
+		return err
+	}
+	stat, err := c.capi.Block().Put(ctx, bytes.NewReader(data), options.Block.Format(blockFormat(id)))
+	if err != nil {
+		return err
+	}
+	if !stat.Path().Cid().Equals(id) {
+		return fmt.Errorf("remote sent data that doesn't match block %s", hash)
+	}
+	return nil
+}
+
+// blockFormat gives the block API format name for the codec of a cid
+func blockFormat(id cid.Cid) string {
+	switch id.Type() {
+	case cid.Raw:
+		return "raw"
+	case cid.DagCBOR:
+		return "cbor"
+	}
+	if id.Version() == 0 {
+		return "v0"
+	}
+	return "protobuf"
+}
+
+// PullDatasetComponents fetches the root of a dataset from a remote along with
+// the named components, leaving all other components on the remote. Component
+// names are package filenames without an extension, eg: "meta" or "body".
+// Fetched components are pinned, the dataset root is pinned non-recursively
+func (c *Client) PullDatasetComponents(ctx context.Context, ref *repo.DatasetRef, remoteAddr string, components []string) error {
+	return c.pullDatasetLinks(ctx, ref, remoteAddr, func(component string) bool {
+		return containsComponent(components, component)
+	})
+}
+
+// PullDatasetExcept fetches a dataset from a remote, skipping the named
+// components. Skipped components can be fetched later with
+// PullDatasetComponents
+func (c *Client) PullDatasetExcept(ctx context.Context, ref *repo.DatasetRef, remoteAddr string, skip []string) error {
+	return c.pullDatasetLinks(ctx, ref, remoteAddr, func(component string) bool {
+		return !containsComponent(skip, component)
+	})
+}
+
+func (c *Client) pullDatasetLinks(ctx context.Context, ref *repo.DatasetRef, remoteAddr string, want func(component string) bool) error {
+	if c == nil {
+		return ErrNoRemoteClient
+	}
+	if addressType(remoteAddr) != "http" {
+		return fmt.Errorf("partial dataset pulls currently only work over HTTP")
+	}
+	log.Debugf("pulling partial dataset: %s from %s", ref.String(), remoteAddr)
+
+	if ref.Path == "" {
+		if err := c.ResolveHeadRef(ctx, ref, remoteAddr); err != nil {
+			log.Errorf("resolving head ref: %s", err.Error())
+			return err
+		}
+	}
+
+	params, err := sigParams(c.pk, *ref)
+	if err != nil {
+		log.Error("generating sig params: ", err)
+		return err
+	}
+
+	root, err := rootCid(ref.Path)
+	if err != nil {
+		return err
+	}
+
+	// the root block is a directory listing the dataset's components. write it
+	// to the local block store so component links can be resolved by name
+	rem := c.dsyncRemote(remoteAddr)
+	data, err := rem.GetBlock(ctx, root.String())
+	if err != nil {
+		log.Errorf("getting dataset root block: %s", err.Error())
+		return err
+	}
+	if err = c.putBlock(ctx, root.String(), data); err != nil {
+		return err
+	}
+
+	links, err := c.capi.Object().Links(ctx, path.IpfsPath(root))
+	if err != nil {
+		return err
+	}
+
+	for _, lnk := range links {
+		if !want(componentName(lnk.Name)) {
+			continue
+		}
+		if err = c.pullDAG(ctx, rem, lnk.Cid, params); err != nil {
+			return fmt.Errorf("pulling %s: %s", lnk.Name, err.Error())
+		}
+		if err = c.capi.Pin().Add(ctx, path.IpfsPath(lnk.Cid)); err != nil {
+			return err
+		}
+	}
+
+	return c.capi.Pin().Add(ctx, path.IpfsPath(root), options.Pin.Recursive(false))
+}
+
+// componentName strips the extension from a package filename, "body.csv"
+// becomes "body"
+func componentName(filename string) string {
+	if i := strings.Index(filename, "."); i > 0 {
+		return filename[:i]
+	}
+	return filename
+}
+
+func containsComponent(components []string, component string) bool {
+	for _, c := range components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveDataset asks a remote to remove a dataset
 func (c *Client) RemoveDataset(ctx context.Context, ref repo.DatasetRef, remoteAddr string) error {
 	if c == nil {
@@ -161,7 +473,7 @@ func (c *Client) RemoveDataset(ctx context.Context, ref repo.DatasetRef, remoteA
 
 	switch addressType(remoteAddr) {
 	case "http":
-		return removeDatasetHTTP(ctx, params, remoteAddr)
+		return removeDatasetHTTP(ctx, c.http(), params, remoteAddr)
 	default:
 		return fmt.Errorf("dataset remove requests currently only work over HTTP")
 	}
@@ -176,13 +488,13 @@ func (c *Client) ResolveHeadRef(ctx context.Context, ref *repo.DatasetRef, remot
 
 	switch addressType(remoteAddr) {
 	case "http":
-		return resolveHeadRefHTTP(ctx, ref, remoteAddr)
+		return resolveHeadRefHTTP(ctx, c.http(), ref, remoteAddr)
 	default:
 		return fmt.Errorf("dataset name resolution currently only works over HTTP")
 	}
 }
 
-func resolveHeadRefHTTP(ctx context.Context, ref *repo.DatasetRef, remoteAddr string) error {
+func resolveHeadRefHTTP(ctx context.Context, cli *http.Client, ref *repo.DatasetRef, remoteAddr string) error {
 	u, err := url.Parse(remoteAddr)
 	if err != nil {
 		return err
@@ -203,12 +515,14 @@ func resolveHeadRefHTTP(ctx context.Context, ref *repo.DatasetRef, remoteAddr st
 
 	req = req.WithContext(ctx)
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := cli.Do(req)
 	if err != nil {
 		return err
 	}
 
-	if res.StatusCode != http.StatusOK {
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	} else if res.StatusCode != http.StatusOK {
 		errMsg, _ := ioutil.ReadAll(res.Body)
 		return fmt.Errorf("resolving dataset ref from remote failed: %s", string(errMsg))
 	}
@@ -216,7 +530,7 @@ func resolveHeadRefHTTP(ctx context.Context, ref *repo.DatasetRef, remoteAddr st
 	return json.NewDecoder(res.Body).Decode(ref)
 }
 
-func removeDatasetHTTP(ctx context.Context, params map[string]string, remoteAddr string) error {
+func removeDatasetHTTP(ctx context.Context, cli *http.Client, params map[string]string, remoteAddr string) error {
 	u, err := url.Parse(remoteAddr)
 	if err != nil {
 		return err
@@ -238,7 +552,7 @@ func removeDatasetHTTP(ctx context.Context, params map[string]string, remoteAddr
 
 	req = req.WithContext(ctx)
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := cli.Do(req)
 	if err != nil {
 		return err
 	}
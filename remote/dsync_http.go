@@ -0,0 +1,208 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/qri-io/dag"
+	"github.com/qri-io/dag/dsync"
+)
+
+// dsyncHTTPClient is the request side of dsync over HTTP. It speaks the same
+// protocol as dsync.HTTPClient, but sends requests with a client's own
+// http.Client so remote credentials stay scoped to that client
+type dsyncHTTPClient struct {
+	URL    string
+	client *http.Client
+	// pull session id handed out with a manifest by remotes that restrict
+	// the datasets they serve, sent with block requests
+	sid string
+}
+
+// assert at compile time that dsyncHTTPClient is a dsync.DagSyncable
+var _ dsync.DagSyncable = (*dsyncHTTPClient)(nil)
+
+// NewReceiveSession sends a manifest to a remote, starting a session for
+// pushing blocks
+func (rem *dsyncHTTPClient) NewReceiveSession(info *dag.Info, pinOnComplete bool, meta map[string]string) (sid string, diff *dag.Manifest, err error) {
+	buf := &bytes.Buffer{}
+	if err = json.NewEncoder(buf).Encode(info); err != nil {
+		return
+	}
+
+	u, err := rem.url(meta)
+	if err != nil {
+		return
+	}
+	q := u.Query()
+	q.Set("pin", fmt.Sprintf("%t", pinOnComplete))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("POST", u.String(), buf)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := rem.do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	sid = res.Header.Get("sid")
+	diff = &dag.Manifest{}
+	err = json.NewDecoder(res.Body).Decode(diff)
+	return
+}
+
+// ReceiveBlock sends a block to a remote
+func (rem *dsyncHTTPClient) ReceiveBlock(sid, hash string, data []byte) dsync.ReceiveResponse {
+	u, err := rem.url(map[string]string{"sid": sid, "hash": hash})
+	if err != nil {
+		return dsync.ReceiveResponse{Hash: hash, Status: dsync.StatusErrored, Err: err}
+	}
+
+	req, err := http.NewRequest("PUT", u.String(), bytes.NewBuffer(data))
+	if err != nil {
+		return dsync.ReceiveResponse{Hash: hash, Status: dsync.StatusErrored, Err: err}
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	res, err := rem.do(req)
+	if err != nil {
+		return dsync.ReceiveResponse{Hash: hash, Status: dsync.StatusErrored, Err: err}
+	}
+	res.Body.Close()
+
+	return dsync.ReceiveResponse{Hash: hash, Status: dsync.StatusOk}
+}
+
+// GetDagInfo fetches a manifest from a remote
+func (rem *dsyncHTTPClient) GetDagInfo(ctx context.Context, id string, meta map[string]string) (*dag.Info, error) {
+	u, err := rem.url(meta)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("manifest", id)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rem.do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	rem.sid = res.Header.Get("sid")
+	info := &dag.Info{}
+	err = json.NewDecoder(res.Body).Decode(info)
+	return info, err
+}
+
+// GetBlock fetches a block from a remote
+func (rem *dsyncHTTPClient) GetBlock(ctx context.Context, id string) ([]byte, error) {
+	params := map[string]string{"block": id}
+	if rem.sid != "" {
+		params["sid"] = rem.sid
+	}
+	u, err := rem.url(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := rem.do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return ioutil.ReadAll(res.Body)
+}
+
+// RemoveCID asks a remote to remove a CID
+func (rem *dsyncHTTPClient) RemoveCID(ctx context.Context, id string, meta map[string]string) error {
+	u, err := rem.url(meta)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	q.Set("cid", id)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := rem.do(req.WithContext(ctx))
+	if err != nil {
+		if rerr, ok := err.(remoteError); ok && rerr.msg == dsync.ErrRemoveNotSupported.Error() {
+			return dsync.ErrRemoveNotSupported
+		}
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// url builds a request URL with params added to the query string
+func (rem *dsyncHTTPClient) url(params map[string]string) (*url.URL, error) {
+	u, err := url.Parse(rem.URL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	for key, val := range params {
+		q.Set(key, val)
+	}
+	u.RawQuery = q.Encode()
+	return u, nil
+}
+
+// do sends a request, turning non-200 responses into errors
+func (rem *dsyncHTTPClient) do(req *http.Request) (*http.Response, error) {
+	cli := rem.client
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+
+	res, err := cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		var msg string
+		if data, err := ioutil.ReadAll(res.Body); err == nil {
+			msg = string(data)
+		}
+		return nil, remoteError{status: res.StatusCode, msg: msg}
+	}
+	return res, nil
+}
+
+// remoteError is a non-200 response from a remote
+type remoteError struct {
+	status int
+	msg    string
+}
+
+func (e remoteError) Error() string {
+	return fmt.Sprintf("remote error: %d %s", e.status, e.msg)
+}
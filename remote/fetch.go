@@ -0,0 +1,75 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultPullConcurrency is the number of blocks requested from a remote at
+// once when pulling, used when a client isn't configured with a value
+const DefaultPullConcurrency = 8
+
+// blockGetter fetches raw block data by hash
+type blockGetter interface {
+	GetBlock(ctx context.Context, hash string) ([]byte, error)
+}
+
+// fetchBlocks requests a list of blocks from a remote using a fixed pool of
+// workers, calling put with the data of each block. put is called
+// concurrently. Blocks are handed to workers only as they become free, so a
+// remote never has more than workers requests in flight. The first error
+// stops any remaining requests
+func fetchBlocks(ctx context.Context, rem blockGetter, hashes []string, workers int, put func(hash string, data []byte) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(hashes) {
+		workers = len(hashes)
+	}
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg     sync.WaitGroup
+		queue  = make(chan string)
+		errs   = make(chan error, workers)
+		worker = func() {
+			defer wg.Done()
+			for hash := range queue {
+				data, err := rem.GetBlock(fetchCtx, hash)
+				if err == nil {
+					err = put(hash, data)
+				}
+				if err != nil {
+					errs <- fmt.Errorf("fetching block %s: %s", hash, err.Error())
+					cancel()
+					return
+				}
+			}
+		}
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go worker()
+	}
+
+enqueue:
+	for _, hash := range hashes {
+		select {
+		case queue <- hash:
+		case <-fetchCtx.Done():
+			break enqueue
+		}
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
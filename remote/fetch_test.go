@@ -0,0 +1,97 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testBlockGetter struct {
+	lk       sync.Mutex
+	inFlight int
+	maxSeen  int
+	fail     string
+}
+
+func (g *testBlockGetter) GetBlock(ctx context.Context, hash string) ([]byte, error) {
+	g.lk.Lock()
+	g.inFlight++
+	if g.inFlight > g.maxSeen {
+		g.maxSeen = g.inFlight
+	}
+	g.lk.Unlock()
+
+	defer func() {
+		g.lk.Lock()
+		g.inFlight--
+		g.lk.Unlock()
+	}()
+
+	time.Sleep(time.Millisecond * 5)
+	if hash == g.fail {
+		return nil, fmt.Errorf("not found")
+	}
+	return []byte("data:" + hash), nil
+}
+
+func TestFetchBlocks(t *testing.T) {
+	ctx := context.Background()
+	hashes := make([]string, 40)
+	for i := range hashes {
+		hashes[i] = fmt.Sprintf("block_%d", i)
+	}
+
+	rem := &testBlockGetter{}
+	got := map[string]string{}
+	lk := sync.Mutex{}
+	put := func(hash string, data []byte) error {
+		lk.Lock()
+		defer lk.Unlock()
+		got[hash] = string(data)
+		return nil
+	}
+
+	if err := fetchBlocks(ctx, rem, hashes, 4, put); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(hashes) {
+		t.Errorf("expected %d blocks, got: %d", len(hashes), len(got))
+	}
+	for _, hash := range hashes {
+		if got[hash] != "data:"+hash {
+			t.Errorf("block %s data mismatch. got: %q", hash, got[hash])
+		}
+	}
+	if rem.maxSeen > 4 {
+		t.Errorf("expected no more than 4 requests in flight, saw: %d", rem.maxSeen)
+	}
+	if rem.maxSeen < 2 {
+		t.Errorf("expected blocks to be fetched concurrently, saw at most %d requests in flight", rem.maxSeen)
+	}
+
+	rem = &testBlockGetter{fail: "block_3"}
+	err := fetchBlocks(ctx, rem, hashes, 4, put)
+	expect := "fetching block block_3: not found"
+	if err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+
+	putErr := func(hash string, data []byte) error {
+		return fmt.Errorf("store full")
+	}
+	if err := fetchBlocks(ctx, &testBlockGetter{}, hashes, 4, putErr); err == nil {
+		t.Error("expected put error to stop the fetch")
+	}
+
+	if err := fetchBlocks(ctx, &testBlockGetter{}, nil, 4, put); err != nil {
+		t.Errorf("fetching no blocks: %s", err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := fetchBlocks(canceled, &testBlockGetter{}, hashes, 4, put); err != context.Canceled {
+		t.Errorf("expected canceled context error, got: %v", err)
+	}
+}
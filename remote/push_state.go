@@ -0,0 +1,167 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/qri-io/dag"
+	"github.com/qri-io/dag/dsync"
+)
+
+// pushStateSaveInterval is the number of blocks sent between writes of push
+// state to disk
+const pushStateSaveInterval = 100
+
+// PushState records the progress of a push that hasn't completed, so it can be
+// resumed later
+type PushState struct {
+	// Path of the dataset being pushed
+	Path string `json:"path"`
+	// RemoteAddr is the address of the remote the dataset is being pushed to
+	RemoteAddr string `json:"remoteAddr"`
+	// Completed is the list of block hashes the remote has confirmed receiving
+	Completed []string `json:"completed"`
+}
+
+// pushStateStore persists partial push states as JSON files in a directory,
+// keyed by dataset path
+type pushStateStore struct {
+	dir string
+}
+
+func (s pushStateStore) filepath(path string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.json", pathKey(path)))
+}
+
+// Get loads the state of a partial push for a dataset path. returns nil if
+// no state exists
+func (s pushStateStore) Get(path string) (*PushState, error) {
+	data, err := ioutil.ReadFile(s.filepath(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	state := &PushState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("reading push state for %s: %s", path, err)
+	}
+	return state, nil
+}
+
+// Put writes the state of a partial push
+func (s pushStateStore) Put(state *PushState) error {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.filepath(state.Path), data, 0644)
+}
+
+// Delete removes the state of a partial push, it's not an error to delete
+// state that doesn't exist
+func (s pushStateStore) Delete(path string) error {
+	if err := os.Remove(s.filepath(path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// pathKey converts a dataset path into a string that's safe to use as a
+// filename
+func pathKey(path string) string {
+	buf := make([]rune, 0, len(path))
+	for _, r := range path {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			buf = append(buf, r)
+		default:
+			buf = append(buf, '_')
+		}
+	}
+	return string(buf)
+}
+
+// resumableRemote wraps a remote, recording blocks the remote confirms
+// receiving in a push state
+type resumableRemote struct {
+	dsync.DagSyncable
+
+	store pushStateStore
+	lk    sync.Mutex
+	state *PushState
+	sent  int
+}
+
+// NewReceiveSession asks the remote for the blocks it's missing. The remote's
+// diff is authoritative: blocks confirmed by earlier attempts of the same push
+// are only skipped if the remote no longer reports them missing. Confirmed
+// blocks the remote asks for again, as remotes that require all blocks do,
+// are dropped from the recorded progress & sent again
+func (r *resumableRemote) NewReceiveSession(info *dag.Info, pinOnComplete bool, meta map[string]string) (sid string, diff *dag.Manifest, err error) {
+	sid, diff, err = r.DagSyncable.NewReceiveSession(info, pinOnComplete, meta)
+	if err != nil {
+		return sid, diff, err
+	}
+
+	if diff != nil && info.Manifest != nil {
+		r.lk.Lock()
+		r.state.Completed = without(r.state.Completed, diff.Nodes)
+		resumed := len(r.state.Completed)
+		r.lk.Unlock()
+
+		if resumed > 0 {
+			log.Infof("resuming push of %s, %d blocks sent by earlier attempts, remote has %d/%d blocks", r.state.Path, resumed, len(info.Manifest.Nodes)-len(diff.Nodes), len(info.Manifest.Nodes))
+		}
+	}
+	return sid, diff, err
+}
+
+// ReceiveBlock sends a block to the remote, recording success
+func (r *resumableRemote) ReceiveBlock(sid, hash string, data []byte) dsync.ReceiveResponse {
+	res := r.DagSyncable.ReceiveBlock(sid, hash, data)
+	if res.Status != dsync.StatusOk {
+		return res
+	}
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.state.Completed = append(r.state.Completed, hash)
+	r.sent++
+	if r.sent%pushStateSaveInterval == 0 {
+		if err := r.store.Put(r.state); err != nil {
+			log.Debugf("saving push state: %s", err)
+		}
+	}
+	return res
+}
+
+// save writes the current push state to the store
+func (r *resumableRemote) save() error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.store.Put(r.state)
+}
+
+// without returns the elements of a that aren't in b
+func without(a, b []string) []string {
+	exclude := make(map[string]bool, len(b))
+	for _, s := range b {
+		exclude[s] = true
+	}
+	res := make([]string, 0, len(a))
+	for _, s := range a {
+		if !exclude[s] {
+			res = append(res, s)
+		}
+	}
+	return res
+}
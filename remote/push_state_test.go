@@ -0,0 +1,189 @@
+package remote
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dag"
+	"github.com/qri-io/dag/dsync"
+)
+
+func TestPushStateStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "push_state_store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := pushStateStore{dir: dir}
+	path := "/ipfs/QmZrmGvTPMCkJYfqaagFZBUWuX5bkqSXu179eNnFfhCKze"
+
+	got, err := s.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("expected missing state to be nil. got: %v", got)
+	}
+
+	state := &PushState{Path: path, RemoteAddr: "http://localhost:2503", Completed: []string{"a", "b"}}
+	if err := s.Put(state); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = s.Get(path); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(state, got) {
+		t.Errorf("state mismatch. expected: %v, got: %v", state, got)
+	}
+
+	if err := s.Delete(path); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = s.Get(path); err != nil || got != nil {
+		t.Errorf("expected deleted state to be nil. got: %v, %v", got, err)
+	}
+	if err := s.Delete(path); err != nil {
+		t.Errorf("deleting missing state shouldn't error. got: %s", err)
+	}
+}
+
+func TestPathKey(t *testing.T) {
+	expect := "_ipfs_QmZrmGvTPMCkJYfqaagFZBUWuX5bkqSXu179eNnFfhCKze_dataset_json"
+	if got := pathKey("/ipfs/QmZrmGvTPMCkJYfqaagFZBUWuX5bkqSXu179eNnFfhCKze/dataset.json"); got != expect {
+		t.Errorf("expected: %q, got: %q", expect, got)
+	}
+}
+
+// testSyncable is a remote that already has some blocks
+type testSyncable struct {
+	dsync.DagSyncable
+	has      map[string]bool
+	failFrom int
+	received int
+}
+
+func (s *testSyncable) NewReceiveSession(info *dag.Info, pinOnComplete bool, meta map[string]string) (string, *dag.Manifest, error) {
+	diff := &dag.Manifest{}
+	for _, hash := range info.Manifest.Nodes {
+		if !s.has[hash] {
+			diff.Nodes = append(diff.Nodes, hash)
+		}
+	}
+	return "sid", diff, nil
+}
+
+func (s *testSyncable) ReceiveBlock(sid, hash string, data []byte) dsync.ReceiveResponse {
+	if s.failFrom > 0 && s.received >= s.failFrom {
+		return dsync.ReceiveResponse{Hash: hash, Status: dsync.StatusErrored, Err: fmt.Errorf("connection lost")}
+	}
+	s.received++
+	s.has[hash] = true
+	return dsync.ReceiveResponse{Hash: hash, Status: dsync.StatusOk}
+}
+
+func TestResumableRemote(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resumable_remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := pushStateStore{dir: dir}
+	info := &dag.Info{Manifest: &dag.Manifest{Nodes: []string{"a", "b", "c", "d"}}}
+	remote := &testSyncable{has: map[string]bool{"a": true, "c": true}}
+	rem := &resumableRemote{
+		DagSyncable: remote,
+		store:       store,
+		// "b" was confirmed in an earlier attempt, but the remote reports it
+		// missing
+		state: &PushState{Path: "/ipfs/root", Completed: []string{"b", "c"}},
+	}
+
+	_, diff, err := rem.NewReceiveSession(info, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"b", "d"}; !reflect.DeepEqual(expect, diff.Nodes) {
+		t.Errorf("expected the remote's missing blocks to be sent. expected: %v, got: %v", expect, diff.Nodes)
+	}
+	if expect := []string{"c"}; !reflect.DeepEqual(expect, rem.state.Completed) {
+		t.Errorf("expected blocks the remote is missing to be dropped from progress. expected: %v, got: %v", expect, rem.state.Completed)
+	}
+
+	// remotes that ask for every block drop recorded progress
+	remote = &testSyncable{has: map[string]bool{}}
+	rem = &resumableRemote{
+		DagSyncable: remote,
+		store:       store,
+		state:       &PushState{Path: "/ipfs/root", Completed: []string{"a", "b"}},
+	}
+	if _, diff, err = rem.NewReceiveSession(info, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(info.Manifest.Nodes, diff.Nodes) {
+		t.Errorf("expected all blocks to be sent. got: %v", diff.Nodes)
+	}
+	if len(rem.state.Completed) != 0 {
+		t.Errorf("expected recorded progress to be dropped. got: %v", rem.state.Completed)
+	}
+}
+
+func TestResumableRemoteInterruptAndResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resumable_remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := pushStateStore{dir: dir}
+	info := &dag.Info{Manifest: &dag.Manifest{Nodes: []string{"a", "b", "c", "d"}}}
+	// the connection drops after two blocks
+	remote := &testSyncable{has: map[string]bool{"a": true}, failFrom: 2}
+
+	push := func() (sent []string) {
+		state, err := store.Get("/ipfs/root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state == nil {
+			state = &PushState{Path: "/ipfs/root"}
+		}
+		rem := &resumableRemote{DagSyncable: remote, store: store, state: state}
+		_, diff, err := rem.NewReceiveSession(info, true, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, hash := range diff.Nodes {
+			if res := rem.ReceiveBlock("sid", hash, nil); res.Status != dsync.StatusOk {
+				break
+			}
+			sent = append(sent, hash)
+		}
+		if err := rem.save(); err != nil {
+			t.Fatal(err)
+		}
+		return sent
+	}
+
+	if sent, expect := push(), []string{"b", "c"}; !reflect.DeepEqual(expect, sent) {
+		t.Errorf("first attempt sent blocks mismatch. expected: %v, got: %v", expect, sent)
+	}
+
+	// reconnect & resume
+	remote.failFrom = 0
+	if sent, expect := push(), []string{"d"}; !reflect.DeepEqual(expect, sent) {
+		t.Errorf("resumed attempt sent blocks mismatch. expected: %v, got: %v", expect, sent)
+	}
+
+	state, err := store.Get("/ipfs/root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"b", "c", "d"}; !reflect.DeepEqual(expect, state.Completed) {
+		t.Errorf("completed blocks mismatch. expected: %v, got: %v", expect, state.Completed)
+	}
+}
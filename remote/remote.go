@@ -6,11 +6,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
+	"strings"
 	"time"
 
 	golog "github.com/ipfs/go-log"
 	"github.com/qri-io/dag"
 	"github.com/qri-io/dag/dsync"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
@@ -37,6 +41,9 @@ type Options struct {
 	DatasetRemoved Hook
 	// called when a client pulls a dataset
 	DatasetPulled Hook
+	// UnpinPrevious unpins the prior version of a dataset when a new version
+	// is pushed, keeping only the latest version pinned
+	UnpinPrevious bool
 }
 
 // Remote receives requests from other qri nodes to perform actions on their
@@ -48,6 +55,10 @@ type Remote struct {
 	acceptSizeMax int64
 	// TODO (b5) - dsync needs to use timeouts
 	acceptTimeoutMs time.Duration
+	requireLicense  bool
+	unpinPrevious   bool
+	access          accessList
+	pulls           pullSessions
 
 	acceptPushPreCheck   Hook
 	acceptPushFinalCheck Hook
@@ -63,11 +74,19 @@ func NewRemote(node *p2p.QriNode, cfg *config.Remote, opts ...func(o *Options))
 		opt(o)
 	}
 
+	access, err := newAccessList(cfg.Allow, cfg.Deny)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &Remote{
 		node: node,
 
 		acceptSizeMax:   cfg.AcceptSizeMax,
 		acceptTimeoutMs: cfg.AcceptTimeoutMs,
+		requireLicense:  cfg.RequireLicense,
+		unpinPrevious:   o.UnpinPrevious,
+		access:          access,
 
 		acceptPushPreCheck:   o.AcceptPushPreCheck,
 		acceptPushFinalCheck: o.AcceptPushFinalCheck,
@@ -173,6 +192,16 @@ func (r *Remote) pushPreCheck(ctx context.Context, info dag.Info, meta map[strin
 }
 
 func (r *Remote) pushFinalCheck(ctx context.Context, info dag.Info, meta map[string]string) error {
+	if r.requireLicense {
+		_, ref, err := r.pidAndRefFromMeta(meta)
+		if err != nil {
+			return err
+		}
+		if err := r.checkLicense(ctx, ref); err != nil {
+			return err
+		}
+	}
+
 	if r.acceptPushFinalCheck != nil {
 		pid, ref, err := r.pidAndRefFromMeta(meta)
 		if err != nil {
@@ -186,6 +215,18 @@ func (r *Remote) pushFinalCheck(ctx context.Context, info dag.Info, meta map[str
 	return nil
 }
 
+// checkLicense confirms a pushed dataset records a license in metadata
+func (r *Remote) checkLicense(ctx context.Context, ref repo.DatasetRef) error {
+	ds, err := dsfs.LoadDataset(ctx, r.node.Repo.Store(), ref.Path)
+	if err != nil {
+		return err
+	}
+	if !base.HasLicense(ds) {
+		return fmt.Errorf("dataset %s must have a license to be published to this remote. set one with qri save --license", ref.AliasString())
+	}
+	return nil
+}
+
 func (r *Remote) pushComplete(ctx context.Context, info dag.Info, meta map[string]string) error {
 	pid, ref, err := r.pidAndRefFromMeta(meta)
 	if err != nil {
@@ -206,10 +247,25 @@ func (r *Remote) pushComplete(ctx context.Context, info dag.Info, meta map[strin
 		}
 	}
 
+	// look up the version being replaced before the ref is overwritten
+	prev := repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}
+	if err := repo.CanonicalizeDatasetRef(r.node.Repo, &prev); err != nil {
+		prev.Path = ""
+	}
+
 	// add completed pushed dataset to our refs
 	// TODO (b5) - this could overwrite any FSI links & other ref details,
 	// need to investigate
-	return r.node.Repo.PutRef(ref)
+	if err = r.node.Repo.PutRef(ref); err != nil {
+		return err
+	}
+
+	if r.unpinPrevious && prev.Path != "" && prev.Path != ref.Path {
+		if err := base.UnpinDataset(ctx, r.node.Repo, prev); err != nil && err != repo.ErrNotPinner {
+			log.Debugf("unpinning previous version %s: %s", prev.Path, err)
+		}
+	}
+	return nil
 }
 
 func (r *Remote) removeCheck(ctx context.Context, info dag.Info, meta map[string]string) error {
@@ -233,6 +289,27 @@ func (r *Remote) getDagInfo(ctx context.Context, into dag.Info, meta map[string]
 		return err
 	}
 
+	if !r.access.Allowed(ref) {
+		log.Debugf("denied dag info request for %s", ref)
+		return ErrAccessDenied
+	}
+
+	if r.access.Restricted() {
+		// a manifest can list any blocks, only hand out manifests of versions
+		// of the dataset that was allowed
+		if into.Manifest == nil || len(into.Manifest.Nodes) == 0 {
+			log.Debugf("denied dag info request for %s: empty manifest", ref)
+			return ErrAccessDenied
+		}
+		if err = r.checkVersionOf(ctx, ref, into.Manifest.Nodes[0]); err != nil {
+			log.Debugf("denied dag info request for %s: %s", ref, err.Error())
+			return ErrAccessDenied
+		}
+		if sid := meta["sid"]; sid != "" {
+			r.pulls.add(sid, into.Manifest.Nodes)
+		}
+	}
+
 	if r.datasetPulled != nil {
 		if err = r.datasetPulled(ctx, pid, ref); err != nil {
 			log.Errorf("dataset pulled hook: %s", err.Error())
@@ -258,9 +335,82 @@ func (r *Remote) pidAndRefFromMeta(meta map[string]string) (profile.ID, repo.Dat
 	return pid, ref, err
 }
 
-// DsyncHTTPHandler provides an http handler for dsync
+// checkVersionOf errors if a root hash isn't the root of a version in the
+// history of a named dataset
+func (r *Remote) checkVersionOf(ctx context.Context, ref repo.DatasetRef, root string) error {
+	if root == "" {
+		return fmt.Errorf("empty manifest")
+	}
+	head := repo.DatasetRef{Peername: ref.Peername, Name: ref.Name}
+	if err := repo.CanonicalizeDatasetRef(r.node.Repo, &head); err != nil {
+		return err
+	}
+
+	for p := head.Path; p != ""; {
+		// dataset paths are /[store]/[root cid], optionally with a
+		// trailing /dataset.json
+		if path.Base(strings.TrimSuffix(p, "/"+dsfs.PackageFileDataset.String())) == root {
+			return nil
+		}
+		ds, err := dsfs.LoadDatasetRefs(ctx, r.node.Repo.Store(), p)
+		if err != nil {
+			return err
+		}
+		p = ds.PreviousPath
+	}
+	return fmt.Errorf("%s is not a version of %s/%s", root, ref.Peername, ref.Name)
+}
+
+// DsyncHTTPHandler provides an http handler for dsync. Requests to fetch a
+// dataset that the remote's access list doesn't permit, or the manifest of
+// anything but a version of a permitted dataset, are rejected before reaching
+// dsync. When the access list is restricted, manifest responses
+// carry a session id in the "sid" header, and blocks are only served to
+// requests that give the id of a session whose manifest lists the block
 func (r *Remote) DsyncHTTPHandler() http.HandlerFunc {
-	return dsync.HTTPRemoteHandler(r.dsync)
+	handler := dsync.HTTPRemoteHandler(r.dsync)
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == "GET" && r.access.Restricted() {
+			q := req.URL.Query()
+			if hash := q.Get("block"); hash != "" {
+				if !r.pulls.has(q.Get("sid"), hash) {
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(ErrAccessDenied.Error()))
+					return
+				}
+			} else {
+				ref := repo.DatasetRef{
+					Peername: q.Get("peername"),
+					Name:     q.Get("name"),
+				}
+				if !r.access.Allowed(ref) {
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(ErrAccessDenied.Error()))
+					return
+				}
+				if err := r.checkVersionOf(req.Context(), ref, q.Get("manifest")); err != nil {
+					log.Debugf("denied manifest request for %s: %s", ref, err.Error())
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(ErrAccessDenied.Error()))
+					return
+				}
+
+				// replace any client-provided session id, the getDagInfo hook
+				// reads it from request meta
+				sid, err := newPullSessionID()
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte(err.Error()))
+					return
+				}
+				q.Set("sid", sid)
+				req.URL.RawQuery = q.Encode()
+				req.Form = nil
+				w.Header().Set("sid", sid)
+			}
+		}
+		handler(w, req)
+	}
 }
 
 // RefsHTTPHandler handles requests for dataset references
@@ -272,6 +422,11 @@ func (r *Remote) RefsHTTPHandler() http.HandlerFunc {
 				Peername: req.FormValue("peername"),
 				Name:     req.FormValue("name"),
 			}
+			if !r.access.Allowed(*ref) {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(ErrAccessDenied.Error()))
+				return
+			}
 			if err := repo.CanonicalizeDatasetRef(r.node.Repo, ref); err != nil {
 				w.WriteHeader(http.StatusNotFound)
 				w.Write([]byte(err.Error()))
@@ -1,7 +1,20 @@
 package remote
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
 	"testing"
+
+	"github.com/qri-io/dag"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/p2p"
+	"github.com/qri-io/qri/repo"
+	testrepo "github.com/qri-io/qri/repo/test"
 )
 
 func TestAddress(t *testing.T) {
@@ -15,3 +28,181 @@ func TestPushDataset(t *testing.T) {
 func TestRemoveDataset(t *testing.T) {
 	t.Skip("TODO (b5)")
 }
+
+func TestComponentName(t *testing.T) {
+	cases := map[string]string{
+		"body.csv":         "body",
+		"body.json":        "body",
+		"meta.json":        "meta",
+		"transform_script": "transform_script",
+		"":                 "",
+	}
+	for filename, expect := range cases {
+		if got := componentName(filename); got != expect {
+			t.Errorf("componentName(%q) expected: %q, got: %q", filename, expect, got)
+		}
+	}
+}
+
+func TestPullDatasetComponentsRequiresHTTP(t *testing.T) {
+	ctx := context.Background()
+	var c *Client
+	if err := c.PullDatasetComponents(ctx, &repo.DatasetRef{}, "http://localhost", []string{"body"}); err != ErrNoRemoteClient {
+		t.Errorf("expected nil client to return ErrNoRemoteClient. got: %v", err)
+	}
+	c = &Client{}
+	expect := "partial dataset pulls currently only work over HTTP"
+	if err := c.PullDatasetExcept(ctx, &repo.DatasetRef{}, "not_an_address", []string{"body"}); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
+
+func TestPushFinalCheckRequireLicense(t *testing.T) {
+	ctx := context.Background()
+	mr, err := testrepo.NewEmptyTestRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeDataset := func(md *dataset.Meta) (string, error) {
+		ds := &dataset.Dataset{
+			Commit:    &dataset.Commit{Title: "initial commit"},
+			Meta:      md,
+			Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte("[]")))
+		return dsfs.WriteDataset(ctx, mr.Store(), ds, false)
+	}
+
+	unlicensed, err := writeDataset(&dataset.Meta{Title: "no license"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	licensed, err := writeDataset(&dataset.Meta{
+		Title:   "licensed",
+		License: &dataset.License{Type: "CC-BY-4.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pushMeta := func(name, path string) map[string]string {
+		return map[string]string{
+			"peername": "peer",
+			"name":     name,
+			"path":     path,
+			"pid":      "QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt",
+		}
+	}
+
+	r := &Remote{node: node}
+	if err := r.pushFinalCheck(ctx, dag.Info{}, pushMeta("unlicensed", unlicensed)); err != nil {
+		t.Errorf("expected remote without license requirement to accept unlicensed push. got: %s", err)
+	}
+
+	r.requireLicense = true
+	expect := "dataset peer/unlicensed must have a license to be published to this remote. set one with qri save --license"
+	if err := r.pushFinalCheck(ctx, dag.Info{}, pushMeta("unlicensed", unlicensed)); err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+	if err := r.pushFinalCheck(ctx, dag.Info{}, pushMeta("licensed", licensed)); err != nil {
+		t.Errorf("expected licensed push to be accepted. got: %s", err)
+	}
+}
+
+func TestRemoteAccessList(t *testing.T) {
+	ctx := context.Background()
+	mr, err := testrepo.NewTestRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := p2p.NewQriNode(mr, config.DefaultP2PForTesting())
+	if err != nil {
+		t.Fatal(err)
+	}
+	acl, err := newAccessList([]string{"peer/*"}, []string{"peer/movies"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Remote{node: node, access: acl}
+
+	refsCases := []struct {
+		name   string
+		status int
+	}{
+		{"cities", http.StatusOK},
+		{"movies", http.StatusForbidden},
+	}
+	for _, c := range refsCases {
+		req := httptest.NewRequest("GET", "/remote/refs?peername=peer&name="+c.name, nil)
+		w := httptest.NewRecorder()
+		r.RefsHTTPHandler()(w, req)
+		if w.Code != c.status {
+			t.Errorf("refs request for peer/%s status mismatch. expected: %d, got: %d", c.name, c.status, w.Code)
+		}
+	}
+
+	movies := repo.DatasetRef{Peername: "peer", Name: "movies"}
+	if err := repo.CanonicalizeDatasetRef(mr, &movies); err != nil {
+		t.Fatal(err)
+	}
+	pid := "QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt"
+	metaCases := []map[string]string{
+		{"peername": "peer", "name": "movies", "path": movies.Path, "pid": pid},
+		{"path": movies.Path, "pid": pid},
+	}
+	for i, meta := range metaCases {
+		if err := r.getDagInfo(ctx, dag.Info{}, meta); err != ErrAccessDenied {
+			t.Errorf("case %d expected ErrAccessDenied, got: %v", i, err)
+		}
+	}
+
+	cities := repo.DatasetRef{Peername: "peer", Name: "cities"}
+	if err := repo.CanonicalizeDatasetRef(mr, &cities); err != nil {
+		t.Fatal(err)
+	}
+	citiesMeta := map[string]string{"peername": "peer", "name": "cities", "path": cities.Path, "pid": pid, "sid": "session"}
+
+	// allowed names can't be used to fetch the manifest of another dataset
+	moviesInfo := dag.Info{Manifest: &dag.Manifest{Nodes: []string{path.Base(movies.Path), "QmMoviesBody"}}}
+	if err := r.getDagInfo(ctx, moviesInfo, citiesMeta); err != ErrAccessDenied {
+		t.Errorf("expected manifest of another dataset to be denied, got: %v", err)
+	}
+	if r.pulls.has("session", "QmMoviesBody") {
+		t.Error("expected denied manifest not to start a pull session")
+	}
+
+	citiesInfo := dag.Info{Manifest: &dag.Manifest{Nodes: []string{path.Base(cities.Path), "QmCitiesBody"}}}
+	if err := r.getDagInfo(ctx, citiesInfo, citiesMeta); err != nil {
+		t.Fatal(err)
+	}
+	if !r.pulls.has("session", "QmCitiesBody") {
+		t.Error("expected allowed manifest to start a pull session")
+	}
+
+	dsyncCases := []struct {
+		query  string
+		status int
+	}{
+		{"manifest=" + path.Base(movies.Path) + "&path=" + movies.Path, http.StatusForbidden},
+		{"manifest=" + path.Base(movies.Path) + "&peername=peer&name=movies", http.StatusForbidden},
+		// allowed names can't be used to fetch the manifest of a path outside
+		// of the allowed dataset's history
+		{"manifest=" + path.Base(movies.Path) + "&peername=peer&name=cities", http.StatusForbidden},
+		{"block=QmCitiesBody", http.StatusForbidden},
+		{"block=QmCitiesBody&sid=other_session", http.StatusForbidden},
+		{"block=QmMoviesBody&sid=session", http.StatusForbidden},
+	}
+	for i, c := range dsyncCases {
+		req := httptest.NewRequest("GET", "/remote/dsync?"+c.query, nil)
+		w := httptest.NewRecorder()
+		r.DsyncHTTPHandler()(w, req)
+		if w.Code != c.status {
+			t.Errorf("dsync case %d status mismatch. expected: %d, got: %d", i, c.status, w.Code)
+		}
+	}
+}
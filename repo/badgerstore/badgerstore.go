@@ -0,0 +1,332 @@
+// Package badgerstore implements a cafs.Filestore that persists files to a
+// local BadgerDB database, giving single-machine setups durable storage
+// without depending on IPFS
+package badgerstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/badger"
+	golog "github.com/ipfs/go-log"
+	"github.com/qri-io/qfs/cafs"
+	"github.com/qri-io/qri/repo/kvstore"
+)
+
+var log = golog.Logger("badgerstore")
+
+// pathPrefix is the prefix for all paths in the store. the "cafs" prefix is
+// routed to the store by the qri filesystem
+const pathPrefix = "cafs"
+
+// pinKeyPrefix namespaces pin records from stored files
+var pinKeyPrefix = []byte("pin:")
+
+// errReadOnly is returned when writing to a read-only store
+var errReadOnly = fmt.Errorf("badger store is read-only")
+
+// Filestore is a cafs.Filestore backed by BadgerDB
+type Filestore struct {
+	*kvstore.Filestore
+	db       *badger.DB
+	values   *valueStore
+	readOnly bool
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+var (
+	// compile-time assertion that Filestore is a cafs.Filestore
+	_ cafs.Filestore = (*Filestore)(nil)
+	// compile-time assertion that Filestore is a cafs.Pinner
+	_ cafs.Pinner = (*Filestore)(nil)
+)
+
+// NewFilestore opens a BadgerDB database in the directory at path, creating
+// the database if one doesn't exist. A read-only Filestore shares the
+// database with other processes & rejects writes. Filestores hold a lock on
+// their directory until closed
+func NewFilestore(path string, readOnly bool) (*Filestore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("badger store requires a path")
+	}
+	opts := badger.DefaultOptions
+	opts.Dir = path
+	opts.ValueDir = path
+	opts.ReadOnly = readOnly
+	opts.Logger = log
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("opening badger store: %s", err)
+	}
+	values := &valueStore{db: db, readOnly: readOnly}
+	return &Filestore{
+		Filestore: kvstore.NewFilestore(pathPrefix, values),
+		db:        db,
+		values:    values,
+		readOnly:  readOnly,
+	}, nil
+}
+
+// ReadOnly reports if the store rejects writes
+func (fst *Filestore) ReadOnly() bool {
+	return fst.readOnly
+}
+
+// Close closes the underlying database, releasing the lock on the store
+// directory. it's safe to call Close more than once
+func (fst *Filestore) Close() error {
+	fst.closeOnce.Do(func() {
+		fst.closeErr = fst.db.Close()
+	})
+	return fst.closeErr
+}
+
+// Size reports the size of the database files on disk
+func (fst *Filestore) Size() (int64, error) {
+	lsm, vlog := fst.db.Size()
+	return lsm + vlog, nil
+}
+
+// NewAdder implements the cafs.Filestore interface
+func (fst *Filestore) NewAdder(pin, wrap bool) (cafs.Adder, error) {
+	if fst.readOnly {
+		return nil, errReadOnly
+	}
+	return fst.Filestore.NewAdder(pin, wrap)
+}
+
+// Pin implements the cafs.Pinner interface. pins are recorded, but the store
+// never removes unpinned files on its own
+func (fst *Filestore) Pin(ctx context.Context, key string, recursive bool) error {
+	if fst.readOnly {
+		return errReadOnly
+	}
+	root, _ := kvstore.SplitKey(key)
+	if has, err := fst.Has(ctx, root); err != nil {
+		return err
+	} else if !has {
+		return cafs.ErrNotFound
+	}
+	fst.values.lk.RLock()
+	defer fst.values.lk.RUnlock()
+	return fst.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(pinKey(root), []byte{})
+	})
+}
+
+// Unpin implements the cafs.Pinner interface
+func (fst *Filestore) Unpin(ctx context.Context, key string, recursive bool) error {
+	if fst.readOnly {
+		return errReadOnly
+	}
+	root, _ := kvstore.SplitKey(key)
+	fst.values.lk.RLock()
+	defer fst.values.lk.RUnlock()
+	return fst.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get(pinKey(root)); err == badger.ErrKeyNotFound {
+			return fmt.Errorf("not pinned")
+		} else if err != nil {
+			return err
+		}
+		return txn.Delete(pinKey(root))
+	})
+}
+
+// Pinned checks if a key is pinned
+func (fst *Filestore) Pinned(ctx context.Context, key string) (pinned bool, err error) {
+	root, _ := kvstore.SplitKey(key)
+	err = fst.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(pinKey(root))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		pinned = true
+		return nil
+	})
+	return pinned, err
+}
+
+// CollectGarbage implements the base.GarbageCollector interface, deleting
+// every stored value that isn't reachable from keep or from a pinned value.
+// Pins on values listed in a directory don't keep them alive on their own, so
+// unpinning a directory makes all of its contents collectable unless some
+// other directory still lists them. Writes to the store wait until collection
+// finishes
+func (fst *Filestore) CollectGarbage(ctx context.Context, keep []string) (reclaimed int64, err error) {
+	if fst.readOnly {
+		return 0, errReadOnly
+	}
+
+	// hold the write lock from mark through sweep, so values written during
+	// collection can't be swept before anything refers to them
+	fst.values.lk.Lock()
+	defer fst.values.lk.Unlock()
+
+	// values are read one at a time while iterating, only value sizes &
+	// directory listings are kept
+	var (
+		sizes    = map[string]int64{}
+		children = map[string][]string{}
+		pinned   = map[string]bool{}
+	)
+	err = fst.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := string(item.KeyCopy(nil))
+			if strings.HasPrefix(key, string(pinKeyPrefix)) {
+				pinned[strings.TrimPrefix(key, string(pinKeyPrefix))] = true
+				continue
+			}
+			sizes[key] = item.ValueSize()
+			err := item.Value(func(value []byte) error {
+				if dirChildren := kvstore.DirChildren(value); len(dirChildren) > 0 {
+					children[key] = dirChildren
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	listed := map[string]bool{}
+	for _, keys := range children {
+		for _, child := range keys {
+			listed[child] = true
+		}
+	}
+
+	roots := make([]string, 0, len(keep))
+	for _, key := range keep {
+		root, _ := kvstore.SplitKey(key)
+		roots = append(roots, root)
+	}
+	for key := range pinned {
+		if !listed[key] {
+			roots = append(roots, key)
+		}
+	}
+
+	// mark
+	reachable := map[string]bool{}
+	for len(roots) > 0 {
+		key := roots[len(roots)-1]
+		roots = roots[:len(roots)-1]
+		if reachable[key] {
+			continue
+		}
+		reachable[key] = true
+		roots = append(roots, children[key]...)
+	}
+
+	// sweep
+	for key, size := range sizes {
+		if reachable[key] {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return reclaimed, err
+		}
+		if err = fst.values.delete(key); err != nil {
+			return reclaimed, err
+		}
+		reclaimed += size
+	}
+	log.Debugf("collected garbage: removed %d bytes, kept %d values", reclaimed, len(reachable))
+	return reclaimed, nil
+}
+
+// valueStore implements kvstore.Store with a badger database
+type valueStore struct {
+	db       *badger.DB
+	readOnly bool
+	// lk is held for reading by writes & for writing by garbage collection
+	lk sync.RWMutex
+}
+
+func (s *valueStore) Get(ctx context.Context, key string) (value []byte, err error) {
+	err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err == badger.ErrKeyNotFound {
+			return cafs.ErrNotFound
+		} else if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	return value, err
+}
+
+// Put writes a value, recording a pin in the same transaction
+func (s *valueStore) Put(ctx context.Context, key string, value []byte, pin bool) error {
+	if s.readOnly {
+		return errReadOnly
+	}
+	s.lk.RLock()
+	defer s.lk.RUnlock()
+	return s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Set([]byte(key), value); err != nil {
+			return err
+		}
+		if pin {
+			return txn.Set(pinKey(key), []byte{})
+		}
+		return nil
+	})
+}
+
+func (s *valueStore) Has(ctx context.Context, key string) (exists bool, err error) {
+	err = s.db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(key))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		exists = true
+		return nil
+	})
+	return exists, err
+}
+
+// Delete removes a stored value & any pin on it
+func (s *valueStore) Delete(ctx context.Context, key string) error {
+	if s.readOnly {
+		return errReadOnly
+	}
+	s.lk.RLock()
+	defer s.lk.RUnlock()
+	return s.delete(key)
+}
+
+// delete removes a value & its pin without taking the lock
+func (s *valueStore) delete(key string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Delete([]byte(key)); err != nil {
+			return err
+		}
+		return txn.Delete(pinKey(key))
+	})
+}
+
+// pinKey gives the database key for a pin record
+func pinKey(key string) []byte {
+	return append(append([]byte{}, pinKeyPrefix...), key...)
+}
@@ -0,0 +1,166 @@
+package badgerstore
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+func newTestStore(t *testing.T) (*Filestore, string, func()) {
+	dir, err := ioutil.TempDir("", "badgerstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fst, err := NewFilestore(dir, false)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return fst, dir, func() {
+		fst.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFilestoreReopen(t *testing.T) {
+	ctx := context.Background()
+	fst, dir, cleanup := newTestStore(t)
+	defer cleanup()
+
+	key, err := fst.Put(ctx, qfs.NewMemdir("/a",
+		qfs.NewMemfileBytes("b.txt", []byte("durable")),
+	), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "LOCK")); err != nil {
+		t.Errorf("expected open store to hold a lock file. got: %s", err)
+	}
+	if err := fst.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "LOCK")); !os.IsNotExist(err) {
+		t.Errorf("expected lock file to be removed on close. got: %v", err)
+	}
+
+	// reopening the store keeps stored files
+	if fst, err = NewFilestore(dir, true); err != nil {
+		t.Fatal(err)
+	}
+	defer fst.Close()
+	if !fst.ReadOnly() {
+		t.Error("expected store to open read-only")
+	}
+	if _, err := fst.Get(ctx, key+"/b.txt"); err != nil {
+		t.Errorf("expected reopened store to keep stored files. got: %s", err)
+	}
+	if _, err := fst.Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("nope")), false); err == nil {
+		t.Error("expected read-only store to reject writes")
+	}
+	if err := fst.Pin(ctx, key, true); err != errReadOnly {
+		t.Errorf("expected pin to return errReadOnly. got: %v", err)
+	}
+	if _, err := fst.CollectGarbage(ctx, nil); err != errReadOnly {
+		t.Errorf("expected garbage collection to return errReadOnly. got: %v", err)
+	}
+}
+
+func TestFilestorePinner(t *testing.T) {
+	ctx := context.Background()
+	fst, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	key, err := fst.Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("hello")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned, _ := fst.Pinned(ctx, key); pinned {
+		t.Error("expected key not to be pinned")
+	}
+	if err := fst.Pin(ctx, key, true); err != nil {
+		t.Fatal(err)
+	}
+	if pinned, _ := fst.Pinned(ctx, key); !pinned {
+		t.Error("expected key to be pinned")
+	}
+	if err := fst.Unpin(ctx, key, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := fst.Unpin(ctx, key, true); err == nil {
+		t.Error("expected unpinning an unpinned key to error")
+	}
+	if err := fst.Pin(ctx, "/cafs/QmNotAKey", true); err != cafs.ErrNotFound {
+		t.Errorf("expected ErrNotFound. got: %v", err)
+	}
+
+	pinnedKey, err := fst.Put(ctx, qfs.NewMemfileBytes("b.txt", []byte("pinned")), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned, _ := fst.Pinned(ctx, pinnedKey); !pinned {
+		t.Error("expected put with pin to pin")
+	}
+}
+
+func TestFilestoreCollectGarbage(t *testing.T) {
+	ctx := context.Background()
+	fst, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	putDir := func(pin bool, files ...qfs.File) string {
+		key, err := fst.Put(ctx, qfs.NewMemdir("/dir", files...), pin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	shared := func() qfs.File { return qfs.NewMemfileBytes("shared.txt", []byte("in both directories")) }
+
+	kept := putDir(false, shared(), qfs.NewMemfileBytes("a.txt", []byte("kept")))
+	garbage := putDir(true, shared(), qfs.NewMemfileBytes("b.txt", []byte("garbage")))
+	pinnedFile, err := fst.Put(ctx, qfs.NewMemfileBytes("photo.jpg", []byte("pinned file")), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	looseFile, err := fst.Put(ctx, qfs.NewMemfileBytes("loose.txt", []byte("loose")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an unpinned directory is collectable even though its contents were pinned
+	if err := fst.Unpin(ctx, garbage, true); err != nil {
+		t.Fatal(err)
+	}
+
+	reclaimed, err := fst.CollectGarbage(ctx, []string{kept + "/dataset.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed == 0 {
+		t.Error("expected garbage collection to reclaim space")
+	}
+
+	for _, key := range []string{kept + "/a.txt", kept + "/shared.txt", pinnedFile} {
+		if _, err := fst.Get(ctx, key); err != nil {
+			t.Errorf("expected %s to be kept. got: %s", key, err)
+		}
+	}
+	for _, key := range []string{garbage, looseFile} {
+		if has, err := fst.Has(ctx, key); err != nil || has {
+			t.Errorf("expected %s to be collected. got has: %t, err: %v", key, has, err)
+		}
+	}
+
+	// collecting again finds nothing to remove
+	if reclaimed, err = fst.CollectGarbage(ctx, []string{kept}); err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed != 0 {
+		t.Errorf("expected second collection to reclaim nothing, got: %d bytes", reclaimed)
+	}
+}
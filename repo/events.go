@@ -1,7 +1,9 @@
 package repo
 
 import (
+	"context"
 	"sort"
+	"sync"
 	"time"
 
 	peer "github.com/libp2p/go-libp2p-peer"
@@ -45,6 +47,61 @@ const (
 	ETCronJobRan = EventType("cron_job_ran")
 )
 
+// EventSubscriber is an opt-in interface for repos that broadcast events to
+// subscribers as they're logged
+type EventSubscriber interface {
+	// SubscribeEvents sends events logged after the subscription began on the
+	// returned channel. The channel is closed when ctx is cancelled
+	SubscribeEvents(ctx context.Context) <-chan *Event
+}
+
+// eventBusBuffer is the number of events a subscriber can fall behind by
+// before it starts missing events
+const eventBusBuffer = 64
+
+// EventBus broadcasts logged events to subscribers. The zero value is an
+// EventBus with no subscribers
+type EventBus struct {
+	lk   sync.Mutex
+	subs map[chan *Event]struct{}
+}
+
+// compile-time assertion that EventBus is an EventSubscriber
+var _ EventSubscriber = (*EventBus)(nil)
+
+// Publish sends an event to every subscriber. Publish doesn't wait on slow
+// subscribers, a subscriber with a full buffer misses the event
+func (b *EventBus) Publish(e *Event) {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// SubscribeEvents implements the EventSubscriber interface
+func (b *EventBus) SubscribeEvents(ctx context.Context) <-chan *Event {
+	ch := make(chan *Event, eventBusBuffer)
+	b.lk.Lock()
+	if b.subs == nil {
+		b.subs = map[chan *Event]struct{}{}
+	}
+	b.subs[ch] = struct{}{}
+	b.lk.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.lk.Lock()
+		delete(b.subs, ch)
+		close(ch)
+		b.lk.Unlock()
+	}()
+	return ch
+}
+
 // MemEventLog is an in-memory implementation of the
 // EventLog interface
 type MemEventLog []*Event
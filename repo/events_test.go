@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
@@ -286,3 +287,39 @@ func TestLogEvent(t *testing.T) {
 	}
 
 }
+
+func TestEventBus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := &EventBus{}
+	// publishing without subscribers is a no-op
+	bus.Publish(&Event{Type: ETDsAdded})
+
+	events := bus.SubscribeEvents(ctx)
+	bus.Publish(&Event{Type: ETDsPinned})
+	bus.Publish(&Event{Type: ETDsCreated})
+
+	for _, expect := range []EventType{ETDsPinned, ETDsCreated} {
+		select {
+		case e := <-events:
+			if e.Type != expect {
+				t.Errorf("expected event type %s, got: %s", expect, e.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s event", expect)
+		}
+	}
+
+	// a subscriber that falls behind misses events instead of blocking
+	for i := 0; i < eventBusBuffer+1; i++ {
+		bus.Publish(&Event{Type: ETDsAdded})
+	}
+
+	cancel()
+	missed := eventBusBuffer + 1
+	for range events {
+		missed--
+	}
+	if missed != 1 {
+		t.Errorf("expected a full subscriber to miss 1 event, missed %d", missed)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/qri-io/qfs/cafs"
@@ -17,15 +18,21 @@ type EventLog struct {
 	basepath
 	file  File
 	store cafs.Filestore
+	// lk guards read-modify-write cycles of the event log file, shared by
+	// copies of an EventLog
+	lk *sync.Mutex
 }
 
 // NewEventLog allocates a new file-based EventLog instance
 func NewEventLog(base string, file File, store cafs.Filestore) EventLog {
-	return EventLog{basepath: basepath(base), file: file, store: store}
+	return EventLog{basepath: basepath(base), file: file, store: store, lk: &sync.Mutex{}}
 }
 
 // LogEvent adds a Event to the store
 func (ql EventLog) LogEvent(t repo.EventType, ref repo.DatasetRef) error {
+	ql.lk.Lock()
+	defer ql.lk.Unlock()
+
 	log, err := ql.logs()
 	if err != nil {
 		return err
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	golog "github.com/ipfs/go-log"
 	crypto "github.com/libp2p/go-libp2p-crypto"
@@ -30,6 +32,9 @@ type Repo struct {
 
 	repo.Refstore
 	EventLog
+	events *repo.EventBus
+
+	refCache *repo.LockingRefstore
 
 	profile *profile.Profile
 
@@ -61,8 +66,10 @@ func NewRepo(store cafs.Filestore, fsys qfs.Filesystem, pro *profile.Profile, ba
 		fsys:     fsys,
 		basepath: bp,
 
-		Refstore: Refstore{basepath: bp, store: store, file: FileRefs},
+		Refstore: Refstore{basepath: bp, store: store, file: FileRefs, lk: &sync.Mutex{}},
 		EventLog: NewEventLog(base, FileEventLogs, store),
+		events:   &repo.EventBus{},
+		refCache: repo.NewLockingRefstore(&repo.MemRefstore{}),
 
 		profiles: NewProfileStore(bp),
 	}
@@ -88,6 +95,21 @@ func NewRepo(store cafs.Filestore, fsys qfs.Filesystem, pro *profile.Profile, ba
 	return r, nil
 }
 
+// LogEvent implements the repo.EventLog interface, broadcasting the event to
+// subscribers
+func (r *Repo) LogEvent(t repo.EventType, ref repo.DatasetRef) error {
+	if err := r.EventLog.LogEvent(t, ref); err != nil {
+		return err
+	}
+	r.events.Publish(&repo.Event{Time: time.Now(), Type: t, Ref: ref})
+	return nil
+}
+
+// SubscribeEvents implements the repo.EventSubscriber interface
+func (r *Repo) SubscribeEvents(ctx context.Context) <-chan *repo.Event {
+	return r.events.SubscribeEvents(ctx)
+}
+
 // Path returns the path to the root of the repo directory
 func (r Repo) Path() string {
 	return string(r.basepath)
@@ -103,6 +125,12 @@ func (r Repo) Filesystem() qfs.Filesystem {
 	return r.fsys
 }
 
+// RefCache gives access to the ephemeral Refstore of references announced
+// by other peers
+func (r *Repo) RefCache() repo.Refstore {
+	return r.refCache
+}
+
 // SetFilesystem implements QFSSetter, currently used during lib contstruction
 func (r *Repo) SetFilesystem(fs qfs.Filesystem) {
 	r.fsys = fs
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"sort"
+	"sync"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsfs"
@@ -20,6 +21,10 @@ import (
 type Refstore struct {
 	basepath
 	file File
+	// lk guards read-modify-write cycles of the refs file, which would
+	// otherwise drop references written by concurrent saves. it's a pointer
+	// so copies of a Refstore share it
+	lk *sync.Mutex
 	// optional search index to add/remove from
 	index search.Index
 	// filestore for checking dataset integrity
@@ -28,6 +33,9 @@ type Refstore struct {
 
 // PutRef adds a reference to the store
 func (rs Refstore) PutRef(r repo.DatasetRef) (err error) {
+	rs.lk.Lock()
+	defer rs.lk.Unlock()
+
 	var (
 		ds   *dataset.Dataset
 		refs repo.Refs
@@ -104,6 +112,9 @@ func (rs Refstore) GetRef(get repo.DatasetRef) (repo.DatasetRef, error) {
 
 // DeleteRef removes a name from the store
 func (rs Refstore) DeleteRef(del repo.DatasetRef) error {
+	rs.lk.Lock()
+	defer rs.lk.Unlock()
+
 	refs, err := rs.refs()
 	if err != nil {
 		return err
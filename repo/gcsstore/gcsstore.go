@@ -0,0 +1,209 @@
+// Package gcsstore implements a cafs.Filestore that persists files as objects
+// in a Google Cloud Storage bucket, for running qri on managed infrastructure
+// without a local disk
+package gcsstore
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	golog "github.com/ipfs/go-log"
+	"github.com/qri-io/qfs/cafs"
+	"github.com/qri-io/qri/repo/kvstore"
+)
+
+var log = golog.Logger("gcsstore")
+
+// pathPrefix is the prefix for all paths in the store. the "gcs" prefix is
+// routed to the store by the qri filesystem
+const pathPrefix = "gcs"
+
+// bucket is the set of object operations a Filestore needs, abstracted so
+// the store can be tested without a GCS connection
+type bucket interface {
+	get(ctx context.Context, name string) ([]byte, error)
+	put(ctx context.Context, name string, data []byte) error
+	has(ctx context.Context, name string) (bool, error)
+	delete(ctx context.Context, name string) error
+	// writable reports if the store's credentials can create objects
+	writable(ctx context.Context) (bool, error)
+}
+
+// errReadOnly is returned when writing to a bucket the store's credentials
+// can't write to
+var errReadOnly = fmt.Errorf("gcs bucket is read-only")
+
+// Filestore is a cafs.Filestore backed by a GCS bucket
+type Filestore struct {
+	*kvstore.Filestore
+	readOnly bool
+	close    func() error
+}
+
+var (
+	// compile-time assertion that Filestore is a cafs.Filestore
+	_ cafs.Filestore = (*Filestore)(nil)
+	// compile-time assertion that Filestore is a cafs.Pinner
+	_ cafs.Pinner = (*Filestore)(nil)
+)
+
+// NewFilestore connects to a GCS bucket using Application Default
+// Credentials. objects are written beneath prefix, which may be empty.
+// credentials that can't create objects in the bucket open the store in
+// read-only mode
+func NewFilestore(ctx context.Context, bucketName, prefix string) (*Filestore, error) {
+	if bucketName == "" {
+		return nil, fmt.Errorf("gcs store requires a bucket")
+	}
+	cli, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to gcs: %s", err)
+	}
+	log.Debugf("using gcs bucket %q, prefix %q", bucketName, prefix)
+	fst := newFilestore(ctx, gcsBucket{cli.Bucket(bucketName)}, prefix)
+	fst.close = cli.Close
+	return fst, nil
+}
+
+// newFilestore creates a Filestore that writes objects to a bucket beneath
+// prefix
+func newFilestore(ctx context.Context, b bucket, prefix string) *Filestore {
+	readOnly := false
+	if ok, err := b.writable(ctx); err != nil {
+		// let writes fail on their own if permissions can't be checked
+		log.Debugf("checking gcs bucket permissions: %s", err)
+	} else {
+		readOnly = !ok
+	}
+	objects := objectStore{bucket: b, prefix: strings.Trim(prefix, "/"), readOnly: readOnly}
+	return &Filestore{Filestore: kvstore.NewFilestore(pathPrefix, objects), readOnly: readOnly}
+}
+
+// ReadOnly reports if the store rejects writes
+func (fst *Filestore) ReadOnly() bool {
+	return fst.readOnly
+}
+
+// NewAdder implements the cafs.Filestore interface
+func (fst *Filestore) NewAdder(pin, wrap bool) (cafs.Adder, error) {
+	if fst.readOnly {
+		return nil, errReadOnly
+	}
+	return fst.Filestore.NewAdder(pin, wrap)
+}
+
+// Close releases the connection to GCS
+func (fst *Filestore) Close() error {
+	if fst.close == nil {
+		return nil
+	}
+	return fst.close()
+}
+
+// Pin implements the cafs.Pinner interface. GCS objects persist until they're
+// deleted, pinning is a no-op
+func (fst *Filestore) Pin(ctx context.Context, key string, recursive bool) error {
+	return nil
+}
+
+// Unpin implements the cafs.Pinner interface. GCS objects persist until
+// they're deleted, unpinning is a no-op
+func (fst *Filestore) Unpin(ctx context.Context, key string, recursive bool) error {
+	return nil
+}
+
+// objectStore implements kvstore.Store, keeping each value in an object
+type objectStore struct {
+	bucket   bucket
+	prefix   string
+	readOnly bool
+}
+
+// objectName gives the name of the object that holds a key
+func (s objectStore) objectName(key string) string {
+	return path.Join(s.prefix, strings.TrimPrefix(key, "/"+pathPrefix+"/"))
+}
+
+func (s objectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.bucket.get(ctx, s.objectName(key))
+}
+
+// Put writes an object. GCS objects don't need pinning, pin is ignored
+func (s objectStore) Put(ctx context.Context, key string, value []byte, pin bool) error {
+	if s.readOnly {
+		return errReadOnly
+	}
+	// content-addressed objects never change, skip writing existing objects
+	if exists, err := s.bucket.has(ctx, s.objectName(key)); err != nil {
+		return err
+	} else if exists {
+		return nil
+	}
+	return s.bucket.put(ctx, s.objectName(key), value)
+}
+
+func (s objectStore) Has(ctx context.Context, key string) (bool, error) {
+	return s.bucket.has(ctx, s.objectName(key))
+}
+
+func (s objectStore) Delete(ctx context.Context, key string) error {
+	if s.readOnly {
+		return errReadOnly
+	}
+	return s.bucket.delete(ctx, s.objectName(key))
+}
+
+// gcsBucket implements bucket with a GCS bucket handle
+type gcsBucket struct {
+	h *storage.BucketHandle
+}
+
+func (b gcsBucket) get(ctx context.Context, name string) ([]byte, error) {
+	r, err := b.h.Object(name).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, cafs.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func (b gcsBucket) put(ctx context.Context, name string, data []byte) error {
+	w := b.h.Object(name).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (b gcsBucket) has(ctx context.Context, name string) (bool, error) {
+	_, err := b.h.Object(name).Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (b gcsBucket) delete(ctx context.Context, name string) error {
+	err := b.h.Object(name).Delete(ctx)
+	if err == storage.ErrObjectNotExist {
+		return cafs.ErrNotFound
+	}
+	return err
+}
+
+func (b gcsBucket) writable(ctx context.Context) (bool, error) {
+	perms, err := b.h.IAM().TestPermissions(ctx, []string{"storage.objects.create"})
+	if err != nil {
+		return false, err
+	}
+	return len(perms) == 1, nil
+}
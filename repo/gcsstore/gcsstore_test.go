@@ -0,0 +1,126 @@
+package gcsstore
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+// memBucket is an in-memory bucket for testing
+type memBucket struct {
+	lk       sync.Mutex
+	objects  map[string][]byte
+	puts     int
+	readOnly bool
+}
+
+func newMemBucket() *memBucket {
+	return &memBucket{objects: map[string][]byte{}}
+}
+
+func (b *memBucket) get(ctx context.Context, name string) ([]byte, error) {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	data, ok := b.objects[name]
+	if !ok {
+		return nil, cafs.ErrNotFound
+	}
+	return data, nil
+}
+
+func (b *memBucket) put(ctx context.Context, name string, data []byte) error {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	b.objects[name] = data
+	b.puts++
+	return nil
+}
+
+func (b *memBucket) has(ctx context.Context, name string) (bool, error) {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	_, ok := b.objects[name]
+	return ok, nil
+}
+
+func (b *memBucket) writable(ctx context.Context) (bool, error) {
+	return !b.readOnly, nil
+}
+
+func (b *memBucket) delete(ctx context.Context, name string) error {
+	b.lk.Lock()
+	defer b.lk.Unlock()
+	if _, ok := b.objects[name]; !ok {
+		return cafs.ErrNotFound
+	}
+	delete(b.objects, name)
+	return nil
+}
+
+func TestFilestorePrefix(t *testing.T) {
+	ctx := context.Background()
+	b := newMemBucket()
+	fst := newFilestore(ctx, b, "qri/store")
+
+	if fst.PathPrefix() != "gcs" {
+		t.Errorf("path prefix mismatch. got: %q", fst.PathPrefix())
+	}
+	key, err := fst.Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("hello")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fst.Put(ctx, qfs.NewMemfileBytes("b.txt", []byte("hello")), false); err != nil {
+		t.Fatal(err)
+	}
+	if b.puts != 1 {
+		t.Errorf("expected existing objects not to be rewritten. got %d writes", b.puts)
+	}
+	if _, ok := b.objects["qri/store/"+key[len("/gcs/"):]]; !ok {
+		t.Errorf("expected object to be written beneath prefix. got: %v", b.objects)
+	}
+
+	// pinning is a no-op
+	if err := fst.Pin(ctx, key, true); err != nil {
+		t.Errorf("expected pin to succeed. got: %s", err)
+	}
+	if err := fst.Unpin(ctx, key, true); err != nil {
+		t.Errorf("expected unpin to succeed. got: %s", err)
+	}
+
+	if err := fst.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.objects) != 0 {
+		t.Errorf("expected delete to remove the object beneath prefix. got: %v", b.objects)
+	}
+}
+
+func TestFilestoreReadOnly(t *testing.T) {
+	ctx := context.Background()
+	b := newMemBucket()
+	key, err := newFilestore(ctx, b, "").Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("hello")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.readOnly = true
+	fst := newFilestore(ctx, b, "")
+	if !fst.ReadOnly() {
+		t.Error("expected a bucket that can't be written to open read-only")
+	}
+	if has, err := fst.Has(ctx, key); err != nil || !has {
+		t.Errorf("expected read-only store to have key. got: %t %v", has, err)
+	}
+	if _, err := fst.Put(ctx, qfs.NewMemfileBytes("b.txt", []byte("world")), false); err != errReadOnly {
+		t.Errorf("expected put to return errReadOnly. got: %v", err)
+	}
+	if _, err := fst.NewAdder(false, false); err != errReadOnly {
+		t.Errorf("expected new adder to return errReadOnly. got: %v", err)
+	}
+	if err := fst.Delete(ctx, key); err != errReadOnly {
+		t.Errorf("expected delete to return errReadOnly. got: %v", err)
+	}
+}
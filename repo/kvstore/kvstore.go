@@ -0,0 +1,256 @@
+// Package kvstore implements a content-addressed cafs.Filestore on top of a
+// key-value store. Files are stored as single values keyed by the hash of
+// their contents, directories as values listing the keys of their entries.
+// Backends like badger & gcs only need to provide reads & writes of raw
+// values
+package kvstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/multiformats/go-multihash"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+)
+
+const (
+	// KindFile marks a stored value as file contents
+	KindFile byte = 'f'
+	// KindDir marks a stored value as a directory listing
+	KindDir byte = 'd'
+)
+
+// Store is a key-value backend for a Filestore. Get must return
+// cafs.ErrNotFound for keys that aren't stored
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put writes a value. stores that record pins should pin the key when pin
+	// is true, others can ignore it
+	Put(ctx context.Context, key string, value []byte, pin bool) error
+	Has(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Filestore is a cafs.Filestore backed by a key-value Store
+type Filestore struct {
+	prefix string
+	store  Store
+}
+
+// compile-time assertion that Filestore is a cafs.Filestore
+var _ cafs.Filestore = (*Filestore)(nil)
+
+// NewFilestore creates a Filestore that writes to store, prefixing keys with
+// pathPrefix
+func NewFilestore(pathPrefix string, store Store) *Filestore {
+	return &Filestore{prefix: pathPrefix, store: store}
+}
+
+// PathPrefix implements the cafs.Filestore interface
+func (fst *Filestore) PathPrefix() string {
+	return fst.prefix
+}
+
+// Put implements the cafs.Filestore interface
+func (fst *Filestore) Put(ctx context.Context, file qfs.File, pin bool) (key string, err error) {
+	var value []byte
+	if file.IsDirectory() {
+		buf := &bytes.Buffer{}
+		for {
+			f, err := file.NextFile()
+			if err != nil {
+				if err.Error() == "EOF" {
+					break
+				}
+				return "", fmt.Errorf("error getting next file: %s", err)
+			}
+			child, err := fst.Put(ctx, f, pin)
+			if err != nil {
+				return "", fmt.Errorf("error putting file: %s", err)
+			}
+			fmt.Fprintf(buf, "%s\t%s\n", f.FileName(), child)
+		}
+		value = append([]byte{KindDir}, buf.Bytes()...)
+	} else {
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			return "", fmt.Errorf("error reading from file: %s", err)
+		}
+		value = append([]byte{KindFile}, data...)
+	}
+	return fst.putValue(ctx, value, pin)
+}
+
+// putValue writes a value keyed by its hash
+func (fst *Filestore) putValue(ctx context.Context, value []byte, pin bool) (key string, err error) {
+	hash, err := multihash.Sum(value, multihash.SHA2_256, -1)
+	if err != nil {
+		return "", fmt.Errorf("error hashing file data: %s", err)
+	}
+	key = "/" + fst.prefix + "/" + hash.B58String()
+	return key, fst.store.Put(ctx, key, value, pin)
+}
+
+// Get implements the cafs.Filestore interface. paths may address a file
+// within a directory, eg: /cafs/QmFoo/dataset.json. if the root of the path
+// is a file, the file is returned
+func (fst *Filestore) Get(ctx context.Context, key string) (qfs.File, error) {
+	root, rest := SplitKey(key)
+	value, err := fst.store.Get(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("empty value for key %s", root)
+	}
+
+	if value[0] != KindDir {
+		return qfs.NewMemfileBytes(key, value[1:]), nil
+	}
+	if rest == "" {
+		return fst.dir(ctx, root, value[1:])
+	}
+
+	name := strings.SplitN(rest, "/", 2)
+	for _, entry := range dirEntries(value[1:]) {
+		if entry[0] == name[0] {
+			if len(name) == 2 {
+				return fst.Get(ctx, entry[1]+"/"+name[1])
+			}
+			return fst.Get(ctx, entry[1])
+		}
+	}
+	return nil, cafs.ErrNotFound
+}
+
+// dir assembles a directory from a stored listing
+func (fst *Filestore) dir(ctx context.Context, path string, listing []byte) (qfs.File, error) {
+	entries := dirEntries(listing)
+	files := make([]qfs.File, len(entries))
+	for i, entry := range entries {
+		f, err := fst.Get(ctx, entry[1])
+		if err != nil {
+			return nil, err
+		}
+		if f.IsDirectory() {
+			files[i] = f
+			continue
+		}
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+		files[i] = qfs.NewMemfileBytes(entry[0], data)
+	}
+	return qfs.NewMemdir(path, files...), nil
+}
+
+// Has implements the cafs.Filestore interface
+func (fst *Filestore) Has(ctx context.Context, key string) (exists bool, err error) {
+	root, _ := SplitKey(key)
+	return fst.store.Has(ctx, root)
+}
+
+// Delete implements the cafs.Filestore interface
+func (fst *Filestore) Delete(ctx context.Context, key string) error {
+	root, _ := SplitKey(key)
+	return fst.store.Delete(ctx, root)
+}
+
+// NewAdder implements the cafs.Filestore interface. When wrap is true, closing
+// the adder writes a directory listing every added file, sent as a final
+// added file with an empty name
+func (fst *Filestore) NewAdder(pin, wrap bool) (cafs.Adder, error) {
+	return &adder{store: fst, pin: pin, wrap: wrap, out: make(chan cafs.AddedFile, 9)}, nil
+}
+
+// adder implements the cafs.Adder interface for a Filestore
+type adder struct {
+	store   *Filestore
+	pin     bool
+	wrap    bool
+	out     chan cafs.AddedFile
+	listing bytes.Buffer
+}
+
+// AddFile implements the cafs.Adder interface
+func (a *adder) AddFile(ctx context.Context, f qfs.File) error {
+	path, err := a.store.Put(ctx, f, a.pin)
+	if err != nil {
+		return fmt.Errorf("error putting file in %s store: %s", a.store.prefix, err)
+	}
+	if a.wrap {
+		fmt.Fprintf(&a.listing, "%s\t%s\n", f.FileName(), path)
+	}
+	a.out <- cafs.AddedFile{
+		Path: path,
+		Name: f.FileName(),
+		Hash: path,
+	}
+	return nil
+}
+
+// Added implements the cafs.Adder interface
+func (a *adder) Added() chan cafs.AddedFile {
+	return a.out
+}
+
+// Close implements the cafs.Adder interface
+func (a *adder) Close() error {
+	defer close(a.out)
+	if !a.wrap {
+		return nil
+	}
+	path, err := a.store.putValue(context.Background(), append([]byte{KindDir}, a.listing.Bytes()...), a.pin)
+	if err != nil {
+		return fmt.Errorf("error putting directory in %s store: %s", a.store.prefix, err)
+	}
+	a.out <- cafs.AddedFile{Path: path, Hash: path}
+	return nil
+}
+
+// SplitKey separates the stored root of a key from a path within it,
+// /cafs/QmFoo/dataset.json becomes /cafs/QmFoo & dataset.json
+func SplitKey(key string) (root, rest string) {
+	parts := strings.SplitN(strings.TrimPrefix(key, "/"), "/", 3)
+	if len(parts) < 2 {
+		return key, ""
+	}
+	root = "/" + parts[0] + "/" + parts[1]
+	if len(parts) == 3 {
+		rest = parts[2]
+	}
+	return root, rest
+}
+
+// DirChildren lists the stored keys a directory value refers to, returning
+// nothing for file values
+func DirChildren(value []byte) []string {
+	if len(value) == 0 || value[0] != KindDir {
+		return nil
+	}
+	entries := dirEntries(value[1:])
+	children := make([]string, len(entries))
+	for i, entry := range entries {
+		children[i], _ = SplitKey(entry[1])
+	}
+	return children
+}
+
+// dirEntries parses a directory listing into name, path pairs
+func dirEntries(listing []byte) [][2]string {
+	var entries [][2]string
+	for _, line := range strings.Split(strings.TrimSpace(string(listing)), "\n") {
+		if line == "" {
+			continue
+		}
+		if parts := strings.SplitN(line, "\t", 2); len(parts) == 2 {
+			entries = append(entries, [2]string{parts[0], parts[1]})
+		}
+	}
+	return entries
+}
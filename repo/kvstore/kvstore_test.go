@@ -0,0 +1,212 @@
+package kvstore
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsfs"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qfs/cafs"
+	"github.com/qri-io/qri/config"
+)
+
+func TestFilestore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemStore()
+	fst := NewFilestore("kv", s)
+
+	if fst.PathPrefix() != "kv" {
+		t.Errorf("path prefix mismatch. got: %q", fst.PathPrefix())
+	}
+
+	key, err := fst.Put(ctx, qfs.NewMemfileBytes("a.txt", []byte("hello")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := fst.Put(ctx, qfs.NewMemfileBytes("b.txt", []byte("hello")), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != again {
+		t.Errorf("expected identical content to have the same key. got: %s, %s", key, again)
+	}
+	if has, err := fst.Has(ctx, key); err != nil || !has {
+		t.Errorf("expected store to have key. got: %t %v", has, err)
+	}
+	assertContents(t, fst, key, "hello")
+	// paths within a file resolve to the file
+	assertContents(t, fst, key+"/dataset.json", "hello")
+
+	if _, err := fst.Get(ctx, "/kv/QmNotAKey"); err != cafs.ErrNotFound {
+		t.Errorf("expected ErrNotFound. got: %v", err)
+	}
+
+	dirKey, err := fst.Put(ctx, qfs.NewMemdir("/a",
+		qfs.NewMemfileBytes("b.txt", []byte("bar")),
+		qfs.NewMemdir("/c",
+			qfs.NewMemfileBytes("d.txt", []byte("baz")),
+		),
+	), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContents(t, fst, dirKey+"/b.txt", "bar")
+	assertContents(t, fst, dirKey+"/c/d.txt", "baz")
+	d, err := fst.Get(ctx, dirKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsDirectory() {
+		t.Errorf("expected directory")
+	}
+	if _, err := fst.Get(ctx, dirKey+"/missing.txt"); err != cafs.ErrNotFound {
+		t.Errorf("expected ErrNotFound for a missing directory entry. got: %v", err)
+	}
+	if !s.pins[dirKey] {
+		t.Error("expected put with pin to pass pin to the store")
+	}
+
+	if children := DirChildren(s.values[dirKey]); len(children) != 2 {
+		t.Errorf("expected directory to list 2 children. got: %v", children)
+	}
+	if children := DirChildren(s.values[key]); children != nil {
+		t.Errorf("expected file to list no children. got: %v", children)
+	}
+
+	if err := fst.Delete(ctx, key+"/ignored"); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := fst.Has(ctx, key); has {
+		t.Error("expected deleted key to be gone")
+	}
+}
+
+func TestFilestoreDataset(t *testing.T) {
+	ctx := context.Background()
+	fst := NewFilestore("kv", NewMemStore())
+
+	ds := &dataset.Dataset{
+		Commit:    &dataset.Commit{Title: "initial commit"},
+		Meta:      &dataset.Meta{Title: "kv dataset"},
+		Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[1,2,3]`)))
+
+	pk, err := config.DefaultP2PForTesting().DecodePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := dsfs.CreateDataset(ctx, fst, ds, nil, pk, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dsfs.LoadDataset(ctx, fst, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Meta.Title != "kv dataset" {
+		t.Errorf("meta title mismatch. got: %q", got.Meta.Title)
+	}
+	assertContents(t, fst, got.BodyPath, `[1,2,3]`)
+}
+
+func TestFilestoreAdder(t *testing.T) {
+	ctx := context.Background()
+	fst := NewFilestore("kv", NewMemStore())
+
+	adder, err := fst.NewAdder(false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan cafs.AddedFile, 1)
+	go func() {
+		for added := range adder.Added() {
+			done <- added
+		}
+		close(done)
+	}()
+	if err := adder.AddFile(ctx, qfs.NewMemfileBytes("a.txt", []byte("added"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := adder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	added := <-done
+	if added.Name != "a.txt" {
+		t.Errorf("added name mismatch. got: %q", added.Name)
+	}
+	assertContents(t, fst, added.Path, "added")
+}
+
+func TestFilestoreAdderWrap(t *testing.T) {
+	ctx := context.Background()
+	fst := NewFilestore("kv", NewMemStore())
+
+	adder, err := fst.NewAdder(false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan []cafs.AddedFile, 1)
+	go func() {
+		var added []cafs.AddedFile
+		for a := range adder.Added() {
+			added = append(added, a)
+		}
+		done <- added
+	}()
+	if err := adder.AddFile(ctx, qfs.NewMemfileBytes("a.txt", []byte("a"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := adder.AddFile(ctx, qfs.NewMemfileBytes("b.txt", []byte("b"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := adder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	added := <-done
+	if len(added) != 3 {
+		t.Fatalf("expected 2 files & a wrapping directory to be added. got: %v", added)
+	}
+	root := added[2]
+	if root.Name != "" {
+		t.Errorf("expected wrapping directory to be added last, with no name. got: %q", root.Name)
+	}
+	assertContents(t, fst, root.Path+"/a.txt", "a")
+	assertContents(t, fst, root.Path+"/b.txt", "b")
+}
+
+func TestSplitKey(t *testing.T) {
+	cases := []struct {
+		key, root, rest string
+	}{
+		{"/kv/QmFoo", "/kv/QmFoo", ""},
+		{"/kv/QmFoo/dataset.json", "/kv/QmFoo", "dataset.json"},
+		{"/kv/QmFoo/a/b.json", "/kv/QmFoo", "a/b.json"},
+		{"QmFoo", "QmFoo", ""},
+	}
+	for _, c := range cases {
+		root, rest := SplitKey(c.key)
+		if !reflect.DeepEqual([2]string{root, rest}, [2]string{c.root, c.rest}) {
+			t.Errorf("%s: expected (%q, %q), got (%q, %q)", c.key, c.root, c.rest, root, rest)
+		}
+	}
+}
+
+func assertContents(t *testing.T, fst *Filestore, key, expect string) {
+	t.Helper()
+	f, err := fst.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("getting %s: %s", key, err)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != expect {
+		t.Errorf("contents of %s mismatch. expected: %q, got: %q", key, expect, string(data))
+	}
+}
@@ -0,0 +1,62 @@
+package kvstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/qri-io/qfs/cafs"
+)
+
+// MemStore is an in-memory Store, useful for tests
+type MemStore struct {
+	lk     sync.Mutex
+	values map[string][]byte
+	pins   map[string]bool
+}
+
+// compile-time assertion that MemStore is a Store
+var _ Store = (*MemStore)(nil)
+
+// NewMemStore creates an empty MemStore
+func NewMemStore() *MemStore {
+	return &MemStore{values: map[string][]byte{}, pins: map[string]bool{}}
+}
+
+// Get implements the Store interface
+func (s *MemStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	value, ok := s.values[key]
+	if !ok {
+		return nil, cafs.ErrNotFound
+	}
+	return value, nil
+}
+
+// Put implements the Store interface
+func (s *MemStore) Put(ctx context.Context, key string, value []byte, pin bool) error {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.values[key] = value
+	if pin {
+		s.pins[key] = true
+	}
+	return nil
+}
+
+// Has implements the Store interface
+func (s *MemStore) Has(ctx context.Context, key string) (bool, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	_, ok := s.values[key]
+	return ok, nil
+}
+
+// Delete implements the Store interface
+func (s *MemStore) Delete(ctx context.Context, key string) error {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	delete(s.values, key)
+	delete(s.pins, key)
+	return nil
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"sort"
+	"sync"
 )
 
 // MemRefstore is an in-memory implementation of the Namestore interface
@@ -76,3 +77,50 @@ func (r MemRefstore) References(offset, limit int) ([]DatasetRef, error) {
 func (r MemRefstore) RefCount() (int, error) {
 	return len(r), nil
 }
+
+// LockingRefstore guards a Refstore with a mutex, making it safe to use from
+// multiple goroutines
+type LockingRefstore struct {
+	lk    sync.Mutex
+	store Refstore
+}
+
+// NewLockingRefstore wraps a Refstore with a mutex
+func NewLockingRefstore(store Refstore) *LockingRefstore {
+	return &LockingRefstore{store: store}
+}
+
+// PutRef implements the Refstore interface
+func (r *LockingRefstore) PutRef(put DatasetRef) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.store.PutRef(put)
+}
+
+// GetRef implements the Refstore interface
+func (r *LockingRefstore) GetRef(get DatasetRef) (DatasetRef, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.store.GetRef(get)
+}
+
+// DeleteRef implements the Refstore interface
+func (r *LockingRefstore) DeleteRef(del DatasetRef) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.store.DeleteRef(del)
+}
+
+// References implements the Refstore interface
+func (r *LockingRefstore) References(offset, limit int) ([]DatasetRef, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.store.References(offset, limit)
+}
+
+// RefCount implements the Refstore interface
+func (r *LockingRefstore) RefCount() (int, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.store.RefCount()
+}
@@ -1,6 +1,9 @@
 package repo
 
 import (
+	"context"
+	"time"
+
 	crypto "github.com/libp2p/go-libp2p-crypto"
 	"github.com/qri-io/dataset/dsgraph"
 	"github.com/qri-io/qfs"
@@ -12,11 +15,12 @@ import (
 type MemRepo struct {
 	*MemRefstore
 	*MemEventLog
+	events *EventBus
 
 	store      cafs.Filestore
 	filesystem qfs.Filesystem
 	graph      map[string]*dsgraph.Node
-	refCache   *MemRefstore
+	refCache   *LockingRefstore
 
 	profile  *profile.Profile
 	profiles profile.Store
@@ -29,12 +33,28 @@ func NewMemRepo(p *profile.Profile, store cafs.Filestore, fsys qfs.Filesystem, p
 		filesystem:  fsys,
 		MemRefstore: &MemRefstore{},
 		MemEventLog: &MemEventLog{},
-		refCache:    &MemRefstore{},
+		events:      &EventBus{},
+		refCache:    NewLockingRefstore(&MemRefstore{}),
 		profile:     p,
 		profiles:    ps,
 	}, nil
 }
 
+// LogEvent implements the EventLog interface, broadcasting the event to
+// subscribers
+func (r *MemRepo) LogEvent(t EventType, ref DatasetRef) error {
+	if err := r.MemEventLog.LogEvent(t, ref); err != nil {
+		return err
+	}
+	r.events.Publish(&Event{Time: time.Now(), Type: t, Ref: ref})
+	return nil
+}
+
+// SubscribeEvents implements the EventSubscriber interface
+func (r *MemRepo) SubscribeEvents(ctx context.Context) <-chan *Event {
+	return r.events.SubscribeEvents(ctx)
+}
+
 // Store returns the underlying cafs.Filestore for this repo
 func (r *MemRepo) Store() cafs.Filestore {
 	return r.store
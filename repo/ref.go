@@ -64,6 +64,8 @@ type DatasetRef struct {
 	Published bool `json:"published"`
 	// If true, this reference doesn't exist locally
 	Foreign bool `json:"foreign,omitempty"`
+	// If true, this dataset's body hasn't been fetched to the local store
+	BodyMissing bool `json:"bodyMissing,omitempty"`
 }
 
 // String implements the Stringer interface for DatasetRef
@@ -131,12 +133,14 @@ func MustParseDatasetRef(refstr string) DatasetRef {
 // ParseDatasetRef decodes a dataset reference from a string value
 // It’s possible to refer to a dataset in a number of ways.
 // The full definition of a dataset reference is as follows:
-//     dataset_reference = peer_name/dataset_name@peer_id/network/hash
+//
+//	dataset_reference = peer_name/dataset_name@peer_id/network/hash
 //
 // we swap in defaults as follows, all of which are represented as
 // empty strings:
-//     network - defaults to /ipfs/
-//     hash - tip of version history (latest known commit)
+//
+//	network - defaults to /ipfs/
+//	hash - tip of version history (latest known commit)
 //
 // these defaults are currently enforced by convention.
 // TODO - make Dataset Ref parsing the responisiblity of the Repo
@@ -145,12 +149,13 @@ func MustParseDatasetRef(refstr string) DatasetRef {
 // dataset names & hashes are disambiguated by checking if the input
 // parses to a valid multihash after base58 decoding.
 // through defaults & base58 checking the following should all parse:
-//     peer_name/dataset_name
-//     /network/hash
-//     peername
-//     peer_id
-//     @peer_id
-//     @peer_id/network/hash
+//
+//	peer_name/dataset_name
+//	/network/hash
+//	peername
+//	peer_id
+//	@peer_id
+//	@peer_id/network/hash
 //
 // see tests for more exmples
 //
@@ -336,6 +341,7 @@ func CanonicalizeDatasetRef(r Repo, ref *DatasetRef) error {
 		ref.Peername = got.Peername
 	}
 	ref.Published = got.Published
+	ref.BodyMissing = got.BodyMissing
 	if ref.FSIPath == "" {
 		ref.FSIPath = got.FSIPath
 	}
@@ -475,6 +481,7 @@ func (r DatasetRef) MarshalFlatbuffer(builder *flatbuffers.Builder) flatbuffers.
 	repofb.DatasetRefAddPath(builder, path)
 	repofb.DatasetRefAddFsiPath(builder, fsiPath)
 	repofb.DatasetRefAddPublished(builder, r.Published)
+	repofb.DatasetRefAddBodyMissing(builder, r.BodyMissing)
 	return repofb.DatasetRefEnd(builder)
 }
 
@@ -482,11 +489,12 @@ func (r DatasetRef) MarshalFlatbuffer(builder *flatbuffers.Builder) flatbuffers.
 func (r *DatasetRef) UnmarshalFlatbuffer(rfb *repofb.DatasetRef) (err error) {
 
 	*r = DatasetRef{
-		Peername:  string(rfb.Peername()),
-		Name:      string(rfb.Name()),
-		Path:      string(rfb.Path()),
-		FSIPath:   string(rfb.FsiPath()),
-		Published: rfb.Published(),
+		Peername:    string(rfb.Peername()),
+		Name:        string(rfb.Name()),
+		Path:        string(rfb.Path()),
+		FSIPath:     string(rfb.FsiPath()),
+		Published:   rfb.Published(),
+		BodyMissing: rfb.BodyMissing(),
 	}
 
 	if pidstr := string(rfb.ProfileID()); pidstr != "" {
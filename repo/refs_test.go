@@ -14,9 +14,10 @@ func TestRefsFlatbuffer(t *testing.T) {
 			FSIPath: "/fsi/path/one",
 		},
 		DatasetRef{
-			Name:    "ref_two",
-			Path:    "/path/two",
-			FSIPath: "/fsi/path/two",
+			Name:        "ref_two",
+			Path:        "/path/two",
+			FSIPath:     "/fsi/path/two",
+			BodyMissing: true,
 		},
 	}
 
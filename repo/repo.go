@@ -84,6 +84,12 @@ type QFSSetter interface {
 	SetFilesystem(qfs.Filesystem)
 }
 
+// RefCacher is implemented by repos that keep an ephemeral store of
+// references to datasets on other peers
+type RefCacher interface {
+	RefCache() Refstore
+}
+
 // SearchParams encapsulates parameters provided to Searchable.Search
 type SearchParams struct {
 	Q             string
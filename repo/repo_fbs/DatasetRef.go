@@ -78,8 +78,20 @@ func (rcv *DatasetRef) MutatePublished(n bool) bool {
 	return rcv._tab.MutateBoolSlot(14, n)
 }
 
+func (rcv *DatasetRef) BodyMissing() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(16))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *DatasetRef) MutateBodyMissing(n bool) bool {
+	return rcv._tab.MutateBoolSlot(16, n)
+}
+
 func DatasetRefStart(builder *flatbuffers.Builder) {
-	builder.StartObject(6)
+	builder.StartObject(7)
 }
 func DatasetRefAddPeername(builder *flatbuffers.Builder, peername flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(peername), 0)
@@ -99,6 +111,9 @@ func DatasetRefAddFsiPath(builder *flatbuffers.Builder, fsiPath flatbuffers.UOff
 func DatasetRefAddPublished(builder *flatbuffers.Builder, published bool) {
 	builder.PrependBoolSlot(5, published, false)
 }
+func DatasetRefAddBodyMissing(builder *flatbuffers.Builder, bodyMissing bool) {
+	builder.PrependBoolSlot(6, bodyMissing, false)
+}
 func DatasetRefEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
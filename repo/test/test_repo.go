@@ -212,7 +212,7 @@ func createDataset(r repo.Repo, tc dstest.TestCase) (ref repo.DatasetRef, err er
 		ds.Commit.Author = &dataset.User{ID: pro.ID.String()}
 	}
 
-	ref, err = base.CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), ds, nil, false, true, false, true)
+	ref, err = base.CreateDataset(ctx, r, ioes.NewDiscardIOStreams(), ds, nil, false, true, false, true, 0)
 	return
 }
 
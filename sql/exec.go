@@ -0,0 +1,346 @@
+package sql
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RowReader reads body rows one at a time. values are in the order of the
+// body's columns. ReadRow returns io.EOF when no rows remain
+type RowReader interface {
+	ReadRow() ([]interface{}, error)
+}
+
+// Result is the output of a query
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Exec runs a query against rows of a body with the given column names.
+// rows are read once, in order
+func (s *Select) Exec(columns []string, rows RowReader) (*Result, error) {
+	if err := s.resolve(columns); err != nil {
+		return nil, err
+	}
+	res := &Result{Columns: s.fieldNames(columns)}
+	if s.Limit == 0 {
+		return res, nil
+	}
+
+	if s.aggregated() {
+		aggs := s.aggregates()
+		accs := make(map[*aggregate]*accumulator, len(aggs))
+		for _, agg := range aggs {
+			accs[agg] = &accumulator{}
+		}
+		err := s.scan(rows, func(row []interface{}) (bool, error) {
+			c := &evalContext{row: row}
+			for _, agg := range aggs {
+				if err := accs[agg].add(agg, c); err != nil {
+					return false, err
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		c := &evalContext{aggs: map[*aggregate]interface{}{}}
+		for agg, acc := range accs {
+			c.aggs[agg] = acc.result(agg)
+		}
+		row, err := s.outputRow(c)
+		if err != nil {
+			return nil, err
+		}
+		// aggregating always yields exactly one row
+		if s.Offset == 0 {
+			res.Rows = append(res.Rows, row)
+		}
+		return res, nil
+	}
+
+	skipped := 0
+	err := s.scan(rows, func(row []interface{}) (bool, error) {
+		if skipped < s.Offset {
+			skipped++
+			return false, nil
+		}
+		out, err := s.outputRow(&evalContext{row: row})
+		if err != nil {
+			return false, err
+		}
+		res.Rows = append(res.Rows, out)
+		// stop reading as soon as the last requested row is in
+		return s.Limit > 0 && len(res.Rows) >= s.Limit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// scan reads rows that pass the WHERE clause into fn until rows are
+// exhausted or fn returns true
+func (s *Select) scan(rows RowReader, fn func(row []interface{}) (bool, error)) error {
+	for {
+		row, err := rows.ReadRow()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if s.Where != nil {
+			v, err := s.Where.eval(&evalContext{row: row})
+			if err != nil {
+				return err
+			}
+			b, err := toBool(v)
+			if err != nil {
+				return fmt.Errorf("WHERE: %s", err)
+			}
+			if b == nil || !*b {
+				continue
+			}
+		}
+
+		done, err := fn(row)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// outputRow evaluates selected fields
+func (s *Select) outputRow(c *evalContext) (vals []interface{}, err error) {
+	if s.Star {
+		vals = make([]interface{}, len(c.row))
+		for i, v := range c.row {
+			vals[i] = number(v)
+		}
+		return vals, nil
+	}
+	vals = make([]interface{}, len(s.Fields))
+	for i, f := range s.Fields {
+		if vals[i], err = f.Expr.eval(c); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// resolve checks the query against body columns, setting the index of each
+// column reference
+func (s *Select) resolve(columns []string) error {
+	var err error
+	resolveExpr := func(e Expr) {
+		walk(e, func(x Expr) {
+			if c, ok := x.(*column); ok && err == nil {
+				c.idx, err = columnIndex(columns, c.name)
+			}
+		})
+	}
+	for _, f := range s.Fields {
+		resolveExpr(f.Expr)
+	}
+	resolveExpr(s.Where)
+	if err != nil || !s.aggregated() {
+		return err
+	}
+
+	// aggregating yields a single row, columns outside aggregate functions
+	// have no single value
+	if s.Star {
+		return fmt.Errorf("SELECT * can't be used with aggregate functions")
+	}
+	for _, f := range s.Fields {
+		bare(f.Expr, func(c *column) {
+			if err == nil {
+				err = fmt.Errorf("column %q must be used in an aggregate function", c.name)
+			}
+		})
+	}
+	return err
+}
+
+// bare calls fn for each column reference in e that isn't an aggregate
+// function argument
+func bare(e Expr, fn func(c *column)) {
+	switch x := e.(type) {
+	case *column:
+		fn(x)
+	case *unaryExpr:
+		bare(x.x, fn)
+	case *binaryExpr:
+		bare(x.l, fn)
+		bare(x.r, fn)
+	case *isNullExpr:
+		bare(x.x, fn)
+	case *likeExpr:
+		bare(x.x, fn)
+	}
+}
+
+// aggregates lists aggregate function calls in selected fields
+func (s *Select) aggregates() (aggs []*aggregate) {
+	for _, f := range s.Fields {
+		walk(f.Expr, func(x Expr) {
+			if a, ok := x.(*aggregate); ok {
+				aggs = append(aggs, a)
+			}
+		})
+	}
+	return aggs
+}
+
+// columnIndex finds a column by name, preferring an exact match over a case
+// insensitive one
+func columnIndex(columns []string, name string) (int, error) {
+	for i, c := range columns {
+		if c == name {
+			return i, nil
+		}
+	}
+	for i, c := range columns {
+		if strings.EqualFold(c, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown column %q", name)
+}
+
+// accumulator tracks the running state of an aggregate function
+type accumulator struct {
+	count int
+	sum   float64
+	val   interface{}
+}
+
+func (a *accumulator) add(agg *aggregate, c *evalContext) error {
+	if agg.arg == nil {
+		a.count++
+		return nil
+	}
+	v, err := agg.arg.eval(c)
+	if err != nil || v == nil {
+		// aggregates skip nulls
+		return err
+	}
+
+	switch agg.fn {
+	case "sum", "avg":
+		f, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s requires numbers, got %v", strings.ToUpper(agg.fn), v)
+		}
+		a.sum += f
+	case "min", "max":
+		if a.val == nil {
+			a.val = v
+			break
+		}
+		cmp, err := compare(v, a.val)
+		if err != nil {
+			return err
+		}
+		if (agg.fn == "min" && cmp < 0) || (agg.fn == "max" && cmp > 0) {
+			a.val = v
+		}
+	}
+	a.count++
+	return nil
+}
+
+func (a *accumulator) result(agg *aggregate) interface{} {
+	switch agg.fn {
+	case "count":
+		return a.count
+	case "sum":
+		if a.count == 0 {
+			return nil
+		}
+		return a.sum
+	case "avg":
+		if a.count == 0 {
+			return nil
+		}
+		return a.sum / float64(a.count)
+	}
+	return a.val
+}
+
+// WriteJSON writes result rows as an array of objects, with keys in column
+// order
+func (r *Result) WriteJSON(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('[')
+	for i, row := range r.Rows {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, v := range row {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(r.Columns[j])
+			if err != nil {
+				return err
+			}
+			val, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(val)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteCSV writes result rows as CSV with a header row of column names
+func (r *Result) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.Columns); err != nil {
+		return err
+	}
+	rec := make([]string, len(r.Columns))
+	for _, row := range r.Rows {
+		for i, v := range row {
+			rec[i] = formatValue(v)
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatValue renders a value as text, nulls are empty strings
+func formatValue(v interface{}) string {
+	switch x := number(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	return fmt.Sprintf("%v", v)
+}
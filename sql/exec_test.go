@@ -0,0 +1,130 @@
+package sql
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// sliceReader is a RowReader over in-memory rows
+type sliceReader struct {
+	rows [][]interface{}
+	read int
+}
+
+func (r *sliceReader) ReadRow() ([]interface{}, error) {
+	if r.read >= len(r.rows) {
+		return nil, io.EOF
+	}
+	r.read++
+	return r.rows[r.read-1], nil
+}
+
+var citiesColumns = []string{"city", "pop", "avg_age", "in_usa"}
+
+func cities() *sliceReader {
+	return &sliceReader{rows: [][]interface{}{
+		{"toronto", int64(40000000), 55.5, false},
+		{"new york", int64(8500000), 44.4, true},
+		{"chicago", int64(300000), 44.4, true},
+		{"chatham", int64(35000), 65.25, true},
+		{"raleigh", int64(250000), 50.65, true},
+		{"nowhere", nil, nil, nil},
+	}}
+}
+
+func TestExec(t *testing.T) {
+	cases := []struct {
+		query string
+		json  string
+	}{
+		{"SELECT city FROM me/cities WHERE in_usa AND pop > 250000",
+			`[{"city":"new york"},{"city":"chicago"}]`},
+		{"SELECT city, pop FROM me/cities LIMIT 2",
+			`[{"city":"toronto","pop":40000000},{"city":"new york","pop":8500000}]`},
+		{"SELECT city FROM me/cities WHERE in_usa LIMIT 2 OFFSET 1",
+			`[{"city":"chicago"},{"city":"chatham"}]`},
+		{"SELECT city FROM me/cities LIMIT 0",
+			`[]`},
+		{"SELECT city FROM me/cities WHERE city LIKE 'ch%'",
+			`[{"city":"chicago"},{"city":"chatham"}]`},
+		{"SELECT city FROM me/cities WHERE pop IS NULL",
+			`[{"city":"nowhere"}]`},
+		{"SELECT count(*), count(pop) AS counted, min(avg_age), max(city) FROM me/cities",
+			`[{"count(*)":6,"counted":5,"min(avg_age)":44.4,"max(city)":"toronto"}]`},
+		{"SELECT count(*) AS n, sum(pop) FROM me/cities WHERE in_usa",
+			`[{"n":4,"sum(pop)":9085000}]`},
+		{"SELECT avg(pop) FROM me/cities WHERE pop > 1000000000",
+			`[{"avg(pop)":null}]`},
+		{"SELECT city, pop / 1000 AS kpop FROM me/cities WHERE CITY = 'chatham'",
+			`[{"city":"chatham","kpop":35}]`},
+		{"SELECT * FROM me/cities LIMIT 1",
+			`[{"city":"toronto","pop":40000000,"avg_age":55.5,"in_usa":false}]`},
+	}
+
+	for i, c := range cases {
+		sel, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("case %d parse error: %s", i, err)
+			continue
+		}
+		res, err := sel.Exec(citiesColumns, cities())
+		if err != nil {
+			t.Errorf("case %d exec error: %s", i, err)
+			continue
+		}
+		buf := &bytes.Buffer{}
+		if err := res.WriteJSON(buf); err != nil {
+			t.Errorf("case %d writing json: %s", i, err)
+			continue
+		}
+		if buf.String() != c.json {
+			t.Errorf("case %d result mismatch.\nexpected: %s\ngot:      %s", i, c.json, buf.String())
+		}
+	}
+}
+
+func TestExecErrors(t *testing.T) {
+	cases := []struct {
+		query, err string
+	}{
+		{"SELECT town FROM me/cities", `unknown column "town"`},
+		{"SELECT city, count(*) FROM me/cities", `column "city" must be used in an aggregate function`},
+		{"SELECT city FROM me/cities WHERE pop", `WHERE: expected a boolean, got 4e+07`},
+		{"SELECT sum(city) FROM me/cities", `SUM requires numbers, got toronto`},
+	}
+
+	for i, c := range cases {
+		sel, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("case %d parse error: %s", i, err)
+			continue
+		}
+		_, err = sel.Exec(citiesColumns, cities())
+		if err == nil {
+			t.Errorf("case %d expected error, got nil", i)
+			continue
+		}
+		if err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: %q, got: %q", i, c.err, err.Error())
+		}
+	}
+}
+
+func TestResultWriteCSV(t *testing.T) {
+	res := &Result{
+		Columns: []string{"city", "pop", "avg_age"},
+		Rows: [][]interface{}{
+			{"new york", float64(8500000), 44.4},
+			{"nowhere", nil, nil},
+		},
+	}
+	buf := &bytes.Buffer{}
+	if err := res.WriteCSV(buf); err != nil {
+		t.Fatal(err)
+	}
+	expect := "city,pop,avg_age\nnew york,8500000,44.4\nnowhere,,\n"
+	if buf.String() != expect {
+		t.Errorf("result mismatch.\nexpected: %q\ngot:      %q", expect, buf.String())
+	}
+}
@@ -0,0 +1,308 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Expr is a node of a parsed expression
+type Expr interface {
+	eval(c *evalContext) (interface{}, error)
+}
+
+// evalContext holds the values an expression is evaluated against
+type evalContext struct {
+	// row is the current body row
+	row []interface{}
+	// aggs holds results of aggregate functions for the current group
+	aggs map[*aggregate]interface{}
+}
+
+// literal is a constant value: a float64, string, bool or nil
+type literal struct {
+	val interface{}
+}
+
+func (e *literal) eval(c *evalContext) (interface{}, error) {
+	return e.val, nil
+}
+
+// column refers to a value of the current row by column name. idx is set
+// when a query is executed
+type column struct {
+	name string
+	idx  int
+}
+
+func (e *column) eval(c *evalContext) (interface{}, error) {
+	if e.idx < len(c.row) {
+		return number(c.row[e.idx]), nil
+	}
+	return nil, nil
+}
+
+type unaryExpr struct {
+	op string
+	x  Expr
+}
+
+func (e *unaryExpr) eval(c *evalContext) (interface{}, error) {
+	v, err := e.x.eval(c)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch e.op {
+	case "not":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("NOT requires a boolean, got %v", v)
+		}
+		return !b, nil
+	case "-":
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("can't negate %v", v)
+		}
+		return -f, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", e.op)
+}
+
+type binaryExpr struct {
+	op   string
+	l, r Expr
+}
+
+func (e *binaryExpr) eval(c *evalContext) (interface{}, error) {
+	l, err := e.l.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	if e.op == "and" || e.op == "or" {
+		return e.logical(c, l)
+	}
+
+	r, err := e.r.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+
+	switch e.op {
+	case "+", "-", "*", "/":
+		lf, lok := l.(float64)
+		rf, rok := r.(float64)
+		if !lok || !rok {
+			return nil, fmt.Errorf("operator %s requires numbers, got %v and %v", e.op, l, r)
+		}
+		switch e.op {
+		case "+":
+			return lf + rf, nil
+		case "-":
+			return lf - rf, nil
+		case "*":
+			return lf * rf, nil
+		default:
+			if rf == 0 {
+				return nil, nil
+			}
+			return lf / rf, nil
+		}
+	}
+
+	cmp, err := compare(l, r)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", e.op)
+}
+
+// logical evaluates AND & OR with SQL's three-valued logic, l is the already
+// evaluated left hand side
+func (e *binaryExpr) logical(c *evalContext, l interface{}) (interface{}, error) {
+	lb, err := toBool(l)
+	if err != nil {
+		return nil, err
+	}
+	// short circuit
+	if lb != nil && *lb == (e.op == "or") {
+		return *lb, nil
+	}
+
+	r, err := e.r.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	rb, err := toBool(r)
+	if err != nil {
+		return nil, err
+	}
+	if rb != nil && *rb == (e.op == "or") {
+		return *rb, nil
+	}
+	if lb == nil || rb == nil {
+		return nil, nil
+	}
+	return *rb, nil
+}
+
+func toBool(v interface{}) (*bool, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("expected a boolean, got %v", v)
+	}
+	return &b, nil
+}
+
+type isNullExpr struct {
+	x   Expr
+	not bool
+}
+
+func (e *isNullExpr) eval(c *evalContext) (interface{}, error) {
+	v, err := e.x.eval(c)
+	if err != nil {
+		return nil, err
+	}
+	return (v == nil) != e.not, nil
+}
+
+type likeExpr struct {
+	x   Expr
+	re  *regexp.Regexp
+	not bool
+}
+
+func (e *likeExpr) eval(c *evalContext) (interface{}, error) {
+	v, err := e.x.eval(c)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	s, ok := v.(string)
+	if !ok {
+		s = formatValue(v)
+	}
+	return e.re.MatchString(s) != e.not, nil
+}
+
+// aggregateFuncs are the supported aggregate functions
+var aggregateFuncs = map[string]bool{
+	"count": true,
+	"sum":   true,
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+}
+
+// aggregate is an aggregate function call, arg is nil for COUNT(*)
+type aggregate struct {
+	fn  string
+	arg Expr
+}
+
+func (e *aggregate) eval(c *evalContext) (interface{}, error) {
+	return c.aggs[e], nil
+}
+
+// walk calls fn for e and each expression nested in e
+func walk(e Expr, fn func(Expr)) {
+	if e == nil {
+		return
+	}
+	fn(e)
+	switch x := e.(type) {
+	case *unaryExpr:
+		walk(x.x, fn)
+	case *binaryExpr:
+		walk(x.l, fn)
+		walk(x.r, fn)
+	case *isNullExpr:
+		walk(x.x, fn)
+	case *likeExpr:
+		walk(x.x, fn)
+	case *aggregate:
+		walk(x.arg, fn)
+	}
+}
+
+// hasAggregate checks if an expression contains an aggregate function call
+func hasAggregate(e Expr) (found bool) {
+	walk(e, func(x Expr) {
+		if _, ok := x.(*aggregate); ok {
+			found = true
+		}
+	})
+	return found
+}
+
+// number converts the numeric types body readers produce to float64, leaving
+// other values untouched
+func number(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	}
+	return v
+}
+
+// compare orders two non-nil values of the same type, returning a negative
+// number if a sorts before b, zero if they're equal and a positive number
+// otherwise
+func compare(a, b interface{}) (int, error) {
+	a, b = number(a), number(b)
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1, nil
+			case av > bv:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), nil
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			switch {
+			case av == bv:
+				return 0, nil
+			case !av:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, fmt.Errorf("can't compare %v with %v", a, b)
+}
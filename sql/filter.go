@@ -0,0 +1,70 @@
+package sql
+
+import "fmt"
+
+// Filter selects rows with a boolean expression written like a WHERE clause,
+// eg: pop > 1000000 AND city != 'toronto'
+type Filter struct {
+	where   Expr
+	columns []string
+}
+
+// ParseFilter parses a filter expression, resolving column references against
+// columns. Rows passed to Match hold values in the order of columns. When
+// columns is nil any column name can be referenced, and rows hold values in
+// the order of the filter's Columns
+func ParseFilter(expr string, columns []string) (*Filter, error) {
+	p := &parser{lx: &lexer{input: expr}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	where, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.typ != tEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", p.tok, p.tok.pos)
+	}
+	if hasAggregate(where) {
+		return nil, fmt.Errorf("aggregate functions aren't allowed in filters")
+	}
+
+	f := &Filter{where: where, columns: columns}
+	walk(where, func(x Expr) {
+		c, ok := x.(*column)
+		if !ok || err != nil {
+			return
+		}
+		if columns != nil {
+			c.idx, err = columnIndex(columns, c.name)
+			return
+		}
+		if c.idx, err = columnIndex(f.columns, c.name); err != nil {
+			c.idx, err = len(f.columns), nil
+			f.columns = append(f.columns, c.name)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Columns lists the columns rows are read as
+func (f *Filter) Columns() []string {
+	return f.columns
+}
+
+// Match reports whether a row passes the filter. Like a WHERE clause, rows
+// the expression evaluates to null for don't pass
+func (f *Filter) Match(row []interface{}) (bool, error) {
+	v, err := f.where.eval(&evalContext{row: row})
+	if err != nil {
+		return false, err
+	}
+	b, err := toBool(v)
+	if err != nil || b == nil {
+		return false, err
+	}
+	return *b, nil
+}
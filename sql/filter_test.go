@@ -0,0 +1,73 @@
+package sql
+
+import (
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		expr   string
+		expect []string
+	}{
+		{"pop > 1000000", []string{"toronto", "new york"}},
+		{"in_usa AND city LIKE 'ch%'", []string{"chicago", "chatham"}},
+		{"pop IS NULL", []string{"nowhere"}},
+		{"city != 'toronto' AND avg_age = 44.4", []string{"new york", "chicago"}},
+	}
+	for _, c := range cases {
+		f, err := ParseFilter(c.expr, citiesColumns)
+		if err != nil {
+			t.Errorf("%q unexpected error: %s", c.expr, err)
+			continue
+		}
+		var got []string
+		for _, row := range cities().rows {
+			ok, err := f.Match(row)
+			if err != nil {
+				t.Errorf("%q unexpected error: %s", c.expr, err)
+				break
+			}
+			if ok {
+				got = append(got, row[0].(string))
+			}
+		}
+		if len(got) != len(c.expect) {
+			t.Errorf("%q mismatch. expected: %v, got: %v", c.expr, c.expect, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.expect[i] {
+				t.Errorf("%q mismatch. expected: %v, got: %v", c.expr, c.expect, got)
+				break
+			}
+		}
+	}
+}
+
+func TestFilterColumns(t *testing.T) {
+	f, err := ParseFilter("a = 1 OR b = 'x' OR a = 2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols := f.Columns(); len(cols) != 2 || cols[0] != "a" || cols[1] != "b" {
+		t.Errorf("expected referenced columns in order. got: %v", cols)
+	}
+	if ok, err := f.Match([]interface{}{nil, "x"}); err != nil || !ok {
+		t.Errorf("expected match on the second column. got: %t, %v", ok, err)
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	cases := []struct {
+		expr, err string
+	}{
+		{"state = 'ny'", `unknown column "state"`},
+		{"count(*) > 1", "aggregate functions aren't allowed in filters"},
+		{"pop > 1 pop", `unexpected "pop" at position 8`},
+	}
+	for _, c := range cases {
+		if _, err := ParseFilter(c.expr, citiesColumns); err == nil || err.Error() != c.err {
+			t.Errorf("%q error mismatch. expected: %q, got: %v", c.expr, c.err, err)
+		}
+	}
+}
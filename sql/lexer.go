@@ -0,0 +1,143 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenType enumerates kinds of lexical tokens
+type tokenType int
+
+const (
+	tEOF tokenType = iota
+	tIdent
+	tNumber
+	tString
+	tSymbol
+)
+
+// token is a lexical token of a query
+type token struct {
+	typ tokenType
+	val string
+	pos int
+	// quoted is true for identifiers wrapped in double quotes or backticks,
+	// which are never keywords
+	quoted bool
+}
+
+// keyword checks if a token is an unquoted identifier matching kw, ignoring
+// case
+func (t token) keyword(kw string) bool {
+	return t.typ == tIdent && !t.quoted && strings.EqualFold(t.val, kw)
+}
+
+func (t token) String() string {
+	if t.typ == tEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.val)
+}
+
+// lexer scans a query into tokens on demand
+type lexer struct {
+	input string
+	pos   int
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+}
+
+// next scans the next token
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{typ: tEOF, pos: start}, nil
+	}
+
+	c := l.input[l.pos]
+	switch {
+	case c == '\'':
+		s, err := l.quoted('\'')
+		return token{typ: tString, val: s, pos: start}, err
+	case c == '"' || c == '`':
+		s, err := l.quoted(c)
+		return token{typ: tIdent, val: s, pos: start, quoted: true}, err
+	case isIdentStart(c):
+		for l.pos < len(l.input) && isIdentChar(l.input[l.pos]) {
+			l.pos++
+		}
+		return token{typ: tIdent, val: l.input[start:l.pos], pos: start}, nil
+	case isDigit(c) || (c == '.' && l.pos+1 < len(l.input) && isDigit(l.input[l.pos+1])):
+		for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+			l.pos++
+		}
+		return token{typ: tNumber, val: l.input[start:l.pos], pos: start}, nil
+	}
+
+	for _, sym := range []string{"<=", ">=", "!=", "<>"} {
+		if strings.HasPrefix(l.input[l.pos:], sym) {
+			l.pos += len(sym)
+			return token{typ: tSymbol, val: sym, pos: start}, nil
+		}
+	}
+	if strings.ContainsRune(",()*+-/=<>;", rune(c)) {
+		l.pos++
+		return token{typ: tSymbol, val: string(c), pos: start}, nil
+	}
+	return token{}, fmt.Errorf("unexpected character %q at position %d", c, start)
+}
+
+// raw scans a whitespace-delimited word, or a quoted string. raw is used for
+// dataset references, which contain characters that aren't valid in
+// identifiers
+func (l *lexer) raw() (string, error) {
+	l.skipSpace()
+	if l.pos < len(l.input) && (l.input[l.pos] == '"' || l.input[l.pos] == '`') {
+		return l.quoted(l.input[l.pos])
+	}
+	start := l.pos
+	for l.pos < len(l.input) && !unicode.IsSpace(rune(l.input[l.pos])) && l.input[l.pos] != ';' {
+		l.pos++
+	}
+	return l.input[start:l.pos], nil
+}
+
+// quoted scans a string wrapped in quote characters. a doubled quote
+// character escapes the quote
+func (l *lexer) quoted(q byte) (string, error) {
+	start := l.pos
+	l.pos++
+	var sb strings.Builder
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		l.pos++
+		if c == q {
+			if l.pos < len(l.input) && l.input[l.pos] == q {
+				sb.WriteByte(q)
+				l.pos++
+				continue
+			}
+			return sb.String(), nil
+		}
+		sb.WriteByte(c)
+	}
+	return "", fmt.Errorf("unterminated quote starting at position %d", start)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,434 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parser builds a Select from lexical tokens, tok is the current token
+type parser struct {
+	lx  *lexer
+	tok token
+}
+
+func (p *parser) advance() (err error) {
+	p.tok, err = p.lx.next()
+	return err
+}
+
+// peek returns the token after the current token without consuming it
+func (p *parser) peek() (token, error) {
+	lx := *p.lx
+	return lx.next()
+}
+
+func (p *parser) symbol(s string) bool {
+	return p.tok.typ == tSymbol && p.tok.val == s
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.tok.keyword(kw) {
+		return fmt.Errorf("expected %s, got %s", strings.ToUpper(kw), p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) expectSymbol(s string) error {
+	if !p.symbol(s) {
+		return fmt.Errorf("expected %q, got %s", s, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseSelect() (*Select, error) {
+	sel := &Select{Limit: -1}
+	if err := p.expectKeyword("select"); err != nil {
+		return nil, err
+	}
+
+	if p.symbol("*") {
+		sel.Star = true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	} else {
+		for {
+			f, err := p.parseField()
+			if err != nil {
+				return nil, err
+			}
+			sel.Fields = append(sel.Fields, f)
+			if !p.symbol(",") {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// dataset references aren't identifiers, read the reference as a raw word
+	if !p.tok.keyword("from") {
+		return nil, fmt.Errorf("expected FROM, got %s", p.tok)
+	}
+	ref, err := p.lx.raw()
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("FROM requires a dataset reference")
+	}
+	sel.From = ref
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.keyword("where") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if sel.Where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if hasAggregate(sel.Where) {
+			return nil, fmt.Errorf("aggregate functions aren't allowed in WHERE")
+		}
+	}
+
+	for _, clause := range []string{"group", "order"} {
+		if p.tok.keyword(clause) {
+			return nil, fmt.Errorf("%s BY isn't supported", strings.ToUpper(clause))
+		}
+	}
+
+	if p.tok.keyword("limit") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if sel.Limit, err = p.parseCount("LIMIT"); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.keyword("offset") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if sel.Offset, err = p.parseCount("OFFSET"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.symbol(";") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.typ != tEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", p.tok, p.tok.pos)
+	}
+	return sel, nil
+}
+
+// parseField parses a selected expression with an optional alias
+func (p *parser) parseField() (Field, error) {
+	start := p.tok.pos
+	expr, err := p.parseExpr()
+	if err != nil {
+		return Field{}, err
+	}
+	f := Field{Expr: expr, Name: strings.TrimSpace(p.lx.input[start:p.tok.pos])}
+	if c, ok := expr.(*column); ok {
+		f.Name = c.name
+	}
+
+	if p.tok.keyword("as") {
+		if err := p.advance(); err != nil {
+			return f, err
+		}
+		if p.tok.typ != tIdent || isReserved(p.tok) {
+			return f, fmt.Errorf("expected an alias after AS, got %s", p.tok)
+		}
+		f.Name = p.tok.val
+		err = p.advance()
+	}
+	return f, err
+}
+
+// parseCount parses a non-negative integer
+func (p *parser) parseCount(clause string) (int, error) {
+	if p.tok.typ != tNumber {
+		return 0, fmt.Errorf("%s requires a number, got %s", clause, p.tok)
+	}
+	n, err := strconv.Atoi(p.tok.val)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s requires a non-negative integer, got %s", clause, p.tok)
+	}
+	return n, p.advance()
+}
+
+func (p *parser) parseExpr() (Expr, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.keyword("or") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: "or", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.keyword("and") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: "and", l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.tok.keyword("not") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "not", x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+var comparisonOps = map[string]bool{"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true}
+
+func (p *parser) parseComparison() (Expr, error) {
+	l, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.tok.typ == tSymbol && comparisonOps[p.tok.val]:
+		op := p.tok.val
+		if op == "<>" {
+			op = "!="
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		r, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryExpr{op: op, l: l, r: r}, nil
+
+	case p.tok.keyword("is"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		e := &isNullExpr{x: l}
+		if p.tok.keyword("not") {
+			e.not = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		return e, p.expectKeyword("null")
+
+	case p.tok.keyword("like"):
+		return p.parseLike(l, false)
+
+	case p.tok.keyword("not"):
+		next, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if next.keyword("like") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return p.parseLike(l, true)
+		}
+	}
+	return l, nil
+}
+
+// parseLike parses the pattern of a LIKE expression. the current token is
+// the LIKE keyword
+func (p *parser) parseLike(x Expr, not bool) (Expr, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.typ != tString {
+		return nil, fmt.Errorf("LIKE requires a string pattern, got %s", p.tok)
+	}
+	re, err := likePattern(p.tok.val)
+	if err != nil {
+		return nil, err
+	}
+	return &likeExpr{x: x, re: re, not: not}, p.advance()
+}
+
+// likePattern converts a LIKE pattern into a regular expression. % matches
+// any run of characters, _ matches a single character
+func likePattern(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^(?s:")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString(")$")
+	return regexp.Compile(sb.String())
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	l, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.symbol("+") || p.symbol("-") {
+		op := p.tok.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		r, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.symbol("*") || p.symbol("/") {
+		op := p.tok.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &binaryExpr{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.symbol("-") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.tok
+	switch {
+	case t.typ == tNumber:
+		f, err := strconv.ParseFloat(t.val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", t)
+		}
+		return &literal{val: f}, p.advance()
+	case t.typ == tString:
+		return &literal{val: t.val}, p.advance()
+	case t.keyword("true"):
+		return &literal{val: true}, p.advance()
+	case t.keyword("false"):
+		return &literal{val: false}, p.advance()
+	case t.keyword("null"):
+		return &literal{val: nil}, p.advance()
+	case p.symbol("("):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expectSymbol(")")
+	case t.typ == tIdent && !isReserved(t):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !t.quoted && p.symbol("(") {
+			return p.parseAggregate(t)
+		}
+		return &column{name: t.val}, nil
+	}
+	return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
+
+// parseAggregate parses the arguments of an aggregate function call. the
+// current token is the opening parenthesis
+func (p *parser) parseAggregate(name token) (Expr, error) {
+	fn := strings.ToLower(name.val)
+	if !aggregateFuncs[fn] {
+		return nil, fmt.Errorf("unknown function %s", strings.ToUpper(name.val))
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	agg := &aggregate{fn: fn}
+	if p.symbol("*") {
+		if fn != "count" {
+			return nil, fmt.Errorf("%s(*) isn't supported, only COUNT(*)", strings.ToUpper(fn))
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	} else {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if hasAggregate(arg) {
+			return nil, fmt.Errorf("aggregate functions can't be nested")
+		}
+		agg.arg = arg
+	}
+	return agg, p.expectSymbol(")")
+}
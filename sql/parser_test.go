@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		query  string
+		from   string
+		fields []string
+		limit  int
+		offset int
+	}{
+		{"SELECT * FROM me/cities", "me/cities", nil, -1, 0},
+		{"select name, pop from me/cities;", "me/cities", []string{"name", "pop"}, -1, 0},
+		{"SELECT name AS city FROM me/cities@/ipfs/QmFoo LIMIT 10 OFFSET 5", "me/cities@/ipfs/QmFoo", []string{"city"}, 10, 5},
+		{"SELECT count(*), avg(pop) FROM \"me/cities\" WHERE pop > 10", "me/cities", []string{"count(*)", "avg(pop)"}, -1, 0},
+		{"SELECT max(pop) AS biggest FROM me/cities WHERE in_usa", "me/cities", []string{"biggest"}, -1, 0},
+		{"SELECT \"select\", pop * 2 FROM me/cities WHERE name LIKE 'new%' AND NOT pop IS NULL", "me/cities", []string{"select", "pop * 2"}, -1, 0},
+	}
+
+	for i, c := range cases {
+		sel, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("case %d unexpected error: %s", i, err)
+			continue
+		}
+		if sel.From != c.from {
+			t.Errorf("case %d from mismatch. expected: %q, got: %q", i, c.from, sel.From)
+		}
+		if len(sel.Fields) != len(c.fields) {
+			t.Errorf("case %d field count mismatch. expected: %d, got: %d", i, len(c.fields), len(sel.Fields))
+			continue
+		}
+		for j, f := range sel.Fields {
+			if f.Name != c.fields[j] {
+				t.Errorf("case %d field %d name mismatch. expected: %q, got: %q", i, j, c.fields[j], f.Name)
+			}
+		}
+		if sel.Limit != c.limit || sel.Offset != c.offset {
+			t.Errorf("case %d limit/offset mismatch. expected: %d/%d, got: %d/%d", i, c.limit, c.offset, sel.Limit, sel.Offset)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		query, err string
+	}{
+		{"", `parsing query: expected SELECT, got end of query`},
+		{"SELECT name", `parsing query: expected FROM, got end of query`},
+		{"SELECT name FROM", `parsing query: FROM requires a dataset reference`},
+		{"SELECT from FROM me/cities", `parsing query: unexpected "from" at position 7`},
+		{"SELECT name FROM me/cities WHERE count(*) > 1", `parsing query: aggregate functions aren't allowed in WHERE`},
+		{"SELECT sum(*) FROM me/cities", `parsing query: SUM(*) isn't supported, only COUNT(*)`},
+		{"SELECT lower(name) FROM me/cities", `parsing query: unknown function LOWER`},
+		{"SELECT max(min(pop)) FROM me/cities", `parsing query: aggregate functions can't be nested`},
+		{"SELECT name FROM me/cities LIMIT -1", `parsing query: LIMIT requires a number, got "-"`},
+		{"SELECT name FROM me/cities WHERE name = 'oops", `parsing query: unterminated quote starting at position 40`},
+		{"SELECT name FROM me/cities extra", `parsing query: unexpected "extra" at position 27`},
+		{"SELECT in_usa, count(*) FROM me/cities GROUP BY in_usa", `parsing query: GROUP BY isn't supported`},
+		{"SELECT name FROM me/cities ORDER BY pop", `parsing query: ORDER BY isn't supported`},
+	}
+
+	for i, c := range cases {
+		_, err := Parse(c.query)
+		if err == nil {
+			t.Errorf("case %d expected error, got nil", i)
+			continue
+		}
+		if err.Error() != c.err {
+			t.Errorf("case %d error mismatch. expected: %q, got: %q", i, c.err, err.Error())
+		}
+	}
+}
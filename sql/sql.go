@@ -0,0 +1,88 @@
+// Package sql runs SQL queries against dataset bodies. Queries select from a
+// single dataset, referenced by name in the FROM clause:
+//
+//	SELECT name, pop FROM me/cities WHERE pop > 100000 LIMIT 10
+//
+// The supported subset of SQL is SELECT with WHERE, LIMIT & OFFSET clauses,
+// the aggregate functions COUNT, SUM, AVG, MIN & MAX, arithmetic, comparison,
+// LIKE & IS NULL operators. Aggregating queries return a single row. Queries
+// read body rows once, in order, without holding the body in memory. Dataset
+// bodies must be tabular: arrays of rows with titled columns, or arrays of
+// objects
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Select is a parsed SELECT query
+type Select struct {
+	// Star is true for queries that select all columns with "*"
+	Star bool
+	// Fields are the selected expressions, empty when Star is true
+	Fields []Field
+	// From is the reference of the queried dataset
+	From string
+	// Where filters rows, nil if the query has no WHERE clause
+	Where Expr
+	// Limit is the maximum number of rows to return, -1 for no limit
+	Limit int
+	// Offset is the number of rows to skip
+	Offset int
+}
+
+// Field is a selected expression
+type Field struct {
+	Expr Expr
+	// Name is the output column name: the field's alias if it has one,
+	// otherwise the expression as written
+	Name string
+}
+
+// Parse parses a SELECT query
+func Parse(query string) (*Select, error) {
+	p := &parser{lx: &lexer{input: query}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	sel, err := p.parseSelect()
+	if err != nil {
+		return nil, fmt.Errorf("parsing query: %s", err)
+	}
+	return sel, nil
+}
+
+// aggregated returns true if a query calls aggregate functions
+func (s *Select) aggregated() bool {
+	for _, f := range s.Fields {
+		if hasAggregate(f.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNames gives the output column names of the query, columns are the
+// names of the queried body's columns
+func (s *Select) fieldNames(columns []string) []string {
+	if s.Star {
+		return columns
+	}
+	names := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// reserved words can't be used as unquoted column names
+var reserved = map[string]bool{
+	"select": true, "from": true, "where": true, "limit": true,
+	"offset": true, "as": true, "and": true, "or": true, "not": true,
+	"is": true, "null": true, "like": true, "true": true, "false": true,
+}
+
+func isReserved(t token) bool {
+	return t.typ == tIdent && !t.quoted && reserved[strings.ToLower(t.val)]
+}
@@ -96,7 +96,9 @@ var DefaultModuleLoader = func(thread *starlark.Thread, module string) (dict sta
 // may be modified, while the prev dataset point is read-only. At a bare minimum this function
 // will set transformation details, but starlark scripts can modify many parts of the dataset
 // pointer, including meta, structure, and transform. opts may provide more ways for output to
-// be produced from this function.
+// be produced from this function. starlark can't interrupt a running script, so ExecScript
+// checks ctx before each stage of execution & whenever the script loads a module or dataset,
+// returning the context error once ctx is done
 func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o *ExecOpts)) error {
 	var err error
 	if next.Transform == nil || next.Transform.ScriptFile() == nil {
@@ -159,6 +161,9 @@ func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o
 	}
 
 	// execute the transformation
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 	t.globals, err = starlark.ExecFile(thread, pipeScript.FileName(), pipeScript, t.locals())
 	if err != nil {
 		if evalErr, ok := err.(*starlark.EvalError); ok {
@@ -173,6 +178,9 @@ func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o
 	}
 
 	for name, fn := range funcs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		val, err := fn(t, thread, skyCtx)
 
 		if err != nil {
@@ -185,6 +193,9 @@ func ExecScript(ctx context.Context, next, prev *dataset.Dataset, opts ...func(o
 		skyCtx.SetResult(name, val)
 	}
 
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 	err = callTransformFunc(t, thread, skyCtx)
 	if evalErr, ok := err.(*starlark.EvalError); ok {
 		return fmt.Errorf(evalErr.Backtrace())
@@ -305,6 +316,9 @@ func (t *transform) locals() starlark.StringDict {
 
 // ModuleLoader sums all loading assets to resolve a module name during transform execution
 func (t *transform) ModuleLoader(thread *starlark.Thread, module string) (dict starlark.StringDict, err error) {
+	if err := t.ctx.Err(); err != nil {
+		return nil, err
+	}
 	if module == skyqri.ModuleName && t.skyqri != nil {
 		return t.skyqri.Namespace(), nil
 	}
@@ -332,6 +346,9 @@ func (t *transform) LoadDataset(thread *starlark.Thread, _ *starlark.Builtin, ar
 }
 
 func (t *transform) loadDataset(ctx context.Context, refstr string) (*dataset.Dataset, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if t.node == nil {
 		return nil, fmt.Errorf("no qri node available to load dataset: %s", refstr)
 	}
@@ -117,6 +117,19 @@ func TestLoadDataset(t *testing.T) {
 	}
 }
 
+func TestExecScriptCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ds := &dataset.Dataset{
+		Transform: &dataset.Transform{},
+	}
+	ds.Transform.SetScriptFile(scriptFile(t, "testdata/tf.star"))
+
+	if err := ExecScript(ctx, ds, nil); err != context.Canceled {
+		t.Errorf("error mismatch. expected: %v, got: %v", context.Canceled, err)
+	}
+}
+
 func TestGetMetaNilPrev(t *testing.T) {
 	ctx := context.Background()
 	ds := &dataset.Dataset{
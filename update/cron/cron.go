@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"sync/atomic"
 	"time"
 
 	golog "github.com/ipfs/go-log"
@@ -41,6 +42,8 @@ type Scheduler interface {
 	Log(ctx context.Context, logName string) (*Job, error)
 	// JobLogFile returns a reader for a file at the given name
 	LogFile(ctx context.Context, logName string) (io.ReadCloser, error)
+	// JobHistory lists logged runs of a job by job name, newest first
+	JobHistory(ctx context.Context, name string) ([]*Job, error)
 }
 
 // RunJobFunc is a function for executing a job. Cron takes care of scheduling
@@ -70,10 +73,19 @@ func NewCronInterval(schedule, log JobStore, factory RunJobFactory, checkInterva
 // Cron coordinates the scheduling of running jobs at specified periodicities
 // (intervals) with a provided job runner function
 type Cron struct {
-	schedule JobStore
-	log      JobStore
-	interval time.Duration
-	factory  RunJobFactory
+	schedule  JobStore
+	log       JobStore
+	interval  time.Duration
+	factory   RunJobFactory
+	retention LogRetention
+	// running is set to 1 while the Start check loop is active
+	running int32
+}
+
+// SetLogRetention configures how long the log store keeps job run history.
+// Runs outside the retention policy are pruned after each job executes
+func (c *Cron) SetLogRetention(r LogRetention) {
+	c.retention = r
 }
 
 // assert Cron is a Scheduler at compile time
@@ -115,10 +127,27 @@ func (c *Cron) LogFile(ctx context.Context, logName string) (io.ReadCloser, erro
 	return os.Open(job.LogFilePath)
 }
 
+// JobHistory lists logged runs of a job by job name, newest first. Runs that
+// fall outside the log retention policy are pruned before listing
+func (c *Cron) JobHistory(ctx context.Context, name string) ([]*Job, error) {
+	if _, err := PruneLogs(ctx, c.log, c.retention, time.Now()); err != nil {
+		return nil, err
+	}
+
+	logs, err := c.log.ListJobs(ctx, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	return historyFor(logs, name), nil
+}
+
 // Start initiates the check loop, looking for updates to execute once at every
 // iteration of the configured check interval.
 // Start blocks until the passed context completes
 func (c *Cron) Start(ctx context.Context) error {
+	atomic.StoreInt32(&c.running, 1)
+	defer atomic.StoreInt32(&c.running, 0)
+
 	check := func(ctx context.Context) {
 		now := time.Now()
 		ctx, cleanup := context.WithCancel(ctx)
@@ -189,6 +218,7 @@ func (c *Cron) runJob(ctx context.Context, job *Job, runner RunJobFunc) {
 	// the updated job that goes to the schedule store shouldn't have a log path
 	scheduleJob := job.Copy()
 	scheduleJob.LogFilePath = ""
+	scheduleJob.DryRunResult = ""
 	scheduleJob.RunStart = time.Time{}
 	scheduleJob.RunStop = time.Time{}
 	scheduleJob.PrevRunStart = job.RunStart
@@ -200,6 +230,12 @@ func (c *Cron) runJob(ctx context.Context, job *Job, runner RunJobFunc) {
 	if err := c.log.PutJob(ctx, job); err != nil {
 		log.Error(err)
 	}
+
+	if n, err := PruneLogs(ctx, c.log, c.retention, time.Now()); err != nil {
+		log.Errorf("pruning logs: %s", err)
+	} else if n > 0 {
+		log.Debugf("pruned %d logged run(s)", n)
+	}
 }
 
 // Schedule adds a job to the cron scheduler
@@ -175,8 +175,20 @@ func (rcv *DatasetOptions) SecretsLength() int {
 	return 0
 }
 
+func (rcv *DatasetOptions) DryRun() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(28))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *DatasetOptions) MutateDryRun(n bool) bool {
+	return rcv._tab.MutateBoolSlot(28, n)
+}
+
 func DatasetOptionsStart(builder *flatbuffers.Builder) {
-	builder.StartObject(12)
+	builder.StartObject(13)
 }
 func DatasetOptionsAddTitle(builder *flatbuffers.Builder, title flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(title), 0)
@@ -223,6 +235,9 @@ func DatasetOptionsAddSecrets(builder *flatbuffers.Builder, secrets flatbuffers.
 func DatasetOptionsStartSecretsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(4, numElems, 4)
 }
+func DatasetOptionsAddDryRun(builder *flatbuffers.Builder, dryRun bool) {
+	builder.PrependBoolSlot(12, dryRun, false)
+}
 func DatasetOptionsEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
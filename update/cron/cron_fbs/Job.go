@@ -143,8 +143,16 @@ func (rcv *Job) RepoPath() []byte {
 	return nil
 }
 
+func (rcv *Job) DryRunResult() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(30))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
 func JobStart(builder *flatbuffers.Builder) {
-	builder.StartObject(13)
+	builder.StartObject(14)
 }
 func JobAddName(builder *flatbuffers.Builder, name flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(name), 0)
@@ -185,6 +193,9 @@ func JobAddOptions(builder *flatbuffers.Builder, options flatbuffers.UOffsetT) {
 func JobAddRepoPath(builder *flatbuffers.Builder, repoPath flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(12, flatbuffers.UOffsetT(repoPath), 0)
 }
+func JobAddDryRunResult(builder *flatbuffers.Builder, dryRunResult flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(13, flatbuffers.UOffsetT(dryRunResult), 0)
+}
 func JobEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -5,13 +5,15 @@ package cron_fbs
 type JobType = int8
 
 const (
-	JobTypeunknown JobType = 0
-	JobTypedataset JobType = 1
-	JobTypeshell   JobType = 2
+	JobTypeunknown   JobType = 0
+	JobTypedataset   JobType = 1
+	JobTypeshell     JobType = 2
+	JobTypetransform JobType = 3
 )
 
 var EnumNamesJobType = map[JobType]string{
-	JobTypeunknown: "unknown",
-	JobTypedataset: "dataset",
-	JobTypeshell:   "shell",
+	JobTypeunknown:   "unknown",
+	JobTypedataset:   "dataset",
+	JobTypeshell:     "shell",
+	JobTypetransform: "transform",
 }
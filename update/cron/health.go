@@ -0,0 +1,81 @@
+package cron
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+)
+
+// DefaultHealthRecentRuns is the number of logged runs a health check reports
+// when no count is given
+const DefaultHealthRecentRuns = 10
+
+// Health summarizes the state of a scheduler
+type Health struct {
+	// Running is true if the scheduler is actively checking for jobs to run
+	Running bool `json:"running"`
+	// JobCount is the number of currently scheduled jobs
+	JobCount int `json:"jobCount"`
+	// RecentRuns lists the most recently logged job runs, newest first
+	RecentRuns []*Job `json:"recentRuns"`
+	// FailedJobs lists scheduled jobs whose most recent run errored
+	FailedJobs []*Job `json:"failedJobs"`
+}
+
+// Running returns true while the check loop started by Start is active
+func (c *Cron) Running() bool {
+	return atomic.LoadInt32(&c.running) == 1
+}
+
+// CheckHealth reports the state of a scheduler, including up to recentRuns
+// logged runs. Schedulers that talk to a remote service are pinged first,
+// returning ErrUnreachable if the service can't be contacted
+func CheckHealth(ctx context.Context, s Scheduler, recentRuns int) (*Health, error) {
+	if s == nil {
+		return nil, ErrUnreachable
+	}
+	if recentRuns <= 0 {
+		recentRuns = DefaultHealthRecentRuns
+	}
+
+	h := &Health{
+		RecentRuns: []*Job{},
+		FailedJobs: []*Job{},
+	}
+
+	switch sch := s.(type) {
+	case interface{ Ping() error }:
+		if err := sch.Ping(); err != nil {
+			log.Debugf("pinging scheduler: %s", err)
+			return nil, ErrUnreachable
+		}
+		h.Running = true
+	case interface{ Running() bool }:
+		h.Running = sch.Running()
+	}
+
+	js, err := s.ListJobs(ctx, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	h.JobCount = len(js)
+	for _, job := range js {
+		if job.RunError != "" {
+			h.FailedJobs = append(h.FailedJobs, job)
+		}
+	}
+
+	logs, err := s.ListLogs(ctx, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return runTime(logs[i]).After(runTime(logs[j]))
+	})
+	if len(logs) > recentRuns {
+		logs = logs[:recentRuns]
+	}
+	h.RecentRuns = append(h.RecentRuns, logs...)
+
+	return h, nil
+}
@@ -0,0 +1,99 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qri-io/ioes"
+)
+
+func TestCheckHealth(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := CheckHealth(ctx, nil, 0); err != ErrUnreachable {
+		t.Errorf("expected nil scheduler to be unreachable. got: %v", err)
+	}
+	if _, err := CheckHealth(ctx, HTTPClient{Addr: ":7898"}, 0); err != ErrUnreachable {
+		t.Errorf("expected stopped http scheduler to be unreachable. got: %v", err)
+	}
+
+	s := &MemJobStore{}
+	l := &MemJobStore{}
+	factory := func(context.Context) RunJobFunc {
+		return func(ctx context.Context, streams ioes.IOStreams, job *Job) error {
+			return nil
+		}
+	}
+	cr := NewCron(s, l, factory)
+
+	ok := &Job{
+		Name:        "b5/ok",
+		Type:        JTDataset,
+		Periodicity: mustRepeatingInterval("R/P1W"),
+	}
+	failed := &Job{
+		Name:        "b5/failed",
+		Type:        JTDataset,
+		Periodicity: mustRepeatingInterval("R/P1W"),
+		RunError:    "oh noes",
+	}
+	if err := s.PutJobs(ctx, ok, failed); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := []*Job{}
+	for i := 0; i < 3; i++ {
+		runs = append(runs, &Job{
+			Name:        ok.Name,
+			Type:        JTDataset,
+			Periodicity: ok.Periodicity,
+			RunNumber:   int64(i),
+			RunStart:    time.Date(2019, 1, i+1, 0, 0, 0, 0, time.UTC),
+			RunStop:     time.Date(2019, 1, i+1, 0, 1, 0, 0, time.UTC),
+		})
+		runs[i].Name = runs[i].LogName()
+	}
+	if err := l.PutJobs(ctx, runs...); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := CheckHealth(ctx, cr, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Running {
+		t.Error("expected cron that hasn't started to not be running")
+	}
+	if h.JobCount != 2 {
+		t.Errorf("job count mismatch. expected: %d, got: %d", 2, h.JobCount)
+	}
+	if len(h.FailedJobs) != 1 || h.FailedJobs[0].Name != failed.Name {
+		t.Errorf("expected failed jobs to contain only %q. got: %v", failed.Name, h.FailedJobs)
+	}
+	if len(h.RecentRuns) != 2 {
+		t.Fatalf("expected 2 recent runs. got: %d", len(h.RecentRuns))
+	}
+	if h.RecentRuns[0].RunNumber != 2 {
+		t.Errorf("expected newest run first. got run number: %d", h.RecentRuns[0].RunNumber)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		cr.Start(ctx)
+		close(done)
+	}()
+	time.Sleep(time.Millisecond * 50)
+	if h, err = CheckHealth(ctx, cr, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !h.Running {
+		t.Error("expected started cron to be running")
+	}
+	cancel()
+	<-done
+	if cr.Running() {
+		t.Error("expected cron to stop running once its context is cancelled")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 
 	flatbuffers "github.com/google/flatbuffers/go"
@@ -132,6 +133,20 @@ func (c HTTPClient) LogFile(ctx context.Context, logName string) (io.ReadCloser,
 	return nil, maybeErrorResponse(res)
 }
 
+// JobHistory lists logged runs of a job by job name, newest first
+func (c HTTPClient) JobHistory(ctx context.Context, name string) ([]*Job, error) {
+	res, err := http.Get(fmt.Sprintf("http://%s/job/history?name=%s", c.Addr, url.QueryEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == 200 {
+		return decodeListJobsResponse(res)
+	}
+
+	return nil, maybeErrorResponse(res)
+}
+
 func (c HTTPClient) postJob(job *Job) error {
 	builder := flatbuffers.NewBuilder(0)
 	off := job.MarshalFlatbuffer(builder)
@@ -210,6 +225,7 @@ func newCronRoutes(c *Cron) http.Handler {
 	m.HandleFunc("/", c.statusHandler)
 	m.HandleFunc("/jobs", c.jobsHandler)
 	m.HandleFunc("/job", c.jobHandler)
+	m.HandleFunc("/job/history", c.jobHistoryHandler)
 	m.HandleFunc("/logs", c.logsHandler)
 	m.HandleFunc("/log", c.loggedJobHandler)
 	m.HandleFunc("/log/output", c.loggedJobFileHandler)
@@ -283,6 +299,18 @@ func (c *Cron) jobHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(job.FlatbufferBytes())
 }
 
+func (c *Cron) jobHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	runs, err := c.JobHistory(r.Context(), name)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Write(jobs(runs).FlatbufferBytes())
+}
+
 func (c *Cron) logsHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 
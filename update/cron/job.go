@@ -2,7 +2,7 @@ package cron
 
 import (
 	"fmt"
-	"path/filepath"
+	"net/url"
 	"time"
 
 	flatbuffers "github.com/google/flatbuffers/go"
@@ -24,6 +24,11 @@ const (
 	// update one or more datasets. A non-zero exit code from shell script
 	// indicates the job failed to execute properly
 	JTShellScript JobType = "shell"
+	// JTTransform indicates a job that runs the transform script of a dataset
+	// specified by Job Name, saving the result as a new version. transform jobs
+	// are executed in-process by a qri instance instead of invoking the qri
+	// binary
+	JTTransform JobType = "transform"
 )
 
 // Enum returns the enumerated representation of a JobType
@@ -33,6 +38,8 @@ func (jt JobType) Enum() int8 {
 		return 1
 	case JTShellScript:
 		return 2
+	case JTTransform:
+		return 3
 	}
 	// "unknown"
 	return 0
@@ -62,6 +69,11 @@ type Job struct {
 	RepoPath string `json:"repoPath,omitempty"`
 
 	Options Options `json:"options,omitempty"`
+
+	// DryRunResult is the output of a dry run, set on logged runs of jobs with
+	// dry run options. for dataset jobs this is the dataset that would have
+	// been saved, without its body
+	DryRunResult string `json:"dryRunResult,omitempty"`
 }
 
 // zero is a "constant" representing an empty repeating interval
@@ -77,7 +89,7 @@ func (job *Job) Validate() error {
 	if job.Periodicity == zero {
 		return fmt.Errorf("period is required")
 	}
-	if job.Type != JTDataset && job.Type != JTShellScript {
+	if job.Type != JTDataset && job.Type != JTShellScript && job.Type != JTTransform {
 		return fmt.Errorf("invalid job type: %s", job.Type)
 	}
 	return nil
@@ -89,10 +101,18 @@ func (job *Job) NextExec() time.Time {
 	return job.Periodicity.After(job.PrevRunStart)
 }
 
+// DryRun returns true if a job simulates running without making changes
+func (job *Job) DryRun() bool {
+	o, ok := job.Options.(*DatasetOptions)
+	return ok && o.DryRun
+}
+
 // LogName returns a canonical name string for a job that's executed and saved
-// to a logging system
+// to a logging system. The full job name is escaped so jobs that share a base
+// name in different directories don't collide, and the result is safe to use
+// as a file name
 func (job *Job) LogName() string {
-	return fmt.Sprintf("%d-%s", job.RunNumber, filepath.Base(job.Name))
+	return fmt.Sprintf("%d-%s", job.RunNumber, url.QueryEscape(job.Name))
 }
 
 // Copy creates a copy of a job
@@ -110,6 +130,8 @@ func (job *Job) Copy() *Job {
 		RunError:    job.RunError,
 		LogFilePath: job.LogFilePath,
 		RepoPath:    job.RepoPath,
+
+		DryRunResult: job.DryRunResult,
 	}
 
 	if job.Options != nil {
@@ -138,6 +160,7 @@ func (job *Job) MarshalFlatbuffer(builder *flatbuffers.Builder) flatbuffers.UOff
 	lastError := builder.CreateString(job.RunError)
 	logPath := builder.CreateString(job.LogFilePath)
 	repoPath := builder.CreateString(job.RepoPath)
+	dryRunResult := builder.CreateString(job.DryRunResult)
 	p := builder.CreateString(job.Periodicity.String())
 
 	var opts flatbuffers.UOffsetT
@@ -159,6 +182,7 @@ func (job *Job) MarshalFlatbuffer(builder *flatbuffers.Builder) flatbuffers.UOff
 	cronfb.JobAddRunError(builder, lastError)
 	cronfb.JobAddLogFilePath(builder, logPath)
 	cronfb.JobAddRepoPath(builder, repoPath)
+	cronfb.JobAddDryRunResult(builder, dryRunResult)
 	cronfb.JobAddOptionsType(builder, job.fbOptionsType())
 	if opts != 0 {
 		cronfb.JobAddOptions(builder, opts)
@@ -208,6 +232,8 @@ func (job *Job) UnmarshalFlatbuffer(j *cronfb.Job) error {
 		RunError:    string(j.RunError()),
 		LogFilePath: string(j.LogFilePath()),
 		RepoPath:    string(j.RepoPath()),
+
+		DryRunResult: string(j.DryRunResult()),
 	}
 
 	unionTable := new(flatbuffers.Table)
@@ -253,6 +279,9 @@ type DatasetOptions struct {
 	Force               bool
 	ConvertFormatToPrev bool
 	ShouldRender        bool
+	// DryRun simulates saving, runs record the would-be dataset instead of
+	// writing a new version
+	DryRun bool
 
 	Config  map[string]string
 	Secrets map[string]string
@@ -336,6 +365,7 @@ func (o *DatasetOptions) MarshalFlatbuffer(builder *flatbuffers.Builder) flatbuf
 	cronfb.DatasetOptionsAddForce(builder, o.Force)
 	cronfb.DatasetOptionsAddConvertFormatToPrev(builder, o.ConvertFormatToPrev)
 	cronfb.DatasetOptionsAddShouldRender(builder, o.ShouldRender)
+	cronfb.DatasetOptionsAddDryRun(builder, o.DryRun)
 
 	cronfb.DatasetOptionsAddFilePaths(builder, filePaths)
 	cronfb.DatasetOptionsAddConfig(builder, config)
@@ -363,6 +393,7 @@ func (o *DatasetOptions) UnmarshalFlatbuffer(fbo *cronfb.DatasetOptions) {
 	o.Force = fbo.Force()
 	o.ConvertFormatToPrev = fbo.ConvertFormatToPrev()
 	o.ShouldRender = fbo.ShouldRender()
+	o.DryRun = fbo.DryRun()
 
 	// TODO (b5): unmarshal secrets & config:
 	// Config  map[string]string
@@ -70,8 +70,10 @@ func RunJobStoreTests(t *testing.T, newStore func() JobStore) {
 			Periodicity: mustRepeatingInterval("R/PT1H"),
 			Type:        JTDataset,
 			Options: &DatasetOptions{
-				Title: "hallo",
+				Title:  "hallo",
+				DryRun: true,
 			},
+			DryRunResult: "{}",
 		}
 		if err = store.PutJob(ctx, jobThree); err != nil {
 			t.Errorf("putting job three: %s", err)
@@ -22,6 +22,7 @@ func TestDatasetOptionsFlatbuffer(t *testing.T) {
 		Force:               true,
 		ConvertFormatToPrev: true,
 		ShouldRender:        true,
+		DryRun:              true,
 
 		Config:  map[string]string{"a": "a"},
 		Secrets: map[string]string{"b": "b"},
@@ -60,6 +61,7 @@ func TestJobCopy(t *testing.T) {
 		Options: &DatasetOptions{
 			FilePaths: []string{"the", "file", "paths"},
 		},
+		DryRunResult: "such result",
 	}
 
 	if err := CompareJobs(a, a.Copy()); err != nil {
@@ -67,6 +69,17 @@ func TestJobCopy(t *testing.T) {
 	}
 }
 
+func TestJobDryRun(t *testing.T) {
+	job := &Job{Type: JTDataset}
+	if job.DryRun() {
+		t.Error("expected job without options not to be a dry run")
+	}
+	job.Options = &DatasetOptions{DryRun: true}
+	if !job.DryRun() {
+		t.Error("expected job with dry run options to be a dry run")
+	}
+}
+
 func CompareJobs(a, b *Job) error {
 	if a.Name != b.Name {
 		return fmt.Errorf("Name mismatch. %s != %s", a.Name, b.Name)
@@ -103,6 +116,9 @@ func CompareJobs(a, b *Job) error {
 	if a.RepoPath != b.RepoPath {
 		return fmt.Errorf("RepoPath mistmatch. %s != %s", a.RepoPath, b.RepoPath)
 	}
+	if a.DryRunResult != b.DryRunResult {
+		return fmt.Errorf("DryRunResult mistmatch. %s != %s", a.DryRunResult, b.DryRunResult)
+	}
 
 	if err := CompareOptions(a.Options, b.Options); err != nil {
 		return fmt.Errorf("Options: %s", err)
@@ -176,6 +192,9 @@ func CompareDatasetOptions(a, b *DatasetOptions) error {
 	if a.ShouldRender != b.ShouldRender {
 		return fmt.Errorf("ShouldRender: %t != %t", a.ShouldRender, b.ShouldRender)
 	}
+	if a.DryRun != b.DryRun {
+		return fmt.Errorf("DryRun: %t != %t", a.DryRun, b.DryRun)
+	}
 
 	if err := compareMapStringString(a.Config, b.Config); err != nil {
 		return fmt.Errorf("Config: %s", err)
@@ -0,0 +1,123 @@
+package cron
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogRetention is a policy for pruning job run history from a log store. Runs
+// older than MaxAge, or beyond the most recent MaxRuns runs of a job are
+// pruned. A zero value for either field disables that limit
+type LogRetention struct {
+	MaxAge  time.Duration
+	MaxRuns int
+}
+
+// IsZero returns true if the retention policy keeps all runs forever
+func (r LogRetention) IsZero() bool {
+	return r.MaxAge <= 0 && r.MaxRuns <= 0
+}
+
+// Expired returns the runs in a log that fall outside the retention policy
+// at time now
+func (r LogRetention) Expired(logs []*Job, now time.Time) []*Job {
+	if r.IsZero() {
+		return nil
+	}
+
+	byJob := map[string][]*Job{}
+	for _, run := range logs {
+		name := loggedJobName(run.Name)
+		byJob[name] = append(byJob[name], run)
+	}
+
+	expired := []*Job{}
+	for _, runs := range byJob {
+		sortRunsNewestFirst(runs)
+		for i, run := range runs {
+			if r.MaxRuns > 0 && i >= r.MaxRuns {
+				expired = append(expired, run)
+			} else if r.MaxAge > 0 && runTime(run).Before(now.Add(-r.MaxAge)) {
+				expired = append(expired, run)
+			}
+		}
+	}
+	return expired
+}
+
+// PruneLogs removes runs that fall outside the retention policy from a log
+// store, along with any log files they wrote to. PruneLogs returns the number
+// of runs removed
+func PruneLogs(ctx context.Context, store JobStore, r LogRetention, now time.Time) (int, error) {
+	if r.IsZero() {
+		return 0, nil
+	}
+
+	logs, err := store.ListJobs(ctx, 0, -1)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := r.Expired(logs, now)
+	for _, run := range expired {
+		if err := store.DeleteJob(ctx, run.Name); err != nil {
+			return 0, err
+		}
+		if run.LogFilePath != "" {
+			if err := os.Remove(run.LogFilePath); err != nil && !os.IsNotExist(err) {
+				log.Debugf("removing log file %s: %s", run.LogFilePath, err)
+			}
+		}
+	}
+	return len(expired), nil
+}
+
+// loggedJobName recovers the job name portion of a job's LogName, which has
+// the form "[RunNumber]-[escaped job name]"
+func loggedJobName(logName string) string {
+	if i := strings.Index(logName, "-"); i > 0 {
+		if _, err := strconv.Atoi(logName[:i]); err == nil {
+			if name, err := url.QueryUnescape(logName[i+1:]); err == nil {
+				return name
+			}
+			return logName[i+1:]
+		}
+	}
+	return logName
+}
+
+// runTime is the time a logged run completed, falling back to the time it
+// started for runs that never stopped
+func runTime(run *Job) time.Time {
+	if !run.RunStop.IsZero() {
+		return run.RunStop
+	}
+	return run.RunStart
+}
+
+func sortRunsNewestFirst(runs []*Job) {
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].RunNumber != runs[j].RunNumber {
+			return runs[i].RunNumber > runs[j].RunNumber
+		}
+		return runTime(runs[i]).After(runTime(runs[j]))
+	})
+}
+
+// historyFor filters a log to the runs of the job with the given name,
+// ordered newest first
+func historyFor(logs []*Job, name string) []*Job {
+	runs := []*Job{}
+	for _, run := range logs {
+		if loggedJobName(run.Name) == name {
+			runs = append(runs, run)
+		}
+	}
+	sortRunsNewestFirst(runs)
+	return runs
+}
@@ -0,0 +1,180 @@
+package cron
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLogRetentionMaxAge(t *testing.T) {
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	newStore := func() JobStore { return &MemJobStore{} }
+
+	tmp, err := ioutil.TempDir("", "TestLogRetentionMaxAge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, store := range []JobStore{newStore(), NewFlatbufferJobStore(filepath.Join(tmp, "logs.qfb"))} {
+		ctx := context.Background()
+		putRuns(t, store, "me/dataset", now, 2*24*time.Hour, 5)
+
+		r := LogRetention{MaxAge: 5 * 24 * time.Hour}
+		pruned, err := PruneLogs(ctx, store, r, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// runs stopped 0, 2, 4, 6 & 8 days ago. the last two are older than 5 days
+		if pruned != 2 {
+			t.Errorf("pruned count mismatch. expected: %d, got: %d", 2, pruned)
+		}
+
+		logs, err := store.ListJobs(ctx, 0, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) != 3 {
+			t.Errorf("remaining log length mismatch. expected: %d, got: %d", 3, len(logs))
+		}
+		for _, run := range logs {
+			if run.RunStop.Before(now.Add(-r.MaxAge)) {
+				t.Errorf("expected run %s to be pruned", run.Name)
+			}
+		}
+	}
+}
+
+func TestLogRetentionMaxRuns(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tmp, err := ioutil.TempDir("", "TestLogRetentionMaxRuns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	store := NewFlatbufferJobStore(filepath.Join(tmp, "logs.qfb"))
+	putRuns(t, store, "me/dataset", now, time.Hour, 4)
+	putRuns(t, store, "/path/to/script.sh", now, time.Hour, 2)
+
+	logFile := filepath.Join(tmp, "1-me%2Fdataset.log")
+	if err := ioutil.WriteFile(logFile, []byte("output"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	oldest, err := store.Job(ctx, "1-me%2Fdataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldest.LogFilePath = logFile
+	if err := store.PutJob(ctx, oldest); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := PruneLogs(ctx, store, LogRetention{MaxRuns: 2}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 2 {
+		t.Errorf("pruned count mismatch. expected: %d, got: %d", 2, pruned)
+	}
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Errorf("expected log file of pruned run to be removed")
+	}
+
+	c := NewCron(&MemJobStore{}, store, nil)
+	runs, err := c.JobHistory(ctx, "me/dataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"4-me%2Fdataset", "3-me%2Fdataset"}
+	if len(runs) != len(expect) {
+		t.Fatalf("history length mismatch. expected: %d, got: %d", len(expect), len(runs))
+	}
+	for i, name := range expect {
+		if runs[i].Name != name {
+			t.Errorf("history index %d name mismatch. expected: %q, got: %q", i, name, runs[i].Name)
+		}
+	}
+
+	runs, err = c.JobHistory(ctx, "/path/to/script.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Errorf("expected runs of other jobs to be unaffected. expected: %d, got: %d", 2, len(runs))
+	}
+
+	c.SetLogRetention(LogRetention{MaxRuns: 1})
+	if runs, err = c.JobHistory(ctx, "me/dataset"); err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Errorf("expected history to apply retention. expected: %d, got: %d", 1, len(runs))
+	}
+}
+
+func TestLogRetentionSameBaseName(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	store := &MemJobStore{}
+	putRuns(t, store, "/a/script.sh", now, time.Hour, 3)
+	putRuns(t, store, "/b/script.sh", now, time.Hour, 3)
+
+	pruned, err := PruneLogs(ctx, store, LogRetention{MaxRuns: 2}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 2 {
+		t.Errorf("pruned count mismatch. expected: %d, got: %d", 2, pruned)
+	}
+
+	c := NewCron(&MemJobStore{}, store, nil)
+	for _, name := range []string{"/a/script.sh", "/b/script.sh"} {
+		runs, err := c.JobHistory(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(runs) != 2 {
+			t.Errorf("%s history length mismatch. expected: %d, got: %d", name, 2, len(runs))
+		}
+	}
+}
+
+func TestLogRetentionZero(t *testing.T) {
+	ctx := context.Background()
+	store := &MemJobStore{}
+	putRuns(t, store, "me/dataset", time.Now(), 24*time.Hour*365, 3)
+
+	pruned, err := PruneLogs(ctx, store, LogRetention{}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 0 {
+		t.Errorf("expected zero retention policy to keep all runs, pruned: %d", pruned)
+	}
+}
+
+// putRuns logs count runs of a job, with the newest run stopping at now and
+// each prior run stopping interval earlier
+func putRuns(t *testing.T, store JobStore, name string, now time.Time, interval time.Duration, count int) {
+	for i := 1; i <= count; i++ {
+		stop := now.Add(-time.Duration(count-i) * interval)
+		run := &Job{
+			Name:        name,
+			Type:        JTDataset,
+			Periodicity: mustRepeatingInterval("R/P1D"),
+			RunNumber:   int64(i),
+			RunStart:    stop.Add(-time.Minute),
+			RunStop:     stop,
+		}
+		run.Name = run.LogName()
+		if err := store.PutJob(context.Background(), run); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package update
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -33,8 +34,9 @@ func Path(repoPath string) (path string, err error) {
 	return
 }
 
-// Start starts the update service
-func Start(ctx context.Context, repoPath string, updateCfg *config.Update, daemonize bool) error {
+// Start starts the update service. factory creates the runner for scheduled
+// jobs, defaulting to Factory when nil
+func Start(ctx context.Context, repoPath string, updateCfg *config.Update, daemonize bool, factory cron.RunJobFactory) error {
 	if updateCfg == nil {
 		updateCfg = config.DefaultUpdate()
 	}
@@ -48,7 +50,10 @@ func Start(ctx context.Context, repoPath string, updateCfg *config.Update, daemo
 		return daemonInstall(repoPath)
 	}
 
-	return start(ctx, repoPath, updateCfg)
+	if factory == nil {
+		factory = Factory
+	}
+	return start(ctx, repoPath, updateCfg, factory)
 }
 
 // StopDaemon checks for a running daemon, uninstalling it if one exists
@@ -61,7 +66,7 @@ func Status() (string, error) {
 	return daemonShow()
 }
 
-func start(ctx context.Context, repoPath string, updateCfg *config.Update) error {
+func start(ctx context.Context, repoPath string, updateCfg *config.Update, factory cron.RunJobFactory) error {
 	path, err := Path(repoPath)
 	if err != nil {
 		return err
@@ -79,7 +84,13 @@ func start(ctx context.Context, repoPath string, updateCfg *config.Update) error
 		return fmt.Errorf("unknown cron type: %s", updateCfg.Type)
 	}
 
-	svc := cron.NewCron(jobStore, logStore, Factory)
+	retention, err := LogRetention(updateCfg)
+	if err != nil {
+		return err
+	}
+
+	svc := cron.NewCron(jobStore, logStore, factory)
+	svc.SetLogRetention(retention)
 	log.Debug("starting update service")
 	go func() {
 		if err := svc.ServeHTTP(updateCfg.Address); err != nil {
@@ -90,12 +101,33 @@ func start(ctx context.Context, repoPath string, updateCfg *config.Update) error
 	return svc.Start(ctx)
 }
 
+// LogRetention reads the run history retention policy from update
+// configuration
+func LogRetention(updateCfg *config.Update) (r cron.LogRetention, err error) {
+	if updateCfg == nil {
+		return r, nil
+	}
+	if updateCfg.LogMaxAge != "" {
+		d, err := iso8601.ParseDuration(updateCfg.LogMaxAge)
+		if err != nil {
+			return r, fmt.Errorf("invalid update log max age %q: %s", updateCfg.LogMaxAge, err)
+		}
+		r.MaxAge = d.Duration
+	}
+	r.MaxRuns = updateCfg.LogMaxRuns
+	return r, nil
+}
+
 // Factory returns a function that can run jobs
 func Factory(context.Context) cron.RunJobFunc {
 	return func(ctx context.Context, streams ioes.IOStreams, job *cron.Job) error {
 		log.Debugf("running update: %s", job.Name)
 
-		var errBuf *bytes.Buffer
+		if job.Type == cron.JTTransform {
+			return fmt.Errorf("transform jobs must be run by a qri instance")
+		}
+
+		var errBuf, outBuf *bytes.Buffer
 		// if the job type is a dataset, error output is semi-predictable
 		// write to a buffer for better error reporting
 		if job.Type == cron.JTDataset {
@@ -103,6 +135,13 @@ func Factory(context.Context) cron.RunJobFunc {
 			teedErrOut := io.MultiWriter(streams.ErrOut, errBuf)
 			streams = ioes.NewIOStreams(streams.In, streams.Out, teedErrOut)
 		}
+		// dry runs write the dataset they would have saved to stdout, capture it
+		// for the run result
+		if job.DryRun() {
+			outBuf = &bytes.Buffer{}
+			teedOut := io.MultiWriter(streams.Out, outBuf)
+			streams = ioes.NewIOStreams(streams.In, teedOut, streams.ErrOut)
+		}
 
 		cmd := JobToCmd(streams, job)
 		if cmd == nil {
@@ -110,10 +149,29 @@ func Factory(context.Context) cron.RunJobFunc {
 		}
 
 		err := cmd.Run()
+		if outBuf != nil {
+			job.DryRunResult = dryRunResult(outBuf.Bytes())
+		}
 		return processJobError(job, errBuf, err)
 	}
 }
 
+// dryRunResult drops the body from the dataset a dry run would have saved.
+// results are kept in the job log, where bodies of any size don't belong.
+// output that isn't a dataset is returned as-is
+func dryRunResult(out []byte) string {
+	ds := map[string]interface{}{}
+	if err := json.Unmarshal(out, &ds); err != nil {
+		return string(out)
+	}
+	delete(ds, "body")
+	data, err := json.MarshalIndent(ds, "", "  ")
+	if err != nil {
+		return string(out)
+	}
+	return string(data)
+}
+
 // JobToCmd returns an operating system command that will execute the given job
 // wiring operating system in/out/errout to the provided iostreams.
 func JobToCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
@@ -163,6 +221,7 @@ func datasetSaveCmd(streams ioes.IOStreams, job *cron.Job) *exec.Cmd {
 			"--force":       o.Force,
 			"--keep-format": o.ConvertFormatToPrev,
 			"--no-render":   !o.ShouldRender,
+			"--dry-run":     o.DryRun,
 		}
 		for flag, use := range boolFlags {
 			if use {
@@ -228,6 +287,22 @@ func DatasetToJob(ds *dataset.Dataset, periodicity string, opts *cron.DatasetOpt
 	return
 }
 
+// TransformToJob converts a dataset with a transform into a cron.Job that
+// re-runs the transform. it's an error to schedule a dataset that has no
+// transform script
+func TransformToJob(ds *dataset.Dataset, periodicity string, opts *cron.DatasetOptions) (job *cron.Job, err error) {
+	if ds.Transform == nil || (ds.Transform.ScriptPath == "" && ds.Transform.ScriptFile() == nil) {
+		return nil, fmt.Errorf("dataset %s/%s has no transform to run", ds.Peername, ds.Name)
+	}
+
+	if job, err = DatasetToJob(ds, periodicity, opts); err != nil {
+		return nil, err
+	}
+	job.Type = cron.JTTransform
+	err = job.Validate()
+	return
+}
+
 // ShellScriptToJob turns a shell script into cron.Job
 func ShellScriptToJob(path string, periodicity string, opts *cron.ShellScriptOptions) (job *cron.Job, err error) {
 	p, err := iso8601.ParseRepeatingInterval(periodicity)
@@ -32,6 +32,37 @@ func TestJobFromDataset(t *testing.T) {
 	}
 }
 
+func TestTransformToJob(t *testing.T) {
+	ds := &dataset.Dataset{
+		Peername: "b5",
+		Name:     "libp2p_node_count",
+		Commit: &dataset.Commit{
+			Timestamp: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	if _, err := TransformToJob(ds, "R/P1W", nil); err == nil {
+		t.Error("expected dataset without a transform to error")
+	}
+
+	ds.Transform = &dataset.Transform{ScriptPath: "/ipfs/QmTransform"}
+	job, err := TransformToJob(ds, "R/P1W", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Type != cron.JTTransform {
+		t.Errorf("expected job type %q, got: %q", cron.JTTransform, job.Type)
+	}
+	if job.Name != "b5/libp2p_node_count" {
+		t.Errorf("job name mismatch. got: %q", job.Name)
+	}
+
+	run := Factory(context.Background())
+	if err := run(context.Background(), ioes.NewDiscardIOStreams(), job); err == nil {
+		t.Error("expected running a transform job outside of an instance to error")
+	}
+}
+
 func TestJobFromShellScript(t *testing.T) {
 	// ShellScriptToJob(qfs.NewMemfileBytes("test.sh", nil)
 }
@@ -64,6 +95,27 @@ func TestDatasetJobToCmd(t *testing.T) {
 	if got != expect {
 		t.Errorf("job string mismatch. expected:\n'%s'\ngot:\n'%s'", expect, got)
 	}
+
+	dsj.Options = &cron.DatasetOptions{ShouldRender: true, DryRun: true}
+	cmd = JobToCmd(streams, dsj)
+	expect = "qri save me/foo --dry-run"
+	if got = strings.Join(cmd.Args, " "); got != expect {
+		t.Errorf("dry run job string mismatch. expected:\n'%s'\ngot:\n'%s'", expect, got)
+	}
+}
+
+func TestDryRunResult(t *testing.T) {
+	got := dryRunResult([]byte(`{"peername":"me","name":"foo","body":[[1,2,3]],"structure":{"format":"json"}}`))
+	if strings.Contains(got, "body") {
+		t.Errorf("expected body to be dropped from dry run result. got:\n%s", got)
+	}
+	if !strings.Contains(got, `"format": "json"`) {
+		t.Errorf("expected dry run result to keep dataset components. got:\n%s", got)
+	}
+
+	if got := dryRunResult([]byte("not a dataset")); got != "not a dataset" {
+		t.Errorf("expected output that isn't a dataset to pass through. got: %q", got)
+	}
 }
 
 func TestShellScriptJobToCmd(t *testing.T) {
@@ -98,7 +150,7 @@ func TestStart(t *testing.T) {
 	// call factory here to ensure we can create a factory with this context
 	Factory(ctx)
 
-	if err := Start(ctx, "", &config.Update{Type: "mem"}, false); err != nil {
+	if err := Start(ctx, "", &config.Update{Type: "mem"}, false, nil); err != nil {
 		t.Error(err)
 	}
 }